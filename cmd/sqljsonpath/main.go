@@ -0,0 +1,289 @@
+// Command sqljsonpath executes a SQL/JSON path expression against JSON
+// documents with PostgreSQL semantics. Usage:
+//
+//	sqljsonpath [flags] PATH [FILE]
+//
+// It reads a stream of one or more JSON documents from FILE, or from standard
+// input when FILE is omitted or "-", and executes PATH against each. By
+// default it writes the items selected by PATH as newline-delimited JSON
+// values, like jsonb_path_query(). The flags are:
+//
+//	--exists    write whether PATH selects any items, like jsonb_path_exists()
+//	--match     write the result of a predicate check path, like jsonb_path_match()
+//	--first     write only the first item selected by PATH, like jsonb_path_query_first()
+//	--silent    suppress structural and execution errors
+//	--tz        allow comparisons of date and time values with and without time zones
+//	--vars JSON a JSON object of variables to substitute into PATH
+//	--indent    indent JSON values with two spaces
+//	--pg        write items in PostgreSQL jsonb text format, like psql
+//
+// In --exists and --match mode it writes true, false, or null for each
+// document, and exits with status 0 when all are true and 1 otherwise. It
+// exits with status 2 on error.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/theory/sqljson/path"
+	"github.com/theory/sqljson/path/exec"
+)
+
+// Exit statuses.
+const (
+	exitOK    = 0
+	exitFalse = 1
+	exitError = 2
+)
+
+// mode determines how to execute a path.
+type mode int
+
+const (
+	modeQuery mode = iota
+	modeExists
+	modeMatch
+	modeFirst
+)
+
+// errUsage indicates invalid arguments.
+var errUsage = errors.New("usage: sqljsonpath [flags] PATH [FILE]")
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command with args, reading documents from stdin when
+// args name no file, and returns the exit status.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd, err := parseArgs(args, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		fmt.Fprintf(stderr, "sqljsonpath: %v\n", err)
+		return exitError
+	}
+
+	in := stdin
+	if cmd.file != "" && cmd.file != "-" {
+		fh, err := os.Open(cmd.file)
+		if err != nil {
+			fmt.Fprintf(stderr, "sqljsonpath: %v\n", err)
+			return exitError
+		}
+		defer fh.Close()
+		in = fh
+	}
+
+	status, err := cmd.execute(ctx, in, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "sqljsonpath: %v\n", err)
+		return exitError
+	}
+	return status
+}
+
+// command contains the path, options, and input file parsed from the
+// command line.
+type command struct {
+	mode   mode
+	path   *path.Path
+	opts   []exec.Option
+	indent bool
+	pg     bool
+	file   string
+}
+
+// parseArgs parses args into a command. Writes usage to stderr for invalid
+// flags or -h.
+func parseArgs(args []string, stderr io.Writer) (*command, error) {
+	flags := flag.NewFlagSet("sqljsonpath", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	exists := flags.Bool("exists", false, "write whether PATH selects any items")
+	match := flags.Bool("match", false, "write the result of a predicate check path")
+	first := flags.Bool("first", false, "write only the first item selected by PATH")
+	silent := flags.Bool("silent", false, "suppress structural and execution errors")
+	tz := flags.Bool("tz", false, "allow comparisons of date and time values with and without time zones")
+	vars := flags.String("vars", "", "a JSON object of variables to substitute into PATH")
+	indent := flags.Bool("indent", false, "indent JSON values with two spaces")
+	pg := flags.Bool("pg", false, "write items in PostgreSQL jsonb text format, like psql")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "%v\n\nFlags:\n", errUsage)
+		flags.PrintDefaults()
+	}
+
+	pos, err := parseFlags(flags, args)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := &command{indent: *indent, pg: *pg}
+	switch len(pos) {
+	case 2:
+		cmd.file = pos[1]
+	case 1:
+	default:
+		return nil, errUsage
+	}
+
+	if cmd.path, err = path.Parse(pos[0]); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case *exists && !*match && !*first:
+		cmd.mode = modeExists
+	case *match && !*exists && !*first:
+		cmd.mode = modeMatch
+	case *first && !*exists && !*match:
+		cmd.mode = modeFirst
+	case *exists || *match || *first:
+		return nil, errors.New("only one of --exists, --match, and --first allowed")
+	}
+	if cmd.pg && (cmd.mode != modeQuery || cmd.indent) {
+		return nil, errors.New("--pg not allowed with --exists, --match, --first, or --indent")
+	}
+
+	if *vars != "" {
+		var v exec.Vars
+		if err := decoder(bytes.NewReader([]byte(*vars))).Decode(&v); err != nil {
+			return nil, fmt.Errorf("invalid --vars: %w", err)
+		}
+		cmd.opts = append(cmd.opts, exec.WithVars(v))
+	}
+	if *silent {
+		cmd.opts = append(cmd.opts, exec.WithSilent())
+	}
+	if *tz {
+		cmd.opts = append(cmd.opts, exec.WithTZ())
+	}
+
+	return cmd, nil
+}
+
+// parseFlags parses args with flags, allowing flags to follow positional
+// arguments, and returns the positional arguments. Treats all arguments
+// following "--" as positional.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := flags.Args()
+		if len(rest) == 0 {
+			return pos, nil
+		}
+		if len(rest) < len(args) && args[len(args)-len(rest)-1] == "--" {
+			return append(pos, rest...), nil
+		}
+		pos = append(pos, rest[0])
+		args = rest[1:]
+	}
+}
+
+// decoder returns a JSON decoder for r that decodes numbers as json.Number
+// values to preserve their precision.
+func decoder(r io.Reader) *json.Decoder {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return dec
+}
+
+// execute executes cmd against each JSON document read from in, writing the
+// results to out. Returns exitFalse if cmd is in exists or match mode and
+// any result is not true.
+func (cmd *command) execute(ctx context.Context, in io.Reader, out io.Writer) (int, error) {
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	if cmd.indent {
+		enc.SetIndent("", "  ")
+	}
+
+	status := exitOK
+	dec := decoder(in)
+	for {
+		var doc any
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return status, nil
+			}
+			return exitError, err
+		}
+
+		ok, err := cmd.write(ctx, doc, enc, out)
+		if err != nil {
+			return exitError, err
+		}
+		if !ok {
+			status = exitFalse
+		}
+	}
+}
+
+// noResult is the default value for first mode, to distinguish a first
+// result of null from no result.
+type noResult struct{}
+
+// write executes cmd against doc and encodes the results to enc, or writes
+// them to out in PostgreSQL jsonb text format if cmd.pg is true. Returns
+// false if cmd is in exists or match mode and the result is not true.
+func (cmd *command) write(ctx context.Context, doc any, enc *json.Encoder, out io.Writer) (bool, error) {
+	switch cmd.mode {
+	case modeExists, modeMatch:
+		var res exec.Ternary
+		var err error
+		if cmd.mode == modeExists {
+			res, err = cmd.path.ExistsTri(ctx, doc, cmd.opts...)
+		} else {
+			res, err = cmd.path.MatchTri(ctx, doc, cmd.opts...)
+		}
+		if err != nil {
+			return false, err
+		}
+		if res == exec.Null {
+			return false, enc.Encode(nil)
+		}
+		return res == exec.True, enc.Encode(res == exec.True)
+	case modeFirst:
+		res, err := cmd.path.FirstOrDefault(ctx, doc, noResult{}, cmd.opts...)
+		if err != nil {
+			return false, err
+		}
+		if _, ok := res.(noResult); ok {
+			return true, nil
+		}
+		return true, enc.Encode(res)
+	default:
+		if cmd.pg {
+			res, err := cmd.path.QueryText(ctx, doc, cmd.opts...)
+			if err != nil {
+				return false, err
+			}
+			for _, item := range res {
+				if _, err := fmt.Fprintln(out, item); err != nil {
+					return false, err
+				}
+			}
+			return true, nil
+		}
+		res, err := cmd.path.QueryArray(ctx, doc, cmd.opts...)
+		if err != nil {
+			return false, err
+		}
+		for _, item := range res {
+			if err := enc.Encode(item); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
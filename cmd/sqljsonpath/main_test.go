@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	const doc = `{"a": [1, 2, 3.50, 12345678901234567890], "b": {"c": "<hi>"}, "n": null}`
+
+	for _, tc := range []struct {
+		name   string
+		args   []string
+		stdin  string
+		status int
+		out    string
+		err    string
+	}{
+		{
+			name:  "query",
+			args:  []string{`$.a[*] ? (@ > 2)`},
+			stdin: doc,
+			out:   "3.50\n12345678901234567890\n",
+		},
+		{
+			name:  "query_object",
+			args:  []string{`$.b`},
+			stdin: doc,
+			out:   `{"c":"<hi>"}` + "\n",
+		},
+		{
+			name:  "query_indent",
+			args:  []string{"--indent", `$.b`},
+			stdin: doc,
+			out:   "{\n  \"c\": \"<hi>\"\n}\n",
+		},
+		{
+			name:  "query_none",
+			args:  []string{`$.a[*] ? (@ > 100000000000000000000)`},
+			stdin: doc,
+		},
+		{
+			name:  "query_stream",
+			args:  []string{`$.x`},
+			stdin: `{"x": 1} {"x": "two"}` + "\n" + `{"y": 3}`,
+			out:   "1\n\"two\"\n",
+		},
+		{
+			name:  "query_datetime",
+			args:  []string{`$.d.date()`},
+			stdin: `{"d": "2024-06-05"}`,
+			out:   "\"2024-06-05\"\n",
+		},
+		{
+			name:  "query_pg",
+			args:  []string{"--pg", `$`},
+			stdin: `{"bb": [1e2, 3.50], "a": "é\u0001", "ccc": {}}`,
+			out:   `{"a": "é\u0001", "bb": [100, 3.50], "ccc": {}}` + "\n",
+		},
+		{
+			name:  "query_pg_stream",
+			args:  []string{"--pg", `$.x`},
+			stdin: `{"x": 1} {"x": "two"}`,
+			out:   "1\n\"two\"\n",
+		},
+		{
+			name:  "vars",
+			args:  []string{`$.a[*] ? (@ >= $min)`, "--vars", `{"min": 2}`},
+			stdin: doc,
+			out:   "2\n3.50\n12345678901234567890\n",
+		},
+		{
+			name:  "first",
+			args:  []string{"--first", `$.a[*] ? (@ > 1)`},
+			stdin: doc,
+			out:   "2\n",
+		},
+		{
+			name:  "first_null",
+			args:  []string{"--first", `$.n`},
+			stdin: doc,
+			out:   "null\n",
+		},
+		{
+			name:  "first_none",
+			args:  []string{"--first", `$.nope`},
+			stdin: doc,
+		},
+		{
+			name:  "exists_true",
+			args:  []string{"--exists", `$.a[*] ? (@ == 2)`},
+			stdin: doc,
+			out:   "true\n",
+		},
+		{
+			name:   "exists_false",
+			args:   []string{"--exists", `$.a[*] ? (@ == 42)`},
+			stdin:  doc,
+			status: exitFalse,
+			out:    "false\n",
+		},
+		{
+			name:   "exists_stream",
+			args:   []string{"--exists", `$.x`},
+			stdin:  `{"x": 1} {"y": 2} {"x": 3}`,
+			status: exitFalse,
+			out:    "true\nfalse\ntrue\n",
+		},
+		{
+			name:  "match_true",
+			args:  []string{"--match", `$.a[0] == 1`},
+			stdin: doc,
+			out:   "true\n",
+		},
+		{
+			name:   "match_false",
+			args:   []string{"--match", `$.a[0] == 2`},
+			stdin:  doc,
+			status: exitFalse,
+			out:    "false\n",
+		},
+		{
+			name:   "match_null",
+			args:   []string{"--match", `$.b == 2`},
+			stdin:  doc,
+			status: exitFalse,
+			out:    "null\n",
+		},
+		{
+			name:   "strict_error",
+			args:   []string{`strict $.a[10]`},
+			stdin:  doc,
+			status: exitError,
+			err:    "sqljsonpath: exec: jsonpath array subscript is out of bounds\n",
+		},
+		{
+			name:  "silent",
+			args:  []string{"--silent", `strict $.a[10]`},
+			stdin: doc,
+		},
+		{
+			name:   "no_tz",
+			args:   []string{`$.d.timestamp_tz() < "2024-06-05 12:00:00".timestamp()`},
+			stdin:  `{"d": "2024-06-05 10:00:00+00"}`,
+			status: exitError,
+			err:    "sqljsonpath: exec: cannot convert value from timestamp to timestamptz without time zone usage. HINT: Use WithTZ() option for time zone support\n",
+		},
+		{
+			name:  "tz",
+			args:  []string{"--tz", `$.d.timestamp_tz() < "2024-06-05 12:00:00".timestamp()`},
+			stdin: `{"d": "2024-06-05 10:00:00+00"}`,
+			out:   "true\n",
+		},
+		{
+			name:  "dash_path",
+			args:  []string{"--", `-$.a[0]`},
+			stdin: doc,
+			out:   "-1\n",
+		},
+		{
+			name:  "stdin_dash",
+			args:  []string{`$.a[0]`, "-"},
+			stdin: doc,
+			out:   "1\n",
+		},
+		{
+			name:   "no_path",
+			status: exitError,
+			err:    "sqljsonpath: usage: sqljsonpath [flags] PATH [FILE]\n",
+		},
+		{
+			name:   "too_many_args",
+			args:   []string{"$", "a.json", "b.json"},
+			status: exitError,
+			err:    "sqljsonpath: usage: sqljsonpath [flags] PATH [FILE]\n",
+		},
+		{
+			name:   "bad_path",
+			args:   []string{"$.a["},
+			status: exitError,
+			err:    "sqljsonpath: path: parser: syntax error at 1:5\n",
+		},
+		{
+			name:   "bad_vars",
+			args:   []string{"--vars", "[1]", "$"},
+			status: exitError,
+			err:    "sqljsonpath: invalid --vars: json: cannot unmarshal array into Go value of type exec.Vars\n",
+		},
+		{
+			name:   "modes",
+			args:   []string{"--exists", "--first", "$"},
+			status: exitError,
+			err:    "sqljsonpath: only one of --exists, --match, and --first allowed\n",
+		},
+		{
+			name:   "pg_mode",
+			args:   []string{"--pg", "--exists", "$"},
+			status: exitError,
+			err:    "sqljsonpath: --pg not allowed with --exists, --match, --first, or --indent\n",
+		},
+		{
+			name:   "pg_indent",
+			args:   []string{"--pg", "--indent", "$"},
+			status: exitError,
+			err:    "sqljsonpath: --pg not allowed with --exists, --match, --first, or --indent\n",
+		},
+		{
+			name:   "bad_json",
+			args:   []string{"$"},
+			stdin:  `{"x": 1} {"x": `,
+			status: exitError,
+			out:    "{\"x\":1}\n",
+			err:    "sqljsonpath: unexpected EOF\n",
+		},
+		{
+			name:   "missing_file",
+			args:   []string{"$", "nonesuch.json"},
+			status: exitError,
+			err:    "sqljsonpath: open nonesuch.json: no such file or directory\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			var stdout, stderr bytes.Buffer
+			status := run(context.Background(), tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+			a.Equal(tc.status, status)
+			a.Equal(tc.out, stdout.String())
+			a.Equal(tc.err, stderr.String())
+		})
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	file := filepath.Join(t.TempDir(), "doc.json")
+	r.NoError(os.WriteFile(file, []byte(`{"x": [true, false]}`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	status := run(context.Background(), []string{`$.x[*]`, file}, strings.NewReader(`{"x": 1}`), &stdout, &stderr)
+	a.Equal(exitOK, status)
+	a.Equal("true\nfalse\n", stdout.String())
+	a.Empty(stderr.String())
+}
+
+func TestRunHelp(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var stdout, stderr bytes.Buffer
+	status := run(context.Background(), []string{"-h"}, strings.NewReader(""), &stdout, &stderr)
+	a.Equal(exitOK, status)
+	a.Empty(stdout.String())
+	a.Contains(stderr.String(), "usage: sqljsonpath [flags] PATH [FILE]\n\nFlags:\n")
+	a.Contains(stderr.String(), "-exists")
+
+	stderr.Reset()
+	status = run(context.Background(), []string{"--nope", "$"}, strings.NewReader(""), &stdout, &stderr)
+	a.Equal(exitError, status)
+	a.Contains(stderr.String(), "flag provided but not defined: -nope\n")
+}
@@ -0,0 +1,191 @@
+package path
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/theory/sqljson/path/ast"
+)
+
+// PathInfo describes the structure of a Path, as reported by [Analyze].
+type PathInfo struct {
+	// Simple is true if the path consists only of the root followed by
+	// member accessors and single array subscripts of non-negative integer
+	// literals, e.g., `$.a.b[0]`, and so can be answered by looking up Keys.
+	// In lax mode, such a path also unwraps arrays where it expects objects
+	// and wraps other values where it expects arrays.
+	Simple bool
+
+	// Keys lists the member names (as strings) and array indexes (as ints)
+	// selected by a Simple path, in order, and is nil for other paths. It is
+	// empty but not nil for the path `$`.
+	Keys []any
+
+	// Variables lists the names of the variables used by the path, in order
+	// of first appearance.
+	Variables []string
+
+	// Methods lists the names of the item methods used by the path, without
+	// the leading dot or parentheses, e.g., "size" or "datetime", in order of
+	// first appearance.
+	Methods []string
+
+	// StrictErrors is true if executing the path in strict mode can return
+	// an error for some JSON value, e.g., because a member is missing, an
+	// array subscript is out of bounds, an item method does not support an
+	// item's type, or a variable is undefined. Errors raised within
+	// predicates, such as filter expressions and comparisons, are not
+	// considered, since predicates convert them to unknown. The analysis is
+	// conservative: a path for which StrictErrors is true may never fail in
+	// practice, but one for which it's false never fails, except for
+	// cancellation or exceeding the maximum depth.
+	StrictErrors bool
+}
+
+// Analyze returns a PathInfo describing path, such as whether it can be
+// answered by a simple lookup of the keys it contains.
+func Analyze(path *Path) PathInfo {
+	info := PathInfo{}
+	info.Keys, info.Simple = simpleKeys(path)
+	ast.Walk(&analyzer{info: &info}, path.Root())
+	return info
+}
+
+// simpleKeys returns the keys selected by path and true if it's a simple
+// path. Otherwise it returns nil and false.
+func simpleKeys(path *Path) ([]any, bool) {
+	if path.IsPredicate() {
+		return nil, false
+	}
+	root, ok := path.Root().(*ast.ConstNode)
+	if !ok || root.Const() != ast.ConstRoot {
+		return nil, false
+	}
+
+	keys := []any{}
+	for node := root.Next(); node != nil; node = node.Next() {
+		switch node := node.(type) {
+		case *ast.KeyNode:
+			keys = append(keys, node.Text())
+		case *ast.ArrayIndexNode:
+			idx, ok := literalIndex(node)
+			if !ok {
+				return nil, false
+			}
+			keys = append(keys, idx)
+		default:
+			return nil, false
+		}
+	}
+	return keys, true
+}
+
+// literalIndex returns the index of node and true if it has a single
+// subscript that is a non-negative integer literal.
+func literalIndex(node *ast.ArrayIndexNode) (int, bool) {
+	subs := node.Subscripts()
+	if len(subs) != 1 {
+		return 0, false
+	}
+	sub, ok := subs[0].(*ast.BinaryNode)
+	if !ok || sub.Right() != nil {
+		return 0, false
+	}
+	integer, ok := sub.Left().(*ast.IntegerNode)
+	if !ok || integer.Next() != nil || integer.Int() < 0 {
+		return 0, false
+	}
+	return int(integer.Int()), true
+}
+
+// analyzer is an [ast.Visitor] that collects a PathInfo.
+type analyzer struct {
+	info        *PathInfo
+	inPredicate bool
+}
+
+// Visit records information about node in a.info and returns the visitor
+// for its children.
+func (a *analyzer) Visit(node ast.Node) ast.Visitor {
+	if node == nil {
+		return nil
+	}
+
+	switch node := node.(type) {
+	case *ast.VariableNode:
+		// Undefined variables are always errors.
+		a.info.StrictErrors = true
+		a.addVariable(node.Text())
+	case *ast.KeyNode, *ast.ArrayIndexNode:
+		a.mayError()
+	case *ast.ConstNode:
+		switch node.Const() {
+		case ast.ConstAnyArray, ast.ConstAnyKey:
+			a.mayError()
+		default:
+			// Cannot fail.
+		}
+	case *ast.MethodNode:
+		a.mayError()
+		if node.Name() == ast.MethodCustom {
+			a.addMethod(node.Custom())
+		} else {
+			a.addMethod(node.Name().String())
+		}
+	case *ast.BinaryNode:
+		switch node.Operator() {
+		case ast.BinaryAnd, ast.BinaryOr, ast.BinaryEqual, ast.BinaryNotEqual,
+			ast.BinaryLess, ast.BinaryGreater, ast.BinaryLessOrEqual,
+			ast.BinaryGreaterOrEqual, ast.BinaryStartsWith:
+			return &analyzer{info: a.info, inPredicate: true}
+		case ast.BinaryDecimal:
+			a.mayError()
+			a.addMethod(node.Operator().String())
+		default:
+			a.mayError()
+		}
+	case *ast.UnaryNode:
+		switch node.Operator() {
+		case ast.UnaryExists, ast.UnaryNot, ast.UnaryIsUnknown, ast.UnaryFilter:
+			return &analyzer{info: a.info, inPredicate: true}
+		case ast.UnaryPlus, ast.UnaryMinus:
+			a.mayError()
+		default:
+			a.mayError()
+			a.addMethod(node.Operator().String())
+		}
+	case *ast.RegexNode:
+		if _, err := node.Compile(); err != nil {
+			// Unsupported patterns are always errors.
+			a.info.StrictErrors = true
+		}
+		return &analyzer{info: a.info, inPredicate: true}
+	}
+
+	return a
+}
+
+// mayError records that the current node may raise an error in strict mode,
+// unless it's in a predicate.
+func (a *analyzer) mayError() {
+	if !a.inPredicate {
+		a.info.StrictErrors = true
+	}
+}
+
+// addVariable adds name to the list of variables if it's not already
+// present.
+func (a *analyzer) addVariable(name string) {
+	if !slices.Contains(a.info.Variables, name) {
+		a.info.Variables = append(a.info.Variables, name)
+	}
+}
+
+// addMethod adds the method name to the list of methods if it's not already
+// present, stripping any leading dot and trailing parentheses.
+func (a *analyzer) addMethod(name string) {
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "."), "()")
+	if !slices.Contains(a.info.Methods, name) {
+		a.info.Methods = append(a.info.Methods, name)
+	}
+}
@@ -0,0 +1,216 @@
+package path
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// JSON values against which to check StrictErrors.
+	docs := []any{
+		nil,
+		true,
+		int64(1),
+		"x",
+		[]any{},
+		[]any{int64(1), "x"},
+		map[string]any{},
+		map[string]any{"a": map[string]any{"b": []any{int64(1)}}},
+		map[string]any{"a": int64(1), "b": "x", "c": []any{"hi"}},
+	}
+
+	for _, tc := range []struct {
+		name string
+		path string
+		exp  PathInfo
+	}{
+		{
+			name: "root",
+			path: `$`,
+			exp:  PathInfo{Simple: true, Keys: []any{}},
+		},
+		{
+			name: "keys",
+			path: `$.a.b`,
+			exp:  PathInfo{Simple: true, Keys: []any{"a", "b"}, StrictErrors: true},
+		},
+		{
+			name: "keys_and_index",
+			path: `$.a.b[0]`,
+			exp:  PathInfo{Simple: true, Keys: []any{"a", "b", 0}, StrictErrors: true},
+		},
+		{
+			name: "quoted_keys",
+			path: `$."a.b"."say \"hi\""[12]`,
+			exp:  PathInfo{Simple: true, Keys: []any{"a.b", `say "hi"`, 12}, StrictErrors: true},
+		},
+		{
+			name: "strict_keys",
+			path: `strict $.a[1]`,
+			exp:  PathInfo{Simple: true, Keys: []any{"a", 1}, StrictErrors: true},
+		},
+		{
+			name: "negative_index",
+			path: `$.a[-1]`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+		{
+			name: "float_index",
+			path: `$.a[1.0]`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+		{
+			name: "range",
+			path: `$.a[1 to 2]`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+		{
+			name: "multiple_subscripts",
+			path: `$.a[1, 2]`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+		{
+			name: "last",
+			path: `$.a[last]`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+		{
+			name: "wildcard_array",
+			path: `$.a[*]`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+		{
+			name: "wildcard_key",
+			path: `$.*`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+		{
+			name: "any",
+			path: `$.**`,
+			exp:  PathInfo{},
+		},
+		{
+			name: "method",
+			path: `$.a.size()`,
+			exp:  PathInfo{Methods: []string{"size"}, StrictErrors: true},
+		},
+		{
+			name: "methods",
+			path: `$.type().size().type()`,
+			exp:  PathInfo{Methods: []string{"type", "size"}, StrictErrors: true},
+		},
+		{
+			name: "decimal",
+			path: `$.decimal(4, 2)`,
+			exp:  PathInfo{Methods: []string{"decimal"}, StrictErrors: true},
+		},
+		{
+			name: "datetime",
+			path: `$.datetime("HH24:MI").time()`,
+			exp:  PathInfo{Methods: []string{"datetime", "time"}, StrictErrors: true},
+		},
+		{
+			name: "variable",
+			path: `$.a[$i]`,
+			exp:  PathInfo{Variables: []string{"i"}, StrictErrors: true},
+		},
+		{
+			name: "variable_root",
+			path: `$x`,
+			exp:  PathInfo{Variables: []string{"x"}, StrictErrors: true},
+		},
+		{
+			name: "filter",
+			path: `$ ? (@.a.size() > $min && @.b == $max || @.c == $min)`,
+			exp: PathInfo{
+				Variables:    []string{"min", "max"},
+				Methods:      []string{"size"},
+				StrictErrors: true,
+			},
+		},
+		{
+			name: "filter_no_errors",
+			path: `$ ? (@.a.b[0].size() > 1 && exists(@.*))`,
+			exp:  PathInfo{Methods: []string{"size"}},
+		},
+		{
+			name: "filter_then_key",
+			path: `$ ? (@.a == 1).b`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+		{
+			name: "predicate",
+			path: `$.a.b[*] > 1`,
+			exp:  PathInfo{},
+		},
+		{
+			name: "predicate_method",
+			path: `$.a.double() like_regex "^1" flag "i"`,
+			exp:  PathInfo{Methods: []string{"double"}},
+		},
+		{
+			name: "predicate_unsupported_regex",
+			path: `$ like_regex "(a)\\1"`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+		{
+			name: "starts_with",
+			path: `$ starts with "x"`,
+			exp:  PathInfo{},
+		},
+		{
+			name: "is_unknown",
+			path: `($.a > 1) is unknown`,
+			exp:  PathInfo{},
+		},
+		{
+			name: "arithmetic",
+			path: `$ + 1`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+		{
+			name: "unary_minus",
+			path: `-$`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+		{
+			name: "literal",
+			path: `"hi"`,
+			exp:  PathInfo{},
+		},
+		{
+			name: "conservative",
+			path: `$ ? (@[0] == 1)[0]`,
+			exp:  PathInfo{StrictErrors: true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := Parse(tc.path)
+			r.NoError(err)
+			a.Equal(tc.exp, Analyze(path))
+
+			// Make sure paths without StrictErrors don't fail for the test
+			// documents.
+			if tc.exp.StrictErrors {
+				return
+			}
+			strict, err := Parse("strict " + strings.TrimPrefix(tc.path, "strict "))
+			r.NoError(err)
+			for _, doc := range docs {
+				_, err := strict.Query(ctx, doc)
+				r.NoError(err)
+			}
+		})
+	}
+}
@@ -60,7 +60,6 @@ package ast
 //go:generate stringer -linecomment -output ast_string.go -type Constant,BinaryOperator,UnaryOperator,MethodName
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -247,12 +246,14 @@ const (
 	MethodInteger                    // .integer()
 	MethodNumber                     // .number()
 	MethodString                     // .string()
+	MethodCustom                     // custom
 )
 
 // MethodNode represents a path method.
 type MethodNode struct {
-	name MethodName
-	next Node
+	name   MethodName
+	custom string
+	next   Node
 }
 
 // NewMethod returns a new MethodNode with name.
@@ -260,9 +261,18 @@ func NewMethod(name MethodName) *MethodNode {
 	return &MethodNode{name: name}
 }
 
+// NewCustomMethod returns a new MethodNode for the custom method named name.
+// Its Name is MethodCustom.
+func NewCustomMethod(name string) *MethodNode {
+	return &MethodNode{name: MethodCustom, custom: name}
+}
+
 // String returns the SQL/JSON representation of the method: A dot, the name,
 // then parentheses.
 func (n *MethodNode) String() string {
+	if n.name == MethodCustom {
+		return "." + n.custom + "()"
+	}
 	return n.name.String()
 }
 
@@ -271,9 +281,15 @@ func (n *MethodNode) Name() MethodName {
 	return n.name
 }
 
+// Custom returns the name of a custom method, or an empty string if the
+// method is not custom.
+func (n *MethodNode) Custom() string {
+	return n.custom
+}
+
 // writeTo writes the string representation of n to buf.
 func (n *MethodNode) writeTo(buf *strings.Builder, _, _ bool) {
-	buf.WriteString(n.name.String())
+	buf.WriteString(n.String())
 	if next := n.Next(); next != nil {
 		next.writeTo(buf, true, true)
 	}
@@ -306,7 +322,42 @@ func (n *quotedString) Text() string {
 
 // String returns the SQL/JSON path-encoded quoted string.
 func (n *quotedString) String() string {
-	return strconv.Quote(n.str)
+	return quote(n.str)
+}
+
+// quote returns str as a double-quoted SQL/JSON path string literal. Like
+// Postgres escape_json(), it escapes only double quotes, backslashes, and
+// control characters, preferring \b, \f, \n, \r, and \t to \u00XX escapes,
+// and leaves all other characters, including non-ASCII characters, as-is.
+func quote(str string) string {
+	const hex = "0123456789abcdef"
+	buf := make([]byte, 0, len(str)+2)
+	buf = append(buf, '"')
+	for i := range len(str) {
+		switch ch := str[i]; ch {
+		case '\b':
+			buf = append(buf, `\b`...)
+		case '\f':
+			buf = append(buf, `\f`...)
+		case '\n':
+			buf = append(buf, `\n`...)
+		case '\r':
+			buf = append(buf, `\r`...)
+		case '\t':
+			buf = append(buf, `\t`...)
+		case '"':
+			buf = append(buf, `\"`...)
+		case '\\':
+			buf = append(buf, `\\`...)
+		default:
+			if ch < ' ' {
+				buf = append(buf, '\\', 'u', '0', '0', hex[ch>>4], hex[ch&0xf])
+			} else {
+				buf = append(buf, ch)
+			}
+		}
+	}
+	return string(append(buf, '"'))
 }
 
 // writeTo writes n.String to buf.
@@ -436,10 +487,9 @@ type NumericNode struct {
 }
 
 // NewNumeric returns a new NumericNode representing num. Panics if num cannot
-// be parsed into JSON-compatible float64.
+// be parsed into float64.
 func NewNumeric(num string) *NumericNode {
-	f, err := strconv.ParseFloat(num, 64)
-	if err != nil {
+	if _, err := strconv.ParseFloat(num, 64); err != nil {
 		panic(err)
 	}
 
@@ -453,13 +503,62 @@ func NewNumeric(num string) *NumericNode {
 	// > (and in JavaScript, but not in SQL proper), there must not be an
 	// > underscore separator directly after the radix prefix.
 	//
-	// Rely on JSON semantics, a subset of the JavaScript.
-	str, err := json.Marshal(f)
-	if err != nil {
-		panic(err)
+	// Normalize like the PostgreSQL numeric type.
+	return &NumericNode{&numberNode{literal: num, parsed: normalizeNumeric(num)}}
+}
+
+// normalizeNumeric normalizes the decimal numeric literal num the same way
+// PostgreSQL normalizes numeric values: without an exponent, without leading
+// zeros other than a single zero before the decimal point, and with the scale
+// (number of digits after the decimal point) of the literal, adjusted by its
+// exponent. For example, 1.50 becomes 1.50, 1.50e1 becomes 15.0, and .1e-1
+// becomes 0.01. num must be a valid decimal literal.
+func normalizeNumeric(num string) string {
+	num = strings.ReplaceAll(num, "_", "")
+	neg := false
+	switch num[0] {
+	case '-':
+		neg = true
+		num = num[1:]
+	case '+':
+		num = num[1:]
+	}
+
+	mantissa, expo, _ := strings.Cut(strings.ToLower(num), "e")
+	exp := 0
+	if expo != "" {
+		exp, _ = strconv.Atoi(expo)
+	}
+
+	// Find the position of the decimal point in the mantissa digits and pad
+	// them with zeros so the point falls within them.
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	digits := intPart + fracPart
+	point := len(intPart) + exp
+	switch {
+	case point > len(digits):
+		digits += strings.Repeat("0", point-len(digits))
+	case point < 0:
+		digits = strings.Repeat("0", -point) + digits
+		point = 0
 	}
 
-	return &NumericNode{&numberNode{literal: num, parsed: string(str)}}
+	buf := new(strings.Builder)
+	if integer := strings.TrimLeft(digits[:point], "0"); integer != "" {
+		buf.WriteString(integer)
+	} else {
+		buf.WriteByte('0')
+	}
+	if frac := digits[point:]; frac != "" {
+		buf.WriteByte('.')
+		buf.WriteString(frac)
+	}
+
+	// Negative zero is zero.
+	if neg && strings.Trim(digits, "0") != "" {
+		return "-" + buf.String()
+	}
+	return buf.String()
 }
 
 // Float returns the floating point number corresponding to n.
@@ -838,23 +937,37 @@ func (n *AnyNode) Next() Node {
 // RegexNode represents a regular expression.
 type RegexNode struct {
 	// jpiLikeRegex
-	operand Node
-	pattern string
-	flags   regexFlags
-	next    Node
+	operand     Node
+	pattern     string
+	flags       regexFlags
+	goPattern   string
+	unsupported error
+	next        Node
 }
 
 // NewRegex returns anew RegexNode that compares node to the regular expression
-// pattern configured by flags.
+// pattern configured by flags. It translates pattern from PostgreSQL regular
+// expression syntax to the RE2 syntax of the [regexp] package. Returns an
+// error if pattern is invalid or flags contains an unrecognized flag.
+// Patterns that use PostgreSQL constructs unsupported by RE2, such as back
+// references, are valid, but [RegexNode.Compile] returns an error for them.
 func NewRegex(expr Node, pattern, flags string) (*RegexNode, error) {
 	f, err := newRegexFlags(flags)
 	if err != nil {
 		return nil, err
 	}
-	if err := validateRegex(pattern, f); err != nil {
+
+	node := &RegexNode{operand: expr, pattern: pattern, flags: f}
+	if f.shouldQuoteMeta() {
+		node.goPattern = regexp.QuoteMeta(pattern)
+	} else {
+		node.goPattern, node.unsupported = translateRegex(pattern, f.isExpanded())
+	}
+
+	if err := validateRegex(node.goPattern, f); err != nil {
 		return nil, err
 	}
-	return &RegexNode{operand: expr, pattern: pattern, flags: f}, nil
+	return node, nil
 }
 
 // String returns the RegexNode as a SQL/JSON path 'like_regex' expression.
@@ -872,7 +985,7 @@ func (n *RegexNode) writeTo(buf *strings.Builder, _, withParens bool) {
 	}
 
 	n.operand.writeTo(buf, false, n.operand.priority() <= n.priority())
-	buf.WriteString(fmt.Sprintf(" like_regex %q%v", n.pattern, n.flags))
+	buf.WriteString(" like_regex " + quote(n.pattern) + n.flags.String())
 
 	if withParens {
 		buf.WriteRune(')')
@@ -885,13 +998,27 @@ func (n *RegexNode) writeTo(buf *strings.Builder, _, withParens bool) {
 // priority returns the priority of the RegexNode, which is always 6.
 func (*RegexNode) priority() uint8 { return lowestPriority }
 
-// Regexp returns a regexp.Regexp compiled from n.
+// Compile compiles n into a regexp.Regexp. Returns an error if the pattern
+// uses PostgreSQL regular expression constructs that the regexp package does
+// not support, such as back references, lookahead constraints, and the word
+// boundary constraints \m and \M.
+func (n *RegexNode) Compile() (*regexp.Regexp, error) {
+	if n.unsupported != nil {
+		return nil, n.unsupported
+	}
+	//nolint:wrapcheck
+	return regexp.Compile(n.flags.goFlags() + n.goPattern)
+}
+
+// Regexp returns a regexp.Regexp compiled from n. Panics if the pattern uses
+// constructs the regexp package does not support; use [RegexNode.Compile] to
+// handle them as errors.
 func (n *RegexNode) Regexp() *regexp.Regexp {
-	flags := n.flags.goFlags()
-	if n.flags.shouldQuoteMeta() {
-		return regexp.MustCompile(flags + regexp.QuoteMeta(n.pattern))
+	re, err := n.Compile()
+	if err != nil {
+		panic(err)
 	}
-	return regexp.MustCompile(n.flags.goFlags() + n.pattern)
+	return re
 }
 
 // Operand returns the RegexNode's operand.
@@ -916,10 +1043,18 @@ type AST struct {
 	pred bool
 }
 
+// MaxDepth is the maximum nesting depth of nodes in an AST. [New] returns an
+// error for deeper node trees, preventing stack overflows when processing
+// pathologically nested paths.
+const MaxDepth = 10_000
+
 // New creates a new AST with n as its root. If lax is true it's considered a
 // lax path query, and if pred is true it's considered a predicate query.
+// Returns an error if n is invalid, for example if it applies ! or is
+// unknown to an operand other than a predicate, which the PostgreSQL
+// grammar does not allow.
 func New(lax, pred bool, n Node) (*AST, error) {
-	if err := validateNode(n, 0, false); err != nil {
+	if err := validateNode(n, 0, 0, false); err != nil {
 		return nil, err
 	}
 	return &AST{root: n, lax: lax, pred: pred}, nil
@@ -931,7 +1066,10 @@ func (a *AST) IsLax() bool { return a.lax }
 // IsStrict indicates whether the path query is strict.
 func (a *AST) IsStrict() bool { return !a.lax }
 
-// String returns the SQL/JSON Path-encoded string representation of the path.
+// String returns the normalized SQL/JSON Path-encoded string representation
+// of the path, formatted the same as the PostgreSQL jsonpath type. Parsing
+// the string returns an equivalent AST with the same string representation,
+// making it suitable for storage or as a cache key.
 func (a *AST) String() string {
 	buf := new(strings.Builder)
 	if !a.lax {
@@ -954,10 +1092,17 @@ func (a *AST) IsPredicate() bool {
 
 // validateNode recursively validates nodes. It's based on the Postgres
 // flattenJsonPathParseItem function, but does not turn the AST into a binary
-// representation, just does a second pass to detect any further issues.
+// representation, just does a second pass to detect any further issues. The
+// level param tracks the nesting level of node to prevent stack overflows.
 //
-//nolint:gocognit
-func validateNode(node Node, depth int, inSubscript bool) error {
+//nolint:gocognit,gocyclo
+func validateNode(node Node, depth, level int, inSubscript bool) error {
+	if level >= MaxDepth {
+		//nolint:err113
+		return errors.New("maximum nesting depth exceeded")
+	}
+	level++
+
 	argDepth := 0
 	switch node := node.(type) {
 	case nil:
@@ -965,21 +1110,38 @@ func validateNode(node Node, depth int, inSubscript bool) error {
 	case *StringNode, *VariableNode, *KeyNode, *NumericNode, *IntegerNode:
 		// Nothing to do.
 	case *BinaryNode:
-		if err := validateNode(node.left, depth+argDepth, inSubscript); err != nil {
+		if err := validateNode(node.left, depth+argDepth, level, inSubscript); err != nil {
 			return err
 		}
-		if err := validateNode(node.right, depth+argDepth, inSubscript); err != nil {
+		if err := validateNode(node.right, depth+argDepth, level, inSubscript); err != nil {
 			return err
 		}
+		if node.op == BinaryDecimal {
+			if err := validateDecimalArg(node.left, "precision"); err != nil {
+				return err
+			}
+			if err := validateDecimalArg(node.right, "scale"); err != nil {
+				return err
+			}
+		}
 	case *UnaryNode:
 		if node.op == UnaryFilter {
 			argDepth++
 		}
-		if err := validateNode(node.operand, depth+argDepth, inSubscript); err != nil {
+		if err := validateNode(node.operand, depth+argDepth, level, inSubscript); err != nil {
 			return err
 		}
+		switch node.op {
+		case UnaryNot, UnaryIsUnknown:
+			if !isPredicate(node.operand) {
+				//nolint:err113
+				return fmt.Errorf("%v can only be applied to a predicate", node.op)
+			}
+		default:
+			// Any operand will do.
+		}
 	case *RegexNode:
-		if err := validateNode(node.operand, depth, inSubscript); err != nil {
+		if err := validateNode(node.operand, depth, level, inSubscript); err != nil {
 			return err
 		}
 	case *ConstNode:
@@ -999,13 +1161,13 @@ func validateNode(node Node, depth int, inSubscript bool) error {
 		}
 	case *ArrayIndexNode:
 		for _, n := range node.subscripts {
-			if err := validateNode(n, depth+argDepth, true); err != nil {
+			if err := validateNode(n, depth+argDepth, level, true); err != nil {
 				return err
 			}
 		}
 	}
 	if next := node.Next(); next != nil {
-		if err := validateNode(next, depth, inSubscript); err != nil {
+		if err := validateNode(next, depth, level, inSubscript); err != nil {
 			return err
 		}
 	}
@@ -1013,6 +1175,53 @@ func validateNode(node Node, depth int, inSubscript bool) error {
 	return nil
 }
 
+// validateDecimalArg returns an error if node, the .decimal() argument named
+// arg, is an integer literal out of the range of int32. The executor checks
+// the ranges of valid precisions and scales.
+func validateDecimalArg(node Node, arg string) error {
+	if num, ok := node.(*IntegerNode); ok {
+		if n := num.Int(); n > math.MaxInt32 || n < math.MinInt32 {
+			//nolint:err113
+			return fmt.Errorf(
+				"%v of jsonpath item method .decimal() is out of integer range",
+				arg,
+			)
+		}
+	}
+	return nil
+}
+
+// isPredicate returns true if node is a predicate, which evaluates to true,
+// false, or unknown: a comparison, logical, starts with, like_regex, exists,
+// !, or is unknown expression with no next node. The PostgreSQL grammar
+// allows only predicates as the operands of ! and is unknown.
+func isPredicate(node Node) bool {
+	if node == nil || node.Next() != nil {
+		return false
+	}
+	switch node := node.(type) {
+	case *BinaryNode:
+		switch node.op {
+		case BinaryAnd, BinaryOr, BinaryEqual, BinaryNotEqual, BinaryLess,
+			BinaryGreater, BinaryLessOrEqual, BinaryGreaterOrEqual, BinaryStartsWith:
+			return true
+		default:
+			return false
+		}
+	case *UnaryNode:
+		switch node.op {
+		case UnaryExists, UnaryNot, UnaryIsUnknown:
+			return true
+		default:
+			return false
+		}
+	case *RegexNode:
+		return true
+	default:
+		return false
+	}
+}
+
 // NewUnaryOrNumber returns a new node for op ast.UnaryPlus or ast.UnaryMinus.
 // If node is numeric and not the first item in an accessor list, it returns a
 // ast.NumericNode or ast.IntegerNode, as appropriate.
@@ -1025,8 +1234,8 @@ func NewUnaryOrNumber(op UnaryOperator, node Node) Node {
 				// Just a positive number, return it.
 				return node
 			case UnaryMinus:
-				// Just a negative number, return it with the minus sign.
-				return NewNumeric("-" + node.literal)
+				// Just a negative number, return it with the sign flipped.
+				return NewNumeric(negateLiteral(node.literal))
 			default:
 				panic(fmt.Sprintf("Operator must be + or - but is %v", op))
 			}
@@ -1036,8 +1245,13 @@ func NewUnaryOrNumber(op UnaryOperator, node Node) Node {
 				// Just a positive number, return it.
 				return node
 			case UnaryMinus:
-				// Just a negative number, return it with the minus sign.
-				return NewInteger("-" + node.literal)
+				// Just a negative number, return it with the sign flipped.
+				// The negation of math.MinInt64 is too large for an
+				// IntegerNode.
+				if node.Int() == math.MinInt64 {
+					return NewNumeric("9223372036854775808")
+				}
+				return NewInteger(negateLiteral(node.literal))
 			default:
 				panic(fmt.Sprintf("Operator must be + or - but is %v", op))
 			}
@@ -1046,3 +1260,12 @@ func NewUnaryOrNumber(op UnaryOperator, node Node) Node {
 
 	return NewUnary(op, node)
 }
+
+// negateLiteral returns the numeric literal lit with its sign flipped: with
+// its leading minus sign removed, or with a minus sign prepended.
+func negateLiteral(lit string) string {
+	if neg, ok := strings.CutPrefix(lit, "-"); ok {
+		return neg
+	}
+	return "-" + lit
+}
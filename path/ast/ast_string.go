@@ -104,11 +104,12 @@ func _() {
 	_ = x[MethodInteger-9]
 	_ = x[MethodNumber-10]
 	_ = x[MethodString-11]
+	_ = x[MethodCustom-12]
 }
 
-const _MethodName_name = ".abs().size().type().floor().ceiling().double().keyvalue().bigint().boolean().integer().number().string()"
+const _MethodName_name = ".abs().size().type().floor().ceiling().double().keyvalue().bigint().boolean().integer().number().string()custom"
 
-var _MethodName_index = [...]uint8{0, 6, 13, 20, 28, 38, 47, 58, 67, 77, 87, 96, 105}
+var _MethodName_index = [...]uint8{0, 6, 13, 20, 28, 38, 47, 58, 67, 77, 87, 96, 105, 111}
 
 func (i MethodName) String() string {
 	if i < 0 || i >= MethodName(len(_MethodName_index)-1) {
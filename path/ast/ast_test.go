@@ -158,6 +158,7 @@ func TestMethodNode(t *testing.T) {
 			a.Equal(tc.meth, node.name)
 			a.Equal(tc.meth, node.Name())
 			a.Equal(tc.str, node.String())
+			a.Equal("", node.Custom())
 			a.Equal(lowestPriority, node.priority())
 
 			// Test next.
@@ -175,6 +176,24 @@ func TestMethodNode(t *testing.T) {
 	}
 }
 
+func TestCustomMethodNode(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	node := NewCustomMethod("upper")
+	a.Implements((*Node)(nil), node)
+	a.Equal(MethodCustom, node.Name())
+	a.Equal("custom", node.Name().String())
+	a.Equal("upper", node.Custom())
+	a.Equal(".upper()", node.String())
+	a.Equal(lowestPriority, node.priority())
+
+	node.setNext(NewMethod(MethodSize))
+	buf := new(strings.Builder)
+	node.writeTo(buf, false, false)
+	a.Equal(".upper().size()", buf.String())
+}
+
 func TestStringNodes(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
@@ -194,10 +213,13 @@ func TestStringNodes(t *testing.T) {
 		{"tab", "hi\tthere", "hi\tthere", `"hi\tthere"`},
 		{"ff", "hi\fthere", "hi\fthere", `"hi\fthere"`},
 		{"return", "hi\rthere", "hi\rthere", `"hi\rthere"`},
-		{"vertical_tab", "hi\vthere", "hi\vthere", `"hi\vthere"`},
+		{"vertical_tab", "hi\vthere", "hi\vthere", `"hi\u000bthere"`},
 		{"backspace", "hi\bthere", "hi\bthere", `"hi\bthere"`},
 		{"emoji", "🤘🏻🎉🐳", "🤘🏻🎉🐳", `"🤘🏻🎉🐳"`},
 		{"multibyte", "\U0001D11E", "𝄞", `"𝄞"`},
+		{"control", "hi\x01\x1fthere", "hi\x01\x1fthere", `"hi\u0001\u001fthere"`},
+		{"delete", "hi\x7fthere", "hi\x7fthere", "\"hi\x7fthere\""},
+		{"line_separator", "hi\u2028there", "hi\u2028there", "\"hi\u2028there\""},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -253,25 +275,31 @@ func TestNumericNode(t *testing.T) {
 		{"number", "42.3", 42.3, "42.3", ""},
 		{"zero_dot", "0.", 0.0, "0", ""},
 		{"dot_one", ".1", 0.1, "0.1", ""},
-		{"zero_dot_zero", "0.0", 0.0, "0", ""},
-		{"zero_dot_000", "0.000", 0.0, "0", ""},
-		{"expo", "0.0010e-1", 0.0001, "0.0001", ""},
-		{"pos_expo", "0.0010e+2", 0.1, "0.1", ""},
+		{"zero_dot_zero", "0.0", 0.0, "0.0", ""},
+		{"zero_dot_000", "0.000", 0.0, "0.000", ""},
+		{"neg_zero", "-0.0", 0.0, "0.0", ""},
+		{"negative", "-0.50", -0.5, "-0.50", ""},
+		{"positive", "+0.50", 0.5, "0.50", ""},
+		{"expo", "0.0010e-1", 0.0001, "0.00010", ""},
+		{"pos_expo", "0.0010e+2", 0.1, "0.10", ""},
+		{"scale_expo", "1.50e1", 15, "15.0", ""},
 		{"dot_001", ".001", 0.001, "0.001", ""},
 		{"dot_expo", "1.e1", 10, "10", ""},
 		{"one_expo_3", "1e3", 1000, "1000", ""},
 		{"1_dot_2e3", "1.2e3", 1200, "1200", ""},
+		{"upper_expo", "1.2E-3", 0.0012, "0.0012", ""},
+		{"underscores", "1_000.000_5", 1000.0005, "1000.0005", ""},
 		{
 			name: "max_float",
 			num:  fmt.Sprintf("%v", math.MaxFloat64),
 			val:  math.MaxFloat64,
-			str:  fmt.Sprintf("%v", math.MaxFloat64),
+			str:  "17976931348623157" + strings.Repeat("0", 292),
 		},
 		{
 			name: "min_float",
 			num:  fmt.Sprintf("%v", math.SmallestNonzeroFloat64),
 			val:  math.SmallestNonzeroFloat64,
-			str:  fmt.Sprintf("%v", math.SmallestNonzeroFloat64),
+			str:  "0." + strings.Repeat("0", 323) + "5",
 		},
 		{
 			name: "invalid_float",
@@ -933,8 +961,32 @@ func TestRegexNode(t *testing.T) {
 			name: "bad_flags",
 			node: NewString("foo"),
 			re:   `.`,
-			flag: "x",
-			err:  `XQuery "x" flag (expanded regular expressions) is not implemented`,
+			flag: "z",
+			err:  `Unrecognized flag character "z" in LIKE_REGEX predicate`,
+		},
+		{
+			name:    "expanded",
+			node:    NewString("foo"),
+			re:      "^a b # comment\n[ ]c",
+			flag:    "x",
+			flags:   regexFlags(regexWSpace),
+			str:     `"foo" like_regex "^a b # comment\n[ ]c" flag "x"`,
+			match:   []string{"ab c", "ab cd"},
+			noMatch: []string{"a b c", "abc"},
+		},
+		{
+			name:    "postgres_escapes",
+			node:    NewString("foo"),
+			re:      `a\b\Bz\Z`,
+			str:     `"foo" like_regex "a\\b\\Bz\\Z"`,
+			match:   []string{"a\b\\z"},
+			noMatch: []string{"a\\z", "a z"},
+		},
+		{
+			name: "bad_escape",
+			node: NewString("foo"),
+			re:   `\u12`,
+			err:  "error parsing regexp: invalid escape sequence: `\\u`",
 		},
 		{
 			name: "bad_pattern",
@@ -1006,6 +1058,27 @@ func TestRegexNode(t *testing.T) {
 	}
 }
 
+func TestRegexNodeCompile(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	node, err := NewRegex(NewConst(ConstCurrent), `^(a)\1`, "i")
+	r.NoError(err)
+	a.Equal(`@ like_regex "^(a)\\1" flag "i"`, node.String())
+	re, err := node.Compile()
+	r.EqualError(err, "back reference `\\1` not supported by like_regex")
+	a.Nil(re)
+	a.PanicsWithError(err.Error(), func() { node.Regexp() })
+
+	node, err = NewRegex(NewConst(ConstCurrent), `^\yhi\b`, "i")
+	r.NoError(err)
+	re, err = node.Compile()
+	r.NoError(err)
+	a.Equal(`(?i)^\bhi\x08`, re.String())
+	a.Equal(re, node.Regexp())
+}
+
 func TestNewUnaryOrNumber(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
@@ -1029,6 +1102,18 @@ func TestNewUnaryOrNumber(t *testing.T) {
 			node: NewInteger("42"),
 			exp:  NewInteger("-42"),
 		},
+		{
+			name: "minus_negative_integer",
+			op:   UnaryMinus,
+			node: NewInteger("-42"),
+			exp:  NewInteger("42"),
+		},
+		{
+			name: "minus_min_integer",
+			op:   UnaryMinus,
+			node: NewInteger("-0x8000000000000000"),
+			exp:  NewNumeric("9223372036854775808"),
+		},
 		{
 			name: "other_integer",
 			op:   UnaryExists,
@@ -1065,6 +1150,12 @@ func TestNewUnaryOrNumber(t *testing.T) {
 			node: NewNumeric("42.0"),
 			exp:  NewNumeric("-42.0"),
 		},
+		{
+			name: "minus_negative_numeric",
+			op:   UnaryMinus,
+			node: NewNumeric("-42.5"),
+			exp:  NewNumeric("42.5"),
+		},
 		{
 			name: "other_numeric",
 			op:   UnaryNot,
@@ -1147,6 +1238,15 @@ func TestWriteToNext(t *testing.T) {
 	}
 }
 
+// deepUnary returns a chain of depth nested unary minus nodes.
+func deepUnary(depth int) Node {
+	var node Node = NewConst(ConstRoot)
+	for range depth - 1 {
+		node = NewUnary(UnaryMinus, node)
+	}
+	return node
+}
+
 func TestAST(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
@@ -1160,6 +1260,7 @@ func TestAST(t *testing.T) {
 		{"string", NewString("foo"), ""},
 		{"accessor", LinkNodes([]Node{NewConst(ConstRoot)}), ""},
 		{"current", NewConst(ConstCurrent), "@ is not allowed in root expressions"},
+		{"too_deep", deepUnary(MaxDepth + 1), "maximum nesting depth exceeded"},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -1241,6 +1342,24 @@ func TestValidateNode(t *testing.T) {
 			node: NewBinary(BinaryAdd, NewConst(ConstRoot), NewConst(ConstCurrent)),
 			err:  "@ is not allowed in root expressions",
 		},
+		{
+			name: "decimal_int32",
+			node: NewBinary(BinaryDecimal, NewInteger("2147483647"), NewInteger("-2147483648")),
+		},
+		{
+			name: "decimal_variable",
+			node: NewBinary(BinaryDecimal, NewVariable("p"), NewVariable("s")),
+		},
+		{
+			name: "decimal_precision_range",
+			node: NewBinary(BinaryDecimal, NewInteger("2147483648"), nil),
+			err:  "precision of jsonpath item method .decimal() is out of integer range",
+		},
+		{
+			name: "decimal_scale_range",
+			node: NewBinary(BinaryDecimal, NewInteger("5"), NewInteger("-2147483649")),
+			err:  "scale of jsonpath item method .decimal() is out of integer range",
+		},
 		{
 			name:  "binary_current_okay_depth",
 			node:  NewBinary(BinaryAdd, NewConst(ConstRoot), NewConst(ConstCurrent)),
@@ -1248,7 +1367,7 @@ func TestValidateNode(t *testing.T) {
 		},
 		{
 			name: "unary",
-			node: NewUnary(UnaryNot, NewConst(ConstRoot)),
+			node: NewUnary(UnaryExists, NewConst(ConstRoot)),
 		},
 		{
 			name: "unary_fail",
@@ -1257,9 +1376,53 @@ func TestValidateNode(t *testing.T) {
 		},
 		{
 			name:  "unary_current_okay_depth",
-			node:  NewUnary(UnaryNot, NewConst(ConstCurrent)),
+			node:  NewUnary(UnaryExists, NewConst(ConstCurrent)),
 			depth: 1,
 		},
+		{
+			name: "not_predicate",
+			node: NewUnary(UnaryNot, NewBinary(BinaryLess, NewConst(ConstRoot), NewInteger("1"))),
+		},
+		{
+			name: "not_not_exists",
+			node: NewUnary(UnaryNot, NewUnary(UnaryNot, NewUnary(UnaryExists, NewConst(ConstRoot)))),
+		},
+		{
+			name:  "not_regex",
+			node:  NewUnary(UnaryNot, goodRegex),
+			depth: 1,
+		},
+		{
+			name: "not_path",
+			node: NewUnary(UnaryNot, NewConst(ConstRoot)),
+			err:  "! can only be applied to a predicate",
+		},
+		{
+			name: "not_math",
+			node: NewUnary(UnaryNot, NewBinary(BinaryAdd, NewConst(ConstRoot), NewInteger("1"))),
+			err:  "! can only be applied to a predicate",
+		},
+		{
+			name: "not_predicate_with_next",
+			node: NewUnary(UnaryNot, LinkNodes([]Node{
+				NewBinary(BinaryEqual, NewConst(ConstRoot), NewInteger("1")), NewKey("x"),
+			})),
+			err: "! can only be applied to a predicate",
+		},
+		{
+			name: "not_nil",
+			node: NewUnary(UnaryNot, nil),
+			err:  "! can only be applied to a predicate",
+		},
+		{
+			name: "is_unknown_predicate",
+			node: NewUnary(UnaryIsUnknown, NewBinary(BinaryOr, NewConst(ConstTrue), NewConst(ConstFalse))),
+		},
+		{
+			name: "is_unknown_path",
+			node: NewUnary(UnaryIsUnknown, NewConst(ConstTrue)),
+			err:  "is unknown can only be applied to a predicate",
+		},
 		{
 			name: "regex",
 			node: goodRegex,
@@ -1336,7 +1499,7 @@ func TestValidateNode(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := validateNode(tc.node, tc.depth, tc.inSub)
+			err := validateNode(tc.node, tc.depth, 0, tc.inSub)
 			if tc.err == "" {
 				r.NoError(err)
 			} else {
@@ -0,0 +1,396 @@
+package ast
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// BinaryVersion is the version of the binary format produced by
+// [AST.MarshalBinary]. [UnmarshalBinary] returns an error for data encoded
+// with any other version. It will be incremented whenever the format
+// changes.
+const BinaryVersion = byte(2)
+
+// Flags for the header byte following the version byte.
+const (
+	binaryLax  = byte(0x01)
+	binaryPred = byte(0x02)
+)
+
+// nodeTag identifies the type of a node in the binary format.
+type nodeTag byte
+
+const (
+	tagNil nodeTag = iota
+	tagConst
+	tagMethod
+	tagString
+	tagVariable
+	tagKey
+	tagNumeric
+	tagInteger
+	tagBinary
+	tagUnary
+	tagArrayIndex
+	tagAny
+	tagRegex
+)
+
+// MarshalBinary encodes a into a compact binary representation suitable for
+// caching and shipping to other processes. The encoding includes the
+// complete node tree, and begins with [BinaryVersion] to guard against
+// format changes. Use [UnmarshalBinary] to decode it.
+func (a *AST) MarshalBinary() ([]byte, error) {
+	flags := byte(0)
+	if a.lax {
+		flags |= binaryLax
+	}
+	if a.pred {
+		flags |= binaryPred
+	}
+
+	buf := []byte{BinaryVersion, flags}
+	return appendNode(buf, a.root)
+}
+
+// UnmarshalBinary decodes data produced by [AST.MarshalBinary] into a new
+// AST. The AST is validated just like those returned by [New]. Returns an
+// error if data was encoded with a different [BinaryVersion] or is
+// malformed.
+func UnmarshalBinary(data []byte) (*AST, error) {
+	//nolint:mnd
+	if len(data) < 2 {
+		//nolint:err113
+		return nil, errors.New("binary AST too short")
+	}
+	if data[0] != BinaryVersion {
+		//nolint:err113
+		return nil, fmt.Errorf(
+			"unsupported binary AST version %d; expected %d",
+			data[0], BinaryVersion,
+		)
+	}
+	flags := data[1]
+	if flags&^(binaryLax|binaryPred) != 0 {
+		//nolint:err113
+		return nil, fmt.Errorf("invalid binary AST flags %#02x", flags)
+	}
+
+	dec := &decoder{data: data[2:]}
+	root, err := dec.node(0)
+	if err != nil {
+		return nil, err
+	}
+	if len(dec.data) > 0 {
+		//nolint:err113
+		return nil, fmt.Errorf("%d unexpected trailing bytes in binary AST", len(dec.data))
+	}
+	if root == nil {
+		//nolint:err113
+		return nil, errors.New("binary AST has no root node")
+	}
+
+	return New(flags&binaryLax != 0, flags&binaryPred != 0, root)
+}
+
+// appendNode appends the binary encoding of node and its next nodes to buf.
+// Each node is encoded as a tag byte, followed by its operands, followed by
+// its next node (or tagNil).
+//
+//nolint:funlen
+func appendNode(buf []byte, node Node) ([]byte, error) {
+	var err error
+	switch node := node.(type) {
+	case nil:
+		return append(buf, byte(tagNil)), nil
+	case *ConstNode:
+		buf = append(buf, byte(tagConst))
+		buf = binary.AppendUvarint(buf, uint64(node.kind))
+	case *MethodNode:
+		buf = append(buf, byte(tagMethod))
+		buf = binary.AppendUvarint(buf, uint64(node.name))
+		if node.name == MethodCustom {
+			buf = appendString(buf, node.custom)
+		}
+	case *StringNode:
+		buf = appendString(append(buf, byte(tagString)), node.str)
+	case *VariableNode:
+		buf = appendString(append(buf, byte(tagVariable)), node.str)
+	case *KeyNode:
+		buf = appendString(append(buf, byte(tagKey)), node.str)
+	case *NumericNode:
+		// Encode the literal to preserve full precision.
+		buf = appendString(append(buf, byte(tagNumeric)), node.literal)
+	case *IntegerNode:
+		buf = appendString(append(buf, byte(tagInteger)), node.literal)
+	case *BinaryNode:
+		buf = append(buf, byte(tagBinary))
+		buf = binary.AppendUvarint(buf, uint64(node.op))
+		if buf, err = appendNode(buf, node.left); err != nil {
+			return nil, err
+		}
+		if buf, err = appendNode(buf, node.right); err != nil {
+			return nil, err
+		}
+	case *UnaryNode:
+		buf = append(buf, byte(tagUnary))
+		buf = binary.AppendUvarint(buf, uint64(node.op))
+		if buf, err = appendNode(buf, node.operand); err != nil {
+			return nil, err
+		}
+	case *ArrayIndexNode:
+		buf = append(buf, byte(tagArrayIndex))
+		buf = binary.AppendUvarint(buf, uint64(len(node.subscripts)))
+		for _, sub := range node.subscripts {
+			if buf, err = appendNode(buf, sub); err != nil {
+				return nil, err
+			}
+		}
+	case *AnyNode:
+		buf = append(buf, byte(tagAny))
+		buf = binary.AppendUvarint(buf, uint64(node.first))
+		buf = binary.AppendUvarint(buf, uint64(node.last))
+	case *RegexNode:
+		buf = appendString(append(buf, byte(tagRegex)), node.pattern)
+		buf = binary.AppendUvarint(buf, uint64(node.flags))
+		if buf, err = appendNode(buf, node.operand); err != nil {
+			return nil, err
+		}
+	default:
+		//nolint:err113
+		return nil, fmt.Errorf("cannot encode unknown node type %T", node)
+	}
+
+	return appendNode(buf, node.Next())
+}
+
+// appendString appends the length of str followed by str to buf.
+func appendString(buf []byte, str string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(str)))
+	return append(buf, str...)
+}
+
+// decoder decodes the binary representation of nodes.
+type decoder struct {
+	data []byte
+}
+
+// errTruncated indicates that the binary data ended unexpectedly.
+//
+//nolint:err113
+var errTruncated = errors.New("unexpected end of binary AST")
+
+// byte reads a single byte.
+func (d *decoder) byte() (byte, error) {
+	if len(d.data) == 0 {
+		return 0, errTruncated
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b, nil
+}
+
+// uvarint reads an unsigned integer no greater than maxVal.
+func (d *decoder) uvarint(maxVal uint64) (uint64, error) {
+	val, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	d.data = d.data[n:]
+	if val > maxVal {
+		//nolint:err113
+		return 0, fmt.Errorf("binary AST value %d out of range", val)
+	}
+	return val, nil
+}
+
+// string reads a length-prefixed string.
+func (d *decoder) string() (string, error) {
+	size, err := d.uvarint(math.MaxInt)
+	if err != nil {
+		return "", err
+	}
+	if size > uint64(len(d.data)) {
+		return "", errTruncated
+	}
+	str := string(d.data[:size])
+	d.data = d.data[size:]
+	return str, nil
+}
+
+// node decodes a node, its operands, and its next nodes. level tracks the
+// nesting level to prevent stack overflows on malicious input.
+//
+//nolint:funlen,gocognit,gocyclo
+func (d *decoder) node(level int) (Node, error) {
+	if level >= MaxDepth {
+		//nolint:err113
+		return nil, errors.New("maximum nesting depth exceeded")
+	}
+	level++
+
+	tag, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	var node Node
+	switch nodeTag(tag) {
+	case tagNil:
+		return nil, nil //nolint:nilnil
+	case tagConst:
+		kind, err := d.uvarint(uint64(ConstNull))
+		if err != nil {
+			return nil, err
+		}
+		node = NewConst(Constant(kind))
+	case tagMethod:
+		name, err := d.uvarint(uint64(MethodCustom))
+		if err != nil {
+			return nil, err
+		}
+		if MethodName(name) != MethodCustom {
+			node = NewMethod(MethodName(name))
+			break
+		}
+		custom, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		if custom == "" {
+			//nolint:err113
+			return nil, errors.New("empty custom method name in binary AST")
+		}
+		node = NewCustomMethod(custom)
+	case tagString, tagVariable, tagKey:
+		str, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		switch nodeTag(tag) {
+		case tagString:
+			node = NewString(str)
+		case tagVariable:
+			node = NewVariable(str)
+		default:
+			node = NewKey(str)
+		}
+	case tagNumeric:
+		lit, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		if !isDecimal(lit) {
+			//nolint:err113
+			return nil, fmt.Errorf("invalid numeric literal %q in binary AST", lit)
+		}
+		node = NewNumeric(lit)
+	case tagInteger:
+		lit, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := strconv.ParseInt(lit, 0, 64); err != nil {
+			//nolint:err113
+			return nil, fmt.Errorf("invalid integer literal %q in binary AST", lit)
+		}
+		node = NewInteger(lit)
+	case tagBinary:
+		op, err := d.uvarint(uint64(BinaryDecimal))
+		if err != nil {
+			return nil, err
+		}
+		left, err := d.node(level)
+		if err != nil {
+			return nil, err
+		}
+		right, err := d.node(level)
+		if err != nil {
+			return nil, err
+		}
+		node = NewBinary(BinaryOperator(op), left, right)
+	case tagUnary:
+		op, err := d.uvarint(uint64(UnaryTimestampTZ))
+		if err != nil {
+			return nil, err
+		}
+		operand, err := d.node(level)
+		if err != nil {
+			return nil, err
+		}
+		node = NewUnary(UnaryOperator(op), operand)
+	case tagArrayIndex:
+		size, err := d.uvarint(math.MaxInt)
+		if err != nil {
+			return nil, err
+		}
+		if size > uint64(len(d.data)) {
+			// Each subscript requires at least one byte.
+			return nil, errTruncated
+		}
+		subs := make([]Node, size)
+		for i := range subs {
+			if subs[i], err = d.node(level); err != nil {
+				return nil, err
+			}
+		}
+		node = NewArrayIndex(subs)
+	case tagAny:
+		first, err := d.uvarint(math.MaxUint32)
+		if err != nil {
+			return nil, err
+		}
+		last, err := d.uvarint(math.MaxUint32)
+		if err != nil {
+			return nil, err
+		}
+		node = &AnyNode{first: uint32(first), last: uint32(last)}
+	case tagRegex:
+		pattern, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		flags, err := d.uvarint(math.MaxUint16)
+		if err != nil {
+			return nil, err
+		}
+		operand, err := d.node(level)
+		if err != nil {
+			return nil, err
+		}
+		re, err := NewRegex(operand, pattern, regexFlags(flags).letters())
+		if err != nil {
+			return nil, err
+		}
+		node = re
+	default:
+		//nolint:err113
+		return nil, fmt.Errorf("unknown node tag %d in binary AST", tag)
+	}
+
+	next, err := d.node(level)
+	if err != nil {
+		return nil, err
+	}
+	if next != nil {
+		node.setNext(next)
+	}
+	return node, nil
+}
+
+// isDecimal returns true if lit is a decimal numeric literal acceptable to
+// [NewNumeric], excluding values such as "Inf" and hexadecimal floats
+// accepted by [strconv.ParseFloat].
+func isDecimal(lit string) bool {
+	if strings.IndexFunc(lit, func(r rune) bool {
+		return (r < '0' || r > '9') && !strings.ContainsRune(".eE+-", r)
+	}) >= 0 {
+		return false
+	}
+	_, err := strconv.ParseFloat(lit, 64)
+	return err == nil
+}
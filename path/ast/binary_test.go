@@ -0,0 +1,275 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	mustRegex := func(expr Node, pattern, flags string) *RegexNode {
+		re, err := NewRegex(expr, pattern, flags)
+		if err != nil {
+			panic(err)
+		}
+		return re
+	}
+
+	for _, tc := range []struct {
+		name string
+		lax  bool
+		pred bool
+		node Node
+		str  string
+	}{
+		{
+			name: "root",
+			lax:  true,
+			node: NewConst(ConstRoot),
+			str:  "$",
+		},
+		{
+			name: "strict_keys",
+			node: LinkNodes([]Node{NewConst(ConstRoot), NewKey("a"), NewKey("b c")}),
+			str:  `strict $."a"."b c"`,
+		},
+		{
+			name: "predicate",
+			lax:  true,
+			pred: true,
+			node: NewBinary(
+				BinaryEqual,
+				LinkNodes([]Node{NewConst(ConstRoot), NewKey("x")}),
+				NewVariable("v"),
+			),
+			str: `($."x" == $"v")`,
+		},
+		{
+			name: "numbers",
+			lax:  true,
+			node: NewBinary(
+				BinaryAdd,
+				NewNumeric("12345678901234567890.123456789012345678901"),
+				NewInteger("0x1F"),
+			),
+			str: "(12345678901234567890.123456789012345678901 + 31)",
+		},
+		{
+			name: "numeric_scale",
+			lax:  true,
+			node: NewNumeric("1.50e1"),
+			str:  "15.0",
+		},
+		{
+			name: "filter",
+			lax:  true,
+			node: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				NewConst(ConstAnyArray),
+				NewUnary(UnaryFilter, NewUnary(UnaryNot, NewBinary(
+					BinaryStartsWith,
+					LinkNodes([]Node{NewConst(ConstCurrent), NewKey("s")}),
+					NewString("x\ny"),
+				))),
+			}),
+			str: `$[*]?(!(@."s" starts with "x\ny"))`,
+		},
+		{
+			name: "regex",
+			lax:  true,
+			node: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				NewUnary(UnaryFilter, mustRegex(NewConst(ConstCurrent), `^a.b$`, "ism")),
+			}),
+			str: `$?(@ like_regex "^a.b$" flag "ism")`,
+		},
+		{
+			name: "regex_quote",
+			lax:  true,
+			node: mustRegex(NewConst(ConstRoot), `a+`, "q"),
+			str:  `($ like_regex "a+" flag "q")`,
+		},
+		{
+			name: "array_index",
+			lax:  true,
+			node: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				NewArrayIndex([]Node{
+					NewBinary(BinarySubscript, NewInteger("1"), nil),
+					NewBinary(BinarySubscript, NewInteger("2"), NewConst(ConstLast)),
+				}),
+			}),
+			str: "$[1,2 to last]",
+		},
+		{
+			name: "any",
+			lax:  true,
+			node: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				NewAny(0, -1),
+				NewAny(2, 5),
+				NewAny(-1, -1),
+			}),
+			str: "$.**.**{2 to 5}.**{last}",
+		},
+		{
+			name: "methods",
+			lax:  true,
+			node: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				NewMethod(MethodSize),
+				NewMethod(MethodString),
+				NewBinary(BinaryDecimal, nil, nil),
+				NewBinary(BinaryDecimal, NewInteger("4"), NewInteger("2")),
+				NewUnary(UnaryDateTime, NewString("YYYY")),
+				NewUnary(UnaryTimestampTZ, nil),
+			}),
+			str: `$.size().string().decimal().decimal(4,2).datetime("YYYY").timestamp_tz()`,
+		},
+		{
+			name: "custom_method",
+			lax:  true,
+			node: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				NewKey("a"),
+				NewCustomMethod("upper"),
+				NewMethod(MethodSize),
+			}),
+			str: `$."a".upper().size()`,
+		},
+		{
+			name: "unary_math",
+			lax:  true,
+			node: NewUnary(UnaryMinus, LinkNodes([]Node{
+				NewConst(ConstRoot), NewKey("a"), NewMethod(MethodAbs),
+			})),
+			str: `(-$."a".abs())`,
+		},
+		{
+			name: "exists_null",
+			lax:  true,
+			node: NewBinary(
+				BinaryOr,
+				NewUnary(UnaryExists, NewConst(ConstRoot)),
+				NewUnary(UnaryIsUnknown, NewBinary(BinaryEqual, NewConst(ConstNull), NewConst(ConstTrue))),
+			),
+			str: "(exists ($) || (null == true) is unknown)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			tree, err := New(tc.lax, tc.pred, tc.node)
+			r.NoError(err)
+			a.Equal(tc.str, tree.String())
+
+			data, err := tree.MarshalBinary()
+			r.NoError(err)
+			a.Equal(BinaryVersion, data[0])
+
+			got, err := UnmarshalBinary(data)
+			r.NoError(err)
+			a.Equal(tree, got)
+			a.Equal(tc.str, got.String())
+			a.Equal(tc.lax, got.IsLax())
+			a.Equal(tc.pred, got.IsPredicate())
+		})
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	t.Parallel()
+
+	valid, err := New(true, false, LinkNodes([]Node{NewConst(ConstRoot), NewKey("a")}))
+	require.NoError(t, err)
+	data, err := valid.MarshalBinary()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"empty", nil, "binary AST too short"},
+		{"no_flags", []byte{BinaryVersion}, "binary AST too short"},
+		{"version", []byte{BinaryVersion + 1, 0, 0}, "unsupported binary AST version 3; expected 2"},
+		{"flags", []byte{BinaryVersion, 0x04, 0}, "invalid binary AST flags 0x04"},
+		{"no_root", []byte{BinaryVersion, 0, byte(tagNil)}, "binary AST has no root node"},
+		{"truncated", data[:len(data)-2], "unexpected end of binary AST"},
+		{"trailing", append(append([]byte{}, data...), 0), "1 unexpected trailing bytes in binary AST"},
+		{"unknown_tag", []byte{BinaryVersion, 0, 0xff}, "unknown node tag 255 in binary AST"},
+		{"bad_const", []byte{BinaryVersion, 0, byte(tagConst), 42, 0}, "binary AST value 42 out of range"},
+		{"bad_method", []byte{BinaryVersion, 0, byte(tagMethod), 99, 0}, "binary AST value 99 out of range"},
+		{
+			"custom_method_no_name",
+			[]byte{BinaryVersion, 0, byte(tagMethod), byte(MethodCustom)},
+			"unexpected end of binary AST",
+		},
+		{
+			"custom_method_empty_name",
+			[]byte{BinaryVersion, 0, byte(tagMethod), byte(MethodCustom), 0, 0},
+			"empty custom method name in binary AST",
+		},
+		{"bad_string_len", []byte{BinaryVersion, 0, byte(tagKey), 5, 'a'}, "unexpected end of binary AST"},
+		{
+			"bad_numeric",
+			append([]byte{BinaryVersion, 0, byte(tagNumeric), 3}, "Inf"...),
+			`invalid numeric literal "Inf" in binary AST`,
+		},
+		{
+			"bad_integer",
+			append([]byte{BinaryVersion, 0, byte(tagInteger), 3}, "1.5"...),
+			`invalid integer literal "1.5" in binary AST`,
+		},
+		{
+			"bad_regex_flags",
+			[]byte{BinaryVersion, 0, byte(tagRegex), 1, 'a', 0x40, byte(tagConst), 0, 0, 0},
+			`Unrecognized flag character "?" in LIKE_REGEX predicate`,
+		},
+		{
+			"invalid_ast",
+			[]byte{BinaryVersion, 0, byte(tagConst), byte(ConstCurrent), 0},
+			"@ is not allowed in root expressions",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+
+			got, err := UnmarshalBinary(tc.data)
+			a.Nil(got)
+			a.EqualError(err, tc.err)
+		})
+	}
+
+	t.Run("too_deep", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+
+		data := []byte{BinaryVersion, 0}
+		for range MaxDepth {
+			data = append(data, byte(tagUnary), byte(UnaryNot))
+		}
+		data = append(data, strings.Repeat(string(byte(tagNil)), MaxDepth+1)...)
+		got, err := UnmarshalBinary(data)
+		a.Nil(got)
+		a.EqualError(err, "maximum nesting depth exceeded")
+	})
+
+	t.Run("any_bounds", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+
+		data := []byte{BinaryVersion, 0, byte(tagAny), 0}
+		data = append(data, 0x80, 0x80, 0x80, 0x80, 0x10, 0) // MaxUint32 + 1
+		got, err := UnmarshalBinary(data)
+		a.Nil(got)
+		a.EqualError(err, "binary AST value 4294967296 out of range")
+	})
+}
@@ -0,0 +1,374 @@
+package ast
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Optimize returns a copy of a with constant expressions evaluated in
+// advance, so that executing the path does not evaluate them again for
+// every item. It:
+//
+//   - Folds arithmetic on numeric literals, so that 2.5 - 1 becomes 1.5 and
+//     @.a == (1 + 1) becomes @.a == 2
+//   - Folds unary + and - applied to numeric literals
+//   - Replaces a && b with b and a || b with b when a is a comparison of
+//     literals that is always true or always false, respectively, and
+//     likewise when b is the constant comparison
+//   - Replaces !(!(a)) with a
+//
+// Folded arithmetic follows the semantics of the executor, including the
+// conversion of integer results out of int64 range and quotients with a
+// remainder to floating point numbers, so that the optimized AST returns
+// the same results as a. Expressions that raise an error when executed,
+// such as division by zero, remain in place to raise it at run time.
+// Arguments to methods such as .decimal() and .datetime() remain as
+// written.
+//
+// Optimize does not modify a. The string representation of the optimized
+// AST parses to an equivalent AST.
+func Optimize(a *AST) *AST {
+	return &AST{root: optimizeNode(a.root, true), lax: a.lax, pred: a.pred}
+}
+
+// optimizeNode returns an optimized copy of node and the nodes that follow
+// it. If fold is false it copies node without optimizing it.
+func optimizeNode(node Node, fold bool) Node {
+	if node == nil {
+		return nil
+	}
+
+	var res Node
+	switch node := node.(type) {
+	case *ConstNode:
+		res = NewConst(node.kind)
+	case *MethodNode:
+		res = &MethodNode{name: node.name, custom: node.custom}
+	case *StringNode:
+		res = NewString(node.str)
+	case *VariableNode:
+		res = NewVariable(node.str)
+	case *KeyNode:
+		res = NewKey(node.str)
+	case *NumericNode:
+		res = &NumericNode{&numberNode{literal: node.literal, parsed: node.parsed}}
+	case *IntegerNode:
+		res = &IntegerNode{&numberNode{literal: node.literal, parsed: node.parsed}}
+	case *BinaryNode:
+		res = optimizeBinary(node, fold)
+	case *UnaryNode:
+		res = optimizeUnary(node, fold)
+	case *ArrayIndexNode:
+		subs := make([]Node, len(node.subscripts))
+		for i, sub := range node.subscripts {
+			subs[i] = optimizeNode(sub, fold)
+		}
+		res = NewArrayIndex(subs)
+	case *AnyNode:
+		res = &AnyNode{first: node.first, last: node.last}
+	case *RegexNode:
+		regex := *node
+		regex.operand = optimizeNode(node.operand, fold)
+		regex.next = nil
+		res = &regex
+	default:
+		// Unknown node type; leave it as is.
+		return node
+	}
+
+	// Simplifications replace node only when it has no next node, and may
+	// return a node with its own next node, so set it only if there is one.
+	if next := node.Next(); next != nil {
+		res.setNext(optimizeNode(next, fold))
+	}
+	return res
+}
+
+// optimizeBinary returns an optimized copy of node, without its next node.
+func optimizeBinary(node *BinaryNode, fold bool) Node {
+	if node.op == BinaryDecimal {
+		// Leave the precision and scale arguments alone.
+		fold = false
+	}
+
+	left := optimizeNode(node.left, fold)
+	right := optimizeNode(node.right, fold)
+	if !fold {
+		return NewBinary(node.op, left, right)
+	}
+
+	switch node.op {
+	case BinaryAdd, BinarySub, BinaryMul, BinaryDiv, BinaryMod:
+		if res := foldMath(node.op, left, right); res != nil {
+			return res
+		}
+	case BinaryAnd, BinaryOr:
+		// true && b is b, and false || b is b.
+		if node.next == nil {
+			identity := node.op == BinaryAnd
+			if val, ok := constPredicate(left); ok && val == identity {
+				return right
+			}
+			if val, ok := constPredicate(right); ok && val == identity {
+				return left
+			}
+		}
+	default:
+		// Nothing to fold.
+	}
+
+	return NewBinary(node.op, left, right)
+}
+
+// optimizeUnary returns an optimized copy of node, without its next node.
+func optimizeUnary(node *UnaryNode, fold bool) Node {
+	switch node.op {
+	case UnaryDateTime, UnaryDate, UnaryTime, UnaryTimeTZ, UnaryTimestamp, UnaryTimestampTZ:
+		// Leave the template and precision arguments alone.
+		fold = false
+	default:
+		// Fold the operand.
+	}
+
+	operand := optimizeNode(node.operand, fold)
+	if !fold || node.next != nil {
+		return NewUnary(node.op, operand)
+	}
+
+	switch node.op {
+	case UnaryPlus, UnaryMinus:
+		switch num := operand.(type) {
+		case *IntegerNode:
+			// Leave the negation of math.MinInt64, which no IntegerNode can
+			// represent, to the executor.
+			if num.next == nil && (node.op == UnaryPlus || num.Int() != math.MinInt64) {
+				return NewUnaryOrNumber(node.op, num)
+			}
+		case *NumericNode:
+			if num.next == nil {
+				return NewUnaryOrNumber(node.op, num)
+			}
+		}
+	case UnaryNot:
+		// !(!(a)) is a.
+		if not, ok := operand.(*UnaryNode); ok && not.op == UnaryNot && not.next == nil {
+			return not.operand
+		}
+	default:
+		// Nothing to fold.
+	}
+
+	return NewUnary(node.op, operand)
+}
+
+// foldMath applies the arithmetic operator op to left and right and returns
+// the result as an IntegerNode or NumericNode. Returns nil if either is not
+// a numeric literal without a next node, or if the operation raises an
+// error when executed.
+func foldMath(op BinaryOperator, left, right Node) Node {
+	if left == nil || right == nil || left.Next() != nil || right.Next() != nil {
+		return nil
+	}
+
+	switch left := left.(type) {
+	case *IntegerNode:
+		switch right := right.(type) {
+		case *IntegerNode:
+			return foldIntegerMath(op, left.Int(), right.Int())
+		case *NumericNode:
+			return foldFloatMath(op, float64(left.Int()), right.Float())
+		}
+	case *NumericNode:
+		switch right := right.(type) {
+		case *IntegerNode:
+			return foldFloatMath(op, left.Float(), float64(right.Int()))
+		case *NumericNode:
+			return foldFloatMath(op, left.Float(), right.Float())
+		}
+	}
+	return nil
+}
+
+// foldIntegerMath applies op to lhs and rhs. Like the executor, it returns
+// quotients with a remainder as the float64 nearest the exact result.
+// Returns nil for division by zero, which raises an error when executed, and
+// for results out of int64 range, which the executor returns as exact
+// json.Number values that no literal node represents.
+func foldIntegerMath(op BinaryOperator, lhs, rhs int64) Node {
+	res, right := big.NewInt(lhs), big.NewInt(rhs)
+	switch op {
+	case BinaryAdd:
+		res.Add(res, right)
+	case BinarySub:
+		res.Sub(res, right)
+	case BinaryMul:
+		res.Mul(res, right)
+	case BinaryDiv:
+		switch {
+		case rhs == 0:
+			return nil
+		case lhs%rhs != 0:
+			return foldDecimalMath(op, new(big.Rat).SetInt(res), new(big.Rat).SetInt(right))
+		}
+		res.Quo(res, right)
+	case BinaryMod:
+		if rhs == 0 {
+			return nil
+		}
+		// big.Int.Rem truncates like Go's %, so that the result has the
+		// sign of lhs.
+		res.Rem(res, right)
+	default:
+		return nil
+	}
+
+	if !res.IsInt64() {
+		return nil
+	}
+	return NewInteger(res.String())
+}
+
+// foldFloatMath applies op to lhs and rhs. Like the executor, it computes
+// with them as the decimal numbers they represent. Returns nil for division
+// by zero and for results too large for a float64, both of which raise
+// errors when executed.
+func foldFloatMath(op BinaryOperator, lhs, rhs float64) Node {
+	if math.IsInf(lhs, 0) || math.IsNaN(lhs) || math.IsInf(rhs, 0) || math.IsNaN(rhs) {
+		return nil
+	}
+	return foldDecimalMath(op, floatRat(lhs), floatRat(rhs))
+}
+
+// foldDecimalMath applies op to lhs and rhs and returns the float64 nearest
+// the exact result. Returns nil for division by zero and for a result too
+// large for a float64.
+func foldDecimalMath(op BinaryOperator, lhs, rhs *big.Rat) Node {
+	res := new(big.Rat)
+	switch op {
+	case BinaryAdd:
+		res.Add(lhs, rhs)
+	case BinarySub:
+		res.Sub(lhs, rhs)
+	case BinaryMul:
+		res.Mul(lhs, rhs)
+	case BinaryDiv, BinaryMod:
+		if rhs.Sign() == 0 {
+			return nil
+		}
+		res.Quo(lhs, rhs)
+		if op == BinaryMod {
+			// lhs - rhs * trunc(lhs / rhs); big.Int.Quo truncates toward zero.
+			trunc := new(big.Int).Quo(res.Num(), res.Denom())
+			res.Sub(lhs, res.Mul(rhs, res.SetInt(trunc)))
+		}
+	default:
+		return nil
+	}
+
+	f, _ := res.Float64()
+	if math.IsInf(f, 0) {
+		return nil
+	}
+	return newFloatNode(f)
+}
+
+// floatRat returns the decimal number that f represents, that is, the
+// shortest decimal that parses to f, as a big.Rat. f must be finite.
+func floatRat(f float64) *big.Rat {
+	rat, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return rat
+}
+
+// newFloatNode returns a NumericNode for f, which must be finite. It always
+// includes a decimal point, so that its string representation parses to a
+// NumericNode, and represents negative zero as zero.
+func newFloatNode(f float64) *NumericNode {
+	if f == 0 {
+		// Replace negative zero.
+		f = 0
+	}
+	num := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.ContainsRune(num, '.') {
+		num += ".0"
+	}
+	return NewNumeric(num)
+}
+
+// constPredicate evaluates node if it's a comparison of two numeric or two
+// string literals, which always has the same result. Returns the result and
+// true if so, and false if node is any other expression. Literal true and
+// false are not predicates in SQL/JSON path, so such comparisons are the
+// only constant predicates.
+func constPredicate(node Node) (bool, bool) {
+	bin, ok := node.(*BinaryNode)
+	if !ok || bin.next != nil || bin.left == nil || bin.right == nil ||
+		bin.left.Next() != nil || bin.right.Next() != nil {
+		return false, false
+	}
+
+	var cmp int
+	switch left := bin.left.(type) {
+	case *IntegerNode, *NumericNode:
+		rhs, ok := numberRat(bin.right, true)
+		if !ok {
+			return false, false
+		}
+		// Compare the decimal values of the literals exactly. The executor
+		// compares the float64 values of non-integer literals, so leave
+		// comparisons for which the two disagree, such as
+		// 0.1 == 0.10000000000000001, for it to evaluate.
+		lhs, _ := numberRat(left, true)
+		cmp = lhs.Cmp(rhs)
+		lhsFloat, _ := numberRat(left, false)
+		rhsFloat, _ := numberRat(bin.right, false)
+		if lhsFloat.Cmp(rhsFloat) != cmp {
+			return false, false
+		}
+	case *StringNode:
+		right, ok := bin.right.(*StringNode)
+		if !ok {
+			return false, false
+		}
+		cmp = strings.Compare(left.str, right.str)
+	default:
+		return false, false
+	}
+
+	switch bin.op {
+	case BinaryEqual:
+		return cmp == 0, true
+	case BinaryNotEqual:
+		return cmp != 0, true
+	case BinaryLess:
+		return cmp < 0, true
+	case BinaryGreater:
+		return cmp > 0, true
+	case BinaryLessOrEqual:
+		return cmp <= 0, true
+	case BinaryGreaterOrEqual:
+		return cmp >= 0, true
+	default:
+		return false, false
+	}
+}
+
+// numberRat returns the value of node as a big.Rat and true if node is an
+// IntegerNode or NumericNode, and false otherwise. If exact is true, the
+// value of a NumericNode is the exact value of its decimal text; otherwise
+// it's the float64 nearest that value, as the executor evaluates it.
+func numberRat(node Node, exact bool) (*big.Rat, bool) {
+	switch node := node.(type) {
+	case *IntegerNode:
+		return new(big.Rat).SetInt64(node.Int()), true
+	case *NumericNode:
+		if exact {
+			rat, _ := new(big.Rat).SetString(node.String())
+			return rat, true
+		}
+		return new(big.Rat).SetFloat64(node.Float()), true
+	default:
+		return nil, false
+	}
+}
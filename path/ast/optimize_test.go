@@ -0,0 +1,381 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimize(t *testing.T) {
+	t.Parallel()
+
+	// Helpers to construct nodes.
+	integer := func(num string) Node { return NewInteger(num) }
+	numeric := func(num string) Node { return NewNumeric(num) }
+	binary := func(op BinaryOperator, left, right Node) Node { return NewBinary(op, left, right) }
+	unary := func(op UnaryOperator, operand Node) Node { return NewUnary(op, operand) }
+	key := func(name string) Node { return LinkNodes([]Node{NewConst(ConstCurrent), NewKey(name)}) }
+	filter := func(pred Node) Node { return LinkNodes([]Node{NewConst(ConstRoot), unary(UnaryFilter, pred)}) }
+	regex := func(operand Node) Node {
+		node, err := NewRegex(operand, "^a", "i")
+		require.NoError(t, err)
+		return node
+	}
+	gt := func(name string) Node { return binary(BinaryGreater, key(name), integer("1")) }
+
+	for _, tc := range []struct {
+		name string
+		node Node
+		exp  Node
+		str  string
+	}{
+		{
+			name: "root",
+			node: NewConst(ConstRoot),
+			exp:  NewConst(ConstRoot),
+			str:  "$",
+		},
+		{
+			name: "accessors",
+			node: LinkNodes([]Node{NewConst(ConstRoot), NewKey("a"), NewAny(1, 2), NewMethod(MethodSize)}),
+			exp:  LinkNodes([]Node{NewConst(ConstRoot), NewKey("a"), NewAny(1, 2), NewMethod(MethodSize)}),
+			str:  `$."a".**{1 to 2}.size()`,
+		},
+		{
+			name: "add_integers",
+			node: binary(BinaryAdd, integer("1"), integer("2")),
+			exp:  integer("3"),
+			str:  "3",
+		},
+		{
+			name: "sub_integers",
+			node: binary(BinarySub, integer("1"), integer("0x10")),
+			exp:  integer("-15"),
+			str:  "-15",
+		},
+		{
+			name: "mul_integers",
+			node: binary(BinaryMul, integer("6"), integer("-7")),
+			exp:  integer("-42"),
+			str:  "-42",
+		},
+		{
+			name: "add_overflow",
+			node: binary(BinaryAdd, integer("9223372036854775807"), integer("1")),
+			exp:  binary(BinaryAdd, integer("9223372036854775807"), integer("1")),
+			str:  "(9223372036854775807 + 1)",
+		},
+		{
+			name: "sub_overflow",
+			node: binary(BinarySub, integer("-0x8000000000000000"), integer("1")),
+			exp:  binary(BinarySub, integer("-0x8000000000000000"), integer("1")),
+			str:  "(-9223372036854775808 - 1)",
+		},
+		{
+			name: "mul_overflow",
+			node: binary(BinaryMul, integer("-9223372036854775807"), integer("3")),
+			exp:  binary(BinaryMul, integer("-9223372036854775807"), integer("3")),
+			str:  "(-9223372036854775807 * 3)",
+		},
+		{
+			name: "mul_min_int_minus_one",
+			node: binary(BinaryMul, integer("-0x8000000000000000"), integer("-1")),
+			exp:  binary(BinaryMul, integer("-0x8000000000000000"), integer("-1")),
+			str:  "(-9223372036854775808 * -1)",
+		},
+		{
+			name: "div_integers",
+			node: binary(BinaryDiv, integer("6"), integer("3")),
+			exp:  integer("2"),
+			str:  "2",
+		},
+		{
+			name: "div_integers_remainder",
+			node: binary(BinaryDiv, integer("1"), integer("3")),
+			exp:  numeric("0.3333333333333333"),
+			str:  "0.3333333333333333",
+		},
+		{
+			name: "div_integers_zero",
+			node: binary(BinaryDiv, integer("1"), integer("0")),
+			exp:  binary(BinaryDiv, integer("1"), integer("0")),
+			str:  "(1 / 0)",
+		},
+		{
+			name: "div_min_int_minus_one",
+			node: binary(BinaryDiv, integer("-0x8000000000000000"), integer("-1")),
+			exp:  binary(BinaryDiv, integer("-0x8000000000000000"), integer("-1")),
+			str:  "(-9223372036854775808 / -1)",
+		},
+		{
+			name: "mod_integers",
+			node: binary(BinaryMod, integer("-7"), integer("3")),
+			exp:  integer("-1"),
+			str:  "-1",
+		},
+		{
+			name: "mod_integers_zero",
+			node: binary(BinaryMod, integer("7"), integer("0")),
+			exp:  binary(BinaryMod, integer("7"), integer("0")),
+			str:  "(7 % 0)",
+		},
+		{
+			name: "sub_numeric_integer",
+			node: binary(BinarySub, numeric("2.5"), integer("1")),
+			exp:  numeric("1.5"),
+			str:  "1.5",
+		},
+		{
+			name: "add_numerics",
+			node: binary(BinaryAdd, numeric("0.1"), numeric("0.2")),
+			exp:  numeric("0.3"),
+			str:  "0.3",
+		},
+		{
+			name: "mul_integer_numeric",
+			node: binary(BinaryMul, integer("2"), numeric("1.5")),
+			exp:  numeric("3.0"),
+			str:  "3.0",
+		},
+		{
+			name: "mul_negative_zero",
+			node: binary(BinaryMul, numeric("-0.5"), integer("0")),
+			exp:  numeric("0.0"),
+			str:  "0.0",
+		},
+		{
+			name: "mod_numerics",
+			node: binary(BinaryMod, numeric("2.5"), numeric("0.3")),
+			exp:  numeric("0.1"),
+			str:  "0.1",
+		},
+		{
+			name: "div_numeric_zero",
+			node: binary(BinaryDiv, numeric("2.5"), numeric("0.0")),
+			exp:  binary(BinaryDiv, numeric("2.5"), numeric("0.0")),
+			str:  "(2.5 / 0.0)",
+		},
+		{
+			name: "mul_numeric_overflow",
+			node: binary(BinaryMul, numeric("1e308"), integer("10")),
+			exp:  binary(BinaryMul, numeric("1e308"), integer("10")),
+		},
+		{
+			name: "nested",
+			node: binary(BinaryMul, binary(BinaryAdd, integer("1"), integer("2")), integer("3")),
+			exp:  integer("9"),
+			str:  "9",
+		},
+		{
+			name: "partial",
+			node: binary(BinaryAdd, binary(BinaryMul, integer("2"), integer("3")), NewVariable("x")),
+			exp:  binary(BinaryAdd, integer("6"), NewVariable("x")),
+			str:  `(6 + $"x")`,
+		},
+		{
+			name: "with_next",
+			node: LinkNodes([]Node{binary(BinaryAdd, integer("1"), integer("1")), NewMethod(MethodType)}),
+			exp:  LinkNodes([]Node{integer("2"), NewMethod(MethodType)}),
+			str:  "(2).type()",
+		},
+		{
+			name: "operand_with_next",
+			node: binary(BinaryAdd, LinkNodes([]Node{integer("1"), NewMethod(MethodAbs)}), integer("1")),
+			exp:  binary(BinaryAdd, LinkNodes([]Node{integer("1"), NewMethod(MethodAbs)}), integer("1")),
+			str:  "((1).abs() + 1)",
+		},
+		{
+			name: "minus_folded",
+			node: unary(UnaryMinus, binary(BinaryAdd, integer("1"), integer("1"))),
+			exp:  integer("-2"),
+			str:  "-2",
+		},
+		{
+			name: "plus_folded",
+			node: unary(UnaryPlus, binary(BinaryAdd, numeric("0.5"), integer("1"))),
+			exp:  numeric("1.5"),
+			str:  "1.5",
+		},
+		{
+			name: "minus_min_int",
+			node: unary(UnaryMinus, binary(BinarySub, integer("-0x7fffffffffffffff"), integer("1"))),
+			exp:  unary(UnaryMinus, integer("-9223372036854775808")),
+		},
+		{
+			name: "filter",
+			node: filter(binary(BinaryEqual, key("a"), binary(BinaryAdd, integer("1"), integer("1")))),
+			exp:  filter(binary(BinaryEqual, key("a"), integer("2"))),
+			str:  `$?(@."a" == 2)`,
+		},
+		{
+			name: "subscripts",
+			node: LinkNodes([]Node{NewConst(ConstRoot), NewArrayIndex([]Node{
+				binary(BinarySubscript, binary(BinarySub, numeric("2.5"), integer("1")), NewConst(ConstLast)),
+				binary(BinarySubscript, binary(BinaryMul, integer("2"), integer("2")), nil),
+			})}),
+			exp: LinkNodes([]Node{NewConst(ConstRoot), NewArrayIndex([]Node{
+				binary(BinarySubscript, numeric("1.5"), NewConst(ConstLast)),
+				binary(BinarySubscript, integer("4"), nil),
+			})}),
+			str: "$[1.5 to last,4]",
+		},
+		{
+			name: "regex",
+			node: filter(regex(LinkNodes([]Node{NewConst(ConstCurrent), NewKey("a")}))),
+			exp:  filter(regex(LinkNodes([]Node{NewConst(ConstCurrent), NewKey("a")}))),
+			str:  `$?(@."a" like_regex "^a" flag "i")`,
+		},
+		{
+			name: "and_true_left",
+			node: filter(binary(BinaryAnd, binary(BinaryEqual, integer("1"), integer("1")), gt("a"))),
+			exp:  filter(gt("a")),
+			str:  `$?(@."a" > 1)`,
+		},
+		{
+			name: "and_true_right",
+			node: filter(binary(BinaryAnd, gt("a"), binary(BinaryLess, numeric("1.5"), integer("2")))),
+			exp:  filter(gt("a")),
+			str:  `$?(@."a" > 1)`,
+		},
+		{
+			name: "and_false",
+			node: filter(binary(BinaryAnd, binary(BinaryEqual, integer("1"), integer("2")), gt("a"))),
+			exp:  filter(binary(BinaryAnd, binary(BinaryEqual, integer("1"), integer("2")), gt("a"))),
+			str:  `$?(1 == 2 && @."a" > 1)`,
+		},
+		{
+			name: "or_false_left",
+			node: filter(binary(BinaryOr, binary(BinaryEqual, NewString("a"), NewString("b")), gt("a"))),
+			exp:  filter(gt("a")),
+			str:  `$?(@."a" > 1)`,
+		},
+		{
+			name: "or_false_right",
+			node: filter(binary(BinaryOr, gt("a"), binary(BinaryGreaterOrEqual, integer("1"), numeric("1.5")))),
+			exp:  filter(gt("a")),
+			str:  `$?(@."a" > 1)`,
+		},
+		{
+			name: "or_true",
+			node: filter(binary(BinaryOr, binary(BinaryNotEqual, integer("1"), integer("2")), gt("a"))),
+			exp:  filter(binary(BinaryOr, binary(BinaryNotEqual, integer("1"), integer("2")), gt("a"))),
+			str:  `$?(1 != 2 || @."a" > 1)`,
+		},
+		{
+			name: "compare_exact",
+			node: filter(binary(BinaryOr,
+				binary(BinaryEqual, integer("9007199254740993"), numeric("9007199254740992.0")),
+				gt("a"),
+			)),
+			exp: filter(gt("a")),
+			str: `$?(@."a" > 1)`,
+		},
+		{
+			name: "compare_decimal",
+			node: filter(binary(BinaryAnd,
+				binary(BinaryLess, numeric("0.1"), numeric("0.10000000000000002")),
+				gt("a"),
+			)),
+			exp: filter(gt("a")),
+			str: `$?(@."a" > 1)`,
+		},
+		{
+			name: "compare_decimal_same_float",
+			node: filter(binary(BinaryOr,
+				binary(BinaryEqual, numeric("0.1"), numeric("0.10000000000000001")),
+				gt("a"),
+			)),
+			exp: filter(binary(BinaryOr,
+				binary(BinaryEqual, numeric("0.1"), numeric("0.10000000000000001")),
+				gt("a"),
+			)),
+			str: `$?(0.1 == 0.10000000000000001 || @."a" > 1)`,
+		},
+		{
+			name: "compare_folded",
+			node: filter(binary(BinaryAnd,
+				binary(BinaryLessOrEqual, binary(BinaryAdd, integer("1"), integer("1")), integer("2")),
+				gt("a"),
+			)),
+			exp: filter(gt("a")),
+			str: `$?(@."a" > 1)`,
+		},
+		{
+			name: "compare_mixed",
+			node: filter(binary(BinaryAnd, binary(BinaryEqual, integer("1"), NewString("1")), gt("a"))),
+			exp:  filter(binary(BinaryAnd, binary(BinaryEqual, integer("1"), NewString("1")), gt("a"))),
+			str:  `$?(1 == "1" && @."a" > 1)`,
+		},
+		{
+			name: "double_not",
+			node: filter(unary(UnaryNot, unary(UnaryNot, gt("a")))),
+			exp:  filter(gt("a")),
+			str:  `$?(@."a" > 1)`,
+		},
+		{
+			name: "triple_not",
+			node: filter(unary(UnaryNot, unary(UnaryNot, unary(UnaryNot, gt("a"))))),
+			exp:  filter(unary(UnaryNot, gt("a"))),
+			str:  `$?(!(@."a" > 1))`,
+		},
+		{
+			name: "decimal_args",
+			node: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				binary(BinaryDecimal, binary(BinaryAdd, integer("1"), integer("1")), nil),
+			}),
+			exp: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				binary(BinaryDecimal, binary(BinaryAdd, integer("1"), integer("1")), nil),
+			}),
+			str: "$.decimal(1 + 1)",
+		},
+		{
+			name: "datetime_args",
+			node: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				unary(UnaryTime, binary(BinaryAdd, integer("1"), integer("1"))),
+			}),
+			exp: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				unary(UnaryTime, binary(BinaryAdd, integer("1"), integer("1"))),
+			}),
+			str: "$.time(1 + 1)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			tree, err := New(true, false, tc.node)
+			r.NoError(err)
+			orig := tree.String()
+
+			opt := Optimize(tree)
+			a.Equal(tc.exp, opt.Root())
+			if tc.str != "" {
+				a.Equal(tc.str, opt.String())
+			}
+			a.True(opt.IsLax())
+			a.False(opt.IsPredicate())
+
+			// The original must be unchanged.
+			a.Equal(orig, tree.String())
+		})
+	}
+
+	t.Run("modes", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+
+		pred := binary(BinaryGreater, LinkNodes([]Node{NewConst(ConstRoot), NewKey("a")}), integer("1"))
+		tree, err := New(false, true, pred)
+		r.NoError(err)
+		opt := Optimize(tree)
+		a.True(opt.IsStrict())
+		a.True(opt.IsPredicate())
+	})
+}
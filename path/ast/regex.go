@@ -1,9 +1,12 @@
 package ast
 
 import (
-	"errors"
 	"fmt"
 	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Use golang.org/x/tools/cmd/stringer to generate the String method for the
@@ -58,13 +61,7 @@ func newRegexFlags(flags string) (regexFlags, error) {
 		}
 	}
 
-	// Validate compatibility with Go flags.
-	reFlags := regexFlags(bitMask)
-	if _, err := reFlags._syntaxFlags(); err != nil {
-		return 0, err
-	}
-
-	return reFlags, nil
+	return regexFlags(bitMask), nil
 }
 
 // String returns the flags formatted as a SQL/JSON path 'flags ""' expression.
@@ -73,28 +70,32 @@ func (f regexFlags) String() string {
 		return ""
 	}
 
-	flags := ` flag "`
+	return ` flag "` + f.letters() + `"`
+}
+
+// letters returns the flag characters for the flags set in f, suitable for
+// passing to newRegexFlags. Unknown bits produce an unrecognized character.
+func (f regexFlags) letters() string {
+	flags := ""
 	bitMask := regexFlag(f)
 
 	for _, flag := range []regexFlag{regexICase, regexDotAll, regexMLine, regexWSpace, regexQuote} {
 		if bitMask&flag > 0 {
 			flags += flag.String()
+			bitMask &^= flag
 		}
 	}
 
-	return flags + `"`
+	if bitMask != 0 {
+		flags += "?"
+	}
+
+	return flags
 }
 
-// convertRegexFlags converts from XQuery regex flags to those recognized by
+// syntaxFlags converts from XQuery regex flags to those recognized by
 // regexp/syntax.
 func (f regexFlags) syntaxFlags() syntax.Flags {
-	synFlags, _ := f._syntaxFlags()
-	return synFlags
-}
-
-// _syntaxFlags converts from XQuery regex flags to those recognized by
-// regexp/syntax. Returns an error for unsupported use of the 'x' flag.
-func (f regexFlags) _syntaxFlags() (syntax.Flags, error) {
 	cFlags := syntax.OneLine | syntax.ClassNL | syntax.PerlX
 	bitMask := regexFlag(f)
 
@@ -106,25 +107,7 @@ func (f regexFlags) _syntaxFlags() (syntax.Flags, error) {
 	// Per XQuery spec, if 'q' is specified then 'm', 's', 'x' are ignored
 	// https://www.w3.org/TR/xpath-functions-3/#flags
 	if bitMask&regexQuote != 0 {
-		return cFlags | syntax.Literal, nil
-	}
-
-	// From the Postgres source
-	// https://github.com/postgres/postgres/blob/REL_17_2/src/backend/utils/adt/jsonpath_gram.y#L667-L673:
-	//
-	// > XQuery's 'x' mode is related to Spencer's expanded mode, but it's
-	// > not really enough alike to justify treating JSP_REGEX_WSPACE as
-	// > REG_EXPANDED. For now we treat 'x' as unimplemented; perhaps in
-	// > future we'll modify the regex library to have an option for
-	// > XQuery-style ignore-whitespace mode.
-	//
-	// Go regexp doesn't appear to support 'x', so we, too, treat it as
-	// unimplemented.
-	if bitMask&regexWSpace != 0 {
-		//nolint:err113
-		return 0, errors.New(
-			`XQuery "x" flag (expanded regular expressions) is not implemented`,
-		)
+		return cFlags | syntax.Literal
 	}
 
 	if bitMask&regexMLine != 0 {
@@ -135,7 +118,14 @@ func (f regexFlags) _syntaxFlags() (syntax.Flags, error) {
 		cFlags |= syntax.DotNL
 	}
 
-	return cFlags, nil
+	return cFlags
+}
+
+// isExpanded returns true if the flags include the 'x' flag and not the 'q'
+// flag, in which case translateRegex strips whitespace and comments from the
+// pattern.
+func (f regexFlags) isExpanded() bool {
+	return regexFlag(f)&(regexWSpace|regexQuote) == regexWSpace
 }
 
 // shouldQuoteMeta returns true if the flags include the 'q' flag, in which case
@@ -183,7 +173,8 @@ func (f regexFlags) goFlags() string {
 	return string(append(flags, ')'))
 }
 
-// validateRegex validates that regexp/syntax compiles pattern with flags.
+// validateRegex validates that regexp/syntax compiles pattern, translated
+// by translateRegex, with flags.
 func validateRegex(pattern string, flags regexFlags) error {
 	// Make sure it parses.
 	_, err := syntax.Parse(pattern, flags.syntaxFlags())
@@ -201,3 +192,265 @@ func validateRegex(pattern string, flags regexFlags) error {
 
 	return nil
 }
+
+// translateRegex translates pattern from the PostgreSQL regular expression
+// syntax used by like_regex to the RE2 syntax of the regexp package. It
+// converts escapes that differ between the two, such as \b (backspace), \B
+// (backslash), \y (word boundary), \Z (end of text), and \uwxyz (Unicode
+// code point), and simple bracket expressions like [[.-.]]. If expanded is
+// true, it also strips whitespace and # comments outside bracket
+// expressions. It passes other syntax through unchanged, so that
+// [validateRegex] reports invalid syntax.
+//
+// Some PostgreSQL constructs, such as back references and lookahead
+// constraints, have no RE2 equivalent. translateRegex replaces them with
+// empty groups and returns an error describing the first of them.
+func translateRegex(pattern string, expanded bool) (string, error) {
+	t := &regexTranslator{src: []rune(pattern), expanded: expanded}
+	t.buf.Grow(len(pattern))
+	for t.pos < len(t.src) {
+		if t.src[t.pos] == '[' {
+			t.bracket()
+		} else {
+			t.atom()
+		}
+	}
+	return t.buf.String(), t.err
+}
+
+// regexTranslator translates a PostgreSQL regular expression to RE2 syntax.
+type regexTranslator struct {
+	src      []rune
+	pos      int
+	expanded bool
+	buf      strings.Builder
+	err      error
+}
+
+// unsupported records an error for construct described by desc, unless one
+// has already been recorded.
+func (t *regexTranslator) unsupported(desc, construct string) {
+	if t.err == nil {
+		//nolint:err113
+		t.err = fmt.Errorf("%v `%v` not supported by like_regex", desc, construct)
+	}
+}
+
+// omit records an error for construct described by desc and writes an empty
+// group in its place.
+func (t *regexTranslator) omit(desc, construct string) {
+	t.unsupported(desc, construct)
+	t.buf.WriteString("(?:)")
+}
+
+// hasPrefix returns true if the unconsumed source starts with prefix.
+func (t *regexTranslator) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(string(t.src[t.pos:]), prefix)
+}
+
+// atom translates the character or escape at t.pos outside a bracket
+// expression.
+func (t *regexTranslator) atom() {
+	ch := t.src[t.pos]
+	switch {
+	case t.expanded && unicode.IsSpace(ch):
+		t.pos++
+	case t.expanded && ch == '#':
+		for t.pos < len(t.src) && t.src[t.pos] != '\n' {
+			t.pos++
+		}
+	case ch == '\\':
+		t.escape(false)
+	case ch == '(' && (t.hasPrefix("(?=") || t.hasPrefix("(?!")):
+		t.unsupported("lookahead constraint", string(t.src[t.pos:t.pos+3]))
+		t.buf.WriteString("(?:")
+		t.pos += 3
+	case ch == '(' && (t.hasPrefix("(?<=") || t.hasPrefix("(?<!")):
+		t.unsupported("lookbehind constraint", string(t.src[t.pos:t.pos+4]))
+		t.buf.WriteString("(?:")
+		t.pos += 4
+	default:
+		t.buf.WriteRune(ch)
+		t.pos++
+	}
+}
+
+// bracket translates the bracket expression starting at t.pos.
+func (t *regexTranslator) bracket() {
+	// Word boundary bracket expressions.
+	for _, word := range []string{"[[:<:]]", "[[:>:]]"} {
+		if t.hasPrefix(word) {
+			t.omit("word boundary constraint", word)
+			t.pos += len(word)
+			return
+		}
+	}
+
+	t.buf.WriteRune('[')
+	t.pos++
+	if t.pos < len(t.src) && t.src[t.pos] == '^' {
+		t.buf.WriteRune('^')
+		t.pos++
+	}
+	if t.pos < len(t.src) && t.src[t.pos] == ']' {
+		// Leading ] is literal.
+		t.buf.WriteString(`\]`)
+		t.pos++
+	}
+
+	for t.pos < len(t.src) {
+		switch ch := t.src[t.pos]; {
+		case ch == ']':
+			t.buf.WriteRune(ch)
+			t.pos++
+			return
+		case ch == '\\':
+			t.escape(true)
+		case t.hasPrefix("[.") || t.hasPrefix("[="):
+			t.element()
+		default:
+			// Includes character classes like [:alpha:], supported by RE2.
+			t.buf.WriteRune(ch)
+			t.pos++
+		}
+	}
+}
+
+// element translates the collating element ([.x.]) or equivalence class
+// ([=x=]) at t.pos. RE2 supports neither, but single characters represent
+// themselves.
+func (t *regexTranslator) element() {
+	delim := t.src[t.pos+1]
+	end := strings.Index(string(t.src[t.pos+2:]), string(delim)+"]")
+	if end < 0 {
+		// Let validateRegex report the unclosed bracket.
+		t.buf.WriteString(string(t.src[t.pos:]))
+		t.pos = len(t.src)
+		return
+	}
+
+	elem := []rune(string(t.src[t.pos+2:])[:end])
+	construct := string(t.src[t.pos : t.pos+2+len(elem)+2])
+	t.pos += len(elem) + 4
+	if len(elem) == 1 {
+		if elem[0] < utf8.RuneSelf && !isAlnum(elem[0]) {
+			t.buf.WriteRune('\\')
+		}
+		t.buf.WriteRune(elem[0])
+		return
+	}
+
+	if delim == '.' {
+		t.omit("collating element", construct)
+	} else {
+		t.omit("equivalence class", construct)
+	}
+}
+
+// escape translates the escape starting with the backslash at t.pos.
+// inBracket indicates whether it appears in a bracket expression.
+func (t *regexTranslator) escape(inBracket bool) {
+	t.pos++
+	if t.pos >= len(t.src) {
+		// Let validateRegex report the trailing backslash.
+		t.buf.WriteRune('\\')
+		return
+	}
+
+	ch := t.src[t.pos]
+	t.pos++
+	switch ch {
+	case 'b':
+		t.buf.WriteString(`\x08`)
+	case 'B':
+		t.buf.WriteString(`\\`)
+	case 'e':
+		t.buf.WriteString(`\x1b`)
+	case 'c':
+		if t.pos < len(t.src) {
+			t.codePoint(int64(t.src[t.pos] & 0x1f))
+			t.pos++
+			return
+		}
+		t.buf.WriteString(`\c`)
+	case 'u':
+		t.hexEscape(ch, 4, 4)
+	case 'U':
+		t.hexEscape(ch, 8, 8)
+	case 'x':
+		if t.pos < len(t.src) && t.src[t.pos] == '{' {
+			// RE2 \x{hhh} syntax.
+			t.buf.WriteString(`\x`)
+			return
+		}
+		t.hexEscape(ch, 1, 8)
+	case '0':
+		t.octalEscape()
+	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		if inBracket {
+			t.pos--
+			t.octalEscape()
+			return
+		}
+		start := t.pos - 2
+		for t.pos < len(t.src) && isDigit(t.src[t.pos]) {
+			t.pos++
+		}
+		t.omit("back reference", string(t.src[start:t.pos]))
+	case 'Z':
+		t.buf.WriteString(`\z`)
+	case 'y':
+		t.buf.WriteString(`\b`)
+	case 'Y':
+		t.buf.WriteString(`\B`)
+	case 'm', 'M':
+		t.omit("word boundary constraint", `\`+string(ch))
+	default:
+		if ch >= utf8.RuneSelf && !isAlnum(ch) {
+			// Escaped non-ASCII symbols represent themselves.
+			t.buf.WriteRune(ch)
+			return
+		}
+		// Escaped ASCII punctuation, escapes shared with RE2, and invalid
+		// escapes.
+		t.buf.WriteRune('\\')
+		t.buf.WriteRune(ch)
+	}
+}
+
+// hexEscape translates the escape \<ch> followed by min to max hexadecimal
+// digits into a RE2 \x{hhh} escape. If too few digits follow, it writes the
+// escape unchanged for validateRegex to report.
+func (t *regexTranslator) hexEscape(ch rune, minDigits, maxDigits int) {
+	start := t.pos
+	for t.pos < len(t.src) && t.pos-start < maxDigits && isHexDigit(t.src[t.pos]) {
+		t.pos++
+	}
+	if t.pos-start < minDigits {
+		t.pos = start
+		t.buf.WriteRune('\\')
+		t.buf.WriteRune(ch)
+		return
+	}
+	t.buf.WriteString(`\x{` + string(t.src[start:t.pos]) + `}`)
+}
+
+// octalEscape translates the up to three octal digits at t.pos into a RE2
+// \x{hh} escape.
+func (t *regexTranslator) octalEscape() {
+	var code int64
+	for i := 0; i < 3 && t.pos < len(t.src) && '0' <= t.src[t.pos] && t.src[t.pos] <= '7'; i++ {
+		code = code*8 + int64(t.src[t.pos]-'0')
+		t.pos++
+	}
+	t.codePoint(code)
+}
+
+// codePoint writes code as a RE2 \x{hh} escape.
+func (t *regexTranslator) codePoint(code int64) {
+	t.buf.WriteString(`\x{` + strconv.FormatInt(code, 16) + `}`)
+}
+
+func isDigit(ch rune) bool    { return '0' <= ch && ch <= '9' }
+func isAlnum(ch rune) bool    { return isDigit(ch) || unicode.IsLetter(ch) }
+func isHexDigit(ch rune) bool { return isDigit(ch) || 'a' <= ch|0x20 && ch|0x20 <= 'f' }
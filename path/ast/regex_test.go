@@ -78,7 +78,9 @@ func TestRegexFlags(t *testing.T) {
 		{
 			name: "x",
 			expr: "x",
-			err:  `XQuery "x" flag (expanded regular expressions) is not implemented`,
+			exp:  regexFlags(regexWSpace),
+			str:  ` flag "x"`,
+			syn:  syntax.OneLine | syntax.ClassNL | syntax.PerlX,
 		},
 		{
 			name: "q",
@@ -202,3 +204,94 @@ func TestValidateRegex(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateRegex(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		re       string
+		expanded bool
+		exp      string
+		err      string
+	}{
+		{"plain", `^a.*b+$`, false, `^a.*b+$`, ""},
+		{"shared_escapes", `\d\D\s\S\w\W\A\n\t\.`, false, `\d\D\s\S\w\W\A\n\t\.`, ""},
+		{"backspace", `a\b`, false, `a\x08`, ""},
+		{"backslash", `a\B`, false, `a\\`, ""},
+		{"escape_char", `\e`, false, `\x1b`, ""},
+		{"control", `\cA\c[`, false, `\x{1}\x{1b}`, ""},
+		{"unicode", `\u00e9\U0001F600`, false, `\x{00e9}\x{0001F600}`, ""},
+		{"short_unicode", `\u12`, false, `\u12`, ""},
+		{"hex", `\x41\xe9`, false, `\x{41}\x{e9}`, ""},
+		{"re2_hex", `\x{41}`, false, `\x{41}`, ""},
+		{"octal", `\0\012`, false, `\x{0}\x{a}`, ""},
+		{"end", `a\Z`, false, `a\z`, ""},
+		{"word_boundary", `\yhi\Y`, false, `\bhi\B`, ""},
+		{"escaped_symbol", `\€\✓`, false, `€✓`, ""},
+		{"bracket", `[a-z\b]`, false, `[a-z\x08]`, ""},
+		{"bracket_leading_close", `[]a]`, false, `[\]a]`, ""},
+		{"bracket_negated_close", `[^]a]`, false, `[^\]a]`, ""},
+		{"posix_class", `[[:alpha:][:digit:]]`, false, `[[:alpha:][:digit:]]`, ""},
+		{"collating_element", `[a[.-.]z]`, false, `[a\-z]`, ""},
+		{"equivalence_class", `[[=a=]b]`, false, `[ab]`, ""},
+		{"bracket_octal", `[\1]`, false, `[\x{1}]`, ""},
+		{"expanded", "a b\t#comment\nc", true, `abc`, ""},
+		{"expanded_bracket", `[a b] c`, true, `[a b]c`, ""},
+		{"expanded_escaped_space", `a\ b\#`, true, `a\ b\#`, ""},
+		{"not_expanded", "a b #c", false, "a b #c", ""},
+		{
+			name: "back_reference",
+			re:   `(a)\1`,
+			exp:  `(a)(?:)`,
+			err:  "back reference `\\1` not supported by like_regex",
+		},
+		{
+			name: "multi_digit_back_reference",
+			re:   `(a)\12b`,
+			exp:  `(a)(?:)b`,
+			err:  "back reference `\\12` not supported by like_regex",
+		},
+		{
+			name: "lookahead",
+			re:   `a(?=b)`,
+			exp:  `a(?:b)`,
+			err:  "lookahead constraint `(?=` not supported by like_regex",
+		},
+		{
+			name: "negative_lookbehind",
+			re:   `(?<!a)b`,
+			exp:  `(?:a)b`,
+			err:  "lookbehind constraint `(?<!` not supported by like_regex",
+		},
+		{
+			name: "word_start",
+			re:   `\mhi\M`,
+			exp:  `(?:)hi(?:)`,
+			err:  "word boundary constraint `\\m` not supported by like_regex",
+		},
+		{
+			name: "word_end_bracket",
+			re:   `hi[[:>:]]`,
+			exp:  `hi(?:)`,
+			err:  "word boundary constraint `[[:>:]]` not supported by like_regex",
+		},
+		{
+			name: "multi_char_collating_element",
+			re:   `[[.ch.]]`,
+			exp:  `[(?:)]`,
+			err:  "collating element `[.ch.]` not supported by like_regex",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			re, err := translateRegex(tc.re, tc.expanded)
+			assert.Equal(t, tc.exp, re)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package ast
+
+// A Visitor's Visit method is invoked for each node encountered by [Walk].
+// If the result visitor w is not nil, Walk visits each of the children of
+// node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the nodes of an AST in depth-first order. It starts by
+// calling v.Visit(node); node must not be nil. If the visitor w returned by
+// v.Visit(node) is not nil, Walk is invoked recursively with visitor w for
+// each of the non-nil children of node, followed by a call of w.Visit(nil).
+// Walk then walks the next node in node's accessor list, if any, with v.
+//
+// The children of a node are the operands of a [BinaryNode], [UnaryNode],
+// or [RegexNode] and the subscripts of an [ArrayIndexNode]. Method arguments,
+// such as the precision and scale of .decimal() and the template of
+// .datetime(), are operands.
+func Walk(v Visitor, node Node) {
+	for ; node != nil; node = node.Next() {
+		w := v.Visit(node)
+		if w == nil {
+			continue
+		}
+
+		switch n := node.(type) {
+		case *BinaryNode:
+			if n.left != nil {
+				Walk(w, n.left)
+			}
+			if n.right != nil {
+				Walk(w, n.right)
+			}
+		case *UnaryNode:
+			if n.operand != nil {
+				Walk(w, n.operand)
+			}
+		case *RegexNode:
+			if n.operand != nil {
+				Walk(w, n.operand)
+			}
+		case *ArrayIndexNode:
+			for _, sub := range n.subscripts {
+				Walk(w, sub)
+			}
+		}
+
+		w.Visit(nil)
+	}
+}
+
+// inspector adapts a function to the Visitor interface.
+type inspector func(Node) bool
+
+// Visit calls f(node) and returns f if it returns true.
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses the nodes of an AST in depth-first order, like [Walk].
+// It starts by calling f(node); node must not be nil. If f returns true,
+// Inspect invokes f recursively for each of the non-nil children of node,
+// followed by a call of f(nil). It then inspects the next node in node's
+// accessor list, if any.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
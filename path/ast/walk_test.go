@@ -0,0 +1,137 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	regex, err := NewRegex(NewConst(ConstCurrent), "^a", "i")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		node Node
+		exp  []string
+		skip string
+	}{
+		{
+			name: "root",
+			node: NewConst(ConstRoot),
+			exp:  []string{"$", "<nil>"},
+		},
+		{
+			name: "accessors",
+			node: LinkNodes([]Node{NewConst(ConstRoot), NewKey("a"), NewMethod(MethodSize)}),
+			exp:  []string{"$", "<nil>", `"a"`, "<nil>", ".size()", "<nil>"},
+		},
+		{
+			name: "binary",
+			node: NewBinary(BinaryAdd, NewInteger("1"), NewVariable("x")),
+			exp:  []string{"1 + $\"x\"", "1", "<nil>", `$"x"`, "<nil>", "<nil>"},
+		},
+		{
+			name: "decimal",
+			node: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				NewBinary(BinaryDecimal, NewInteger("4"), NewInteger("2")),
+			}),
+			exp: []string{"$", "<nil>", ".decimal(4,2)", "4", "<nil>", "2", "<nil>", "<nil>"},
+		},
+		{
+			name: "decimal_no_args",
+			node: NewBinary(BinaryDecimal, nil, nil),
+			exp:  []string{".decimal()", "<nil>"},
+		},
+		{
+			name: "unary",
+			node: NewUnary(UnaryNot, NewConst(ConstTrue)),
+			exp:  []string{"!(true)", "true", "<nil>", "<nil>"},
+		},
+		{
+			name: "datetime_template",
+			node: NewUnary(UnaryDateTime, NewString("HH24")),
+			exp:  []string{`.datetime("HH24")`, `"HH24"`, "<nil>", "<nil>"},
+		},
+		{
+			name: "datetime_no_template",
+			node: NewUnary(UnaryDateTime, nil),
+			exp:  []string{".datetime()", "<nil>"},
+		},
+		{
+			name: "regex",
+			node: regex,
+			exp:  []string{`@ like_regex "^a" flag "i"`, "@", "<nil>", "<nil>"},
+		},
+		{
+			name: "array_index",
+			node: NewArrayIndex([]Node{
+				NewBinary(BinarySubscript, NewInteger("1"), nil),
+				NewBinary(BinarySubscript, NewInteger("2"), NewConst(ConstLast)),
+			}),
+			exp: []string{
+				"[1,2 to last]",
+				"1", "1", "<nil>", "<nil>",
+				"2 to last", "2", "<nil>", "last", "<nil>", "<nil>",
+				"<nil>",
+			},
+		},
+		{
+			name: "filter",
+			node: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				NewKey("a"),
+				NewUnary(UnaryFilter, NewBinary(
+					BinaryGreater,
+					LinkNodes([]Node{NewConst(ConstCurrent), NewKey("b")}),
+					NewInteger("1"),
+				)),
+				NewKey("c"),
+			}),
+			exp: []string{
+				"$", "<nil>",
+				`"a"`, "<nil>",
+				`?(@."b" > 1)."c"`,
+				`@."b" > 1`, "@", "<nil>", `"b"`, "<nil>", "1", "<nil>", "<nil>",
+				"<nil>",
+				`"c"`, "<nil>",
+			},
+		},
+		{
+			name: "skip_children",
+			node: LinkNodes([]Node{
+				NewConst(ConstRoot),
+				NewUnary(UnaryFilter, NewBinary(BinaryEqual, NewConst(ConstCurrent), NewInteger("1"))),
+				NewMethod(MethodType),
+			}),
+			skip: "?(@ == 1).type()",
+			exp:  []string{"$", "<nil>", "?(@ == 1).type()", ".type()", "<nil>"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+
+			visited := []string{}
+			Inspect(tc.node, func(node Node) bool {
+				if node == nil {
+					visited = append(visited, "<nil>")
+					return false
+				}
+				// String includes any next nodes, except for constants and
+				// keys.
+				str := node.String()
+				if c, ok := node.(*ConstNode); ok {
+					str = c.Const().String()
+				}
+				visited = append(visited, str)
+				return str != tc.skip
+			})
+			a.Equal(tc.exp, visited)
+		})
+	}
+}
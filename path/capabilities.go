@@ -0,0 +1,128 @@
+package path
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/theory/sqljson/path/ast"
+)
+
+// ArgKind describes the arguments accepted by an item method.
+type ArgKind int
+
+//revive:disable:exported
+const (
+	ArgNone           ArgKind = iota // no arguments, e.g., .size()
+	ArgPrecision                     // optional integer precision, e.g., .time(3)
+	ArgTemplate                      // optional template string, e.g., .datetime("HH24:MI")
+	ArgPrecisionScale                // optional precision and scale, e.g., .decimal(5, 2)
+)
+
+// MethodInfo describes an item method, as reported by [Capabilities].
+type MethodInfo struct {
+	// Name is the name of the method, without the leading dot or
+	// parentheses, e.g., "size" or "datetime".
+	Name string
+
+	// Args describes the arguments the method accepts.
+	Args ArgKind
+
+	// ArgsImplemented is false if execution does not yet support calls to
+	// the method with arguments, which return an error. It's true for
+	// methods that take no arguments.
+	ArgsImplemented bool
+}
+
+// SyntaxInfo describes the item methods, operators, and keywords supported
+// by the parser, as reported by [Capabilities].
+type SyntaxInfo struct {
+	// Methods lists the built-in item methods, sorted by name. Custom
+	// methods registered with [exec.RegisterMethod] are not included.
+	Methods []MethodInfo
+
+	// BinaryOperators lists the binary operators, e.g., "==" and
+	// "starts with", in order of increasing precedence.
+	BinaryOperators []string
+
+	// UnaryOperators lists the prefix unary operators, e.g., "!" and
+	// "exists", and the postfix operator "is unknown".
+	UnaryOperators []string
+
+	// Keywords lists the remaining syntax: the mode prefixes "lax" and
+	// "strict", the constants, e.g., "$", "@", "last", and "null", the
+	// filter operator "?", the array subscript range keyword "to", and the
+	// regular expression keywords "like_regex" and "flag".
+	Keywords []string
+}
+
+// methodArgs maps the names of item methods that take arguments to the kind
+// of arguments they take.
+var methodArgs = map[string]ArgKind{
+	"decimal":      ArgPrecisionScale,
+	"datetime":     ArgTemplate,
+	"time":         ArgPrecision,
+	"time_tz":      ArgPrecision,
+	"timestamp":    ArgPrecision,
+	"timestamp_tz": ArgPrecision,
+}
+
+// unimplementedArgs records the item methods whose arguments the executor
+// does not yet support.
+var unimplementedArgs = map[string]bool{
+	"datetime": true,
+}
+
+// Capabilities returns a SyntaxInfo describing the item methods, operators,
+// and keywords supported by the parser, for applications such as editors
+// that offer completions. The method and operator names come from the
+// [ast] package constants the parser produces.
+func Capabilities() SyntaxInfo {
+	info := SyntaxInfo{}
+
+	for name := ast.MethodAbs; name < ast.MethodCustom; name++ {
+		info.addMethod(name.String())
+	}
+	info.addMethod(ast.BinaryDecimal.String())
+	for op := ast.UnaryDateTime; op <= ast.UnaryTimestampTZ; op++ {
+		info.addMethod(op.String())
+	}
+	slices.SortFunc(info.Methods, func(a, b MethodInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, op := range []ast.BinaryOperator{
+		ast.BinaryOr, ast.BinaryAnd,
+		ast.BinaryEqual, ast.BinaryNotEqual, ast.BinaryLess, ast.BinaryGreater,
+		ast.BinaryLessOrEqual, ast.BinaryGreaterOrEqual, ast.BinaryStartsWith,
+		ast.BinaryAdd, ast.BinarySub,
+		ast.BinaryMul, ast.BinaryDiv, ast.BinaryMod,
+	} {
+		info.BinaryOperators = append(info.BinaryOperators, op.String())
+	}
+
+	for op := ast.UnaryExists; op < ast.UnaryFilter; op++ {
+		info.UnaryOperators = append(info.UnaryOperators, op.String())
+	}
+
+	info.Keywords = []string{"lax", "strict"}
+	for c := ast.ConstRoot; c <= ast.ConstNull; c++ {
+		info.Keywords = append(info.Keywords, c.String())
+	}
+	info.Keywords = append(
+		info.Keywords,
+		ast.UnaryFilter.String(), ast.BinarySubscript.String(), "like_regex", "flag",
+	)
+
+	return info
+}
+
+// addMethod appends a MethodInfo for the method named by name to
+// info.Methods, stripping any leading dot and trailing parentheses.
+func (info *SyntaxInfo) addMethod(name string) {
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "."), "()")
+	info.Methods = append(info.Methods, MethodInfo{
+		Name:            name,
+		Args:            methodArgs[name],
+		ArgsImplemented: !unimplementedArgs[name],
+	})
+}
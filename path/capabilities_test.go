@@ -0,0 +1,136 @@
+package path
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/exec"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	info := Capabilities()
+
+	names := make([]string, len(info.Methods))
+	for i, m := range info.Methods {
+		names[i] = m.Name
+	}
+	a.Equal([]string{
+		"abs", "bigint", "boolean", "ceiling", "date", "datetime", "decimal",
+		"double", "floor", "integer", "keyvalue", "number", "size", "string",
+		"time", "time_tz", "timestamp", "timestamp_tz", "type",
+	}, names)
+	a.Equal([]string{
+		"||", "&&", "==", "!=", "<", ">", "<=", ">=", "starts with",
+		"+", "-", "*", "/", "%",
+	}, info.BinaryOperators)
+	a.Equal([]string{"exists", "!", "is unknown", "+", "-"}, info.UnaryOperators)
+	a.Equal([]string{
+		"lax", "strict", "$", "@", "last", "[*]", "*", "true", "false", "null",
+		"?", "to", "like_regex", "flag",
+	}, info.Keywords)
+}
+
+func TestCapabilitiesParse(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	info := Capabilities()
+
+	// Arguments accepted by each kind of method; the first is always none.
+	args := map[ArgKind][]string{
+		ArgNone:           {""},
+		ArgPrecision:      {"", "2"},
+		ArgTemplate:       {"", `"HH24:MI"`},
+		ArgPrecisionScale: {"", "5", "5, 2"},
+	}
+
+	for _, m := range info.Methods {
+		t.Run(m.Name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			for i, arg := range args[m.Args] {
+				path, err := Parse("$." + m.Name + "(" + arg + ")")
+				r.NoError(err)
+
+				_, err = path.Query(ctx, "12:34", exec.WithSilent())
+				if i > 0 && !m.ArgsImplemented {
+					r.ErrorContains(err, "not yet supported")
+				} else {
+					r.NoError(err)
+				}
+			}
+
+			// Methods that take no arguments reject them.
+			if m.Args == ArgNone {
+				_, err := Parse("$." + m.Name + "(1)")
+				a.Error(err)
+			}
+		})
+	}
+
+	for _, op := range info.BinaryOperators {
+		t.Run(op, func(t *testing.T) {
+			t.Parallel()
+			snippet := "$ " + op + " 1"
+			switch op {
+			case "&&", "||":
+				snippet = "$ == 1 " + op + " $ == 2"
+			case "starts with":
+				snippet = `$ starts with "a"`
+			}
+			_, err := Parse(snippet)
+			require.NoError(t, err)
+		})
+	}
+
+	for _, op := range info.UnaryOperators {
+		t.Run(op, func(t *testing.T) {
+			t.Parallel()
+			snippet := op + " $"
+			switch op {
+			case "exists":
+				snippet = "exists($)"
+			case "!":
+				snippet = "!($ == 1)"
+			case "is unknown":
+				snippet = "($ == 1) is unknown"
+			}
+			_, err := Parse(snippet)
+			require.NoError(t, err)
+		})
+	}
+
+	for _, kw := range info.Keywords {
+		t.Run(kw, func(t *testing.T) {
+			t.Parallel()
+			snippet := kw
+			switch kw {
+			case "lax", "strict":
+				snippet = kw + " $"
+			case "@":
+				snippet = "$ ? (@ == 1)"
+			case "last":
+				snippet = "$[last]"
+			case "[*]":
+				snippet = "$" + kw
+			case "*":
+				snippet = "$.*"
+			case "?":
+				snippet = "$ ? ($ == 1)"
+			case "to":
+				snippet = "$[0 to 1]"
+			case "like_regex":
+				snippet = `$ like_regex "a"`
+			case "flag":
+				snippet = `$ like_regex "a" flag "i"`
+			}
+			_, err := Parse(snippet)
+			require.NoError(t, err)
+		})
+	}
+}
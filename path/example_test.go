@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/theory/sqljson/path"
@@ -182,6 +183,24 @@ func ExampleNew() {
 	// Output: $."x"[*]?(@ > 2)
 }
 
+func ExampleFromJSONPointer() {
+	p, err := path.FromJSONPointer("/a/b~1c/0")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%v\n", p)
+	// Output: $."a"."b/c"[0]
+}
+
+func ExampleFromDotted() {
+	p, err := path.FromDotted(`a."b.c"[0]`, path.WithStrict())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%v\n", p)
+	// Output: strict $."a"."b.c"[0]
+}
+
 func ExamplePath_PgIndexOperator() {
 	p := path.MustParse("$.x[*] ?(@ > 2)")
 	fmt.Printf("SQL Standard:    %v\n", p.PgIndexOperator())
@@ -191,6 +210,22 @@ func ExamplePath_PgIndexOperator() {
 	// Predicate Check: @@
 }
 
+// Use [exec.WithPredicateCheck] to catch the use of a predicate check
+// expression where a SQL standard path expression is expected, which would
+// otherwise return the result of the predicate check:
+func Example_withPredicateCheck() {
+	p := path.MustParse("$.x[*] > 2")
+	ctx := context.Background()
+	value := map[string][]int{"x": {1, 2, 3}}
+	res, err := p.Query(ctx, value)
+	fmt.Printf("%v: %v\n", res, err)
+
+	res, err = p.Query(ctx, value, exec.WithPredicateCheck())
+	fmt.Printf("%v: %v\n", res, err)
+	// Output: [true]: <nil>
+	// []: exec: Query expects a SQL standard path expression but "($.\"x\"[*] > 2)" is a predicate check expression
+}
+
 // [exec.WithVars] provides named values to be substituted into the
 // path expression. PostgreSQL jsonb_path_query() example:
 //
@@ -324,3 +359,204 @@ func ExamplePath_Exists_nULL() {
 	}
 	// Output: result was null
 }
+
+// Use [Path.AtQuestion] to mimic the PostgreSQL @? operator, which suppresses
+// errors that [Path.Exists] returns, such as an out of bounds array subscript
+// in a strict path, and instead returns [exec.NULL] for an unknown result:
+//
+//	=> SELECT jsonb_path_exists('["hi"]', 'strict $[1]');
+//	ERROR:  jsonpath array subscript is out of bounds
+//
+//	=> SELECT '["hi"]' @? 'strict $[1]';
+//	 ?column?
+//	----------
+//
+//	(1 row)
+func ExamplePath_AtQuestion() {
+	p := path.MustParse("strict $[1]")
+	ctx := context.Background()
+	res, err := p.Exists(ctx, []any{"hi"})
+	fmt.Printf("%v: %v\n", res, err)
+
+	res, err = p.AtQuestion(ctx, []any{"hi"})
+	fmt.Printf("%v: %v\n", res, err)
+	// Output: false: exec: jsonpath array subscript is out of bounds
+	// false: NULL
+}
+
+// Replace every item selected by a path, similar to the PostgreSQL jsonb_set()
+// function. The original value is not modified.
+func ExamplePath_Replace() {
+	p := path.MustParse(`$.items[*] ? (@.qty == 0).status`)
+	var value any
+	if err := json.Unmarshal([]byte(`{"items": [
+		{"id": 1, "qty": 0, "status": "ok"},
+		{"id": 2, "qty": 5, "status": "ok"},
+		{"id": 3, "qty": 0, "status": "ok"}
+	]}`), &value); err != nil {
+		log.Fatal(err)
+	}
+
+	res, err := p.Replace(context.Background(), value, "sold out")
+	if err != nil {
+		log.Fatal(err)
+	}
+	js, _ := json.Marshal(res)
+	fmt.Printf("%s\n", js)
+	// Output: {"items":[{"id":1,"qty":0,"status":"sold out"},{"id":2,"qty":5,"status":"ok"},{"id":3,"qty":0,"status":"sold out"}]}
+}
+
+// Delete every item selected by a path. The original value is not modified.
+func ExamplePath_Delete() {
+	p := path.MustParse(`$.tags[*] ? (@ starts with "tmp")`)
+	value := map[string]any{"tags": []any{"go", "tmp1", "json", "tmp2"}}
+
+	res, err := p.Delete(context.Background(), value)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%v\n", res)
+	// Output: map[tags:[go json]]
+}
+
+// Use [exec.WithStructTags] to execute a path against Go structs, honoring
+// json tags, without marshaling them to JSON. Objects selected by the path are
+// returned as the original Go values.
+func Example_withStructTags() {
+	type User struct {
+		Name  string   `json:"name"`
+		Age   int      `json:"age"`
+		Email *string  `json:"email"`
+		Tags  []string `json:"tags,omitempty"`
+	}
+
+	users := []*User{
+		{Name: "Kamala", Age: 42, Tags: []string{"admin"}},
+		{Name: "Tim", Age: 25},
+	}
+
+	ctx := context.Background()
+	opt := exec.WithStructTags("json")
+	p := path.MustParse(`$[*] ? (@.age > 30 && exists(@.tags))`)
+	res, err := p.First(ctx, users, opt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%v\n", res.(*User).Name)
+
+	p = path.MustParse(`$[*] ? (@.email == null).name`)
+	names, err := p.Query(ctx, users, opt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%v\n", names)
+	// Output: Kamala
+	// [Kamala Tim]
+}
+
+// Use [exec.RegisterMethod] to add a custom item method, such as .upper(),
+// and call it like a built-in method, including in filters. Register methods
+// before parsing paths that call them, usually in an init function.
+func Example_registerMethod() {
+	err := exec.RegisterMethod("upper", func(_ context.Context, val any) (any, error) {
+		str, ok := val.(string)
+		if !ok {
+			return nil, errors.New("can only be applied to a string")
+		}
+		return strings.ToUpper(str), nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p := path.MustParse(`$[*] ? (@.name.upper() starts with "AL").name`)
+	ctx := context.Background()
+	val := []any{
+		map[string]any{"name": "alice"},
+		map[string]any{"name": "Bob"},
+		map[string]any{"name": "Alfred"},
+	}
+	res, err := p.Query(ctx, val)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%v\n", res)
+	// Output: [alice Alfred]
+}
+
+// Use [Path.FirstOrDefault] to distinguish between a path that selects no
+// items and one that selects a JSON null.
+func ExamplePath_FirstOrDefault() {
+	p := path.MustParse("$.a")
+	ctx := context.Background()
+	for _, val := range []any{
+		map[string]any{"a": nil},
+		map[string]any{"b": 1},
+	} {
+		res, err := p.FirstOrDefault(ctx, val, "missing")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%v\n", res)
+	}
+	// Output: <nil>
+	// missing
+}
+
+// Use [Path.Keys] and [Path.Values] to select the keys and values of
+// objects, as if the path ended in .keyvalue().key or .keyvalue().value.
+func ExamplePath_Keys() {
+	p := path.MustParse("$[*]")
+	ctx := context.Background()
+	val := []any{
+		map[string]any{"y": "hi", "x": true},
+		map[string]any{"z": nil},
+	}
+
+	keys, err := p.Keys(ctx, val)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%v\n", keys)
+
+	values, err := p.Values(ctx, val)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%v\n", values)
+	// Output: [x y z]
+	// [true hi <nil>]
+}
+
+// Use [exec.WithStats] to collect statistics about each execution, such as
+// the number of nodes visited and items matched, for example to log the cost
+// of each request. Pass a new [exec.Stats] to each execution; its String
+// method formats the counters and wall time for a log line.
+func Example_withStats() {
+	p := path.MustParse(`$.items[*] ? (@.price > $max).name`)
+	ctx := context.Background()
+	val := map[string]any{"items": []any{
+		map[string]any{"name": "pen", "price": float64(2)},
+		map[string]any{"name": "lamp", "price": float64(40)},
+		map[string]any{"name": "desk", "price": float64(300)},
+	}}
+
+	for _, limit := range []float64{10, 100} {
+		var stats exec.Stats
+		res, err := p.Query(
+			ctx, val,
+			exec.WithVars(exec.Vars{"max": limit}),
+			exec.WithStats(&stats),
+		)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// Log stats.String() to include the wall time.
+		fmt.Printf(
+			"%v: nodes=%v matched=%v filters=%v\n",
+			res, stats.NodesVisited, stats.ItemsMatched, stats.FilterEvaluations,
+		)
+	}
+	// Output: [lamp desk]: nodes=17 matched=2 filters=3
+	// [desk]: nodes=16 matched=1 filters=3
+}
@@ -19,7 +19,7 @@ func (exec *Executor) execSubscript(
 	if !ok || subscript.Operator() != ast.BinarySubscript {
 		return 0, 0, fmt.Errorf(
 			"%w: jsonpath array subscript is not a single numeric value",
-			ErrExecution,
+			ErrType,
 		)
 	}
 
@@ -36,11 +36,13 @@ func (exec *Executor) execSubscript(
 		}
 	}
 
-	if !exec.ignoreStructuralErrors && (indexFrom < 0 || indexFrom > indexTo || indexTo >= arraySize) {
-		return 0, 0, fmt.Errorf(
-			"%w: jsonpath array subscript is out of bounds",
-			ErrVerbose,
-		)
+	if indexFrom < 0 || indexFrom > indexTo || indexTo >= arraySize {
+		if !exec.ignoreStructuralErrors {
+			return 0, 0, subscriptBoundsErr()
+		}
+		if exec.logSuppressed {
+			exec.suppressed(ctx, subscriptBoundsErr())
+		}
 	}
 
 	if indexFrom < 0 {
@@ -54,6 +56,15 @@ func (exec *Executor) execSubscript(
 	return indexFrom, indexTo, nil
 }
 
+// subscriptBoundsErr returns the error for an array subscript out of the
+// bounds of an array.
+func subscriptBoundsErr() error {
+	return fmt.Errorf(
+		"%w: jsonpath array subscript is out of bounds",
+		errVerboseStructural,
+	)
+}
+
 // execArrayIndex executes node against value and passes the values selected
 // to the next node. value must be an array ([]any) unless exec.autoWrap
 // returns true, in which case it is considered the sole value in an array.
@@ -84,16 +95,21 @@ func (exec *Executor) execArrayIndex(
 			}
 
 			for index := indexFrom; index <= indexTo; index++ {
-				v := array[index]
-				if v == nil {
-					continue
+				// Check for interrupts.
+				if err := checkContext(ctx); err != nil {
+					return statusFailed, err
 				}
-
+				v := array[index]
 				if next == nil && found == nil {
 					return statusOK, nil
 				}
 
+				loc := len(exec.location)
+				if ok {
+					exec.enterIndex(index)
+				}
 				res, resErr = exec.executeNextItem(ctx, node, next, v, found)
+				exec.leave(loc)
 				if res.failed() || (res == statusOK && found == nil) {
 					break
 				}
@@ -106,7 +122,7 @@ func (exec *Executor) execArrayIndex(
 	// In strict mode we accept only arrays.
 	return exec.returnVerboseError(fmt.Errorf(
 		"%w: jsonpath array accessor can only be applied to an array",
-		ErrVerbose,
+		errVerboseStructural,
 	))
 }
 
@@ -126,7 +142,7 @@ func (exec *Executor) executeItemUnwrapTargetArray(
 		)
 	}
 
-	return exec.executeAnyItem(ctx, node, array, found, 1, 1, 1, false, false)
+	return exec.executeAnyItem(ctx, node, array, nil, found, 1, 1, 1, false, false)
 }
 
 // getArrayIndex executes an array subscript expression and converts the
@@ -136,7 +152,8 @@ func (exec *Executor) getArrayIndex(
 	node ast.Node,
 	value any,
 ) (int, error) {
-	found := newList()
+	found := getList()
+	defer putList(found)
 	res, err := exec.executeItem(ctx, node, value, found)
 	if res == statusFailed {
 		return 0, err
@@ -145,7 +162,7 @@ func (exec *Executor) getArrayIndex(
 	if len(found.list) != 1 {
 		return 0, fmt.Errorf(
 			"%w: jsonpath array subscript is not a single numeric value",
-			ErrVerbose,
+			errVerboseType,
 		)
 	}
 
@@ -241,14 +241,14 @@ func TestExecArrayIndex(t *testing.T) {
 			errIs: ErrExecution,
 		},
 		{
-			name: "skip_nil",
+			name: "include_null",
 			path: strict,
 			node: ast.NewArrayIndex([]ast.Node{
 				ast.NewBinary(ast.BinarySubscript, ast.NewInteger("0"), ast.NewConst(ast.ConstLast)),
 			}),
 			value: []any{"hi", nil, "go", "on"},
 			exp:   statusOK,
-			found: []any{"hi", "go", "on"},
+			found: []any{"hi", nil, "go", "on"},
 		},
 		{
 			name: "no_found_param",
@@ -378,6 +378,7 @@ func TestGetArrayIndex(t *testing.T) {
 		name  string
 		node  ast.Node
 		value any
+		size  int
 		exp   int
 		err   string
 		errIs error
@@ -401,11 +402,52 @@ func TestGetArrayIndex(t *testing.T) {
 			value: []any{int64(1)},
 			exp:   1,
 		},
+		{
+			name:  "last_times_two",
+			node:  ast.NewBinary(ast.BinaryMul, ast.NewConst(ast.ConstLast), ast.NewInteger("2")),
+			size:  1<<62 + 1,
+			err:   `exec: jsonpath array subscript is out of integer range`,
+			errIs: ErrVerbose,
+		},
+		{
+			name:  "last_times_four",
+			node:  ast.NewBinary(ast.BinaryMul, ast.NewConst(ast.ConstLast), ast.NewInteger("4")),
+			size:  1<<62 + 1,
+			err:   `exec: jsonpath array subscript is out of integer range`,
+			errIs: ErrVerbose,
+		},
+		{
+			name:  "last_plus_max_int",
+			node:  ast.NewBinary(ast.BinaryAdd, ast.NewConst(ast.ConstLast), ast.NewInteger("9223372036854775807")),
+			size:  3,
+			err:   `exec: jsonpath array subscript is out of integer range`,
+			errIs: ErrVerbose,
+		},
+		{
+			name: "neg_last_minus_max_int",
+			node: ast.NewBinary(
+				ast.BinarySub,
+				ast.NewUnary(ast.UnaryMinus, ast.NewConst(ast.ConstLast)),
+				ast.NewInteger("9223372036854775807"),
+			),
+			size:  1<<62 + 1,
+			err:   `exec: jsonpath array subscript is out of integer range`,
+			errIs: ErrVerbose,
+		},
+		{
+			name: "last_times_two_in_range",
+			node: ast.NewBinary(ast.BinaryMul, ast.NewConst(ast.ConstLast), ast.NewInteger("2")),
+			size: 1<<29 + 1,
+			exp:  1 << 30,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			e := newTestExecutor(path, nil, true, false)
 			e.root = tc.value
+			if tc.size > 0 {
+				e.innermostArraySize = tc.size
+			}
 			integer, err := e.getArrayIndex(ctx, tc.node, tc.value)
 			a.Equal(tc.exp, integer)
 			if tc.errIs == nil {
@@ -417,3 +459,411 @@ func TestGetArrayIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestArrayAccessorModes(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	// Lax mode wraps non-array values in an array, while strict mode
+	// requires arrays. Nested values follow the same rules.
+	for _, tc := range []queryTestCase{
+		{
+			name: "lax_wildcard_scalar",
+			json: js(`1`),
+			path: "lax $[*]",
+			exp:  []any{float64(1)},
+		},
+		{
+			name: "lax_wildcard_string",
+			json: js(`"x"`),
+			path: "lax $[*]",
+			exp:  []any{"x"},
+		},
+		{
+			name: "lax_wildcard_null",
+			json: js(`null`),
+			path: "lax $[*]",
+			exp:  []any{nil},
+		},
+		{
+			name: "lax_wildcard_empty_object",
+			json: js(`{}`),
+			path: "lax $[*]",
+			exp:  []any{map[string]any{}},
+		},
+		{
+			name: "lax_wildcard_object",
+			json: js(`{"a": 1}`),
+			path: "lax $[*]",
+			exp:  []any{map[string]any{"a": float64(1)}},
+		},
+		{
+			name: "lax_wildcard_empty_array",
+			json: js(`[]`),
+			path: "lax $[*]",
+			exp:  []any{},
+		},
+		{
+			name: "strict_wildcard_scalar",
+			json: js(`1`),
+			path: "strict $[*]",
+			err:  "exec: jsonpath wildcard array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_wildcard_string",
+			json: js(`"x"`),
+			path: "strict $[*]",
+			err:  "exec: jsonpath wildcard array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_wildcard_null",
+			json: js(`null`),
+			path: "strict $[*]",
+			err:  "exec: jsonpath wildcard array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_wildcard_empty_object",
+			json: js(`{}`),
+			path: "strict $[*]",
+			err:  "exec: jsonpath wildcard array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_wildcard_object",
+			json: js(`{"a": 1}`),
+			path: "strict $[*]",
+			err:  "exec: jsonpath wildcard array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_wildcard_empty_array",
+			json: js(`[]`),
+			path: "strict $[*]",
+			exp:  []any{},
+		},
+		{
+			name: "lax_index_scalar",
+			json: js(`1`),
+			path: "lax $[0]",
+			exp:  []any{float64(1)},
+		},
+		{
+			name: "lax_index_string",
+			json: js(`"x"`),
+			path: "lax $[0]",
+			exp:  []any{"x"},
+		},
+		{
+			name: "lax_index_null",
+			json: js(`null`),
+			path: "lax $[0]",
+			exp:  []any{nil},
+		},
+		{
+			name: "lax_index_empty_object",
+			json: js(`{}`),
+			path: "lax $[0]",
+			exp:  []any{map[string]any{}},
+		},
+		{
+			name: "lax_index_object",
+			json: js(`{"a": 1}`),
+			path: "lax $[0]",
+			exp:  []any{map[string]any{"a": float64(1)}},
+		},
+		{
+			name: "lax_index_empty_array",
+			json: js(`[]`),
+			path: "lax $[0]",
+			exp:  []any{},
+		},
+		{
+			name: "strict_index_scalar",
+			json: js(`1`),
+			path: "strict $[0]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_index_string",
+			json: js(`"x"`),
+			path: "strict $[0]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_index_null",
+			json: js(`null`),
+			path: "strict $[0]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_index_empty_object",
+			json: js(`{}`),
+			path: "strict $[0]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_index_object",
+			json: js(`{"a": 1}`),
+			path: "strict $[0]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_index_empty_array",
+			json: js(`[]`),
+			path: "strict $[0]",
+			err:  "exec: jsonpath array subscript is out of bounds",
+		},
+		{
+			name: "lax_last_scalar",
+			json: js(`1`),
+			path: "lax $[last]",
+			exp:  []any{float64(1)},
+		},
+		{
+			name: "lax_last_string",
+			json: js(`"x"`),
+			path: "lax $[last]",
+			exp:  []any{"x"},
+		},
+		{
+			name: "lax_last_null",
+			json: js(`null`),
+			path: "lax $[last]",
+			exp:  []any{nil},
+		},
+		{
+			name: "lax_last_empty_object",
+			json: js(`{}`),
+			path: "lax $[last]",
+			exp:  []any{map[string]any{}},
+		},
+		{
+			name: "lax_last_object",
+			json: js(`{"a": 1}`),
+			path: "lax $[last]",
+			exp:  []any{map[string]any{"a": float64(1)}},
+		},
+		{
+			name: "lax_last_empty_array",
+			json: js(`[]`),
+			path: "lax $[last]",
+			exp:  []any{},
+		},
+		{
+			name: "strict_last_scalar",
+			json: js(`1`),
+			path: "strict $[last]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_last_string",
+			json: js(`"x"`),
+			path: "strict $[last]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_last_null",
+			json: js(`null`),
+			path: "strict $[last]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_last_empty_object",
+			json: js(`{}`),
+			path: "strict $[last]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_last_object",
+			json: js(`{"a": 1}`),
+			path: "strict $[last]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_last_empty_array",
+			json: js(`[]`),
+			path: "strict $[last]",
+			err:  "exec: jsonpath array subscript is out of bounds",
+		},
+		{
+			name: "lax_wildcard_nested_scalar",
+			json: js(`{"a": 1}`),
+			path: "lax $.a[*]",
+			exp:  []any{float64(1)},
+		},
+		{
+			name: "lax_wildcard_nested_null",
+			json: js(`{"a": null}`),
+			path: "lax $.a[*]",
+			exp:  []any{nil},
+		},
+		{
+			name: "lax_wildcard_nested_object",
+			json: js(`{"a": {}}`),
+			path: "lax $.a[*]",
+			exp:  []any{map[string]any{}},
+		},
+		{
+			name: "strict_wildcard_nested_scalar",
+			json: js(`{"a": 1}`),
+			path: "strict $.a[*]",
+			err:  "exec: jsonpath wildcard array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_wildcard_nested_null",
+			json: js(`{"a": null}`),
+			path: "strict $.a[*]",
+			err:  "exec: jsonpath wildcard array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_wildcard_nested_object",
+			json: js(`{"a": {}}`),
+			path: "strict $.a[*]",
+			err:  "exec: jsonpath wildcard array accessor can only be applied to an array",
+		},
+		{
+			name: "lax_wildcard_nested_null_element",
+			json: js(`{"a": [null]}`),
+			path: "lax $.a[*]",
+			exp:  []any{nil},
+		},
+		{
+			name: "strict_wildcard_nested_null_element",
+			json: js(`{"a": [null]}`),
+			path: "strict $.a[*]",
+			exp:  []any{nil},
+		},
+		{
+			name: "lax_index_nested_scalar",
+			json: js(`{"a": 1}`),
+			path: "lax $.a[0]",
+			exp:  []any{float64(1)},
+		},
+		{
+			name: "lax_index_nested_null",
+			json: js(`{"a": null}`),
+			path: "lax $.a[0]",
+			exp:  []any{nil},
+		},
+		{
+			name: "lax_index_nested_object",
+			json: js(`{"a": {}}`),
+			path: "lax $.a[0]",
+			exp:  []any{map[string]any{}},
+		},
+		{
+			name: "strict_index_nested_scalar",
+			json: js(`{"a": 1}`),
+			path: "strict $.a[0]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_index_nested_null",
+			json: js(`{"a": null}`),
+			path: "strict $.a[0]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_index_nested_object",
+			json: js(`{"a": {}}`),
+			path: "strict $.a[0]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "lax_index_nested_null_element",
+			json: js(`{"a": [null]}`),
+			path: "lax $.a[0]",
+			exp:  []any{nil},
+		},
+		{
+			name: "strict_index_nested_null_element",
+			json: js(`{"a": [null]}`),
+			path: "strict $.a[0]",
+			exp:  []any{nil},
+		},
+		{
+			name: "lax_last_nested_scalar",
+			json: js(`{"a": 1}`),
+			path: "lax $.a[last]",
+			exp:  []any{float64(1)},
+		},
+		{
+			name: "lax_last_nested_null",
+			json: js(`{"a": null}`),
+			path: "lax $.a[last]",
+			exp:  []any{nil},
+		},
+		{
+			name: "lax_last_nested_object",
+			json: js(`{"a": {}}`),
+			path: "lax $.a[last]",
+			exp:  []any{map[string]any{}},
+		},
+		{
+			name: "strict_last_nested_scalar",
+			json: js(`{"a": 1}`),
+			path: "strict $.a[last]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_last_nested_null",
+			json: js(`{"a": null}`),
+			path: "strict $.a[last]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "strict_last_nested_object",
+			json: js(`{"a": {}}`),
+			path: "strict $.a[last]",
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "lax_last_nested_null_element",
+			json: js(`{"a": [null]}`),
+			path: "lax $.a[last]",
+			exp:  []any{nil},
+		},
+		{
+			name: "strict_last_nested_null_element",
+			json: js(`{"a": [null]}`),
+			path: "strict $.a[last]",
+			exp:  []any{nil},
+		},
+		{
+			name: "strict_filter_scalar",
+			json: js(`[1, [2]]`),
+			path: "strict $[*] ? (@[*] == 2)",
+			exp:  []any{[]any{float64(2)}},
+		},
+		{
+			name: "lax_filter_scalar",
+			json: js(`[1, [2]]`),
+			path: "lax $[*] ? (@[*] == 2)",
+			exp:  []any{float64(2)},
+		},
+		{
+			name: "strict_filter_index_scalar",
+			json: js(`[1, [1]]`),
+			path: "strict $[*] ? (@[0] == 1)",
+			exp:  []any{[]any{float64(1)}},
+		},
+		{
+			name: "lax_filter_index_scalar",
+			json: js(`[1, [1]]`),
+			path: "lax $[*] ? (@[0] == 1)",
+			exp:  []any{float64(1), float64(1)},
+		},
+		{
+			name: "strict_index_scalar_silent",
+			json: js(`1`),
+			path: "strict $[0]",
+			opt:  []Option{WithSilent()},
+			exp:  []any{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.run(ctx, a, r)
+		})
+	}
+}
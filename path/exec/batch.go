@@ -0,0 +1,102 @@
+package exec
+
+import (
+	"context"
+	"maps"
+	"regexp"
+
+	"github.com/theory/sqljson/path/ast"
+)
+
+// BatchResult contains the result of executing a path against one of the
+// values passed to [QueryBatch].
+type BatchResult struct {
+	// Values contains the JSON items selected from the value, as returned
+	// by [Query].
+	Values []any
+	// Err contains the error returned by executing the path against the
+	// value, if any.
+	Err error
+}
+
+// WithFailFast causes [QueryBatch] to stop at the first value for which
+// execution returns an error, and to return that error, rather than
+// recording it in the value's [BatchResult] and proceeding to the next
+// value.
+func WithFailFast() Option { return func(e *Executor) { e.failFast = true } }
+
+// QueryBatch executes path against each of values like [Query], but sets up
+// execution once for all of them: it applies options, converts variables,
+// and compiles like_regex patterns only once. Returns a [BatchResult] for
+// each value, in order. Execution errors for a value appear in its
+// BatchResult and do not stop the batch, unless [WithFailFast] is
+// specified, in which case QueryBatch returns the results up to and
+// including that of the failing value, along with its error. Likewise, if
+// ctx is canceled or its deadline exceeded, QueryBatch stops and returns the
+// results so far and an error wrapping ctx.Err(). The options act the same
+// as for [Query].
+func QueryBatch(ctx context.Context, path *ast.AST, values []any, opt ...Option) ([]BatchResult, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.release()
+	if err := exec.checkPredicate("QueryBatch", false); err != nil {
+		return nil, err
+	}
+	if err := exec.convertVars(ctx); err != nil {
+		return nil, err
+	}
+
+	// Retain the original Go values of variables for every value.
+	varOrigins := exec.origins
+	results := make([]BatchResult, 0, len(values))
+	for _, value := range values {
+		if err := checkContext(ctx); err != nil {
+			return results, err
+		}
+
+		exec.reset(varOrigins)
+		vals, err := exec.queryAll(ctx, value)
+		results = append(results, BatchResult{Values: vals, Err: err})
+		if err != nil && (exec.failFast || ctx.Err() != nil) {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// reset restores the state exec accumulates while executing its path
+// against a value, so that it can execute the path against another value.
+// Converted variables and compiled regular expressions remain, and origins
+// replaces exec.origins.
+func (exec *Executor) reset(origins map[uintptr]origin) {
+	exec.root = nil
+	exec.current = nil
+	exec.baseObject = kvBaseObject{}
+	exec.lastGeneratedObjectID = 1
+	exec.kvOffsets = nil
+	exec.innermostArraySize = -1
+	exec.depth = 0
+	exec.location = exec.location[:0]
+	exec.origins = maps.Clone(origins)
+}
+
+// compileRegex returns rn compiled into a regexp.Regexp, compiling it only
+// once for exec.
+func (exec *Executor) compileRegex(rn *ast.RegexNode) (*regexp.Regexp, error) {
+	if re, ok := exec.regexes[rn]; ok {
+		return re, nil
+	}
+
+	re, err := rn.Compile()
+	if err != nil {
+		return nil, err
+	}
+	if exec.regexes == nil {
+		exec.regexes = map[*ast.RegexNode]*regexp.Regexp{}
+	}
+	exec.regexes[rn] = re
+	return re, nil
+}
@@ -0,0 +1,275 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestQueryBatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	missingErr := `exec: JSON object does not contain key "a"`
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		values []any
+		opt    []Option
+		exp    []BatchResult
+		err    string
+	}{
+		{
+			name:   "empty",
+			path:   "$.a",
+			values: []any{},
+			exp:    []BatchResult{},
+		},
+		{
+			name: "values",
+			path: "$.a",
+			values: []any{
+				map[string]any{"a": int64(1)},
+				map[string]any{"a": []any{"x", "y"}},
+				map[string]any{"b": int64(2)},
+			},
+			exp: []BatchResult{
+				{Values: []any{int64(1)}},
+				{Values: []any{[]any{"x", "y"}}},
+				{Values: []any{}},
+			},
+		},
+		{
+			name: "errors",
+			path: "strict $.a",
+			values: []any{
+				map[string]any{"a": int64(1)},
+				map[string]any{"b": int64(2)},
+				map[string]any{"a": int64(3)},
+			},
+			exp: []BatchResult{
+				{Values: []any{int64(1)}},
+				{Err: errors.New(missingErr)},
+				{Values: []any{int64(3)}},
+			},
+		},
+		{
+			name: "silent",
+			path: "strict $.a",
+			values: []any{
+				map[string]any{"a": int64(1)},
+				map[string]any{"b": int64(2)},
+			},
+			opt: []Option{WithSilent()},
+			exp: []BatchResult{
+				{Values: []any{int64(1)}},
+				{Values: []any{}},
+			},
+		},
+		{
+			name: "fail_fast",
+			path: "strict $.a",
+			values: []any{
+				map[string]any{"a": int64(1)},
+				map[string]any{"b": int64(2)},
+				map[string]any{"a": int64(3)},
+			},
+			opt: []Option{WithFailFast()},
+			exp: []BatchResult{
+				{Values: []any{int64(1)}},
+				{Err: errors.New(missingErr)},
+			},
+			err: missingErr,
+		},
+		{
+			name: "vars_and_regex",
+			path: `$[*] ? (@.n > $min && @.s like_regex "^a").n`,
+			values: []any{
+				[]any{map[string]any{"n": int64(1), "s": "ab"}, map[string]any{"n": int64(5), "s": "ac"}},
+				[]any{map[string]any{"n": int64(7), "s": "bc"}, map[string]any{"n": int64(9), "s": "a"}},
+			},
+			opt: []Option{WithVars(Vars{"min": int64(2)})},
+			exp: []BatchResult{
+				{Values: []any{int64(5)}},
+				{Values: []any{int64(9)}},
+			},
+		},
+		{
+			name:   "predicate_check",
+			path:   "$ == 1",
+			values: []any{int64(1), int64(2)},
+			opt:    []Option{WithPredicateCheck()},
+			err:    `exec: QueryBatch expects a SQL standard path expression but "($ == 1)" is a predicate check expression`,
+		},
+		{
+			name:   "vars_from_error",
+			path:   "$x",
+			values: []any{int64(1)},
+			opt:    []Option{WithVarsFrom([]int{1})},
+			err:    `exec: cannot use []int as variables: not an object`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := QueryBatch(ctx, path, tc.values, tc.opt...)
+			if tc.err == "" {
+				r.NoError(err)
+			} else {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+			}
+			r.Len(res, len(tc.exp))
+			for i, exp := range tc.exp {
+				a.Equal(exp.Values, res[i].Values, "values %v", i)
+				if exp.Err == nil {
+					r.NoError(res[i].Err, "error %v", i)
+				} else {
+					r.EqualError(res[i].Err, exp.Err.Error(), "error %v", i)
+					r.ErrorIs(res[i].Err, ErrExecution)
+				}
+			}
+
+			// Results should be the same as from Query.
+			if tc.err != "" {
+				return
+			}
+			for i, value := range tc.values {
+				vals, err := Query(ctx, path, value, tc.opt...)
+				a.Equal(vals, res[i].Values)
+				a.Equal(err, res[i].Err)
+			}
+		})
+	}
+}
+
+func TestQueryBatchGo(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	type item struct {
+		Name string `json:"name"`
+		Tags []int  `json:"tags"`
+	}
+	tags := []int{1, 2}
+	path, err := parser.Parse(`$[*] ? (@.tags[0] == $tags[0]).name`)
+	r.NoError(err)
+
+	// Variable and value origins should be retained and reset per value.
+	res, err := QueryBatch(ctx, path, []any{
+		[]item{{"a", []int{1}}, {"b", []int{2}}},
+		[]item{{"c", []int{3}}, {"d", []int{1, 3}}},
+	}, WithStructTags("json"), WithVarsFrom(map[string]any{"tags": tags}))
+	r.NoError(err)
+	a.Equal([]BatchResult{
+		{Values: []any{"a"}},
+		{Values: []any{"d"}},
+	}, res)
+
+	path, err = parser.Parse(`$tags`)
+	r.NoError(err)
+	res, err = QueryBatch(ctx, path, []any{int64(1), int64(2)}, WithVarsFrom(map[string]any{"tags": tags}))
+	r.NoError(err)
+	a.Equal([]BatchResult{
+		{Values: []any{tags}},
+		{Values: []any{tags}},
+	}, res)
+}
+
+func TestQueryBatchContext(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	path, err := parser.Parse("$.a")
+	r.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := QueryBatch(ctx, path, []any{map[string]any{"a": int64(1)}})
+	r.ErrorIs(err, context.Canceled)
+	r.NotErrorIs(err, ErrExecution)
+	a.Empty(res)
+}
+
+func TestCompileRegex(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	path, err := parser.Parse(`$ ? (@ like_regex "^a" flag "i")`)
+	r.NoError(err)
+	e, err := newExec(path)
+	r.NoError(err)
+
+	var rn *ast.RegexNode
+	ast.Inspect(path.Root(), func(node ast.Node) bool {
+		if node, ok := node.(*ast.RegexNode); ok {
+			rn = node
+		}
+		return true
+	})
+	r.NotNil(rn)
+
+	re, err := e.compileRegex(rn)
+	r.NoError(err)
+	a.True(re.MatchString("Abc"))
+	again, err := e.compileRegex(rn)
+	r.NoError(err)
+	a.Same(re, again)
+
+	// Forks compile their own.
+	a.Nil(e.fork(nil).regexes)
+
+	// Compile errors are not cached.
+	bad, err := ast.NewRegex(ast.NewConst(ast.ConstCurrent), `(a)\1`, "")
+	r.NoError(err)
+	_, err = e.compileRegex(bad)
+	r.Error(err)
+	a.NotContains(e.regexes, bad)
+}
+
+func BenchmarkQueryBatch(b *testing.B) {
+	const size = 100_000
+	values := make([]any, size)
+	for i := range values {
+		values[i] = map[string]any{"id": int64(i), "name": fmt.Sprintf("item %v", i)}
+	}
+
+	path, err := parser.Parse(`$ ? (@.id % 7 == 0 && @.name like_regex "9$").id`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+
+	b.Run("query", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			for _, value := range values {
+				if _, err := Query(ctx, path, value); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if _, err := QueryBatch(ctx, path, values, WithFailFast()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,474 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/parser"
+)
+
+// BenchmarkExec runs representative workloads against synthetic documents
+// generated by benchDoc, to measure the performance impact of changes to
+// the executor. Sub-benchmark names take the form
+//
+//	BenchmarkExec/doc=w10000d1/numbers=float64/workload=filter
+//
+// where doc names the width and depth passed to benchDoc, numbers the
+// representation of numbers, and workload the path executed. The key=value
+// elements allow benchstat to group and compare results by any of them.
+// To compare a change to a baseline, run `make bench` before and after the
+// change and save the output of each, or run, e.g.:
+//
+//	go test ./path/exec -run '^$' -bench '^BenchmarkExec$' -benchmem -count 10 > old.txt
+//	# apply the change
+//	go test ./path/exec -run '^$' -bench '^BenchmarkExec$' -benchmem -count 10 > new.txt
+//	benchstat old.txt new.txt
+//
+// Use patterns such as '^BenchmarkExec$/doc=w10000d1//workload=filter' to
+// run a subset, and -short to skip the documents with a million items.
+func BenchmarkExec(b *testing.B) {
+	ctx := context.Background()
+	workloads := benchWorkloads()
+
+	for _, shape := range benchShapes(workloads) {
+		b.Run("doc="+shape.String(), func(b *testing.B) {
+			if testing.Short() && shape.width > 100_000 {
+				b.Skip("skipping large document in short mode")
+			}
+			for _, numbers := range []benchNumbers{benchFloat64, benchJSONNumber} {
+				b.Run("numbers="+string(numbers), func(b *testing.B) {
+					doc := benchDoc(shape.width, shape.depth, numbers)
+					for _, wl := range workloads {
+						if !slices.Contains(wl.shapes, shape) {
+							continue
+						}
+						path, err := parser.Parse(wl.path)
+						if err != nil {
+							b.Fatal(err)
+						}
+						b.Run("workload="+wl.name, func(b *testing.B) {
+							b.ReportAllocs()
+							for range b.N {
+								if _, err := Query(ctx, path, doc); err != nil {
+									b.Fatal(err)
+								}
+							}
+						})
+					}
+				})
+			}
+		})
+	}
+}
+
+// benchShape describes the width and depth of a document generated by
+// benchDoc.
+type benchShape struct {
+	width, depth int
+}
+
+// String returns a name for s suitable for benchmark names, such as
+// "w10000d1".
+func (s benchShape) String() string {
+	return fmt.Sprintf("w%vd%v", s.width, s.depth)
+}
+
+// benchWorkload is a path executed by BenchmarkExec against documents of
+// the listed shapes.
+type benchWorkload struct {
+	name   string
+	path   string
+	shapes []benchShape
+}
+
+// benchWorkloads returns the workloads run by BenchmarkExec.
+func benchWorkloads() []benchWorkload {
+	items := []benchShape{{10_000, 1}, {1_000_000, 1}}
+	small := []benchShape{{10_000, 1}}
+	return []benchWorkload{
+		{"member", `$.name`, []benchShape{{10, 1}}},
+		{"chain", `$` + strings.Repeat(`.items[0]`, 20) + `.name`, []benchShape{{1, 20}}},
+		{"wildcard", `$.items[*].id`, items},
+		{"filter", `$.items[*] ? (@.score > 50 && @.name != "item 1")`, items},
+		{"like_regex", `$.items[*] ? (@.name like_regex "^item 9")`, items},
+		{"descent", `strict $.**.id`, []benchShape{{8, 5}}},
+		{"keyvalue", `$.items[*].keyvalue() ? (@.key == "score" && @.value > 90)`, small},
+		{"datetime", `$.items[*] ? (@.ts.datetime() >= "2024-01-02 00:00:00".datetime())`, small},
+	}
+}
+
+// benchShapes returns the distinct shapes of the documents the workloads
+// run against, in the order in which they appear.
+func benchShapes(workloads []benchWorkload) []benchShape {
+	shapes := []benchShape{}
+	for _, wl := range workloads {
+		for _, s := range wl.shapes {
+			if !slices.Contains(shapes, s) {
+				shapes = append(shapes, s)
+			}
+		}
+	}
+	return shapes
+}
+
+// benchNumbers determines how benchDoc represents numbers.
+type benchNumbers string
+
+const (
+	// benchFloat64 represents numbers as float64 values, as decoded by
+	// [json.Unmarshal].
+	benchFloat64 benchNumbers = "float64"
+
+	// benchJSONNumber represents numbers as [json.Number] values, as
+	// decoded by [json.Decoder.UseNumber].
+	benchJSONNumber benchNumbers = "json.Number"
+)
+
+// number returns f represented as n specifies.
+func (n benchNumbers) number(f float64) any {
+	if n == benchJSONNumber {
+		return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+	}
+	return f
+}
+
+// benchDoc generates a synthetic document for benchmarks: an object with
+// the members "id", "name", "score", and "ts" and, if depth is greater than
+// zero, "items", an array of width objects generated the same way one level
+// less deep. The ids number the objects in depth-first order, names are
+// "item " followed by the id, scores range from 0 to 99.9, and ts is a
+// timestamp string in 2024. numbers determines the representation of ids
+// and scores.
+func benchDoc(width, depth int, numbers benchNumbers) any {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := 0
+	var gen func(depth int) map[string]any
+	gen = func(depth int) map[string]any {
+		obj := map[string]any{
+			"id":    numbers.number(float64(id)),
+			"name":  "item " + strconv.Itoa(id),
+			"score": numbers.number(float64(id%1000) / 10),
+			"ts":    start.Add(time.Duration(id%(366*24)) * time.Hour).Format(time.DateTime),
+		}
+		id++
+		if depth > 0 {
+			items := make([]any, width)
+			for i := range items {
+				items[i] = gen(depth - 1)
+			}
+			obj["items"] = items
+		}
+		return obj
+	}
+	return gen(depth)
+}
+
+func TestBenchDoc(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		numbers benchNumbers
+		id      any
+		score   any
+	}{
+		{benchFloat64, float64(4), float64(0.4)},
+		{benchJSONNumber, json.Number("4"), json.Number("0.4")},
+	} {
+		doc := benchDoc(3, 2, tc.numbers)
+		path, err := parser.Parse(`strict $.**.id`)
+		r.NoError(err)
+		res, err := Query(ctx, path, doc)
+		r.NoError(err)
+		a.Len(res, 1+3+9, tc.numbers)
+
+		path, err = parser.Parse(`$.items[0].items[2]`)
+		r.NoError(err)
+		res, err = Query(ctx, path, doc)
+		r.NoError(err)
+		a.Equal([]any{map[string]any{
+			"id":    tc.id,
+			"name":  "item 4",
+			"score": tc.score,
+			"ts":    "2024-01-01 04:00:00",
+		}}, res, tc.numbers)
+	}
+}
+
+func TestBenchWorkloads(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// Make sure each workload selects items from smaller documents of its
+	// shapes, so that the benchmarks measure real work.
+	for _, wl := range benchWorkloads() {
+		for _, numbers := range []benchNumbers{benchFloat64, benchJSONNumber} {
+			t.Run(wl.name+"/"+string(numbers), func(t *testing.T) {
+				t.Parallel()
+				a := assert.New(t)
+				r := require.New(t)
+				path, err := parser.Parse(wl.path)
+				r.NoError(err)
+				for _, shape := range wl.shapes {
+					doc := benchDoc(min(shape.width, 1000), shape.depth, numbers)
+					res, err := Query(ctx, path, doc)
+					r.NoError(err)
+					a.NotEmpty(res, shape)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkAccessorChain executes strict-mode chains of member accessors
+// of increasing length, as generated by accessorChainPath, against
+// documents generated by accessorChainDoc, to check that execution time
+// grows linearly with the length of the chain. The "match" sub-benchmarks
+// select the innermost value, while the "error" sub-benchmarks append a
+// missing key, so that the error propagates through every step.
+func BenchmarkAccessorChain(b *testing.B) {
+	ctx := context.Background()
+	for _, steps := range []int{125, 250, 500, 1000} {
+		doc := accessorChainDoc(steps)
+		for _, tc := range []struct {
+			name string
+			path string
+		}{
+			{"match", accessorChainPath(steps)},
+			{"error", accessorChainPath(steps) + ".b"},
+		} {
+			path, err := parser.Parse(tc.path)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Run(fmt.Sprintf("steps=%v/result=%v", steps, tc.name), func(b *testing.B) {
+				b.ReportAllocs()
+				for range b.N {
+					_, _ = Query(ctx, path, doc)
+				}
+			})
+		}
+	}
+}
+
+// accessorChainPath returns a strict-mode path of steps member accessors
+// for the key "a".
+func accessorChainPath(steps int) string {
+	return "strict $" + strings.Repeat(".a", steps)
+}
+
+// accessorChainDoc returns an empty object nested in depth objects with the
+// key "a".
+func accessorChainDoc(depth int) any {
+	var doc any = map[string]any{}
+	for range depth {
+		doc = map[string]any{"a": doc}
+	}
+	return doc
+}
+
+// BenchmarkQueryLoad executes small queries typical of a service against
+// a small document from parallel goroutines, as a simple load test, to
+// measure the per-call overhead of execution, including allocations and
+// garbage collection. In addition to throughput, it reports the 50th and
+// 99th percentile latency of the calls, in nanoseconds.
+func BenchmarkQueryLoad(b *testing.B) {
+	ctx := context.Background()
+	doc := map[string]any{
+		"user": map[string]any{"id": int64(42), "name": "Ada", "roles": []any{"admin", "dev"}},
+		"items": []any{
+			map[string]any{"name": "pen", "price": 1.5, "tags": []any{"office"}},
+			map[string]any{"name": "lamp", "price": 24.0, "tags": []any{"home", "sale"}},
+			map[string]any{"name": "desk", "price": 180.0, "tags": []any{"office", "sale"}},
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		path string
+	}{
+		{"accessor", `$.user.name`},
+		{"wildcard", `$.items[*].name`},
+		{"filter", `$.items[*] ? (@.price > 10 && @.tags[*] == "sale").name`},
+		{"method", `$.user.roles.size()`},
+	} {
+		path, err := parser.Parse(tc.path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run("path="+tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var mu sync.Mutex
+			latencies := make([]time.Duration, 0, b.N)
+			b.RunParallel(func(pb *testing.PB) {
+				local := make([]time.Duration, 0, 1024)
+				for pb.Next() {
+					start := time.Now()
+					if _, err := Query(ctx, path, doc); err != nil {
+						b.Error(err)
+						return
+					}
+					local = append(local, time.Since(start))
+				}
+				mu.Lock()
+				latencies = append(latencies, local...)
+				mu.Unlock()
+			})
+			slices.Sort(latencies)
+			for _, p := range []int{50, 99} {
+				i := min(len(latencies)*p/100, len(latencies)-1)
+				b.ReportMetric(float64(latencies[i].Nanoseconds()), fmt.Sprintf("p%v-ns", p))
+			}
+		})
+	}
+}
+
+// BenchmarkIndexed runs benchIndexedPaths against a config-like document
+// of about 5MB generated by benchConfig, with and without [IndexDocument],
+// to measure the benefit of indexing a document queried many times. Each
+// iteration runs all the paths. The "index" sub-benchmark measures the cost
+// of indexing the document.
+func BenchmarkIndexed(b *testing.B) {
+	ctx := context.Background()
+	doc := benchConfig(9, 5)
+	paths := make([]*ast.AST, 0, 500)
+	for _, src := range benchIndexedPaths(9, 5) {
+		path, err := parser.Parse(src)
+		if err != nil {
+			b.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	b.Run("index", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			IndexDocument(doc)
+		}
+	})
+
+	for _, tc := range []struct {
+		name  string
+		value any
+	}{
+		{"doc", doc},
+		{"indexed", IndexDocument(doc)},
+	} {
+		b.Run("value="+tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				for _, path := range paths {
+					if _, err := Query(ctx, path, tc.value); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+// benchConfig generates a config-like document for BenchmarkIndexed: an
+// object with width members named "s0", "s1", etc., each an object
+// generated the same way one level less deep, and, at depth zero, a setting
+// object with the members "name", "value", "enabled", and "tags". Setting
+// names are the paths to them joined by dots, values number the settings in
+// depth-first order, and each setting has two tags.
+func benchConfig(width, depth int) any {
+	id := 0
+	var gen func(prefix string, depth int) map[string]any
+	gen = func(prefix string, depth int) map[string]any {
+		if depth == 0 {
+			id++
+			return map[string]any{
+				"name":    prefix,
+				"value":   float64(id),
+				"enabled": id%2 == 0,
+				"tags":    []any{"t" + strconv.Itoa(id%10), "t" + strconv.Itoa(id%7)},
+			}
+		}
+		obj := make(map[string]any, width)
+		for i := range width {
+			key := "s" + strconv.Itoa(i)
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			obj["s"+strconv.Itoa(i)] = gen(key, depth-1)
+		}
+		return obj
+	}
+	return gen("", depth)
+}
+
+// benchIndexedPaths returns 500 distinct paths for BenchmarkIndexed to run
+// against a document generated by benchConfig with the same width and
+// depth: chains of member accessors to setting members, and searches for
+// setting values with .**, starting from the root or a section.
+func benchIndexedPaths(width, depth int) []string {
+	const count = 500
+	paths := make([]string, count)
+	member := []string{"name", "value", "enabled", "tags"}
+	for i := range count {
+		// Pick sections from the digits of i in base width.
+		keys := make([]string, depth)
+		for d, n := 0, i; d < depth; d, n = d+1, n/width {
+			keys[d] = "s" + strconv.Itoa(n%width)
+		}
+		// Make searches distinct by excluding value i.
+		filter := ".value ? (@ != " + strconv.Itoa(i) + ")"
+
+		switch i % 4 {
+		case 0, 1:
+			paths[i] = "$." + strings.Join(keys, ".") + "." + member[i%len(member)]
+		case 2:
+			paths[i] = "$." + strings.Join(keys[:2], ".") + ".**" + filter
+		default:
+			paths[i] = "strict $.**." + keys[0] + "." + keys[1] + filter
+		}
+	}
+	return paths
+}
+
+func TestBenchIndexed(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	// Make sure the paths are distinct, select items, and select the same
+	// items with and without the index.
+	doc := benchConfig(4, 5)
+	idx := IndexDocument(doc)
+	r.NotNil(idx.items)
+	paths := benchIndexedPaths(4, 5)
+	seen := map[string]bool{}
+	for _, src := range paths {
+		a.False(seen[src], src)
+		seen[src] = true
+		path, err := parser.Parse(src)
+		r.NoError(err)
+		exp, err := Query(ctx, path, doc, WithOrderedKeys())
+		r.NoError(err)
+		a.NotEmpty(exp, src)
+		res, err := Query(ctx, path, idx, WithOrderedKeys())
+		r.NoError(err)
+		a.Equal(exp, res, src)
+	}
+	a.Len(seen, 500)
+
+	// The benchmark document is about 5MB.
+	src, err := json.Marshal(benchConfig(9, 5))
+	r.NoError(err)
+	a.InDelta(5_000_000, len(src), 1_000_000)
+}
@@ -40,7 +40,7 @@ func (exec *Executor) executeBinaryBoolItem(
 		ast.BinaryGreater, ast.BinaryLessOrEqual, ast.BinaryGreaterOrEqual:
 		return exec.executePredicate(ctx, node, node.Left(), node.Right(), value, true, exec.compareItems)
 	case ast.BinaryStartsWith:
-		return exec.executePredicate(ctx, node, node.Left(), node.Right(), value, false, executeStartsWith)
+		return exec.executePredicate(ctx, node, node.Left(), node.Right(), value, false, exec.executeStartsWith)
 	default:
 		return predUnknown, fmt.Errorf(
 			"%w: invalid jsonpath boolean operator %v",
@@ -74,7 +74,7 @@ func (exec *Executor) executeUnaryBoolItem(
 		if exec.strictAbsenceOfErrors() {
 			// In strict mode we must get a complete list of values to
 			// check that there are no errors at all.
-			vals := newList()
+			vals := newCounter()
 			res, err := exec.executeItemOptUnwrapResultSilent(ctx, node.Operand(), value, false, vals)
 			if res == statusFailed {
 				return predUnknown, err
@@ -85,6 +85,8 @@ func (exec *Executor) executeUnaryBoolItem(
 			return predTrue, nil
 		}
 
+		// Otherwise collect nothing, so that execution stops at the first
+		// item found.
 		res, err := exec.executeItemOptUnwrapResultSilent(ctx, node.Operand(), value, false, nil)
 		if res == statusFailed {
 			return predUnknown, err
@@ -117,6 +119,23 @@ func (exec *Executor) executeBoolItem(
 		)
 	}
 
+	// Check for interrupts.
+	if err := checkContext(ctx); err != nil {
+		return predUnknown, err
+	}
+
+	if exec.trace != nil {
+		step := exec.traceStart(node, value)
+		res, err := exec.executeBoolNode(ctx, node, value)
+		exec.traceEnd(step, res)
+		return res, err
+	}
+
+	return exec.executeBoolNode(ctx, node, value)
+}
+
+// executeBoolNode dispatches node to the function that executes its type.
+func (exec *Executor) executeBoolNode(ctx context.Context, node ast.Node, value any) (predOutcome, error) {
 	switch node := node.(type) {
 	case *ast.BinaryNode:
 		return exec.executeBinaryBoolItem(ctx, node, value)
@@ -171,5 +190,6 @@ func (exec *Executor) executeNestedBoolItem(
 	prev := exec.current
 	defer func(e *Executor, c any) { e.current = c }(exec, prev)
 	exec.current = value
+	defer exec.endMemo(exec.startMemo())
 	return exec.executeBoolItem(ctx, node, value, false)
 }
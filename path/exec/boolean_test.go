@@ -441,6 +441,108 @@ func TestExecuteUnaryBoolItem(t *testing.T) {
 	}
 }
 
+// countingContext counts calls to Err, which execution calls to check for
+// interrupts before evaluating each item.
+type countingContext struct {
+	context.Context
+	calls int
+}
+
+func (c *countingContext) Err() error {
+	c.calls++
+	return c.Context.Err()
+}
+
+func TestExistsEarlyTermination(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	const size = 1000
+	array := make([]any, size)
+	for i := range array {
+		array[i] = map[string]any{"a": int64(i)}
+	}
+	// An element without "a" makes strict mode accessors fail.
+	withMissing := append(array[:size:size], map[string]any{"b": int64(0)})
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value []any
+		exp   predOutcome
+		early bool
+	}{
+		{
+			name:  "lax_first",
+			path:  "exists ($[*] ? (@.a == 0))",
+			value: array,
+			exp:   predTrue,
+			early: true,
+		},
+		{
+			name:  "lax_last",
+			path:  "exists ($[*] ? (@.a == 999))",
+			value: array,
+			exp:   predTrue,
+		},
+		{
+			name:  "lax_none",
+			path:  "exists ($[*] ? (@.a < 0))",
+			value: array,
+			exp:   predFalse,
+		},
+		{
+			name:  "lax_accessor",
+			path:  "exists ($[*].a)",
+			value: withMissing,
+			exp:   predTrue,
+			early: true,
+		},
+		{
+			name:  "lax_any",
+			path:  "exists ($.**.a)",
+			value: array,
+			exp:   predTrue,
+			early: true,
+		},
+		{
+			// Strict mode must evaluate every item to detect errors.
+			name:  "strict_first",
+			path:  "strict exists ($[*] ? (@.a == 0))",
+			value: array,
+			exp:   predTrue,
+		},
+		{
+			name:  "strict_error",
+			path:  "strict exists ($[*].a)",
+			value: withMissing,
+			exp:   predUnknown,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			node, ok := path.Root().(*ast.UnaryNode)
+			r.True(ok)
+			r.Equal(ast.UnaryExists, node.Operator())
+
+			e := newTestExecutor(path, nil, true, false)
+			e.root = tc.value
+			ctx := &countingContext{Context: context.Background()}
+			res, err := e.executeUnaryBoolItem(ctx, node, tc.value)
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+			if tc.early {
+				a.Less(ctx.calls, 20)
+			} else {
+				a.Greater(ctx.calls, size)
+			}
+		})
+	}
+}
+
 func TestExecuteBoolItem(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
@@ -683,3 +785,469 @@ func TestExecuteNestedBoolItem(t *testing.T) {
 		})
 	}
 }
+
+func TestStrictUnknownLogic(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	json := js(`[{"a": 1}, {"a": 5}, {"b": 2}]`)
+
+	// Errors raised by structurally invalid strict mode operands become
+	// unknown, which propagates through derived expressions following the
+	// same three-valued logic as top-level filters.
+	for _, tc := range []queryTestCase{
+		{
+			name: "nested_comparison_type",
+			json: json,
+			path: "strict ($[*].a > 3).type()",
+			exp:  []any{"null"},
+		},
+		{
+			name: "nested_comparison",
+			json: json,
+			path: "strict ($[*].a > 3)",
+			exp:  []any{nil},
+		},
+		{
+			name: "not_nested_comparison",
+			json: json,
+			path: "strict !($[*].a > 3)",
+			exp:  []any{nil},
+		},
+		{
+			name: "nested_comparison_is_unknown",
+			json: json,
+			path: "strict ($[*].a > 3) is unknown",
+			exp:  []any{true},
+		},
+		{
+			name: "not_nested_comparison_is_unknown",
+			json: json,
+			path: "strict (!($[*].a > 3)) is unknown",
+			exp:  []any{true},
+		},
+		{
+			name: "not_missing_is_unknown",
+			json: json,
+			path: "strict (!($.missing > 1)) is unknown",
+			exp:  []any{true},
+		},
+		{
+			name: "not_missing_type",
+			json: json,
+			path: "strict (!($.missing > 1)).type()",
+			exp:  []any{"null"},
+		},
+		{
+			name: "is_unknown_type",
+			json: json,
+			path: "strict ((!($.missing > 1)) is unknown).type()",
+			exp:  []any{"boolean"},
+		},
+		{
+			name: "not_is_unknown",
+			json: json,
+			path: "strict !(($.missing > 1) is unknown)",
+			exp:  []any{false},
+		},
+		{
+			name: "known_is_unknown",
+			json: json,
+			path: "strict ((1 == 1) is unknown)",
+			exp:  []any{false},
+		},
+		{
+			name: "unknown_and_true",
+			json: json,
+			path: "strict ($.missing > 1) && (1 == 1)",
+			exp:  []any{nil},
+		},
+		{
+			name: "unknown_and_false",
+			json: json,
+			path: "strict ($.missing > 1) && (1 == 2)",
+			exp:  []any{false},
+		},
+		{
+			name: "true_and_unknown",
+			json: json,
+			path: "strict (1 == 1) && ($.missing > 1)",
+			exp:  []any{nil},
+		},
+		{
+			name: "false_and_unknown",
+			json: json,
+			path: "strict (1 == 2) && ($.missing > 1)",
+			exp:  []any{false},
+		},
+		{
+			name: "unknown_and_unknown",
+			json: json,
+			path: "strict ($.missing > 1) && ($[*].a > 3)",
+			exp:  []any{nil},
+		},
+		{
+			name: "unknown_or_true",
+			json: json,
+			path: "strict ($.missing > 1) || (1 == 1)",
+			exp:  []any{true},
+		},
+		{
+			name: "unknown_or_false",
+			json: json,
+			path: "strict ($.missing > 1) || (1 == 2)",
+			exp:  []any{nil},
+		},
+		{
+			name: "true_or_unknown",
+			json: json,
+			path: "strict (1 == 1) || ($.missing > 1)",
+			exp:  []any{true},
+		},
+		{
+			name: "false_or_unknown",
+			json: json,
+			path: "strict (1 == 2) || ($.missing > 1)",
+			exp:  []any{nil},
+		},
+		{
+			name: "unknown_or_unknown",
+			json: json,
+			path: "strict ($.missing > 1) || ($[*].a > 3)",
+			exp:  []any{nil},
+		},
+		{
+			name: "and_is_unknown",
+			json: json,
+			path: "strict ((1 == 1) && ($.missing > 1)) is unknown",
+			exp:  []any{true},
+		},
+		{
+			name: "false_and_is_unknown",
+			json: json,
+			path: "strict ((1 == 2) && ($.missing > 1)) is unknown",
+			exp:  []any{false},
+		},
+		{
+			name: "or_is_unknown",
+			json: json,
+			path: "strict (($.missing > 1) || (1 == 2)) is unknown",
+			exp:  []any{true},
+		},
+		{
+			name: "true_or_is_unknown",
+			json: json,
+			path: "strict ((1 == 1) || ($.missing > 1)) is unknown",
+			exp:  []any{false},
+		},
+		{
+			name: "not_and_is_unknown",
+			json: json,
+			path: "strict (!(($.missing > 1) && (1 == 1))) is unknown",
+			exp:  []any{true},
+		},
+		{
+			name: "not_or",
+			json: json,
+			path: "strict !(($[*].a > 3) || ($.missing > 1))",
+			exp:  []any{nil},
+		},
+		{
+			name: "or_type",
+			json: json,
+			path: "strict (($.missing > 1) || (1 == 2)).type()",
+			exp:  []any{"null"},
+		},
+		{
+			name: "exists_is_unknown",
+			json: json,
+			path: "strict (exists($[*].a)) is unknown",
+			exp:  []any{true},
+		},
+		{
+			name: "not_exists_is_unknown",
+			json: json,
+			path: "strict (!(exists($.missing))) is unknown",
+			exp:  []any{true},
+		},
+		{
+			name: "like_regex_is_unknown",
+			json: json,
+			path: `strict (!($.missing like_regex "x")) is unknown`,
+			exp:  []any{true},
+		},
+		{
+			name: "starts_with_is_unknown",
+			json: json,
+			path: `strict (!($.missing starts with "x")) is unknown`,
+			exp:  []any{true},
+		},
+		{
+			name: "lax_not_missing_is_unknown",
+			json: json,
+			path: "lax (!($.missing > 1)) is unknown",
+			exp:  []any{false},
+		},
+		{
+			name: "lax_not_exists_is_unknown",
+			json: json,
+			path: "lax (!(exists($.missing))) is unknown",
+			exp:  []any{false},
+		},
+		{
+			name: "filter_comparison",
+			json: json,
+			path: "strict $[*] ? (@.a > 3)",
+			exp:  []any{jv(`{"a": 5}`)},
+		},
+		{
+			name: "filter_not",
+			json: json,
+			path: "strict $[*] ? (!(@.a > 3))",
+			exp:  []any{jv(`{"a": 1}`)},
+		},
+		{
+			name: "filter_is_unknown",
+			json: json,
+			path: "strict $[*] ? ((@.a > 3) is unknown)",
+			exp:  []any{jv(`{"b": 2}`)},
+		},
+		{
+			name: "filter_not_is_unknown",
+			json: json,
+			path: "strict $[*] ? ((!(@.a > 3)) is unknown)",
+			exp:  []any{jv(`{"b": 2}`)},
+		},
+		{
+			name: "filter_or",
+			json: json,
+			path: "strict $[*] ? ((@.a > 3) || (@.b == 2))",
+			exp:  []any{jv(`{"a": 5}`), jv(`{"b": 2}`)},
+		},
+		{
+			name: "filter_not_and",
+			json: json,
+			path: "strict $[*] ? (!((@.a > 3) && (@.b == 2)))",
+			exp:  []any{jv(`{"a": 1}`)},
+		},
+		{
+			name: "filter_not_and_is_unknown",
+			json: json,
+			path: "strict $[*] ? ((!((@.a > 3) && (1 == 1))) is unknown)",
+			exp:  []any{jv(`{"b": 2}`)},
+		},
+		{
+			name: "filter_or_is_unknown",
+			json: json,
+			path: "strict $[*] ? (((@.a > 3) || (1 == 2)) is unknown)",
+			exp:  []any{jv(`{"b": 2}`)},
+		},
+		{
+			name: "filter_nested_not",
+			json: json,
+			path: "strict $ ? (!(@[*].a > 3))",
+			exp:  []any{},
+		},
+		{
+			name: "filter_nested_exists",
+			json: json,
+			path: "strict $ ? (exists(@[*].a))",
+			exp:  []any{},
+		},
+		{
+			name: "filter_nested_is_unknown",
+			json: json,
+			path: "strict $ ? ((@[*].a > 3) is unknown)",
+			exp:  []any{json},
+		},
+		{
+			name: "filter_nested_not_is_unknown",
+			json: json,
+			path: "strict $ ? ((!(@.missing > 1)) is unknown)",
+			exp:  []any{json},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.run(ctx, a, r)
+		})
+	}
+}
+
+func TestNegation(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	json := js(`["abc", "xyz", 1, {"a": 1}, null]`)
+
+	// Negation flips true and false and leaves unknown unknown, so filters
+	// exclude items for which the negated predicate is unknown.
+	for _, tc := range []queryTestCase{
+		{
+			name: "not_exists",
+			json: json,
+			path: "$[*] ? (!exists(@.a))",
+			exp:  []any{"abc", "xyz", float64(1), nil},
+		},
+		{
+			name: "strict_not_exists",
+			json: json,
+			path: "strict $[*] ? (!exists(@.a))",
+			exp:  []any{},
+		},
+		{
+			name: "strict_not_exists_is_unknown",
+			json: json,
+			path: "strict $[*] ? ((!exists(@.a)) is unknown)",
+			exp:  []any{"abc", "xyz", float64(1), nil},
+		},
+		{
+			name: "not_starts_with",
+			json: json,
+			path: `$[*] ? (!(@ starts with "a"))`,
+			exp:  []any{"xyz"},
+		},
+		{
+			name: "not_not_starts_with",
+			json: json,
+			path: `$[*] ? (!(!(@ starts with "a")))`,
+			exp:  []any{"abc"},
+		},
+		{
+			name: "not_starts_with_is_unknown",
+			json: json,
+			path: `$[*] ? ((!(@ starts with "a")) is unknown)`,
+			exp:  []any{float64(1), map[string]any{"a": float64(1)}, nil},
+		},
+		{
+			name: "not_like_regex",
+			json: json,
+			path: `$[*] ? (!(@ like_regex "^x"))`,
+			exp:  []any{"abc"},
+		},
+		{
+			name: "not_not_like_regex",
+			json: json,
+			path: `$[*] ? (!(!(@ like_regex "^x")))`,
+			exp:  []any{"xyz"},
+		},
+		{
+			name: "not_like_regex_is_unknown",
+			json: json,
+			path: `$[*] ? ((!(@ like_regex "^x")) is unknown)`,
+			exp:  []any{float64(1), map[string]any{"a": float64(1)}, nil},
+		},
+		{
+			name: "not_comparison",
+			json: json,
+			path: `$[*] ? (!(@ == 1))`,
+			exp:  []any{nil},
+		},
+		{
+			name: "not_not_exists_predicate",
+			json: json,
+			path: `!(!exists($[*] ? (@ == 1)))`,
+			exp:  []any{true},
+		},
+		{
+			name: "not_unknown_predicate",
+			json: json,
+			path: `!($[2] like_regex "a")`,
+			exp:  []any{nil},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.run(ctx, a, r)
+		})
+	}
+}
+
+func TestNestedExists(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	json := js(`{"a": [
+		{"b": {"c": [{"d": 1}, {"e": 2}]}},
+		{"b": {"c": [{"e": 3}]}},
+		{"b": [{"c": {"d": 4}}, {"c": 5}]},
+		{"b": 6}
+	]}`)
+	elems, _ := json.(map[string]any)["a"].([]any)
+	bs := []any{jv(`{"c": [{"d": 1}, {"e": 2}]}`), jv(`{"c": [{"e": 3}]}`)}
+
+	// Paths in exists nested within filters unwrap arrays in lax mode at
+	// every depth, as in PostgreSQL, and their errors in strict mode make
+	// exists unknown only at the depth that raises them: a filter whose
+	// predicate is unknown selects nothing, so an enclosing exists is false.
+	for _, tc := range []queryTestCase{
+		{
+			name: "lax_two_levels",
+			json: json,
+			path: `lax $.a[*].b ? (exists(@.c[*].d))`,
+			exp:  []any{bs[0], jv(`{"c": {"d": 4}}`)},
+		},
+		{
+			name: "lax_two_levels_is_unknown",
+			json: json,
+			path: `lax $.a[*].b ? ((exists(@.c[*].d)) is unknown)`,
+			exp:  []any{},
+		},
+		{
+			name: "strict_two_levels",
+			json: json,
+			path: `strict $.a[*].b ? (exists(@.c[*].d))`,
+			exp:  []any{},
+		},
+		{
+			name: "strict_two_levels_is_unknown",
+			json: json,
+			path: `strict $.a[*].b ? ((exists(@.c[*].d)) is unknown)`,
+			exp:  []any{bs[0], bs[1], elems[2].(map[string]any)["b"], float64(6)},
+		},
+		{
+			name: "lax_three_levels",
+			json: json,
+			path: `lax $.a ? (exists(@.b ? (exists(@.c[*].d))))`,
+			exp:  []any{elems[0], elems[2]},
+		},
+		{
+			name: "lax_three_levels_is_unknown",
+			json: json,
+			path: `lax $.a ? ((exists(@.b ? (exists(@.c[*].d)))) is unknown)`,
+			exp:  []any{},
+		},
+		{
+			name: "lax_three_levels_unwrap_inner",
+			json: json,
+			path: `lax $.a[*] ? (exists(@.b.c ? (exists(@[*].d))))`,
+			exp:  []any{elems[0], elems[2]},
+		},
+		{
+			name: "strict_three_levels",
+			json: json,
+			path: `strict $.a[*] ? (exists(@.b.c ? (exists(@[*].d))))`,
+			exp:  []any{},
+		},
+		{
+			name: "strict_three_levels_is_unknown",
+			json: json,
+			path: `strict $.a[*] ? ((exists(@.b.c ? (exists(@[*].d)))) is unknown)`,
+			exp:  []any{elems[2], elems[3]},
+		},
+		{
+			name: "strict_three_levels_inner_is_unknown",
+			json: json,
+			path: `strict $.a[*] ? (exists(@.b.c ? ((exists(@[*].d)) is unknown)))`,
+			exp:  []any{elems[0], elems[1]},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.run(ctx, a, r)
+		})
+	}
+}
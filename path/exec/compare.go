@@ -3,7 +3,10 @@ package exec
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/theory/sqljson/path/ast"
@@ -13,7 +16,6 @@ import (
 // compareItems compares two SQL/JSON items using comparison operation 'op'.
 // Implements predicateCallback.
 func (exec *Executor) compareItems(ctx context.Context, node ast.Node, left, right any) (predOutcome, error) {
-	var cmp int
 	bin, ok := node.(*ast.BinaryNode)
 	if !ok {
 		return predUnknown, fmt.Errorf(
@@ -28,46 +30,145 @@ func (exec *Executor) compareItems(ctx context.Context, node ast.Node, left, rig
 		return predFrom(op == ast.BinaryNotEqual), nil
 	}
 
+	if exec.deepEqual && (op == ast.BinaryEqual || op == ast.BinaryNotEqual) {
+		switch left.(type) {
+		case map[string]any, []any:
+			return exec.compareContainers(ctx, op, left, right)
+		}
+	}
+
+	cmp, ok, err := exec.compareScalars(ctx, left, right)
+	if err != nil {
+		_, err = exec.returnError(err)
+		return predUnknown, err
+	}
+	if !ok {
+		return predUnknown, nil
+	}
+
+	return applyCompare(op, cmp)
+}
+
+// compareScalars compares two SQL/JSON scalar items and returns 0, 1, or -1.
+// Returns false if they are not comparable, either because they are of
+// different types or because either is an object or array.
+func (exec *Executor) compareScalars(ctx context.Context, left, right any) (int, bool, error) {
 	switch left := left.(type) {
 	case nil:
-		cmp = 0
+		return 0, right == nil, nil
 	case bool:
-		cmp, ok = compareBool(left, right)
-		if !ok {
-			return predUnknown, nil
-		}
+		cmp, ok := compareBool(left, right)
+		return cmp, ok, nil
 	case int64, float64, json.Number:
 		switch right.(type) {
 		case int64, float64, json.Number:
-			cmp = compareNumeric(left, right)
+			return compareNumeric(left, right), true, nil
 		default:
-			return predUnknown, nil
+			return 0, false, nil
 		}
 	case string:
 		right, ok := right.(string)
 		if !ok {
-			return predUnknown, nil
+			return 0, false, nil
 		}
-		cmp = strings.Compare(left, right)
-		if op == ast.BinaryEqual {
-			return predFrom(cmp == 0), nil
+		left, err := exec.validUTF8(left)
+		if err != nil {
+			return 0, false, err
 		}
+		right, err = exec.validUTF8(right)
+		if err != nil {
+			return 0, false, err
+		}
+		return strings.Compare(left, right), true, nil
 	case *types.Date, *types.Time, *types.TimeTZ, *types.Timestamp, *types.TimestampTZ:
-		var err error
-		cmp, err = compareDatetime(ctx, left, right, exec.useTZ)
+		cmp, err := compareDatetime(ctx, left, right, exec.useTZ)
 		if cmp < -1 || err != nil {
-			return predUnknown, err
+			return 0, false, err
 		}
+		return cmp, true, nil
 	case map[string]any, []any:
 		// non-scalars are not comparable
-		return predUnknown, nil
+		return 0, false, nil
 	default:
-		return predUnknown, fmt.Errorf(
+		return 0, false, fmt.Errorf(
 			"%w: invalid json value type %T", ErrInvalid, left,
 		)
 	}
+}
 
-	return applyCompare(op, cmp)
+// compareContainers implements [WithDeepEqual] by comparing left, an object
+// or array, to right with op, which must be ast.BinaryEqual or
+// ast.BinaryNotEqual. Returns predUnknown if right is not also an object or
+// array, respectively.
+func (exec *Executor) compareContainers(
+	ctx context.Context,
+	op ast.BinaryOperator,
+	left, right any,
+) (predOutcome, error) {
+	switch left.(type) {
+	case map[string]any:
+		if _, ok := right.(map[string]any); !ok {
+			return predUnknown, nil
+		}
+	case []any:
+		if _, ok := right.([]any); !ok {
+			return predUnknown, nil
+		}
+	}
+
+	eq, err := exec.deepEqualItems(ctx, left, right)
+	if err != nil {
+		return predUnknown, err
+	}
+	return predFrom(eq == (op == ast.BinaryEqual)), nil
+}
+
+// deepEqualItems returns true if left and right are structurally equal:
+// objects with the same keys and equal values, in any order, arrays with
+// equal elements in the same order, or scalars for which compareScalars
+// returns 0. Items of different types, including scalars that
+// compareScalars cannot compare, are not equal. Converts Go values nested
+// in objects and arrays as necessary.
+func (exec *Executor) deepEqualItems(ctx context.Context, left, right any) (bool, error) {
+	var err error
+	if left, err = exec.normalize(left); err != nil {
+		return false, err
+	}
+	if right, err = exec.normalize(right); err != nil {
+		return false, err
+	}
+
+	switch left := left.(type) {
+	case map[string]any:
+		right, ok := right.(map[string]any)
+		if !ok || len(left) != len(right) {
+			return false, nil
+		}
+		for key, lVal := range left {
+			rVal, ok := right[key]
+			if !ok {
+				return false, nil
+			}
+			if eq, err := exec.deepEqualItems(ctx, lVal, rVal); !eq || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	case []any:
+		right, ok := right.([]any)
+		if !ok || len(left) != len(right) {
+			return false, nil
+		}
+		for i, lVal := range left {
+			if eq, err := exec.deepEqualItems(ctx, lVal, right[i]); !eq || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	cmp, ok, err := exec.compareScalars(ctx, left, right)
+	return ok && cmp == 0, err
 }
 
 // compareBool compares two boolean values and returns 0, 1, or -1. Returns
@@ -121,8 +222,11 @@ func compareNumbers[T int | int64 | float64](left, right T) int {
 	return 0
 }
 
-// compareBool compares two numeric values and returns 0, 1, or -1. The left
-// and right params must be int64, float64, or json.Number values.
+// compareNumeric compares two numeric values and returns 0, 1, or -1. The
+// left and right params must be int64, float64, or json.Number values. The
+// comparison is exact except between a float64 and a non-integral
+// json.Number, which compares the json.Number as a float64. Panics if a
+// json.Number is not a valid number.
 func compareNumeric(left, right any) int {
 	switch left := left.(type) {
 	case int64:
@@ -130,44 +234,310 @@ func compareNumeric(left, right any) int {
 		case int64:
 			return compareNumbers(left, right)
 		case float64:
-			return compareNumbers(float64(left), right)
+			return compareIntFloat(left, right)
 		case json.Number:
-			if right, err := right.Int64(); err == nil {
-				return compareNumbers(left, right)
-			}
-			if right, err := right.Float64(); err == nil {
-				return compareNumbers(float64(left), right)
-			} else {
-				// This should not happen.
-				panic(err)
-			}
+			return -compareJSONNumber(right, left)
 		}
 	case float64:
 		switch right := right.(type) {
 		case float64:
 			return compareNumbers(left, right)
 		case int64:
-			return compareNumbers(left, float64(right))
+			return -compareIntFloat(right, left)
 		case json.Number:
-			if right, err := right.Float64(); err == nil {
-				return compareNumbers(left, right)
-			} else {
-				// This should not happen.
-				panic(err)
-			}
+			return -compareJSONNumber(right, left)
 		}
 	case json.Number:
-		if left, err := left.Int64(); err == nil {
-			return compareNumeric(left, right)
-		}
-		if left, err := left.Float64(); err == nil {
-			return compareNumeric(left, right)
-		} else {
-			// This should not happen.
-			panic(err)
+		switch right.(type) {
+		case int64, float64, json.Number:
+			return compareJSONNumber(left, right)
 		}
 	}
 
 	// This should not happen
 	panic(fmt.Sprintf("Value not numeric: %q", left))
 }
+
+// compareIntFloat compares i to f without converting i to a float64, which
+// would lose precision for integers greater than 2^53, and returns 0, 1, or
+// -1.
+func compareIntFloat(i int64, f float64) int {
+	const twoTo63 = float64(1 << 63)
+	switch {
+	case math.IsNaN(f):
+		return compareNumbers(float64(i), f)
+	case f >= twoTo63:
+		return -1
+	case f < -twoTo63:
+		return 1
+	}
+
+	// f is within int64 range, so compare the integer parts, then the
+	// fraction.
+	trunc := math.Trunc(f)
+	if cmp := compareNumbers(i, int64(trunc)); cmp != 0 {
+		return cmp
+	}
+	return compareNumbers(0, f-trunc)
+}
+
+// compareJSONNumber compares num to other, an int64, float64, or json.Number,
+// and returns 0, 1, or -1. It compares num as an int64 if possible, and
+// otherwise compares the decimal text of num and other, except when other is
+// a float64 and num is not an integer, in which case it compares them as
+// float64s. Panics if num or other is not a valid number.
+func compareJSONNumber(num json.Number, other any) int {
+	if i, err := num.Int64(); err == nil {
+		return compareNumeric(i, other)
+	}
+
+	var text string
+	switch other := other.(type) {
+	case int64:
+		text = strconv.FormatInt(other, 10)
+	case json.Number:
+		text = string(other)
+	case float64:
+		dec, ok := parseDecimal(string(num))
+		if !ok || !dec.isInteger() || math.IsInf(other, 0) || math.IsNaN(other) {
+			return compareNumbers(jsonNumberFloat(num), other)
+		}
+		// Compare integers exactly. Integral float64s format exactly with
+		// no fractional digits; the shortest representation of any other
+		// lies between the same two integers.
+		prec := -1
+		if other == math.Trunc(other) {
+			prec = 0
+		}
+		text = strconv.FormatFloat(other, 'f', prec, 64)
+	}
+
+	left, lok := parseDecimal(string(num))
+	right, rok := parseDecimal(text)
+	if lok && rok {
+		return left.compare(right)
+	}
+	return compareNumbers(jsonNumberFloat(num), jsonNumberFloat(json.Number(text)))
+}
+
+// jsonNumberFloat converts num to a float64, returning ±Inf for numbers out
+// of range. Panics if num is not a valid number.
+func jsonNumberFloat(num json.Number) float64 {
+	f, err := num.Float64()
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		// This should not happen.
+		panic(err)
+	}
+	return f
+}
+
+// decimal represents the decimal number 0.digits × 10^exp, where digits has
+// no leading or trailing zeros. Zero has no digits.
+type decimal struct {
+	neg    bool
+	digits string
+	exp    int
+}
+
+// maxDecimalExp is the largest absolute exponent parseDecimal accepts.
+const maxDecimalExp = 1 << 30
+
+// parseDecimal parses str, a JSON number, into a decimal. Returns false if
+// str is not a valid number or its exponent is out of range.
+func parseDecimal(str string) (decimal, bool) {
+	var dec decimal
+	if str != "" && (str[0] == '-' || str[0] == '+') {
+		dec.neg = str[0] == '-'
+		str = str[1:]
+	}
+
+	if idx := strings.IndexAny(str, "eE"); idx >= 0 {
+		exp, err := strconv.Atoi(str[idx+1:])
+		if err != nil || exp > maxDecimalExp || exp < -maxDecimalExp {
+			return dec, false
+		}
+		dec.exp = exp
+		str = str[:idx]
+	}
+
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool {
+		return r < '0' || r > '9'
+	}) >= 0 {
+		return dec, false
+	}
+
+	dec.exp += len(intPart)
+	trimmed := strings.TrimLeft(digits, "0")
+	dec.exp -= len(digits) - len(trimmed)
+	dec.digits = strings.TrimRight(trimmed, "0")
+	if dec.digits == "" {
+		// Zero.
+		return decimal{}, true
+	}
+	return dec, true
+}
+
+// isInteger returns true if dec has no fractional digits.
+func (dec decimal) isInteger() bool {
+	return dec.exp >= len(dec.digits)
+}
+
+// sign returns -1, 0, or 1 depending on the sign of dec.
+func (dec decimal) sign() int {
+	switch {
+	case dec.digits == "":
+		return 0
+	case dec.neg:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compare compares dec to other and returns 0, 1, or -1.
+func (dec decimal) compare(other decimal) int {
+	sign := dec.sign()
+	if cmp := compareNumbers(sign, other.sign()); cmp != 0 || sign == 0 {
+		return cmp
+	}
+
+	// Same sign, compare magnitudes.
+	cmp := compareNumbers(dec.exp, other.exp)
+	if cmp == 0 {
+		cmp = strings.Compare(dec.digits, other.digits)
+	}
+	return cmp * sign
+}
+
+// roundedExp returns the exponent of dec rounded half away from zero to
+// scale fractional digits, or false if it rounds to zero.
+func (dec decimal) roundedExp(scale int) (int, bool) {
+	keep := dec.exp + scale // number of digits to keep
+	switch {
+	case dec.digits == "" || keep < 0 || (keep == 0 && dec.digits[0] < '5'):
+		return 0, false
+	case keep >= len(dec.digits) || dec.digits[keep] < '5':
+		return dec.exp, true
+	case strings.Trim(dec.digits[:keep], "9") == "":
+		// Rounds up to the next power of ten.
+		return dec.exp + 1, true
+	default:
+		return dec.exp, true
+	}
+}
+
+// round returns dec rounded half away from zero to scale fractional digits.
+// A negative scale rounds to a multiple of a power of ten.
+func (dec decimal) round(scale int) decimal {
+	keep := dec.exp + scale // number of digits to keep
+	switch {
+	case dec.digits == "" || keep < 0:
+		return decimal{}
+	case keep >= len(dec.digits):
+		return dec
+	}
+
+	digits := []byte(dec.digits[:keep])
+	if dec.digits[keep] >= '5' {
+		// Round up, carrying through trailing nines.
+		i := len(digits) - 1
+		for ; i >= 0 && digits[i] == '9'; i-- {
+			digits[i] = '0'
+		}
+		if i >= 0 {
+			digits[i]++
+		} else {
+			digits = append([]byte{'1'}, digits...)
+			dec.exp++
+		}
+	}
+
+	dec.digits = strings.TrimRight(string(digits), "0")
+	if dec.digits == "" {
+		return decimal{}
+	}
+	return dec
+}
+
+// text formats dec as a decimal number with scale fractional digits, or
+// none if scale is not positive, like a PostgreSQL numeric value with that
+// scale. Digits beyond scale are truncated; call round first to round them.
+func (dec decimal) text(scale int) string {
+	buf := new(strings.Builder)
+	if dec.neg && dec.digits != "" {
+		buf.WriteByte('-')
+	}
+
+	// Integer digits.
+	if dec.exp <= 0 {
+		buf.WriteByte('0')
+	} else {
+		intPart := dec.digits[:min(dec.exp, len(dec.digits))]
+		buf.WriteString(intPart)
+		buf.WriteString(strings.Repeat("0", dec.exp-len(intPart)))
+	}
+
+	if scale <= 0 {
+		return buf.String()
+	}
+
+	// Fractional digits.
+	var frac string
+	if dec.exp < 0 {
+		frac = strings.Repeat("0", -dec.exp) + dec.digits
+	} else if dec.exp < len(dec.digits) {
+		frac = dec.digits[dec.exp:]
+	}
+	if len(frac) > scale {
+		frac = frac[:scale]
+	}
+	buf.WriteByte('.')
+	buf.WriteString(frac)
+	buf.WriteString(strings.Repeat("0", scale-len(frac)))
+	return buf.String()
+}
+
+// trunc returns dec truncated toward zero to an integer.
+func (dec decimal) trunc() decimal {
+	switch {
+	case dec.exp <= 0:
+		return decimal{}
+	case dec.exp < len(dec.digits):
+		dec.digits = strings.TrimRight(dec.digits[:dec.exp], "0")
+	}
+	return dec
+}
+
+// int64 returns dec rounded half away from zero to an integer, and false if
+// the result is out of the range of int64.
+func (dec decimal) int64() (int64, bool) {
+	const maxInt64Digits = 19
+	if exp, ok := dec.roundedExp(0); !ok {
+		return 0, true
+	} else if exp > maxInt64Digits {
+		return 0, false
+	}
+
+	// Parse the integer digits, then round.
+	var abs uint64
+	if dec.exp > 0 {
+		intPart := dec.digits[:min(dec.exp, len(dec.digits))]
+		abs, _ = strconv.ParseUint(intPart+strings.Repeat("0", dec.exp-len(intPart)), 10, 64)
+	}
+	if dec.exp >= 0 && dec.exp < len(dec.digits) && dec.digits[dec.exp] >= '5' {
+		abs++
+	}
+
+	if dec.neg {
+		if abs > -math.MinInt64 {
+			return 0, false
+		}
+		return -int64(abs-1) - 1, true
+	}
+	if abs > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(abs), true
+}
@@ -3,6 +3,8 @@ package exec
 import (
 	"context"
 	"encoding/json"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -199,13 +201,18 @@ func TestCompareItems(t *testing.T) {
 			exp:   predUnknown,
 		},
 		{
-			name:  "datetime_unknown_err",
+			name:  "datetime_string_unknown",
 			path:  "$ == $",
 			left:  types.NewDate(now),
 			right: "not a date",
 			exp:   predUnknown,
-			err:   `exec invalid: unrecognized SQL/JSON datetime type string`,
-			isErr: ErrInvalid,
+		},
+		{
+			name:  "datetime_number_unknown",
+			path:  "$ == $",
+			left:  types.NewTimestampTZ(context.Background(), now),
+			right: int64(1),
+			exp:   predUnknown,
 		},
 		{
 			name:  "object_unknown",
@@ -252,6 +259,290 @@ func TestCompareItems(t *testing.T) {
 	}
 }
 
+func TestCompareItemsDeepEqual(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	obj := map[string]any{"a": int64(1), "b": []any{true, nil}}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		left  any
+		right any
+		exp   predOutcome
+	}{
+		{
+			name:  "empty_objects",
+			path:  "$ == $",
+			left:  map[string]any{},
+			right: map[string]any{},
+			exp:   predTrue,
+		},
+		{
+			name:  "empty_arrays",
+			path:  "$ == $",
+			left:  []any{},
+			right: []any{},
+			exp:   predTrue,
+		},
+		{
+			name:  "same_object",
+			path:  "$ == $",
+			left:  obj,
+			right: obj,
+			exp:   predTrue,
+		},
+		{
+			name:  "same_object_ne",
+			path:  "$ != $",
+			left:  obj,
+			right: obj,
+			exp:   predFalse,
+		},
+		{
+			name:  "object_key_order",
+			path:  "$ == $",
+			left:  map[string]any{"a": int64(1), "b": "x"},
+			right: map[string]any{"b": "x", "a": int64(1)},
+			exp:   predTrue,
+		},
+		{
+			name:  "object_extra_key",
+			path:  "$ == $",
+			left:  map[string]any{"a": int64(1)},
+			right: map[string]any{"a": int64(1), "b": int64(2)},
+			exp:   predFalse,
+		},
+		{
+			name:  "object_different_key",
+			path:  "$ != $",
+			left:  map[string]any{"a": int64(1)},
+			right: map[string]any{"b": int64(1)},
+			exp:   predTrue,
+		},
+		{
+			name:  "array_order",
+			path:  "$ == $",
+			left:  []any{int64(1), int64(2)},
+			right: []any{int64(2), int64(1)},
+			exp:   predFalse,
+		},
+		{
+			name:  "array_length",
+			path:  "$ != $",
+			left:  []any{int64(1)},
+			right: []any{int64(1), int64(1)},
+			exp:   predTrue,
+		},
+		{
+			name:  "numeric_types",
+			path:  "$ == $",
+			left:  []any{int64(1), float64(2), json.Number("3.0")},
+			right: []any{json.Number("1"), int64(2), float64(3)},
+			exp:   predTrue,
+		},
+		{
+			name:  "nested_nulls",
+			path:  "$ == $",
+			left:  map[string]any{"a": nil, "b": []any{nil}},
+			right: map[string]any{"a": nil, "b": []any{nil}},
+			exp:   predTrue,
+		},
+		{
+			name:  "null_vs_scalar",
+			path:  "$ == $",
+			left:  []any{nil},
+			right: []any{int64(0)},
+			exp:   predFalse,
+		},
+		{
+			name:  "nested",
+			path:  "$ == $",
+			left:  map[string]any{"a": []any{map[string]any{"b": []any{"c", true}}}},
+			right: map[string]any{"a": []any{map[string]any{"b": []any{"c", true}}}},
+			exp:   predTrue,
+		},
+		{
+			name:  "nested_diff",
+			path:  "$ == $",
+			left:  map[string]any{"a": []any{map[string]any{"b": []any{"c", true}}}},
+			right: map[string]any{"a": []any{map[string]any{"b": []any{"c", false}}}},
+			exp:   predFalse,
+		},
+		{
+			name:  "mixed_scalars",
+			path:  "$ == $",
+			left:  []any{int64(1)},
+			right: []any{"1"},
+			exp:   predFalse,
+		},
+		{
+			name:  "mixed_scalars_ne",
+			path:  "$ != $",
+			left:  []any{true},
+			right: []any{"true"},
+			exp:   predTrue,
+		},
+		{
+			name:  "object_vs_array",
+			path:  "$ == $",
+			left:  map[string]any{},
+			right: []any{},
+			exp:   predUnknown,
+		},
+		{
+			name:  "array_vs_object",
+			path:  "$ != $",
+			left:  []any{},
+			right: map[string]any{},
+			exp:   predUnknown,
+		},
+		{
+			name:  "array_vs_scalar",
+			path:  "$ == $",
+			left:  []any{int64(1)},
+			right: int64(1),
+			exp:   predUnknown,
+		},
+		{
+			name:  "scalar_vs_array",
+			path:  "$ == $",
+			left:  int64(1),
+			right: []any{int64(1)},
+			exp:   predUnknown,
+		},
+		{
+			name:  "less_than",
+			path:  "$ < $",
+			left:  []any{int64(1)},
+			right: []any{int64(2)},
+			exp:   predUnknown,
+		},
+		{
+			name:  "go_values",
+			path:  "$ == $",
+			left:  map[string]any{"a": []int{1, 2}, "b": map[string]float32{"c": 1.5}},
+			right: map[string]any{"a": []any{int64(1), int64(2)}, "b": map[string]any{"c": float64(1.5)}},
+			exp:   predTrue,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			// Without WithDeepEqual, the comparison is unknown, as in PostgreSQL.
+			e := newTestExecutor(path, nil, true, false)
+			res, err := e.compareItems(ctx, path.Root(), tc.left, tc.right)
+			r.NoError(err)
+			a.Equal(predUnknown, res)
+
+			e.deepEqual = true
+			res, err = e.compareItems(ctx, path.Root(), tc.left, tc.right)
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+		})
+	}
+}
+
+func TestDeepEqualQuery(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	value := []any{
+		map[string]any{"id": int64(1), "tags": []any{"a", "b"}, "meta": map[string]any{"x": int64(1)}},
+		map[string]any{"id": int64(2), "tags": []any{"b", "a"}, "meta": map[string]any{"x": float64(1)}},
+		map[string]any{"id": int64(3), "tags": "a", "meta": []any{}},
+	}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		vars  Vars
+		exp   []any
+		plain []any
+	}{
+		{
+			name:  "array_eq",
+			path:  `strict $[*] ? (@.tags == $tags).id`,
+			vars:  Vars{"tags": []any{"a", "b"}},
+			exp:   []any{int64(1)},
+			plain: []any{},
+		},
+		{
+			name:  "array_ne",
+			path:  `strict $[*] ? (@.tags != $tags).id`,
+			vars:  Vars{"tags": []any{"a", "b"}},
+			exp:   []any{int64(2)},
+			plain: []any{},
+		},
+		{
+			name:  "object_eq",
+			path:  `strict $[*] ? (@.meta == $meta).id`,
+			vars:  Vars{"meta": map[string]any{"x": json.Number("1.0")}},
+			exp:   []any{int64(1), int64(2)},
+			plain: []any{},
+		},
+		{
+			name:  "object_literal_path",
+			path:  `strict $[*] ? (@.meta == $[0].meta).id`,
+			exp:   []any{int64(1), int64(2)},
+			plain: []any{},
+		},
+		{
+			name:  "is_unknown_object_vs_array",
+			path:  `strict $[*] ? ((@.meta == $meta) is unknown).id`,
+			vars:  Vars{"meta": map[string]any{"x": int64(1)}},
+			exp:   []any{int64(3)},
+			plain: []any{int64(1), int64(2), int64(3)},
+		},
+		{
+			name:  "is_unknown_scalar",
+			path:  `strict $[*] ? ((@.tags == $tags) is unknown).id`,
+			vars:  Vars{"tags": []any{"a", "b"}},
+			exp:   []any{int64(3)},
+			plain: []any{int64(1), int64(2), int64(3)},
+		},
+		{
+			name:  "is_unknown_less_than",
+			path:  `strict $[*] ? ((@.meta < @.meta) is unknown).id`,
+			exp:   []any{int64(1), int64(2), int64(3)},
+			plain: []any{int64(1), int64(2), int64(3)},
+		},
+		{
+			name:  "lax_unwraps_arrays",
+			path:  `lax $[*] ? (@.tags == $tags).id`,
+			vars:  Vars{"tags": []any{"a", "b"}},
+			exp:   []any{int64(1), int64(2), int64(3)},
+			plain: []any{int64(1), int64(2), int64(3)},
+		},
+		{
+			name:  "predicate_check",
+			path:  `strict $[0].meta == $[1].meta`,
+			exp:   []any{true},
+			plain: []any{nil},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := Query(ctx, path, value, WithVars(tc.vars))
+			r.NoError(err)
+			a.Equal(tc.plain, res)
+
+			res, err = Query(ctx, path, value, WithVars(tc.vars), WithDeepEqual())
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+		})
+	}
+}
+
 func TestCompareBool(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
@@ -640,6 +931,126 @@ func TestCompareNumeric(t *testing.T) {
 			left:  "hi",
 			panic: true,
 		},
+		{
+			name:  "int64_float64_2_53_plus_1",
+			left:  int64(1<<53 + 1),
+			right: float64(1 << 53),
+			exp:   1,
+		},
+		{
+			name:  "float64_int64_2_53_plus_1",
+			left:  float64(1 << 53),
+			right: int64(1<<53 + 1),
+			exp:   -1,
+		},
+		{
+			name:  "int64_float64_neg_2_53_minus_1",
+			left:  int64(-1<<53 - 1),
+			right: float64(-1 << 53),
+			exp:   -1,
+		},
+		{
+			name:  "int64_float64_max",
+			left:  int64(math.MaxInt64),
+			right: float64(math.MaxInt64), // 2^63
+			exp:   -1,
+		},
+		{
+			name:  "int64_float64_min",
+			left:  int64(math.MinInt64),
+			right: float64(math.MinInt64),
+			exp:   0,
+		},
+		{
+			name:  "int64_float64_below_min",
+			left:  int64(math.MinInt64),
+			right: float64(-1 << 64),
+			exp:   1,
+		},
+		{
+			name:  "int64_float64_fraction",
+			left:  int64(-3),
+			right: float64(-3.5),
+			exp:   1,
+		},
+		{
+			name:  "int64_float64_inf",
+			left:  int64(math.MaxInt64),
+			right: math.Inf(1),
+			exp:   -1,
+		},
+		{
+			name:  "int64_json_2_53_plus_1",
+			left:  int64(1<<53 + 1),
+			right: json.Number("9007199254740992"),
+			exp:   1,
+		},
+		{
+			name:  "int64_json_beyond_max",
+			left:  int64(math.MaxInt64),
+			right: json.Number("9223372036854775808"),
+			exp:   -1,
+		},
+		{
+			name:  "int64_json_beyond_min",
+			left:  int64(math.MinInt64),
+			right: json.Number("-9223372036854775809"),
+			exp:   1,
+		},
+		{
+			name:  "int64_json_exponent",
+			left:  int64(1<<53 + 1),
+			right: json.Number("9.007199254740993e15"),
+			exp:   0,
+		},
+		{
+			name:  "json_json_big_eq",
+			left:  json.Number("123456789012345678901234567890"),
+			right: json.Number("1.2345678901234567890123456789e29"),
+			exp:   0,
+		},
+		{
+			name:  "json_json_big_lt",
+			left:  json.Number("123456789012345678901234567890"),
+			right: json.Number("123456789012345678901234567891"),
+			exp:   -1,
+		},
+		{
+			name:  "json_json_neg_big_gt",
+			left:  json.Number("-123456789012345678901234567890"),
+			right: json.Number("-123456789012345678901234567891"),
+			exp:   1,
+		},
+		{
+			name:  "json_json_fraction",
+			left:  json.Number("0.10000000000000000001"),
+			right: json.Number("0.1"),
+			exp:   1,
+		},
+		{
+			name:  "json_float64_big_int",
+			left:  json.Number("9223372036854775809"),
+			right: float64(1 << 63),
+			exp:   1,
+		},
+		{
+			name:  "json_float64_big_int_eq",
+			left:  json.Number("9223372036854775808"),
+			right: float64(1 << 63),
+			exp:   0,
+		},
+		{
+			name:  "json_float64_decimal",
+			left:  json.Number("0.1"),
+			right: float64(0.1),
+			exp:   0,
+		},
+		{
+			name:  "json_float64_out_of_range",
+			left:  json.Number("1e400"),
+			right: math.MaxFloat64,
+			exp:   1,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -651,3 +1062,367 @@ func TestCompareNumeric(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareIntFloat(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	for _, tc := range []struct {
+		i   int64
+		f   float64
+		exp int
+	}{
+		{0, 0, 0},
+		{0, -0.5, 1},
+		{0, 0.5, -1},
+		{42, 42, 0},
+		{42, 42.1, -1},
+		{-42, -42.1, 1},
+		{1 << 53, 1 << 53, 0},
+		{1<<53 + 1, 1 << 53, 1},
+		{1<<53 - 1, 1 << 53, -1},
+		{-1<<53 - 1, -1 << 53, -1},
+		{math.MaxInt64, math.MaxInt64, -1},
+		{math.MinInt64, math.MinInt64, 0},
+		{math.MinInt64 + 1, math.MinInt64, 1},
+		{math.MaxInt64, math.Inf(1), -1},
+		{math.MinInt64, math.Inf(-1), 1},
+		{0, math.NaN(), 0},
+	} {
+		a.Equal(tc.exp, compareIntFloat(tc.i, tc.f), "%v <=> %v", tc.i, tc.f)
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	for _, tc := range []struct {
+		str string
+		exp decimal
+		ok  bool
+	}{
+		{"0", decimal{}, true},
+		{"-0.000", decimal{}, true},
+		{"0e10", decimal{}, true},
+		{"1", decimal{digits: "1", exp: 1}, true},
+		{"-1", decimal{neg: true, digits: "1", exp: 1}, true},
+		{"+1", decimal{digits: "1", exp: 1}, true},
+		{"100", decimal{digits: "1", exp: 3}, true},
+		{"0.01", decimal{digits: "1", exp: -1}, true},
+		{"12.340", decimal{digits: "1234", exp: 2}, true},
+		{"1.5e3", decimal{digits: "15", exp: 4}, true},
+		{"15E-3", decimal{digits: "15", exp: -1}, true},
+		{"00042", decimal{digits: "42", exp: 2}, true},
+		{"", decimal{}, false},
+		{"-", decimal{}, false},
+		{".", decimal{}, false},
+		{"1e", decimal{}, false},
+		{"1x", decimal{}, false},
+		{"1.2.3", decimal{}, false},
+		{"1e9999999999", decimal{}, false},
+		{"NaN", decimal{}, false},
+	} {
+		dec, ok := parseDecimal(tc.str)
+		a.Equal(tc.ok, ok, tc.str)
+		if ok {
+			a.Equal(tc.exp, dec, tc.str)
+		}
+	}
+}
+
+func TestDecimalCompare(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	for _, tc := range []struct {
+		left  string
+		right string
+		exp   int
+	}{
+		{"0", "-0", 0},
+		{"0", "1", -1},
+		{"0", "-1", 1},
+		{"-1", "1", -1},
+		{"1", "1.0", 0},
+		{"10", "9", 1},
+		{"0.12", "0.123", -1},
+		{"-0.12", "-0.123", 1},
+		{"1e2", "100", 0},
+		{"9223372036854775807", "9223372036854775808", -1},
+		{"-9223372036854775808", "-9223372036854775809", 1},
+		{"1e-400", "0", 1},
+	} {
+		left, ok := parseDecimal(tc.left)
+		a.True(ok)
+		right, ok := parseDecimal(tc.right)
+		a.True(ok)
+		a.Equal(tc.exp, left.compare(right), "%v <=> %v", tc.left, tc.right)
+		a.Equal(-tc.exp, right.compare(left), "%v <=> %v", tc.right, tc.left)
+	}
+}
+
+func TestDecimalRoundedExp(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	for _, tc := range []struct {
+		str   string
+		scale int
+		exp   int
+		ok    bool
+	}{
+		{"0", 0, 0, false},
+		{"0.4", 0, 0, false},
+		{"0.5", 0, 1, true},
+		{"-0.5", 0, 1, true},
+		{"1", 0, 1, true},
+		{"99", 0, 2, true},
+		{"99.4", 0, 2, true},
+		{"99.5", 0, 3, true},
+		{"9.995", 2, 2, true},
+		{"9.994", 2, 1, true},
+		{"0.05", 6, -1, true},
+		{"0.004", 2, 0, false},
+		{"0.005", 2, -1, true},
+		{"150", -2, 3, true},
+		{"49", -2, 0, false},
+		{"1e400", 0, 401, true},
+	} {
+		dec, ok := parseDecimal(tc.str)
+		a.True(ok)
+		exp, ok := dec.roundedExp(tc.scale)
+		a.Equal(tc.ok, ok, "%v at %v", tc.str, tc.scale)
+		a.Equal(tc.exp, exp, "%v at %v", tc.str, tc.scale)
+	}
+}
+
+func TestDecimalRoundText(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	for _, tc := range []struct {
+		str   string
+		scale int
+		exp   string
+	}{
+		{"0", 0, "0"},
+		{"0", 2, "0.00"},
+		{"0.4", 0, "0"},
+		{"0.5", 0, "1"},
+		{"-0.5", 0, "-1"},
+		{"-0.001", 2, "0.00"},
+		{"1.5", 3, "1.500"},
+		{"12", 2, "12.00"},
+		{"12.333", 2, "12.33"},
+		{"9.995", 2, "10.00"},
+		{"99.5", 0, "100"},
+		{"0.005", 6, "0.005000"},
+		{"0.0012345", 4, "0.0012"},
+		{"1234.5678", -2, "1200"},
+		{"-1234.5678", -2, "-1200"},
+		{"1250", -2, "1300"},
+		{"49", -2, "0"},
+		{"-0.00123456", -4, "0"},
+		{"1e3", 1, "1000.0"},
+		{"123456789012345678901.5", 0, "123456789012345678902"},
+	} {
+		dec, ok := parseDecimal(tc.str)
+		a.True(ok)
+		a.Equal(tc.exp, dec.round(tc.scale).text(tc.scale), "%v at %v", tc.str, tc.scale)
+	}
+}
+
+func TestDecimalTrunc(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	for _, tc := range []struct {
+		str string
+		exp string
+	}{
+		{"0", "0"},
+		{"0.9", "0"},
+		{"-0.9", "0"},
+		{"1.5", "1"},
+		{"-1.5", "-1"},
+		{"10.01", "10"},
+		{"2.5e1", "25"},
+		{"1e400", "1" + strings.Repeat("0", 400)},
+	} {
+		dec, ok := parseDecimal(tc.str)
+		a.True(ok)
+		a.Equal(tc.exp, dec.trunc().text(0), tc.str)
+	}
+}
+
+func TestDecimalInt64(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	for _, tc := range []struct {
+		str string
+		exp int64
+		ok  bool
+	}{
+		{"0", 0, true},
+		{"0.4", 0, true},
+		{"-0.4", 0, true},
+		{"0.5", 1, true},
+		{"-0.5", -1, true},
+		{"1.83", 2, true},
+		{"-42.3", -42, true},
+		{"12e2", 1200, true},
+		{"1200e-2", 12, true},
+		{"9223372036854775807", math.MaxInt64, true},
+		{"9223372036854775807.4", math.MaxInt64, true},
+		{"9223372036854775807.5", 0, false},
+		{"9223372036854775808", 0, false},
+		{"-9223372036854775808", math.MinInt64, true},
+		{"-9223372036854775808.4", math.MinInt64, true},
+		{"-9223372036854775808.5", 0, false},
+		{"-9223372036854775809", 0, false},
+		{"12345678901234567890", 0, false},
+		{"1e400", 0, false},
+		{"1e-400", 0, true},
+	} {
+		dec, ok := parseDecimal(tc.str)
+		a.True(ok)
+		i, ok := dec.int64()
+		a.Equal(tc.ok, ok, tc.str)
+		a.Equal(tc.exp, i, tc.str)
+	}
+}
+
+func TestNumericComparisonPrecision(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		value any
+		lit   string
+		exp   map[string]bool
+	}{
+		{
+			name:  "2_53_plus_1",
+			value: int64(1<<53 + 1),
+			lit:   "9007199254740993",
+			exp:   map[string]bool{"==": true, "<": false, "<=": true, ">": false, ">=": true},
+		},
+		{
+			name:  "2_53_plus_1_vs_2_53",
+			value: int64(1<<53 + 1),
+			lit:   "9007199254740992",
+			exp:   map[string]bool{"==": false, "<": false, "<=": false, ">": true, ">=": true},
+		},
+		{
+			name:  "2_53_vs_2_53_plus_1",
+			value: int64(1 << 53),
+			lit:   "9007199254740993",
+			exp:   map[string]bool{"==": false, "<": true, "<=": true, ">": false, ">=": false},
+		},
+		{
+			name:  "neg_2_53_minus_1_vs_neg_2_53",
+			value: int64(-1<<53 - 1),
+			lit:   "-9007199254740992",
+			exp:   map[string]bool{"==": false, "<": true, "<=": true, ">": false, ">=": false},
+		},
+		{
+			name:  "2_53_plus_1_vs_float",
+			value: int64(1<<53 + 1),
+			lit:   "9007199254740993.0", // float64 9007199254740992
+			exp:   map[string]bool{"==": false, "<": false, "<=": false, ">": true, ">=": true},
+		},
+		{
+			name:  "max_int64",
+			value: int64(math.MaxInt64),
+			lit:   "9223372036854775807",
+			exp:   map[string]bool{"==": true, "<": false, "<=": true, ">": false, ">=": true},
+		},
+		{
+			name:  "max_int64_vs_max_minus_1",
+			value: int64(math.MaxInt64),
+			lit:   "9223372036854775806",
+			exp:   map[string]bool{"==": false, "<": false, "<=": false, ">": true, ">=": true},
+		},
+		{
+			name:  "max_int64_vs_2_63",
+			value: int64(math.MaxInt64),
+			lit:   "9223372036854775808", // float64 2^63
+			exp:   map[string]bool{"==": false, "<": true, "<=": true, ">": false, ">=": false},
+		},
+		{
+			name:  "min_int64",
+			value: int64(math.MinInt64),
+			lit:   "-9223372036854775808",
+			exp:   map[string]bool{"==": true, "<": false, "<=": true, ">": false, ">=": true},
+		},
+		{
+			name:  "min_int64_plus_1",
+			value: int64(math.MinInt64 + 1),
+			lit:   "-9223372036854775808",
+			exp:   map[string]bool{"==": false, "<": false, "<=": false, ">": true, ">=": true},
+		},
+		{
+			name:  "json_2_53_plus_1",
+			value: json.Number("9007199254740993"),
+			lit:   "9007199254740992",
+			exp:   map[string]bool{"==": false, "<": false, "<=": false, ">": true, ">=": true},
+		},
+		{
+			name:  "json_beyond_max_int64",
+			value: json.Number("9223372036854775809"),
+			lit:   "9223372036854775807",
+			exp:   map[string]bool{"==": false, "<": false, "<=": false, ">": true, ">=": true},
+		},
+		{
+			name:  "json_neg_beyond_min_int64",
+			value: json.Number("-9223372036854775809"),
+			lit:   "-9223372036854775808",
+			exp:   map[string]bool{"==": false, "<": true, "<=": true, ">": false, ">=": false},
+		},
+	} {
+		for _, op := range []string{"==", "<", "<=", ">", ">="} {
+			exp := tc.exp[op]
+			t.Run(tc.name+op, func(t *testing.T) {
+				t.Parallel()
+
+				// Top-level comparison.
+				path, err := parser.Parse("$ " + op + " " + tc.lit)
+				r.NoError(err)
+				res, err := Query(ctx, path, tc.value)
+				r.NoError(err)
+				a.Equal([]any{exp}, res)
+
+				// Reversed operands.
+				rev := map[string]string{"==": "==", "<": ">", "<=": ">=", ">": "<", ">=": "<="}[op]
+				path, err = parser.Parse(tc.lit + " " + rev + " $")
+				r.NoError(err)
+				res, err = Query(ctx, path, tc.value)
+				r.NoError(err)
+				a.Equal([]any{exp}, res)
+
+				// Filter expression.
+				path, err = parser.Parse("$[*] ? (@ " + op + " " + tc.lit + ")")
+				r.NoError(err)
+				res, err = Query(ctx, path, []any{tc.value})
+				r.NoError(err)
+				if exp {
+					a.Equal([]any{tc.value}, res)
+				} else {
+					a.Empty(res)
+				}
+
+				// Variable.
+				path, err = parser.Parse("$x " + op + " " + tc.lit)
+				r.NoError(err)
+				res, err = Query(ctx, path, nil, WithVars(Vars{"x": tc.value}))
+				r.NoError(err)
+				a.Equal([]any{exp}, res)
+			})
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/theory/sqljson/path/ast"
-	"golang.org/x/exp/maps" // Switch to maps when go 1.22 dropped
 )
 
 // execConstNode Executes node against value.
@@ -20,7 +19,8 @@ func (exec *Executor) execConstNode(
 	case ast.ConstNull, ast.ConstTrue, ast.ConstFalse:
 		return exec.execLiteralConst(ctx, node, found)
 	case ast.ConstRoot:
-		defer exec.setTempBaseObject(exec.root, 0)()
+		defer exec.leave(exec.enter(locElem{kind: locRoot}))
+		defer exec.setTempBaseObject(0)()
 		return exec.executeNextItem(ctx, node, nil, exec.root, found)
 	case ast.ConstCurrent:
 		return exec.executeNextItem(ctx, node, nil, exec.current, found)
@@ -74,8 +74,9 @@ func (exec *Executor) execAnyKey(
 ) (resultStatus, error) {
 	switch value := value.(type) {
 	case map[string]any:
+		values, keys := exec.members(value)
 		return exec.executeAnyItem(
-			ctx, node.Next(), maps.Values(value), found,
+			ctx, node.Next(), values, keys, found,
 			1, 1, 1, false, exec.autoUnwrap(),
 		)
 	case []any:
@@ -86,19 +87,30 @@ func (exec *Executor) execAnyKey(
 
 	if !exec.ignoreStructuralErrors {
 		// https://github.com/postgres/postgres/blob/REL_17_2/src/backend/utils/adt/jsonpath_exec.c#L874
-		return exec.returnVerboseError(fmt.Errorf(
-			"%w: jsonpath wildcard member accessor can only be applied to an object",
-			ErrVerbose,
-		))
+		return exec.returnVerboseError(anyKeyTypeErr())
+	}
+	if exec.logSuppressed {
+		exec.suppressed(ctx, anyKeyTypeErr())
 	}
 
 	return statusNotFound, nil
 }
 
+// anyKeyTypeErr returns the error for a wildcard member accessor applied to
+// a value other than an object.
+func anyKeyTypeErr() error {
+	return fmt.Errorf(
+		"%w: jsonpath wildcard member accessor can only be applied to an object",
+		errVerboseStructural,
+	)
+}
+
 // execAnyArray executes node against value. If value's type is not []any but
 // exec.autoWrap() returns true, it passed it to executeNextItem to be
 // unwrapped. Otherwise it returns statusFailed and an error if
 // exec.ignoreStructuralErrors is false, and statusNotFound if it is true.
+// Passes arrays for which exec.useParallel returns true to
+// executeAnyArrayParallel.
 func (exec *Executor) execAnyArray(
 	ctx context.Context,
 	node *ast.ConstNode,
@@ -106,7 +118,13 @@ func (exec *Executor) execAnyArray(
 	found *valueList,
 ) (resultStatus, error) {
 	if value, ok := value.([]any); ok {
-		return exec.executeAnyItem(ctx, node.Next(), value, found, 1, 1, 1, false, exec.autoUnwrap())
+		if found != nil && selectsOne(node.Next()) {
+			found.grow(len(value))
+		}
+		if exec.useParallel(node.Next(), value) {
+			return exec.executeAnyArrayParallel(ctx, node.Next(), value, found, exec.autoUnwrap())
+		}
+		return exec.executeAnyItem(ctx, node.Next(), value, nil, found, 1, 1, 1, false, exec.autoUnwrap())
 	}
 
 	if exec.autoWrap() {
@@ -115,15 +133,24 @@ func (exec *Executor) execAnyArray(
 
 	if !exec.ignoreStructuralErrors {
 		// https://github.com/postgres/postgres/blob/REL_17_2/src/backend/utils/adt/jsonpath_exec.c#L851
-		return exec.returnVerboseError(fmt.Errorf(
-			"%w: jsonpath wildcard array accessor can only be applied to an array",
-			ErrVerbose,
-		))
+		return exec.returnVerboseError(anyArrayTypeErr())
+	}
+	if exec.logSuppressed {
+		exec.suppressed(ctx, anyArrayTypeErr())
 	}
 
 	return statusNotFound, nil
 }
 
+// anyArrayTypeErr returns the error for a wildcard array accessor applied to
+// a value other than an array.
+func anyArrayTypeErr() error {
+	return fmt.Errorf(
+		"%w: jsonpath wildcard array accessor can only be applied to an array",
+		errVerboseStructural,
+	)
+}
+
 // execLastConst handles execution of the LAST node. Returns an error if
 // execution is not currently part of an array subscript.
 func (exec *Executor) execLastConst(
@@ -16,8 +16,9 @@ func TestExecConstNode(t *testing.T) {
 	r := require.New(t)
 	ctx := context.Background()
 	path, _ := parser.Parse("$")
-	base := kvBaseObject{addr: uintptr(42), id: -1}
+	base := kvBaseObject{id: -1, loc: 42}
 	current := []any{"hi", true}
+	root := map[string]any{"root": true}
 
 	for _, tc := range []struct {
 		name   string
@@ -51,7 +52,7 @@ func TestExecConstNode(t *testing.T) {
 			name: "root",
 			node: ast.NewConst(ast.ConstRoot),
 			exp:  statusOK,
-			find: []any{path.Root()},
+			find: []any{root},
 		},
 		{
 			name: "current",
@@ -122,7 +123,7 @@ func TestExecConstNode(t *testing.T) {
 
 			// Construct executor.
 			e := newTestExecutor(path, nil, true, false)
-			e.root = path.Root()
+			e.root = root
 			e.baseObject = base
 			e.current = current
 			e.innermostArraySize = 4
@@ -0,0 +1,144 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/parser"
+)
+
+// MethodFunc implements a custom item method. It receives a JSON item and
+// returns the item to pass to the rest of the path. Items are nil or values
+// of type bool, int64, float64, [encoding/json.Number], string, []any,
+// map[string]any, or date and time values implementing types.DateTime.
+// Execution converts other return values as it does Go values passed to
+// [Query]. Returned errors are wrapped in [ErrVerbose] errors, and therefore
+// suppressed by [WithSilent].
+type MethodFunc func(ctx context.Context, value any) (any, error)
+
+// registeredMethods records the methods registered by RegisterMethod.
+//
+//nolint:gochecknoglobals
+var registeredMethods sync.Map
+
+// RegisterMethod registers fn as the implementation of the custom item
+// method name for all executions, and registers name with
+// [parser.RegisterMethod] so that paths can call it, as in $.name(). Custom
+// methods take no arguments. In lax mode, calling a custom method on an
+// array applies it to each of its elements, as for .double(). Returns an
+// error if name is not a valid identifier, conflicts with a built-in method
+// or other jsonpath reserved word, or has already been registered, or if fn
+// is nil. Usually called from an init function.
+func RegisterMethod(name string, fn MethodFunc) error {
+	if fn == nil {
+		return fmt.Errorf("%w: nil function for method %q", ErrInvalid, name)
+	}
+	if err := parser.RegisterMethod(name); err != nil {
+		return err
+	}
+	if _, loaded := registeredMethods.LoadOrStore(name, fn); loaded {
+		return fmt.Errorf("%w: method %q already registered", ErrInvalid, name)
+	}
+	return nil
+}
+
+// WithMethods provides implementations of custom item methods for a single
+// execution, keyed by name. They take precedence over methods registered by
+// [RegisterMethod]. Parsing a path that calls one of them requires its name
+// to be registered by [RegisterMethod] or [parser.RegisterMethod]; paths
+// cannot call methods named for built-in methods or other reserved words,
+// so WithMethods cannot replace them. When passed more than once, it merges
+// the methods, those passed later taking precedence over those with the same
+// names passed earlier. Execution returns an [ErrInvalid] error for a nil
+// function.
+func WithMethods(methods map[string]MethodFunc) Option {
+	return func(e *Executor) {
+		if e.methods == nil {
+			e.methods = methods
+			return
+		}
+		// Merge into a new map, since other executions share methods.
+		merged := make(map[string]MethodFunc, len(e.methods)+len(methods))
+		for name, fn := range e.methods {
+			merged[name] = fn
+		}
+		for name, fn := range methods {
+			merged[name] = fn
+		}
+		e.methods = merged
+	}
+}
+
+// method returns the implementation of the custom method name, preferring
+// those passed to WithMethods to those registered by RegisterMethod. Returns
+// nil if no implementation exists.
+func (exec *Executor) method(name string) MethodFunc {
+	if fn, ok := exec.methods[name]; ok {
+		return fn
+	}
+	if fn, ok := registeredMethods.Load(name); ok {
+		return fn.(MethodFunc)
+	}
+	return nil
+}
+
+// JSONString is a [MethodFunc] that serializes an item of any type,
+// including objects and arrays, to compact JSON text, for the common need
+// for the JSON text of a matched subtree, which .string() cannot provide
+// because PostgreSQL allows it only for scalar values. Its result is a
+// string for use in comparisons and like_regex, as in
+// $[*] ? (@.json_string() like_regex "\"id\":"). Register it to call it as a
+// custom method:
+//
+//	exec.RegisterMethod("json_string", exec.JSONString)
+//
+// Object keys appear in sorted order, numbers retain the text of
+// [encoding/json.Number] values, and date and time values appear as quoted
+// ISO 8601 strings. As for other custom methods, lax mode applies it to
+// each element of an array rather than to the array itself; use strict mode
+// to serialize arrays.
+func JSONString(_ context.Context, value any) (any, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		// execCustomMethod wraps the error.
+		//nolint:wrapcheck
+		return nil, err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// execCustomMethod handles the execution of a custom method by passing value
+// to its implementation and the result to the next execution node. Returns
+// an [ErrInvalid] error if the method has no implementation.
+func (exec *Executor) execCustomMethod(
+	ctx context.Context,
+	node *ast.MethodNode,
+	value any,
+	found *valueList,
+) (resultStatus, error) {
+	fn := exec.method(node.Custom())
+	if fn == nil {
+		return statusFailed, fmt.Errorf(
+			"%w: unknown method %v", ErrInvalid, node,
+		)
+	}
+
+	res, err := fn(ctx, value)
+	if err != nil {
+		return exec.returnVerboseError(fmt.Errorf(
+			"%w: jsonpath item method %v failed: %w", ErrVerbose, node, err,
+		))
+	}
+	if res, err = exec.normalize(res); err != nil {
+		return statusFailed, err
+	}
+
+	return exec.executeNextItem(ctx, node, nil, res, found)
+}
@@ -0,0 +1,443 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/parser"
+)
+
+var errNotString = errors.New("not a string")
+
+// testUpper is a MethodFunc that upper-cases strings.
+func testUpper(_ context.Context, value any) (any, error) {
+	if str, ok := value.(string); ok {
+		return strings.ToUpper(str), nil
+	}
+	return nil, errNotString
+}
+
+// testLower is a MethodFunc that lower-cases strings.
+func testLower(_ context.Context, value any) (any, error) {
+	if str, ok := value.(string); ok {
+		return strings.ToLower(str), nil
+	}
+	return nil, errNotString
+}
+
+// testWords is a MethodFunc that splits strings into a []string of words.
+func testWords(_ context.Context, value any) (any, error) {
+	if str, ok := value.(string); ok {
+		return strings.Fields(str), nil
+	}
+	return nil, errNotString
+}
+
+// registerTestMethods registers custom methods for tests, once.
+//
+//nolint:gochecknoglobals
+var registerTestMethods = sync.OnceFunc(func() {
+	for name, fn := range map[string]MethodFunc{
+		"test_upper":  testUpper,
+		"test_words":  testWords,
+		"json_string": JSONString,
+	} {
+		if err := RegisterMethod(name, fn); err != nil {
+			panic(err)
+		}
+	}
+	// Methods known to the parser but implemented only by WithMethods.
+	for _, name := range []string{"test_local", "test_missing"} {
+		if err := parser.RegisterMethod(name); err != nil {
+			panic(err)
+		}
+	}
+})
+
+func TestRegisterMethod(t *testing.T) {
+	t.Parallel()
+	registerTestMethods()
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		fn     MethodFunc
+		err    string
+		isErr  error
+	}{
+		{
+			name:   "nil",
+			method: "test_nil",
+			err:    `exec invalid: nil function for method "test_nil"`,
+			isErr:  ErrInvalid,
+		},
+		{
+			name:   "invalid",
+			method: "test-dash",
+			fn:     testUpper,
+			err:    `parser: invalid method name "test-dash"`,
+			isErr:  parser.ErrParse,
+		},
+		{
+			name:   "builtin",
+			method: "double",
+			fn:     testUpper,
+			err:    `parser: method name "double" conflicts with a reserved word`,
+			isErr:  parser.ErrParse,
+		},
+		{
+			name:   "registered",
+			method: "test_upper",
+			fn:     testLower,
+			err:    `exec invalid: method "test_upper" already registered`,
+			isErr:  ErrInvalid,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := RegisterMethod(tc.method, tc.fn)
+			require.EqualError(t, err, tc.err)
+			require.ErrorIs(t, err, tc.isErr)
+		})
+	}
+}
+
+func TestWithMethods(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	e := &Executor{}
+	WithMethods(map[string]MethodFunc{"test_local": testLower})(e)
+	a.Len(e.methods, 1)
+	a.NotNil(e.method("test_local"))
+	a.Nil(e.method("test_nope"))
+}
+
+func TestExecCustomMethod(t *testing.T) {
+	t.Parallel()
+	registerTestMethods()
+	ctx := context.Background()
+	upper := ast.NewCustomMethod("test_upper")
+
+	for _, tc := range []struct {
+		methodTestCase
+		methods map[string]MethodFunc
+	}{
+		{
+			methodTestCase: methodTestCase{
+				name:  "string",
+				node:  upper,
+				value: "hi",
+				exp:   statusOK,
+				find:  []any{"HI"},
+			},
+		},
+		{
+			methodTestCase: methodTestCase{
+				name:   "array_unwrap",
+				node:   upper,
+				value:  []any{"hi", "there"},
+				unwrap: true,
+				exp:    statusOK,
+				find:   []any{"HI", "THERE"},
+			},
+		},
+		{
+			methodTestCase: methodTestCase{
+				name:  "array_no_unwrap",
+				node:  upper,
+				value: []any{"hi", "there"},
+				exp:   statusFailed,
+				err:   `exec: jsonpath item method .test_upper() failed: not a string`,
+				isErr: ErrVerbose,
+			},
+		},
+		{
+			methodTestCase: methodTestCase{
+				name:  "not_string",
+				node:  upper,
+				value: int64(42),
+				exp:   statusFailed,
+				err:   `exec: jsonpath item method .test_upper() failed: not a string`,
+				isErr: errNotString,
+			},
+		},
+		{
+			methodTestCase: methodTestCase{
+				name:   "not_string_silent",
+				node:   upper,
+				value:  int64(42),
+				silent: true,
+				exp:    statusFailed,
+				find:   []any{},
+			},
+		},
+		{
+			methodTestCase: methodTestCase{
+				name:  "go_result",
+				node:  ast.NewCustomMethod("test_words"),
+				value: "hi there",
+				exp:   statusOK,
+				find:  []any{[]any{"hi", "there"}},
+			},
+		},
+		{
+			methodTestCase: methodTestCase{
+				name: "next",
+				node: ast.LinkNodes([]ast.Node{
+					ast.NewCustomMethod("test_words"),
+					ast.NewMethod(ast.MethodSize),
+				}),
+				value: "hi there you",
+				exp:   statusOK,
+				find:  []any{int64(3)},
+			},
+		},
+		{
+			methodTestCase: methodTestCase{
+				name:  "override",
+				node:  upper,
+				value: "Hi",
+				exp:   statusOK,
+				find:  []any{"hi"},
+			},
+			methods: map[string]MethodFunc{"test_upper": testLower},
+		},
+		{
+			methodTestCase: methodTestCase{
+				name:  "local",
+				node:  ast.NewCustomMethod("test_local"),
+				value: "Hi",
+				exp:   statusOK,
+				find:  []any{"hi"},
+			},
+			methods: map[string]MethodFunc{"test_local": testLower},
+		},
+		{
+			methodTestCase: methodTestCase{
+				name:  "unknown",
+				node:  ast.NewCustomMethod("test_missing"),
+				value: "Hi",
+				exp:   statusFailed,
+				err:   `exec invalid: unknown method .test_missing()`,
+				isErr: ErrInvalid,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			node, ok := tc.node.(*ast.MethodNode)
+			tc.checkNode(t, ok, node, ast.MethodCustom)
+
+			e, list := tc.prep()
+			e.methods = tc.methods
+			res, err := e.executeNode(ctx, node, tc.value, list, tc.unwrap)
+			tc.checkResults(t, res, list, err)
+		})
+	}
+}
+
+func TestCustomMethodQuery(t *testing.T) {
+	t.Parallel()
+	registerTestMethods()
+	ctx := context.Background()
+
+	value := map[string]any{
+		"users": []any{
+			map[string]any{"name": "Ann", "tags": []any{"admin", "dev"}},
+			map[string]any{"name": "bob", "tags": []any{"Ops"}},
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		path string
+		opt  []Option
+		exp  []any
+		err  string
+	}{
+		{
+			name: "method",
+			path: `$.users[*].name.test_upper()`,
+			exp:  []any{"ANN", "BOB"},
+		},
+		{
+			name: "filter",
+			path: `$.users[*] ? (@.name.test_upper() == "BOB").name`,
+			exp:  []any{"bob"},
+		},
+		{
+			name: "lax_unwrap",
+			path: `$.users[*].tags.test_upper()`,
+			exp:  []any{"ADMIN", "DEV", "OPS"},
+		},
+		{
+			name: "strict_array",
+			path: `strict $.users[0].tags.test_upper()`,
+			err:  `exec: jsonpath item method .test_upper() failed: not a string`,
+		},
+		{
+			name: "strict_array_silent",
+			path: `strict $.users[0].tags.test_upper()`,
+			opt:  []Option{WithSilent()},
+			exp:  []any{},
+		},
+		{
+			name: "strict_filter_error",
+			path: `strict $.users[*] ? (@.tags.test_upper() == "OPS").name`,
+			exp:  []any{},
+		},
+		{
+			name: "with_methods",
+			path: `$.users[*].name.test_local()`,
+			opt:  []Option{WithMethods(map[string]MethodFunc{"test_local": testLower})},
+			exp:  []any{"ann", "bob"},
+		},
+		{
+			name: "unknown",
+			path: `$.users[*].name.test_local()`,
+			err:  `exec invalid: unknown method .test_local()`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			require.NoError(t, err)
+			res, err := Query(ctx, path, value, tc.opt...)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, res)
+		})
+	}
+}
+
+func TestCustomMethodBinary(t *testing.T) {
+	t.Parallel()
+	registerTestMethods()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	path, err := parser.Parse(`$.names[*].test_upper()`)
+	r.NoError(err)
+	data, err := path.MarshalBinary()
+	r.NoError(err)
+	got, err := ast.UnmarshalBinary(data)
+	r.NoError(err)
+	a.Equal(path, got)
+	a.Equal(`$."names"[*].test_upper()`, got.String())
+
+	value := map[string]any{"names": []any{"ann", "bob"}}
+	res, err := Query(ctx, got, value)
+	r.NoError(err)
+	a.Equal([]any{"ANN", "BOB"}, res)
+}
+
+func TestJSONString(t *testing.T) {
+	t.Parallel()
+	registerTestMethods()
+	ctx := context.Background()
+	value, err := NormalizeJSON([]byte(`[
+		{"id": 1, "name": "Ann <a&b>", "tags": ["x", "y"], "price": 1.50},
+		{"name": "Bob", "meta": {"z": 1e2, "a": null, "when": "2024-01-02"}},
+		[1, 2]
+	]`))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		path string
+		opt  []Option
+		exp  []any
+		err  string
+	}{
+		{
+			name: "object",
+			path: `$[0].json_string()`,
+			exp:  []any{`{"id":1,"name":"Ann <a&b>","price":1.50,"tags":["x","y"]}`},
+		},
+		{
+			name: "nested",
+			path: `$[1].meta.json_string()`,
+			exp:  []any{`{"a":null,"when":"2024-01-02","z":1e2}`},
+		},
+		{
+			name: "strict_array",
+			path: `strict $[2].json_string()`,
+			exp:  []any{`[1,2]`},
+		},
+		{
+			name: "lax_array",
+			path: `lax $[2].json_string()`,
+			exp:  []any{`1`, `2`},
+		},
+		{
+			name: "scalars",
+			path: `strict $[0].*.json_string()`,
+			opt:  []Option{WithOrderedKeys()},
+			exp:  []any{`1`, `"Ann <a&b>"`, `1.50`, `["x","y"]`},
+		},
+		{
+			name: "datetime",
+			path: `$[1].meta.when.datetime().json_string()`,
+			exp:  []any{`"2024-01-02"`},
+		},
+		{
+			name: "datetime_tz",
+			path: `"2024-01-02 03:04:05+01".datetime().json_string()`,
+			exp:  []any{`"2024-01-02T03:04:05+01:00"`},
+		},
+		{
+			name: "like_regex",
+			path: `$[*] ? (@.json_string() like_regex "\"id\":").name`,
+			exp:  []any{"Ann <a&b>"},
+		},
+		{
+			name: "comparison",
+			path: `strict $[*] ? (@.json_string() == "[1,2]")`,
+			exp:  []any{[]any{json.Number("1"), json.Number("2")}},
+		},
+		{
+			name: "starts_with",
+			path: `$[*] ? (@.meta.json_string() starts with "{\"a\":null").name`,
+			exp:  []any{"Bob"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			require.NoError(t, err)
+			res, err := Query(ctx, path, value, tc.opt...)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, res)
+		})
+	}
+
+	t.Run("number_text", func(t *testing.T) {
+		t.Parallel()
+		res, err := JSONString(ctx, []any{json.Number("1.50e3"), int64(2), float64(0.5)})
+		require.NoError(t, err)
+		assert.Equal(t, `[1.50e3,2,0.5]`, res)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+		res, err := JSONString(ctx, func() {})
+		require.EqualError(t, err, "json: unsupported type: func()")
+		assert.Nil(t, res)
+	})
+}
@@ -2,7 +2,9 @@ package exec
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/theory/sqljson/path/ast"
 	"github.com/theory/sqljson/path/types"
@@ -13,7 +15,7 @@ import (
 func tzRequiredCast(type1, type2 string) error {
 	return fmt.Errorf(
 		"%w: cannot convert value from %v to %v without time zone usage. HINT: Use WithTZ() option for time zone support",
-		ErrExecution, type1, type2,
+		ErrDateTime, type1, type2,
 	)
 }
 
@@ -27,133 +29,34 @@ func unknownDateTime(val any) (int, error) {
 }
 
 // compareDatetime performs a Cross-type comparison of two datetime SQL/JSON
-// items. Returns <= -1 if items are incomparable. Returns an error if a cast
-// requires timezone useTZ is false.
+// items by calling [types.Compare]. Returns -2 if the items are incomparable,
+// including when val2 is not a datetime. Returns an error if a cast requires
+// time zone usage and useTZ is false.
 func compareDatetime(ctx context.Context, val1, val2 any, useTZ bool) (int, error) {
-	switch val1 := val1.(type) {
-	case *types.Date:
-		return compareDate(ctx, val1, val2, useTZ)
-	case *types.Time:
-		return compareTime(ctx, val1, val2, useTZ)
-	case *types.TimeTZ:
-		return compareTimeTZ(ctx, val1, val2, useTZ)
-	case *types.Timestamp:
-		return compareTimestamp(ctx, val1, val2, useTZ)
-	case *types.TimestampTZ:
-		return compareTimestampTZ(ctx, val1, val2, useTZ)
-	default:
+	dt1, ok := val1.(types.DateTime)
+	if !ok {
 		return unknownDateTime(val1)
 	}
-}
-
-// compareDate compares val1 to val1. Returns -2 if they're incomparable and
-// an error if a cast requires timezone useTZ is false.
-func compareDate(_ context.Context, val1 *types.Date, val2 any, useTZ bool) (int, error) {
-	switch val2 := val2.(type) {
-	case *types.Date:
-		return val1.Compare(val2.Time), nil
-	case *types.Timestamp:
-		return val1.Compare(val2.Time), nil
-	case *types.TimestampTZ:
-		if !useTZ {
-			return 0, tzRequiredCast("date", "timestamptz")
-		}
-		return val1.Compare(val2.Time), nil
-	case *types.Time, *types.TimeTZ:
-		// Incomparable types
-		return -2, nil
-	default:
-		return unknownDateTime(val2)
-	}
-}
-
-// compareTime compares val1 to val1. Returns -2 if they're incomparable and
-// an error if a cast requires timezone useTZ is false.
-func compareTime(ctx context.Context, val1 *types.Time, val2 any, useTZ bool) (int, error) {
-	switch val2 := val2.(type) {
-	case *types.Time:
-		return val1.Compare(val2.Time), nil
-	case *types.TimeTZ:
-		if !useTZ {
-			return 0, tzRequiredCast("time", "timetz")
-		}
-		// Convert time to timetz context time.
-		ttz := val1.ToTimeTZ(ctx)
-		// There are special comparison rules for TimeTZ, so use its Compare
-		// function and invert the result.
-		return -val2.Compare(ttz.Time), nil
-
-	case *types.Date, *types.Timestamp, *types.TimestampTZ:
-		// Incomparable types
-		return -2, nil
-	default:
-		return unknownDateTime(val2)
-	}
-}
-
-// compareTimeTZ compares val1 to val1. Returns -2 if they're incomparable and
-// an error if a cast requires timezone useTZ is false.
-func compareTimeTZ(ctx context.Context, val1 *types.TimeTZ, val2 any, useTZ bool) (int, error) {
-	switch val2 := val2.(type) {
-	case *types.Time:
-		if !useTZ {
-			return 0, tzRequiredCast("time", "timetz")
-		}
-		// Convert time to timetz context time.
-		return val1.Compare(val2.ToTimeTZ(ctx).Time), nil
-	case *types.TimeTZ:
-		return val1.Compare(val2.Time), nil
-	case *types.Date, *types.Timestamp, *types.TimestampTZ:
-		// Incomparable types
-		return -2, nil
-	default:
-		return unknownDateTime(val2)
-	}
-}
-
-// compareTimestamp compares val1 to val1. Returns -2 if they're incomparable
-// and an error if a cast requires timezone useTZ is false.
-func compareTimestamp(_ context.Context, val1 *types.Timestamp, val2 any, useTZ bool) (int, error) {
-	switch val2 := val2.(type) {
-	case *types.Date:
-		return val1.Compare(val2.Time), nil
-	case *types.Timestamp:
-		return val1.Compare(val2.Time), nil
-	case *types.TimestampTZ:
-		if !useTZ {
-			return 0, tzRequiredCast("timestamp", "timestamptz")
-		}
-		return val1.UTC().Compare(val2.Time), nil
-	case *types.Time, *types.TimeTZ:
+	dt2, ok := val2.(types.DateTime)
+	if !ok {
 		// Incomparable types
 		return -2, nil
-	default:
-		return unknownDateTime(val2)
 	}
-}
 
-// compareTimestampTZ compares val1 to val1. Returns -2 if they're
-// incomparable and an error if a cast requires timezone useTZ is false.
-func compareTimestampTZ(_ context.Context, val1 *types.TimestampTZ, val2 any, useTZ bool) (int, error) {
-	switch val2 := val2.(type) {
-	case *types.Date:
-		if !useTZ {
-			return 0, tzRequiredCast("date", "timestamptz")
-		}
-		return val1.Compare(val2.Time.UTC()), nil
-	case *types.Timestamp:
-		if !useTZ {
-			return 0, tzRequiredCast("timestamp", "timestamptz")
+	cmp, err := types.Compare(ctx, dt1, dt2, useTZ)
+	if err != nil {
+		var convErr *types.ConversionError
+		switch {
+		case !errors.As(err, &convErr):
+			return 0, fmt.Errorf("%w: %w", ErrInvalid, err)
+		case convErr.TZRequired:
+			return 0, tzRequiredCast(convErr.From, convErr.To)
+		default:
+			// Incomparable types
+			return -2, nil
 		}
-		return val1.Compare(val2.Time.UTC()), nil
-	case *types.TimestampTZ:
-		return val1.Compare(val2.Time), nil
-	case *types.Time, *types.TimeTZ:
-		// Incomparable types
-		return -2, nil
-	default:
-		return unknownDateTime(val2)
 	}
+	return cmp, nil
 }
 
 // executeDateTimeMethod implements .datetime() and related methods.
@@ -164,7 +67,9 @@ func compareTimestampTZ(_ context.Context, val1 *types.TimestampTZ, val2 any, us
 // unimplemented, so it instead returns an error.
 //
 // In all other cases, it calls [types.ParseTime], which attempts a number of
-// formats fitting ISO, and the first to succeed determines the type.
+// formats fitting ISO, and the first to succeed determines the type. The
+// PostgreSQL special input strings whose values depend on the current time,
+// such as "now", return an error.
 //
 // .time(), .time_tz(), .timestamp(), .timestamp_tz() take an optional time
 // precision.
@@ -180,7 +85,7 @@ func (exec *Executor) executeDateTimeMethod(
 	if !ok {
 		return exec.returnVerboseError(fmt.Errorf(
 			"%w: jsonpath item method %v() can only be applied to a string",
-			ErrVerbose, op,
+			errVerboseType, op,
 		))
 	}
 
@@ -240,7 +145,7 @@ func (exec *Executor) parseDateTimeFormat(_ string, _ ast.Node) error {
 	// XXX: Requires a format parser, so defer for now.
 	return fmt.Errorf(
 		"%w: .datetime(template) is not yet supported",
-		ErrExecution,
+		ErrDateTime,
 	)
 
 	// var str *ast.StringNode
@@ -256,7 +161,8 @@ func (exec *Executor) parseDateTimeFormat(_ string, _ ast.Node) error {
 
 // parseDateTime extracts an optional precision from arg, if it's not nil, the
 // passes it along with datetime to [types.ParseTime] to parse datetime and
-// apply precision to the resulting [types.DateTime] value.
+// apply precision to the resulting [types.DateTime] value. As in PostgreSQL,
+// a precision greater than 6 triggers a warning and is reduced to 6.
 func (exec *Executor) parseDateTime(
 	ctx context.Context,
 	op ast.UnaryOperator,
@@ -276,13 +182,16 @@ func (exec *Executor) parseDateTime(
 		if precision < 0 {
 			return nil, fmt.Errorf(
 				"%w: time precision of jsonpath item method %v() is invalid",
-				ErrVerbose, op,
+				errVerboseDateTime, op,
 			)
 		}
 
 		const maxTimestampPrecision = 6
 		if precision > maxTimestampPrecision {
-			// pg: issues a warning
+			exec.warning(ctx, WarnPrecisionReduced, fmt.Sprintf(
+				"%v precision reduced to maximum allowed, %v",
+				precisionTypeName(op, precision), maxTimestampPrecision,
+			))
 			precision = maxTimestampPrecision
 		}
 	}
@@ -290,92 +199,91 @@ func (exec *Executor) parseDateTime(
 	// Parse the value.
 	timeVal, ok := types.ParseTime(ctx, datetime, precision)
 	if !ok {
+		if types.IsVolatile(datetime) {
+			return nil, fmt.Errorf(
+				`%w: %v value "%v" depends on the current time and is not allowed in jsonpath`,
+				errVerboseDateTime, op.String()[1:], datetime,
+			)
+		}
 		return nil, fmt.Errorf(
 			`%w: %v format is not recognized: "%v"`,
-			ErrVerbose, op.String()[1:], datetime,
+			errVerboseDateTime, op.String()[1:], datetime,
 		)
 	}
 
 	return timeVal, nil
 }
 
+// precisionTypeName returns the name of the SQL type with precision to which
+// op converts values, for use in warnings.
+//
+//nolint:exhaustive // Only time and timestamp methods take a precision
+func precisionTypeName(op ast.UnaryOperator, precision int) string {
+	switch op {
+	case ast.UnaryTimeTZ:
+		return fmt.Sprintf("TIME(%v) WITH TIME ZONE", precision)
+	case ast.UnaryTimestamp:
+		return fmt.Sprintf("TIMESTAMP(%v)", precision)
+	case ast.UnaryTimestampTZ:
+		return fmt.Sprintf("TIMESTAMP(%v) WITH TIME ZONE", precision)
+	default:
+		return fmt.Sprintf("TIME(%v)", precision)
+	}
+}
+
 // notRecognized creates an error when the format of datetime is not able to
 // be parsed into a [types.DateTime].
 func notRecognized(op ast.UnaryOperator, datetime string) error {
 	return fmt.Errorf(
 		`%w: %v format is not recognized: "%v"`,
-		ErrVerbose, op.String()[1:], datetime,
+		errVerboseDateTime, op.String()[1:], datetime,
 	)
 }
 
+// castError converts err, returned by a [types] conversion function for
+// timeVal, into an execution error. The op and datetime params are used in
+// error messages.
+func castError(err error, op ast.UnaryOperator, timeVal types.DateTime, datetime string) error {
+	var convErr *types.ConversionError
+	switch {
+	case !errors.As(err, &convErr):
+		return fmt.Errorf("%w: type %T not supported", ErrInvalid, timeVal)
+	case convErr.TZRequired:
+		return tzRequiredCast(convErr.From, convErr.To)
+	default:
+		// Incompatible.
+		return notRecognized(op, datetime)
+	}
+}
+
 // castDate casts timeVal to [types.Date]. The datetime param is used in error
 // messages.
 func (exec *Executor) castDate(ctx context.Context, timeVal types.DateTime, datetime string) (*types.Date, error) {
-	// Convert result type to date
-	switch tv := timeVal.(type) {
-	case *types.Date:
-		// Nothing to do for DATE
-		return tv, nil
-	case *types.Time, *types.TimeTZ:
-		// Incompatible.
-		return nil, notRecognized(ast.UnaryDate, datetime)
-	case *types.Timestamp:
-		return tv.ToDate(ctx), nil
-	case *types.TimestampTZ:
-		if !exec.useTZ {
-			return nil, tzRequiredCast("timestamptz", "date")
-		}
-		return tv.ToDate(ctx), nil
-	default:
-		return nil, fmt.Errorf("%w: type %T not supported", ErrInvalid, tv)
+	d, err := types.ToDate(ctx, timeVal, exec.useTZ)
+	if err != nil {
+		return nil, castError(err, ast.UnaryDate, timeVal, datetime)
 	}
+	return d, nil
 }
 
 // castTime casts timeVal to [types.Time]. The datetime param is used in error
 // messages.
 func (exec *Executor) castTime(ctx context.Context, timeVal types.DateTime, datetime string) (*types.Time, error) {
-	switch tv := timeVal.(type) {
-	case *types.Date:
-		return nil, notRecognized(ast.UnaryTime, datetime)
-	case *types.Time:
-		// Nothing to do for time
-		return tv, nil
-	case *types.TimeTZ:
-		if !exec.useTZ {
-			return nil, tzRequiredCast("timetz", "time")
-		}
-		return tv.ToTime(ctx), nil
-	case *types.Timestamp:
-		return tv.ToTime(ctx), nil
-	case *types.TimestampTZ:
-		if !exec.useTZ {
-			return nil, tzRequiredCast("timestamptz", "time")
-		}
-		return tv.ToTime(ctx), nil
-	default:
-		return nil, fmt.Errorf("%w: type %T not supported", ErrInvalid, tv)
+	t, err := types.ToTime(ctx, timeVal, exec.useTZ)
+	if err != nil {
+		return nil, castError(err, ast.UnaryTime, timeVal, datetime)
 	}
+	return t, nil
 }
 
 // castTimeTZ casts timeVal to [types.TimeTZ]. The datetime param is used in
 // error messages.
 func (exec *Executor) castTimeTZ(ctx context.Context, timeVal types.DateTime, datetime string) (*types.TimeTZ, error) {
-	switch tv := timeVal.(type) {
-	case *types.Date, *types.Timestamp:
-		return nil, notRecognized(ast.UnaryTimeTZ, datetime)
-	case *types.Time:
-		if !exec.useTZ {
-			return nil, tzRequiredCast("time", "timetz")
-		}
-		return tv.ToTimeTZ(ctx), nil
-	case *types.TimeTZ:
-		// Nothing to do for TIMETZ
-		return tv, nil
-	case *types.TimestampTZ:
-		return tv.ToTimeTZ(ctx), nil
-	default:
-		return nil, fmt.Errorf("%w: type %T not supported", ErrInvalid, tv)
+	t, err := types.ToTimeTZ(ctx, timeVal, exec.useTZ)
+	if err != nil {
+		return nil, castError(err, ast.UnaryTimeTZ, timeVal, datetime)
 	}
+	return t, nil
 }
 
 // castTimestamp casts timeVal to [types.Timestamp]. The datetime param is
@@ -385,22 +293,11 @@ func (exec *Executor) castTimestamp(
 	timeVal types.DateTime,
 	datetime string,
 ) (*types.Timestamp, error) {
-	switch tv := timeVal.(type) {
-	case *types.Date:
-		return tv.ToTimestamp(ctx), nil
-	case *types.Time, *types.TimeTZ:
-		return nil, notRecognized(ast.UnaryTimestamp, datetime)
-	case *types.Timestamp:
-		// Nothing to do for TIMESTAMP
-		return tv, nil
-	case *types.TimestampTZ:
-		if !exec.useTZ {
-			return nil, tzRequiredCast("timestamptz", "timestamp")
-		}
-		return tv.ToTimestamp(ctx), nil
-	default:
-		return nil, fmt.Errorf("%w: type %T not supported", ErrInvalid, tv)
+	ts, err := types.ToTimestamp(ctx, timeVal, exec.useTZ)
+	if err != nil {
+		return nil, castError(err, ast.UnaryTimestamp, timeVal, datetime)
 	}
+	return ts, nil
 }
 
 // castTimestampTZ casts timeVal to [types.TimestampTZ]. The datetime param is
@@ -410,23 +307,64 @@ func (exec *Executor) castTimestampTZ(
 	timeVal types.DateTime,
 	datetime string,
 ) (*types.TimestampTZ, error) {
-	switch tv := timeVal.(type) {
-	case *types.Date:
-		if !exec.useTZ {
-			return nil, tzRequiredCast("date", "timestamptz")
+	ts, err := types.ToTimestampTZ(ctx, timeVal, exec.useTZ)
+	if err != nil {
+		return nil, castError(err, ast.UnaryTimestampTZ, timeVal, datetime)
+	}
+	return ts, nil
+}
+
+// dateTimeOutput specifies the form in which results materialize date and
+// time values.
+type dateTimeOutput uint8
+
+const (
+	// dateTimeOutputTypes materializes [types.DateTime] values.
+	dateTimeOutputTypes dateTimeOutput = iota
+	// dateTimeOutputTime materializes [time.Time] values.
+	dateTimeOutputTime
+	// dateTimeOutputString materializes RFC 3339 strings.
+	dateTimeOutputString
+)
+
+// displayDateTime converts dt to exec.displayLoc if it is a timestamp with
+// time zone and [WithDisplayTZ] was specified. Otherwise returns dt.
+func (exec *Executor) displayDateTime(dt types.DateTime) types.DateTime {
+	ts, ok := dt.(*types.TimestampTZ)
+	if !ok || exec.displayLoc == nil {
+		return dt
+	}
+	ctx := types.ContextWithTZ(context.Background(), exec.displayLoc)
+	return types.NewTimestampTZ(ctx, ts.In(exec.displayLoc))
+}
+
+// outputDateTime converts dt to the form specified by [WithTimeValues] or
+// [WithStringDateTime], after converting it as specified by
+// [WithDisplayTZ]. Returns dt if none was specified.
+func (exec *Executor) outputDateTime(dt types.DateTime) any {
+	dt = exec.displayDateTime(dt)
+	switch exec.dateTimeOutput {
+	case dateTimeOutputTime:
+		switch dt := dt.(type) {
+		case *types.TimeTZ:
+			return dt.GoTime()
+		default:
+			return dt.GoTime().UTC()
 		}
-		return tv.ToTimestampTZ(ctx), nil
-	case *types.Time, *types.TimeTZ:
-		return nil, notRecognized(ast.UnaryTimestampTZ, datetime)
-	case *types.Timestamp:
-		if !exec.useTZ {
-			return nil, tzRequiredCast("timestamp", "timestamptz")
+	case dateTimeOutputString:
+		switch dt := dt.(type) {
+		case *types.Date:
+			return dt.GoTime().Format(time.DateOnly)
+		case *types.Time:
+			return dt.GoTime().Format("15:04:05.999999999")
+		case *types.TimeTZ:
+			return dt.GoTime().Format("15:04:05.999999999Z07:00")
+		case *types.Timestamp:
+			return dt.GoTime().UTC().Format(time.RFC3339Nano)
+		default:
+			return dt.GoTime().Format(time.RFC3339Nano)
 		}
-		return tv.ToTimestampTZ(ctx), nil
-	case *types.TimestampTZ:
-		// Nothing to do for TIMESTAMPTZ
-		return tv, nil
-	default:
-		return nil, fmt.Errorf("%w: type %T not supported", ErrInvalid, tv)
+	case dateTimeOutputTypes:
 	}
+	return dt
 }
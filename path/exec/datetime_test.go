@@ -244,7 +244,6 @@ func TestCompareDatetime(t *testing.T) {
 
 func TestCompareDate(t *testing.T) {
 	t.Parallel()
-	a := assert.New(t)
 	moment := stableTime()
 	ctx := context.Background()
 
@@ -286,17 +285,21 @@ func TestCompareDate(t *testing.T) {
 			exp:  -2,
 		},
 		{
-			name: "unknown_type",
+			name: "not_datetime",
 			val1: types.NewDate(moment),
 			val2: "not a timestamp",
-			err:  errors.New("exec invalid: unrecognized SQL/JSON datetime type string"),
+			exp:  -2,
+		},
+		{
+			name: "unknown_type",
+			val1: types.NewDate(moment),
+			val2: mockDateTime{},
+			err:  errors.New("exec invalid: type: unsupported DateTime type exec.mockDateTime"),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			val1, ok := tc.val1.(*types.Date)
-			a.True(ok)
-			res, err := compareDate(ctx, val1, tc.val2, tc.useTZ)
+			res, err := compareDatetime(ctx, tc.val1, tc.val2, tc.useTZ)
 			tc.checkCompare(t, res, err)
 		})
 	}
@@ -304,7 +307,6 @@ func TestCompareDate(t *testing.T) {
 
 func TestCompareTime(t *testing.T) {
 	t.Parallel()
-	a := assert.New(t)
 	r := require.New(t)
 
 	moment := stableTime()
@@ -350,17 +352,21 @@ func TestCompareTime(t *testing.T) {
 			exp:  -2,
 		},
 		{
-			name: "unknown_type",
+			name: "not_datetime",
 			val1: types.NewTime(moment),
 			val2: "not a timestamp",
-			err:  errors.New("exec invalid: unrecognized SQL/JSON datetime type string"),
+			exp:  -2,
+		},
+		{
+			name: "unknown_type",
+			val1: types.NewTime(moment),
+			val2: mockDateTime{},
+			err:  errors.New("exec invalid: type: unsupported DateTime type exec.mockDateTime"),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			val1, ok := tc.val1.(*types.Time)
-			a.True(ok)
-			res, err := compareTime(ctx, val1, tc.val2, tc.useTZ)
+			res, err := compareDatetime(ctx, tc.val1, tc.val2, tc.useTZ)
 			tc.checkCompare(t, res, err)
 		})
 	}
@@ -368,7 +374,6 @@ func TestCompareTime(t *testing.T) {
 
 func TestCompareTimeTZ(t *testing.T) {
 	t.Parallel()
-	a := assert.New(t)
 	r := require.New(t)
 
 	moment := stableTime()
@@ -414,17 +419,21 @@ func TestCompareTimeTZ(t *testing.T) {
 			exp:  -2,
 		},
 		{
-			name: "unknown_type",
+			name: "not_datetime",
 			val1: types.NewTimeTZ(moment),
 			val2: "not a timestamp",
-			err:  errors.New("exec invalid: unrecognized SQL/JSON datetime type string"),
+			exp:  -2,
+		},
+		{
+			name: "unknown_type",
+			val1: types.NewTimeTZ(moment),
+			val2: mockDateTime{},
+			err:  errors.New("exec invalid: type: unsupported DateTime type exec.mockDateTime"),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			val1, ok := tc.val1.(*types.TimeTZ)
-			a.True(ok)
-			res, err := compareTimeTZ(ctx, val1, tc.val2, tc.useTZ)
+			res, err := compareDatetime(ctx, tc.val1, tc.val2, tc.useTZ)
 			tc.checkCompare(t, res, err)
 		})
 	}
@@ -432,7 +441,6 @@ func TestCompareTimeTZ(t *testing.T) {
 
 func TestCompareTimestamp(t *testing.T) {
 	t.Parallel()
-	a := assert.New(t)
 	moment := stableTime()
 	ctx := context.Background()
 
@@ -473,17 +481,21 @@ func TestCompareTimestamp(t *testing.T) {
 			exp:  -2,
 		},
 		{
-			name: "unknown_type",
+			name: "not_datetime",
 			val1: types.NewTimestamp(moment),
 			val2: "not a timestamp",
-			err:  errors.New("exec invalid: unrecognized SQL/JSON datetime type string"),
+			exp:  -2,
+		},
+		{
+			name: "unknown_type",
+			val1: types.NewTimestamp(moment),
+			val2: mockDateTime{},
+			err:  errors.New("exec invalid: type: unsupported DateTime type exec.mockDateTime"),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			val1, ok := tc.val1.(*types.Timestamp)
-			a.True(ok)
-			res, err := compareTimestamp(ctx, val1, tc.val2, tc.useTZ)
+			res, err := compareDatetime(ctx, tc.val1, tc.val2, tc.useTZ)
 			tc.checkCompare(t, res, err)
 		})
 	}
@@ -491,7 +503,6 @@ func TestCompareTimestamp(t *testing.T) {
 
 func TestCompareTimestampTZ(t *testing.T) {
 	t.Parallel()
-	a := assert.New(t)
 	moment := stableTime()
 	ctx := context.Background()
 
@@ -539,17 +550,21 @@ func TestCompareTimestampTZ(t *testing.T) {
 			exp:  -2,
 		},
 		{
-			name: "unknown_type",
+			name: "not_datetime",
 			val1: types.NewTimestampTZ(ctx, moment),
 			val2: "not a timestamp",
-			err:  errors.New("exec invalid: unrecognized SQL/JSON datetime type string"),
+			exp:  -2,
+		},
+		{
+			name: "unknown_type",
+			val1: types.NewTimestampTZ(ctx, moment),
+			val2: mockDateTime{},
+			err:  errors.New("exec invalid: type: unsupported DateTime type exec.mockDateTime"),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			val1, ok := tc.val1.(*types.TimestampTZ)
-			a.True(ok)
-			res, err := compareTimestampTZ(ctx, val1, tc.val2, tc.useTZ)
+			res, err := compareDatetime(ctx, tc.val1, tc.val2, tc.useTZ)
 			tc.checkCompare(t, res, err)
 		})
 	}
@@ -596,6 +611,14 @@ func TestExecuteDateTimeMethod(t *testing.T) {
 			err:   `exec: datetime format is not recognized: "nope"`,
 			isErr: ErrExecution,
 		},
+		{
+			name:  "datetime_unknown_zone",
+			node:  ast.NewUnary(ast.UnaryDateTime, nil),
+			value: "2024-06-05 12:34:56 XYZ",
+			exp:   statusFailed,
+			err:   `exec: datetime format is not recognized: "2024-06-05 12:34:56 XYZ"`,
+			isErr: ErrExecution,
+		},
 		{
 			name:   "datetime_parse_failure_silent",
 			node:   ast.NewUnary(ast.UnaryDateTime, nil),
@@ -844,6 +867,7 @@ func TestParseDateTime(t *testing.T) {
 		value string
 		arg   ast.Node
 		exp   types.DateTime
+		warn  []string
 		err   string
 		isErr error
 	}{
@@ -867,6 +891,38 @@ func TestParseDateTime(t *testing.T) {
 			arg:   ast.NewInteger("9"),
 			value: "14:15:31.78599685301",
 			exp:   types.NewTime(time.Date(0, 1, 1, 14, 15, 31, 785997000, time.UTC)),
+			warn:  []string{"TIME(9) precision reduced to maximum allowed, 6"},
+		},
+		{
+			name:  "max_precision_time_tz",
+			op:    ast.UnaryTimeTZ,
+			arg:   ast.NewInteger("7"),
+			value: "14:15:31.7859968Z",
+			exp:   types.NewTimeTZ(time.Date(0, 1, 1, 14, 15, 31, 785997000, time.UTC)),
+			warn:  []string{"TIME(7) WITH TIME ZONE precision reduced to maximum allowed, 6"},
+		},
+		{
+			name:  "max_precision_timestamp",
+			op:    ast.UnaryTimestamp,
+			arg:   ast.NewInteger("12"),
+			value: "2024-06-05 14:15:31.7859968",
+			exp:   types.NewTimestamp(time.Date(2024, 6, 5, 14, 15, 31, 785997000, time.UTC)),
+			warn:  []string{"TIMESTAMP(12) precision reduced to maximum allowed, 6"},
+		},
+		{
+			name:  "max_precision_timestamp_tz",
+			op:    ast.UnaryTimestampTZ,
+			arg:   ast.NewInteger("7"),
+			value: "2024-06-05 14:15:31.7859968Z",
+			exp:   types.NewTimestampTZ(ctx, time.Date(2024, 6, 5, 14, 15, 31, 785997000, time.UTC)),
+			warn:  []string{"TIMESTAMP(7) WITH TIME ZONE precision reduced to maximum allowed, 6"},
+		},
+		{
+			name:  "precision_six",
+			op:    ast.UnaryTime,
+			arg:   ast.NewInteger("6"),
+			value: "14:15:31.78599685301",
+			exp:   types.NewTime(time.Date(0, 1, 1, 14, 15, 31, 785997000, time.UTC)),
 		},
 		{
 			name:  "precision_three",
@@ -888,8 +944,11 @@ func TestParseDateTime(t *testing.T) {
 
 			// Test parseDateTime.
 			e := newTestExecutor(path, nil, true, false)
+			var warnings []string
+			e.warn = func(msg string) { warnings = append(warnings, msg) }
 			res, err := e.parseDateTime(ctx, tc.op, tc.value, tc.arg)
 			a.Equal(tc.exp, res)
+			a.Equal(tc.warn, warnings)
 
 			// Check the error.
 			if tc.isErr == nil {
@@ -976,8 +1035,9 @@ func (tc testDatetimeCast) run(t *testing.T, cast func(*Executor) (types.DateTim
 // To test the handling of unknown types.DateTime types.
 type mockDateTime struct{}
 
-func (mockDateTime) GoTime() time.Time { return time.Now() }
-func (mockDateTime) String() string    { return "" }
+func (mockDateTime) GoTime() time.Time                  { return time.Now() }
+func (mockDateTime) String() string                     { return "" }
+func (mockDateTime) FormatStyle(types.DateStyle) string { return "" }
 func TestCastDate(t *testing.T) {
 	t.Parallel()
 	moment := stableTime()
@@ -1309,3 +1369,312 @@ func TestCastTimestampTZ(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeZoneTransitions(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+	a := assert.New(t)
+
+	for _, zone := range []struct {
+		name  string
+		cases []queryTestCase
+	}{
+		{
+			name: "America/New_York",
+			cases: []queryTestCase{
+				{
+					name: "spring_forward",
+					json: js(`"2024-03-10 02:30:00"`),
+					path: `$.timestamp_tz().string()`,
+					exp:  []any{"2024-03-10T03:30:00-04:00"},
+				},
+				{
+					name: "fall_back",
+					json: js(`"2024-11-03 01:30:00"`),
+					path: `$.timestamp_tz().string()`,
+					exp:  []any{"2024-11-03T01:30:00-05:00"},
+				},
+				{
+					name: "compare_date",
+					json: js(`["2024-03-10", "2024-03-10 05:00:00Z"]`),
+					path: `$[0].date() == $[1].timestamp_tz()`,
+					exp:  []any{true},
+				},
+				{
+					name: "compare_spring_forward",
+					json: js(`["2024-03-10 02:30:00", "2024-03-10 03:30:00-04"]`),
+					path: `$[0].timestamp() == $[1].timestamp_tz()`,
+					exp:  []any{true},
+				},
+				{
+					name: "compare_fall_back",
+					json: js(`["2024-11-03 01:30:00", "2024-11-03 06:30:00Z"]`),
+					path: `$[0].timestamp() == $[1].timestamp_tz()`,
+					exp:  []any{true},
+				},
+			},
+		},
+		{
+			name: "Australia/Lord_Howe",
+			cases: []queryTestCase{
+				{
+					name: "spring_forward",
+					json: js(`"2024-10-06 02:15:00"`),
+					path: `$.timestamp_tz().string()`,
+					exp:  []any{"2024-10-06T02:45:00+11:00"},
+				},
+				{
+					name: "fall_back",
+					json: js(`"2024-04-07 01:45:00"`),
+					path: `$.timestamp_tz().string()`,
+					exp:  []any{"2024-04-07T01:45:00+10:30"},
+				},
+				{
+					name: "compare_fall_back",
+					json: js(`["2024-04-07 01:45:00", "2024-04-06 15:15:00Z"]`),
+					path: `$[0].timestamp() == $[1].timestamp_tz()`,
+					exp:  []any{true},
+				},
+			},
+		},
+	} {
+		loc, err := time.LoadLocation(zone.name)
+		r.NoError(err)
+		ctx := types.ContextWithTZ(context.Background(), loc)
+
+		for _, tc := range zone.cases {
+			tc.opt = []Option{WithTZ()}
+			t.Run(zone.name+"/"+tc.name, func(t *testing.T) {
+				t.Parallel()
+				tc.run(ctx, a, r)
+			})
+		}
+	}
+}
+
+func TestTimeTZOrdering(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+	a := assert.New(t)
+	ctx := types.ContextWithTZ(context.Background(), time.FixedZone("", 0))
+	json := js(`["12:35:00+01", "11:35:00+00", "13:35:00+02", "00:30:00+01", "23:30:00-01", "00:00:00+00"]`)
+
+	// Like PostgreSQL, compare timetz values by their UTC equivalents, without
+	// wrapping around midnight, and then by offset, so that values with the
+	// same UTC equivalent are equal only when their offsets are equal, and
+	// greater offsets sort first.
+	for _, tc := range []queryTestCase{
+		{
+			name: "equal_utc",
+			json: json,
+			path: `$[*].time_tz() ? (@ == "11:35:00+00".time_tz())`,
+			exp:  []any{pt(ctx, "11:35:00+00:00")},
+		},
+		{
+			name: "less_than_same_utc",
+			json: json,
+			path: `$[*].time_tz() ? (@ < "11:35:00+00".time_tz())`,
+			exp: []any{
+				pt(ctx, "12:35:00+01:00"), pt(ctx, "13:35:00+02:00"),
+				pt(ctx, "00:30:00+01:00"), pt(ctx, "00:00:00+00:00"),
+			},
+		},
+		{
+			name: "greater_than_same_utc",
+			json: json,
+			path: `$[*].time_tz() ? (@ > "12:35:00+01".time_tz())`,
+			exp:  []any{pt(ctx, "11:35:00+00:00"), pt(ctx, "23:30:00-01:00")},
+		},
+		{
+			name: "before_midnight_utc",
+			json: json,
+			path: `$[*].time_tz() ? (@ < "00:00:00+00".time_tz())`,
+			exp:  []any{pt(ctx, "00:30:00+01:00")},
+		},
+		{
+			name: "after_midnight_utc",
+			json: json,
+			path: `$[*].time_tz() ? (@ > "23:30:00+00".time_tz())`,
+			exp:  []any{pt(ctx, "23:30:00-01:00")},
+		},
+		{
+			name: "time",
+			json: json,
+			path: `$[*].time_tz() ? (@ == "11:35:00".time())`,
+			opt:  []Option{WithTZ()},
+			exp:  []any{pt(ctx, "11:35:00+00:00")},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.run(ctx, a, r)
+		})
+	}
+}
+
+func TestSpecialDateTimeInputs(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+	a := assert.New(t)
+	ctx := types.ContextWithTZ(context.Background(), time.UTC)
+
+	for _, tc := range []queryTestCase{
+		// Julian day numbers are dates.
+		{
+			name: "julian_datetime",
+			json: js(`"J2451187"`),
+			path: `$.datetime().string()`,
+			exp:  []any{"1999-01-08"},
+		},
+		{
+			name: "julian_type",
+			json: js(`"j2451545"`),
+			path: `$.datetime().type()`,
+			exp:  []any{"date"},
+		},
+		{
+			name: "julian_date",
+			json: js(`"J2451545"`),
+			path: `$.date().string()`,
+			exp:  []any{"2000-01-01"},
+		},
+		{
+			name: "julian_zero",
+			json: js(`"J0"`),
+			path: `$.date().string()`,
+			exp:  []any{"4714-11-24 BC"},
+		},
+		{
+			name: "julian_timestamp",
+			json: js(`"J2451187"`),
+			path: `$.timestamp().string()`,
+			exp:  []any{"1999-01-08T00:00:00"},
+		},
+		{
+			name: "julian_time",
+			json: js(`"J2451187"`),
+			path: `$.time()`,
+			err:  `exec: time format is not recognized: "J2451187"`,
+		},
+		{
+			name: "julian_out_of_range",
+			json: js(`"J2147483494"`),
+			path: `$.date()`,
+			err:  `exec: date format is not recognized: "J2147483494"`,
+		},
+		{
+			name: "julian_not_number",
+			json: js(`"J12x"`),
+			path: `$.datetime()`,
+			err:  `exec: datetime format is not recognized: "J12x"`,
+		},
+
+		// epoch is a timestamp.
+		{
+			name: "epoch_datetime",
+			json: js(`"epoch"`),
+			path: `$.datetime().string()`,
+			exp:  []any{"1970-01-01T00:00:00"},
+		},
+		{
+			name: "epoch_type",
+			json: js(`"EPOCH"`),
+			path: `$.datetime().type()`,
+			exp:  []any{"timestamp without time zone"},
+		},
+		{
+			name: "epoch_date",
+			json: js(`"epoch"`),
+			path: `$.date().string()`,
+			exp:  []any{"1970-01-01"},
+		},
+		{
+			name: "epoch_time",
+			json: js(`"epoch"`),
+			path: `$.time().string()`,
+			exp:  []any{"00:00:00"},
+		},
+		{
+			name: "epoch_timestamp",
+			json: js(`"epoch"`),
+			path: `$.timestamp().string()`,
+			exp:  []any{"1970-01-01T00:00:00"},
+		},
+		{
+			name: "epoch_compare",
+			json: js(`["epoch", "1970-01-01 00:00:00"]`),
+			path: `$[0].datetime() == $[1].datetime()`,
+			exp:  []any{true},
+		},
+
+		// allballs is a time.
+		{
+			name: "allballs_datetime",
+			json: js(`"allballs"`),
+			path: `$.datetime().string()`,
+			exp:  []any{"00:00:00"},
+		},
+		{
+			name: "allballs_type",
+			json: js(`"AllBalls"`),
+			path: `$.datetime().type()`,
+			exp:  []any{"time without time zone"},
+		},
+		{
+			name: "allballs_time",
+			json: js(`"allballs"`),
+			path: `$.time().string()`,
+			exp:  []any{"00:00:00"},
+		},
+		{
+			name: "allballs_date",
+			json: js(`"allballs"`),
+			path: `$.date()`,
+			err:  `exec: date format is not recognized: "allballs"`,
+		},
+		{
+			name: "allballs_timestamp",
+			json: js(`"allballs"`),
+			path: `$.timestamp()`,
+			err:  `exec: timestamp format is not recognized: "allballs"`,
+		},
+
+		// Values that depend on the current time are not allowed.
+		{
+			name: "now_datetime",
+			json: js(`"now"`),
+			path: `$.datetime()`,
+			err:  `exec: datetime value "now" depends on the current time and is not allowed in jsonpath`,
+		},
+		{
+			name: "today_date",
+			json: js(`"today"`),
+			path: `$.date()`,
+			err:  `exec: date value "today" depends on the current time and is not allowed in jsonpath`,
+		},
+		{
+			name: "tomorrow_time",
+			json: js(`"Tomorrow"`),
+			path: `$.time()`,
+			err:  `exec: time value "Tomorrow" depends on the current time and is not allowed in jsonpath`,
+		},
+		{
+			name: "yesterday_timestamp",
+			json: js(`"YESTERDAY"`),
+			path: `$.timestamp()`,
+			err:  `exec: timestamp value "YESTERDAY" depends on the current time and is not allowed in jsonpath`,
+		},
+		{
+			name: "now_silent",
+			json: js(`"now"`),
+			path: `$.datetime()`,
+			opt:  []Option{WithSilent()},
+			exp:  []any{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.run(ctx, a, r)
+		})
+	}
+}
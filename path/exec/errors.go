@@ -0,0 +1,70 @@
+package exec
+
+import "errors"
+
+// Execution error categories. Errors returned by execution that wrap
+// [ErrExecution] wrap at most one of these categories, so that callers can
+// use [errors.Is] to distinguish, e.g., a missing key from a type mismatch,
+// without parsing error messages. Errors in these categories that PostgreSQL
+// suppresses in silent mode also wrap [ErrVerbose]; use [IsSuppressible] to
+// check for them.
+//
+//nolint:gochecknoglobals
+var (
+	// ErrStructural errors denote a JSON value that lacks the structure the
+	// path expects, such as a missing object key, an array subscript out of
+	// bounds, or an accessor applied to the wrong kind of container.
+	ErrStructural = &categoryError{wraps: []error{ErrExecution}}
+
+	// ErrType errors denote a JSON item of a type an operator or method
+	// does not support, such as a string operand to an arithmetic operator
+	// or a .size() applied to an object.
+	ErrType = &categoryError{wraps: []error{ErrExecution}}
+
+	// ErrNumeric errors denote invalid numeric operations and conversions,
+	// such as division by zero, overflow, or an argument to .integer()
+	// that is not a valid integer.
+	ErrNumeric = &categoryError{wraps: []error{ErrExecution}}
+
+	// ErrDateTime errors denote invalid datetime values and operations,
+	// such as a string .datetime() cannot parse or a comparison that
+	// requires a time zone when [WithTZ] is not specified.
+	ErrDateTime = &categoryError{wraps: []error{ErrExecution}}
+
+	// ErrVariable errors denote a reference to a variable that is not
+	// defined, or variables that cannot be used, such as a non-object
+	// passed to [WithVarsFrom] or a function passed to [WithVars].
+	ErrVariable = &categoryError{wraps: []error{ErrExecution}}
+)
+
+// Suppressible variants of the error categories, which wrap both the
+// category and [ErrVerbose].
+//
+//nolint:gochecknoglobals
+var (
+	errVerboseStructural = &categoryError{wraps: []error{ErrStructural, ErrVerbose}}
+	errVerboseType       = &categoryError{wraps: []error{ErrType, ErrVerbose}}
+	errVerboseNumeric    = &categoryError{wraps: []error{ErrNumeric, ErrVerbose}}
+	errVerboseDateTime   = &categoryError{wraps: []error{ErrDateTime, ErrVerbose}}
+)
+
+// categoryError is an execution error category. It wraps one or more
+// errors, and its message is that of [ErrExecution], so that errors that
+// wrap it have the same messages as errors that wrap [ErrExecution].
+type categoryError struct {
+	wraps []error
+}
+
+// Error returns the [ErrExecution] message.
+func (e *categoryError) Error() string { return ErrExecution.Error() }
+
+// Unwrap returns the errors e wraps.
+func (e *categoryError) Unwrap() []error { return e.wraps }
+
+// IsSuppressible returns true if err is an execution error that
+// [WithSilent] suppresses, that is, one that wraps [ErrVerbose]. Other
+// execution errors, such as references to undefined variables, are
+// returned even in silent mode, as in PostgreSQL.
+func IsSuppressible(err error) bool {
+	return errors.Is(err, ErrVerbose)
+}
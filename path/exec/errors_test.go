@@ -0,0 +1,398 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestErrorCategories(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	categories := []error{ErrStructural, ErrType, ErrNumeric, ErrDateTime, ErrVariable}
+
+	for _, tc := range []struct {
+		name     string
+		path     string
+		value    any
+		opt      []Option
+		err      string
+		category error
+		silent   bool
+	}{
+		{
+			name:     "missing_key",
+			path:     "strict $.a",
+			value:    map[string]any{},
+			err:      `exec: JSON object does not contain key "a"`,
+			category: ErrStructural,
+			silent:   true,
+		},
+		{
+			name:     "index_out_of_bounds",
+			path:     "strict $[3]",
+			value:    []any{int64(1)},
+			err:      "exec: jsonpath array subscript is out of bounds",
+			category: ErrStructural,
+			silent:   true,
+		},
+		{
+			name:     "member_accessor",
+			path:     "strict $.a",
+			value:    []any{},
+			err:      "exec: jsonpath member accessor can only be applied to an object",
+			category: ErrStructural,
+			silent:   true,
+		},
+		{
+			name:     "method_type",
+			path:     "strict $.size()",
+			value:    map[string]any{},
+			err:      "exec: jsonpath item method .size() can only be applied to an array",
+			category: ErrType,
+			silent:   true,
+		},
+		{
+			name:     "operand_type",
+			path:     `$ + 1`,
+			value:    "x",
+			err:      "exec: left operand of jsonpath operator + is not a single numeric value",
+			category: ErrType,
+			silent:   true,
+		},
+		{
+			name:     "division_by_zero",
+			path:     "$ / 0",
+			value:    int64(1),
+			err:      "exec: division by zero",
+			category: ErrNumeric,
+			silent:   true,
+		},
+		{
+			name:     "invalid_integer",
+			path:     "$.integer()",
+			value:    "hi",
+			err:      `exec: argument "hi" of jsonpath item method .integer() is invalid for type integer`,
+			category: ErrNumeric,
+			silent:   true,
+		},
+		{
+			name:     "datetime_format",
+			path:     "$.datetime()",
+			value:    "hi",
+			err:      `exec: datetime format is not recognized: "hi"`,
+			category: ErrDateTime,
+			silent:   true,
+		},
+		{
+			name:     "datetime_tz",
+			path:     `$.datetime() < "2024-01-01T00:00:00Z".datetime()`,
+			value:    "2024-01-01",
+			err:      "exec: cannot convert value from date to timestamptz without time zone usage. HINT: Use WithTZ() option for time zone support",
+			category: ErrDateTime,
+		},
+		{
+			name:     "undefined_variable",
+			path:     "$x",
+			value:    int64(1),
+			err:      `exec: could not find jsonpath variable "x"`,
+			category: ErrVariable,
+		},
+		{
+			name:     "vars_from",
+			path:     "$x",
+			value:    int64(1),
+			opt:      []Option{WithVarsFrom([]int{1})},
+			err:      "exec: cannot use []int as variables: not an object",
+			category: ErrVariable,
+		},
+		{
+			name:     "unsupported_variable",
+			path:     "$x",
+			value:    int64(1),
+			opt:      []Option{WithVars(Vars{"x": func() {}})},
+			err:      `exec: variable "x" has unsupported type func()`,
+			category: ErrVariable,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			_, err = Query(ctx, path, tc.value, tc.opt...)
+			r.EqualError(err, tc.err)
+			r.ErrorIs(err, ErrExecution)
+			r.ErrorIs(err, tc.category)
+			for _, cat := range categories {
+				if cat != tc.category {
+					a.NotErrorIs(err, cat)
+				}
+			}
+			a.Equal(tc.silent, IsSuppressible(err))
+			a.Equal(tc.silent, errors.Is(err, ErrVerbose))
+
+			// WithSilent should suppress exactly the suppressible errors.
+			_, err = Query(ctx, path, tc.value, append(tc.opt, WithSilent())...)
+			if tc.silent {
+				r.NoError(err)
+			} else {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, tc.category)
+			}
+		})
+	}
+}
+
+func TestIsSuppressible(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.True(IsSuppressible(ErrVerbose))
+	a.True(IsSuppressible(errVerboseStructural))
+	a.True(IsSuppressible(errVerboseType))
+	a.True(IsSuppressible(errVerboseNumeric))
+	a.True(IsSuppressible(errVerboseDateTime))
+	a.False(IsSuppressible(nil))
+	a.False(IsSuppressible(ErrExecution))
+	a.False(IsSuppressible(ErrVariable))
+	a.False(IsSuppressible(ErrInvalid))
+	a.False(IsSuppressible(NULL))
+	a.False(IsSuppressible(context.Canceled))
+
+	for _, err := range []error{ErrStructural, ErrType, ErrNumeric, ErrDateTime, ErrVariable} {
+		a.Equal("exec", err.Error())
+		a.ErrorIs(err, ErrExecution)
+		a.False(IsSuppressible(err))
+	}
+}
+
+func TestSilentMode(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// Mirrors PostgreSQL, where silent mode suppresses the errors that
+	// jsonpath_exec.c raises with RETURN_ERROR(), but not those it raises
+	// directly, such as for missing variables and time zone casts. Errors
+	// inside filters always make the predicate unknown, silent or not, except
+	// for the latter.
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		// error without WithSilent
+		err string
+		// true if WithSilent also returns err
+		raise bool
+		// results in silent mode, or without err
+		exp []any
+	}{
+		// Suppressible
+		{
+			name:  "division_by_zero",
+			path:  `1 / $`,
+			value: js(`0`),
+			err:   "exec: division by zero",
+			exp:   []any{},
+		},
+		{
+			name:  "modulo_by_zero",
+			path:  `$ % 0`,
+			value: js(`1`),
+			err:   "exec: division by zero",
+			exp:   []any{},
+		},
+		{
+			name:  "subscript_out_of_int_range",
+			path:  `$[1e10]`,
+			value: js(`[1]`),
+			err:   "exec: jsonpath array subscript is out of integer range",
+			exp:   []any{},
+		},
+		{
+			name:  "subscript_range_out_of_int_range",
+			path:  `$[0 to 1e10]`,
+			value: js(`[1]`),
+			err:   "exec: jsonpath array subscript is out of integer range",
+			exp:   []any{},
+		},
+		{
+			name:  "subscript_out_of_bounds",
+			path:  `strict $[1]`,
+			value: js(`[1]`),
+			err:   "exec: jsonpath array subscript is out of bounds",
+			exp:   []any{},
+		},
+		{
+			name:  "missing_key",
+			path:  `strict $.a`,
+			value: js(`{}`),
+			err:   `exec: JSON object does not contain key "a"`,
+			exp:   []any{},
+		},
+		{
+			name:  "array_accessor",
+			path:  `strict $[0]`,
+			value: js(`{}`),
+			err:   "exec: jsonpath array accessor can only be applied to an array",
+			exp:   []any{},
+		},
+		{
+			name:  "wildcard_member_accessor",
+			path:  `strict $.*`,
+			value: js(`[1]`),
+			err:   "exec: jsonpath wildcard member accessor can only be applied to an object",
+			exp:   []any{},
+		},
+		{
+			name:  "binary_operand",
+			path:  `$ + 1`,
+			value: js(`"x"`),
+			err:   "exec: left operand of jsonpath operator + is not a single numeric value",
+			exp:   []any{},
+		},
+		{
+			name:  "unary_operand",
+			path:  `-$`,
+			value: js(`"x"`),
+			err:   "exec: operand of unary jsonpath operator - is not a numeric value",
+			exp:   []any{},
+		},
+		{
+			name:  "method_type",
+			path:  `$.keyvalue()`,
+			value: js(`1`),
+			err:   "exec: jsonpath item method .keyvalue() can only be applied to an object",
+			exp:   []any{},
+		},
+		{
+			name:  "double_argument",
+			path:  `$.double()`,
+			value: js(`"1.23aaa"`),
+			err:   `exec: argument "1.23aaa" of jsonpath item method .double() is invalid for type double precision`,
+			exp:   []any{},
+		},
+		{
+			name:  "bigint_argument",
+			path:  `$.bigint()`,
+			value: js(`"1e30"`),
+			err:   `exec: argument "1e30" of jsonpath item method .bigint() is invalid for type bigint`,
+			exp:   []any{},
+		},
+		{
+			name:  "decimal_argument",
+			path:  `$.decimal(5, 10)`,
+			value: js(`1`),
+			err:   `exec: argument "1" of jsonpath item method .decimal() is invalid for type numeric`,
+			exp:   []any{},
+		},
+		{
+			name:  "datetime_format",
+			path:  `$.datetime()`,
+			value: js(`"nope"`),
+			err:   `exec: datetime format is not recognized: "nope"`,
+			exp:   []any{},
+		},
+
+		// Not suppressible
+		{
+			name:  "missing_variable",
+			path:  `$x`,
+			value: js(`1`),
+			err:   `exec: could not find jsonpath variable "x"`,
+			raise: true,
+		},
+		{
+			name:  "missing_variable_in_filter",
+			path:  `$[*] ? (@ == $x)`,
+			value: js(`[1]`),
+			err:   `exec: could not find jsonpath variable "x"`,
+			raise: true,
+		},
+		{
+			name:  "missing_variable_in_exists",
+			path:  `exists($x)`,
+			value: js(`1`),
+			err:   `exec: could not find jsonpath variable "x"`,
+			raise: true,
+		},
+		{
+			name:  "tz_cast",
+			path:  `$.datetime() < "2024-01-01T00:00:00Z".datetime()`,
+			value: js(`"2024-01-01"`),
+			err:   "exec: cannot convert value from date to timestamptz without time zone usage. HINT: Use WithTZ() option for time zone support",
+			raise: true,
+		},
+		{
+			name:  "tz_cast_in_filter",
+			path:  `$[*] ? (@.datetime() < "2024-01-01T00:00:00Z".datetime())`,
+			value: js(`["2024-01-01"]`),
+			err:   "exec: cannot convert value from date to timestamptz without time zone usage. HINT: Use WithTZ() option for time zone support",
+			raise: true,
+		},
+		{
+			name:  "decimal_precision",
+			path:  `$.decimal(0)`,
+			value: js(`1`),
+			err:   "exec: NUMERIC precision 0 must be between 1 and 1000",
+			raise: true,
+		},
+
+		// Unknown inside filters
+		{
+			name:  "division_by_zero_in_filter",
+			path:  `$[*] ? (1 / @ > 0)`,
+			value: js(`[0, 1]`),
+			exp:   []any{float64(1)},
+		},
+		{
+			name:  "subscript_out_of_int_range_in_filter",
+			path:  `$ ? (@[1e10] == 1)`,
+			value: js(`[1]`),
+			exp:   []any{},
+		},
+		{
+			name:  "missing_key_in_filter",
+			path:  `strict $[*] ? (@.a == 1)`,
+			value: js(`[{}, {"a": 1}]`),
+			exp:   []any{map[string]any{"a": float64(1)}},
+		},
+		{
+			name:  "double_argument_in_filter",
+			path:  `$[*] ? (@.double() > 1)`,
+			value: js(`["x", "2"]`),
+			exp:   []any{"2"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := Query(ctx, path, tc.value)
+			if tc.err == "" {
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+			} else {
+				r.EqualError(err, tc.err)
+				a.Equal(!tc.raise, IsSuppressible(err))
+			}
+
+			res, err = Query(ctx, path, tc.value, WithSilent())
+			if tc.raise {
+				r.EqualError(err, tc.err)
+			} else {
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+			}
+		})
+	}
+}
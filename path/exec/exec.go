@@ -5,8 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/types"
 )
 
 // Things to improve or document as different:
@@ -23,6 +29,10 @@ import (
 //   - Implement interfaces to be compatible with the SQL-standard
 //     json_exists(), json_query(), and json_value() functions added in Postgres 17.
 
+// DefaultMaxDepth is the default maximum recursion depth of execution. See
+// [WithMaxDepth].
+const DefaultMaxDepth = 10_000
+
 // Vars represents JSON path variables and their values.
 type Vars map[string]any
 
@@ -31,11 +41,13 @@ var (
 	ErrExecution = errors.New("exec")
 
 	// ErrVerbose errors are execution errors that can be suppressed by
-	// [WithSilent].
+	// [WithSilent]. They also wrap one of [ErrStructural], [ErrType],
+	// [ErrNumeric], or [ErrDateTime], except for errors returned by custom
+	// methods. See [IsSuppressible].
 	ErrVerbose = fmt.Errorf("%w", ErrExecution)
 
 	// ErrInvalid errors denote invalid or unexpected execution. Generally
-	// internal-only.
+	// internal-only, except for errors for invalid Options.
 	ErrInvalid = errors.New("exec invalid")
 )
 
@@ -45,6 +57,26 @@ var (
 	NULL = errors.New("NULL")
 )
 
+// Error is an [ErrExecution] error that records the location in the JSON
+// value at which it occurred. Its message is that of the underlying error,
+// so it matches the PostgreSQL error message; use [Error.Path] to find where
+// execution failed.
+type Error struct {
+	err   error
+	elems []locElem // location, formatted on demand by Path
+}
+
+// Error returns the error message.
+func (e *Error) Error() string { return e.err.Error() }
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error { return e.err }
+
+// Path returns the normalized path to the item that was being evaluated when
+// the error occurred, starting from the root ($) or a variable, with
+// concrete indexes and keys in place of wildcards, e.g., $."a"."b"[2]."c".
+func (e *Error) Path() string { return formatLocation(e.elems) }
+
 // resultStatus represents the result of jsonpath expression evaluation.
 type resultStatus uint8
 
@@ -76,117 +108,753 @@ func (s resultStatus) failed() bool {
 // valueList holds a list of jsonb values optimized for a single-value list.
 type valueList struct {
 	list []any
+	// "true" counts values in count rather than appending them to list
+	counter bool
+	count   int
+	// limits on the values appended to the results of a query, if any
+	limits *resultLimits
+	// receives each value appended rather than collecting it, if not nil;
+	// "true" once it returns false to stop execution
+	yield   func(any) bool
+	stopped bool
+	// records the location of each value appended in locs, if not nil
+	locator *Executor
+	locs    []Located
+	// records whether each value appended is synthetic in synthetic, if not
+	// nil
+	provenance *Executor
+	synthetic  []bool
 }
 
+// errStopped is returned by valueList.append once its yield function returns
+// false, to stop execution. It never escapes the package.
+var errStopped = errors.New("iteration stopped")
+
 // newList creates a valueList with space allocated a single value.
 func newList() *valueList {
 	return &valueList{list: make([]any, 0, 1)}
 }
 
+// newCounter creates a valueList that counts the values appended to it
+// rather than collecting them, for executions that must evaluate every item
+// but need only know whether any were found.
+func newCounter() *valueList {
+	return &valueList{counter: true}
+}
+
+// size returns the number of values appended to vl.
+func (vl *valueList) size() int {
+	return len(vl.list) + vl.count
+}
+
 // isEmpty returns true when vl is empty.
 func (vl *valueList) isEmpty() bool {
-	return len(vl.list) == 0
+	return vl.size() == 0
 }
 
-// append appends val to vl, allocating more space if needed.
-func (vl *valueList) append(val any) {
+// append appends val to vl, allocating more space if needed. Returns an
+// error if val exceeds the limits of vl.
+func (vl *valueList) append(val any) error {
+	if vl.counter {
+		vl.count++
+		return nil
+	}
+	if vl.limits != nil {
+		if err := vl.limits.add(val); err != nil {
+			return err
+		}
+	}
+	if vl.yield != nil {
+		if vl.stopped {
+			return errStopped
+		}
+		vl.count++
+		if !vl.yield(val) {
+			vl.stopped = true
+			return errStopped
+		}
+		return nil
+	}
+	if vl.locator != nil {
+		vl.locs = append(vl.locs, vl.locator.located(val))
+	}
+	if vl.provenance != nil {
+		vl.synthetic = append(vl.synthetic, vl.provenance.synthetic)
+	}
 	vl.list = append(vl.list, val)
+	return nil
+}
+
+// appendList appends the values in other to vl. Returns an error if they
+// exceed the limits of vl.
+func (vl *valueList) appendList(other *valueList) error {
+	if vl.counter {
+		vl.count += other.size()
+		return nil
+	}
+	if vl.yield != nil {
+		for _, val := range other.list {
+			if err := vl.append(val); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if vl.limits != nil {
+		for _, val := range other.list {
+			if err := vl.limits.add(val); err != nil {
+				return err
+			}
+		}
+	}
+	vl.list = append(vl.list, other.list...)
+	vl.locs = append(vl.locs, other.locs...)
+	vl.synthetic = append(vl.synthetic, other.synthetic...)
+	return nil
+}
+
+// limitErr returns the error for exceeding the limits of vl, if any.
+func (vl *valueList) limitErr() error {
+	if vl.limits == nil {
+		return nil
+	}
+	return vl.limits.err
+}
+
+// grow ensures space for another n values in vl, so that appending them
+// allocates no more than once.
+func (vl *valueList) grow(n int) {
+	if !vl.counter && vl.yield == nil {
+		vl.list = slices.Grow(vl.list, n)
+	}
+}
+
+// maxPooledList is the largest capacity of a valueList returned to listPool,
+// so that the pool does not retain the memory of very large lists.
+const maxPooledList = 1024
+
+// listPool pools valueLists to collect intermediate values that execution
+// discards once evaluated, such as the operands of predicates.
+//
+//nolint:gochecknoglobals
+var listPool = sync.Pool{New: func() any { return newList() }}
+
+// getList returns an empty valueList from listPool. Pass it to putList once
+// its values are no longer needed.
+func getList() *valueList {
+	//nolint:forcetypeassert // listPool contains only *valueList values.
+	return listPool.Get().(*valueList)
+}
+
+// putList clears vl and returns it to listPool. vl must not be used after
+// putList returns.
+func putList(vl *valueList) {
+	if cap(vl.list) > maxPooledList {
+		return
+	}
+	clear(vl.list)
+	vl.list = vl.list[:0]
+	listPool.Put(vl)
+}
+
+// maxPooledBuffer is the largest capacity of the location and scratch
+// buffers an Executor retains when returned to execPool.
+const maxPooledBuffer = 4096
+
+// execPool pools Executors, and the location and scratch buffers they
+// allocate, for query functions that discard the Executor when they return.
+//
+//nolint:gochecknoglobals
+var execPool = sync.Pool{New: func() any { return &Executor{} }}
+
+// release resets exec to its zero value, retaining only the capacity of its
+// location and scratch buffers, and returns it to execPool. Call it only
+// once nothing returned by the query function references exec, and do not
+// use exec after release returns.
+func (exec *Executor) release() {
+	location, scratch := exec.location, exec.scratch
+	if cap(location) > maxPooledBuffer {
+		location = nil
+	}
+	if cap(scratch) > maxPooledBuffer {
+		scratch = nil
+	}
+	// Clear the strings that may reference queried values.
+	clear(location[:cap(location)])
+	*exec = Executor{location: location[:0], scratch: scratch[:0]}
+	execPool.Put(exec)
 }
 
 // Executor represents the context for jsonpath execution.
 type Executor struct {
-	vars                  Vars         // variables to substitute into jsonpath
-	root                  any          // for $ evaluation
-	current               any          // for @ evaluation
-	baseObject            kvBaseObject // "base object" for .keyvalue() evaluation
-	lastGeneratedObjectID int          // "id" counter for .keyvalue() evaluation
-	innermostArraySize    int          // for LAST array index evaluation
+	vars                  Vars             // variables to substitute into jsonpath
+	goVars                Vars             // variables to convert into vars
+	varsFrom              any              // Go value to convert into vars
+	rootVars              Vars             // documents to convert into vars
+	goVarOverrides        Vars             // variables to convert into varOverrides
+	varOverrides          Vars             // variables that take precedence over vars
+	root                  any              // for $ evaluation
+	current               any              // for @ evaluation
+	baseObject            kvBaseObject     // "base object" for .keyvalue() evaluation
+	lastGeneratedObjectID int              // "id" counter for .keyvalue() evaluation
+	kvOffsets             map[string]int64 // object offsets for .keyvalue() evaluation
+	innermostArraySize    int              // for LAST array index evaluation
 	path                  *ast.AST
 
 	// with "true" structural errors such as absence of required json item or
 	// unexpected json item type are ignored
 	ignoreStructuralErrors bool
+	// "true" executes in lax mode and "false" in strict mode; defaults to
+	// the mode of path
+	lax bool
+	// "true" overrides the mode of path with strict or lax mode, strict
+	// taking precedence
+	forceStrict bool
+	forceLax    bool
 
 	// with "false" all suppressible errors are suppressed
 	verbose bool
+	// "true" selects null for missing object keys in strict mode
+	missingAsNull bool
+	// "true" matches member accessors to object keys case-insensitively
+	caseInsensitiveKeys bool
+	// "true" replaces invalid UTF-8 in strings rather than raise an error
+	lenientUTF8 bool
+	// "true" returns Result values from Query and QueryArray
+	provenance bool
+	// "true" when the item being passed to the next node was constructed
+	// rather than selected, when provenance is true
+	synthetic bool
 	// "true" enables casting between TZ and non-TZ time and timestamp types
 	useTZ bool
+	// "true" converts timestamptz results to displayLoc, the time zone of
+	// the execution context
+	displayTZ  bool
+	displayLoc *time.Location
+	// "true" compares objects and arrays for equality structurally
+	deepEqual bool
+	// "true" visits object members in sorted key order
+	orderedKeys bool
+	// style with which .string() formats date and time values
+	dateStyle types.DateStyle
+	// form in which results materialize date and time values
+	dateTimeOutput dateTimeOutput
+	// "true" returns deep copies of results rather than aliases of the input
+	copyResults bool
+	// maximum number and approximate size of results; zero for no limit
+	maxResults     int
+	maxResultBytes int
+	// maximum size of a document read by QueryReader; zero for no limit
+	maxDocumentBytes int
+	// "true" requires Replace and Delete to select at least one item
+	strictMutation bool
+	// struct tag for member names of Go values traversed via reflection
+	structTag string
+	// original Go values of objects and arrays converted from Go values
+	origins map[uintptr]origin
+	// maximum and current recursion depth; no maximum when <= 0
+	maxDepth int
+	depth    int
+	// addresses of the containers into which .** is descending, to detect
+	// cycles
+	descending map[uintptr]bool
+	// "true" requires the path to be the right kind of expression for the
+	// executing function
+	predicateCheck bool
+	// when not nil, receives predicate check errors from functions that
+	// expect SQL standard path expressions instead of returning them
+	predicateWarn func(error)
+	// when not nil, receives warnings like those PostgreSQL reports
+	warn func(string)
+	// when not nil, receives warnings as structured log records
+	logger *slog.Logger
+	// "true" logs structural errors suppressed in lax mode to logger
+	logSuppressed bool
+	// serializes calls to warn and logger by parallel workers, if not nil
+	callbackMu *sync.Mutex
+	// location of the item being evaluated, for errors
+	location []locElem
+	// buffer for formatting text, reused by pooled Executors
+	scratch []byte
+	// number of goroutines across which to evaluate wildcard array
+	// accessors for arrays with at least parallelThreshold elements
+	parallel          int
+	parallelThreshold int
+	// steps recorded by Explain, and the maximum number to record
+	trace      *Trace
+	traceLimit int
+	// statistics filled in by execution, if not nil
+	stats *Stats
+	// implementations of custom methods, consulted before those registered
+	// by RegisterMethod
+	methods map[string]MethodFunc
+	// like_regex patterns compiled during execution
+	regexes map[*ast.RegexNode]*regexp.Regexp
+	// predicate operands repeated within a filter, mapped to the first
+	// equivalent operand, and their results for the filter items being
+	// evaluated, starting at memoStart for the innermost; see planMemo
+	memoPlanned bool
+	memoKeys    map[ast.Node]ast.Node
+	memo        []memoEntry
+	memoItems   []any
+	memoStart   int
+	// "true" stops QueryBatch at the first error
+	failFast bool
+	// index of the document being queried, if passed an *Indexed that
+	// execution can use
+	index *Indexed
 }
 
-// Option specifies an execution option.
+// Option specifies an execution option. The order of Options does not
+// matter, except that, when passed the same Option more than once with
+// different values, such as [WithMaxDepth], execution uses the last value.
+// Options that combine, such as [WithVars], merge their values, and Options
+// that conflict, such as [WithStrict] and [WithLax], resolve as documented,
+// regardless of order. Query functions return an [ErrInvalid] error for
+// invalid Option values, such as negative limits.
 type Option func(*Executor)
 
-// WithVars specifies variables to use during execution.
-func WithVars(vars Vars) Option { return func(e *Executor) { e.vars = vars } }
+// WithVars specifies variables to use during execution. It copies vars, so
+// that changes to vars after WithVars returns do not affect execution. When
+// passed more than once, it merges the variables, those passed later taking
+// precedence over those with the same names passed earlier; a nil vars adds
+// no variables.
+// Before execution, it converts each variable value as described for
+// [WithStructTags], so that, e.g., variables of type int8, uint64, and
+// float32 compare equal to the same numbers in paths and JSON values, and
+// nested structs, maps, and slices become objects and arrays. Struct fields
+// use the names in their json tags, or in the tags named by
+// [WithStructTags]. Execution returns an [ErrVariable] error for values of
+// unsupported types, such as functions and channels.
+func WithVars(vars Vars) Option {
+	goVars := make(Vars, len(vars))
+	for k, v := range vars {
+		goVars[k] = v
+	}
+	return func(e *Executor) {
+		if e.goVars == nil {
+			e.goVars = goVars
+			return
+		}
+		// Merge into a new map, since other executions share goVars.
+		merged := make(Vars, len(e.goVars)+len(goVars))
+		for k, v := range e.goVars {
+			merged[k] = v
+		}
+		for k, v := range goVars {
+			merged[k] = v
+		}
+		e.goVars = merged
+	}
+}
+
+// WithVarOverride specifies value as the variable named name, taking
+// precedence over a variable of the same name specified by [WithVars],
+// [WithVarsFrom], or [WithRootVar]. It neither copies nor modifies the
+// variables specified by those options, so it cheaply varies a variable
+// per execution while sharing the others, e.g.:
+//
+//	common := exec.WithVars(vars)
+//	res, err := exec.Query(ctx, path, doc, common, exec.WithVarOverride("id", id))
+//
+// Before execution, it converts value as described for [WithVars]. Pass
+// WithVarOverride multiple times to override multiple variables.
+func WithVarOverride(name string, value any) Option {
+	return func(e *Executor) {
+		if e.goVarOverrides == nil {
+			e.goVarOverrides = Vars{}
+		}
+		e.goVarOverrides[name] = value
+	}
+}
 
 // WithTZ allows casting between TZ and non-TZ time and timestamp types.
 func WithTZ() Option { return func(e *Executor) { e.useTZ = true } }
 
+// WithDisplayTZ converts timestamp with time zone results to the time zone
+// of the execution context, specified by [types.ContextWithTZ] and
+// defaulting to UTC, as PostgreSQL displays timestamptz values in the
+// session time zone, so that "2023-08-15 12:34:56+05:30" becomes
+// 07:04:56+00:00 in UTC. By default, results retain the offsets with which
+// they were parsed, as do the jsonb results of jsonb_path_query(). Affects
+// the results of query functions and of [QueryText], but not execution,
+// since comparisons consider instants regardless of offset. As in
+// PostgreSQL, time with time zone values always retain their offsets.
+func WithDisplayTZ() Option { return func(e *Executor) { e.displayTZ = true } }
+
+// WithDeepEqual makes the == and != operators compare objects and arrays
+// structurally, an extension to the SQL/JSON standard and PostgreSQL, which
+// consider comparisons of objects and arrays unknown. Objects are equal if
+// they have the same keys, in any order, with equal values, and arrays are
+// equal if they have equal elements in the same order. Nested scalars follow
+// the usual comparison rules, so that, for example, 1 equals 1.0, except
+// that scalars that cannot be compared, such as a string and a number, are
+// simply unequal. Comparisons of objects or arrays to items of any other
+// type, and other comparison operators, remain unknown. Lax mode still
+// unwraps array operands, so compares whole arrays only when nested in
+// other arrays.
+func WithDeepEqual() Option { return func(e *Executor) { e.deepEqual = true } }
+
+// WithOrderedKeys makes the wildcard member accessor (.*) and the recursive
+// wildcard member accessor (.**) visit object members in byte-wise sorted
+// key order, so that they select items in the same order on every
+// execution. PostgreSQL jsonb stores object keys sorted by length and then
+// bytes, so this order differs from PostgreSQL for keys of different
+// lengths. By default, they visit members in Go's random map iteration
+// order, which is faster. The .keyvalue() method always processes members
+// in sorted key order.
+func WithOrderedKeys() Option { return func(e *Executor) { e.orderedKeys = true } }
+
+// WithDateStyle specifies the style in which the .string() method formats
+// date and time values. Defaults to [types.DateStyleISO]. Execution returns
+// an [ErrInvalid] error for an unknown style.
+func WithDateStyle(style types.DateStyle) Option {
+	return func(e *Executor) { e.dateStyle = style }
+}
+
+// WithTimeValues converts date and time values selected by [Query],
+// [QueryArray], [First], [FirstOrDefault], and [Values] from [types.DateTime]
+// values to [time.Time] values, leaving comparisons and other operations
+// during execution unchanged:
+//
+//   - date: midnight UTC on the date
+//   - time: the time of day on January 1, year 0, UTC
+//   - timetz: the time of day on January 1, year 0, in a fixed zone with
+//     the value's UTC offset
+//   - timestamp: the date and time in UTC
+//   - timestamptz: the instant in UTC
+//
+// A time.Time cannot fully express a time with time zone, which has no date:
+// methods such as [time.Time.UTC] or [time.Time.In] may move it to December
+// 31 of year -1 or January 2 of year 0, and comparisons with other time.Time
+// values consider year 0 rather than the current date. [WithStringDateTime]
+// takes precedence over it, regardless of order.
+func WithTimeValues() Option {
+	return func(e *Executor) { e.dateTimeOutput = max(e.dateTimeOutput, dateTimeOutputTime) }
+}
+
+// WithStringDateTime is like [WithTimeValues], but converts date and time
+// values to RFC 3339 strings: full-date for date, partial-time for time,
+// full-time for timetz, and date-time for timestamp and timestamptz, in UTC
+// for the former and with its offset for the latter. RFC 3339 supports only
+// offsets of whole minutes, so strings omit the seconds of offsets that
+// have them. Takes precedence over [WithTimeValues], regardless of order.
+func WithStringDateTime() Option {
+	return func(e *Executor) { e.dateTimeOutput = dateTimeOutputString }
+}
+
+// WithCopyResults returns deep copies of the objects, arrays, and date and
+// time values selected by [Query], [QueryArray], [First], [FirstOrDefault],
+// and [Values], so that callers can modify results without modifying the
+// input value. By default, results alias the input: objects and arrays
+// selected from it are returned as-is, without copying, except for those
+// created during execution, such as the objects returned by .keyvalue().
+// Copies of objects and arrays are always map[string]any and []any values,
+// including those selected from typed Go maps, slices, and structs.
+func WithCopyResults() Option { return func(e *Executor) { e.copyResults = true } }
+
+// WithMaxDepth specifies the maximum recursion depth of execution, which
+// increases with the nesting level of both the path expression and the JSON
+// value it traverses. Execution returns an [ErrExecution] error when it
+// exceeds the maximum, rather than risk overflowing the stack. Defaults to
+// [DefaultMaxDepth]; zero disables the limit. Execution returns an
+// [ErrInvalid] error for a negative value.
+func WithMaxDepth(n int) Option { return func(e *Executor) { e.maxDepth = n } }
+
+// WithPredicateCheck requires the path to be the kind of path expression
+// appropriate to the executing function, mirroring the distinction
+// PostgreSQL draws between SQL standard path expressions and predicate check
+// expressions (see [ast.AST.IsPredicate]). [Match] and [AtAt] return an
+// [ErrExecution] error for a SQL standard path expression, while [Query],
+// [QueryArray], [First], [FirstOrDefault], [Exists], and [AtQuestion] return
+// an [ErrExecution] error for a predicate check expression, rather than
+// returning, e.g., a slice containing a single boolean. [WithSilent] does
+// not suppress these errors.
+func WithPredicateCheck() Option { return func(e *Executor) { e.predicateCheck = true } }
+
+// WithPredicateWarning is like [WithPredicateCheck], except that functions
+// that expect SQL standard path expressions pass the error for a predicate
+// check expression to warn and proceed with execution instead of returning
+// it. [Match] and [AtAt] still return an error for a SQL standard path
+// expression.
+func WithPredicateWarning(warn func(error)) Option {
+	return func(e *Executor) {
+		e.predicateCheck = true
+		e.predicateWarn = warn
+	}
+}
+
+// WithWarningHandler specifies a function to receive the warnings that
+// PostgreSQL would report during execution, such as when the .time() method
+// reduces a precision greater than 6 to 6. Execution proceeds after calling
+// warn. Calls to warn never overlap, even with [WithParallel], though its
+// warnings may then arrive out of array order. Warnings are discarded by
+// default.
+func WithWarningHandler(warn func(string)) Option {
+	return func(e *Executor) { e.warn = warn }
+}
+
 // WithSilent suppresses the following errors: missing object field or array
-// element, unexpected JSON item type, datetime and numeric errors. This
-// behavior emulates the behavior of the PostgreSQL @? and @@ operators, and
-// might be helpful when searching JSON document collections of varying
-// structure.
+// element, unexpected JSON item type, datetime and numeric errors; that is,
+// errors for which [IsSuppressible] returns true. This behavior emulates the
+// behavior of the PostgreSQL @? and @@ operators, and might be helpful when
+// searching JSON document collections of varying structure. As in
+// PostgreSQL, it does not suppress references to undefined variables, casts
+// that require [WithTZ], or invalid .decimal() precisions and scales.
 func WithSilent() Option { return func(e *Executor) { e.verbose = false } }
 
-// newExec creates and returns a new Executor.
-func newExec(path *ast.AST, opt ...Option) *Executor {
-	e := &Executor{
-		path:                   path,
-		innermostArraySize:     -1,
-		ignoreStructuralErrors: path.IsLax(),
-		lastGeneratedObjectID:  1, // Reserved for IDs from vars
-		verbose:                true,
+// WithMissingAsNull makes member accessors in strict mode select null for a
+// missing object key rather than raise a structural error, so that strict
+// mode paths can query objects with optional members without lax mode's
+// automatic wrapping and unwrapping of arrays. Key accessors that directly
+// follow the missing key also select null, so that $.a.b.c selects null when
+// either a or b is missing. Because the missing key selects null, predicates
+// treat it as they would a JSON null, which compares unequal to every other
+// item: @.a > 1 is false, rather than unknown, so that filters drop items
+// that lack a, while @.a != 1, @.a == null, and exists(@.a) are true.
+//
+// All other strict mode behaviors remain, including errors for accessors
+// applied to items of the wrong type, such as a member accessor applied to
+// an explicit null or an array. Lax mode, which ignores missing keys, is
+// unaffected, as are .** accessors, which ignore structural errors in both
+// modes. [Replace] and [Delete], which cannot modify a missing member,
+// select nothing for it.
+//
+// This option diverges from PostgreSQL, which has no equivalent: strict mode
+// paths in PostgreSQL always raise an error for a missing key, which becomes
+// an unknown result in filters and an error or no result elsewhere.
+func WithMissingAsNull() Option { return func(e *Executor) { e.missingAsNull = true } }
+
+// WithCaseInsensitiveKeys makes member accessors, such as $.userid and
+// $."userId", match object keys case-insensitively, under Unicode case
+// folding, for JSON with inconsistently-cased keys. A key that matches
+// exactly always wins; otherwise, when several keys differ from the
+// accessor only by case, such as "UserId" and "userID", the accessor selects
+// the member with the first of them in sorted order, so that the result does
+// not depend on map iteration order. The locations reported by
+// [QueryLocations] name the key as stored.
+//
+// Wildcard member accessors, .**, and .keyvalue() are unaffected, and
+// continue to select and report keys as stored. Missing keys behave as
+// usual in strict and lax modes, including with [WithMissingAsNull]. The
+// option also applies to the paths passed to [Replace] and [Delete].
+//
+// This option diverges from PostgreSQL, which always matches keys exactly.
+func WithCaseInsensitiveKeys() Option { return func(e *Executor) { e.caseInsensitiveKeys = true } }
+
+// WithStrict executes the path in strict mode, even if it was written in lax
+// mode, for example to validate documents against a stored lax mode path.
+// Execution raises structural errors, such as for missing object keys, and
+// neither wraps nor unwraps arrays, just as it would for a path with the
+// strict prefix. The option takes precedence over the mode of the path, and
+// leaves the path unchanged, so that other executions of the path use its
+// own mode. It takes precedence over [WithLax], regardless of order.
+func WithStrict() Option { return func(e *Executor) { e.forceStrict = true } }
+
+// WithLax executes the path in lax mode, even if it was written in strict
+// mode. Execution ignores structural errors, automatically wraps items in
+// arrays and unwraps arrays, just as it would for a path with no mode
+// prefix or the lax prefix. Like [WithStrict], it takes precedence over the
+// mode of the path without changing it, but [WithStrict] takes precedence
+// over it.
+func WithLax() Option { return func(e *Executor) { e.forceLax = true } }
+
+// newExec returns an Executor from execPool configured by opt. Returns an
+// [ErrInvalid] error if opt specifies invalid values; see validate. Query
+// functions that discard the Executor when they return should release it.
+func newExec(path *ast.AST, opt ...Option) (*Executor, error) {
+	//nolint:forcetypeassert // execPool contains only *Executor values.
+	e := execPool.Get().(*Executor)
+	*e = Executor{
+		location:              e.location[:0],
+		scratch:               e.scratch[:0],
+		path:                  path,
+		innermostArraySize:    -1,
+		lax:                   path.IsLax(),
+		lastGeneratedObjectID: 1, // Reserved for IDs from vars
+		verbose:               true,
+		maxDepth:              DefaultMaxDepth,
+		parallelThreshold:     DefaultParallelThreshold,
+		traceLimit:            DefaultTraceLimit,
 	}
 
 	for _, o := range opt {
 		o(e)
 	}
-	return e
+	if err := e.validate(); err != nil {
+		e.release()
+		return nil, err
+	}
+
+	switch {
+	case e.forceStrict:
+		e.lax = false
+	case e.forceLax:
+		e.lax = true
+	}
+	e.ignoreStructuralErrors = e.lax
+	return e, nil
+}
+
+// validate returns an [ErrInvalid] error if the Options applied to exec
+// specified a negative limit, an unknown date style, or a nil custom method.
+func (exec *Executor) validate() error {
+	for _, limit := range []struct {
+		option string
+		value  int
+	}{
+		{"WithMaxDepth", exec.maxDepth},
+		{"WithMaxResults", exec.maxResults},
+		{"WithMaxResultBytes", exec.maxResultBytes},
+		{"WithMaxDocumentBytes", exec.maxDocumentBytes},
+		{"WithParallel", exec.parallel},
+		{"WithTraceLimit", exec.traceLimit},
+	} {
+		if limit.value < 0 {
+			return fmt.Errorf("%w: %v value %v is negative", ErrInvalid, limit.option, limit.value)
+		}
+	}
+
+	if exec.dateStyle > types.DateStyleGerman {
+		return fmt.Errorf("%w: unknown date style %d", ErrInvalid, exec.dateStyle)
+	}
+
+	var nilMethods []string
+	for name, fn := range exec.methods {
+		if fn == nil {
+			nilMethods = append(nilMethods, name)
+		}
+	}
+	if len(nilMethods) > 0 {
+		// Report the same method every time.
+		return fmt.Errorf("%w: nil function for method %q", ErrInvalid, slices.Min(nilMethods))
+	}
+	return nil
 }
 
 // Query returns all JSON items returned by the JSON path for the specified
 // JSON value. For SQL-standard JSON path expressions it returns the JSON
 // values selected from target. For predicate check expressions it returns the
-// result of the predicate check: true, false, or null (false + ErrNull). The
-// optional [WithVars] and [WithSilent] Options act the same as for [Exists].
+// result of the predicate check: true, false, or null (false + ErrNull).
+// Selected objects and arrays alias value unless [WithCopyResults] is
+// specified. The optional [WithVars] and [WithSilent] Options act the same as
+// for [Exists]. Pass an [Indexed] document returned by [IndexDocument] as
+// value to speed up repeated queries of the same document.
 func Query(ctx context.Context, path *ast.AST, value any, opt ...Option) ([]any, error) {
-	exec := newExec(path, opt...)
-	// if exec.verbose && exec.path.IsPredicate() {
-	// 	return nil, fmt.Errorf(
-	// 		"%w: Query expects a SQL standard path expression",
-	// 		ErrVerbose,
-	// 	)
-	// }
-
-	vals, err := exec.execute(ctx, value)
+	return queryAll(ctx, "Query", path, value, opt)
+}
+
+// QueryArray mirrors the PostgreSQL jsonb_path_query_array() function. It
+// is equivalent to [Query], which likewise returns a slice, never nil,
+// unless it returns an error. When the path selects no items it returns an
+// empty slice rather than an error. Like the silent argument to
+// jsonb_path_query_array(), [WithSilent] suppresses the errors that would
+// otherwise be returned, such as for missing keys in strict mode.
+func QueryArray(ctx context.Context, path *ast.AST, value any, opt ...Option) ([]any, error) {
+	return queryAll(ctx, "QueryArray", path, value, opt)
+}
+
+// queryAll implements [Query] and [QueryArray], the name of which should be
+// passed as fn.
+func queryAll(ctx context.Context, fn string, path *ast.AST, value any, opt []Option) ([]any, error) {
+	exec, err := newExec(path, opt...)
 	if err != nil {
 		return nil, err
 	}
-	return vals.list, nil
+	defer exec.release()
+	if err := exec.checkPredicate(fn, false); err != nil {
+		return nil, err
+	}
+
+	if exec.provenance {
+		return exec.queryResults(ctx, value)
+	}
+	return exec.queryAll(ctx, value)
 }
 
 // First returns the first JSON item returned by the JSON path for the
 // specified JSON value, or nil if there are no results. The parameters are
 // the same as for [Query].
 func First(ctx context.Context, path *ast.AST, value any, opt ...Option) (any, error) {
-	exec := newExec(path, opt...)
-	// if exec.verbose && exec.path.IsPredicate() {
-	// 	return nil, fmt.Errorf(
-	// 		"%w: First expects a SQL standard path expression",
-	// 		ErrVerbose,
-	// 	)
-	// }
-
-	vals, err := exec.execute(ctx, value)
+	exec, err := newExec(path, opt...)
 	if err != nil {
 		return nil, err
 	}
-	if vals.isEmpty() {
-		//nolint:nilnil // nil is a valid return value, standing in for JSON null.
-		return nil, nil
+	defer exec.release()
+	if err := exec.checkPredicate("First", false); err != nil {
+		return nil, err
+	}
+
+	return exec.queryFirst(ctx, value, nil)
+}
+
+// FirstOrDefault is like [First], but returns def instead of nil when there
+// are no results. Useful to distinguish between a path that selects no items
+// and a path whose first item is JSON null. The parameters are otherwise the
+// same as for [Query].
+func FirstOrDefault(ctx context.Context, path *ast.AST, value, def any, opt ...Option) (any, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.release()
+	if err := exec.checkPredicate("FirstOrDefault", false); err != nil {
+		return nil, err
+	}
+	return exec.queryFirst(ctx, value, def)
+}
+
+// Keys returns the keys of the objects selected by the JSON path for the
+// specified JSON value, each object's keys in sorted order. It is equivalent
+// to appending .keyvalue().key to the path and passing it to [Query], and
+// likewise unwraps arrays in lax mode and returns an error for any other
+// non-object value, unless [WithSilent] is specified. The optional [WithVars]
+// and [WithSilent] Options act the same as for [Exists].
+func Keys(ctx context.Context, path *ast.AST, value any, opt ...Option) ([]string, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.release()
+	if err := exec.checkPredicate("Keys", false); err != nil {
+		return nil, err
+	}
+
+	vals, err := exec.queryKeyValue(ctx, value, "key")
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(vals.list))
+	for i, key := range vals.list {
+		keys[i], _ = key.(string)
+	}
+	return keys, nil
+}
+
+// Values returns the values of the objects selected by the JSON path for
+// the specified JSON value, each object's values in the order of their
+// sorted keys. It is equivalent to appending .keyvalue().value to the path
+// and passing it to [Query], and otherwise behaves like [Keys].
+func Values(ctx context.Context, path *ast.AST, value any, opt ...Option) ([]any, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.release()
+	if err := exec.checkPredicate("Values", false); err != nil {
+		return nil, err
+	}
+
+	vals, err := exec.queryKeyValue(ctx, value, "value")
+	if err != nil {
+		return nil, err
 	}
-	return vals.list[0], nil
+	for i, val := range vals.list {
+		if vals.list[i], err = exec.result(val); err != nil {
+			return nil, err
+		}
+	}
+	return vals.list, nil
 }
 
 // Exists checks whether the JSON path returns any item for the specified JSON
@@ -205,79 +873,248 @@ func First(ctx context.Context, path *ast.AST, value any, opt ...Option) (any, e
 //		`$[*] ? (@.datetime() < "2015-08-02".datetime())`,
 //		WithTZ(),
 //	) → true
+//
+// Use [ExistsTri] to get an unknown result as [Null] rather than the [NULL]
+// error value. As with [Query], value may be an [Indexed] document.
 func Exists(ctx context.Context, path *ast.AST, value any, opt ...Option) (bool, error) {
-	exec := newExec(path, opt...)
-	// if exec.verbose && exec.path.IsPredicate() {
-	// 	return false, fmt.Errorf(
-	// 		"%w: Exists expects a SQL standard path expression",
-	// 		ErrVerbose,
-	// 	)
-	// }
+	return boolResult(ExistsTri(ctx, path, value, opt...))
+}
+
+// existsResult implements [ExistsTri] and [AtQuestionTri], the name of
+// whose bool counterpart should be passed as fn.
+func (exec *Executor) existsResult(ctx context.Context, fn string, value any) (Ternary, error) {
+	if err := exec.checkPredicate(fn, false); err != nil {
+		return Null, err
+	}
 
 	res, err := exec.exists(ctx, value)
 	if err != nil {
-		return false, err
+		return Null, err
 	}
 	if res.failed() {
-		return false, NULL
+		return Null, nil
 	}
-	return res == statusOK, nil
+	return ternaryOf(res == statusOK), nil
 }
 
 // Match returns the result of a JSON path predicate check for the specified
 // JSON value. (This is useful only with predicate check expressions, not
 // SQL-standard JSON path expressions, since it will either fail or return
 // NULL if the path result is not a single boolean value.) The optional
-// [WithVars] and [WithSilent] Options act the same as for [Exists].
+// [WithVars] and [WithSilent] Options act the same as for [Exists]. Use
+// [MatchTri] to get an unknown result as [Null] rather than the [NULL]
+// error value.
 func Match(ctx context.Context, path *ast.AST, value any, opt ...Option) (bool, error) {
-	exec := newExec(path, opt...)
-	// if exec.verbose && !exec.path.IsPredicate() {
-	// 	return false, fmt.Errorf(
-	// 		"%w: Match expects a predicate path expression",
-	// 		ErrVerbose,
-	// 	)
-	// }
-
-	vals, err := exec.execute(ctx, value)
+	return boolResult(MatchTri(ctx, path, value, opt...))
+}
+
+// matchResult implements [MatchTri] and [AtAtTri], the name of whose bool
+// counterpart should be passed as fn.
+func (exec *Executor) matchResult(ctx context.Context, fn string, value any) (Ternary, error) {
+	if err := exec.checkPredicate(fn, true); err != nil {
+		return Null, err
+	}
+
+	vals, err := exec.execute(ctx, value, newList())
 	if err != nil {
-		return false, err
+		return Null, err
 	}
 
 	if len(vals.list) == 1 {
 		switch val := vals.list[0].(type) {
 		case nil:
-			return false, NULL
+			return Null, nil
 		case bool:
-			return val, nil
+			return ternaryOf(val), nil
 		}
 	}
 
 	if exec.verbose {
-		return false, fmt.Errorf(
+		return Null, fmt.Errorf(
 			"%w: single boolean result is expected",
-			ErrVerbose,
+			errVerboseType,
 		)
 	}
 
-	return false, NULL
+	return Null, nil
 }
 
-func (exec *Executor) strictAbsenceOfErrors() bool { return exec.path.IsStrict() }
-func (exec *Executor) autoUnwrap() bool            { return exec.path.IsLax() }
-func (exec *Executor) autoWrap() bool              { return exec.path.IsLax() }
+// AtQuestion implements the semantics of the PostgreSQL @? operator: it acts
+// like [Exists] with [WithSilent], so that structural and execution errors
+// that [WithSilent] suppresses produce an unknown result rather than an error.
+// When the result is unknown, AtQuestion returns false and the [NULL] error
+// value. For example, whereas Exists returns an error for the path
+// `strict $[1]` against []any{1}, AtQuestion returns false and [NULL], just
+// as `'[1]' @? 'strict $[1]'` returns NULL in PostgreSQL. Use
+// [AtQuestionTri] to get an unknown result as [Null] instead.
+func AtQuestion(ctx context.Context, path *ast.AST, value any, opt ...Option) (bool, error) {
+	return boolResult(AtQuestionTri(ctx, path, value, opt...))
+}
 
-// execute executes exec.path against value, returning selected values or an error.
-func (exec *Executor) execute(ctx context.Context, value any) (*valueList, error) {
+// AtAt implements the semantics of the PostgreSQL @@ operator: it acts like
+// [Match] with [WithSilent], so that suppressible errors, and any result
+// other than a single boolean value, produce an unknown result. When the
+// result is unknown, AtAt returns false and the [NULL] error value. For
+// example, whereas Match returns an error for the path `$[0]` against
+// []any{1}, because its result is not a boolean, AtAt returns false and
+// [NULL], just as `'[1]' @@ '$[0]'` returns NULL in PostgreSQL. Use
+// [AtAtTri] to get an unknown result as [Null] instead.
+func AtAt(ctx context.Context, path *ast.AST, value any, opt ...Option) (bool, error) {
+	return boolResult(AtAtTri(ctx, path, value, opt...))
+}
+
+// ExistsOrMatch dispatches path to [AtAt] if it is a predicate check
+// expression, as determined by [ast.AST.IsPredicate], and to [AtQuestion]
+// otherwise, mirroring the choice between the PostgreSQL @@ and @?
+// operators, so that callers need not know which kind of expression they
+// have. For example, `$ == "2"` is a predicate check expression that returns
+// true for "2" and false and [NULL] for 2, while `$.a` is a SQL standard
+// path expression that returns true if the value has the key "a". Results
+// and options are the same as for [AtAt] and [AtQuestion]; use
+// [ExistsOrMatchTri] to get an unknown result as [Null] instead.
+func ExistsOrMatch(ctx context.Context, path *ast.AST, value any, opt ...Option) (bool, error) {
+	return boolResult(ExistsOrMatchTri(ctx, path, value, opt...))
+}
+
+// silently returns a copy of opt with [WithSilent] appended, leaving the
+// caller's slice unmodified.
+func silently(opt []Option) []Option {
+	return append(opt[:len(opt):len(opt)], WithSilent())
+}
+
+func (exec *Executor) strictAbsenceOfErrors() bool { return !exec.lax }
+func (exec *Executor) autoUnwrap() bool            { return exec.lax }
+func (exec *Executor) autoWrap() bool              { return exec.lax }
+
+// checkPredicate returns an error when exec.predicateCheck is true and
+// exec.path is not the kind of path expression expected by the function
+// named fn: a predicate check expression when pred is true and a SQL
+// standard path expression when pred is false. If pred is false and
+// exec.predicateWarn is not nil, it passes the error to exec.predicateWarn
+// and returns nil.
+func (exec *Executor) checkPredicate(fn string, pred bool) error {
+	if !exec.predicateCheck || exec.path.IsPredicate() == pred {
+		return nil
+	}
+
+	if pred {
+		return fmt.Errorf(
+			"%w: %v expects a predicate check expression but %q is a SQL standard path expression",
+			ErrExecution, fn, exec.path,
+		)
+	}
+
+	err := fmt.Errorf(
+		"%w: %v expects a SQL standard path expression but %q is a predicate check expression",
+		ErrExecution, fn, exec.path,
+	)
+	if exec.predicateWarn != nil {
+		exec.predicateWarn(err)
+		return nil
+	}
+	return err
+}
+
+// queryAll executes exec.path against value and returns all selected
+// values. The returned slice is never nil unless there is an error.
+func (exec *Executor) queryAll(ctx context.Context, value any) ([]any, error) {
+	vals, err := exec.execute(ctx, value, exec.newResultList())
+	if err != nil {
+		return nil, err
+	}
+	if exec.origins != nil || exec.dateTimeOutput != dateTimeOutputTypes || exec.copyResults || exec.displayTZ {
+		for i, val := range vals.list {
+			if vals.list[i], err = exec.result(val); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return vals.list, nil
+}
+
+// queryKeyValue executes exec.path against value and then executes
+// .keyvalue() followed by the accessor for field ("key" or "value") against
+// each selected value, returning the results.
+func (exec *Executor) queryKeyValue(ctx context.Context, value any, field string) (*valueList, error) {
+	vals, err := exec.execute(ctx, value, exec.newResultList())
+	if err != nil {
+		return nil, err
+	}
+
+	node := ast.LinkNodes([]ast.Node{ast.NewMethod(ast.MethodKeyValue), ast.NewKey(field)})
+	found := exec.newResultList()
+	for _, val := range vals.list {
+		res, err := exec.executeNode(ctx, node, val, found, exec.autoUnwrap())
+		if res == statusFailed {
+			if err != nil {
+				return nil, err
+			}
+			// Silent error: stop, like the query itself would.
+			break
+		}
+	}
+	if err := found.limitErr(); err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// queryFirst executes exec.path against value and returns the first selected
+// value, or def if there are no results.
+func (exec *Executor) queryFirst(ctx context.Context, value, def any) (any, error) {
+	vals, err := exec.execute(ctx, value, exec.newResultList())
+	if err != nil {
+		return nil, err
+	}
+	if vals.isEmpty() {
+		return def, nil
+	}
+	return exec.result(vals.list[0])
+}
+
+// execute executes exec.path against value, appending selected values to
+// vals and returning them or an error.
+func (exec *Executor) execute(ctx context.Context, value any, vals *valueList) (*valueList, error) {
+	var err error
+	value = exec.useIndex(value)
+	if exec.structTag != "" {
+		if value, err = exec.fromGo(ctx, value); err != nil {
+			return nil, err
+		}
+	} else if value, err = exec.normalizeDoc(value); err != nil {
+		return nil, err
+	}
+	if err = exec.convertVars(ctx); err != nil {
+		return nil, err
+	}
+	if exec.displayTZ {
+		exec.displayLoc = types.TZFromContext(ctx)
+	}
 	exec.root = value
 	exec.current = value
-	vals := newList()
-	_, err := exec.query(ctx, vals, exec.path.Root(), value)
+	_, err = exec.query(ctx, vals, exec.path.Root(), value)
+	if err := vals.limitErr(); err != nil {
+		// Report the limit even if execution suppressed it.
+		return vals, err
+	}
 	return vals, err
 }
 
 // exists returns true if the path passed to New() returns at least one item
 // for json.
 func (exec *Executor) exists(ctx context.Context, json any) (resultStatus, error) {
+	var err error
+	json = exec.useIndex(json)
+	if exec.structTag != "" {
+		if json, err = exec.fromGo(ctx, json); err != nil {
+			return statusFailed, err
+		}
+	} else if json, err = exec.normalizeDoc(json); err != nil {
+		return statusFailed, err
+	}
+	if err = exec.convertVars(ctx); err != nil {
+		return statusFailed, err
+	}
 	exec.root = json
 	exec.current = json
 	return exec.query(ctx, nil, exec.path.Root(), json)
@@ -3,8 +3,14 @@ package exec
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -54,6 +60,144 @@ func TestValueList(t *testing.T) {
 	a.False(list.isEmpty())
 	a.Len(list.list, 2)
 	a.Equal(2, cap(list.list))
+	a.Equal(2, list.size())
+
+	list.grow(10)
+	a.Len(list.list, 2)
+	a.GreaterOrEqual(cap(list.list), 12)
+
+	other := newList()
+	other.append(true)
+	list.appendList(other)
+	a.Equal([]any{"foo", 42, true}, list.list)
+}
+
+func TestValueCounter(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	list := newCounter()
+	a.True(list.isEmpty())
+	a.Zero(list.size())
+
+	list.append("foo")
+	list.append(42)
+	a.False(list.isEmpty())
+	a.Equal(2, list.size())
+	a.Nil(list.list)
+
+	list.grow(10)
+	a.Nil(list.list)
+
+	other := newList()
+	other.append(true)
+	list.appendList(other)
+	a.Equal(3, list.size())
+	a.Nil(list.list)
+}
+
+//nolint:paralleltest // Other tests may take the pooled list from the pool.
+func TestListPool(t *testing.T) {
+	a := assert.New(t)
+
+	list := getList()
+	a.True(list.isEmpty())
+	list.append("foo")
+	list.append(42)
+	backing := list.list
+	putList(list)
+	a.Empty(list.list)
+	a.Equal([]any{nil, nil}, backing, "should clear values")
+
+	// Lists too large to pool are left alone.
+	list = getList()
+	for i := range maxPooledList + 1 {
+		list.append(i)
+	}
+	putList(list)
+	a.Len(list.list, maxPooledList+1)
+}
+
+func TestExecPool(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	path, err := parser.Parse("$")
+	r.NoError(err)
+	fresh := func() *Executor {
+		return &Executor{
+			path:                   path,
+			innermostArraySize:     -1,
+			ignoreStructuralErrors: true,
+			lax:                    true,
+			lastGeneratedObjectID:  1,
+			verbose:                true,
+			maxDepth:               DefaultMaxDepth,
+			parallelThreshold:      DefaultParallelThreshold,
+			traceLimit:             DefaultTraceLimit,
+		}
+	}
+
+	// poison sets fields of e that execution and options set.
+	poison := func(e *Executor) {
+		e.vars = Vars{"secret": "x"}
+		e.root = "secret"
+		e.current = "secret"
+		e.kvOffsets = map[string]int64{"secret": 1}
+		e.depth = 99
+		e.verbose = false
+		e.provenance = true
+		e.stats = &Stats{}
+		e.origins = map[uintptr]origin{1: {orig: "secret"}}
+		e.regexes = map[*ast.RegexNode]*regexp.Regexp{}
+		e.memoItems = []any{"secret"}
+		e.index = &Indexed{}
+		e.maxResults = 1
+		e.location = append(e.location, locElem{kind: locKey, name: "secret"})
+		e.scratch = append(e.scratch, "secret"...)
+	}
+
+	// Released Executors retain nothing but the capacity of their buffers.
+	e := fresh()
+	poison(e)
+	location := e.location
+	e.release()
+	a.Equal(&Executor{location: []locElem{}, scratch: []byte{}}, e)
+	a.Equal(locElem{}, location[:1][0], "should clear location")
+
+	// Executors returned by newExec are fresh, even if poisoned in the pool.
+	for range 10 {
+		e := fresh()
+		poison(e)
+		execPool.Put(e)
+	}
+	for range 10 {
+		e, err := newExec(path)
+		r.NoError(err)
+		a.Empty(e.location)
+		a.Empty(e.scratch)
+		e.location, e.scratch = nil, nil
+		a.Equal(fresh(), e)
+	}
+
+	// Executors are reset and reused across concurrent queries.
+	doc := map[string]any{"a": []any{"x", "y"}}
+	path, err = parser.Parse(`$.a[*] ? (@ starts with $p)`)
+	r.NoError(err)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prefix := []string{"x", "y"}[i%2]
+			for range 100 {
+				res, err := QueryText(context.Background(), path, doc, WithVars(Vars{"p": prefix}))
+				a.NoError(err)
+				a.Equal([]string{`"` + prefix + `"`}, res)
+			}
+		}()
+	}
+	wg.Wait()
 }
 
 func TestOptions(t *testing.T) {
@@ -68,23 +212,133 @@ func TestOptions(t *testing.T) {
 		{
 			name: "vars",
 			opt:  WithVars(Vars{"foo": 1}),
-			exp:  &Executor{verbose: true, vars: Vars{"foo": 1}},
+			exp:  &Executor{verbose: true, goVars: Vars{"foo": 1}},
 		},
 		{
 			name: "vars_nested",
 			opt:  WithVars(Vars{"foo": 1, "bar": []any{1, 2}}),
-			exp:  &Executor{verbose: true, vars: Vars{"foo": 1, "bar": []any{1, 2}}},
+			exp:  &Executor{verbose: true, goVars: Vars{"foo": 1, "bar": []any{1, 2}}},
+		},
+		{
+			name: "root_var",
+			opt:  WithRootVar("doc", []any{1, 2}),
+			exp:  &Executor{verbose: true, rootVars: Vars{"doc": []any{1, 2}}},
+		},
+		{
+			name: "var_override",
+			opt:  WithVarOverride("x", 1),
+			exp:  &Executor{verbose: true, goVarOverrides: Vars{"x": 1}},
 		},
 		{
 			name: "tz",
 			opt:  WithTZ(),
 			exp:  &Executor{verbose: true, useTZ: true},
 		},
+		{
+			name: "display_tz",
+			opt:  WithDisplayTZ(),
+			exp:  &Executor{verbose: true, displayTZ: true},
+		},
 		{
 			name: "silent",
 			opt:  WithSilent(),
 			exp:  &Executor{verbose: false},
 		},
+		{
+			name: "missing_as_null",
+			opt:  WithMissingAsNull(),
+			exp:  &Executor{verbose: true, missingAsNull: true},
+		},
+		{
+			name: "case_insensitive_keys",
+			opt:  WithCaseInsensitiveKeys(),
+			exp:  &Executor{verbose: true, caseInsensitiveKeys: true},
+		},
+		{
+			name: "lenient_utf8",
+			opt:  WithLenientUTF8(),
+			exp:  &Executor{verbose: true, lenientUTF8: true},
+		},
+		{
+			name: "provenance",
+			opt:  WithProvenance(),
+			exp:  &Executor{verbose: true, provenance: true},
+		},
+		{
+			name: "logger",
+			opt:  WithLogger(slog.Default()),
+			exp:  &Executor{verbose: true, logger: slog.Default()},
+		},
+		{
+			name: "log_suppressed",
+			opt:  WithLogSuppressed(),
+			exp:  &Executor{verbose: true, logSuppressed: true},
+		},
+		{
+			name: "date_style",
+			opt:  WithDateStyle(types.DateStylePostgres),
+			exp:  &Executor{verbose: true, dateStyle: types.DateStylePostgres},
+		},
+		{
+			name: "max_depth",
+			opt:  WithMaxDepth(42),
+			exp:  &Executor{verbose: true, maxDepth: 42},
+		},
+		{
+			name: "max_document_bytes",
+			opt:  WithMaxDocumentBytes(1024),
+			exp:  &Executor{verbose: true, maxDocumentBytes: 1024},
+		},
+		{
+			name: "struct_tags",
+			opt:  WithStructTags("json"),
+			exp:  &Executor{verbose: true, structTag: "json"},
+		},
+		{
+			name: "predicate_check",
+			opt:  WithPredicateCheck(),
+			exp:  &Executor{verbose: true, predicateCheck: true},
+		},
+		{
+			name: "parallel",
+			opt:  WithParallel(4),
+			exp:  &Executor{verbose: true, parallel: 4},
+		},
+		{
+			name: "trace_limit",
+			opt:  WithTraceLimit(100),
+			exp:  &Executor{verbose: true, traceLimit: 100},
+		},
+		{
+			name: "stats",
+			opt:  WithStats(&Stats{}),
+			exp:  &Executor{verbose: true, stats: &Stats{}},
+		},
+		{
+			name: "copy_results",
+			opt:  WithCopyResults(),
+			exp:  &Executor{verbose: true, copyResults: true},
+		},
+		{
+			name: "deep_equal",
+			opt:  WithDeepEqual(),
+			exp:  &Executor{verbose: true, deepEqual: true},
+		},
+		{
+			name: "ordered_keys",
+			opt:  WithOrderedKeys(),
+			exp:  &Executor{verbose: true, orderedKeys: true},
+		},
+		{
+			name: "strict",
+			opt:  WithStrict(),
+			exp:  &Executor{verbose: true, forceStrict: true},
+		},
+		{
+			name: "lax",
+			opt:  WithLax(),
+			exp:  &Executor{verbose: true, forceLax: true},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -114,8 +368,12 @@ func TestNewExec(t *testing.T) {
 				path:                   lax,
 				innermostArraySize:     -1,
 				ignoreStructuralErrors: true,
+				lax:                    true,
 				lastGeneratedObjectID:  1,
 				verbose:                true,
+				maxDepth:               DefaultMaxDepth,
+				parallelThreshold:      DefaultParallelThreshold,
+				traceLimit:             DefaultTraceLimit,
 			},
 		},
 		{
@@ -127,6 +385,9 @@ func TestNewExec(t *testing.T) {
 				ignoreStructuralErrors: false,
 				lastGeneratedObjectID:  1,
 				verbose:                true,
+				maxDepth:               DefaultMaxDepth,
+				parallelThreshold:      DefaultParallelThreshold,
+				traceLimit:             DefaultTraceLimit,
 			},
 		},
 		{
@@ -137,9 +398,13 @@ func TestNewExec(t *testing.T) {
 				path:                   lax,
 				innermostArraySize:     -1,
 				ignoreStructuralErrors: true,
+				lax:                    true,
 				lastGeneratedObjectID:  1,
 				verbose:                false,
-				vars:                   Vars{"x": 1},
+				maxDepth:               DefaultMaxDepth,
+				parallelThreshold:      DefaultParallelThreshold,
+				traceLimit:             DefaultTraceLimit,
+				goVars:                 Vars{"x": 1},
 			},
 		},
 		{
@@ -152,224 +417,2263 @@ func TestNewExec(t *testing.T) {
 				ignoreStructuralErrors: false,
 				lastGeneratedObjectID:  1,
 				verbose:                false,
+				maxDepth:               DefaultMaxDepth,
+				parallelThreshold:      DefaultParallelThreshold,
+				traceLimit:             DefaultTraceLimit,
 				useTZ:                  true,
 			},
 		},
+		{
+			name: "lax_with_strict",
+			path: lax,
+			opts: []Option{WithStrict()},
+			exp: &Executor{
+				path:                   lax,
+				innermostArraySize:     -1,
+				ignoreStructuralErrors: false,
+				forceStrict:            true,
+				lastGeneratedObjectID:  1,
+				verbose:                true,
+				maxDepth:               DefaultMaxDepth,
+				parallelThreshold:      DefaultParallelThreshold,
+				traceLimit:             DefaultTraceLimit,
+			},
+		},
+		{
+			name: "strict_with_lax",
+			path: strict,
+			opts: []Option{WithLax()},
+			exp: &Executor{
+				path:                   strict,
+				innermostArraySize:     -1,
+				ignoreStructuralErrors: true,
+				lax:                    true,
+				forceLax:               true,
+				lastGeneratedObjectID:  1,
+				verbose:                true,
+				maxDepth:               DefaultMaxDepth,
+				parallelThreshold:      DefaultParallelThreshold,
+				traceLimit:             DefaultTraceLimit,
+			},
+		},
+		{
+			name: "strict_over_lax",
+			path: lax,
+			opts: []Option{WithLax(), WithStrict()},
+			exp: &Executor{
+				path:                   lax,
+				innermostArraySize:     -1,
+				ignoreStructuralErrors: false,
+				forceStrict:            true,
+				forceLax:               true,
+				lastGeneratedObjectID:  1,
+				verbose:                true,
+				maxDepth:               DefaultMaxDepth,
+				parallelThreshold:      DefaultParallelThreshold,
+				traceLimit:             DefaultTraceLimit,
+			},
+		},
+		{
+			name: "strict_over_later_lax",
+			path: lax,
+			opts: []Option{WithStrict(), WithLax()},
+			exp: &Executor{
+				path:                   lax,
+				innermostArraySize:     -1,
+				ignoreStructuralErrors: false,
+				forceStrict:            true,
+				forceLax:               true,
+				lastGeneratedObjectID:  1,
+				verbose:                true,
+				maxDepth:               DefaultMaxDepth,
+				parallelThreshold:      DefaultParallelThreshold,
+				traceLimit:             DefaultTraceLimit,
+			},
+		},
+		{
+			name: "max_depth",
+			path: lax,
+			opts: []Option{WithMaxDepth(5)},
+			exp: &Executor{
+				path:                   lax,
+				innermostArraySize:     -1,
+				ignoreStructuralErrors: true,
+				lax:                    true,
+				lastGeneratedObjectID:  1,
+				verbose:                true,
+				maxDepth:               5,
+				parallelThreshold:      DefaultParallelThreshold,
+				traceLimit:             DefaultTraceLimit,
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			e := newExec(tc.path, tc.opts...)
+			e, err := newExec(tc.path, tc.opts...)
+			require.NoError(t, err)
+			// Pooled Executors retain empty buffers.
+			a.Empty(e.location)
+			a.Empty(e.scratch)
+			e.location, e.scratch = nil, nil
 			a.Equal(tc.exp, e)
 		})
 	}
 }
 
-func TestQueryAndFirstAndExists(t *testing.T) {
+func TestModeOverride(t *testing.T) {
 	t.Parallel()
-	a := assert.New(t)
-	r := require.New(t)
 	ctx := context.Background()
 
 	for _, tc := range []struct {
-		name  string
-		path  string
-		value any
-		opts  []Option
-		exp   []any
-		err   string
-		isErr error
-		null  bool
+		name      string
+		path      string
+		value     any
+		lax       []any
+		strict    []any
+		strictErr string
 	}{
 		{
-			name:  "root",
-			path:  "$",
-			value: []any{1, 2},
-			exp:   []any{[]any{1, 2}},
-		},
-		{
-			name:  "empty",
-			path:  "$[3]",
-			value: []any{1, 2},
-			exp:   []any{},
+			name:      "missing_key",
+			path:      "$.a.x",
+			value:     map[string]any{"a": map[string]any{"b": int64(1)}},
+			lax:       []any{},
+			strictErr: `exec: JSON object does not contain key "x"`,
 		},
 		{
-			name:  "error",
-			path:  "$.string()",
-			value: []any{1, 2},
-			err:   "exec: jsonpath item method .string() can only be applied to a boolean, string, numeric, or datetime value",
-			isErr: ErrVerbose,
+			name:      "scalar_wildcard",
+			path:      "$[*]",
+			value:     int64(1),
+			lax:       []any{int64(1)},
+			strictErr: "exec: jsonpath wildcard array accessor can only be applied to an array",
 		},
 		{
-			name:  "silent_no_error",
-			path:  "$.string()",
-			opts:  []Option{WithSilent()},
-			value: []any{1, 2},
-			exp:   []any{},
-			null:  true,
+			name:      "array_member",
+			path:      "$.a",
+			value:     []any{map[string]any{"a": int64(1)}, map[string]any{"a": int64(2)}},
+			lax:       []any{int64(1), int64(2)},
+			strictErr: "exec: jsonpath member accessor can only be applied to an object",
 		},
 		{
-			name:  "like_regex_object",
-			path:  `$ like_regex "^hi"`,
-			value: map[string]any{"x": "HIGH"},
-			exp:   []any{nil},
+			name:      "scalar_size",
+			path:      "$.size()",
+			value:     "x",
+			lax:       []any{int64(1)},
+			strictErr: "exec: jsonpath item method .size() can only be applied to an array",
 		},
 		{
-			name:  "like_regex_object_filter",
-			path:  `$ ?(@ like_regex "^hi")`,
-			value: map[string]any{"x": "HIGH"},
-			exp:   []any{},
+			name:   "same",
+			path:   "$.a[0]",
+			value:  map[string]any{"a": []any{true}},
+			lax:    []any{true},
+			strict: []any{true},
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			// Parse the path.
-			path, err := parser.Parse(tc.path)
-			r.NoError(err)
-
-			t.Run("query", func(t *testing.T) {
-				t.Parallel()
-				// Run the query.
-				res, err := Query(ctx, path, tc.value, tc.opts...)
-				a.Equal(tc.exp, res)
-
-				// Check the error.
-				if tc.isErr == nil {
-					r.NoError(err)
-				} else {
-					r.EqualError(err, tc.err)
-					r.ErrorIs(err, tc.isErr)
-				}
-			})
-
-			t.Run("first", func(t *testing.T) {
-				t.Parallel()
-				// Run the query.
-				res, err := First(ctx, path, tc.value, tc.opts...)
-				if len(tc.exp) > 0 {
-					a.Equal(tc.exp[0], res)
-				} else {
-					a.Nil(res)
-				}
+			a := assert.New(t)
+			r := require.New(t)
 
-				// Check the error.
-				if tc.isErr == nil {
-					r.NoError(err)
-				} else {
-					r.EqualError(err, tc.err)
-					r.ErrorIs(err, tc.isErr)
-				}
-			})
+			laxPath, err := parser.Parse("lax " + tc.path)
+			r.NoError(err)
+			strictPath, err := parser.Parse("strict " + tc.path)
+			r.NoError(err)
 
-			t.Run("exists", func(t *testing.T) {
-				t.Parallel()
-				// Run the query.
-				res, err := Exists(ctx, path, tc.value, tc.opts...)
-				a.Equal(len(tc.exp) > 0, res)
+			for _, path := range []*ast.AST{laxPath, strictPath} {
+				// WithLax wins over the mode of the path.
+				res, err := Query(ctx, path, tc.value, WithLax())
+				r.NoError(err)
+				a.Equal(tc.lax, res)
 
-				// Check the error.
-				if tc.isErr == nil {
-					if tc.null {
-						r.EqualError(err, "NULL")
-						r.ErrorIs(err, NULL)
-					} else {
+				// As does WithStrict, which also wins over WithLax.
+				for _, opt := range [][]Option{
+					{WithStrict()},
+					{WithStrict(), WithLax()},
+					{WithLax(), WithStrict()},
+				} {
+					res, err = Query(ctx, path, tc.value, opt...)
+					if tc.strictErr == "" {
 						r.NoError(err)
+						a.Equal(tc.strict, res)
+					} else {
+						r.EqualError(err, tc.strictErr)
+						r.ErrorIs(err, ErrVerbose)
+						a.Nil(res)
 					}
-				} else {
-					r.EqualError(err, tc.err)
-					r.ErrorIs(err, tc.isErr)
 				}
-			})
+			}
+
+			// Options do not change the paths.
+			a.True(laxPath.IsLax())
+			a.True(strictPath.IsStrict())
+			res, err := Query(ctx, laxPath, tc.value)
+			r.NoError(err)
+			a.Equal(tc.lax, res)
+			if tc.strictErr == "" {
+				res, err = Query(ctx, strictPath, tc.value)
+				r.NoError(err)
+				a.Equal(tc.strict, res)
+			} else {
+				_, err = Query(ctx, strictPath, tc.value)
+				r.EqualError(err, tc.strictErr)
+			}
 		})
 	}
 }
 
-func TestMatch(t *testing.T) {
+func TestOptionCombinations(t *testing.T) {
 	t.Parallel()
-	a := assert.New(t)
-	r := require.New(t)
 	ctx := context.Background()
+	registerTestMethods()
+	methods := []Option{
+		WithMethods(map[string]MethodFunc{"test_local": testUpper, "test_missing": testUpper}),
+		WithMethods(map[string]MethodFunc{"test_local": testLower}),
+	}
 
 	for _, tc := range []struct {
 		name  string
 		path  string
 		value any
-		opts  []Option
-		exp   bool
+		opt   []Option
+		exp   []any
 		err   string
-		isErr error
 	}{
 		{
-			name:  "root_eq",
-			path:  "$ == 42",
-			value: int64(42),
-			exp:   true,
+			name: "vars_merge",
+			path: `$a + $b * 10 + $c * 100`,
+			opt: []Option{
+				WithVars(Vars{"a": 1, "b": 2}),
+				WithVars(Vars{"b": 3, "c": 4}),
+			},
+			exp: []any{int64(431)},
+		},
+		{
+			name: "vars_nil",
+			path: `$a`,
+			opt:  []Option{WithVars(Vars{"a": 1}), WithVars(nil)},
+			exp:  []any{int64(1)},
+		},
+		{
+			name: "vars_nil_first",
+			path: `$a`,
+			opt:  []Option{WithVars(nil), WithVars(Vars{"a": 1})},
+			exp:  []any{int64(1)},
+		},
+		{
+			name: "override_before_vars",
+			path: `$a`,
+			opt:  []Option{WithVarOverride("a", 2), WithVars(Vars{"a": 1})},
+			exp:  []any{int64(2)},
+		},
+		{
+			name:  "methods_later_wins",
+			path:  `$.test_local()`,
+			value: "Hi",
+			opt:   methods,
+			exp:   []any{"hi"},
+		},
+		{
+			name:  "methods_merge",
+			path:  `$.test_missing()`,
+			value: "Hi",
+			opt:   methods,
+			exp:   []any{"HI"},
+		},
+		{
+			name:  "tz_twice",
+			path:  `$.timestamp_tz()`,
+			value: "2024-01-02 03:04:05",
+			opt:   []Option{WithTZ(), WithTZ()},
+			exp:   []any{types.NewTimestampTZ(ctx, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))},
+		},
+		{
+			name: "silent_twice",
+			path: `strict $.x`,
+			opt:  []Option{WithSilent(), WithSilent()},
+			exp:  []any{},
+		},
+		{
+			name:  "max_depth_last",
+			path:  `$.a.b`,
+			value: map[string]any{"a": map[string]any{}},
+			opt:   []Option{WithMaxDepth(100), WithMaxDepth(1)},
+			err:   "exec: maximum recursion depth exceeded",
+		},
+		{
+			name:  "max_depth_zero",
+			path:  `$.a.b`,
+			value: map[string]any{"a": map[string]any{}},
+			opt:   []Option{WithMaxDepth(1), WithMaxDepth(0)},
+			exp:   []any{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			// Options may be reused.
+			for range 2 {
+				res, err := Query(ctx, path, tc.value, tc.opt...)
+				if tc.err == "" {
+					r.NoError(err)
+					a.Equal(tc.exp, res)
+				} else {
+					r.EqualError(err, tc.err)
+				}
+			}
+		})
+	}
+
+	// Merging leaves the options unchanged.
+	a := assert.New(t)
+	r := require.New(t)
+	path, err := parser.Parse(`$a + $b * 10`)
+	r.NoError(err)
+	first := WithVars(Vars{"a": 1, "b": 2})
+	res, err := Query(ctx, path, nil, first, WithVars(Vars{"b": 3}))
+	r.NoError(err)
+	a.Equal([]any{int64(31)}, res)
+	res, err = Query(ctx, path, nil, first)
+	r.NoError(err)
+	a.Equal([]any{int64(21)}, res)
+}
+
+func TestInvalidOptions(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path, err := parser.Parse(`$`)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		opt  Option
+		err  string
+	}{
+		{
+			name: "max_depth",
+			opt:  WithMaxDepth(-1),
+			err:  "exec invalid: WithMaxDepth value -1 is negative",
+		},
+		{
+			name: "max_results",
+			opt:  WithMaxResults(-2),
+			err:  "exec invalid: WithMaxResults value -2 is negative",
+		},
+		{
+			name: "max_result_bytes",
+			opt:  WithMaxResultBytes(-3),
+			err:  "exec invalid: WithMaxResultBytes value -3 is negative",
+		},
+		{
+			name: "parallel",
+			opt:  WithParallel(-4),
+			err:  "exec invalid: WithParallel value -4 is negative",
+		},
+		{
+			name: "trace_limit",
+			opt:  WithTraceLimit(-5),
+			err:  "exec invalid: WithTraceLimit value -5 is negative",
+		},
+		{
+			name: "max_document_bytes",
+			opt:  WithMaxDocumentBytes(-6),
+			err:  "exec invalid: WithMaxDocumentBytes value -6 is negative",
+		},
+		{
+			name: "date_style",
+			opt:  WithDateStyle(types.DateStyle(99)),
+			err:  "exec invalid: unknown date style 99",
+		},
+		{
+			name: "nil_method",
+			opt:  WithMethods(map[string]MethodFunc{"b": nil, "a": nil, "c": JSONString}),
+			err:  `exec invalid: nil function for method "a"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			// Every entry point returns the error. TestIterInvalidOptions
+			// tests the iterators.
+			for fn, call := range map[string]func() error{
+				"Query": func() error { _, err := Query(ctx, path, nil, tc.opt); return err },
+				"First": func() error { _, err := First(ctx, path, nil, tc.opt); return err },
+				"Keys":  func() error { _, err := Keys(ctx, path, nil, tc.opt); return err },
+				"Exists": func() error {
+					_, err := Exists(ctx, path, nil, tc.opt)
+					return err
+				},
+				"Match": func() error {
+					_, err := Match(ctx, path, nil, tc.opt)
+					return err
+				},
+				"AtQuestion": func() error {
+					_, err := AtQuestion(ctx, path, nil, tc.opt)
+					return err
+				},
+				"AtAtTri": func() error {
+					res, err := AtAtTri(ctx, path, nil, tc.opt)
+					a.Equal(Null, res)
+					return err
+				},
+				"QueryAs": func() error {
+					_, err := QueryAs[string](ctx, path, nil, tc.opt)
+					return err
+				},
+				"QueryText": func() error {
+					_, err := QueryText(ctx, path, nil, tc.opt)
+					return err
+				},
+				"QueryLocations": func() error {
+					_, err := QueryLocations(ctx, path, nil, tc.opt)
+					return err
+				},
+				"QueryBatch": func() error {
+					_, err := QueryBatch(ctx, path, []any{nil}, tc.opt)
+					return err
+				},
+				"Project": func() error {
+					_, err := Project(ctx, path, nil, map[string]*ast.AST{"x": path}, tc.opt)
+					return err
+				},
+				"Replace": func() error {
+					_, err := Replace(ctx, path, nil, nil, tc.opt)
+					return err
+				},
+				"Delete": func() error {
+					_, err := Delete(ctx, path, nil, tc.opt)
+					return err
+				},
+				"Explain": func() error {
+					_, err := Explain(ctx, path, nil, tc.opt)
+					return err
+				},
+			} {
+				err := call()
+				r.EqualError(err, tc.err, fn)
+				r.ErrorIs(err, ErrInvalid, fn)
+				a.False(IsSuppressible(err), fn)
+			}
+
+			// WithSilent doesn't suppress it.
+			_, err := Query(ctx, path, nil, tc.opt, WithSilent())
+			r.EqualError(err, tc.err)
+		})
+	}
+}
+
+func TestQueryAndFirstAndExists(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opts  []Option
+		exp   []any
+		err   string
+		isErr error
+		null  bool
+	}{
+		{
+			name:  "root",
+			path:  "$",
+			value: []any{1, 2},
+			exp:   []any{[]any{1, 2}},
+		},
+		{
+			name:  "empty",
+			path:  "$[3]",
+			value: []any{1, 2},
+			exp:   []any{},
+		},
+		{
+			name:  "error",
+			path:  "$.string()",
+			value: []any{1, 2},
+			err:   "exec: jsonpath item method .string() can only be applied to a boolean, string, numeric, or datetime value",
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "silent_no_error",
+			path:  "$.string()",
+			opts:  []Option{WithSilent()},
+			value: []any{1, 2},
+			exp:   []any{},
+			null:  true,
+		},
+		{
+			name:  "null",
+			path:  "$.a",
+			value: map[string]any{"a": nil},
+			exp:   []any{nil},
+		},
+		{
+			name:  "like_regex_object",
+			path:  `$ like_regex "^hi"`,
+			value: map[string]any{"x": "HIGH"},
+			exp:   []any{nil},
+		},
+		{
+			name:  "like_regex_object_filter",
+			path:  `$ ?(@ like_regex "^hi")`,
+			value: map[string]any{"x": "HIGH"},
+			exp:   []any{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// Parse the path.
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			t.Run("query", func(t *testing.T) {
+				t.Parallel()
+				// Run the query.
+				res, err := Query(ctx, path, tc.value, tc.opts...)
+				a.Equal(tc.exp, res)
+
+				// Check the error.
+				if tc.isErr == nil {
+					r.NoError(err)
+				} else {
+					r.EqualError(err, tc.err)
+					r.ErrorIs(err, tc.isErr)
+				}
+			})
+
+			t.Run("first", func(t *testing.T) {
+				t.Parallel()
+				// Run the query.
+				res, err := First(ctx, path, tc.value, tc.opts...)
+				if len(tc.exp) > 0 {
+					a.Equal(tc.exp[0], res)
+				} else {
+					a.Nil(res)
+				}
+
+				// Check the error.
+				if tc.isErr == nil {
+					r.NoError(err)
+				} else {
+					r.EqualError(err, tc.err)
+					r.ErrorIs(err, tc.isErr)
+				}
+			})
+
+			t.Run("query_array", func(t *testing.T) {
+				t.Parallel()
+				// Run the query.
+				res, err := QueryArray(ctx, path, tc.value, tc.opts...)
+
+				// Check the error.
+				if tc.isErr == nil {
+					r.NoError(err)
+					r.NotNil(res)
+					a.Equal(tc.exp, res)
+				} else {
+					r.EqualError(err, tc.err)
+					r.ErrorIs(err, tc.isErr)
+					a.Nil(res)
+				}
+			})
+
+			t.Run("first_or_default", func(t *testing.T) {
+				t.Parallel()
+				// Run the query.
+				def := "default"
+				res, err := FirstOrDefault(ctx, path, tc.value, def, tc.opts...)
+
+				// Check the error.
+				switch {
+				case tc.isErr != nil:
+					r.EqualError(err, tc.err)
+					r.ErrorIs(err, tc.isErr)
+					a.Nil(res)
+				case len(tc.exp) > 0:
+					r.NoError(err)
+					a.Equal(tc.exp[0], res)
+				default:
+					r.NoError(err)
+					a.Equal(def, res)
+				}
+			})
+
+			t.Run("exists", func(t *testing.T) {
+				t.Parallel()
+				// Run the query.
+				res, err := Exists(ctx, path, tc.value, tc.opts...)
+				a.Equal(len(tc.exp) > 0, res)
+
+				// Check the error.
+				if tc.isErr == nil {
+					if tc.null {
+						r.EqualError(err, "NULL")
+						r.ErrorIs(err, NULL)
+					} else {
+						r.NoError(err)
+					}
+				} else {
+					r.EqualError(err, tc.err)
+					r.ErrorIs(err, tc.isErr)
+				}
+			})
+		})
+	}
+}
+
+func TestJSONNumber(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	dec := json.NewDecoder(strings.NewReader(
+		`{"a": 0.1, "b": [1, 2.50, 3e0], "c": 12345678901234567890}`,
+	))
+	dec.UseNumber()
+	var value any
+	r.NoError(dec.Decode(&value))
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opts  []Option
+		exp   []any
+	}{
+		{
+			name: "member",
+			path: "$.a",
+			exp:  []any{json.Number("0.1")},
+		},
+		{
+			name: "big",
+			path: "$.c",
+			exp:  []any{json.Number("12345678901234567890")},
+		},
+		{
+			name: "array_wildcard",
+			path: "$.b[*]",
+			exp:  []any{json.Number("1"), json.Number("2.50"), json.Number("3e0")},
+		},
+		{
+			name: "array_index",
+			path: "$.b[1]",
+			exp:  []any{json.Number("2.50")},
+		},
+		{
+			name: "filter",
+			path: "$.b[*] ? (@ > 2)",
+			exp:  []any{json.Number("2.50"), json.Number("3e0")},
+		},
+		{
+			name: "filter_object",
+			path: "$ ? (@.a < 1).a",
+			exp:  []any{json.Number("0.1")},
+		},
+		{
+			name: "key_value",
+			path: `$.keyvalue() ? (@.key == "a").value`,
+			exp:  []any{json.Number("0.1")},
+		},
+		{
+			name: "add_float",
+			path: "$.a + 1",
+			exp:  []any{float64(1.1)},
+		},
+		{
+			name: "add_int",
+			path: "$.b[0] + 1",
+			exp:  []any{int64(2)},
+		},
+		{
+			name: "add_big",
+			path: "$.c + 0",
+			exp:  []any{float64(12345678901234567890)},
+		},
+		{
+			name: "method",
+			path: "$.b[1].floor()",
+			exp:  []any{float64(2)},
+		},
+		{
+			name:  "typed_map",
+			path:  "$.x",
+			value: map[string]json.Number{"x": "0.1"},
+			exp:   []any{json.Number("0.1")},
+		},
+		{
+			name:  "typed_slice",
+			path:  "$[*] ? (@ > 1)",
+			value: []json.Number{"1.0", "2.0"},
+			exp:   []any{json.Number("2.0")},
+		},
+		{
+			name: "struct",
+			path: "$.n",
+			value: struct {
+				N json.Number `json:"n"`
+			}{"0.1"},
+			opts: []Option{WithStructTags("json")},
+			exp:  []any{json.Number("0.1")},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			val := tc.value
+			if val == nil {
+				val = value
+			}
+
+			res, err := Query(ctx, path, val, tc.opts...)
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+
+			first, err := First(ctx, path, val, tc.opts...)
+			r.NoError(err)
+			a.Equal(tc.exp[0], first)
+		})
+	}
+}
+
+func TestDateTimeOutput(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := types.ContextWithTZ(context.Background(), time.UTC)
+	ist := time.FixedZone("", 5*3600+30*60)
+
+	for _, tc := range []struct {
+		name string
+		path string
+		time time.Time
+		str  string
+	}{
+		{
+			name: "date",
+			path: `"2024-03-05".date()`,
+			time: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+			str:  "2024-03-05",
+		},
+		{
+			name: "time",
+			path: `"12:34:56.789".time()`,
+			time: time.Date(0, 1, 1, 12, 34, 56, 789000000, time.UTC),
+			str:  "12:34:56.789",
+		},
+		{
+			name: "time_tz",
+			path: `"12:34:56+05:30".time_tz()`,
+			time: time.Date(0, 1, 1, 12, 34, 56, 0, ist),
+			str:  "12:34:56+05:30",
+		},
+		{
+			name: "timestamp",
+			path: `"2024-03-05 12:34:56.5".timestamp()`,
+			time: time.Date(2024, 3, 5, 12, 34, 56, 500000000, time.UTC),
+			str:  "2024-03-05T12:34:56.5Z",
+		},
+		{
+			name: "timestamp_tz",
+			path: `"2024-03-05 12:34:56+05:30".timestamp_tz()`,
+			time: time.Date(2024, 3, 5, 7, 4, 56, 0, time.UTC),
+			str:  "2024-03-05T12:34:56+05:30",
+		},
+		{
+			name: "datetime",
+			path: `"2024-03-05T12:34:56-08:00".datetime()`,
+			time: time.Date(2024, 3, 5, 20, 34, 56, 0, time.UTC),
+			str:  "2024-03-05T12:34:56-08:00",
+		},
+		{
+			name: "filter",
+			path: `"2024-03-05".date() ? (@ > "2024-03-04 23:00:00".timestamp())`,
+			time: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+			str:  "2024-03-05",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			// By default, results are types.DateTime values.
+			res, err := First(ctx, path, nil)
+			r.NoError(err)
+			a.Implements((*types.DateTime)(nil), res)
+
+			// Convert to time.Time.
+			for _, opts := range [][]Option{
+				{WithTimeValues()},
+				{WithTimeValues(), WithTimeValues()},
+			} {
+				res, err = First(ctx, path, nil, opts...)
+				r.NoError(err)
+				r.IsType(time.Time{}, res)
+				//nolint:forcetypeassert // Checked above.
+				got := res.(time.Time)
+				a.True(tc.time.Equal(got), "%v != %v", tc.time, got)
+				_, expOff := tc.time.Zone()
+				_, gotOff := got.Zone()
+				a.Equal(expOff, gotOff)
+
+				vals, err := Query(ctx, path, nil, opts...)
+				r.NoError(err)
+				a.Equal([]any{res}, vals)
+			}
+
+			// Convert to string, regardless of WithTimeValues.
+			for _, opts := range [][]Option{
+				{WithStringDateTime()},
+				{WithTimeValues(), WithStringDateTime()},
+				{WithStringDateTime(), WithTimeValues()},
+			} {
+				res, err = First(ctx, path, nil, opts...)
+				r.NoError(err)
+				a.Equal(tc.str, res)
+
+				vals, err := Query(ctx, path, nil, opts...)
+				r.NoError(err)
+				a.Equal([]any{tc.str}, vals)
+			}
+		})
+	}
+
+	t.Run("mixed", func(t *testing.T) {
+		t.Parallel()
+		path, err := parser.Parse(`$[*].date()`)
+		r.NoError(err)
+		res, err := Query(ctx, path, []any{"2024-03-05", "2024-03-06"}, WithStringDateTime())
+		r.NoError(err)
+		a.Equal([]any{"2024-03-05", "2024-03-06"}, res)
+
+		path, err = parser.Parse(`$[*]`)
+		r.NoError(err)
+		res, err = Query(ctx, path, []any{int64(1), "2024-03-05"}, WithTimeValues())
+		r.NoError(err)
+		a.Equal([]any{int64(1), "2024-03-05"}, res)
+	})
+
+	t.Run("seconds_offset", func(t *testing.T) {
+		t.Parallel()
+		path, err := parser.Parse(`$x`)
+		r.NoError(err)
+		tz := time.FixedZone("", 5*3600+30*60+15)
+		vars := WithVars(Vars{"x": types.NewTimeTZ(time.Date(0, 1, 1, 12, 34, 56, 0, tz))})
+		res, err := First(ctx, path, nil, vars, WithStringDateTime())
+		r.NoError(err)
+		a.Equal("12:34:56+05:30", res)
+	})
+}
+
+func TestDisplayTZ(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	plus10 := types.ContextWithTZ(context.Background(), time.FixedZone("", 10*3600))
+	value := []any{"2024-03-05 12:34:56+05:30", "12:34:56+05:30", "2024-03-05 12:34:56"}
+
+	for _, tc := range []struct {
+		name string
+		ctx  context.Context
+		path string
+		opt  []Option
+		exp  []any
+		text []string
+	}{
+		{
+			name: "timestamp_tz",
+			ctx:  plus10,
+			path: `$[0].datetime()`,
+			exp:  []any{pt(plus10, "2024-03-05T17:04:56+10:00")},
+			text: []string{`"2024-03-05T17:04:56+10:00"`},
+		},
+		{
+			name: "default_utc",
+			ctx:  context.Background(),
+			path: `$[0].datetime()`,
+			exp:  []any{pt(context.Background(), "2024-03-05T07:04:56+00:00")},
+			text: []string{`"2024-03-05T07:04:56+00:00"`},
+		},
+		{
+			name: "time_tz",
+			ctx:  plus10,
+			path: `$[1].datetime()`,
+			exp:  []any{pt(plus10, "12:34:56+05:30")},
+			text: []string{`"12:34:56+05:30"`},
+		},
+		{
+			name: "timestamp",
+			ctx:  plus10,
+			path: `$[2].datetime()`,
+			exp:  []any{pt(plus10, "2024-03-05T12:34:56")},
+			text: []string{`"2024-03-05T12:34:56"`},
+		},
+		{
+			name: "filter",
+			ctx:  plus10,
+			path: `$[0].datetime() ? (@ == "2024-03-05 07:04:56Z".timestamp_tz())`,
+			exp:  []any{pt(plus10, "2024-03-05T17:04:56+10:00")},
+			text: []string{`"2024-03-05T17:04:56+10:00"`},
+		},
+		{
+			name: "string",
+			ctx:  plus10,
+			path: `$[0].datetime()`,
+			opt:  []Option{WithStringDateTime()},
+			exp:  []any{"2024-03-05T17:04:56+10:00"},
+			text: []string{`"2024-03-05T17:04:56+10:00"`},
+		},
+		{
+			name: "copy",
+			ctx:  plus10,
+			path: `$[0].datetime()`,
+			opt:  []Option{WithCopyResults()},
+			exp:  []any{pt(plus10, "2024-03-05T17:04:56+10:00")},
+			text: []string{`"2024-03-05T17:04:56+10:00"`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			opt := append([]Option{WithDisplayTZ()}, tc.opt...)
+
+			res, err := Query(tc.ctx, path, value, opt...)
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+
+			first, err := First(tc.ctx, path, value, opt...)
+			r.NoError(err)
+			a.Equal(tc.exp[0], first)
+
+			text, err := QueryText(tc.ctx, path, value, opt...)
+			r.NoError(err)
+			a.Equal(tc.text, text)
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+		path, err := parser.Parse(`$[0].datetime()`)
+		r.NoError(err)
+		res, err := Query(plus10, path, value)
+		r.NoError(err)
+		a.Equal([]any{pt(plus10, "2024-03-05T12:34:56+05:30")}, res)
+		text, err := QueryText(plus10, path, value)
+		r.NoError(err)
+		a.Equal([]string{`"2024-03-05T12:34:56+05:30"`}, text)
+	})
+}
+
+func TestCopyResults(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	date := func() *types.Date { return types.NewDate(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) }
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		json   func() any
+		exp    any
+		mutate func(res any)
+		alias  bool // whether the default result aliases the input
+	}{
+		{
+			name:   "object",
+			path:   `$.a`,
+			json:   func() any { return map[string]any{"a": map[string]any{"b": int64(1)}} },
+			exp:    map[string]any{"b": int64(1)},
+			mutate: func(res any) { res.(map[string]any)["b"] = "x" },
+			alias:  true,
+		},
+		{
+			name:   "array",
+			path:   `$.a`,
+			json:   func() any { return map[string]any{"a": []any{int64(1), int64(2)}} },
+			exp:    []any{int64(1), int64(2)},
+			mutate: func(res any) { res.([]any)[0] = "x" },
+			alias:  true,
+		},
+		{
+			name: "nested",
+			path: `$`,
+			json: func() any {
+				return map[string]any{"a": []any{map[string]any{"b": []any{json.Number("1.50")}}}}
+			},
+			exp: map[string]any{"a": []any{map[string]any{"b": []any{json.Number("1.50")}}}},
+			mutate: func(res any) {
+				obj := res.(map[string]any)["a"].([]any)[0].(map[string]any)
+				obj["b"].([]any)[0] = "x"
+			},
+			alias: true,
+		},
+		{
+			name:   "keyvalue_value",
+			path:   `$.keyvalue()`,
+			json:   func() any { return map[string]any{"a": map[string]any{"b": int64(1)}} },
+			exp:    map[string]any{"id": int64(0), "key": "a", "value": map[string]any{"b": int64(1)}},
+			mutate: func(res any) { res.(map[string]any)["value"].(map[string]any)["b"] = "x" },
+			alias:  true,
+		},
+		{
+			name:   "keyvalue_object",
+			path:   `$.keyvalue()`,
+			json:   func() any { return map[string]any{"a": int64(1)} },
+			exp:    map[string]any{"id": int64(0), "key": "a", "value": int64(1)},
+			mutate: func(res any) { res.(map[string]any)["key"] = "x" },
+			alias:  false,
+		},
+		{
+			name:   "typed_slice",
+			path:   `$.a`,
+			json:   func() any { return map[string][]int64{"a": {1, 2}} },
+			exp:    []any{int64(1), int64(2)},
+			mutate: func(res any) { setFirst(res, int64(42)) },
+			alias:  true,
+		},
+		{
+			name:   "typed_nested",
+			path:   `$`,
+			json:   func() any { return map[string]map[string][]string{"a": {"b": {"c"}}} },
+			exp:    map[string]any{"a": map[string]any{"b": []any{"c"}}},
+			mutate: func(res any) { setFirst(getIndex(getIndex(res, "a"), "b"), "x") },
+			alias:  true,
+		},
+		{
+			name:   "date",
+			path:   `$.a`,
+			json:   func() any { return map[string]any{"a": date()} },
+			exp:    date(),
+			mutate: func(res any) { *res.(*types.Date) = *types.NewDate(time.Time{}) },
+			alias:  true,
+		},
+		{
+			name:   "scalar",
+			path:   `$.a`,
+			json:   func() any { return map[string]any{"a": json.Number("1.0")} },
+			exp:    json.Number("1.0"),
+			mutate: func(any) {},
+			alias:  false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			orig := tc.json()
+
+			// By default, results may alias the input.
+			input := tc.json()
+			res, err := First(ctx, path, input)
+			r.NoError(err)
+			tc.mutate(res)
+			if tc.alias {
+				a.NotEqual(orig, input)
+			} else {
+				a.Equal(orig, input)
+			}
+
+			// With WithCopyResults, they never do.
+			for _, query := range []func(any) (any, error){
+				func(val any) (any, error) { return First(ctx, path, val, WithCopyResults()) },
+				func(val any) (any, error) {
+					res, err := Query(ctx, path, val, WithCopyResults())
+					r.Len(res, 1)
+					return res[0], err
+				},
+			} {
+				input := tc.json()
+				res, err := query(input)
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+				tc.mutate(res)
+				a.Equal(orig, input)
+			}
+		})
+	}
+
+	t.Run("values", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse(`$`)
+		r.NoError(err)
+		orig := map[string]any{"a": []any{int64(1)}}
+		input := map[string]any{"a": []any{int64(1)}}
+
+		res, err := Values(ctx, path, input, WithCopyResults())
+		r.NoError(err)
+		a.Equal([]any{[]any{int64(1)}}, res)
+		res[0].([]any)[0] = "x"
+		a.Equal(orig, input)
+
+		res, err = Values(ctx, path, input)
+		r.NoError(err)
+		res[0].([]any)[0] = "x"
+		a.NotEqual(orig, input)
+	})
+
+	t.Run("invalid_typed_map", func(t *testing.T) {
+		t.Parallel()
+		r := require.New(t)
+		path, err := parser.Parse(`$`)
+		r.NoError(err)
+		res, err := Query(ctx, path, map[string]any{"a": map[int]string{1: "x"}}, WithCopyResults())
+		r.EqualError(err, "exec: cannot query map[int]string: map keys must be strings")
+		r.ErrorIs(err, ErrExecution)
+		r.Nil(res)
+	})
+
+	t.Run("time_values", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse(`$.a`)
+		r.NoError(err)
+		res, err := First(ctx, path, map[string]any{"a": date()}, WithCopyResults(), WithTimeValues())
+		r.NoError(err)
+		a.Equal(date().GoTime().UTC(), res)
+	})
+}
+
+// setFirst sets the first element of the slice or array val to v.
+func setFirst(val, v any) {
+	reflect.ValueOf(val).Index(0).Set(reflect.ValueOf(v))
+}
+
+// getIndex returns the value for key in the map val.
+func getIndex(val any, key string) any {
+	return reflect.ValueOf(val).MapIndex(reflect.ValueOf(key)).Interface()
+}
+
+func TestErrorLocation(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	doc := map[string]any{
+		"a": map[string]any{
+			"b": []any{
+				int64(0),
+				int64(1),
+				map[string]any{"c": map[string]any{"e": true}},
+			},
+		},
+		"x": []any{
+			map[string]any{"y": "hi"},
+			map[string]any{"z": "yo"},
+		},
+		"s": []any{map[string]any{"t": "nope"}},
+	}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opts  []Option
+		err   string
+		isErr error
+		loc   string
+	}{
+		{
+			name:  "missing_key",
+			path:  "strict $.a.b[2].c.d",
+			err:   `exec: JSON object does not contain key "d"`,
+			isErr: ErrVerbose,
+			loc:   `$."a"."b"[2]."c"`,
+		},
+		{
+			name:  "subscript_out_of_bounds",
+			path:  "strict $.a.b[5]",
+			err:   "exec: jsonpath array subscript is out of bounds",
+			isErr: ErrVerbose,
+			loc:   `$."a"."b"`,
+		},
+		{
+			name:  "array_wildcard",
+			path:  "strict $.x[*].y",
+			err:   `exec: JSON object does not contain key "y"`,
+			isErr: ErrVerbose,
+			loc:   `$."x"[1]`,
+		},
+		{
+			name:  "member_wildcard",
+			path:  "strict $.a.*[5]",
+			err:   "exec: jsonpath array subscript is out of bounds",
+			isErr: ErrVerbose,
+			loc:   `$."a"."b"`,
+		},
+		{
+			name:  "any",
+			path:  "strict $.a.**{3}.e.double()",
+			err:   "exec: jsonpath item method .double() can only be applied to a string or numeric value",
+			isErr: ErrVerbose,
+			loc:   `$."a"."b"[2]."c"."e"`,
+		},
+		{
+			name:  "lax_unwrap",
+			path:  "$.s.t.double()",
+			err:   `exec: argument "nope" of jsonpath item method .double() is invalid for type double precision`,
+			isErr: ErrExecution,
+			loc:   `$."s"[0]."t"`,
+		},
+		{
+			name:  "variable",
+			path:  "strict $v.a.b",
+			opts:  []Option{WithVars(Vars{"v": map[string]any{"a": map[string]any{}}})},
+			err:   `exec: JSON object does not contain key "b"`,
+			isErr: ErrVerbose,
+			loc:   `$"v"."a"`,
+		},
+		{
+			name: "root_in_filter",
+			path: "strict $.x[*] ? (@.y == $.a.b[1].c)",
+			err:  "", // Filter errors are unknown results.
+		},
+		{
+			name:  "arithmetic",
+			path:  "$.a.b[0] / 0",
+			err:   "exec: division by zero",
+			isErr: ErrExecution,
+			loc:   "$",
+		},
+		{
+			name:  "arithmetic_in_path",
+			path:  `$.a.b[2].c.e.size() + $.x[0].y`,
+			err:   "exec: right operand of jsonpath operator + is not a single numeric value",
+			isErr: ErrVerbose,
+			loc:   "$",
+		},
+		{
+			name: "silent",
+			path: "strict $.a.b[2].c.d",
+			opts: []Option{WithSilent()},
+		},
+		{
+			name:  "deep_chain_missing_key",
+			path:  accessorChainPath(500) + ".b",
+			value: accessorChainDoc(500),
+			err:   `exec: JSON object does not contain key "b"`,
+			isErr: ErrVerbose,
+			loc:   "$" + strings.Repeat(`."a"`, 500),
+		},
+		{
+			name:  "deep_chain_short_doc",
+			path:  accessorChainPath(500),
+			value: accessorChainDoc(300),
+			err:   `exec: JSON object does not contain key "a"`,
+			isErr: ErrVerbose,
+			loc:   "$" + strings.Repeat(`."a"`, 300),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			value := tc.value
+			if value == nil {
+				value = doc
+			}
+			_, err = Query(ctx, path, value, tc.opts...)
+			if tc.err == "" {
+				r.NoError(err)
+				return
+			}
+
+			r.EqualError(err, tc.err)
+			r.ErrorIs(err, tc.isErr)
+			var execErr *Error
+			r.ErrorAs(err, &execErr)
+			a.Equal(tc.loc, execErr.Path())
+			a.Equal(tc.err, execErr.Error())
+			r.ErrorIs(execErr.Unwrap(), tc.isErr)
+
+			// Exists returns the same error.
+			_, err = Exists(ctx, path, value, tc.opts...)
+			r.ErrorAs(err, &execErr)
+			a.Equal(tc.loc, execErr.Path())
+		})
+	}
+
+	t.Run("not_execution_error", func(t *testing.T) {
+		t.Parallel()
+		path, err := parser.Parse("$.a")
+		r.NoError(err)
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = Query(ctx, path, doc)
+		r.ErrorIs(err, context.Canceled)
+		var execErr *Error
+		a.False(errors.As(err, &execErr))
+	})
+}
+
+func TestCancellation(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	// Generate a deeply-nested document.
+	var doc any = "leaf"
+	for i := range 5000 {
+		doc = map[string]any{
+			"level": float64(i),
+			"items": []any{"a", "b", "c", doc},
+		}
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	for _, tc := range []struct {
+		name  string
+		ctx   context.Context //nolint:containedctx
+		isErr error
+	}{
+		{"canceled", canceled, context.Canceled},
+		{"deadline", expired, context.DeadlineExceeded},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			for _, p := range []string{
+				`$.**{0 to last}`,
+				`lax $.** ? (@ like_regex "^x")`,
+				`$.** ? (@.level > 10).items[*]`,
+			} {
+				path, err := parser.Parse(p)
+				r.NoError(err)
+
+				start := time.Now()
+				res, err := Query(tc.ctx, path, doc)
+				r.ErrorIs(err, tc.isErr)
+				r.NotErrorIs(err, ErrExecution)
+				a.Nil(res)
+
+				ok, err := Exists(tc.ctx, path, doc, WithSilent())
+				r.ErrorIs(err, tc.isErr)
+				a.False(ok)
+				a.Less(time.Since(start), time.Second)
+			}
+
+			// Wildcard descent without a next node should also check.
+			e := newTestExecutor(laxRootPath, nil, true, false)
+			list := newList()
+			res, err := e.executeAnyItem(tc.ctx, nil, []any{doc}, nil, list, 0, 0, math.MaxUint32, false, false)
+			a.Equal(statusFailed, res)
+			r.ErrorIs(err, tc.isErr)
+			a.Empty(list.list)
+		})
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	// Generate a deeply-nested document.
+	deep := func(depth int) any {
+		var doc any = "leaf"
+		for range depth {
+			doc = []any{doc}
+		}
+		return doc
+	}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opts  []Option
+		exp   int
+		err   bool
+	}{
+		{
+			name:  "shallow",
+			path:  "$.**",
+			value: deep(100),
+			exp:   101,
+		},
+		{
+			name:  "deep",
+			path:  "$.**",
+			value: deep(DefaultMaxDepth + 1),
+			err:   true,
+		},
+		{
+			name:  "deep_silent",
+			path:  "$.** ? (@ == 1)",
+			value: deep(DefaultMaxDepth + 1),
+			opts:  []Option{WithSilent()},
+			err:   true,
+		},
+		{
+			name:  "deep_unlimited",
+			path:  "$.**",
+			value: deep(DefaultMaxDepth + 1),
+			opts:  []Option{WithMaxDepth(0)},
+			exp:   DefaultMaxDepth + 2,
+		},
+		{
+			name:  "low_max",
+			path:  "$.**",
+			value: deep(10),
+			opts:  []Option{WithMaxDepth(5)},
+			err:   true,
+		},
+		{
+			name:  "long_path",
+			path:  "$.a.a.a.a.a",
+			value: js(`{"a": {"a": {"a": {"a": {"a": 1}}}}}`),
+			opts:  []Option{WithMaxDepth(3)},
+			err:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := Query(ctx, path, tc.value, tc.opts...)
+			if tc.err {
+				r.EqualError(err, "exec: maximum recursion depth exceeded")
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(res)
+				return
+			}
+
+			r.NoError(err)
+			a.Len(res, tc.exp)
+		})
+	}
+
+	t.Run("mutation", func(t *testing.T) {
+		t.Parallel()
+		path, err := parser.Parse("$.**")
+		r.NoError(err)
+
+		res, err := Delete(ctx, path, deep(DefaultMaxDepth+1))
+		r.EqualError(err, "exec: maximum recursion depth exceeded")
+		r.ErrorIs(err, ErrExecution)
+		a.Nil(res)
+	})
+}
+
+func TestMalformedValues(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	cyclicMap := map[string]any{"a": int64(1)}
+	cyclicMap["self"] = cyclicMap
+	cyclicSlice := []any{int64(1), nil}
+	cyclicSlice[1] = cyclicSlice
+	indirect := map[string]any{"b": []any{nil}}
+	indirect["b"].([]any)[0] = indirect
+	shared := map[string]any{"x": int64(1)}
+	type point struct{ X, Y int }
+	type object map[string]any
+	cyclicTyped := object{"a": int64(1)}
+	cyclicTyped["self"] = cyclicTyped
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		value  any
+		opts   []Option
+		exp    []any
+		exists bool
+		err    string
+		loc    string
+	}{
+		{
+			name:   "cyclic_map_any",
+			path:   "$.**",
+			value:  cyclicMap,
+			opts:   []Option{WithOrderedKeys()},
+			err:    "exec: encountered a cycle via map[string]interface {}",
+			loc:    `$."self"."self"`,
+			exists: true,
+		},
+		{
+			name:  "cyclic_map_any_unlimited",
+			path:  "$.** ? (@ == 2)",
+			value: cyclicMap,
+			opts:  []Option{WithMaxDepth(0), WithSilent()},
+			err:   "exec: encountered a cycle via map[string]interface {}",
+			loc:   `$."self"."self"`,
+		},
+		{
+			name:   "cyclic_map_levels",
+			path:   "$.**{0 to 2}.a",
+			value:  cyclicMap,
+			exp:    []any{int64(1), int64(1), int64(1)},
+			exists: true,
+		},
+		{
+			name:   "cyclic_map_member",
+			path:   "$.self.self.a",
+			value:  cyclicMap,
+			exp:    []any{int64(1)},
+			exists: true,
+		},
+		{
+			name:   "cyclic_map_keyvalue",
+			path:   "$.self.keyvalue().key",
+			value:  cyclicMap,
+			exp:    []any{"a", "self"},
+			exists: true,
+		},
+		{
+			name:   "cyclic_typed_map_any",
+			path:   "$.**",
+			value:  cyclicTyped,
+			opts:   []Option{WithOrderedKeys()},
+			err:    "exec: encountered a cycle via exec.object",
+			loc:    `$."self"."self"`,
+			exists: true,
+		},
+		{
+			name:   "cyclic_slice_any",
+			path:   "$.**",
+			value:  cyclicSlice,
+			err:    "exec: encountered a cycle via []interface {}",
+			loc:    "$[1][1]",
+			exists: true,
+		},
+		{
+			name:   "cyclic_slice_index",
+			path:   "$[1][1][0]",
+			value:  cyclicSlice,
+			exp:    []any{int64(1)},
+			exists: true,
+		},
+		{
+			name:   "cyclic_slice_size",
+			path:   "strict $[1][1].size()",
+			value:  cyclicSlice,
+			exp:    []any{int64(2)},
+			exists: true,
+		},
+		{
+			name:   "indirect_cycle",
+			path:   "$.**",
+			value:  indirect,
+			err:    "exec: encountered a cycle via []interface {}",
+			loc:    `$."b"[0]."b"`,
+			exists: true,
+		},
+		{
+			name:   "shared_not_cyclic",
+			path:   "$.** ? (@ == 1)",
+			value:  []any{shared, shared, map[string]any{"y": shared}},
+			exp:    []any{int64(1), int64(1), int64(1)},
+			exists: true,
+		},
+		{
+			name:  "struct",
+			path:  "$.a",
+			value: map[string]any{"a": point{1, 2}},
+			err:   "exec: unsupported value type exec.point",
+			loc:   `$."a"`,
+		},
+		{
+			name:  "struct_keyvalue",
+			path:  "$.a.keyvalue()",
+			value: map[string]any{"a": point{1, 2}},
+			err:   "exec: unsupported value type exec.point",
+			loc:   `$."a"`,
+		},
+		{
+			name:   "struct_keyvalue_value",
+			path:   "$.keyvalue()",
+			value:  map[string]any{"a": point{1, 2}},
+			err:    "exec: unsupported value type exec.point",
+			loc:    `$."a"`,
+			exists: true,
+		},
+		{
+			name:  "struct_keyvalue_field",
+			path:  "$.keyvalue().value",
+			value: map[string]any{"a": point{1, 2}},
+			err:   "exec: unsupported value type exec.point",
+			loc:   `$."a"`,
+		},
+		{
+			name:  "chan_keyvalue_value",
+			path:  "$.keyvalue() ? (@.key == \"a\")",
+			value: map[string]any{"a": make(chan int)},
+			err:   "exec: unsupported value type chan int",
+			loc:   `$."a"`,
+		},
+		{
+			name:   "struct_any",
+			path:   "$.**",
+			value:  map[string]any{"a": []any{point{1, 2}}},
+			err:    "exec: unsupported value type exec.point",
+			loc:    `$."a"[0]`,
+			exists: true,
+		},
+		{
+			name:  "struct_silent",
+			path:  "$.a",
+			value: map[string]any{"a": &point{1, 2}},
+			opts:  []Option{WithSilent()},
+			err:   "exec: unsupported value type *exec.point",
+			loc:   `$."a"`,
+		},
+		{
+			name:  "chan",
+			path:  "$.a ? (@ == 1)",
+			value: map[string]any{"a": make(chan int)},
+			err:   "exec: unsupported value type chan int",
+			loc:   `$."a"`,
+		},
+		{
+			name:  "root_func",
+			path:  "$",
+			value: func() {},
+			err:   "exec: unsupported value type func()",
+		},
+		{
+			name:   "struct_tags",
+			path:   "$.a.X",
+			value:  map[string]any{"a": point{1, 2}},
+			opts:   []Option{WithStructTags("json")},
+			exp:    []any{int64(1)},
+			exists: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := Query(ctx, path, tc.value, tc.opts...)
+			exists, existsErr := Exists(ctx, path, tc.value, tc.opts...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.False(IsSuppressible(err))
+				var execErr *Error
+				if tc.loc == "" {
+					a.NotErrorAs(err, &execErr)
+				} else {
+					r.ErrorAs(err, &execErr)
+					a.Equal(tc.loc, execErr.Path())
+				}
+				a.Nil(res)
+				if tc.exists {
+					// Exists stops at the first item.
+					r.NoError(existsErr)
+					a.True(exists)
+				} else {
+					r.EqualError(existsErr, tc.err)
+				}
+				return
+			}
+
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+			r.NoError(existsErr)
+			a.Equal(tc.exists, exists)
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opts  []Option
+		exp   bool
+		err   string
+		isErr error
+	}{
+		{
+			name:  "root_eq",
+			path:  "$ == 42",
+			value: int64(42),
+			exp:   true,
+		},
+		{
+			name:  "root_ne",
+			path:  "$ != 42",
+			value: int64(42),
+			exp:   false,
+		},
+		{
+			name:  "null",
+			path:  "$.string() == 12",
+			value: []any{1, 2},
+			err:   "NULL",
+			isErr: NULL,
+		},
+		{
+			name:  "strict_null",
+			path:  "strict $.string() == 12",
+			value: []any{1, 2},
+			err:   "NULL",
+			isErr: NULL,
+		},
+		{
+			name:  "not_boolean",
+			path:  "$",
+			value: []any{1, 2},
+			err:   "exec: single boolean result is expected",
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "not_boolean_silent",
+			path:  "$",
+			opts:  []Option{WithSilent()},
+			value: []any{1, 2},
+			err:   "NULL",
+			isErr: NULL,
+		},
+		{
+			name:  "single_boolean_non_predicate",
+			path:  "$",
+			value: true,
+			exp:   true,
+		},
+		{
+			name:  "error",
+			path:  `strict $.a`,
+			value: map[string]any{},
+			err:   `exec: JSON object does not contain key "a"`,
+			isErr: ErrVerbose,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// Parse the path.
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			// Run the query.
+			res, err := Match(ctx, path, tc.value, tc.opts...)
+			a.Equal(tc.exp, res)
+
+			// Check the error.
+			if tc.isErr == nil {
+				r.NoError(err)
+			} else {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, tc.isErr)
+			}
+		})
+	}
+}
+
+func TestOperators(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		match bool
+		exp   bool
+		err   string
+		opExp bool
+		opErr error
+	}{
+		{
+			name:  "exists",
+			path:  "$[0]",
+			value: []any{int64(1)},
+			exp:   true,
+			opExp: true,
+		},
+		{
+			name:  "not_exists",
+			path:  "$[1]",
+			value: []any{int64(1)},
+		},
+		{
+			name:  "strict_out_of_bounds",
+			path:  "strict $[1]",
+			value: []any{int64(1)},
+			err:   "exec: jsonpath array subscript is out of bounds",
+			opErr: NULL,
+		},
+		{
+			name:  "strict_missing_key",
+			path:  "strict $.a",
+			value: map[string]any{"b": 1},
+			err:   `exec: JSON object does not contain key "a"`,
+			opErr: NULL,
+		},
+		{
+			name:  "filter_unknown",
+			path:  `$ ? (@ == "x" || @ > 1)`,
+			value: true,
+		},
+		{
+			name:  "match",
+			path:  "$[0] == 1",
+			value: []any{int64(1)},
+			match: true,
+			exp:   true,
+			opExp: true,
+		},
+		{
+			name:  "match_unknown",
+			path:  `$[0] == "x"`,
+			value: []any{int64(1)},
+			match: true,
+			err:   "NULL",
+			opErr: NULL,
+		},
+		{
+			name:  "match_not_boolean",
+			path:  "$[0]",
+			value: []any{int64(1)},
+			match: true,
+			err:   "exec: single boolean result is expected",
+			opErr: NULL,
+		},
+		{
+			name:  "match_strict_out_of_bounds",
+			path:  "strict $[1]",
+			value: []any{int64(1)},
+			match: true,
+			err:   "exec: jsonpath array subscript is out of bounds",
+			opErr: NULL,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			fn, op := Exists, AtQuestion
+			if tc.match {
+				fn, op = Match, AtAt
+			}
+
+			// Test the function.
+			res, err := fn(ctx, path, tc.value)
+			a.Equal(tc.exp, res)
+			if tc.err == "" {
+				r.NoError(err)
+			} else {
+				r.EqualError(err, tc.err)
+			}
+
+			// Test the operator.
+			opts := make([]Option, 0, 1)
+			res, err = op(ctx, path, tc.value, opts...)
+			a.Equal(tc.opExp, res)
+			if tc.opErr == nil {
+				r.NoError(err)
+			} else {
+				r.ErrorIs(err, tc.opErr)
+			}
+
+			// The operator should not append to the caller's options.
+			a.Nil(opts[:1][0])
+		})
+	}
+}
+
+func TestExistsOrMatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opt   []Option
+		exp   Ternary
+		err   string
+	}{
+		{
+			name:  "match_true",
+			path:  `$ == "2"`,
+			value: "2",
+			exp:   True,
+		},
+		{
+			name:  "match_false",
+			path:  `$ == "2"`,
+			value: "3",
+			exp:   False,
+		},
+		{
+			name:  "match_null",
+			path:  `$ == "2"`,
+			value: int64(2),
+			exp:   Null,
+		},
+		{
+			name:  "match_vars",
+			path:  `$ == $x`,
+			value: "2",
+			opt:   []Option{WithVars(Vars{"x": "2"})},
+			exp:   True,
+		},
+		{
+			name:  "exists_true",
+			path:  `$.a`,
+			value: map[string]any{"a": int64(1)},
+			exp:   True,
+		},
+		{
+			name:  "exists_false",
+			path:  `$.a`,
+			value: map[string]any{"b": int64(1)},
+			exp:   False,
+		},
+		{
+			name:  "exists_boolean",
+			path:  `$.a`,
+			value: map[string]any{"a": false},
+			exp:   True,
+		},
+		{
+			name:  "exists_null",
+			path:  `strict $.a`,
+			value: map[string]any{"b": int64(1)},
+			exp:   Null,
+		},
+		{
+			name:  "predicate_check",
+			path:  `$ == "2"`,
+			value: "2",
+			opt:   []Option{WithPredicateCheck()},
+			exp:   True,
+		},
+		{
+			name:  "error",
+			path:  `$ == $x`,
+			value: "2",
+			err:   `exec: could not find jsonpath variable "x"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			tri, err := ExistsOrMatchTri(ctx, path, tc.value, tc.opt...)
+			a.Equal(tc.exp, tri)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+			} else {
+				r.NoError(err)
+			}
+
+			// Should return the same result as the operator function for
+			// the kind of path expression.
+			op := AtQuestionTri
+			if path.IsPredicate() {
+				op = AtAtTri
+			}
+			opTri, opErr := op(ctx, path, tc.value, tc.opt...)
+			a.Equal(opTri, tri)
+			a.Equal(opErr, err)
+
+			res, err := ExistsOrMatch(ctx, path, tc.value, tc.opt...)
+			a.Equal(tc.exp == True, res)
+			switch {
+			case tc.err != "":
+				r.EqualError(err, tc.err)
+			case tc.exp == Null:
+				r.ErrorIs(err, NULL)
+			default:
+				r.NoError(err)
+			}
+		})
+	}
+}
+
+func TestKeysAndValues(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	kvErr := "exec: jsonpath item method .keyvalue() can only be applied to an object"
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		value  any
+		opts   []Option
+		keys   []string
+		values []any
+		err    string
+	}{
+		{
+			name:   "root",
+			path:   "$",
+			value:  map[string]any{"b": int64(1), "a": "x"},
+			keys:   []string{"a", "b"},
+			values: []any{"x", int64(1)},
 		},
 		{
-			name:  "root_ne",
-			path:  "$ != 42",
-			value: int64(42),
-			exp:   false,
+			name:   "nested",
+			path:   "$.a",
+			value:  map[string]any{"a": map[string]any{"y": true, "x": nil}},
+			keys:   []string{"x", "y"},
+			values: []any{nil, true},
 		},
 		{
-			name:  "null",
-			path:  "$.string() == 12",
-			value: []any{1, 2},
-			err:   "NULL",
-			isErr: NULL,
+			name:   "empty",
+			path:   "$",
+			value:  map[string]any{},
+			keys:   []string{},
+			values: []any{},
 		},
 		{
-			name:  "strict_null",
-			path:  "strict $.string() == 12",
-			value: []any{1, 2},
-			err:   "NULL",
-			isErr: NULL,
+			name:   "no_match",
+			path:   "$.nope",
+			value:  map[string]any{"a": int64(1)},
+			keys:   []string{},
+			values: []any{},
 		},
 		{
-			name:  "not_boolean",
-			path:  "$",
-			value: []any{1, 2},
-			err:   "exec: single boolean result is expected",
-			isErr: ErrVerbose,
+			name: "multiple",
+			path: "$[*]",
+			value: []any{
+				map[string]any{"b": int64(1), "a": int64(2)},
+				map[string]any{"c": int64(3)},
+			},
+			keys:   []string{"a", "b", "c"},
+			values: []any{int64(2), int64(1), int64(3)},
 		},
 		{
-			name:  "not_boolean_silent",
-			path:  "$",
-			opts:  []Option{WithSilent()},
-			value: []any{1, 2},
-			err:   "NULL",
-			isErr: NULL,
+			name:   "lax_unwrap",
+			path:   "$",
+			value:  []any{map[string]any{"a": int64(1)}, map[string]any{"b": int64(2)}},
+			keys:   []string{"a", "b"},
+			values: []any{int64(1), int64(2)},
 		},
 		{
-			name:  "single_boolean_non_predicate",
-			path:  "$",
-			value: true,
-			exp:   true,
+			name:  "strict_array",
+			path:  "strict $",
+			value: []any{map[string]any{"a": int64(1)}},
+			err:   kvErr,
 		},
 		{
-			name:  "error",
-			path:  `strict $.a`,
+			name:  "scalar",
+			path:  "$.a",
+			value: map[string]any{"a": int64(1)},
+			err:   kvErr,
+		},
+		{
+			name:   "scalar_silent",
+			path:   "$.a",
+			value:  map[string]any{"a": int64(1)},
+			opts:   []Option{WithSilent()},
+			keys:   []string{},
+			values: []any{},
+		},
+		{
+			name:   "silent_stops",
+			path:   "$[*]",
+			value:  []any{map[string]any{"a": int64(1)}, true, map[string]any{"b": int64(2)}},
+			opts:   []Option{WithSilent()},
+			keys:   []string{"a"},
+			values: []any{int64(1)},
+		},
+		{
+			name:   "vars",
+			path:   "$.* ? (@.x == $x)",
+			value:  map[string]any{"a": map[string]any{"x": int64(1)}, "b": map[string]any{"x": int64(2)}},
+			opts:   []Option{WithVars(Vars{"x": int64(2)})},
+			keys:   []string{"x"},
+			values: []any{int64(2)},
+		},
+		{
+			name:  "path_error",
+			path:  "strict $.a",
 			value: map[string]any{},
 			err:   `exec: JSON object does not contain key "a"`,
-			isErr: ErrVerbose,
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			// Parse the path.
 			path, err := parser.Parse(tc.path)
 			r.NoError(err)
 
-			// Run the query.
-			res, err := Match(ctx, path, tc.value, tc.opts...)
-			a.Equal(tc.exp, res)
+			keys, err := Keys(ctx, path, tc.value, tc.opts...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(keys)
+			} else {
+				r.NoError(err)
+				a.Equal(tc.keys, keys)
+			}
 
-			// Check the error.
-			if tc.isErr == nil {
+			values, err := Values(ctx, path, tc.value, tc.opts...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(values)
+			} else {
+				r.NoError(err)
+				a.Equal(tc.values, values)
+			}
+
+			// Results should be the same as with .keyvalue() appended.
+			for field, exp := range map[string]any{"key": keys, "value": values} {
+				path, err := parser.Parse(tc.path + ".keyvalue()." + field)
+				r.NoError(err)
+				res, err := Query(ctx, path, tc.value, tc.opts...)
+				if tc.err != "" {
+					r.EqualError(err, tc.err)
+					continue
+				}
+				r.NoError(err)
+				if field == "key" {
+					strs := make([]string, len(res))
+					for i, v := range res {
+						strs[i], _ = v.(string)
+					}
+					a.Equal(exp, strs)
+				} else {
+					a.Equal(exp, res)
+				}
+			}
+		})
+	}
+}
+
+func TestPredicateCheck(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	value := map[string]any{"a": "xyz", "b": []any{int64(1), int64(2)}}
+
+	for _, tc := range []struct {
+		name string
+		path string
+		pred bool
+	}{
+		{"key", "$.a", false},
+		{"filter_starts_with", `$.a ? (@ starts with "x")`, false},
+		{"filter_exists", "$ ? (exists (@.b))", false},
+		{"filter_is_unknown", `$.b ? ((@ > "x") is unknown)`, false},
+		{"filter_like_regex", `$.a ? (@ like_regex "^x")`, false},
+		{"math", "$.b[0] + 1", false},
+		{"compare", "$.b[0] == 1", true},
+		{"starts_with", `$.a starts with "x"`, true},
+		{"exists", "exists ($.b)", true},
+		{"is_unknown", `($.a > 1) is unknown`, true},
+		{"like_regex", `$.a like_regex "^x"`, true},
+		{"not", "!($.b[0] == 2)", true},
+		{"and_or", `$.b[0] == 1 && ($.a starts with "y" || exists ($.a))`, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			a.Equal(tc.pred, path.IsPredicate())
+
+			sqlErr := fmt.Sprintf(
+				"exec: %%v expects a SQL standard path expression but %q is a predicate check expression",
+				path,
+			)
+			predErr := fmt.Sprintf(
+				"exec: %%v expects a predicate check expression but %q is a SQL standard path expression",
+				path,
+			)
+			opt := WithPredicateCheck()
+
+			// Functions that expect SQL standard path expressions.
+			for name, fn := range map[string]func() (any, error){
+				"Query":          func() (any, error) { return Query(ctx, path, value, opt) },
+				"QueryArray":     func() (any, error) { return QueryArray(ctx, path, value, opt) },
+				"First":          func() (any, error) { return First(ctx, path, value, opt) },
+				"FirstOrDefault": func() (any, error) { return FirstOrDefault(ctx, path, value, "x", opt) },
+				"Keys":           func() (any, error) { return Keys(ctx, path, value, opt, WithSilent()) },
+				"Values":         func() (any, error) { return Values(ctx, path, value, opt, WithSilent()) },
+				"Exists":         func() (any, error) { return Exists(ctx, path, value, opt) },
+				"AtQuestion":     func() (any, error) { return AtQuestion(ctx, path, value, opt) },
+			} {
+				_, err := fn()
+				if tc.pred {
+					r.EqualError(err, fmt.Sprintf(sqlErr, name))
+					r.ErrorIs(err, ErrExecution)
+				} else {
+					r.NoError(err)
+				}
+			}
+
+			// Functions that expect predicate check expressions.
+			for name, fn := range map[string]func() (bool, error){
+				"Match": func() (bool, error) { return Match(ctx, path, value, opt, WithSilent()) },
+				"AtAt":  func() (bool, error) { return AtAt(ctx, path, value, opt) },
+			} {
+				ok, err := fn()
+				if tc.pred {
+					r.NoError(err)
+					a.True(ok)
+				} else {
+					r.EqualError(err, fmt.Sprintf(predErr, name))
+					r.ErrorIs(err, ErrExecution)
+					a.False(ok)
+				}
+			}
+
+			// Warnings.
+			var warnings []string
+			warn := WithPredicateWarning(func(err error) {
+				a.ErrorIs(err, ErrExecution)
+				warnings = append(warnings, err.Error())
+			})
+			res, err := Query(ctx, path, value, warn)
+			r.NoError(err)
+			a.NotEmpty(res)
+			_, err = Match(ctx, path, value, warn, WithSilent())
+			if tc.pred {
 				r.NoError(err)
+				a.Equal([]string{fmt.Sprintf(sqlErr, "Query")}, warnings)
 			} else {
-				r.EqualError(err, tc.err)
-				r.ErrorIs(err, tc.isErr)
+				r.EqualError(err, fmt.Sprintf(predErr, "Match"))
+				a.Empty(warnings)
 			}
+
+			// No check by default.
+			_, err = Query(ctx, path, value)
+			r.NoError(err)
 		})
 	}
 }
@@ -377,17 +2681,20 @@ func TestMatch(t *testing.T) {
 func TestExecAccessors(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
+	r := require.New(t)
 
 	// Test lax.
 	lax, _ := parser.Parse("$")
-	e := newExec(lax)
+	e, err := newExec(lax)
+	r.NoError(err)
 	a.False(e.strictAbsenceOfErrors())
 	a.True(e.autoWrap())
 	a.True(e.autoUnwrap())
 
 	// Test strict.
 	strict, _ := parser.Parse("strict $")
-	e = newExec(strict)
+	e, err = newExec(strict)
+	r.NoError(err)
 	a.True(e.strictAbsenceOfErrors())
 	a.False(e.autoWrap())
 	a.False(e.autoUnwrap())
@@ -446,6 +2753,7 @@ func newTestExecutor(path *ast.AST, vars Vars, throwErrors, useTZ bool) *Executo
 		innermostArraySize:     -1,
 		useTZ:                  useTZ,
 		ignoreStructuralErrors: path.IsLax(),
+		lax:                    path.IsLax(),
 		verbose:                throwErrors,
 		lastGeneratedObjectID:  1,
 	}
@@ -458,7 +2766,7 @@ func (tc execTestCase) run(t *testing.T) {
 	path, err := parser.Parse(tc.path)
 	r.NoError(err)
 	exec := newTestExecutor(path, tc.vars, !tc.silent, tc.useTZ)
-	list, err := exec.execute(context.Background(), tc.json)
+	list, err := exec.execute(context.Background(), tc.json, newList())
 	if tc.err != "" {
 		r.EqualError(err, tc.err)
 		r.ErrorIs(err, ErrExecution)
@@ -597,6 +2905,30 @@ func TestExecutePathKeys(t *testing.T) {
 			json: map[string]any{"x": map[string]any{"y": map[string]any{"z": "yep"}}},
 			exp:  []any{map[string]any{"z": "yep"}},
 		},
+		{
+			name: "quote_key",
+			path: `$."a\"b"`,
+			json: map[string]any{`a"b`: 1, "ab": 2},
+			exp:  []any{1},
+		},
+		{
+			name: "backslash_key",
+			path: `$."a\\b"`,
+			json: map[string]any{`a\b`: 1, "ab": 2},
+			exp:  []any{1},
+		},
+		{
+			name: "newline_key",
+			path: `$."line\nbreak"`,
+			json: map[string]any{"line\nbreak": 1, `line\nbreak`: 2},
+			exp:  []any{1},
+		},
+		{
+			name: "non_bmp_key",
+			path: `$."\ud83d\ude00"."😀"`,
+			json: map[string]any{"😀": map[string]any{"\U0001F600": 1}},
+			exp:  []any{1},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -768,7 +3100,7 @@ func TestExecuteNumberMethods(t *testing.T) {
 			name: "decimal_method_precision",
 			path: `$.x.decimal(4)`,
 			json: map[string]any{"x": "12.2"},
-			exp:  []any{float64(12)},
+			exp:  []any{json.Number("12")},
 		},
 		{
 			name: "decimal_method_precision_short",
@@ -781,13 +3113,13 @@ func TestExecuteNumberMethods(t *testing.T) {
 			name: "decimal_method_precision_ok",
 			path: `$.x.decimal(5,3)`,
 			json: map[string]any{"x": "12.233"},
-			exp:  []any{float64(12.233)},
+			exp:  []any{json.Number("12.233")},
 		},
 		{
 			name: "decimal_method_precision_scale",
 			path: `$.x.decimal(4, 2)`,
 			json: map[string]any{"x": "12.233"},
-			exp:  []any{float64(12.23)},
+			exp:  []any{json.Number("12.23")},
 		},
 		{
 			name: "decimal_method_precision_scale_short",
@@ -914,6 +3246,181 @@ func TestExecuteArraySubscripts(t *testing.T) {
 			json: map[string]any{"x": []any{"hi", "", true, "x", "y"}},
 			exp:  []any{"hi", "x", "y"},
 		},
+		{
+			name: "array_subscript_var",
+			path: `$.x[$i]`,
+			vars: Vars{"i": int64(1)},
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			exp:  []any{"b"},
+		},
+		{
+			name: "array_subscript_var_float",
+			path: `$.x[$i]`,
+			vars: Vars{"i": float64(2.9)},
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			exp:  []any{"c"},
+		},
+		{
+			name: "array_subscript_var_json_number",
+			path: `$.x[$i to $j]`,
+			vars: Vars{"i": json.Number("1.5"), "j": json.Number("3")},
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			exp:  []any{"b", "c", "d"},
+		},
+		{
+			name: "array_subscript_var_math",
+			path: `$.x[$i - 1 to $i + 1]`,
+			vars: Vars{"i": int64(2)},
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			exp:  []any{"b", "c", "d"},
+		},
+		{
+			name: "array_subscript_var_not_numeric",
+			path: `$.x[$i]`,
+			vars: Vars{"i": "1"},
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is not a single numeric value`,
+		},
+		{
+			name: "array_subscript_var_huge",
+			path: `$.x[$i]`,
+			vars: Vars{"i": json.Number("1e400")},
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_var_float_huge",
+			path: `$.x[$i]`,
+			vars: Vars{"i": float64(1e20)},
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_lax_last_add_overflow",
+			path: `$.x[last + 9223372036854775807]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_lax_last_mul_overflow",
+			path: `$.x[last * 4611686018427387904]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_lax_last_sub_overflow",
+			path: `$.x[last - 9223372036854775807 - 9223372036854775807 - 4]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_lax_size_mul_overflow",
+			path: `$.x[$.x.size() * 3689348814741910324 - 3]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_lax_range_overflow",
+			path: `$.x[0 to last * 4611686018427387904]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_strict_last_add_overflow",
+			path: `strict $.x[last + 9223372036854775807]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_strict_last_mul_overflow",
+			path: `strict $.x[last * 4611686018427387904]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_strict_last_sub_overflow",
+			path: `strict $.x[last - 9223372036854775807 - 9223372036854775807 - 4]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_strict_size_mul_overflow",
+			path: `strict $.x[$.x.size() * 3689348814741910324 - 3]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_strict_range_overflow",
+			path: `strict $.x[0 to last * 4611686018427387904]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of integer range`,
+		},
+		{
+			name: "array_subscript_size",
+			path: `$.x[$.x.size() - 2]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			exp:  []any{"d"},
+		},
+		{
+			name: "array_subscript_double",
+			path: `$.x[$.y.double()]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}, "y": "2.7"},
+			exp:  []any{"c"},
+		},
+		{
+			name: "array_subscript_decimal",
+			path: `$.x[$.y.decimal(2, 1)]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}, "y": float64(2.66)},
+			exp:  []any{"c"},
+		},
+		{
+			name: "array_subscript_filter",
+			path: `$.x[$.y[*] ? (@ > 2)]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}, "y": []any{int64(1), int64(3)}},
+			exp:  []any{"d"},
+		},
+		{
+			name: "array_subscript_filter_multiple",
+			path: `$.x[$.y[*] ? (@ > 0)]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}, "y": []any{int64(1), int64(3)}},
+			err:  `exec: jsonpath array subscript is not a single numeric value`,
+		},
+		{
+			name: "array_subscript_filter_none",
+			path: `$.x[$.y[*] ? (@ > 5)]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}, "y": []any{int64(1), int64(3)}},
+			err:  `exec: jsonpath array subscript is not a single numeric value`,
+		},
+		{
+			name: "array_subscript_negative_fraction",
+			path: `$.x[-1.5 to 1]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			exp:  []any{"a", "b"},
+		},
+		{
+			name: "array_subscript_negative_fraction_strict",
+			path: `strict $.x[-0.9 to 1]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			exp:  []any{"a", "b"},
+		},
+		{
+			name: "array_subscript_negative_strict",
+			path: `strict $.x[-1.5 to 1]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			err:  `exec: jsonpath array subscript is out of bounds`,
+		},
+		{
+			name: "array_subscript_last_fraction",
+			path: `$.x[last - 1.5]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			exp:  []any{"c"},
+		},
+		{
+			name: "array_subscript_last_fraction_strict",
+			path: `strict $.x[last - 1.5 to last - 0.5]`,
+			json: map[string]any{"x": []any{"a", "b", "c", "d", "e"}},
+			exp:  []any{"c", "d"},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -949,6 +3456,61 @@ func TestExecuteLikeRegex(t *testing.T) {
 			json: map[string]any{"x": "HIGH"},
 			exp:  []any{true},
 		},
+		{
+			name: "like_regex_backspace",
+			path: `$.x like_regex "^a\\b$"`,
+			json: map[string]any{"x": "a\b"},
+			exp:  []any{true},
+		},
+		{
+			name: "like_regex_word_boundary",
+			path: `$.x like_regex "\\yhi\\y"`,
+			json: map[string]any{"x": "say hi there"},
+			exp:  []any{true},
+		},
+		{
+			name: "like_regex_posix_class",
+			path: `$.x like_regex "^[[:alpha:]]+$"`,
+			json: map[string]any{"x": "abc1"},
+			exp:  []any{false},
+		},
+		{
+			name: "like_regex_collating_element",
+			path: `$.x like_regex "^a[[.-.]]b$"`,
+			json: map[string]any{"x": "a-b"},
+			exp:  []any{true},
+		},
+		{
+			name: "like_regex_expanded",
+			path: `$.x like_regex "^h i  # greeting" flag "x"`,
+			json: map[string]any{"x": "hi"},
+			exp:  []any{true},
+		},
+		{
+			name: "like_regex_back_reference",
+			path: `$.x like_regex "(a)\\1"`,
+			json: map[string]any{"x": "aa"},
+			err:  "exec: back reference `\\1` not supported by like_regex",
+		},
+		{
+			name: "like_regex_lookahead",
+			path: `$.x like_regex "a(?=b)"`,
+			json: map[string]any{"x": "ab"},
+			err:  "exec: lookahead constraint `(?=` not supported by like_regex",
+		},
+		{
+			name:   "like_regex_unsupported_not_string",
+			path:   `$.x like_regex "\\mhi"`,
+			json:   map[string]any{"x": int64(1)},
+			exp:    []any{nil},
+			result: statusOK,
+		},
+		{
+			name: "like_regex_word_start",
+			path: `$.x like_regex "\\mhi"`,
+			json: map[string]any{"x": "hi"},
+			err:  "exec: word boundary constraint `\\m` not supported by like_regex",
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -2431,6 +4993,48 @@ func TestExecuteBooleanMethod(t *testing.T) {
 			json: map[string]any{"x": "0n"},
 			err:  `exec: argument "0n" of jsonpath item method .boolean() is invalid for type boolean`,
 		},
+		{
+			name: "bool_string_tr_prefix",
+			path: "$.x.boolean()",
+			json: map[string]any{"x": "tr"},
+			exp:  []any{true},
+		},
+		{
+			name: "bool_string_fals_prefix",
+			path: "$.x.boolean()",
+			json: map[string]any{"x": "FALS"},
+			exp:  []any{false},
+		},
+		{
+			name: "bool_string_ye_prefix",
+			path: "$.x.boolean()",
+			json: map[string]any{"x": "Ye"},
+			exp:  []any{true},
+		},
+		{
+			name: "bool_string_of_prefix",
+			path: "$.x.boolean()",
+			json: map[string]any{"x": "of"},
+			exp:  []any{false},
+		},
+		{
+			name: "bool_string_leading_space",
+			path: "$.x.boolean()",
+			json: map[string]any{"x": " true"},
+			err:  `exec: argument " true" of jsonpath item method .boolean() is invalid for type boolean`,
+		},
+		{
+			name: "bool_string_trailing_space",
+			path: "$.x.boolean()",
+			json: map[string]any{"x": "true "},
+			err:  `exec: argument "true " of jsonpath item method .boolean() is invalid for type boolean`,
+		},
+		{
+			name: "bool_string_padded_on",
+			path: "$.x.boolean()",
+			json: map[string]any{"x": " on "},
+			err:  `exec: argument " on " of jsonpath item method .boolean() is invalid for type boolean`,
+		},
 		{
 			name: "bool_array",
 			path: "$.x.boolean()",
@@ -2456,3 +5060,78 @@ func TestExecuteBooleanMethod(t *testing.T) {
 		})
 	}
 }
+
+//nolint:paralleltest // AllocsPerRun counts allocations by all goroutines.
+func TestExistsAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items with the race detector enabled")
+	}
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	array := make([]any, 10_000)
+	for i := range array {
+		array[i] = map[string]any{"a": int64(i)}
+	}
+
+	for _, tc := range []struct {
+		name string
+		path string
+		exp  bool
+	}{
+		{"lax_filter", "$[*] ? (@.a % 2 == 0)", true},
+		{"lax_filter_none", "$[*] ? (@.a < 0)", false},
+		{"strict_filter", "strict $[*] ? (@.a % 2 == 0)", true},
+		{"strict_exists", "strict $ ? (exists ($[*] ? (@.a > 0)))", true},
+	} {
+		path, err := parser.Parse(tc.path)
+		r.NoError(err)
+		allocs := testing.AllocsPerRun(10, func() {
+			ok, err := Exists(ctx, path, array)
+			r.NoError(err)
+			a.Equal(tc.exp, ok)
+		})
+		// Evaluation of the elements allocates nothing.
+		a.Less(allocs, float64(20), tc.name)
+	}
+}
+
+func BenchmarkQuery(b *testing.B) {
+	const size = 100_000
+	array := make([]any, size)
+	for i := range array {
+		array[i] = map[string]any{"a": int64(i), "b": "x"}
+	}
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name string
+		path string
+	}{
+		{"wildcard_member", "$[*].a"},
+		{"filter", "$[*] ? (@.a % 2 == 0)"},
+		{"strict_filter", "strict $[*] ? (@.a % 2 == 0)"},
+	} {
+		path, err := parser.Parse(tc.path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				if _, err := Query(ctx, path, array); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(tc.name+"_exists", func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				if _, err := Exists(ctx, path, array); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
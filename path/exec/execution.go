@@ -3,6 +3,7 @@ package exec
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/theory/sqljson/path/ast"
 )
@@ -13,10 +14,15 @@ import (
 // statusFailed on error. When statusFailed is returned, an error will also be
 // returned, except when query.verbose is false and the error is ErrVerbose.
 func (exec *Executor) query(ctx context.Context, vals *valueList, node ast.Node, value any) (resultStatus, error) {
+	if exec.stats != nil {
+		start := time.Now()
+		defer func() { exec.stats.Duration += time.Since(start) }()
+	}
+
 	if exec.strictAbsenceOfErrors() && vals == nil {
 		// In strict mode we must get a complete list of values to check that
 		// there are no errors at all.
-		vals := newList()
+		vals := newCounter()
 		res, err := exec.executeItem(ctx, node, value, vals)
 		if res.failed() {
 			return res, err
@@ -25,10 +31,29 @@ func (exec *Executor) query(ctx context.Context, vals *valueList, node ast.Node,
 		if vals.isEmpty() {
 			return statusNotFound, nil
 		}
+		exec.countMatches(vals.size())
 		return statusOK, nil
 	}
 
-	return exec.executeItem(ctx, node, value, vals)
+	if vals == nil {
+		res, err := exec.executeItem(ctx, node, value, nil)
+		if res == statusOK {
+			exec.countMatches(1)
+		}
+		return res, err
+	}
+
+	size := vals.size()
+	res, err := exec.executeItem(ctx, node, value, vals)
+	exec.countMatches(vals.size() - size)
+	return res, err
+}
+
+// countMatches adds n to the items matched recorded by exec.stats, if any.
+func (exec *Executor) countMatches(n int) {
+	if exec.stats != nil {
+		exec.stats.ItemsMatched += n
+	}
 }
 
 // executeItem executes jsonpath with automatic unwrapping of current item in
@@ -53,7 +78,8 @@ func (exec *Executor) executeItemOptUnwrapResult(
 	found *valueList,
 ) (resultStatus, error) {
 	if unwrap && exec.autoUnwrap() {
-		seq := newList()
+		seq := getList()
+		defer putList(seq)
 		res, err := exec.executeItem(ctx, node, value, seq)
 		if res.failed() {
 			return res, err
@@ -64,7 +90,9 @@ func (exec *Executor) executeItemOptUnwrapResult(
 			case []any:
 				_, _ = exec.executeItemUnwrapTargetArray(ctx, nil, item, found)
 			default:
-				found.append(item)
+				if err := found.append(item); err != nil {
+					return statusFailed, err
+				}
 			}
 		}
 		return statusOK, nil
@@ -87,11 +115,38 @@ func (exec *Executor) executeItemOptUnwrapResultSilent(
 	return exec.executeItemOptUnwrapResult(ctx, node, value, unwrap, found)
 }
 
+// checkContext returns an error wrapping ctx.Err() if ctx has been canceled
+// or its deadline exceeded. The error does not wrap ErrExecution, but can be
+// compared to [context.Canceled] or [context.DeadlineExceeded].
+func checkContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+	return nil
+}
+
+// descend increments the recursion depth. Returns an error if the depth
+// exceeds exec.maxDepth. Callers must call ascend when they return, unless
+// descend returns an error.
+func (exec *Executor) descend() error {
+	if exec.maxDepth > 0 && exec.depth >= exec.maxDepth {
+		return fmt.Errorf("%w: maximum recursion depth exceeded", ErrExecution)
+	}
+	exec.depth++
+	if exec.stats != nil {
+		exec.stats.MaxDepth = max(exec.stats.MaxDepth, exec.depth)
+	}
+	return nil
+}
+
+// ascend decrements the recursion depth incremented by descend.
+func (exec *Executor) ascend() { exec.depth-- }
+
 // executeItemOptUnwrapTarget is the main executor function: walks on jsonpath
 // structure, finds relevant parts of value and evaluates expressions over
 // them. When unwrap is true, the current SQL/JSON item is unwrapped if it is
-// an array. Before execution it checks ctx and base with statusNotFound if it
-// is done.
+// an array. Before execution it checks ctx and returns statusFailed and an
+// error if it is done.
 func (exec *Executor) executeItemOptUnwrapTarget(
 	ctx context.Context,
 	node ast.Node,
@@ -100,10 +155,49 @@ func (exec *Executor) executeItemOptUnwrapTarget(
 	unwrap bool,
 ) (resultStatus, error) {
 	// Check for interrupts.
-	select {
-	case <-ctx.Done():
-		return statusFailed, fmt.Errorf("%w: %w", ErrExecution, ctx.Err())
-	default:
+	if err := checkContext(ctx); err != nil {
+		return statusFailed, err
+	}
+
+	if err := exec.descend(); err != nil {
+		return statusFailed, err
+	}
+	defer exec.ascend()
+
+	if exec.stats != nil {
+		exec.stats.NodesVisited++
+	}
+
+	step := -1
+	if exec.trace != nil {
+		step = exec.traceStart(node, value)
+	}
+
+	res, err := exec.executeNode(ctx, node, value, found, unwrap)
+	if err != nil {
+		err = exec.locate(err)
+	}
+
+	if exec.trace != nil {
+		exec.traceEnd(step, res)
+	}
+	return res, err
+}
+
+// executeNode dispatches node to the function that executes its type. When
+// unwrap is true and node is an item method, it applies node to each item of
+// an array value; see unwrapsTarget.
+func (exec *Executor) executeNode(
+	ctx context.Context,
+	node ast.Node,
+	value any,
+	found *valueList,
+	unwrap bool,
+) (resultStatus, error) {
+	if unwrap && unwrapsTarget(node) {
+		if array, ok := value.([]any); ok {
+			return exec.executeItemUnwrapTargetArray(ctx, node, array, found)
+		}
 	}
 
 	switch node := node.(type) {
@@ -120,13 +214,13 @@ func (exec *Executor) executeItemOptUnwrapTarget(
 	case *ast.KeyNode:
 		return exec.execKeyNode(ctx, node, value, found, unwrap)
 	case *ast.BinaryNode:
-		return exec.execBinaryNode(ctx, node, value, found, unwrap)
+		return exec.execBinaryNode(ctx, node, value, found)
 	case *ast.UnaryNode:
 		return exec.execUnaryNode(ctx, node, value, found, unwrap)
 	case *ast.RegexNode:
 		return exec.execRegexNode(ctx, node, value, found)
 	case *ast.MethodNode:
-		return exec.execMethodNode(ctx, node, value, found, unwrap)
+		return exec.execMethodNode(ctx, node, value, found)
 	case *ast.AnyNode:
 		return exec.execAnyNode(ctx, node, value, found)
 	case *ast.ArrayIndexNode:
@@ -155,12 +249,25 @@ func (exec *Executor) executeNextItem(
 		hasNext = next != nil
 	}
 
+	value, err := exec.normalize(value)
+	if err != nil {
+		// Report the location of value rather than of the item from which
+		// cur selected it.
+		return statusFailed, exec.locate(err)
+	}
+
+	if exec.provenance {
+		defer exec.tempSetSynthetic(cur)()
+	}
+
 	if hasNext {
 		return exec.executeItem(ctx, next, value, found)
 	}
 
 	if found != nil {
-		found.append(value)
+		if err := found.append(value); err != nil {
+			return statusFailed, err
+		}
 	}
 
 	return statusOK, nil
@@ -369,7 +369,7 @@ func TestExecuteItemOptUnwrapTarget(t *testing.T) {
 			value:  true,
 			exp:    statusFailed,
 			err:    "exec: context canceled",
-			isErr:  ErrExecution,
+			isErr:  context.Canceled,
 		},
 		{
 			name:  "const",
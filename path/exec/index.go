@@ -0,0 +1,351 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/types"
+	"golang.org/x/exp/maps" // Switch to maps when go 1.22 dropped
+)
+
+// Indexed is a JSON document indexed by [IndexDocument] for repeated
+// queries. Pass it to [Query], [Exists], or any other query function in
+// place of the document to accelerate:
+//
+//   - Chains of member accessors, such as $.a.b.c, which select members of
+//     nested objects directly rather than executing each accessor in turn
+//   - The .** accessor followed by a member accessor, such as $.**.name,
+//     which jumps directly to the objects that have the member rather than
+//     visiting every item below the current item
+//
+// Execution otherwise proceeds as usual, and selects the same items as it
+// would from the document itself, in the same order when [WithOrderedKeys]
+// is set. Execution ignores the index with [WithStructTags],
+// [WithCaseInsensitiveKeys], [WithStats], and [Explain], and when the
+// document contains values other than JSON values, such as structs and
+// Go maps of other types. [Replace] and [Delete] accept an Indexed, too, but
+// ignore the index.
+//
+// An Indexed may be used concurrently by multiple goroutines, but its
+// document must not be modified once indexed.
+type Indexed struct {
+	doc any
+	// objects and arrays of doc in pre-order, visiting object members in
+	// sorted key order; nil if doc cannot be indexed
+	items []indexItem
+	// indexes in items of the non-empty objects and arrays of doc, keyed by
+	// their addresses
+	ords map[uintptr]int
+	// member values .** followed by a member accessor selects, keyed by
+	// member name, in the order it selects them
+	members map[string][]indexHit
+}
+
+// indexItem is an object or array in an indexed document.
+type indexItem struct {
+	// index in Indexed.items of the parent, or -1 for the root
+	parent int
+	// location in the parent
+	elem locElem
+	// nesting level, 0 for the root
+	level int
+	// deepest nesting level of the item and its descendants, including
+	// scalars
+	deepest int
+	// index in Indexed.items after the last descendant
+	end int
+	// number of members or elements
+	size int
+	// true for arrays
+	array bool
+}
+
+// indexHit is a member value that .** followed by a member accessor
+// selects when it visits the item at index from in Indexed.items: either
+// the object at obj itself, or its parent array, which lax mode unwraps.
+type indexHit struct {
+	from  int
+	obj   int
+	value any
+}
+
+// IndexDocument indexes doc for repeated queries. See [Indexed]. Indexing
+// visits every item in doc, so it pays off only for documents queried more
+// than a few times. Documents that contain values other than JSON values,
+// or that contain an object or array more than once, are not indexed, but
+// may still be passed to query functions as an Indexed.
+func IndexDocument(doc any) *Indexed {
+	idx := &Indexed{
+		doc:     doc,
+		ords:    map[uintptr]int{},
+		members: map[string][]indexHit{},
+	}
+	if _, ok := idx.add(doc, -1, locElem{kind: locRoot}, 0); !ok {
+		return &Indexed{doc: doc}
+	}
+	for _, hits := range idx.members {
+		slices.SortFunc(hits, func(a, b indexHit) int {
+			if a.from != b.from {
+				return a.from - b.from
+			}
+			return a.obj - b.obj
+		})
+	}
+	return idx
+}
+
+// add adds value, located at elem in the item at index parent in
+// idx.items, and its descendants to idx. Returns the deepest nesting level
+// of value and its descendants, and false if value or a descendant is not
+// a JSON value or is an object or array already added.
+func (idx *Indexed) add(value any, parent int, elem locElem, level int) (int, bool) {
+	switch value := value.(type) {
+	case nil, string, int64, float64, bool, json.Number, types.DateTime:
+		return level, true
+	case map[string]any:
+		ord, ok := idx.addItem(value, len(value), parent, elem, level)
+		if !ok {
+			return 0, false
+		}
+		keys := maps.Keys(value)
+		slices.Sort(keys)
+		deepest := level
+		for _, key := range keys {
+			val := value[key]
+			idx.members[key] = append(idx.members[key], indexHit{ord, ord, val})
+			if parent >= 0 && idx.items[parent].array {
+				idx.members[key] = append(idx.members[key], indexHit{parent, ord, val})
+			}
+			depth, ok := idx.add(val, ord, locElem{kind: locKey, name: key}, level+1)
+			if !ok {
+				return 0, false
+			}
+			deepest = max(deepest, depth)
+		}
+		idx.items[ord].deepest = deepest
+		idx.items[ord].end = len(idx.items)
+		return deepest, true
+	case []any:
+		ord, ok := idx.addItem(value, len(value), parent, elem, level)
+		if !ok {
+			return 0, false
+		}
+		idx.items[ord].array = true
+		deepest := level
+		for i, val := range value {
+			depth, ok := idx.add(val, ord, locElem{kind: locIndex, index: i}, level+1)
+			if !ok {
+				return 0, false
+			}
+			deepest = max(deepest, depth)
+		}
+		idx.items[ord].deepest = deepest
+		idx.items[ord].end = len(idx.items)
+		return deepest, true
+	default:
+		return 0, false
+	}
+}
+
+// addItem appends an item for value, an object or array of size items, to
+// idx.items and returns its index. Returns false if value has already been
+// added. Empty objects and arrays aren't recorded in idx.ords, since they
+// may share an address.
+func (idx *Indexed) addItem(value any, size, parent int, elem locElem, level int) (int, bool) {
+	ord := len(idx.items)
+	if size > 0 {
+		addr := addrOf(value)
+		if _, ok := idx.ords[addr]; ok {
+			return 0, false
+		}
+		idx.ords[addr] = ord
+	}
+	idx.items = append(idx.items, indexItem{parent: parent, elem: elem, level: level, size: size})
+	return ord, true
+}
+
+// ord returns the index in idx.items of value, or false if value is not a
+// non-empty object or array in idx. Requires value to have the size of the
+// item at its address, since a subslice of an indexed array may share its
+// address.
+func (idx *Indexed) ord(value any) (int, bool) {
+	ord, ok := idx.ords[addrOf(value)]
+	if !ok {
+		return 0, false
+	}
+	switch value := value.(type) {
+	case map[string]any:
+		ok = !idx.items[ord].array && len(value) == idx.items[ord].size
+	case []any:
+		ok = idx.items[ord].array && len(value) == idx.items[ord].size
+	default:
+		ok = false
+	}
+	return ord, ok
+}
+
+// useIndex returns the document of value if it's an *Indexed, and
+// otherwise value. Sets exec.index to value if it's an *Indexed exec can
+// use, and otherwise to nil.
+func (exec *Executor) useIndex(value any) any {
+	exec.index = nil
+	idx, ok := value.(*Indexed)
+	if !ok {
+		return value
+	}
+	if idx.items != nil && exec.structTag == "" && !exec.caseInsensitiveKeys &&
+		exec.stats == nil && exec.trace == nil {
+		exec.index = idx
+	}
+	return idx.doc
+}
+
+// indexedKeys returns the last of node and the member accessors that
+// directly follow it that select members of nested objects starting with
+// obj, the member it selects, and the number of accessors. Returns zero
+// for obj not in exec.index, or if executing that many accessors one at a
+// time might exceed exec.maxDepth.
+func (exec *Executor) indexedKeys(node *ast.KeyNode, obj map[string]any) (*ast.KeyNode, any, int) {
+	if _, ok := exec.index.ord(obj); !ok {
+		return nil, nil, 0
+	}
+
+	var last *ast.KeyNode
+	var cur any = obj
+	count := 0
+	for key, ok := node, true; ok; key, ok = key.Next().(*ast.KeyNode) {
+		obj, isObj := cur.(map[string]any)
+		if !isObj {
+			break
+		}
+		val, exists := obj[key.Text()]
+		if !exists {
+			break
+		}
+		last, cur = key, val
+		count++
+	}
+
+	if exec.maxDepth > 0 && exec.depth+count >= exec.maxDepth {
+		return nil, nil, 0
+	}
+	return last, cur, count
+}
+
+// executeIndexedKeys executes the count member accessors starting with
+// node, the last of which, last, selects value, as found by indexedKeys,
+// and then executes the node that follows last, as though it had executed
+// each accessor in turn.
+func (exec *Executor) executeIndexedKeys(
+	ctx context.Context,
+	node, last *ast.KeyNode,
+	value any,
+	count int,
+	found *valueList,
+) (resultStatus, error) {
+	defer exec.leave(len(exec.location))
+	var key ast.Node = node
+	for range count {
+		exec.enterKey(key.(*ast.KeyNode).Text()) //nolint:forcetypeassert // indexedKeys found count keys
+		key = key.Next()
+	}
+
+	// Execute the next node at the depth it would have reached.
+	depth := exec.depth
+	exec.depth += count - 1
+	defer func() { exec.depth = depth }()
+	return exec.executeNextItem(ctx, last, nil, value, found)
+}
+
+// indexedAny returns the index in exec.index.items of value, to which
+// .** is applied, or false if value is not in exec.index or visiting each
+// of its descendants might exceed exec.maxDepth.
+func (exec *Executor) indexedAny(value any) (int, bool) {
+	ord, ok := exec.index.ord(value)
+	if !ok {
+		return 0, false
+	}
+	item := exec.index.items[ord]
+	const unwrapDepth = 3
+	if exec.maxDepth > 0 && exec.depth+item.deepest-item.level+unwrapDepth > exec.maxDepth {
+		return 0, false
+	}
+	return ord, true
+}
+
+// executeIndexedAny executes node, an unbounded .** accessor followed by
+// key, against the item at index ord in exec.index.items, as found by
+// indexedAny. Rather than visit every item, it executes the node that
+// follows key against each member value key selects, as executeAnyItem
+// would, and with the same location and depth.
+func (exec *Executor) executeIndexedAny(
+	ctx context.Context,
+	key *ast.KeyNode,
+	ord int,
+	found *valueList,
+) (resultStatus, error) {
+	defer exec.tempSetIgnoreStructuralErrors(true)()
+	loc := len(exec.location)
+	defer exec.leave(loc)
+	depth := exec.depth
+	defer func() { exec.depth = depth }()
+
+	item := exec.index.items[ord]
+	hits := exec.index.members[key.Text()]
+	start := sort.Search(len(hits), func(i int) bool { return hits[i].from >= ord })
+	unwrap := exec.autoUnwrap()
+	res := statusNotFound
+	skip := false
+	for _, hit := range hits[start:] {
+		if hit.from >= item.end {
+			break
+		}
+		unwrapped := hit.from != hit.obj
+		if (unwrapped && !unwrap) || (skip && hit.from == ord) {
+			continue
+		}
+		if err := checkContext(ctx); err != nil {
+			return statusFailed, err
+		}
+
+		exec.leave(loc)
+		exec.enterIndexed(ord, hit.obj)
+		exec.enterKey(key.Text())
+		exec.depth = depth + exec.index.items[hit.from].level - item.level + 1
+		if unwrapped {
+			exec.depth += 2
+		}
+		status, err := exec.executeNextItem(ctx, key, nil, hit.value, found)
+		exec.depth = depth
+
+		switch {
+		case status.failed():
+			// Like execAnyNode, continue past silent failures of the item
+			// to which .** is applied, but not of its descendants.
+			if err != nil || hit.from != ord {
+				return status, err
+			}
+			skip = true
+		case status != statusOK:
+		case found == nil:
+			return statusOK, nil
+		case hit.from != ord:
+			res = statusOK
+		}
+	}
+
+	return res, nil
+}
+
+// enterIndexed enters the location of the item at index ord in
+// exec.index.items relative to its ancestor at index ancestor.
+func (exec *Executor) enterIndexed(ancestor, ord int) {
+	start := len(exec.location)
+	for i := ord; i != ancestor; i = exec.index.items[i].parent {
+		exec.location = append(exec.location, exec.index.items[i].elem)
+	}
+	slices.Reverse(exec.location[start:])
+}
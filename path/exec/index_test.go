@@ -0,0 +1,368 @@
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestIndexDocument(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	doc := js(`{"a": {"b": [{"c": 1}, {"c": 2, "d": []}]}, "c": {}}`)
+	idx := IndexDocument(doc)
+	a.Equal(doc, idx.doc)
+	a.Len(idx.items, 7)
+	a.Len(idx.ords, 5)
+	a.Equal([]indexItem{
+		{parent: -1, elem: locElem{kind: locRoot}, level: 0, deepest: 4, end: 7, size: 2},
+		{parent: 0, elem: locElem{kind: locKey, name: "a"}, level: 1, deepest: 4, end: 6, size: 1},
+		{parent: 1, elem: locElem{kind: locKey, name: "b"}, level: 2, deepest: 4, end: 6, size: 2, array: true},
+		{parent: 2, elem: locElem{kind: locIndex, index: 0}, level: 3, deepest: 4, end: 4, size: 1},
+		{parent: 2, elem: locElem{kind: locIndex, index: 1}, level: 3, deepest: 4, end: 6, size: 2},
+		{parent: 4, elem: locElem{kind: locKey, name: "d"}, level: 4, deepest: 4, end: 6, array: true},
+		{parent: 0, elem: locElem{kind: locKey, name: "c"}, level: 1, deepest: 1, end: 7},
+	}, idx.items)
+
+	// Members of objects in arrays appear for the array and the object.
+	a.Equal([]indexHit{
+		{from: 0, obj: 0, value: map[string]any{}},
+		{from: 2, obj: 3, value: float64(1)},
+		{from: 2, obj: 4, value: float64(2)},
+		{from: 3, obj: 3, value: float64(1)},
+		{from: 4, obj: 4, value: float64(2)},
+	}, idx.members["c"])
+
+	// Documents that cannot be indexed.
+	shared := map[string]any{"x": int64(1)}
+	cycle := map[string]any{}
+	cycle["self"] = cycle
+	for _, tc := range []struct {
+		name string
+		doc  any
+	}{
+		{"struct", map[string]any{"a": struct{}{}}},
+		{"go_map", map[string]any{"a": map[string]int{"b": 1}}},
+		{"int", []any{1}},
+		{"shared", []any{shared, shared}},
+		{"cycle", cycle},
+	} {
+		idx := IndexDocument(tc.doc)
+		a.Nil(idx.items, tc.name)
+		a.Equal(tc.doc, idx.doc, tc.name)
+	}
+
+	// Empty containers may share an address.
+	idx = IndexDocument([]any{[]any{}, []any{}, map[string]any{}})
+	a.Len(idx.items, 4)
+}
+
+func TestIndexedQuery(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	doc := js(`{
+		"a": {"b": {"c": [1, {"x": 2}, [{"x": 3}]], "x": "four"}},
+		"x": 5,
+		"y": [{"x": "6"}, {"z": {"x": 7}}]
+	}`)
+
+	for _, tc := range []struct {
+		name string
+		path string
+		opt  []Option
+		exp  []any
+		err  string
+	}{
+		{
+			name: "chain",
+			path: `$.a.b.c`,
+			exp:  []any{js(`[1, {"x": 2}, [{"x": 3}]]`)},
+		},
+		{
+			name: "chain_unwrap",
+			path: `$.a.b.c.x`,
+			exp:  []any{float64(2)},
+		},
+		{
+			name: "chain_method",
+			path: `$.a.b.c.size()`,
+			exp:  []any{int64(3)},
+		},
+		{
+			name: "chain_missing",
+			path: `$.a.b.nope.x`,
+			exp:  []any{},
+		},
+		{
+			name: "strict_chain_missing",
+			path: `strict $.a.b.nope.x`,
+			err:  `exec: JSON object does not contain key "nope"`,
+		},
+		{
+			name: "strict_chain_not_object",
+			path: `strict $.a.b.c.x`,
+			err:  `exec: jsonpath member accessor can only be applied to an object`,
+		},
+		{
+			name: "missing_as_null",
+			path: `strict $.a.b.nope.x`,
+			opt:  []Option{WithMissingAsNull()},
+			exp:  []any{nil},
+		},
+		{
+			name: "chain_in_filter",
+			path: `$.y[*] ? (@.z.x == 7)`,
+			exp:  []any{js(`{"z": {"x": 7}}`)},
+		},
+		{
+			name: "any",
+			path: `$.**.x`,
+			exp: []any{
+				float64(5), "four", float64(2), float64(2), float64(3), float64(3),
+				"6", "6", float64(7),
+			},
+		},
+		{
+			name: "strict_any",
+			path: `strict $.**.x`,
+			exp:  []any{float64(5), "four", float64(2), float64(3), "6", float64(7)},
+		},
+		{
+			name: "any_from_key",
+			path: `$.a.**.x`,
+			exp:  []any{"four", float64(2), float64(2), float64(3), float64(3)},
+		},
+		{
+			name: "any_from_array",
+			path: `$.y.**.x`,
+			exp:  []any{"6", "6", float64(7)},
+		},
+		{
+			name: "any_from_array_element",
+			path: `$.y[0].**.x`,
+			exp:  []any{"6"},
+		},
+		{
+			name: "any_next",
+			path: `$.**.x ? (@ > 4)`,
+			exp:  []any{float64(5), float64(7)},
+		},
+		{
+			name: "any_missing",
+			path: `$.**.nope`,
+			exp:  []any{},
+		},
+		{
+			name: "any_bounded",
+			path: `$.**{2}.x`,
+			exp:  []any{"four", "6"},
+		},
+		{
+			name: "any_error",
+			path: `$.**.x + 1`,
+			err:  "exec: left operand of jsonpath operator + is not a single numeric value",
+		},
+		{
+			name: "any_silent",
+			path: `$.**.x.double()`,
+			opt:  []Option{WithSilent()},
+			exp:  []any{float64(5)},
+		},
+		{
+			name: "any_max_results",
+			path: `$.**.x`,
+			opt:  []Option{WithMaxResults(2)},
+			err:  "exec: result limit exceeded",
+		},
+		{
+			name: "any_max_depth",
+			path: `$.**.x`,
+			opt:  []Option{WithMaxDepth(5)},
+			err:  "exec: maximum recursion depth exceeded",
+		},
+		{
+			name: "any_variable",
+			path: `$v.**.x`,
+			opt:  []Option{WithVars(Vars{"v": doc.(map[string]any)["y"]})},
+			exp:  []any{"6", "6", float64(7)},
+		},
+		{
+			name: "exists",
+			path: `exists($.**.z.x)`,
+			exp:  []any{true},
+		},
+		{
+			name: "case_insensitive",
+			path: `$.A.B.X`,
+			opt:  []Option{WithCaseInsensitiveKeys()},
+			exp:  []any{"four"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			opt := append([]Option{WithOrderedKeys()}, tc.opt...)
+
+			for _, value := range []any{doc, IndexDocument(doc)} {
+				res, err := Query(ctx, path, value, opt...)
+				if tc.err == "" {
+					r.NoError(err)
+					a.Equal(tc.exp, res)
+				} else {
+					r.EqualError(err, tc.err)
+					a.Nil(res)
+				}
+			}
+
+			// Locations must match, too.
+			exp, expErr := QueryLocations(ctx, path, doc, opt...)
+			res, err := QueryLocations(ctx, path, IndexDocument(doc), opt...)
+			a.Equal(expErr, err)
+			a.Equal(exp, res)
+		})
+	}
+}
+
+func TestIndexedEmpty(t *testing.T) {
+	t.Parallel()
+	registerTestMethods()
+	ctx := context.Background()
+
+	// Empty objects and arrays may share an address, so the index does not
+	// record them, and execution visits them as usual.
+	doc := js(`{
+		"a": {},
+		"b": [],
+		"c": [{}, [], {"x": {}}, {"x": []}],
+		"d": {"e": {}, "f": [[], {}], "x": [{}, {"x": 1}]},
+		"x": {"y": {}}
+	}`)
+
+	// first returns a subslice of an array, which shares its address.
+	first := func(_ context.Context, value any) (any, error) {
+		if array, ok := value.([]any); ok && len(array) > 0 {
+			return array[:1], nil
+		}
+		return value, nil
+	}
+
+	for _, tc := range []struct {
+		name string
+		path string
+		opt  []Option
+	}{
+		{"chain_empty_object", `$.a.x`, nil},
+		{"chain_empty_array", `$.b.x`, nil},
+		{"chain_into_empty", `$.d.e.x`, nil},
+		{"strict_chain_empty", `strict $.a.x`, nil},
+		{"any_empty_object", `$.a.**.x`, nil},
+		{"any_empty_array", `$.b.**.x`, nil},
+		{"any", `$.**.x`, nil},
+		{"any_all", `$.**`, nil},
+		{"any_unwrap", `$.c.**.x`, nil},
+		{"any_from_empty_elements", `$.c[*].**.x`, nil},
+		{"any_nested", `$.d.**.x.**.x`, nil},
+		{"any_levels", `$.**{1 to 2}.x`, nil},
+		{"filter_empty", `$.** ? (@.x == @.x)`, nil},
+		{"filter_size", `$.** ? (@.type() == "array" && @.size() == 0)`, nil},
+		{"keyvalue", `$.d.keyvalue().value.**.x`, nil},
+		{"variable_empty", `$v.**.x`, []Option{WithVars(Vars{"v": map[string]any{}})}},
+		{
+			"subslice",
+			`strict $.d.x.test_local().**.x`,
+			[]Option{WithMethods(map[string]MethodFunc{"test_local": first})},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			opt := append([]Option{WithOrderedKeys()}, tc.opt...)
+
+			exp, expErr := Query(ctx, path, doc, opt...)
+			res, err := Query(ctx, path, IndexDocument(doc), opt...)
+			a.Equal(expErr, err)
+			a.Equal(exp, res)
+
+			expLocs, expErr := QueryLocations(ctx, path, doc, opt...)
+			locs, err := QueryLocations(ctx, path, IndexDocument(doc), opt...)
+			a.Equal(expErr, err)
+			a.Equal(expLocs, locs)
+		})
+	}
+}
+
+func TestIndexedSilentFailures(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	// Like execAnyNode, continue past a silent failure of the item .** is
+	// applied to, but stop at a failure of a descendant.
+	path, err := parser.Parse(`$.**.x.double()`)
+	r.NoError(err)
+	for _, tc := range []struct {
+		doc string
+		exp []any
+	}{
+		{`{"x": "nope", "y": {"x": "1"}}`, []any{float64(1)}},
+		{`[{"x": "nope"}, {"y": {"x": "1"}}]`, []any{}},
+		{`{"a": {"x": "nope"}, "b": {"x": "1"}}`, []any{}},
+	} {
+		doc := js(tc.doc)
+		for _, value := range []any{doc, IndexDocument(doc)} {
+			res, err := Query(ctx, path, value, WithOrderedKeys(), WithSilent())
+			r.NoError(err)
+			a.Equal(tc.exp, res, tc.doc)
+		}
+	}
+}
+
+func TestIndexedOther(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	doc := js(`{"a": {"b": [{"c": 1}, {"c": 2}]}}`)
+	idx := IndexDocument(doc)
+	path, err := parser.Parse(`$.**.c`)
+	r.NoError(err)
+
+	ok, err := Exists(ctx, path, idx)
+	r.NoError(err)
+	a.True(ok)
+
+	// Stats and traces ignore the index.
+	var stats Stats
+	res, err := Query(ctx, path, idx, WithStats(&stats))
+	r.NoError(err)
+	a.ElementsMatch([]any{float64(1), float64(2), float64(1), float64(2)}, res)
+	a.Positive(stats.NodesVisited)
+
+	// Unindexable documents still work.
+	unindexed := IndexDocument(map[string]any{"c": int8(3)})
+	res, err = Query(ctx, path, unindexed)
+	r.NoError(err)
+	a.Equal([]any{int8(3)}, res)
+
+	// Mutations ignore the index and leave the document unchanged.
+	changed, err := Replace(ctx, path, idx, "x")
+	r.NoError(err)
+	a.Equal(js(`{"a": {"b": [{"c": "x"}, {"c": "x"}]}}`), changed)
+	a.Equal(js(`{"a": {"b": [{"c": 1}, {"c": 2}]}}`), idx.doc)
+
+	deleted, err := Delete(ctx, path, idx)
+	r.NoError(err)
+	a.Equal(js(`{"a": {"b": [{}, {}]}}`), deleted)
+}
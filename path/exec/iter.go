@@ -0,0 +1,188 @@
+//go:build go1.23
+
+package exec
+
+import (
+	"context"
+	"iter"
+	"slices"
+
+	"github.com/theory/sqljson/path/ast"
+	"golang.org/x/exp/maps" // Switch to maps when go 1.22 dropped
+)
+
+// Iter returns an iterator over the JSON items returned by the JSON path for
+// the specified JSON value. Rather than collecting the items like [Query], it
+// yields each item with a nil error as soon as execution selects it, and
+// breaking out of a range loop over the iterator stops execution. If
+// execution fails, it yields nil and the error and stops. Because it yields
+// items before execution completes, it may yield items before an error for
+// which [Query] would return no items, such as a missing key in strict mode.
+// With [WithParallel], it yields the items selected by a parallel evaluation
+// once all of its goroutines complete. The options otherwise act the same as
+// for [Query].
+func Iter(ctx context.Context, path *ast.AST, value any, opt ...Option) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		exec, err := newExec(path, opt...)
+		if err == nil {
+			err = exec.checkPredicate("Iter", false)
+		}
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		var resErr error
+		err = exec.iterate(ctx, value, func(val any) bool {
+			if val, resErr = exec.result(val); resErr != nil {
+				return false
+			}
+			return yield(val, nil)
+		})
+		if err == nil {
+			err = resErr
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// IterKeyValues is like [Iter], but yields the key and value of each member
+// of the objects selected by the JSON path for the specified JSON value,
+// each object's members in sorted key order, as [Keys] and [Values] return
+// them. Like .keyvalue(), it unwraps arrays in lax mode and fails for any
+// other non-object value, unless [WithSilent] is specified, in which case
+// iteration skips the non-object, where [Keys] and [Values] stop. Returns the iterator and a function
+// that returns the error that stopped iteration, if any. Call it after the
+// range loop over the iterator completes.
+func IterKeyValues(ctx context.Context, path *ast.AST, value any, opt ...Option) (iter.Seq2[string, any], func() error) {
+	var err error
+	seq := func(yield func(string, any) bool) {
+		var exec *Executor
+		if exec, err = newExec(path, opt...); err != nil {
+			return
+		}
+		if err = exec.checkPredicate("IterKeyValues", false); err != nil {
+			return
+		}
+
+		var kvErr error
+		err = exec.iterate(ctx, value, func(val any) bool {
+			var more bool
+			more, kvErr = exec.yieldMembers(val, exec.autoUnwrap(), yield)
+			return more
+		})
+		if err == nil {
+			err = kvErr
+		}
+	}
+	return seq, func() error { return err }
+}
+
+// IterIndexed is like [Iter], but yields the index and value of each
+// element of the arrays selected by the JSON path for the specified JSON
+// value, as the wildcard array accessor [*] would select them. In lax mode,
+// it yields any other value with index 0, as though wrapped in an array; in
+// strict mode, it fails for any other value, unless [WithSilent] is
+// specified, in which case iteration skips the non-array. Returns the
+// iterator and a function that returns the error that stopped iteration, if
+// any. Call it after the range loop over the iterator completes.
+func IterIndexed(ctx context.Context, path *ast.AST, value any, opt ...Option) (iter.Seq2[int, any], func() error) {
+	var err error
+	seq := func(yield func(int, any) bool) {
+		var exec *Executor
+		if exec, err = newExec(path, opt...); err != nil {
+			return
+		}
+		if err = exec.checkPredicate("IterIndexed", false); err != nil {
+			return
+		}
+
+		var idxErr error
+		err = exec.iterate(ctx, value, func(val any) bool {
+			var more bool
+			more, idxErr = exec.yieldElements(val, yield)
+			return more
+		})
+		if err == nil {
+			err = idxErr
+		}
+	}
+	return seq, func() error { return err }
+}
+
+// iterate executes exec.path against value, passing each selected item to
+// yield until it returns false. Returns nil if yield stops execution.
+func (exec *Executor) iterate(ctx context.Context, value any, yield func(any) bool) error {
+	vals := exec.newResultList()
+	vals.yield = yield
+	_, err := exec.execute(ctx, value, vals)
+	if vals.stopped {
+		return nil
+	}
+	return err
+}
+
+// yieldMembers passes the key and value of each member of val to yield, in
+// sorted key order. If val is an array and unwrap is true, it passes the
+// members of each of its elements. Returns false if yield returns false or
+// if val is not an object and exec is not silent, along with an error for
+// the latter. Returns true for a non-object when exec is silent, so that
+// iteration skips it.
+func (exec *Executor) yieldMembers(val any, unwrap bool, yield func(string, any) bool) (bool, error) {
+	switch val := val.(type) {
+	case map[string]any:
+		keys := maps.Keys(val)
+		slices.Sort(keys)
+		for _, k := range keys {
+			v, err := exec.result(val[k])
+			if err != nil {
+				return false, err
+			}
+			if !yield(k, v) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case []any:
+		if unwrap {
+			for _, elem := range val {
+				if more, err := exec.yieldMembers(elem, false, yield); !more {
+					return false, err
+				}
+			}
+			return true, nil
+		}
+	}
+
+	_, err := exec.returnVerboseError(keyValueTypeErr())
+	return err == nil, err
+}
+
+// yieldElements passes the index and value of each element of val to
+// yield. If val is not an array, it passes it with index 0 in lax mode.
+// Returns false if yield returns false or if val is not an array in strict
+// mode and exec is not silent, along with an error for the latter. Returns
+// true for a non-array when exec is silent, so that iteration skips it.
+func (exec *Executor) yieldElements(val any, yield func(int, any) bool) (bool, error) {
+	array, ok := val.([]any)
+	if !ok {
+		if !exec.autoWrap() {
+			_, err := exec.returnVerboseError(anyArrayTypeErr())
+			return err == nil, err
+		}
+		array = []any{val}
+	}
+
+	for i, elem := range array {
+		v, err := exec.result(elem)
+		if err != nil {
+			return false, err
+		}
+		if !yield(i, v) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
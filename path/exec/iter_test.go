@@ -0,0 +1,490 @@
+//go:build go1.23
+
+package exec
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestIter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	large := make([]any, DefaultParallelThreshold)
+	for i := range large {
+		large[i] = int64(i)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opt   []Option
+		exp   []any
+		err   string
+	}{
+		{
+			name:  "empty",
+			path:  "$.a",
+			value: map[string]any{},
+			exp:   []any{},
+		},
+		{
+			name:  "values",
+			path:  "$[*] ? (@ > 1)",
+			value: []any{int64(1), int64(2), int64(3)},
+			exp:   []any{int64(2), int64(3)},
+		},
+		{
+			name:  "vars",
+			path:  "$[*] ? (@ >= $min)",
+			value: []any{int64(1), int64(2), int64(3)},
+			opt:   []Option{WithVars(Vars{"min": int64(3)})},
+			exp:   []any{int64(3)},
+		},
+		{
+			name:  "strict_error_after_values",
+			path:  "strict $[*].a",
+			value: []any{map[string]any{"a": "x"}, map[string]any{}},
+			exp:   []any{"x"},
+			err:   `exec: JSON object does not contain key "a"`,
+		},
+		{
+			name:  "silent",
+			path:  "strict $[*].a",
+			value: []any{map[string]any{"a": "x"}, map[string]any{}},
+			opt:   []Option{WithSilent()},
+			exp:   []any{"x"},
+		},
+		{
+			name:  "limit",
+			path:  "$[*]",
+			value: []any{int64(1), int64(2), int64(3)},
+			opt:   []Option{WithMaxResults(2)},
+			exp:   []any{int64(1), int64(2)},
+			err:   "exec: result limit exceeded",
+		},
+		{
+			name:  "parallel",
+			path:  "$[*] ? (@ > 4093)",
+			value: large,
+			opt:   []Option{WithParallel(2)},
+			exp:   []any{int64(4094), int64(4095)},
+		},
+		{
+			name:  "predicate_check",
+			path:  "$ == 1",
+			value: int64(1),
+			opt:   []Option{WithPredicateCheck()},
+			exp:   []any{},
+			err:   `exec: Iter expects a SQL standard path expression but "($ == 1)" is a predicate check expression`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			vals := []any{}
+			var iterErr error
+			for val, err := range Iter(ctx, path, tc.value, tc.opt...) {
+				if err != nil {
+					r.NoError(iterErr, "yielded more than one error")
+					iterErr = err
+					continue
+				}
+				r.NoError(iterErr, "yielded a value after an error")
+				vals = append(vals, val)
+			}
+			a.Equal(tc.exp, vals)
+			if tc.err == "" {
+				r.NoError(iterErr)
+			} else {
+				r.EqualError(iterErr, tc.err)
+				r.ErrorIs(iterErr, ErrExecution)
+			}
+		})
+	}
+}
+
+func TestIterKeyValues(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	obj := map[string]any{"b": int64(2), "a": int64(1)}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opt   []Option
+		keys  []string
+		vals  []any
+		skips bool // Keys and Values stop at the non-objects skipped
+		err   string
+	}{
+		{
+			name:  "object",
+			path:  "$",
+			value: obj,
+			keys:  []string{"a", "b"},
+			vals:  []any{int64(1), int64(2)},
+		},
+		{
+			name:  "objects",
+			path:  "$[*]",
+			value: []any{obj, map[string]any{}, map[string]any{"c": "x"}},
+			keys:  []string{"a", "b", "c"},
+			vals:  []any{int64(1), int64(2), "x"},
+		},
+		{
+			name:  "lax_unwrap",
+			path:  "$",
+			value: []any{obj, map[string]any{"c": "x"}},
+			keys:  []string{"a", "b", "c"},
+			vals:  []any{int64(1), int64(2), "x"},
+		},
+		{
+			name:  "strict_array",
+			path:  "strict $",
+			value: []any{obj},
+			keys:  []string{},
+			vals:  []any{},
+			err:   "exec: jsonpath item method .keyvalue() can only be applied to an object",
+		},
+		{
+			name:  "not_object",
+			path:  "$[*]",
+			value: []any{obj, "hi", map[string]any{"c": "x"}},
+			keys:  []string{"a", "b"},
+			vals:  []any{int64(1), int64(2)},
+			err:   "exec: jsonpath item method .keyvalue() can only be applied to an object",
+		},
+		{
+			name:  "silent",
+			path:  "$[*]",
+			value: []any{obj, "hi", map[string]any{"c": "x"}},
+			opt:   []Option{WithSilent()},
+			keys:  []string{"a", "b", "c"},
+			vals:  []any{int64(1), int64(2), "x"},
+			skips: true,
+		},
+		{
+			name:  "silent_unwrap",
+			path:  "$",
+			value: []any{int64(1), obj, []any{"hi"}, map[string]any{"c": "x"}, true},
+			opt:   []Option{WithSilent()},
+			keys:  []string{"a", "b", "c"},
+			vals:  []any{int64(1), int64(2), "x"},
+			skips: true,
+		},
+		{
+			name:  "predicate_check",
+			path:  "$ == 1",
+			value: obj,
+			opt:   []Option{WithPredicateCheck()},
+			keys:  []string{},
+			vals:  []any{},
+			err:   `exec: IterKeyValues expects a SQL standard path expression but "($ == 1)" is a predicate check expression`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			keys := []string{}
+			vals := []any{}
+			seq, errFn := IterKeyValues(ctx, path, tc.value, tc.opt...)
+			for k, v := range seq {
+				keys = append(keys, k)
+				vals = append(vals, v)
+			}
+			a.Equal(tc.keys, keys)
+			a.Equal(tc.vals, vals)
+			switch {
+			case tc.err != "":
+				r.EqualError(errFn(), tc.err)
+				r.ErrorIs(errFn(), ErrExecution)
+			case tc.skips:
+				r.NoError(errFn())
+			default:
+				r.NoError(errFn())
+				// Should agree with Keys and Values.
+				expKeys, err := Keys(ctx, path, tc.value, tc.opt...)
+				r.NoError(err)
+				a.Equal(expKeys, keys)
+				expVals, err := Values(ctx, path, tc.value, tc.opt...)
+				r.NoError(err)
+				a.Equal(expVals, vals)
+			}
+		})
+	}
+}
+
+func TestIterIndexed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opt   []Option
+		idxs  []int
+		vals  []any
+		err   string
+	}{
+		{
+			name:  "array",
+			path:  "$",
+			value: []any{"x", "y"},
+			idxs:  []int{0, 1},
+			vals:  []any{"x", "y"},
+		},
+		{
+			name:  "arrays",
+			path:  "$.a",
+			value: []any{map[string]any{"a": []any{"x", "y"}}, map[string]any{"a": []any{"z"}}},
+			idxs:  []int{0, 1, 0},
+			vals:  []any{"x", "y", "z"},
+		},
+		{
+			name:  "lax_wrap",
+			path:  "$[*]",
+			value: []any{[]any{"x", "y"}, "z"},
+			idxs:  []int{0, 1, 0},
+			vals:  []any{"x", "y", "z"},
+		},
+		{
+			name:  "strict_not_array",
+			path:  "strict $[*]",
+			value: []any{[]any{"x", "y"}, "z"},
+			idxs:  []int{0, 1},
+			vals:  []any{"x", "y"},
+			err:   "exec: jsonpath wildcard array accessor can only be applied to an array",
+		},
+		{
+			name:  "strict_silent",
+			path:  "strict $[*]",
+			value: []any{[]any{"x", "y"}, "z", map[string]any{"b": 1}, []any{"a"}},
+			opt:   []Option{WithSilent()},
+			idxs:  []int{0, 1, 0},
+			vals:  []any{"x", "y", "a"},
+		},
+		{
+			name:  "predicate_check",
+			path:  "$ == 1",
+			value: int64(1),
+			opt:   []Option{WithPredicateCheck()},
+			idxs:  []int{},
+			vals:  []any{},
+			err:   `exec: IterIndexed expects a SQL standard path expression but "($ == 1)" is a predicate check expression`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			idxs := []int{}
+			vals := []any{}
+			seq, errFn := IterIndexed(ctx, path, tc.value, tc.opt...)
+			for i, v := range seq {
+				idxs = append(idxs, i)
+				vals = append(vals, v)
+			}
+			a.Equal(tc.idxs, idxs)
+			a.Equal(tc.vals, vals)
+			if tc.err == "" {
+				r.NoError(errFn())
+			} else {
+				r.EqualError(errFn(), tc.err)
+				r.ErrorIs(errFn(), ErrExecution)
+			}
+		})
+	}
+}
+
+func TestIterStop(t *testing.T) {
+	t.Parallel()
+	registerTestMethods()
+	ctx := context.Background()
+	const size = 100
+
+	// visits returns an option that counts the items passed to
+	// .test_local(), to instrument the traversal of a value.
+	visits := func(n *int) Option {
+		return WithMethods(map[string]MethodFunc{
+			"test_local": func(_ context.Context, value any) (any, error) {
+				*n++
+				return value, nil
+			},
+		})
+	}
+
+	t.Run("iter", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse("$[*].test_local()")
+		r.NoError(err)
+		value := make([]any, size)
+		for i := range value {
+			value[i] = int64(i)
+		}
+
+		var n int
+		vals := []any{}
+		for val, err := range Iter(ctx, path, value, visits(&n)) {
+			r.NoError(err)
+			vals = append(vals, val)
+			if len(vals) == 3 {
+				break
+			}
+		}
+		a.Equal([]any{int64(0), int64(1), int64(2)}, vals)
+		a.Equal(3, n)
+
+		// Query visits every item.
+		n = 0
+		_, err = Query(ctx, path, value, visits(&n))
+		r.NoError(err)
+		a.Equal(size, n)
+	})
+
+	t.Run("key_values", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse("$[*].test_local()")
+		r.NoError(err)
+		value := make([]any, size)
+		for i := range value {
+			value[i] = map[string]any{"a": int64(i), "b": int64(i)}
+		}
+
+		var n int
+		keys := []string{}
+		seq, errFn := IterKeyValues(ctx, path, value, visits(&n))
+		for k := range seq {
+			keys = append(keys, k)
+			if len(keys) == 3 {
+				break
+			}
+		}
+		r.NoError(errFn())
+		a.Equal([]string{"a", "b", "a"}, keys)
+		a.Equal(2, n)
+	})
+
+	t.Run("indexed", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse("strict $[*].test_local()")
+		r.NoError(err)
+		value := make([]any, size)
+		for i := range value {
+			value[i] = []any{int64(i), int64(i)}
+		}
+
+		var n int
+		idxs := []int{}
+		seq, errFn := IterIndexed(ctx, path, value, visits(&n))
+		for i := range seq {
+			idxs = append(idxs, i)
+			if len(idxs) == 3 {
+				break
+			}
+		}
+		r.NoError(errFn())
+		a.Equal([]int{0, 1, 0}, idxs)
+		a.Equal(2, n)
+	})
+}
+
+func TestIterInvalidOptions(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	path, err := parser.Parse("$")
+	r.NoError(err)
+	opt := WithMaxResults(-1)
+	const msg = "exec invalid: WithMaxResults value -1 is negative"
+
+	count := 0
+	for val, err := range Iter(ctx, path, nil, opt) {
+		count++
+		a.Nil(val)
+		r.EqualError(err, msg)
+		r.ErrorIs(err, ErrInvalid)
+	}
+	a.Equal(1, count)
+
+	kvs, errFn := IterKeyValues(ctx, path, nil, opt)
+	for range kvs {
+		t.Fatal("IterKeyValues yielded with invalid options")
+	}
+	r.EqualError(errFn(), msg)
+
+	idxs, errFn := IterIndexed(ctx, path, nil, opt)
+	for range idxs {
+		t.Fatal("IterIndexed yielded with invalid options")
+	}
+	r.EqualError(errFn(), msg)
+}
+
+func TestIterParallelStop(t *testing.T) {
+	t.Parallel()
+	registerTestMethods()
+	a := assert.New(t)
+	r := require.New(t)
+
+	array := make([]any, 100)
+	for i := range array {
+		array[i] = int64(i)
+	}
+	path, err := parser.Parse(`$[*].test_local()`)
+	r.NoError(err)
+
+	// The second chunk blocks until stopping the iteration cancels it.
+	var timedOut atomic.Int32
+	method := func(ctx context.Context, value any) (any, error) {
+		if n, _ := value.(int64); n < 50 {
+			return value, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Second):
+			timedOut.Add(1)
+			return value, nil
+		}
+	}
+
+	got := []any{}
+	for val, err := range Iter(
+		context.Background(), path, array,
+		WithMethods(map[string]MethodFunc{"test_local": method}),
+		WithParallel(2), withParallelThreshold(1),
+	) {
+		r.NoError(err)
+		got = append(got, val)
+		break
+	}
+	a.Equal([]any{int64(0)}, got)
+	a.Zero(timedOut.Load())
+}
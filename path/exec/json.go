@@ -0,0 +1,155 @@
+package exec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrDuplicateKey errors denote a JSON object with duplicate keys passed to
+// [NormalizeJSONStrict].
+//
+//nolint:gochecknoglobals
+var ErrDuplicateKey = fmt.Errorf("%w: duplicate JSON object key", ErrExecution)
+
+// ErrDecode errors denote a failure to decode the JSON passed to
+// [NormalizeJSON], [NormalizeJSONStrict], or [QueryReader] and its variants:
+// invalid JSON, more than one JSON value, a value nested more deeply than
+// [DefaultMaxDepth] for NormalizeJSONStrict, an error reading from the
+// [io.Reader] passed to QueryReader, or a document read by the latter that
+// exceeds the limit set by [WithMaxDocumentBytes]. Duplicate keys found by
+// NormalizeJSONStrict return [ErrDuplicateKey] errors instead. ErrDecode
+// errors also wrap [ErrExecution], but no execution error category, so that
+// callers can distinguish invalid input from errors executing a path.
+//
+//nolint:gochecknoglobals
+var ErrDecode = errors.New("invalid JSON")
+
+// NormalizeJSON decodes data, which must contain a single JSON value, into
+// a value for execution, decoding numbers as [json.Number] values to
+// preserve their precision. Like PostgreSQL jsonb, objects with duplicate
+// keys retain only the value of the last instance of each key. Returns an
+// [ErrDecode] error if data is not valid JSON.
+func NormalizeJSON(data []byte) (any, error) {
+	dec := newJSONDecoder(data)
+	var val any
+	if err := dec.Decode(&val); err != nil {
+		return nil, jsonError(err)
+	}
+	if err := checkJSONEnd(dec); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// NormalizeJSONStrict is like [NormalizeJSON], but returns an
+// [ErrDuplicateKey] error for an object with duplicate keys, for use in
+// validating JSON documents. The error message includes the duplicate key
+// and the normalized path to the object that contains it, such as $."a"[1].
+func NormalizeJSONStrict(data []byte) (any, error) {
+	dec := newJSONDecoder(data)
+	exec := &Executor{}
+	exec.enter(locElem{kind: locRoot})
+	val, err := exec.decodeStrict(dec)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkJSONEnd(dec); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// newJSONDecoder returns a JSON decoder for data that decodes numbers as
+// [json.Number] values.
+func newJSONDecoder(data []byte) *json.Decoder {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec
+}
+
+// checkJSONEnd returns an [ErrDecode] error if dec contains more than
+// whitespace after the value it has decoded.
+func checkJSONEnd(dec *json.Decoder) error {
+	_, err := dec.Token()
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil
+	case err != nil:
+		return jsonError(err)
+	default:
+		return fmt.Errorf("%w: %w: unexpected data after top-level value", ErrExecution, ErrDecode)
+	}
+}
+
+// jsonError wraps err, an error decoding JSON, in an [ErrDecode] error.
+func jsonError(err error) error {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("%w: %w: %w", ErrExecution, ErrDecode, err)
+}
+
+// decodeStrict decodes the next JSON value from dec, returning an
+// [ErrDuplicateKey] error for an object with duplicate keys. Uses the
+// location of exec to record the location of the value being decoded.
+func (exec *Executor) decodeStrict(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, jsonError(err)
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+	if len(exec.location) > DefaultMaxDepth {
+		return nil, fmt.Errorf("%w: %w: exceeded max depth", ErrExecution, ErrDecode)
+	}
+
+	switch delim {
+	case '{':
+		obj := map[string]any{}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, jsonError(err)
+			}
+			key, _ := tok.(string) // Token returns only string keys
+			if _, dup := obj[key]; dup {
+				return nil, fmt.Errorf("%w %q at %v", ErrDuplicateKey, key, exec.locationString())
+			}
+			size := exec.enterKey(key)
+			val, err := exec.decodeStrict(dec)
+			exec.leave(size)
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = val
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, jsonError(err)
+		}
+		return obj, nil
+	case '[':
+		array := []any{}
+		for dec.More() {
+			size := exec.enterIndex(len(array))
+			val, err := exec.decodeStrict(dec)
+			exec.leave(size)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, val)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, jsonError(err)
+		}
+		return array, nil
+	}
+
+	// The decoder does not return closing delimiters without opening ones.
+	return nil, fmt.Errorf("%w: unexpected JSON delimiter %v", ErrInvalid, delim)
+}
@@ -0,0 +1,194 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestNormalizeJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		json   string
+		exp    any
+		err    string
+		strict string
+	}{
+		{
+			name: "scalar",
+			json: `1.50`,
+			exp:  json.Number("1.50"),
+		},
+		{
+			name: "whitespace",
+			json: " \n\t\"hi\" \n",
+			exp:  "hi",
+		},
+		{
+			name: "object",
+			json: `{"b": 1, "a": [true, null, 1e2]}`,
+			exp:  map[string]any{"a": []any{true, nil, json.Number("1e2")}, "b": json.Number("1")},
+		},
+		{
+			name:   "duplicate_root",
+			json:   `{"a": 1, "b": 2, "a": 3}`,
+			exp:    map[string]any{"a": json.Number("3"), "b": json.Number("2")},
+			strict: `exec: duplicate JSON object key "a" at $`,
+		},
+		{
+			name:   "duplicate_nested",
+			json:   `{"x": {"y": {"z": 1, "z": [2]}}}`,
+			exp:    map[string]any{"x": map[string]any{"y": map[string]any{"z": []any{json.Number("2")}}}},
+			strict: `exec: duplicate JSON object key "z" at $."x"."y"`,
+		},
+		{
+			name: "duplicate_in_array",
+			json: `[{"a": 1}, {"a": {"a": 2, "b": 3}, "a": {"c": 4}}]`,
+			exp: []any{
+				map[string]any{"a": json.Number("1")},
+				map[string]any{"a": map[string]any{"c": json.Number("4")}},
+			},
+			strict: `exec: duplicate JSON object key "a" at $[1]`,
+		},
+		{
+			name: "duplicate_multiple_levels",
+			json: `{"a": [0, {"k": 1, "k": 2}], "a": {"k": {"q": 1, "q": 2}, "k": 3}}`,
+			exp:  map[string]any{"a": map[string]any{"k": json.Number("3")}},
+			// Reports the first duplicate.
+			strict: `exec: duplicate JSON object key "k" at $."a"[1]`,
+		},
+		{
+			name:   "duplicate_quoted_key",
+			json:   `{"a b": {"": 1, "": 2}}`,
+			exp:    map[string]any{"a b": map[string]any{"": json.Number("2")}},
+			strict: `exec: duplicate JSON object key "" at $."a b"`,
+		},
+		{
+			name: "same_key_different_objects",
+			json: `{"a": {"a": 1}, "b": {"a": 2}}`,
+			exp: map[string]any{
+				"a": map[string]any{"a": json.Number("1")},
+				"b": map[string]any{"a": json.Number("2")},
+			},
+		},
+		{
+			name: "empty",
+			json: ``,
+			err:  "exec: invalid JSON: unexpected EOF",
+		},
+		{
+			name:   "truncated",
+			json:   `{"a": [1,`,
+			err:    "exec: invalid JSON: unexpected EOF",
+			strict: "exec: invalid JSON: unexpected end of JSON input",
+		},
+		{
+			name: "trailing_data",
+			json: `{"a": 1} {"b": 2}`,
+			err:  "exec: invalid JSON: unexpected data after top-level value",
+		},
+		{
+			name: "invalid",
+			json: `{"a": nope}`,
+			err:  "exec: invalid JSON: invalid character 'o' in literal null (expecting 'u')",
+		},
+		{
+			name: "invalid_trailing",
+			json: `1 }`,
+			err:  "exec: invalid JSON: invalid character '}' looking for beginning of value",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			val, err := NormalizeJSON([]byte(tc.json))
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				r.ErrorIs(err, ErrDecode)
+				a.Nil(val)
+				val, err = NormalizeJSONStrict([]byte(tc.json))
+				if tc.strict != "" {
+					r.EqualError(err, tc.strict)
+				} else {
+					r.EqualError(err, tc.err)
+				}
+				r.ErrorIs(err, ErrExecution)
+				r.ErrorIs(err, ErrDecode)
+				r.NotErrorIs(err, ErrDuplicateKey)
+				a.Nil(val)
+				return
+			}
+			r.NoError(err)
+			a.Equal(tc.exp, val)
+
+			val, err = NormalizeJSONStrict([]byte(tc.json))
+			if tc.strict != "" {
+				r.EqualError(err, tc.strict)
+				r.ErrorIs(err, ErrDuplicateKey)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(val)
+				return
+			}
+			r.NoError(err)
+			a.Equal(tc.exp, val)
+		})
+	}
+
+	t.Run("max_depth", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		doc := strings.Repeat("[", DefaultMaxDepth+1) + strings.Repeat("]", DefaultMaxDepth+1)
+		val, err := NormalizeJSONStrict([]byte(doc))
+		a.EqualError(err, "exec: invalid JSON: exceeded max depth")
+		a.Nil(val)
+	})
+}
+
+func TestNormalizeJSONKeyValue(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	// .keyvalue() sees only the last instance of each duplicate key, and
+	// visits keys in sorted order regardless of their order in the JSON.
+	val, err := NormalizeJSON([]byte(`{
+		"c": 1, "a": {"z": 1, "y": 2, "z": 3}, "b": 2, "c": 4, "a": {"y": 5, "x": 6, "y": 7}
+	}`))
+	r.NoError(err)
+
+	path, err := parser.Parse(`$`)
+	r.NoError(err)
+	keys, err := Keys(ctx, path, val)
+	r.NoError(err)
+	a.Equal([]string{"a", "b", "c"}, keys)
+
+	path, err = parser.Parse(`$.a`)
+	r.NoError(err)
+	keys, err = Keys(ctx, path, val)
+	r.NoError(err)
+	a.Equal([]string{"x", "y"}, keys)
+	vals, err := Values(ctx, path, val)
+	r.NoError(err)
+	a.Equal([]any{json.Number("6"), json.Number("7")}, vals)
+
+	path, err = parser.Parse(`$.keyvalue().value`)
+	r.NoError(err)
+	res, err := Query(ctx, path, val)
+	r.NoError(err)
+	a.Equal([]any{
+		map[string]any{"x": json.Number("6"), "y": json.Number("7")},
+		json.Number("2"),
+		json.Number("4"),
+	}, res)
+}
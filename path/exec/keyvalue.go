@@ -3,8 +3,8 @@ package exec
 import (
 	"context"
 	"fmt"
-	"reflect"
 	"slices"
+	"strconv"
 
 	"github.com/theory/sqljson/path/ast"
 	"golang.org/x/exp/maps" // Switch to maps when go 1.22 dropped
@@ -13,39 +13,68 @@ import (
 // kvBaseObject represents the "base object" and its "id" for .keyvalue()
 // evaluation.
 type kvBaseObject struct {
-	addr uintptr
-	id   int
+	id  int // "id" of the base object
+	loc int // length of exec.location at the base object
 }
 
-// addrOf returns the pointer address of obj when obj is a valid JSON
-// container: one of map[string]any, []any, or Vars. Otherwise it returns 0.
-// Used for .keyvalue() ID generation.
-func addrOf(obj any) uintptr {
-	switch obj := obj.(type) {
-	case []any, map[string]any, Vars:
-		return reflect.ValueOf(obj).Pointer()
-	default:
+// setTempBaseObject sets the item at the current location as
+// exec.baseObject with id and returns a function that will reset it to the
+// previous value.
+func (exec *Executor) setTempBaseObject(id int) func() {
+	bo := exec.baseObject
+	exec.baseObject = kvBaseObject{id: id, loc: len(exec.location)}
+	return func() { exec.baseObject = bo }
+}
+
+// kvOffset returns the offset of the object at the current location from
+// exec.baseObject for .keyvalue() ID generation: 0 for the base object
+// itself, and otherwise a number assigned the first time the execution
+// requests the offset of an object at the same location relative to the
+// same base object, in the order of those requests. Unlike the addresses
+// of Go values, locations are independent of memory layout, so IDs are
+// deterministic, and executions over a shared value have no shared state.
+func (exec *Executor) kvOffset() int64 {
+	elems := exec.location[exec.baseObject.loc:]
+	if len(elems) == 0 {
 		return 0
 	}
-}
 
-// OffsetOf returns the offset of obj from bo. This is the difference between
-// their pointer addresses.
-func (bo kvBaseObject) OffsetOf(obj any) int64 {
-	addr := addrOf(obj)
-	if addr > bo.addr {
-		return int64(addr - bo.addr)
+	// Base objects replace one another for roots, variables, and generated
+	// objects, so the location relative to the base object contains only
+	// the variable it contains, keys, and indexes.
+	key := make([]byte, 0, 64)
+	key = strconv.AppendInt(key, int64(exec.baseObject.id), 10)
+	for _, elem := range elems {
+		switch elem.kind {
+		case locVariable, locKey:
+			key = append(key, '.')
+			key = strconv.AppendQuote(key, elem.name)
+		case locIndex:
+			key = append(key, '[')
+			key = strconv.AppendInt(key, int64(elem.index), 10)
+			key = append(key, ']')
+		case locRoot, locSynthetic:
+		}
+	}
+
+	if off, ok := exec.kvOffsets[string(key)]; ok {
+		return off
+	}
+	if exec.kvOffsets == nil {
+		exec.kvOffsets = map[string]int64{}
 	}
-	return int64(bo.addr - addr)
+	off := int64(len(exec.kvOffsets)) + 1
+	exec.kvOffsets[string(key)] = off
+	return off
 }
 
-// setTempBaseObject sets obj as exec.baseObject and returns a function that
-// will reset it to the previous value.
-func (exec *Executor) setTempBaseObject(obj any, id int) func() {
-	bo := exec.baseObject
-	exec.baseObject.addr = addrOf(obj)
-	exec.baseObject.id = id
-	return func() { exec.baseObject = bo }
+// keyValueTypeErr creates the error for .keyvalue() applied to an item other
+// than an object.
+func keyValueTypeErr() error {
+	return fmt.Errorf(
+		`%w: jsonpath item method .keyvalue() can only be applied to an object`,
+		errVerboseType,
+	)
 }
 
 // executeKeyValueMethod implements the .keyvalue() method.
@@ -54,12 +83,14 @@ func (exec *Executor) setTempBaseObject(obj any, id int) func() {
 // following format: '{ "key": key, "value": value, "id": id }'.
 //
 // "id" field is an object identifier which is constructed from the two parts:
-// base object id and its binary offset from the base object:
-// id = exec.baseObject.id * 10000000000 + exec.baseObject.OffsetOf(object).
+// base object id and its offset from the base object:
+// id = exec.baseObject.id * 10000000000 + exec.kvOffset().
 //
 // 10000000000 (10^10) -- is the first round decimal number greater than 2^32
 // (maximal offset in jsonb). The decimal multiplier is used here to improve
-// the readability of identifiers.
+// the readability of identifiers. Go values have no binary offsets, so
+// rather than the offset in bytes as in PostgreSQL, the offset is the order
+// in which .keyvalue() first reaches each object within the base object.
 //
 // exec.baseObject is usually the root object of the path (context item '$')
 // or path variable '$var' (literals can't produce objects for now). Objects
@@ -70,30 +101,20 @@ func (exec *Executor) setTempBaseObject(obj any, id int) func() {
 //   - ID of '$var' is 10000000000.
 //   - IDs for objects generated by .keyvalue() are assigned using global counter
 //     exec.lastGeneratedObjectId: 20000000000, 30000000000, 40000000000, etc.
+//
+// As in PostgreSQL, lax mode unwraps arrays but does not skip the
+// non-objects they contain: the first one raises an error, which in silent
+// mode ends iteration with the pairs already found. Pairs are generated one
+// at a time and passed to the next node before generating the next pair.
 func (exec *Executor) executeKeyValueMethod(
 	ctx context.Context,
 	node ast.Node,
 	value any,
 	found *valueList,
-	unwrap bool,
 ) (resultStatus, error) {
-	var obj map[string]any
-	switch val := value.(type) {
-	case []any:
-		if unwrap {
-			return exec.executeItemUnwrapTargetArray(ctx, node, value, found)
-		}
-		return exec.returnVerboseError(fmt.Errorf(
-			`%w: jsonpath item method .keyvalue() can only be applied to an object`,
-			ErrVerbose,
-		))
-	case map[string]any:
-		obj = val
-	default:
-		return exec.returnVerboseError(fmt.Errorf(
-			`%w: jsonpath item method .keyvalue() can only be applied to an object`,
-			ErrVerbose,
-		))
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return exec.returnVerboseError(keyValueTypeErr())
 	}
 
 	if len(obj) == 0 {
@@ -106,22 +127,41 @@ func (exec *Executor) executeKeyValueMethod(
 		return statusOK, nil
 	}
 
-	id := exec.baseObject.OffsetOf(obj)
+	id := exec.kvOffset()
 	const tenTen = 10000000000 // 10^10
 	id += int64(exec.baseObject.id) * tenTen
 
 	// Process the keys in a deterministic order for consistent ID assignment.
 	keys := maps.Keys(obj)
 	slices.Sort(keys)
+	names, err := exec.validUTF8Keys(keys)
+	if err != nil {
+		return exec.returnError(err)
+	}
+
+	// Emit .keyvalue().key and .keyvalue().value directly rather than
+	// materializing the key/value objects, unless a subsequent .keyvalue()
+	// might need one as its base object.
+	if field, ok := next.(*ast.KeyNode); ok && !hasKeyValue(field.Next()) {
+		switch field.Text() {
+		case "key", "value":
+			return exec.executeKeyValueField(ctx, field, obj, keys, names, found)
+		}
+	}
 
 	var res resultStatus
-	for _, k := range keys {
-		obj := map[string]any{"key": k, "value": obj[k], "id": id}
+	for i, k := range keys {
+		val, err := exec.memberValue(obj, k)
+		if err != nil {
+			return statusFailed, err
+		}
+		obj := map[string]any{"key": names[i], "value": val, "id": id}
 		exec.lastGeneratedObjectID++
-		defer exec.setTempBaseObject(obj, exec.lastGeneratedObjectID)()
+		loc := exec.enter(locElem{kind: locSynthetic})
+		defer exec.setTempBaseObject(exec.lastGeneratedObjectID)()
 
-		var err error
 		res, err = exec.executeNextItem(ctx, node, next, obj, found)
+		exec.leave(loc)
 		if res == statusFailed {
 			return res, err
 		}
@@ -132,3 +172,83 @@ func (exec *Executor) executeKeyValueMethod(
 	}
 	return res, nil
 }
+
+// executeKeyValueField implements the .keyvalue().key and .keyvalue().value
+// fast path by passing each key or value in obj, in the order of keys, to
+// field's next node. names contains the keys to emit, which differ from
+// keys only when [WithLenientUTF8] replaces invalid UTF-8.
+func (exec *Executor) executeKeyValueField(
+	ctx context.Context,
+	field *ast.KeyNode,
+	obj map[string]any,
+	keys, names []string,
+	found *valueList,
+) (resultStatus, error) {
+	wantKey := field.Text() == "key"
+	var res resultStatus
+	for i, k := range keys {
+		var val any = names[i]
+		var err error
+		if !wantKey {
+			if val, err = exec.memberValue(obj, k); err != nil {
+				return statusFailed, err
+			}
+		}
+
+		// Keep IDs consistent with those generated by the full
+		// implementation.
+		exec.lastGeneratedObjectID++
+
+		loc := exec.enter(locElem{kind: locSynthetic})
+		res, err = exec.executeNextItem(ctx, field, nil, val, found)
+		exec.leave(loc)
+		if res == statusFailed {
+			return res, err
+		}
+
+		if res == statusOK && found == nil {
+			break
+		}
+	}
+	return res, nil
+}
+
+// memberValue returns the value of the member key of obj normalized by
+// normalize, so that .keyvalue() pairs contain only JSON values. Errors
+// report the location of the member.
+func (exec *Executor) memberValue(obj map[string]any, key string) (any, error) {
+	loc := exec.enterKey(key)
+	defer exec.leave(loc)
+	val, err := exec.normalize(obj[key])
+	return val, exec.locate(err)
+}
+
+// hasKeyValue returns true if node, its operands, or its next nodes include
+// the .keyvalue() method.
+func hasKeyValue(node ast.Node) bool {
+	for ; node != nil; node = node.Next() {
+		switch node := node.(type) {
+		case *ast.MethodNode:
+			if node.Name() == ast.MethodKeyValue {
+				return true
+			}
+		case *ast.BinaryNode:
+			if hasKeyValue(node.Left()) || hasKeyValue(node.Right()) {
+				return true
+			}
+		case *ast.UnaryNode:
+			if hasKeyValue(node.Operand()) {
+				return true
+			}
+		case *ast.RegexNode:
+			if hasKeyValue(node.Operand()) {
+				return true
+			}
+		case *ast.ArrayIndexNode:
+			if slices.ContainsFunc(node.Subscripts(), hasKeyValue) {
+				return true
+			}
+		}
+	}
+	return false
+}
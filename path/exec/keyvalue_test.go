@@ -2,140 +2,63 @@ package exec
 
 import (
 	"context"
-	"reflect"
+	"sync"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/theory/sqljson/path/parser"
-	"github.com/theory/sqljson/path/types"
 )
 
-func TestAddrOf(t *testing.T) {
+func TestKVOffset(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
 
-	for _, tc := range []struct {
-		name  string
-		value any
-		noID  bool
-	}{
-		{
-			name:  "map",
-			value: map[string]any{"hi": 1},
-		},
-		{
-			name:  "slice",
-			value: []any{1, 2},
-		},
-		{
-			name:  "vars",
-			value: Vars{"x": true},
-		},
-		{
-			name:  "int",
-			value: int64(42),
-			noID:  true,
-		},
-		{
-			name:  "bool",
-			value: true,
-			noID:  true,
-		},
-		{
-			name: "nil",
-			noID: true,
-		},
-		{
-			name:  "datetime",
-			value: types.NewDate(time.Now()),
-			noID:  true,
-		},
-	} {
-		t.Run(tc.name, func(t *testing.T) {
-			t.Parallel()
-			ptr := addrOf(tc.value)
-			if tc.noID {
-				a.Zero(ptr)
-			} else {
-				a.Equal(ptr, reflect.ValueOf(tc.value).Pointer())
-			}
-		})
-	}
-}
+	e := &Executor{}
+	e.enter(locElem{kind: locRoot})
+	done := e.setTempBaseObject(0)
 
-// deltaBetween determines the memory distance between collection and one of
-// the items it contains. Used to determine keyvalue IDs at runtime because
-// the memory distance can vary at runtime, but should be consistent between
-// the same two literal values.
-func deltaBetween(collection, item any) int64 {
-	delta := int64(reflect.ValueOf(item).Pointer() - reflect.ValueOf(collection).Pointer())
-	if delta < 0 {
-		return -delta
-	}
-	return delta
-}
+	// The base object has offset 0.
+	a.Equal(int64(0), e.kvOffset())
+	a.Nil(e.kvOffsets)
 
-func TestKVBaseObject(t *testing.T) {
-	t.Parallel()
-	a := assert.New(t)
-	r := require.New(t)
-	ctx := context.Background()
-	const tenTen = int64(10000000000) // 10^10
+	// Other objects have offsets in the order first requested.
+	size := e.enterKey("a")
+	a.Equal(int64(1), e.kvOffset())
+	e.enterIndex(2)
+	a.Equal(int64(2), e.kvOffset())
+	e.leave(size)
+	e.enterKey("b")
+	a.Equal(int64(3), e.kvOffset())
+	e.leave(size)
+	e.enterKey("a")
+	a.Equal(int64(1), e.kvOffset())
+	e.enterIndex(2)
+	a.Equal(int64(2), e.kvOffset())
+	e.leave(size)
 
-	// The offset of an array inside a map can very by execution, so calculate
-	// it at runtime.
-	mapArray := map[string]any{"x": []any{1, 4}}
-	mapArrayOff := deltaBetween(mapArray, mapArray["x"])
+	// Keys are quoted so that they cannot be confused with other locations.
+	e.enterKey(`a"[2]`)
+	a.Equal(int64(4), e.kvOffset())
+	e.leave(size)
 
-	for _, tc := range []struct {
-		name string
-		base any
-		path string
-		exp  int64
-	}{
-		{
-			name: "sub-map",
-			base: map[string]any{"x": map[string]any{"y": 1}},
-			path: "$.x",
-		},
-		{
-			name: "sub-sub-map",
-			base: map[string]any{"x": map[string]any{"y": map[string]any{"z": 1}}},
-			path: "$.x.y",
-		},
-		{
-			name: "sub-array",
-			base: mapArray,
-			path: "$.x",
-			exp:  mapArrayOff,
-		},
-	} {
-		t.Run(tc.name, func(t *testing.T) {
-			t.Parallel()
-
-			// Use path to fetch the object from base
-			path, err := parser.Parse(tc.path)
-			r.NoError(err)
-			obj, err := First(ctx, path, tc.base)
-			r.NoError(err)
-
-			kvBase := kvBaseObject{addr: addrOf(tc.base), id: 0}
-			off := kvBase.OffsetOf(obj)
+	// The same location relative to another base object has another offset.
+	e.enter(locElem{kind: locSynthetic})
+	restore := e.setTempBaseObject(2)
+	a.Equal(int64(0), e.kvOffset())
+	e.enterKey("a")
+	a.Equal(int64(5), e.kvOffset())
+	restore()
+	a.Equal(int64(1), e.kvOffset())
+	e.leave(size)
 
-			if tc.exp > 0 {
-				// We pre-calculated the id.
-				a.Equal(tc.exp, off)
-			} else {
-				// The ID can vary at runtime (48, 96, 144, 480 are common, but so
-				// are much larger numbers), so just make sure it's greater than 0
-				// and less than 10000000000.
-				a.Positive(off)
-				a.Less(off, tenTen)
-			}
-		})
-	}
+	// Variables are relative to the variables object.
+	e.setTempBaseObject(1)
+	e.enter(locElem{kind: locVariable, name: "a"})
+	a.Equal(int64(6), e.kvOffset())
+	e.leave(size)
+	done()
+	a.Equal(kvBaseObject{}, e.baseObject)
 }
 
 func TestSetTempBaseObject(t *testing.T) {
@@ -143,25 +66,23 @@ func TestSetTempBaseObject(t *testing.T) {
 	a := assert.New(t)
 
 	// Set up a base object.
-	e := &Executor{baseObject: kvBaseObject{addr: uintptr(90210), id: 4}}
+	e := &Executor{baseObject: kvBaseObject{id: 4, loc: 1}}
 
 	// Replace it.
-	obj := map[string]any{"x": 1}
-	done := e.setTempBaseObject(obj, 2)
-	a.Equal(reflect.ValueOf(obj).Pointer(), e.baseObject.addr)
-	a.Equal(2, e.baseObject.id)
+	e.enter(locElem{kind: locRoot})
+	e.enterKey("x")
+	done := e.setTempBaseObject(2)
+	a.Equal(kvBaseObject{id: 2, loc: 2}, e.baseObject)
 
 	// Restore the original.
 	done()
-	a.Equal(uintptr(90210), e.baseObject.addr)
-	a.Equal(4, e.baseObject.id)
+	a.Equal(kvBaseObject{id: 4, loc: 1}, e.baseObject)
 }
 
 func TestExecuteKeyValueMethod(t *testing.T) {
 	t.Parallel()
-	// ID can vary at runtime, so figure out the value at runtime.
 	vars := Vars{"foo": map[string]any{"x": true, "y": 1}}
-	fooID := 10000000000 + deltaBetween(vars, vars["foo"])
+	fooID := int64(10000000001)
 
 	for _, tc := range []execTestCase{
 		{
@@ -262,6 +183,81 @@ func TestExecuteKeyValueMethod(t *testing.T) {
 			err:  "exec: jsonpath item method .keyvalue() can only be applied to an object",
 			exp:  []any{},
 		},
+		{
+			name: "kv_key",
+			path: "$.keyvalue().key",
+			json: map[string]any{"y": "hi", "x": true},
+			exp:  []any{"x", "y"},
+		},
+		{
+			name: "kv_value",
+			path: "$.keyvalue().value",
+			json: map[string]any{"y": "hi", "x": true},
+			exp:  []any{true, "hi"},
+		},
+		{
+			name: "kv_key_filter",
+			path: `$.keyvalue().key ? (@ starts with "b")`,
+			json: map[string]any{"foo": 1, "bar": 2, "baz": 3},
+			exp:  []any{"bar", "baz"},
+		},
+		{
+			name: "kv_value_accessor",
+			path: "$.keyvalue().value.a",
+			json: map[string]any{"x": map[string]any{"a": 1}, "y": map[string]any{"a": 2}},
+			exp:  []any{1, 2},
+		},
+		{
+			name: "kv_value_strict_error",
+			path: "strict $.keyvalue().value.a",
+			json: map[string]any{"x": true, "y": map[string]any{"a": 1}},
+			err:  `exec: jsonpath member accessor can only be applied to an object`,
+			exp:  []any{},
+		},
+		{
+			name: "kv_id",
+			path: "$.keyvalue().id",
+			json: map[string]any{"x": true, "y": "hi"},
+			exp:  []any{int64(0), int64(0)},
+		},
+		{
+			name: "kv_key_sequence",
+			path: "$.keyvalue().value.keyvalue().key",
+			json: map[string]any{"x": map[string]any{"b": 1, "a": 2}},
+			exp:  []any{"a", "b"},
+		},
+		{
+			name: "kv_empty_key",
+			path: "$.keyvalue().key",
+			json: map[string]any{},
+			exp:  []any{},
+		},
+		{
+			name: "kv_null_key",
+			path: "$.keyvalue().key",
+			json: nil,
+			err:  "exec: jsonpath item method .keyvalue() can only be applied to an object",
+			exp:  []any{},
+		},
+		{
+			name: "array_no_unwrap_key",
+			path: "strict $.keyvalue().key",
+			json: []any{map[string]any{"x": true}},
+			err:  "exec: jsonpath item method .keyvalue() can only be applied to an object",
+			exp:  []any{},
+		},
+		{
+			name: "array_unwrap_key",
+			path: "$.keyvalue().key",
+			json: []any{map[string]any{"x": true}, map[string]any{"y": true}},
+			exp:  []any{"x", "y"},
+		},
+		{
+			name: "filter_key",
+			path: "$.keyvalue() ? (@.value > 2).key",
+			json: []any{map[string]any{"a": int64(1), "b": int64(3)}, map[string]any{"c": int64(5)}},
+			exp:  []any{"b", "c"},
+		},
 		{
 			name: "next_error",
 			path: "$.keyvalue().string()",
@@ -277,15 +273,60 @@ func TestExecuteKeyValueMethod(t *testing.T) {
 	}
 }
 
+func TestKeyValueConcurrent(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	value := js(`{"a": {"x": 1, "y": [{"z": 2}, {"z": 3}]}, "b": [{"c": 4}, {"d": {"e": 5}}]}`)
+	path, err := parser.Parse(`$.** ? (@.type() == "object").keyvalue() ? (@.key != "a" && @.key != "b")`)
+	r.NoError(err)
+
+	// IDs follow the order in which .keyvalue() reaches objects, so order
+	// keys for the same order every time. Lax mode visits array elements
+	// twice, with the same IDs.
+	exp, err := Query(ctx, path, value, WithOrderedKeys())
+	r.NoError(err)
+	ids := make([]any, len(exp))
+	for i, res := range exp {
+		obj, ok := res.(map[string]any)
+		r.True(ok)
+		ids[i] = obj["id"]
+	}
+	a.Equal([]any{
+		int64(1), int64(1), int64(2), int64(3), int64(2), int64(3),
+		int64(4), int64(5), int64(4), int64(5), int64(6),
+	}, ids)
+
+	// Concurrent executions over the same value share no state.
+	const n = 100
+	results := make([][]any, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = Query(ctx, path, value, WithOrderedKeys())
+		}()
+	}
+	wg.Wait()
+
+	for i := range n {
+		r.NoError(errs[i])
+		a.Equal(exp, results[i])
+	}
+}
+
 func TestExecuteKeyValueMethodUnwrap(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
 	r := require.New(t)
 	ctx := context.Background()
 
-	// Offset of object in a slice is non-determinate, so calculate it at runtime.
 	value := []any{map[string]any{"x": true, "y": "hi"}}
-	offset := deltaBetween(value, value[0])
+	offset := int64(1)
 
 	// Run the query; lax mode will unwrap value to execute method on its items.
 	path, err := parser.Parse("$.keyvalue()")
@@ -297,3 +338,134 @@ func TestExecuteKeyValueMethodUnwrap(t *testing.T) {
 		map[string]any{"id": offset, "key": "y", "value": "hi"},
 	}, found)
 }
+
+func TestExecuteKeyValueMethodMixed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	kvErr := "exec: jsonpath item method .keyvalue() can only be applied to an object"
+
+	// As in PostgreSQL, non-objects raise an error in lax and strict mode,
+	// and in silent mode end iteration with the results already found.
+	objects := []any{
+		map[string]any{"a": int64(1), "b": []any{int64(3), int64(4)}},
+		int64(2),
+		map[string]any{"c": int64(5)},
+	}
+	offset := int64(1)
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		silent bool
+		exp    []any
+		err    string
+	}{
+		{
+			name: "lax",
+			path: "lax $.keyvalue()",
+			err:  kvErr,
+		},
+		{
+			name:   "lax_silent",
+			path:   "lax $.keyvalue()",
+			silent: true,
+			exp: []any{
+				map[string]any{"id": offset, "key": "a", "value": int64(1)},
+				map[string]any{"id": offset, "key": "b", "value": []any{int64(3), int64(4)}},
+			},
+		},
+		{
+			name: "strict",
+			path: "strict $[*].keyvalue()",
+			err:  kvErr,
+		},
+		{
+			name:   "strict_silent",
+			path:   "strict $.keyvalue()",
+			silent: true,
+			exp:    []any{},
+		},
+		{
+			name: "value_wildcard",
+			path: "lax $[*].keyvalue().value[*]",
+			err:  kvErr,
+		},
+		{
+			name:   "value_wildcard_silent",
+			path:   "lax $[*].keyvalue().value[*]",
+			silent: true,
+			exp:    []any{int64(1), int64(3), int64(4)},
+		},
+		{
+			name: "filter_key",
+			path: "lax $.keyvalue() ? (@.value > 2).key",
+			err:  kvErr,
+		},
+		{
+			name:   "filter_key_silent",
+			path:   "lax $.keyvalue() ? (@.value > 2).key",
+			silent: true,
+			exp:    []any{"b"},
+		},
+		{
+			name:   "key_silent",
+			path:   "lax $.keyvalue().key",
+			silent: true,
+			exp:    []any{"a", "b"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			var opt []Option
+			if tc.silent {
+				opt = append(opt, WithSilent())
+			}
+			res, err := Query(ctx, path, objects, opt...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrVerbose)
+				a.Nil(res)
+			} else {
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+			}
+		})
+	}
+}
+
+func TestHasKeyValue(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	for _, tc := range []struct {
+		path string
+		exp  bool
+	}{
+		{"$", false},
+		{"$.a.b[*]", false},
+		{"$.keyvalue()", true},
+		{"$.a.keyvalue().key", true},
+		{"$.size()", false},
+		{"$ ? (@.a == 1)", false},
+		{"$ ? (@.keyvalue().key == 1)", true},
+		{"$ ? (exists (@.keyvalue()))", true},
+		{"$.a + $.keyvalue().value", true},
+		{`$ ? (@.keyvalue().key like_regex "^a")`, true},
+		{"$[$.keyvalue().value]", true},
+		{"$[1 to $.keyvalue().value]", true},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			a.Equal(tc.exp, hasKeyValue(path.Root()))
+		})
+	}
+	a.False(hasKeyValue(nil))
+}
@@ -0,0 +1,126 @@
+package exec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/theory/sqljson/path/types"
+)
+
+// ErrLimit errors denote that execution exceeded a limit set by
+// [WithMaxResults] or [WithMaxResultBytes]. They also wrap [ErrExecution].
+var ErrLimit = errors.New("result limit exceeded")
+
+// WithMaxResults limits the number of items selected by [Query],
+// [QueryArray], [First], [FirstOrDefault], [Keys], [Values], and [Explain]
+// to n, aborting execution with an [ErrLimit] error as soon as it selects
+// more. Useful to prevent paths like $.** from exhausting memory when
+// executed against large values. [Exists], [Match], and the other predicate
+// functions do not collect results and are unaffected. Zero, the default,
+// disables the limit. Execution returns an [ErrInvalid] error for a
+// negative value.
+func WithMaxResults(n int) Option { return func(e *Executor) { e.maxResults = n } }
+
+// WithMaxResultBytes is like [WithMaxResults], but limits the approximate
+// size of the selected items, in bytes, to n. The size of an item
+// approximates the length of its JSON representation, including all of the
+// members and elements of objects and arrays, even though results alias the
+// queried value unless [WithCopyResults] is specified.
+func WithMaxResultBytes(n int) Option { return func(e *Executor) { e.maxResultBytes = n } }
+
+// resultLimits tracks the items appended to a valueList to enforce the
+// limits set by [WithMaxResults] and [WithMaxResultBytes].
+type resultLimits struct {
+	maxResults int
+	maxBytes   int
+	results    int
+	bytes      int
+	err        error
+}
+
+// newResultList creates a valueList to collect the results of a query,
+// subject to the limits configured for exec.
+func (exec *Executor) newResultList() *valueList {
+	vl := newList()
+	if exec.maxResults > 0 || exec.maxResultBytes > 0 {
+		vl.limits = &resultLimits{maxResults: exec.maxResults, maxBytes: exec.maxResultBytes}
+	}
+	return vl
+}
+
+// add counts val and returns an error if it exceeds either limit. Once it
+// returns an error it always returns the same error, so that execution
+// cannot continue by ignoring it.
+func (rl *resultLimits) add(val any) error {
+	if rl.err != nil {
+		return rl.err
+	}
+
+	rl.results++
+	if rl.maxResults > 0 && rl.results > rl.maxResults {
+		rl.err = fmt.Errorf("%w: %w", ErrExecution, ErrLimit)
+		return rl.err
+	}
+
+	if rl.maxBytes > 0 {
+		rl.bytes += approxSize(val, rl.maxBytes-rl.bytes+1)
+		if rl.bytes > rl.maxBytes {
+			rl.err = fmt.Errorf("%w: %w", ErrExecution, ErrLimit)
+			return rl.err
+		}
+	}
+
+	return nil
+}
+
+// approxSize returns the approximate size of the JSON representation of
+// val. It stops counting once the size reaches limit, so that the cost of
+// checking the size of large objects and arrays is bounded by the limit.
+func approxSize(val any, limit int) int {
+	const (
+		nullSize     = len("null")
+		boolSize     = len("false")
+		numberSize   = 8
+		dateTimeSize = len(`"2006-01-02T15:04:05.999999999+00:00"`)
+		quoteSize    = 2 // ""
+		bracketSize  = 2 // [] or {}
+		memberSize   = 4 // "":,
+	)
+
+	switch val := val.(type) {
+	case nil:
+		return nullSize
+	case bool:
+		return boolSize
+	case int64, float64:
+		return numberSize
+	case json.Number:
+		return len(val)
+	case string:
+		return len(val) + quoteSize
+	case types.DateTime:
+		return dateTimeSize
+	case map[string]any:
+		size := bracketSize
+		for k, v := range val {
+			if size >= limit {
+				break
+			}
+			size += len(k) + memberSize + approxSize(v, limit-size)
+		}
+		return size
+	case []any:
+		size := bracketSize
+		for _, v := range val {
+			if size >= limit {
+				break
+			}
+			size += 1 + approxSize(v, limit-size)
+		}
+		return size
+	default:
+		// Unconverted Go value.
+		return numberSize
+	}
+}
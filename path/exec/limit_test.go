@@ -0,0 +1,250 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+	"github.com/theory/sqljson/path/types"
+)
+
+func TestResultLimits(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// Create a nested object with 10 arrays of 10 objects.
+	doc := map[string]any{}
+	for i := range 10 {
+		array := make([]any, 10)
+		for j := range array {
+			array[j] = map[string]any{"x": int64(j)}
+		}
+		doc[string(rune('a'+i))] = array
+	}
+
+	big := make([]any, DefaultParallelThreshold*2)
+	for i := range big {
+		big[i] = int64(i)
+	}
+
+	const limitErr = "exec: result limit exceeded"
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		json  any
+		opt   []Option
+		count int
+		err   bool
+	}{
+		{
+			name:  "no_limit",
+			path:  `$.**`,
+			json:  doc,
+			count: 211,
+		},
+		{
+			name: "max_results",
+			path: `$.**`,
+			json: doc,
+			opt:  []Option{WithMaxResults(100)},
+			err:  true,
+		},
+		{
+			name:  "max_results_exact",
+			path:  `$.*[*].x`,
+			json:  doc,
+			opt:   []Option{WithMaxResults(100)},
+			count: 100,
+		},
+		{
+			name: "max_results_one_over",
+			path: `$.*[*].x`,
+			json: doc,
+			opt:  []Option{WithMaxResults(99)},
+			err:  true,
+		},
+		{
+			name: "max_results_silent",
+			path: `$.**`,
+			json: doc,
+			opt:  []Option{WithMaxResults(10), WithSilent()},
+			err:  true,
+		},
+		{
+			name: "max_results_lax_unwrap",
+			path: `$.a.x`,
+			json: doc,
+			opt:  []Option{WithMaxResults(5)},
+			err:  true,
+		},
+		{
+			name:  "max_results_filter",
+			path:  `$.*[*] ? (@.x > 8)`,
+			json:  doc,
+			opt:   []Option{WithMaxResults(10)},
+			count: 10,
+		},
+		{
+			name: "max_results_parallel",
+			path: `$[*] ? (@ >= 0)`,
+			json: big,
+			opt:  []Option{WithMaxResults(100), WithParallel(4)},
+			err:  true,
+		},
+		{
+			name:  "max_results_parallel_ok",
+			path:  `$[*] ? (@ < 100)`,
+			json:  big,
+			opt:   []Option{WithMaxResults(100), WithParallel(4)},
+			count: 100,
+		},
+		{
+			name:  "max_results_zero",
+			path:  `$.**`,
+			json:  doc,
+			opt:   []Option{WithMaxResults(0)},
+			count: 211,
+		},
+		{
+			name: "max_bytes",
+			path: `$.**`,
+			json: doc,
+			opt:  []Option{WithMaxResultBytes(1000)},
+			err:  true,
+		},
+		{
+			name: "max_bytes_root",
+			path: `$`,
+			json: doc,
+			opt:  []Option{WithMaxResultBytes(100)},
+			err:  true,
+		},
+		{
+			name: "max_bytes_string",
+			path: `$`,
+			json: strings.Repeat("x", 100),
+			opt:  []Option{WithMaxResultBytes(100)},
+			err:  true,
+		},
+		{
+			name:  "max_bytes_ok",
+			path:  `$.a[0 to 1]`,
+			json:  doc,
+			opt:   []Option{WithMaxResultBytes(100)},
+			count: 2,
+		},
+		{
+			name:  "both_limits_ok",
+			path:  `$.*[0].x`,
+			json:  doc,
+			opt:   []Option{WithMaxResults(10), WithMaxResultBytes(80)},
+			count: 10,
+		},
+		{
+			name: "both_limits_bytes",
+			path: `$.*[0].x`,
+			json: doc,
+			opt:  []Option{WithMaxResults(10), WithMaxResultBytes(79)},
+			err:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := Query(ctx, path, tc.json, tc.opt...)
+			if tc.err {
+				r.EqualError(err, limitErr)
+				r.ErrorIs(err, ErrExecution)
+				r.ErrorIs(err, ErrLimit)
+				a.Nil(res)
+			} else {
+				r.NoError(err)
+				a.Len(res, tc.count)
+			}
+
+			// Exists and Match are unaffected.
+			_, err = Exists(ctx, path, tc.json, tc.opt...)
+			r.NoError(err)
+			pred, err := parser.Parse("exists(" + tc.path + ")")
+			r.NoError(err)
+			_, err = Match(ctx, pred, tc.json, tc.opt...)
+			r.NoError(err)
+		})
+	}
+
+	t.Run("functions", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse(`$.*[*]`)
+		r.NoError(err)
+		opt := WithMaxResults(50)
+
+		for name, fn := range map[string]func() (any, error){
+			"QueryArray": func() (any, error) { return QueryArray(ctx, path, doc, opt) },
+			"First":      func() (any, error) { return First(ctx, path, doc, opt) },
+			"FirstOrDefault": func() (any, error) {
+				return FirstOrDefault(ctx, path, doc, "x", opt)
+			},
+			"Keys":    func() (any, error) { return Keys(ctx, path, doc, opt) },
+			"Values":  func() (any, error) { return Values(ctx, path, doc, opt) },
+			"Explain": func() (any, error) { return Explain(ctx, path, doc, opt) },
+		} {
+			_, err := fn()
+			r.EqualError(err, limitErr, name)
+			a.ErrorIs(err, ErrLimit, name)
+		}
+
+		// Keys and Values limit the keys and values, too.
+		path, err = parser.Parse(`$.a[*]`)
+		r.NoError(err)
+		keys, err := Keys(ctx, path, doc, opt)
+		r.NoError(err)
+		a.Len(keys, 10)
+		_, err = Keys(ctx, path, doc, WithMaxResults(9))
+		r.EqualError(err, limitErr)
+		_, err = Values(ctx, path, doc, WithMaxResults(9))
+		r.EqualError(err, limitErr)
+	})
+}
+
+func TestApproxSize(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	ctx := types.ContextWithTZ(context.Background(), time.UTC)
+
+	for _, tc := range []struct {
+		name  string
+		val   any
+		limit int
+		exp   int
+	}{
+		{"null", nil, 100, 4},
+		{"true", true, 100, 5},
+		{"int", int64(42), 100, 8},
+		{"float", float64(98.6), 100, 8},
+		{"json_number", json.Number("12345.6789"), 100, 10},
+		{"string", "hello", 100, 7},
+		{"date", types.NewDate(time.Now()), 100, 37},
+		{"timestamptz", types.NewTimestampTZ(ctx, time.Now()), 100, 37},
+		{"empty_array", []any{}, 100, 2},
+		{"array", []any{"a", int64(1)}, 100, 2 + 4 + 9},
+		{"empty_object", map[string]any{}, 100, 2},
+		{"object", map[string]any{"a": "b"}, 100, 2 + 5 + 3},
+		{"nested", map[string]any{"a": []any{nil}}, 100, 2 + 5 + 2 + 5},
+		{"array_limit", []any{"xxxx", "xxxx", "xxxx", "xxxx"}, 10, 2 + 7 + 7},
+		{"go_value", []int{1, 2}, 100, 8},
+	} {
+		a.Equal(tc.exp, approxSize(tc.val, tc.limit), tc.name)
+	}
+}
@@ -3,6 +3,7 @@ package exec
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/theory/sqljson/path/ast"
 )
@@ -29,16 +30,17 @@ func (exec *Executor) execVariable(
 	node *ast.VariableNode,
 	found *valueList,
 ) (resultStatus, error) {
-	if val, ok := exec.vars[node.Text()]; ok {
+	if val, ok := exec.variable(node.Text()); ok {
 		// keyvalue ID 1 reserved for variables.
-		defer exec.setTempBaseObject(exec.vars, 1)()
+		defer exec.setTempBaseObject(1)()
+		defer exec.leave(exec.enter(locElem{kind: locVariable, name: node.Text()}))
 		return exec.executeNextItem(ctx, node, node.Next(), val, found)
 	}
 
 	// Return error for missing variable.
 	return statusFailed, fmt.Errorf(
 		"%w: could not find jsonpath variable %q",
-		ErrExecution, node.Text(),
+		ErrVariable, node.Text(),
 	)
 }
 
@@ -57,32 +59,98 @@ func (exec *Executor) execKeyNode(
 	key := node.Text()
 	switch value := value.(type) {
 	case map[string]any:
-		val, ok := value[key]
+		if exec.index != nil {
+			if last, val, count := exec.indexedKeys(node, value); count > 1 {
+				return exec.executeIndexedKeys(ctx, node, last, val, count, found)
+			}
+		}
+		val, stored, ok := exec.lookupKey(value, key)
 		if ok {
+			key = stored
+			defer exec.leave(exec.enterKey(key))
 			return exec.executeNextItem(ctx, node, nil, val, found)
 		}
 
 		if !exec.ignoreStructuralErrors {
+			if exec.missingAsNull {
+				return exec.executeMissingKey(ctx, node, found)
+			}
 			if !exec.verbose {
 				return statusFailed, nil
 			}
-
-			return statusFailed, fmt.Errorf(
-				`%w: JSON object does not contain key "%s"`,
-				ErrVerbose, key,
-			)
+			return statusFailed, missingKeyErr(key)
 		}
+		if exec.logSuppressed {
+			exec.suppressed(ctx, missingKeyErr(key))
+		}
+		return statusNotFound, nil
 	case []any:
 		if unwrap {
-			return exec.executeAnyItem(ctx, node, value, found, 1, 1, 1, false, false)
+			return exec.executeAnyItem(ctx, node, value, nil, found, 1, 1, 1, false, false)
 		}
 	}
 	if !exec.ignoreStructuralErrors {
-		return exec.returnVerboseError(fmt.Errorf(
-			"%w: jsonpath member accessor can only be applied to an object",
-			ErrVerbose,
-		))
+		return exec.returnVerboseError(memberAccessorTypeErr())
+	}
+	if exec.logSuppressed {
+		exec.suppressed(ctx, memberAccessorTypeErr())
 	}
 
 	return statusNotFound, nil
 }
+
+// missingKeyErr returns the error for an object that does not contain key.
+func missingKeyErr(key string) error {
+	return fmt.Errorf(
+		`%w: JSON object does not contain key "%s"`,
+		errVerboseStructural, key,
+	)
+}
+
+// memberAccessorTypeErr returns the error for a member accessor applied to
+// a value other than an object.
+func memberAccessorTypeErr() error {
+	return fmt.Errorf(
+		"%w: jsonpath member accessor can only be applied to an object",
+		errVerboseStructural,
+	)
+}
+
+// lookupKey returns the value of the member of obj named key, the key under
+// which obj stores it, and true if obj contains it. Implements
+// [WithCaseInsensitiveKeys]: if obj contains no key that equals key, it
+// selects the first in sorted order of the keys equal to key under Unicode
+// case folding.
+func (exec *Executor) lookupKey(obj map[string]any, key string) (any, string, bool) {
+	if val, ok := obj[key]; ok || !exec.caseInsensitiveKeys {
+		return val, key, ok
+	}
+
+	stored, ok := "", false
+	for k := range obj {
+		if strings.EqualFold(k, key) && (!ok || k < stored) {
+			stored, ok = k, true
+		}
+	}
+	if !ok {
+		return nil, key, false
+	}
+	return obj[stored], stored, true
+}
+
+// executeMissingKey implements [WithMissingAsNull] for node, whose key is
+// missing from the object it was applied to. It passes null to the node
+// following node and any key accessors that directly follow it, as though
+// they had selected null.
+func (exec *Executor) executeMissingKey(
+	ctx context.Context,
+	node *ast.KeyNode,
+	found *valueList,
+) (resultStatus, error) {
+	defer exec.leave(exec.enterKey(node.Text()))
+	for next, ok := node.Next().(*ast.KeyNode); ok; next, ok = next.Next().(*ast.KeyNode) {
+		node = next
+		exec.enterKey(node.Text())
+	}
+	return exec.executeNextItem(ctx, node, nil, nil, found)
+}
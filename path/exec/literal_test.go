@@ -88,9 +88,8 @@ func TestExecVariable(t *testing.T) {
 	path, _ := parser.Parse("$")
 	ctx := context.Background()
 
-	// Offset of object in a slice is non-determinate, so calculate it at runtime.
 	vars := Vars{"x": map[string]any{"y": "hi"}}
-	xID := 10000000000 + deltaBetween(vars, vars["x"])
+	xID := int64(10000000001)
 
 	for _, tc := range []struct {
 		name  string
@@ -309,3 +308,380 @@ func TestExecKeyNode(t *testing.T) {
 		})
 	}
 }
+
+func TestWithMissingAsNull(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	items := `{"items": [{"a": 2}, {"b": 1}, {"a": null}, {"a": {"b": 3}}]}`
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		json   string
+		exp    []any
+		err    string
+		exists bool
+	}{
+		{
+			name:   "missing_key",
+			path:   "strict $.a",
+			json:   `{"b": 1}`,
+			exp:    []any{nil},
+			exists: true,
+		},
+		{
+			name:   "present_key",
+			path:   "strict $.a",
+			json:   `{"a": 1}`,
+			exp:    []any{float64(1)},
+			exists: true,
+		},
+		{
+			name:   "wildcard",
+			path:   "strict $.items[*].a",
+			json:   items,
+			exp:    []any{float64(2), nil, nil, js(`{"b": 3}`)},
+			exists: true,
+		},
+		{
+			name:   "chained_after_missing",
+			path:   "strict $.x.y.z",
+			json:   `{"a": 1}`,
+			exp:    []any{nil},
+			exists: true,
+		},
+		{
+			name:   "chained_after_missing_nested",
+			path:   `strict $.a.x.y`,
+			json:   `{"a": {"b": 1}}`,
+			exp:    []any{nil},
+			exists: true,
+		},
+		{
+			name: "method_after_missing",
+			path: "strict $.x.size()",
+			json: `{"a": 1}`,
+			err:  "exec: jsonpath item method .size() can only be applied to an array",
+		},
+		{
+			name: "array_accessor_after_missing",
+			path: "strict $.x[0]",
+			json: `{"a": 1}`,
+			err:  "exec: jsonpath array accessor can only be applied to an array",
+		},
+		{
+			name: "accessor_on_explicit_null",
+			path: "strict $.a.b",
+			json: `{"a": null}`,
+			err:  "exec: jsonpath member accessor can only be applied to an object",
+		},
+		{
+			name: "accessor_on_array",
+			path: "strict $.a.b",
+			json: `{"a": [{"b": 1}]}`,
+			err:  "exec: jsonpath member accessor can only be applied to an object",
+		},
+		{
+			name:   "lax_unchanged",
+			path:   "lax $.a",
+			json:   `{"b": 1}`,
+			exp:    []any{},
+			exists: false,
+		},
+		{
+			name:   "any_unchanged",
+			path:   "strict $.**.a",
+			json:   `{"b": {"a": 1}}`,
+			exp:    []any{float64(1)},
+			exists: true,
+		},
+		{
+			name:   "filter_comparison",
+			path:   "strict $.items[*] ? (@.a > 1)",
+			json:   items,
+			exp:    []any{js(`{"a": 2}`)},
+			exists: true,
+		},
+		{
+			name:   "filter_not_equal",
+			path:   "strict $.items[*] ? (@.a != 2)",
+			json:   items,
+			exp:    []any{js(`{"b": 1}`), js(`{"a": null}`)},
+			exists: true,
+		},
+		{
+			name:   "filter_null",
+			path:   "strict $.items[*] ? (@.a == null)",
+			json:   items,
+			exp:    []any{js(`{"b": 1}`), js(`{"a": null}`)},
+			exists: true,
+		},
+		{
+			name:   "filter_chained",
+			path:   "strict $.items[*] ? (@.a.b == 3)",
+			json:   items,
+			exp:    []any{js(`{"a": {"b": 3}}`)},
+			exists: true,
+		},
+		{
+			name:   "filter_exists",
+			path:   "strict $.items[*] ? (exists(@.b))",
+			json:   items,
+			exp:    []any{js(`{"a": 2}`), js(`{"b": 1}`), js(`{"a": null}`), js(`{"a": {"b": 3}}`)},
+			exists: true,
+		},
+		{
+			name:   "filter_is_unknown",
+			path:   "strict $.items[*] ? ((@.a > 1) is unknown)",
+			json:   items,
+			exp:    []any{js(`{"a": {"b": 3}}`)},
+			exists: true,
+		},
+		{
+			name:   "filter_chained_is_unknown",
+			path:   "strict $.items[*] ? ((@.a.b > 1) is unknown)",
+			json:   items,
+			exp:    []any{js(`{"a": 2}`), js(`{"a": null}`)},
+			exists: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			value := js(tc.json)
+
+			res, err := Query(ctx, path, value, WithMissingAsNull())
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrVerbose)
+				a.Nil(res)
+				return
+			}
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+
+			ok, err := Exists(ctx, path, value, WithMissingAsNull())
+			r.NoError(err)
+			a.Equal(tc.exists, ok)
+		})
+	}
+
+	t.Run("predicate", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		value := js(`{"b": 1}`)
+
+		// Without the option, the missing key makes the predicate unknown.
+		path, err := parser.Parse("strict $.a == null")
+		r.NoError(err)
+		res, err := MatchTri(ctx, path, value)
+		r.NoError(err)
+		a.Equal(Null, res)
+		res, err = MatchTri(ctx, path, value, WithMissingAsNull())
+		r.NoError(err)
+		a.Equal(True, res)
+
+		path, err = parser.Parse("strict ($.a > 1) is unknown")
+		r.NoError(err)
+		res, err = MatchTri(ctx, path, value)
+		r.NoError(err)
+		a.Equal(True, res)
+		res, err = MatchTri(ctx, path, value, WithMissingAsNull())
+		r.NoError(err)
+		a.Equal(False, res)
+	})
+}
+
+func TestWithCaseInsensitiveKeys(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	users := `{"users": [{"UserId": 1}, {"userid": 2}, {"name": 3}, {"userId": 4, "USERID": 5}]}`
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		json   string
+		opt    []Option
+		exp    []any
+		err    string
+		exists bool
+	}{
+		{
+			name:   "exact",
+			path:   "$.userId",
+			json:   `{"userId": 1}`,
+			exp:    []any{float64(1)},
+			exists: true,
+		},
+		{
+			name:   "case_differs",
+			path:   "$.userid",
+			json:   `{"UserId": 1}`,
+			exp:    []any{float64(1)},
+			exists: true,
+		},
+		{
+			name:   "quoted",
+			path:   `$."USERID"`,
+			json:   `{"UserId": 1}`,
+			exp:    []any{float64(1)},
+			exists: true,
+		},
+		{
+			name:   "exact_wins",
+			path:   "$.userId",
+			json:   `{"UserId": 1, "userId": 2, "USERID": 3}`,
+			exp:    []any{float64(2)},
+			exists: true,
+		},
+		{
+			name:   "first_sorted_wins",
+			path:   "$.userid",
+			json:   `{"userId": 1, "UserId": 2, "USERID": 3, "UserID": 4}`,
+			exp:    []any{float64(3)},
+			exists: true,
+		},
+		{
+			name:   "nested",
+			path:   "$.user.name",
+			json:   `{"User": {"NAME": "x"}}`,
+			exp:    []any{"x"},
+			exists: true,
+		},
+		{
+			name:   "unicode",
+			path:   `$."ÉTÉ"`,
+			json:   `{"été": 1}`,
+			exp:    []any{float64(1)},
+			exists: true,
+		},
+		{
+			name:   "array_unwrap",
+			path:   "$.users.userid",
+			json:   users,
+			exp:    []any{float64(1), float64(2), float64(5)},
+			exists: true,
+		},
+		{
+			name:   "wildcard_unaffected",
+			path:   "$.*",
+			json:   `{"b": 2, "A": 1}`,
+			opt:    []Option{WithOrderedKeys()},
+			exp:    []any{float64(1), float64(2)},
+			exists: true,
+		},
+		{
+			name:   "keyvalue_stored_keys",
+			path:   "$.user.keyvalue().key",
+			json:   `{"User": {"Name": "x"}}`,
+			exp:    []any{"Name"},
+			exists: true,
+		},
+		{
+			name:   "keyvalue_key_accessor",
+			path:   "$.keyvalue().KEY",
+			json:   `{"UserId": 1}`,
+			exp:    []any{"UserId"},
+			exists: true,
+		},
+		{
+			name:   "lax_missing",
+			path:   "$.userid",
+			json:   `{"name": 1}`,
+			exp:    []any{},
+			exists: false,
+		},
+		{
+			name: "strict_missing",
+			path: "strict $.userid",
+			json: `{"name": 1}`,
+			err:  `exec: JSON object does not contain key "userid"`,
+		},
+		{
+			name:   "strict_case_differs",
+			path:   "strict $.userid",
+			json:   `{"UserId": 1}`,
+			exp:    []any{float64(1)},
+			exists: true,
+		},
+		{
+			name:   "strict_missing_as_null",
+			path:   "strict $.userid",
+			json:   `{"name": 1}`,
+			opt:    []Option{WithMissingAsNull()},
+			exp:    []any{nil},
+			exists: true,
+		},
+		{
+			name:   "strict_filter_exists",
+			path:   "strict $.users[*] ? (exists(@.userid))",
+			json:   users,
+			exp:    []any{js(`{"UserId": 1}`), js(`{"userid": 2}`), js(`{"userId": 4, "USERID": 5}`)},
+			exists: true,
+		},
+		{
+			name:   "strict_filter_unknown",
+			path:   "strict $.users[*] ? ((@.userid > 0) is unknown)",
+			json:   users,
+			exp:    []any{js(`{"name": 3}`)},
+			exists: true,
+		},
+		{
+			name:   "lax_filter_exists",
+			path:   "$.users[*] ? (exists(@.NAME))",
+			json:   users,
+			exp:    []any{js(`{"name": 3}`)},
+			exists: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			value := js(tc.json)
+			opt := append([]Option{WithCaseInsensitiveKeys()}, tc.opt...)
+
+			res, err := Query(ctx, path, value, opt...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrVerbose)
+				a.Nil(res)
+				return
+			}
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+
+			ok, err := Exists(ctx, path, value, opt...)
+			r.NoError(err)
+			a.Equal(tc.exists, ok)
+		})
+	}
+
+	t.Run("off_by_default", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse("$.userid")
+		r.NoError(err)
+		res, err := Query(ctx, path, js(`{"UserId": 1}`))
+		r.NoError(err)
+		a.Equal([]any{}, res)
+	})
+
+	t.Run("locations", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse("$.user.name")
+		r.NoError(err)
+		res, err := QueryLocations(ctx, path, js(`{"User": {"NAME": "x"}}`), WithCaseInsensitiveKeys())
+		r.NoError(err)
+		a.Equal([]Located{{Value: "x", Path: `$."User"."NAME"`, Pointer: "/User/NAME"}}, res)
+	})
+}
@@ -0,0 +1,205 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/theory/sqljson/path/ast"
+)
+
+// Located is a JSON item selected by [QueryLocations] and its location.
+type Located struct {
+	// Value is the selected item.
+	Value any
+
+	// Path is the normalized path to the item, such as $."a"[3]."b",
+	// starting from the root of the queried value or, for an item selected
+	// from a variable, from the variable.
+	Path string
+
+	// Pointer is the RFC 6901 JSON Pointer to the item, such as /a/3/b,
+	// relative to the same root or variable as Path.
+	Pointer string
+}
+
+// QueryLocations is like [Query], but returns the location of each selected
+// item along with its value. Accessors, including wildcards and .**, and
+// filters report the locations of the items they select. Items computed by
+// item methods, such as .size(), report the location of the item the method
+// was applied to, and those computed by operators and literals report the
+// location of the current item, $ at the top level. Objects generated by
+// .keyvalue(), and items selected from them, report the location of the
+// object passed to .keyvalue(). Other query functions do not format
+// locations.
+func QueryLocations(ctx context.Context, path *ast.AST, value any, opt ...Option) ([]Located, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.release()
+	if err := exec.checkPredicate("QueryLocations", false); err != nil {
+		return nil, err
+	}
+
+	vals := exec.newResultList()
+	vals.locator = exec
+	vals.locs = []Located{}
+	if _, err := exec.execute(ctx, value, vals); err != nil {
+		return nil, err
+	}
+	for i, loc := range vals.locs {
+		val, err := exec.result(loc.Value)
+		if err != nil {
+			return nil, err
+		}
+		vals.locs[i].Value = val
+	}
+	return vals.locs, nil
+}
+
+// locKind identifies the kind of a locElem.
+type locKind uint8
+
+const (
+	locRoot locKind = iota
+	locVariable
+	locKey
+	locIndex
+	// locSynthetic marks an object generated by .keyvalue(), which has no
+	// location of its own. Items selected from it take the location of the
+	// object passed to .keyvalue().
+	locSynthetic
+)
+
+// locElem is an element of the location of the item being evaluated: the
+// root, a variable, an object key, an array index, or a generated object.
+type locElem struct {
+	kind  locKind
+	name  string
+	index int
+}
+
+// enter appends elem to the location of the item being evaluated and
+// returns the previous length of the location, to be passed to leave.
+func (exec *Executor) enter(elem locElem) int {
+	size := len(exec.location)
+	exec.location = append(exec.location, elem)
+	return size
+}
+
+// enterKey is shorthand for entering the object member key.
+func (exec *Executor) enterKey(key string) int {
+	return exec.enter(locElem{kind: locKey, name: key})
+}
+
+// enterIndex is shorthand for entering the array element at index.
+func (exec *Executor) enterIndex(index int) int {
+	return exec.enter(locElem{kind: locIndex, index: index})
+}
+
+// leave restores the location to size elements.
+func (exec *Executor) leave(size int) {
+	exec.location = exec.location[:size]
+}
+
+// locationElems returns the elements of the location of the item being
+// evaluated, starting from the most recently entered root or variable and
+// ending before the first generated object.
+func (exec *Executor) locationElems() []locElem {
+	start := 0
+	for i := len(exec.location) - 1; i >= 0; i-- {
+		if kind := exec.location[i].kind; kind == locRoot || kind == locVariable {
+			start = i
+			break
+		}
+	}
+
+	elems := exec.location[start:]
+	for i, elem := range elems {
+		if elem.kind == locSynthetic {
+			return elems[:i]
+		}
+	}
+	return elems
+}
+
+// locationString formats the location of the item being evaluated as a
+// normalized path, starting from the most recently entered root or
+// variable.
+func (exec *Executor) locationString() string {
+	return formatLocation(exec.locationElems())
+}
+
+// formatLocation formats elems, as returned by locationElems, as a
+// normalized path. Returns "$" if elems is empty.
+func formatLocation(elems []locElem) string {
+	var buf strings.Builder
+	if len(elems) == 0 {
+		buf.WriteByte('$')
+	}
+	for _, elem := range elems {
+		switch elem.kind {
+		case locRoot:
+			buf.WriteByte('$')
+		case locVariable:
+			buf.WriteString(ast.NewVariable(elem.name).String())
+		case locKey:
+			buf.WriteByte('.')
+			buf.WriteString(ast.NewKey(elem.name).String())
+		case locIndex:
+			buf.WriteByte('[')
+			buf.WriteString(strconv.Itoa(elem.index))
+			buf.WriteByte(']')
+		case locSynthetic:
+		}
+	}
+	return buf.String()
+}
+
+// locationPointer formats the location of the item being evaluated as an
+// RFC 6901 JSON Pointer relative to the most recently entered root or
+// variable.
+func (exec *Executor) locationPointer() string {
+	var buf strings.Builder
+	for _, elem := range exec.locationElems() {
+		switch elem.kind {
+		case locKey:
+			buf.WriteByte('/')
+			name := strings.ReplaceAll(elem.name, "~", "~0")
+			buf.WriteString(strings.ReplaceAll(name, "/", "~1"))
+		case locIndex:
+			buf.WriteByte('/')
+			buf.WriteString(strconv.Itoa(elem.index))
+		case locRoot, locVariable, locSynthetic:
+		}
+	}
+	return buf.String()
+}
+
+// located returns val and the location of the item being evaluated.
+func (exec *Executor) located(val any) Located {
+	return Located{Value: val, Path: exec.locationString(), Pointer: exec.locationPointer()}
+}
+
+// locate wraps err in an [Error] that records the location of the item
+// being evaluated, unless err is not an [ErrExecution] error or already
+// records its location. Every node through which err propagates calls
+// locate, so it returns an [Error] from a deeper node without further
+// checks, and copies the location elements rather than formatting them,
+// since predicates and silent mode discard most errors.
+func (exec *Executor) locate(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*Error); ok { //nolint:errorlint // errors.As below handles wrapped errors
+		return err
+	}
+	var located *Error
+	if !errors.Is(err, ErrExecution) || errors.As(err, &located) {
+		return err
+	}
+	return &Error{err: err, elems: slices.Clone(exec.locationElems())}
+}
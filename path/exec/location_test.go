@@ -0,0 +1,294 @@
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestQueryLocations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	large := make([]any, DefaultParallelThreshold)
+	for i := range large {
+		large[i] = map[string]any{"n": int64(i)}
+	}
+	doc := map[string]any{
+		"a": []any{int64(1), int64(2), map[string]any{"b": "x"}},
+		"c": map[string]any{"d": true},
+	}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opt   []Option
+		exp   []Located
+		rand  bool
+		err   string
+	}{
+		{
+			name:  "root",
+			path:  "$",
+			value: int64(1),
+			exp:   []Located{{Value: int64(1), Path: "$", Pointer: ""}},
+		},
+		{
+			name:  "not_found",
+			path:  "$.x",
+			value: doc,
+			exp:   []Located{},
+		},
+		{
+			name:  "key_and_index",
+			path:  "$.a[2].b",
+			value: doc,
+			exp:   []Located{{Value: "x", Path: `$."a"[2]."b"`, Pointer: "/a/2/b"}},
+		},
+		{
+			name:  "array_wildcard",
+			path:  "$.a[*]",
+			value: doc,
+			exp: []Located{
+				{Value: int64(1), Path: `$."a"[0]`, Pointer: "/a/0"},
+				{Value: int64(2), Path: `$."a"[1]`, Pointer: "/a/1"},
+				{Value: map[string]any{"b": "x"}, Path: `$."a"[2]`, Pointer: "/a/2"},
+			},
+		},
+		{
+			name:  "array_slice",
+			path:  "$.a[1 to last]",
+			value: doc,
+			exp: []Located{
+				{Value: int64(2), Path: `$."a"[1]`, Pointer: "/a/1"},
+				{Value: map[string]any{"b": "x"}, Path: `$."a"[2]`, Pointer: "/a/2"},
+			},
+		},
+		{
+			name:  "lax_unwrap",
+			path:  "$.a.b",
+			value: doc,
+			exp:   []Located{{Value: "x", Path: `$."a"[2]."b"`, Pointer: "/a/2/b"}},
+		},
+		{
+			name:  "lax_wrap",
+			path:  "$.c[0].d",
+			value: doc,
+			exp:   []Located{{Value: true, Path: `$."c"."d"`, Pointer: "/c/d"}},
+		},
+		{
+			name:  "object_wildcard",
+			path:  "$.*",
+			value: doc,
+			rand:  true,
+			exp: []Located{
+				{Value: doc["a"], Path: `$."a"`, Pointer: "/a"},
+				{Value: doc["c"], Path: `$."c"`, Pointer: "/c"},
+			},
+		},
+		{
+			name:  "filter",
+			path:  "$.a[*] ? (@ > 1 || @.b == \"x\")",
+			value: doc,
+			exp: []Located{
+				{Value: int64(2), Path: `$."a"[1]`, Pointer: "/a/1"},
+				{Value: map[string]any{"b": "x"}, Path: `$."a"[2]`, Pointer: "/a/2"},
+			},
+		},
+		{
+			name:  "filter_then_accessor",
+			path:  "$.a ? (@.b == \"x\").b",
+			value: doc,
+			exp:   []Located{{Value: "x", Path: `$."a"[2]."b"`, Pointer: "/a/2/b"}},
+		},
+		{
+			name:  "any",
+			path:  "$.**{2 to last}",
+			value: doc,
+			rand:  true,
+			exp: []Located{
+				{Value: int64(1), Path: `$."a"[0]`, Pointer: "/a/0"},
+				{Value: int64(2), Path: `$."a"[1]`, Pointer: "/a/1"},
+				{Value: map[string]any{"b": "x"}, Path: `$."a"[2]`, Pointer: "/a/2"},
+				{Value: "x", Path: `$."a"[2]."b"`, Pointer: "/a/2/b"},
+				{Value: true, Path: `$."c"."d"`, Pointer: "/c/d"},
+			},
+		},
+		{
+			name:  "any_filter",
+			path:  `strict $.** ? (@ == "x")`,
+			value: doc,
+			exp:   []Located{{Value: "x", Path: `$."a"[2]."b"`, Pointer: "/a/2/b"}},
+		},
+		{
+			name:  "method",
+			path:  "$.a.size()",
+			value: doc,
+			exp:   []Located{{Value: int64(3), Path: `$."a"`, Pointer: "/a"}},
+		},
+		{
+			name:  "method_unwrap",
+			path:  "$.a[0 to 1].double()",
+			value: doc,
+			exp: []Located{
+				{Value: float64(1), Path: `$."a"[0]`, Pointer: "/a/0"},
+				{Value: float64(2), Path: `$."a"[1]`, Pointer: "/a/1"},
+			},
+		},
+		{
+			name:  "arithmetic",
+			path:  "$.a[0] + 1",
+			value: doc,
+			exp:   []Located{{Value: int64(2), Path: "$", Pointer: ""}},
+		},
+		{
+			name:  "arithmetic_in_filter",
+			path:  "$.a[*] ? (@ == 2)",
+			value: doc,
+			exp:   []Located{{Value: int64(2), Path: `$."a"[1]`, Pointer: "/a/1"}},
+		},
+		{
+			name:  "literal",
+			path:  `"hi"`,
+			value: doc,
+			exp:   []Located{{Value: "hi", Path: "$", Pointer: ""}},
+		},
+		{
+			name:  "keyvalue",
+			path:  "$.c.keyvalue().key",
+			value: doc,
+			exp:   []Located{{Value: "d", Path: `$."c"`, Pointer: "/c"}},
+		},
+		{
+			name:  "keyvalue_value",
+			path:  "$.a[2].keyvalue().value",
+			value: doc,
+			exp:   []Located{{Value: "x", Path: `$."a"[2]`, Pointer: "/a/2"}},
+		},
+		{
+			name:  "keyvalue_filter",
+			path:  `$.c.keyvalue() ? (@.key == "d").value`,
+			value: doc,
+			exp:   []Located{{Value: true, Path: `$."c"`, Pointer: "/c"}},
+		},
+		{
+			name:  "keyvalue_then_accessor",
+			path:  "$.keyvalue().value.d",
+			value: map[string]any{"c": doc["c"]},
+			exp:   []Located{{Value: true, Path: "$", Pointer: ""}},
+		},
+		{
+			name:  "variable",
+			path:  "$x.y[1]",
+			value: doc,
+			opt:   []Option{WithVars(Vars{"x": map[string]any{"y": []any{"a", "b"}}})},
+			exp:   []Located{{Value: "b", Path: `$"x"."y"[1]`, Pointer: "/y/1"}},
+		},
+		{
+			name:  "variable_in_filter",
+			path:  "$.a[*] ? (@ == $x)",
+			value: doc,
+			opt:   []Option{WithVars(Vars{"x": int64(1)})},
+			exp:   []Located{{Value: int64(1), Path: `$."a"[0]`, Pointer: "/a/0"}},
+		},
+		{
+			name: "pointer_escapes",
+			path: `$."a/b"."c~d"."e\"f"`,
+			value: map[string]any{"a/b": map[string]any{"c~d": map[string]any{
+				`e"f`: int64(1),
+			}}},
+			exp: []Located{{Value: int64(1), Path: `$."a/b"."c~d"."e\"f"`, Pointer: `/a~1b/c~0d/e"f`}},
+		},
+		{
+			name:  "parallel",
+			path:  "$[*] ? (@.n > 4093).n",
+			value: large,
+			opt:   []Option{WithParallel(2)},
+			exp: []Located{
+				{Value: int64(4094), Path: `$[4094]."n"`, Pointer: "/4094/n"},
+				{Value: int64(4095), Path: `$[4095]."n"`, Pointer: "/4095/n"},
+			},
+		},
+		{
+			name:  "copy_results",
+			path:  "$.c",
+			value: doc,
+			opt:   []Option{WithCopyResults()},
+			exp:   []Located{{Value: doc["c"], Path: `$."c"`, Pointer: "/c"}},
+		},
+		{
+			name:  "strict_error",
+			path:  "strict $.a[*].b",
+			value: doc,
+			err:   "exec: jsonpath member accessor can only be applied to an object",
+		},
+		{
+			name:  "silent",
+			path:  "strict $.a[*].b",
+			value: doc,
+			opt:   []Option{WithSilent()},
+			exp:   []Located{},
+		},
+		{
+			name:  "limit",
+			path:  "$.a[*]",
+			value: doc,
+			opt:   []Option{WithMaxResults(2)},
+			err:   "exec: result limit exceeded",
+		},
+		{
+			name:  "predicate_check",
+			path:  "$ == 1",
+			value: int64(1),
+			opt:   []Option{WithPredicateCheck()},
+			err:   `exec: QueryLocations expects a SQL standard path expression but "($ == 1)" is a predicate check expression`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := QueryLocations(ctx, path, tc.value, tc.opt...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(res)
+				return
+			}
+			r.NoError(err)
+			if tc.rand {
+				a.ElementsMatch(tc.exp, res)
+			} else {
+				a.Equal(tc.exp, res)
+			}
+
+			// Values should match those returned by Query.
+			vals, err := Query(ctx, path, tc.value, tc.opt...)
+			r.NoError(err)
+			r.Len(res, len(vals))
+			if !tc.rand {
+				for i, loc := range res {
+					a.Equal(vals[i], loc.Value)
+				}
+			}
+
+			// Each located item should be selected by its path.
+			for _, loc := range res {
+				if loc.Path == "$" {
+					continue
+				}
+				locPath, err := parser.Parse("strict " + loc.Path)
+				r.NoError(err)
+				got, err := Query(ctx, locPath, tc.value, tc.opt...)
+				r.NoError(err)
+				r.Len(got, 1)
+			}
+		})
+	}
+}
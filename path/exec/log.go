@@ -0,0 +1,79 @@
+package exec
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WarningCode identifies the kind of a record logged to the logger
+// specified by [WithLogger].
+type WarningCode string
+
+const (
+	// WarnPrecisionReduced indicates that a time or timestamp item method
+	// reduced a precision greater than 6 to 6, as PostgreSQL does.
+	WarnPrecisionReduced WarningCode = "precision_reduced"
+
+	// WarnErrorSuppressed indicates a structural error suppressed in lax
+	// mode, such as a missing key or an array subscript out of bounds.
+	// Logged only with [WithLogSuppressed].
+	WarnErrorSuppressed WarningCode = "error_suppressed"
+)
+
+// WithLogger specifies a logger to which execution logs warnings, such as
+// when the .time() method reduces a precision greater than 6 to 6, at
+// [slog.LevelWarn]. Each record has the attributes "code", the
+// [WarningCode] identifying the warning, and "path", the normalized path to
+// the item being evaluated, as returned by [Error.Path]. Warnings are also
+// passed to the function specified by [WithWarningHandler]. Calls to the
+// logger never overlap, even with [WithParallel], though its records may
+// then arrive out of array order. Warnings are not logged by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(e *Executor) { e.logger = logger }
+}
+
+// WithLogSuppressed makes execution log the structural errors that lax
+// mode and the .** accessor suppress, such as missing keys, array
+// subscripts out of bounds, and accessors applied to the wrong type, to
+// the logger specified by [WithLogger] at [slog.LevelDebug], with the code
+// [WarnErrorSuppressed]. Useful for debugging paths that select nothing.
+func WithLogSuppressed() Option {
+	return func(e *Executor) { e.logSuppressed = true }
+}
+
+// warning passes msg to exec.warn, if it's not nil, and logs it with code
+// to exec.logger, if it's not nil.
+func (exec *Executor) warning(ctx context.Context, code WarningCode, msg string) {
+	if exec.callbackMu != nil {
+		exec.callbackMu.Lock()
+		defer exec.callbackMu.Unlock()
+	}
+	if exec.warn != nil {
+		exec.warn(msg)
+	}
+	exec.log(ctx, slog.LevelWarn, code, msg)
+}
+
+// suppressed logs err, a structural error suppressed because
+// exec.ignoreStructuralErrors is true, when exec.logSuppressed is true.
+// Callers should check exec.logSuppressed before constructing err.
+func (exec *Executor) suppressed(ctx context.Context, err error) {
+	if exec.callbackMu != nil {
+		exec.callbackMu.Lock()
+		defer exec.callbackMu.Unlock()
+	}
+	exec.log(ctx, slog.LevelDebug, WarnErrorSuppressed, err.Error())
+}
+
+// log logs msg at level with code and the location of the item being
+// evaluated to exec.logger, if it's not nil.
+func (exec *Executor) log(ctx context.Context, level slog.Level, code WarningCode, msg string) {
+	if exec.logger == nil || !exec.logger.Enabled(ctx, level) {
+		return
+	}
+	exec.logger.LogAttrs(
+		ctx, level, msg,
+		slog.String("code", string(code)),
+		slog.String("path", exec.locationString()),
+	)
+}
@@ -0,0 +1,181 @@
+package exec
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+)
+
+// logRecord is the level and attributes of a record logged by the
+// executor.
+type logRecord struct {
+	level slog.Level
+	code  string
+	path  string
+}
+
+// recordHandler is a slog.Handler that records the records it handles at
+// or above level.
+type recordHandler struct {
+	mu      sync.Mutex
+	level   slog.Level
+	records []logRecord
+}
+
+func (h *recordHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	rec := logRecord{level: r.Level}
+	r.Attrs(func(attr slog.Attr) bool {
+		switch attr.Key {
+		case "code":
+			rec.code = attr.Value.String()
+		case "path":
+			rec.path = attr.Value.String()
+		}
+		return true
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, rec)
+	return nil
+}
+
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	suppressed := string(WarnErrorSuppressed)
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		json  string
+		quiet bool
+		opts  []Option
+		exp   []logRecord
+	}{
+		{
+			name: "no_warnings",
+			path: `$.a`,
+			json: `{"a": 1}`,
+		},
+		{
+			name: "precision_reduced",
+			path: `$.a.time(9)`,
+			json: `{"a": "12:34:56"}`,
+			exp: []logRecord{
+				{level: slog.LevelWarn, code: string(WarnPrecisionReduced), path: `$."a"`},
+			},
+		},
+		{
+			name: "suppressed_not_logged",
+			path: `$.a.b`,
+			json: `{"a": {}}`,
+			exp:  nil,
+		},
+		{
+			name:  "suppressed_level_disabled",
+			path:  `$.a.b`,
+			json:  `{"a": {}}`,
+			quiet: true,
+			opts:  []Option{WithLogSuppressed()},
+			exp:   nil,
+		},
+		{
+			name: "missing_key",
+			path: `$.a.b`,
+			json: `{"a": {}}`,
+			opts: []Option{WithLogSuppressed()},
+			exp:  []logRecord{{level: slog.LevelDebug, code: suppressed, path: `$."a"`}},
+		},
+		{
+			name: "member_accessor",
+			path: `$.a.b`,
+			json: `{"a": 1}`,
+			opts: []Option{WithLogSuppressed()},
+			exp:  []logRecord{{level: slog.LevelDebug, code: suppressed, path: `$."a"`}},
+		},
+		{
+			name: "wildcard_member",
+			path: `$.a.*`,
+			json: `{"a": 1}`,
+			opts: []Option{WithLogSuppressed()},
+			exp:  []logRecord{{level: slog.LevelDebug, code: suppressed, path: `$."a"`}},
+		},
+		{
+			name: "subscript_out_of_bounds",
+			path: `$.a[1 to 5]`,
+			json: `{"a": [1, 2]}`,
+			opts: []Option{WithLogSuppressed()},
+			exp:  []logRecord{{level: slog.LevelDebug, code: suppressed, path: `$."a"`}},
+		},
+		{
+			name: "any",
+			path: `strict $.**.b`,
+			json: `{"a": 1, "b": 2}`,
+			opts: []Option{WithLogSuppressed(), WithOrderedKeys()},
+			exp: []logRecord{
+				{level: slog.LevelDebug, code: suppressed, path: `$."a"`},
+				{level: slog.LevelDebug, code: suppressed, path: `$."b"`},
+			},
+		},
+		{
+			name: "strict_not_suppressed",
+			path: `strict $.a ? (@.b == 1)`,
+			json: `{"a": {}}`,
+			opts: []Option{WithLogSuppressed()},
+			exp:  nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			handler := &recordHandler{level: slog.LevelDebug}
+			if tc.quiet {
+				handler.level = slog.LevelInfo
+			}
+			opts := append([]Option{WithLogger(slog.New(handler))}, tc.opts...)
+			_, err = Query(ctx, path, js(tc.json), opts...)
+			r.NoError(err)
+			a.Equal(tc.exp, handler.records)
+
+			// No logger, no records.
+			_, err = Query(ctx, path, js(tc.json), tc.opts...)
+			r.NoError(err)
+		})
+	}
+
+	t.Run("warning_handler", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse(`$.time(7)`)
+		r.NoError(err)
+
+		// Both the logger and the warning handler receive warnings.
+		handler := &recordHandler{level: slog.LevelDebug}
+		var warnings []string
+		_, err = Query(
+			ctx, path, "12:34:56",
+			WithLogger(slog.New(handler)),
+			WithWarningHandler(func(msg string) { warnings = append(warnings, msg) }),
+		)
+		r.NoError(err)
+		a.Equal([]logRecord{{level: slog.LevelWarn, code: string(WarnPrecisionReduced), path: "$"}}, handler.records)
+		a.Equal([]string{"TIME(7) precision reduced to maximum allowed, 6"}, warnings)
+	})
+}
@@ -5,13 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
+	"strconv"
 
 	"github.com/theory/sqljson/path/ast"
 )
 
 // executeIntegerMath compares lhs to rhs using op and returns the resulting
 // value. op must be a binary math operator. Returns an error for an attempt
-// to divide by zero.
+// to divide by zero and for math.MinInt64 / -1, which executeIntegerOp
+// returns exactly.
 func executeIntegerMath(lhs, rhs int64, op ast.BinaryOperator) (int64, error) {
 	switch op {
 	case ast.BinaryAdd:
@@ -22,13 +25,18 @@ func executeIntegerMath(lhs, rhs int64, op ast.BinaryOperator) (int64, error) {
 		return lhs * rhs, nil
 	case ast.BinaryDiv:
 		if rhs == 0 {
-			return 0, fmt.Errorf("%w: division by zero", ErrVerbose)
+			return 0, fmt.Errorf("%w: division by zero", errVerboseNumeric)
+		}
+		if lhs == math.MinInt64 && rhs == -1 {
+			return 0, fmt.Errorf("%w: value overflows numeric format", errVerboseNumeric)
 		}
 		return lhs / rhs, nil
 	case ast.BinaryMod:
 		if rhs == 0 {
-			return 0, fmt.Errorf("%w: division by zero", ErrVerbose)
+			return 0, fmt.Errorf("%w: division by zero", errVerboseNumeric)
 		}
+		// Go's % truncates toward zero, so that the result has the sign of
+		// lhs as in PostgreSQL, and MinInt64 % -1 is 0.
 		return lhs % rhs, nil
 	default:
 		// We process only the binary math operators here.
@@ -36,31 +44,183 @@ func executeIntegerMath(lhs, rhs int64, op ast.BinaryOperator) (int64, error) {
 	}
 }
 
-// executeIntegerMath compares lhs to rhs using op and returns the resulting
-// value. op must be a binary math operator. Returns an error for an attempt
-// to divide by zero.
-func executeFloatMath(lhs, rhs float64, op ast.BinaryOperator) (float64, error) {
+// executeIntegerOp applies op to lhs and rhs. Like PostgreSQL, which
+// computes with numeric values, it returns the quotient of a division with a
+// remainder as the float64 nearest the exact quotient, rather than
+// truncating it, and a sum, difference, product, or quotient outside the
+// range of int64 as a [json.Number] with all of its digits, rather than
+// wrapping it. Defers to executeIntegerMath otherwise.
+func executeIntegerOp(lhs, rhs int64, op ast.BinaryOperator) (any, error) {
+	switch op {
+	case ast.BinaryDiv:
+		if rhs != 0 && lhs%rhs != 0 {
+			return executeDecimalMath(new(big.Rat).SetInt64(lhs), new(big.Rat).SetInt64(rhs), op)
+		}
+		if integerOverflows(lhs, rhs, op) {
+			return executeBigIntegerMath(lhs, rhs, op), nil
+		}
+	case ast.BinaryAdd, ast.BinarySub, ast.BinaryMul:
+		if integerOverflows(lhs, rhs, op) {
+			return executeBigIntegerMath(lhs, rhs, op), nil
+		}
+	default:
+	}
+	res, err := executeIntegerMath(lhs, rhs, op)
+	return res, err
+}
+
+// integerOverflows returns true if adding, subtracting, multiplying, or
+// dividing lhs and rhs, as determined by op, overflows int64.
+func integerOverflows(lhs, rhs int64, op ast.BinaryOperator) bool {
 	switch op {
 	case ast.BinaryAdd:
-		return lhs + rhs, nil
+		return (lhs+rhs > lhs) != (rhs > 0)
 	case ast.BinarySub:
-		return lhs - rhs, nil
+		return (lhs-rhs < lhs) != (rhs > 0)
 	case ast.BinaryMul:
-		return lhs * rhs, nil
+		if lhs == 0 || rhs == 0 {
+			return false
+		}
+		// Go defines MinInt64 / -1 as MinInt64, so check it separately.
+		return (lhs*rhs)/rhs != lhs || (lhs == math.MinInt64 && rhs == -1)
 	case ast.BinaryDiv:
+		return lhs == math.MinInt64 && rhs == -1
+	default:
+		return false
+	}
+}
+
+// executeBigIntegerMath adds, subtracts, multiplies, or divides lhs and rhs,
+// as determined by op, without overflow, and returns the exact result as a
+// [json.Number]. Division must have no remainder.
+func executeBigIntegerMath(lhs, rhs int64, op ast.BinaryOperator) json.Number {
+	res, right := big.NewInt(lhs), big.NewInt(rhs)
+	switch op {
+	case ast.BinaryAdd:
+		res.Add(res, right)
+	case ast.BinarySub:
+		res.Sub(res, right)
+	case ast.BinaryMul:
+		res.Mul(res, right)
+	case ast.BinaryDiv:
+		res.Quo(res, right)
+	default:
+	}
+	return json.Number(res.String())
+}
+
+// executeFloatMath compares lhs to rhs using op and returns the resulting
+// value. op must be a binary math operator. Like PostgreSQL, which computes
+// with numeric values, it applies op to finite operands as the decimal
+// numbers they represent, so that 0.1 + 0.2 is 0.3 rather than
+// 0.30000000000000004. Returns an error for an attempt to divide by zero or
+// for a result that is NaN or Infinity, which JSON cannot represent, and
+// returns positive zero for a negative zero result.
+func executeFloatMath(lhs, rhs float64, op ast.BinaryOperator) (float64, error) {
+	if isFinite(lhs) && isFinite(rhs) && (!isExactInteger(lhs) || !isExactInteger(rhs)) {
+		return executeDecimalMath(floatRat(lhs), floatRat(rhs), op)
+	}
+
+	// float64 arithmetic on integers that it represents exactly rounds to
+	// the float64 nearest the exact result, just like executeDecimalMath,
+	// so skip the big.Rat conversions.
+
+	var res float64
+	switch op {
+	case ast.BinaryAdd:
+		res = lhs + rhs
+	case ast.BinarySub:
+		res = lhs - rhs
+	case ast.BinaryMul:
+		res = lhs * rhs
+	case ast.BinaryDiv, ast.BinaryMod:
 		if rhs == 0 {
-			return 0, fmt.Errorf("%w: division by zero", ErrVerbose)
+			return 0, fmt.Errorf("%w: division by zero", errVerboseNumeric)
 		}
-		return lhs / rhs, nil
-	case ast.BinaryMod:
-		if rhs == 0 {
-			return 0, fmt.Errorf("%w: division by zero", ErrVerbose)
+		if op == ast.BinaryDiv {
+			res = lhs / rhs
+		} else {
+			res = math.Mod(lhs, rhs)
 		}
-		return math.Mod(lhs, rhs), nil
 	default:
 		// We process only the binary math operators here.
 		return 0, fmt.Errorf("%w: %v is not a binary math operator", ErrInvalid, op)
 	}
+
+	if isFinite(res) {
+		return unsignedZero(res), nil
+	}
+	return 0, nonFiniteErr("operator", op)
+}
+
+// executeDecimalMath applies op to lhs and rhs and returns the float64
+// nearest the exact result. This mirrors PostgreSQL, which applies math
+// operators to numeric values: operands such as 2.5 and 0.3 combine as the
+// decimal numbers they represent rather than as their binary
+// approximations, so that 2.5 % 0.3 is 0.1 and 12.3 * 3 is 36.9, and the
+// remainder has the sign of lhs. Returns an error for a divisor of zero or
+// a result too large for a float64.
+func executeDecimalMath(lhs, rhs *big.Rat, op ast.BinaryOperator) (float64, error) {
+	res := new(big.Rat)
+	switch op {
+	case ast.BinaryAdd:
+		res.Add(lhs, rhs)
+	case ast.BinarySub:
+		res.Sub(lhs, rhs)
+	case ast.BinaryMul:
+		res.Mul(lhs, rhs)
+	case ast.BinaryDiv, ast.BinaryMod:
+		if rhs.Sign() == 0 {
+			return 0, fmt.Errorf("%w: division by zero", errVerboseNumeric)
+		}
+		res.Quo(lhs, rhs)
+		if op == ast.BinaryMod {
+			// lhs - rhs * trunc(lhs / rhs); big.Int.Quo truncates toward zero.
+			trunc := new(big.Int).Quo(res.Num(), res.Denom())
+			res.Sub(lhs, res.Mul(rhs, res.SetInt(trunc)))
+		}
+	default:
+		// We process only the binary math operators here.
+		return 0, fmt.Errorf("%w: %v is not a binary math operator", ErrInvalid, op)
+	}
+
+	f, _ := res.Float64()
+	if !isFinite(f) {
+		return 0, fmt.Errorf("%w: value overflows numeric format", errVerboseNumeric)
+	}
+	return unsignedZero(f), nil
+}
+
+// floatRat returns the decimal number that f represents, that is, the
+// shortest decimal that parses to f, as a big.Rat. f must be finite.
+func floatRat(f float64) *big.Rat {
+	rat, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return rat
+}
+
+// maxExactInteger is the largest integer such that float64 represents it
+// and all smaller integers exactly.
+const maxExactInteger = 1 << 53
+
+// isExactInteger returns true if f is an integer no larger in magnitude
+// than maxExactInteger, so that it represents the same decimal number as
+// its binary value.
+func isExactInteger(f float64) bool {
+	return f == math.Trunc(f) && math.Abs(f) <= maxExactInteger
+}
+
+// isFinite returns true if f is neither NaN nor Infinity.
+func isFinite(f float64) bool {
+	return !math.IsInf(f, 0) && !math.IsNaN(f)
+}
+
+// nonFiniteErr creates an error for a NaN or Infinity passed to or produced
+// by what, an operator or item method, named kind in the message.
+func nonFiniteErr(kind string, what any) error {
+	return fmt.Errorf(
+		"%w: NaN or Infinity is not allowed for jsonpath %v %v",
+		errVerboseNumeric, kind, what,
+	)
 }
 
 // mathOperandErr creates an error for an invalid operand to op. pos is the
@@ -68,7 +228,7 @@ func executeFloatMath(lhs, rhs float64, op ast.BinaryOperator) (float64, error)
 func mathOperandErr(op ast.BinaryOperator, pos string) error {
 	return fmt.Errorf(
 		"%w: %v operand of jsonpath operator %v is not a single numeric value",
-		ErrVerbose, pos, op,
+		errVerboseType, pos, op,
 	)
 }
 
@@ -84,7 +244,8 @@ func (exec *Executor) execUnaryMathExpr(
 	floatCallback floatCallback,
 	found *valueList,
 ) (resultStatus, error) {
-	seq := newList()
+	seq := getList()
+	defer putList(seq)
 	res, err := exec.executeItemOptUnwrapResult(ctx, node.Operand(), value, true, seq)
 	if res == statusFailed {
 		return res, err
@@ -120,9 +281,15 @@ func (exec *Executor) execUnaryMathExpr(
 		if !ok {
 			return exec.returnVerboseError(fmt.Errorf(
 				"%w: operand of unary jsonpath operator %v is not a numeric value",
-				ErrVerbose, node.Operator(),
+				errVerboseType, node.Operator(),
 			))
 		}
+		if f, isFloat := val.(float64); isFloat {
+			if !isFinite(f) {
+				return exec.returnVerboseError(nonFiniteErr("operator", node.Operator()))
+			}
+			val = unsignedZero(f)
+		}
 
 		nextRes, err := exec.executeNextItem(ctx, node, next, val, found)
 		if nextRes.failed() {
@@ -150,7 +317,8 @@ func (exec *Executor) execBinaryMathExpr(
 	// Get the left node.
 	// XXX: The standard says only operands of multiplicative expressions are
 	// unwrapped. We extend it to other binary arithmetic expressions too.
-	lSeq := newList()
+	lSeq := getList()
+	defer putList(lSeq)
 	res, err := exec.executeItemOptUnwrapResult(ctx, node.Left(), value, true, lSeq)
 	if res == statusFailed {
 		return res, err
@@ -161,7 +329,8 @@ func (exec *Executor) execBinaryMathExpr(
 		return exec.returnVerboseError(mathOperandErr(op, "left"))
 	}
 
-	rSeq := newList()
+	rSeq := getList()
+	defer putList(rSeq)
 	res, err = exec.executeItemOptUnwrapResult(ctx, node.Right(), value, true, rSeq)
 	if res == statusFailed {
 		return res, err
@@ -193,12 +362,12 @@ func execMathOp(left, right any, op ast.BinaryOperator) (any, error) {
 	case int64:
 		switch right := right.(type) {
 		case int64:
-			return executeIntegerMath(left, right, op)
+			return executeIntegerOp(left, right, op)
 		case float64:
 			return executeFloatMath(float64(left), right, op)
 		case json.Number:
 			if right, err := right.Int64(); err == nil {
-				return executeIntegerMath(left, right, op)
+				return executeIntegerOp(left, right, op)
 			}
 			if right, err := right.Float64(); err == nil {
 				return executeFloatMath(float64(left), right, op)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -77,6 +78,21 @@ func TestExecuteIntegerMath(t *testing.T) {
 			err:   "exec: division by zero",
 			isErr: ErrVerbose,
 		},
+		{
+			name:  "div_min_int_neg_one",
+			left:  math.MinInt64,
+			right: -1,
+			op:    ast.BinaryDiv,
+			err:   "exec: value overflows numeric format",
+			isErr: ErrNumeric,
+		},
+		{
+			name:  "mod_min_int_neg_one",
+			left:  math.MinInt64,
+			right: -1,
+			op:    ast.BinaryMod,
+			exp:   0,
+		},
 		{
 			name:  "not_math",
 			op:    ast.BinaryAnd,
@@ -164,6 +180,134 @@ func TestExecuteFloatMath(t *testing.T) {
 			err:   "exec: division by zero",
 			isErr: ErrVerbose,
 		},
+		{
+			name:  "add_overflow",
+			left:  math.MaxFloat64,
+			right: math.MaxFloat64,
+			op:    ast.BinaryAdd,
+			err:   "exec: value overflows numeric format",
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "sub_overflow",
+			left:  -math.MaxFloat64,
+			right: math.MaxFloat64,
+			op:    ast.BinarySub,
+			err:   "exec: value overflows numeric format",
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "mul_overflow",
+			left:  1e308,
+			right: 10,
+			op:    ast.BinaryMul,
+			err:   "exec: value overflows numeric format",
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "div_overflow",
+			left:  1e308,
+			right: 1e-10,
+			op:    ast.BinaryDiv,
+			err:   "exec: value overflows numeric format",
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "add_inf",
+			left:  math.Inf(1),
+			right: 1,
+			op:    ast.BinaryAdd,
+			err:   "exec: NaN or Infinity is not allowed for jsonpath operator +",
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "sub_inf_nan",
+			left:  math.Inf(1),
+			right: math.Inf(1),
+			op:    ast.BinarySub,
+			err:   "exec: NaN or Infinity is not allowed for jsonpath operator -",
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "mul_nan",
+			left:  math.NaN(),
+			right: 2,
+			op:    ast.BinaryMul,
+			err:   "exec: NaN or Infinity is not allowed for jsonpath operator *",
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "mod_inf_nan",
+			left:  math.Inf(-1),
+			right: 2,
+			op:    ast.BinaryMod,
+			err:   "exec: NaN or Infinity is not allowed for jsonpath operator %",
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "int_add",
+			left:  9007199254740992,
+			right: 1,
+			op:    ast.BinaryAdd,
+			exp:   9007199254740992,
+		},
+		{
+			name:  "int_mul",
+			left:  -9007199254740992,
+			right: 9007199254740992,
+			op:    ast.BinaryMul,
+			exp:   -81129638414606681695789005144064,
+		},
+		{
+			name:  "int_div",
+			left:  7,
+			right: 2,
+			op:    ast.BinaryDiv,
+			exp:   3.5,
+		},
+		{
+			name:  "int_div_inexact",
+			left:  1,
+			right: 3,
+			op:    ast.BinaryDiv,
+			exp:   float64(1) / 3,
+		},
+		{
+			name:  "int_mod",
+			left:  -7,
+			right: 2,
+			op:    ast.BinaryMod,
+			exp:   -1,
+		},
+		{
+			name:  "int_mod_neg_zero",
+			left:  -4,
+			right: 2,
+			op:    ast.BinaryMod,
+			exp:   0,
+		},
+		{
+			name:  "int_div_zero",
+			left:  4,
+			right: 0,
+			op:    ast.BinaryDiv,
+			err:   "exec: division by zero",
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "large_int",
+			left:  1e20,
+			right: 0.5,
+			op:    ast.BinaryAdd,
+			exp:   1e20,
+		},
+		{
+			name:  "div_by_inf",
+			left:  2,
+			right: math.Inf(1),
+			op:    ast.BinaryDiv,
+			exp:   0,
+		},
 		{
 			name:  "not_math",
 			op:    ast.BinaryAnd,
@@ -177,6 +321,7 @@ func TestExecuteFloatMath(t *testing.T) {
 			res, err := executeFloatMath(tc.left, tc.right, tc.op)
 			//nolint:testifylint
 			a.Equal(tc.exp, res)
+			a.False(math.Signbit(res) && res == 0, "negative zero")
 			if tc.isErr == nil {
 				r.NoError(err)
 			} else {
@@ -187,6 +332,57 @@ func TestExecuteFloatMath(t *testing.T) {
 	}
 }
 
+func TestExecuteFloatMathIntegers(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	// The float64 arithmetic used for integers must agree with the decimal
+	// arithmetic used for other numbers.
+	ints := []float64{
+		0, 1, -1, 2, 3, -7, 10, 123456789, 1e15 + 1, 5559060566555523,
+		maxExactInteger - 1, maxExactInteger, -maxExactInteger,
+	}
+	ops := []ast.BinaryOperator{ast.BinaryAdd, ast.BinarySub, ast.BinaryMul, ast.BinaryDiv, ast.BinaryMod}
+	for _, lhs := range ints {
+		for _, rhs := range ints {
+			for _, op := range ops {
+				exp, expErr := executeDecimalMath(floatRat(lhs), floatRat(rhs), op)
+				res, err := executeFloatMath(lhs, rhs, op)
+				if expErr != nil {
+					r.EqualError(err, expErr.Error(), "%v %v %v", lhs, op, rhs)
+					continue
+				}
+				r.NoError(err, "%v %v %v", lhs, op, rhs)
+				//nolint:testifylint
+				a.Equal(exp, res, "%v %v %v", lhs, op, rhs)
+			}
+		}
+	}
+}
+
+func BenchmarkExecuteFloatMath(b *testing.B) {
+	for _, tc := range []struct {
+		name string
+		lhs  float64
+		rhs  float64
+	}{
+		{"integers", 12345, 678},
+		{"fractions", 123.45, 6.78},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				for _, op := range []ast.BinaryOperator{ast.BinaryAdd, ast.BinaryMul, ast.BinaryDiv} {
+					if _, err := executeFloatMath(tc.lhs, tc.rhs, op); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestMathOperandErr(t *testing.T) {
 	t.Parallel()
 	r := require.New(t)
@@ -225,7 +421,7 @@ func TestExecUnaryMathExpr(t *testing.T) {
 	r := require.New(t)
 	ctx := context.Background()
 	path, _ := parser.Parse("$")
-	icb := func(i int64) int64 { return i * 2 }
+	icb := func(i int64) any { return i * 2 }
 	fcb := func(i float64) float64 { return i * 3 }
 
 	for _, tc := range []struct {
@@ -640,3 +836,469 @@ func TestExecMathOp(t *testing.T) {
 		})
 	}
 }
+
+func TestDivisionAndModulo(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	overflow := "exec: value overflows numeric format"
+	divZero := "exec: division by zero"
+
+	// Expected results from PostgreSQL, e.g.,
+	// SELECT jsonb_path_query('[7, -2]', '$[0] % $[1]');
+	for _, tc := range []struct {
+		name  string
+		left  any
+		right any
+		div   any
+		mod   any
+		err   string
+	}{
+		{"int_exact", int64(42), int64(6), int64(7), int64(0), ""},
+		{"int_inexact", int64(7), int64(2), float64(3.5), int64(1), ""},
+		{"int_neg_dividend", int64(-7), int64(2), float64(-3.5), int64(-1), ""},
+		{"int_neg_divisor", int64(7), int64(-2), float64(-3.5), int64(1), ""},
+		{"int_neg_both", int64(-7), int64(-2), float64(3.5), int64(-1), ""},
+		{"int_thirds", int64(1), int64(3), float64(1) / 3, int64(1), ""},
+		{"int_zero_dividend", int64(0), int64(-5), int64(0), int64(0), ""},
+		{"int_zero", int64(7), int64(0), nil, nil, divZero},
+		{"min_int_neg_one", int64(math.MinInt64), int64(-1), json.Number("9223372036854775808"), int64(0), ""},
+		{"min_int_one", int64(math.MinInt64), int64(1), int64(math.MinInt64), int64(0), ""},
+		{"min_int_two", int64(math.MinInt64), int64(2), int64(math.MinInt64 / 2), int64(0), ""},
+		{"max_int_neg_one", int64(math.MaxInt64), int64(-1), int64(-math.MaxInt64), int64(0), ""},
+		{"float_fraction", float64(2.5), float64(0.3), float64(8.333333333333334), float64(0.1), ""},
+		{"float_neg_dividend", float64(-2.5), float64(0.3), float64(-8.333333333333334), float64(-0.1), ""},
+		{"float_neg_divisor", float64(2.5), float64(-0.3), float64(-8.333333333333334), float64(0.1), ""},
+		{"float_decimal_quotient", float64(0.3), float64(0.1), float64(3), float64(0), ""},
+		{"float_small_modulus", float64(1), float64(0.1), float64(10), float64(0), ""},
+		{"float_neg_zero_result", float64(-0.6), float64(0.3), float64(-2), float64(0), ""},
+		{"float_zero", float64(2.5), float64(0), nil, nil, divZero},
+		{"float_neg_zero", float64(2.5), math.Copysign(0, -1), nil, nil, divZero},
+		{"float_overflow", float64(1e308), float64(0.1), nil, float64(0), overflow},
+		{"int_float", int64(7), float64(0.5), float64(14), float64(0), ""},
+		{"float_int", float64(7.5), int64(2), float64(3.75), float64(1.5), ""},
+		{"float_int_zero", float64(7.5), int64(0), nil, nil, divZero},
+		{"json_int", json.Number("-7"), json.Number("2"), float64(-3.5), int64(-1), ""},
+		{"json_float", json.Number("2.5"), json.Number("-0.3"), float64(-8.333333333333334), float64(0.1), ""},
+		{"json_int_float", json.Number("10"), float64(0.3), float64(33.333333333333336), float64(0.1), ""},
+		{"int_json_float", int64(10), json.Number("0.3"), float64(33.333333333333336), float64(0.1), ""},
+		{"json_min_int", json.Number("-9223372036854775808"), int64(-1), json.Number("9223372036854775808"), int64(0), ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			for _, op := range []ast.BinaryOperator{ast.BinaryDiv, ast.BinaryMod} {
+				exp := tc.div
+				if op == ast.BinaryMod {
+					exp = tc.mod
+				}
+
+				res, err := execMathOp(tc.left, tc.right, op)
+				if exp == nil {
+					r.EqualError(err, tc.err, op)
+					r.ErrorIs(err, ErrNumeric, op)
+					r.ErrorIs(err, ErrVerbose, op)
+					continue
+				}
+				r.NoError(err, op)
+				a.Equal(exp, res, op)
+				if f, ok := res.(float64); ok {
+					a.False(math.Signbit(f) && f == 0, "negative zero for %v", op)
+				}
+			}
+
+			// Execute through a path, too.
+			path, err := parser.Parse("$[0] % $[1]")
+			r.NoError(err)
+			res, err := Query(ctx, path, []any{tc.left, tc.right})
+			if tc.mod == nil {
+				r.EqualError(err, tc.err)
+			} else {
+				r.NoError(err)
+				a.Equal([]any{tc.mod}, res)
+			}
+		})
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// Expected results from PostgreSQL, which computes with numeric values,
+	// e.g., SELECT jsonb_path_query('[0.1, 0.2]', '$[0] + $[1]');
+	for _, tc := range []struct {
+		name  string
+		left  any
+		right any
+		op    string
+		exp   any
+	}{
+		{"add_floats", float64(0.1), float64(0.2), "+", float64(0.3)},
+		{"sub_floats", float64(0.1), float64(0.3), "-", float64(-0.2)},
+		{"mul_floats", float64(1.1), float64(1.1), "*", float64(1.21)},
+		{"mul_float_int", float64(12.3), int64(3), "*", float64(36.9)},
+		{"mul_int_float", int64(3), float64(0.1), "*", float64(0.3)},
+		{"add_int_float", int64(1), float64(0.1), "+", float64(1.1)},
+		{"mul_json_int", json.Number("12.3"), json.Number("2"), "*", float64(24.6)},
+		{"sub_json_floats", json.Number("1.3"), json.Number("0.1"), "-", float64(1.2)},
+		{"add_json_float", json.Number("0.7"), float64(0.1), "+", float64(0.8)},
+		{"sub_neg_zero", float64(-0.1), float64(-0.1), "-", float64(0)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse("$[0] " + tc.op + " $[1]")
+			r.NoError(err)
+			res, err := Query(ctx, path, []any{tc.left, tc.right})
+			r.NoError(err)
+			a.Equal([]any{tc.exp}, res)
+			if f, ok := res[0].(float64); ok {
+				a.False(math.Signbit(f) && f == 0, "negative zero")
+			}
+		})
+	}
+
+	// Including decimal literals and the results of .decimal().
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		exp   any
+	}{
+		{"decimal_json_number", `$.decimal() * 3`, json.Number("12.3"), float64(36.9)},
+		{"decimal_string", `$.decimal() * 2`, "12.3", float64(24.6)},
+		{"literal", `$ * 0.1`, int64(3), float64(0.3)},
+		{"floor_mod", `-($.a * $.a).floor() % 4.3`, map[string]any{"a": float64(2.5)}, float64(-1.7)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			res, err := Query(ctx, path, tc.value)
+			r.NoError(err)
+			a.Equal([]any{tc.exp}, res)
+		})
+	}
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	t.Parallel()
+	add, sub, mul, div := ast.BinaryAdd, ast.BinarySub, ast.BinaryMul, ast.BinaryDiv
+
+	// PostgreSQL computes with numeric values, so that results outside the
+	// range of int64 are exact rather than wrapped or rounded, e.g.,
+	// SELECT jsonb_path_query('9223372036854775807', '$ + 1');
+	for _, tc := range []struct {
+		name  string
+		left  any
+		right any
+		op    ast.BinaryOperator
+		exp   any
+	}{
+		{"add_max", int64(math.MaxInt64), int64(0), add, int64(math.MaxInt64)},
+		{"add_overflow", int64(math.MaxInt64), int64(1), add, json.Number("9223372036854775808")},
+		{"add_max_max", int64(math.MaxInt64), int64(math.MaxInt64), add, json.Number("18446744073709551614")},
+		{"add_neg_overflow", int64(math.MinInt64), int64(-1), add, json.Number("-9223372036854775809")},
+		{"add_min_max", int64(math.MinInt64), int64(math.MaxInt64), add, int64(-1)},
+		{"sub_min", int64(math.MinInt64 + 1), int64(1), sub, int64(math.MinInt64)},
+		{"sub_overflow", int64(math.MinInt64), int64(1), sub, json.Number("-9223372036854775809")},
+		{"sub_neg_overflow", int64(math.MaxInt64), int64(-1), sub, json.Number("9223372036854775808")},
+		{"sub_zero_min", int64(0), int64(math.MinInt64), sub, json.Number("9223372036854775808")},
+		{"sub_neg_one_min", int64(-1), int64(math.MinInt64), sub, int64(math.MaxInt64)},
+		{"mul_max", int64(math.MaxInt64), int64(1), mul, int64(math.MaxInt64)},
+		{"mul_overflow", int64(1) << 62, int64(2), mul, json.Number("9223372036854775808")},
+		{"mul_wrap_zero", int64(1) << 62, int64(4), mul, json.Number("18446744073709551616")},
+		{"mul_neg_overflow", int64(1) << 62, int64(-4), mul, json.Number("-18446744073709551616")},
+		{"mul_min_neg_one", int64(math.MinInt64), int64(-1), mul, json.Number("9223372036854775808")},
+		{"mul_neg_one_min", int64(-1), int64(math.MinInt64), mul, json.Number("9223372036854775808")},
+		{"mul_min_one", int64(math.MinInt64), int64(1), mul, int64(math.MinInt64)},
+		{"mul_min_zero", int64(math.MinInt64), int64(0), mul, int64(0)},
+		{"mul_neg_min", int64(1) << 62, int64(-2), mul, int64(math.MinInt64)},
+		{"div_min_neg_one", int64(math.MinInt64), int64(-1), div, json.Number("9223372036854775808")},
+		{"div_min_one", int64(math.MinInt64), int64(1), div, int64(math.MinInt64)},
+		{"json_overflow", json.Number("9223372036854775807"), json.Number("2"), mul, json.Number("18446744073709551614")},
+		{"int_json_overflow", int64(2), json.Number("9223372036854775807"), add, json.Number("9223372036854775809")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			res, err := execMathOp(tc.left, tc.right, tc.op)
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+		})
+	}
+}
+
+func TestUnaryIntegerOverflow(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// Negating math.MinInt64 overflows int64, e.g.,
+	// SELECT jsonb_path_query('-9223372036854775808', '-$');
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		exp   []any
+	}{
+		{"minus_min", "-$", int64(math.MinInt64), []any{json.Number("9223372036854775808")}},
+		{"minus_max", "-$", int64(math.MaxInt64), []any{int64(-math.MaxInt64)}},
+		{"minus_json_min", "-$", json.Number("-9223372036854775808"), []any{json.Number("9223372036854775808")}},
+		{"minus_array", "-$[*]", []any{int64(1), int64(math.MinInt64)}, []any{int64(-1), json.Number("9223372036854775808")}},
+		{"plus_min", "+$", int64(math.MinInt64), []any{int64(math.MinInt64)}},
+		{"abs_min", "$.abs()", int64(math.MinInt64), []any{json.Number("9223372036854775808")}},
+		{"abs_json_min", "$.abs()", json.Number("-9223372036854775808"), []any{json.Number("9223372036854775808")}},
+		{"add_exact", "$ + 1", int64(math.MaxInt64), []any{json.Number("9223372036854775808")}},
+		{"div_exact", "$ / -1", int64(math.MinInt64), []any{json.Number("9223372036854775808")}},
+		{"literal_add", "9223372036854775807 + 1", nil, []any{json.Number("9223372036854775808")}},
+		{"literal_mul", "-9223372036854775807 * 3", nil, []any{json.Number("-27670116110564327421")}},
+		{"literal_div", "(-9223372036854775807 - 1) / -1", nil, []any{json.Number("9223372036854775808")}},
+		{"filter", "$ ? (-@ > 9223372036854775807)", int64(math.MinInt64), []any{int64(math.MinInt64)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			res, err := Query(ctx, path, tc.value)
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+		})
+	}
+}
+
+func TestNonFiniteMath(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	overflow := "exec: value overflows numeric format"
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		exp   []any
+		err   string
+	}{
+		{
+			name:  "mul_overflow",
+			path:  "$ * 1e308 * 10",
+			value: float64(1),
+			err:   overflow,
+		},
+		{
+			name:  "mul_overflow_json",
+			path:  "$.a * $.a",
+			value: map[string]any{"a": json.Number("1e200")},
+			err:   overflow,
+		},
+		{
+			name:  "div_overflow",
+			path:  "$ / 0.0000000001",
+			value: float64(1e308),
+			err:   overflow,
+		},
+		{
+			name:  "div_zero",
+			path:  "$ / 0",
+			value: float64(0),
+			err:   "exec: division by zero",
+		},
+		{
+			name:  "inf_minus_inf",
+			path:  "$[0] - $[1]",
+			value: []any{math.Inf(1), math.Inf(1)},
+			err:   "exec: NaN or Infinity is not allowed for jsonpath operator -",
+		},
+		{
+			name:  "unary_minus_inf",
+			path:  "-$",
+			value: math.Inf(1),
+			err:   "exec: NaN or Infinity is not allowed for jsonpath operator -",
+		},
+		{
+			name:  "abs_inf",
+			path:  "$.abs()",
+			value: math.Inf(-1),
+			err:   "exec: NaN or Infinity is not allowed for jsonpath item method .abs()",
+		},
+		{
+			name:  "floor_nan",
+			path:  "$.floor()",
+			value: math.NaN(),
+			err:   "exec: NaN or Infinity is not allowed for jsonpath item method .floor()",
+		},
+		{
+			name:  "filter_overflow",
+			path:  "$[*] ? (@ * 1e308 > 0)",
+			value: []any{float64(0.5), float64(10), int64(1)},
+			exp:   []any{float64(0.5), int64(1)},
+		},
+		{
+			name:  "filter_nan",
+			path:  "$[*] ? (@ - @ == 0)",
+			value: []any{math.Inf(1), float64(2)},
+			exp:   []any{float64(2)},
+		},
+		{
+			name:  "predicate_overflow",
+			path:  "$ * 1e308 > 0",
+			value: float64(10),
+			exp:   []any{nil},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := Query(ctx, path, tc.value)
+			if tc.err == "" {
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+			} else {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(res)
+			}
+
+			// Silent mode suppresses the errors.
+			res, err = Query(ctx, path, tc.value, WithSilent())
+			r.NoError(err)
+			if tc.err == "" {
+				a.Equal(tc.exp, res)
+			} else {
+				a.Empty(res)
+			}
+		})
+	}
+}
+
+func TestSignedZero(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	negZero := math.Copysign(0, -1)
+	values := []any{int64(0), float64(0), negZero, json.Number("-0.0"), json.Number("-0")}
+
+	for _, tc := range []struct {
+		name string
+		path string
+		exp  []any
+	}{
+		{
+			name: "unary_minus",
+			path: "-$[*]",
+			exp:  []any{int64(0), float64(0), float64(0), float64(0), int64(0)},
+		},
+		{
+			name: "unary_plus",
+			path: "+$[*]",
+			exp:  []any{int64(0), float64(0), float64(0), float64(0), int64(0)},
+		},
+		{
+			name: "abs",
+			path: "$[*].abs()",
+			exp:  []any{int64(0), float64(0), float64(0), float64(0), int64(0)},
+		},
+		{
+			name: "ceiling",
+			path: "$[*].ceiling()",
+			exp:  []any{int64(0), float64(0), float64(0), float64(0), int64(0)},
+		},
+		{
+			name: "ceiling_negative",
+			path: "($[0] - 0.5).ceiling()",
+			exp:  []any{float64(0)},
+		},
+		{
+			name: "floor",
+			path: "$[*].floor()",
+			exp:  []any{int64(0), float64(0), float64(0), float64(0), int64(0)},
+		},
+		{
+			name: "multiply",
+			path: "$[0] * -1.0",
+			exp:  []any{float64(0)},
+		},
+		{
+			name: "multiply_json",
+			path: "$[3] * -1",
+			exp:  []any{float64(0)},
+		},
+		{
+			name: "subtract",
+			path: "$[2] - 0",
+			exp:  []any{float64(0)},
+		},
+		{
+			name: "double",
+			path: "$[*].double()",
+			exp:  []any{float64(0), float64(0), float64(0), float64(0), float64(0)},
+		},
+		{
+			name: "number",
+			path: "$[*].number()",
+			exp:  []any{float64(0), float64(0), float64(0), float64(0), float64(0)},
+		},
+		{
+			name: "eq_zero",
+			path: "$[*] ? (@ == 0)",
+			exp:  values,
+		},
+		{
+			name: "eq_neg_zero",
+			path: "$[*] ? (@ == -0.0)",
+			exp:  values,
+		},
+		{
+			name: "eq_neg_expr",
+			path: "$[*] ? (-@ == 0)",
+			exp:  values,
+		},
+		{
+			name: "lt_zero",
+			path: "$[*] ? (@ < 0)",
+			exp:  []any{},
+		},
+		{
+			name: "string",
+			path: "$[*].string()",
+			exp:  []any{"0", "0", "0", "0.0", "0"},
+		},
+		{
+			name: "neg_string",
+			path: "(-$[*]).string()",
+			exp:  []any{"0", "0", "0", "0", "0"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := Query(ctx, path, values)
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+			for i, v := range res {
+				// Equal considers -0.0 and 0.0 equal, so compare signs, too.
+				if f, ok := v.(float64); ok {
+					a.Equal(math.Signbit(tc.exp[i].(float64)), math.Signbit(f), "item %v", i)
+				}
+			}
+		})
+	}
+}
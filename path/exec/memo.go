@@ -0,0 +1,230 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/theory/sqljson/path/ast"
+)
+
+// memoEntry records the result of executing a predicate operand against the
+// current filter item, and the span of exec.memoItems with the resulting
+// items.
+type memoEntry struct {
+	node       ast.Node
+	unwrap     bool
+	res        resultStatus
+	start, end int
+}
+
+// planMemo finds the predicate operands in exec.path that appear more than
+// once in the same filter, such as @.a in ? (@.a > 1 && @.a < 5), and
+// records them in exec.memoKeys, mapping each to the first operand
+// equivalent to it. Only operands without custom methods or .keyvalue(),
+// which may not be pure, and with more than one node, which are not worth
+// the overhead, qualify. Built-in item methods are pure: their results
+// depend only on their inputs and the execution options.
+func (exec *Executor) planMemo() {
+	exec.memoPlanned = true
+	ast.Walk(&memoPlanner{exec: exec}, exec.path.Root())
+}
+
+// memoPlanner is an [ast.Visitor] that plans the memoization of the
+// operands of each filter it visits.
+type memoPlanner struct {
+	exec     *Executor
+	operands []ast.Node
+}
+
+// Visit plans the memoization of the operands of node if it is a filter.
+func (p *memoPlanner) Visit(node ast.Node) ast.Visitor {
+	if filter, ok := node.(*ast.UnaryNode); ok && filter.Operator() == ast.UnaryFilter {
+		p.operands = collectOperands(filter.Operand(), p.operands[:0])
+		p.exec.planFilterMemo(p.operands)
+	}
+	return p
+}
+
+// planFilterMemo records the operands of a filter that are equivalent to
+// other operands of the same filter in exec.memoKeys.
+func (exec *Executor) planFilterMemo(operands []ast.Node) {
+	if len(operands) < 2 {
+		return
+	}
+
+	keys := make([]string, len(operands))
+	for i, operand := range operands {
+		keys[i] = chainKey(operand)
+	}
+	for i, operand := range operands {
+		for j, key := range keys {
+			if i != j && key == keys[i] {
+				if exec.memoKeys == nil {
+					exec.memoKeys = map[ast.Node]ast.Node{}
+				}
+				exec.memoKeys[operand] = operands[min(i, j)]
+				break
+			}
+		}
+	}
+}
+
+// collectOperands appends to operands the memoizable operands of the
+// comparison, starts with, and like_regex predicates in the boolean
+// expression node, including those combined by &&, ||, !, and is unknown,
+// but not those in nested filters. Returns the resulting slice.
+func collectOperands(node ast.Node, operands []ast.Node) []ast.Node {
+	switch node := node.(type) {
+	case *ast.BinaryNode:
+		switch node.Operator() {
+		case ast.BinaryAnd, ast.BinaryOr:
+			operands = collectOperands(node.Left(), operands)
+			return collectOperands(node.Right(), operands)
+		case ast.BinaryEqual, ast.BinaryNotEqual, ast.BinaryLess,
+			ast.BinaryGreater, ast.BinaryLessOrEqual, ast.BinaryGreaterOrEqual,
+			ast.BinaryStartsWith:
+			return appendMemoizable(operands, node.Left(), node.Right())
+		default:
+		}
+	case *ast.UnaryNode:
+		switch node.Operator() {
+		case ast.UnaryNot, ast.UnaryIsUnknown:
+			return collectOperands(node.Operand(), operands)
+		default:
+		}
+	case *ast.RegexNode:
+		return appendMemoizable(operands, node.Operand())
+	}
+	return operands
+}
+
+// appendMemoizable appends to operands those of nodes whose results may be
+// reused for the same filter item: those with a next node or operands, and
+// without custom methods or .keyvalue().
+func appendMemoizable(operands []ast.Node, nodes ...ast.Node) []ast.Node {
+	for _, node := range nodes {
+		switch node.(type) {
+		case *ast.BinaryNode, *ast.UnaryNode:
+		default:
+			if node.Next() == nil {
+				continue
+			}
+		}
+		if pure(node) {
+			operands = append(operands, node)
+		}
+	}
+	return operands
+}
+
+// pure returns false if node, its operands, subscripts, or the nodes that
+// follow it call a custom method or .keyvalue().
+func pure(node ast.Node) bool {
+	for ; node != nil; node = node.Next() {
+		switch node := node.(type) {
+		case *ast.MethodNode:
+			if name := node.Name(); name == ast.MethodCustom || name == ast.MethodKeyValue {
+				return false
+			}
+		case *ast.BinaryNode:
+			if !pure(node.Left()) || !pure(node.Right()) {
+				return false
+			}
+		case *ast.UnaryNode:
+			if !pure(node.Operand()) {
+				return false
+			}
+		case *ast.RegexNode:
+			if !pure(node.Operand()) {
+				return false
+			}
+		case *ast.ArrayIndexNode:
+			for _, sub := range node.Subscripts() {
+				if !pure(sub) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// chainKey returns a string that identifies node and the nodes that follow
+// it, so that equivalent operands have the same key.
+func chainKey(node ast.Node) string {
+	var buf strings.Builder
+	for ; node != nil; node = node.Next() {
+		fmt.Fprintf(&buf, "%T %v\x00", node, node)
+	}
+	return buf.String()
+}
+
+// executeOperand executes node, a predicate operand, against value as
+// executeItemOptUnwrapResultSilent does, adding the resulting items to
+// found, which must be empty. If node appears more than once in the filter
+// being evaluated, it records the items for the current filter item, and
+// appends the recorded items to found rather than execute node again for
+// the equivalents of node that follow. Does not record the results of
+// failed execution, nor while Explain records steps.
+func (exec *Executor) executeOperand(
+	ctx context.Context,
+	node ast.Node,
+	value any,
+	unwrap bool,
+	found *valueList,
+) (resultStatus, error) {
+	key, ok := exec.memoKeys[node]
+	if !ok || exec.trace != nil {
+		return exec.executeItemOptUnwrapResultSilent(ctx, node, value, unwrap, found)
+	}
+
+	for _, entry := range exec.memo[exec.memoStart:] {
+		if entry.node == key && entry.unwrap == unwrap {
+			for _, item := range exec.memoItems[entry.start:entry.end] {
+				// Intermediate lists have no limits.
+				_ = found.append(item)
+			}
+			return entry.res, nil
+		}
+	}
+
+	res, err := exec.executeItemOptUnwrapResultSilent(ctx, node, value, unwrap, found)
+	if res != statusFailed && err == nil {
+		start := len(exec.memoItems)
+		exec.memoItems = append(exec.memoItems, found.list...)
+		exec.memo = append(exec.memo, memoEntry{
+			node:   key,
+			unwrap: unwrap,
+			res:    res,
+			start:  start,
+			end:    len(exec.memoItems),
+		})
+	}
+	return res, err
+}
+
+// startMemo starts recording the results of repeated predicate operands
+// for a new filter item, planning them first if necessary. Returns the
+// start of the results recorded for the enclosing filter item, to be passed
+// to endMemo.
+func (exec *Executor) startMemo() int {
+	if !exec.memoPlanned {
+		exec.planMemo()
+	}
+	prev := exec.memoStart
+	exec.memoStart = len(exec.memo)
+	return prev
+}
+
+// endMemo discards the results recorded for the current filter item and
+// restores prev, the start of those recorded for the enclosing filter item.
+func (exec *Executor) endMemo(prev int) {
+	if exec.memoStart < len(exec.memo) {
+		start := exec.memo[exec.memoStart].start
+		clear(exec.memoItems[start:])
+		exec.memoItems = exec.memoItems[:start]
+		exec.memo = exec.memo[:exec.memoStart]
+	}
+	exec.memoStart = prev
+}
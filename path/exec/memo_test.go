@@ -0,0 +1,299 @@
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestPlanMemo(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		path string
+		exp  []string
+	}{
+		{
+			name: "no_filter",
+			path: `$.a == $.a`,
+		},
+		{
+			name: "single",
+			path: `$[*] ? (@.a > 1)`,
+		},
+		{
+			name: "repeated",
+			path: `$[*] ? (@.a > 1 && @.a < 5)`,
+			exp:  []string{`@."a"`, `@."a"`},
+		},
+		{
+			name: "three_times",
+			path: `$[*] ? (@.a.b > $min && @.a.b < $max && @.a.b != 7)`,
+			exp:  []string{`@."a"."b"`, `@."a"."b"`, `@."a"."b"`},
+		},
+		{
+			name: "different",
+			path: `$[*] ? (@.a > 1 && @.b < 5 && @.a.b == 2)`,
+		},
+		{
+			name: "not_and_or",
+			path: `$[*] ? (!(@.a == 1) || (@.a like_regex "x" && @.b starts with "y") is unknown)`,
+			exp:  []string{`@."a"`, `@."a"`},
+		},
+		{
+			name: "arithmetic",
+			path: `$[*] ? (@.a + 1 > 2 && @.a + 1 < 5)`,
+			exp:  []string{`@."a" + 1`, `@."a" + 1`},
+		},
+		{
+			name: "methods",
+			path: `$[*] ? (@.a.size() > 2 && @.a.size() < 5)`,
+			exp:  []string{`@."a".size()`, `@."a".size()`},
+		},
+		{
+			name: "single_nodes",
+			path: `$[*] ? (@ > 1 && @ < 5 && $x == $x)`,
+		},
+		{
+			name: "keyvalue",
+			path: `$[*] ? (@.keyvalue().key == "a" && @.keyvalue().key == "b")`,
+		},
+		{
+			name: "custom_method",
+			path: `$[*] ? (@.a.test_local() == 1 && @.a.test_local() == 2)`,
+		},
+		{
+			name: "nested_filters",
+			path: `$[*] ? (@.a > 1 && @.a < 5).b ? (@.a > 2 && @.c == 1)`,
+			exp:  []string{`@."a"`, `@."a"`},
+		},
+		{
+			name: "filter_in_operand",
+			path: `$[*] ? (@.a ? (@.b == 1 && @.b == 2).c == 1 && @.a ? (@.b == 1 && @.b == 2).c == 2)`,
+			exp: []string{
+				`@."a"?(@."b" == 1 && @."b" == 2)."c"`,
+				`@."a"?(@."b" == 1 && @."b" == 2)."c"`,
+				`@."b"`,
+				`@."b"`,
+				`@."b"`,
+				`@."b"`,
+			},
+		},
+		{
+			name: "exists_operand",
+			path: `$[*] ? (exists(@.a) && exists(@.a))`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			exec, err := newExec(path)
+			r.NoError(err)
+			exec.planMemo()
+			a.True(exec.memoPlanned)
+			keys := []string{}
+			ast.Inspect(path.Root(), func(node ast.Node) bool {
+				if key, ok := exec.memoKeys[node]; ok {
+					keys = append(keys, chainString(node))
+					a.Equal(chainKey(node), chainKey(key))
+				}
+				return true
+			})
+			if tc.exp == nil {
+				a.Empty(keys)
+				a.Nil(exec.memoKeys)
+			} else {
+				a.ElementsMatch(tc.exp, keys)
+			}
+		})
+	}
+}
+
+// chainString returns the string representation of node and the nodes
+// that follow it.
+func chainString(node ast.Node) string {
+	var str string
+	for ; node != nil; node = node.Next() {
+		switch node.(type) {
+		case *ast.KeyNode:
+			str += "."
+		case *ast.UnaryNode:
+			// Includes the nodes that follow.
+			return str + node.String()
+		}
+		str += node.String()
+	}
+	return str
+}
+
+func TestMemo(t *testing.T) {
+	t.Parallel()
+	registerTestMethods()
+
+	value := []any{
+		map[string]any{"a": map[string]any{"b": int64(1)}, "s": "xyz"},
+		map[string]any{"a": map[string]any{"b": int64(3)}, "s": "abc"},
+		map[string]any{"a": map[string]any{"b": []any{int64(3), int64(9)}}, "s": []any{"xy", "ab"}},
+		map[string]any{"a": map[string]any{"b": "x"}, "s": "ab"},
+		map[string]any{"a": map[string]any{}},
+		map[string]any{"a": map[string]any{"b": int64(7)}},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		opt    []Option
+		exp    []any
+		err    string
+		reused bool
+	}{
+		{
+			name:   "lax",
+			path:   `$[*] ? (@.a.b > $min && @.a.b < $max && @.a.b != 7)`,
+			opt:    []Option{WithVars(Vars{"min": int64(2), "max": int64(10)})},
+			exp:    []any{value[1], value[2]},
+			reused: true,
+		},
+		{
+			name:   "strict",
+			path:   `strict $[*] ? (@.a.b > 2 && @.a.b < 10)`,
+			exp:    []any{value[1], value[5]},
+			reused: true,
+		},
+		{
+			name:   "unknown",
+			path:   `$[*] ? ((@.a.b > 2) is unknown || @.a.b == 1)`,
+			exp:    []any{value[0], value[3]},
+			reused: true,
+		},
+		{
+			name:   "strict_missing_key",
+			path:   `strict $[*] ? ((@.a.b > 2) is unknown && (@.a.b < 10) is unknown)`,
+			exp:    []any{value[2], value[3], value[4]},
+			reused: true,
+		},
+		{
+			name: "failed",
+			path: `strict $[*] ? ((@.x > 2) is unknown && (@.x < 10) is unknown)`,
+			exp:  value,
+		},
+		{
+			name:   "starts_with",
+			path:   `$[*] ? (@.s starts with "x" || @.s starts with "a")`,
+			exp:    []any{value[0], value[1], value[2], value[3]},
+			reused: true,
+		},
+		{
+			name:   "like_regex",
+			path:   `$[*] ? (@.s like_regex "^x" && !(@.s starts with "xyz"))`,
+			exp:    []any{value[2]},
+			reused: true,
+		},
+		{
+			name:   "nested",
+			path:   `$[*] ? (@.a ? (@.b > 2 && @.b < 10).b == @.a ? (@.b > 2 && @.b < 10).b)`,
+			exp:    []any{value[1], value[2], value[5]},
+			reused: true,
+		},
+		{
+			name: "custom_method",
+			path: `$[*] ? (@.s.test_upper() starts with "A" || @.s.test_upper() starts with "X")`,
+			exp:  []any{value[0], value[1], value[2], value[3]},
+		},
+		{
+			name: "parallel",
+			path: `$[*] ? (@.a.b > 2 && @.a.b < 10)`,
+			opt:  []Option{WithParallel(2), func(e *Executor) { e.parallelThreshold = 2 }},
+			exp:  []any{value[1], value[2], value[5]},
+			// Workers evaluate items with contexts of their own.
+		},
+		{
+			name: "error",
+			path: `$[*] ? (@.a.b > 2 && @.a.b < 10).s.double()`,
+			err:  `exec: argument "abc" of jsonpath item method .double() is invalid for type double precision`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			run := func(memo bool) ([]any, int, error) {
+				ctx := &countingContext{Context: context.Background()}
+				exec, err := newExec(path, tc.opt...)
+				if err != nil {
+					return nil, 0, err
+				}
+				// Planned without memo keys disables memoization.
+				exec.memoPlanned = !memo
+				res, err := exec.queryAll(ctx, value)
+				return res, ctx.calls, err
+			}
+
+			res, calls, err := run(true)
+			expRes, expCalls, expErr := run(false)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.EqualError(expErr, tc.err)
+				return
+			}
+			r.NoError(err)
+			r.NoError(expErr)
+			a.Equal(tc.exp, res)
+			a.Equal(expRes, res)
+			if tc.reused {
+				a.Less(calls, expCalls)
+			} else {
+				a.Equal(expCalls, calls)
+			}
+		})
+	}
+}
+
+func BenchmarkMemo(b *testing.B) {
+	const size = 100_000
+	array := make([]any, size)
+	for i := range array {
+		array[i] = map[string]any{"a": map[string]any{"b": map[string]any{"c": int64(i)}}}
+	}
+	ctx := context.Background()
+	path, err := parser.Parse(
+		`$[*] ? (@.a.b.c > 10 && @.a.b.c < 90000 && @.a.b.c != 500 && @.a.b.c % 2 == 0 && @.a.b.c >= 12)`,
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, bc := range []struct {
+		name string
+		memo bool
+	}{
+		{"memo", true},
+		{"no_memo", false},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				exec, err := newExec(path)
+				if err != nil {
+					b.Fatal(err)
+				}
+				exec.memoPlanned = !bc.memo
+				if _, err := exec.queryAll(ctx, array); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
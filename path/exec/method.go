@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"math"
 	"strconv"
-	"strings"
 
 	"github.com/theory/sqljson/path/ast"
 	"github.com/theory/sqljson/path/types"
@@ -18,24 +17,23 @@ func (exec *Executor) execMethodNode(
 	node *ast.MethodNode,
 	value any,
 	found *valueList,
-	unwrap bool,
 ) (resultStatus, error) {
 	switch name := node.Name(); name {
 	case ast.MethodNumber:
-		return exec.executeNumberMethod(ctx, node, value, found, unwrap, node)
+		return exec.executeNumberMethod(ctx, node, value, found, node)
 	case ast.MethodAbs:
 		return exec.executeNumericItemMethod(
-			ctx, node, value, unwrap,
+			ctx, node, value,
 			intAbs, math.Abs, found,
 		)
 	case ast.MethodFloor:
 		return exec.executeNumericItemMethod(
-			ctx, node, value, unwrap,
+			ctx, node, value,
 			intSelf, math.Floor, found,
 		)
 	case ast.MethodCeiling:
 		return exec.executeNumericItemMethod(
-			ctx, node, value, unwrap,
+			ctx, node, value,
 			intSelf, math.Ceil, found,
 		)
 	case ast.MethodType:
@@ -43,17 +41,19 @@ func (exec *Executor) execMethodNode(
 	case ast.MethodSize:
 		return exec.execMethodSize(ctx, node, value, found)
 	case ast.MethodDouble:
-		return exec.execMethodDouble(ctx, node, value, found, unwrap)
+		return exec.execMethodDouble(ctx, node, value, found)
 	case ast.MethodInteger:
-		return exec.execMethodInteger(ctx, node, value, found, unwrap)
+		return exec.execMethodInteger(ctx, node, value, found)
 	case ast.MethodBigInt:
-		return exec.execMethodBigInt(ctx, node, value, found, unwrap)
+		return exec.execMethodBigInt(ctx, node, value, found)
 	case ast.MethodString:
-		return exec.execMethodString(ctx, node, value, found, unwrap)
+		return exec.execMethodString(ctx, node, value, found)
 	case ast.MethodBoolean:
-		return exec.execMethodBoolean(ctx, node, value, found, unwrap)
+		return exec.execMethodBoolean(ctx, node, value, found)
 	case ast.MethodKeyValue:
-		return exec.executeKeyValueMethod(ctx, node, value, found, unwrap)
+		return exec.executeKeyValueMethod(ctx, node, value, found)
+	case ast.MethodCustom:
+		return exec.execCustomMethod(ctx, node, value, found)
 	default:
 		return statusFailed, fmt.Errorf(
 			"%w: unknown method %v", ErrInvalid, name,
@@ -61,6 +61,32 @@ func (exec *Executor) execMethodNode(
 	}
 }
 
+// unwrapsTarget returns true if node is an item method that lax mode applies
+// to each item of an array rather than to the array itself, as PostgreSQL
+// does for every item method but .type() and .size(). Lax mode unwraps only
+// one level, so an item method applied to a nested array raises the same
+// error as in strict mode.
+func unwrapsTarget(node ast.Node) bool {
+	switch node := node.(type) {
+	case *ast.MethodNode:
+		switch node.Name() {
+		case ast.MethodType, ast.MethodSize:
+			return false
+		default:
+			return true
+		}
+	case *ast.UnaryNode:
+		switch node.Operator() {
+		case ast.UnaryDateTime, ast.UnaryDate, ast.UnaryTime, ast.UnaryTimeTZ,
+			ast.UnaryTimestamp, ast.UnaryTimestampTZ:
+			return true
+		}
+	case *ast.BinaryNode:
+		return node.Operator() == ast.BinaryDecimal
+	}
+	return false
+}
+
 // execMethodType handles the execution of .type() by determining the type of
 // value and passing it to the next execution node.
 func (exec *Executor) execMethodType(
@@ -104,8 +130,9 @@ func (exec *Executor) execMethodType(
 
 // execMethodSize handles the execution of .size() by determining the size of
 // value and passing it to the next execution node. value's type should be
-// []any, but it will be passed on if exec.autoWrap returns true and
-// exec.ignoreStructuralErrors is true.
+// []any. Any other value has size 1 when exec.autoWrap returns true, and is
+// otherwise an error, unless exec.ignoreStructuralErrors is true, in which
+// case it returns statusNotFound.
 func (exec *Executor) execMethodSize(
 	ctx context.Context,
 	node *ast.MethodNode,
@@ -117,11 +144,14 @@ func (exec *Executor) execMethodSize(
 	case []any:
 		size = len(value)
 	default:
-		if !exec.autoWrap() && !exec.ignoreStructuralErrors {
+		if !exec.autoWrap() {
 			// https://github.com/postgres/postgres/blob/REL_17_2/src/backend/utils/adt/jsonpath_exec.c#L1114
+			if exec.ignoreStructuralErrors {
+				return statusNotFound, nil
+			}
 			return exec.returnVerboseError(fmt.Errorf(
 				"%w: jsonpath item method %v can only be applied to an array",
-				ErrVerbose, node.Name(),
+				errVerboseType, node.Name(),
 			))
 		}
 	}
@@ -129,28 +159,17 @@ func (exec *Executor) execMethodSize(
 }
 
 // execMethodDouble handles the execution of .double(). value must be a
-// numeric value or a string that can be parsed into a float64, or an array
-// ([]any) to which .double() will be applied to all of its values when unwrap
-// is true.
+// numeric value or a string that can be parsed into a float64.
 func (exec *Executor) execMethodDouble(
 	ctx context.Context,
 	node *ast.MethodNode,
 	value any,
 	found *valueList,
-	unwrap bool,
 ) (resultStatus, error) {
 	var double float64
 	name := node.Name()
 
 	switch val := value.(type) {
-	case []any:
-		if unwrap {
-			return exec.executeItemUnwrapTargetArray(ctx, node, value, found)
-		}
-		return exec.returnVerboseError(fmt.Errorf(
-			"%w: jsonpath item method %v can only be applied to a string or numeric value",
-			ErrVerbose, name,
-		))
 	case int64:
 		double = float64(val)
 	case float64:
@@ -159,41 +178,31 @@ func (exec *Executor) execMethodDouble(
 		var err error
 		double, err = val.Float64()
 		if err != nil {
-			return statusFailed, fmt.Errorf(
-				`%w: argument %q of jsonpath item method %v is invalid for type double precision`,
-				ErrExecution, val, name,
-			)
+			return exec.returnVerboseError(invalidArgumentErr(errVerboseNumeric, val, name, "double precision"))
 		}
 	case string:
 		var err error
 		double, err = strconv.ParseFloat(val, 64)
 		if err != nil {
-			return statusFailed, fmt.Errorf(
-				`%w: argument %q of jsonpath item method %v is invalid for type double precision`,
-				ErrExecution, val, name,
-			)
+			return exec.returnVerboseError(invalidArgumentErr(errVerboseNumeric, val, name, "double precision"))
 		}
 	default:
 		return exec.returnVerboseError(fmt.Errorf(
 			"%w: jsonpath item method %v can only be applied to a string or numeric value",
-			ErrVerbose, name,
+			errVerboseType, name,
 		))
 	}
 
-	if math.IsInf(double, 0) || math.IsNaN(double) {
-		return exec.returnVerboseError(fmt.Errorf(
-			"%w: NaN or Infinity is not allowed for jsonpath item method %v",
-			ErrVerbose, name,
-		))
+	if !isFinite(double) {
+		return exec.returnVerboseError(nonFiniteErr("item method", name))
 	}
 
-	return exec.executeNextItem(ctx, node, nil, double, found)
+	return exec.executeNextItem(ctx, node, nil, unsignedZero(double), found)
 }
 
 // execMethodInteger handles the execution of .integer(). value must be a
-// numeric value or a string that can be parsed into an int32, or an array
-// ([]any) to which .integer() will be applied to all of its values when
-// unwrap is true. The value must be within the bounds of int32; returns a
+// numeric value or a string that can be parsed into an int32. The value
+// must be within the bounds of int32; returns a
 // value of int64 since to allow its processing by other parts of the
 // executor, which does not handle int32.
 func (exec *Executor) execMethodInteger(
@@ -201,119 +210,128 @@ func (exec *Executor) execMethodInteger(
 	node *ast.MethodNode,
 	value any,
 	found *valueList,
-	unwrap bool,
 ) (resultStatus, error) {
 	var (
 		integer int64
-		err     error
+		ok      bool
 	)
 
 	switch val := value.(type) {
-	case []any:
-		if unwrap {
-			return exec.executeItemUnwrapTargetArray(ctx, node, value, found)
-		}
-		return exec.returnVerboseError(fmt.Errorf(
-			"%w: jsonpath item method %v can only be applied to a string or numeric value",
-			ErrVerbose, node.Name(),
-		))
 	case int64:
-		integer = val
+		integer, ok = val, true
 	case float64:
-		integer = int64(math.Round(val))
+		integer, ok = floatToInt64(val)
 	case json.Number:
-		integer, err = val.Int64()
-		if err != nil {
-			var f float64
-			f, err = val.Float64()
-			if err == nil {
-				integer = int64(math.Round(f))
-			}
-		}
+		integer, ok = jsonNumberToInt64(val)
 	case string:
+		var err error
 		integer, err = strconv.ParseInt(val, 10, 32)
+		ok = err == nil
 	default:
 		return exec.returnVerboseError(fmt.Errorf(
 			"%w: jsonpath item method %v can only be applied to a string or numeric value",
-			ErrVerbose, node.Name(),
+			errVerboseType, node.Name(),
 		))
 	}
 
-	if err != nil || integer > math.MaxInt32 || integer < math.MinInt32 {
-		return exec.returnVerboseError(fmt.Errorf(
-			`%w: argument "%v" of jsonpath item method %v is invalid for type integer`,
-			ErrVerbose, value, node.Name(),
-		))
+	if !ok || integer > math.MaxInt32 || integer < math.MinInt32 {
+		return exec.returnVerboseError(invalidArgumentErr(errVerboseNumeric, value, node.Name(), "integer"))
 	}
 
 	return exec.executeNextItem(ctx, node, nil, integer, found)
 }
 
 // execMethodBigInt handles the execution of .bigint(). value must be a
-// numeric value or a string that can be parsed into an int64, or an array
-// ([]any) to which .bigint() will be applied to all of its values when unwrap
-// is true.
+// numeric value or a string that can be parsed into an int64.
 func (exec *Executor) execMethodBigInt(
 	ctx context.Context,
 	node *ast.MethodNode,
 	value any,
 	found *valueList,
-	unwrap bool,
 ) (resultStatus, error) {
-	var bigInt int64
+	var (
+		bigInt int64
+		ok     bool
+	)
 
 	switch val := value.(type) {
-	case []any:
-		if unwrap {
-			return exec.executeItemUnwrapTargetArray(ctx, node, value, found)
-		}
-		return exec.returnVerboseError(fmt.Errorf(
-			"%w: jsonpath item method %v can only be applied to a string or numeric value",
-			ErrVerbose, node.Name(),
-		))
 	case int64:
-		bigInt = val
+		bigInt, ok = val, true
 	case float64:
-		if val > math.MaxInt64 || val < math.MinInt64 || math.IsInf(val, 0) || math.IsNaN(val) {
-			return exec.returnVerboseError(fmt.Errorf(
-				`%w: argument "%v" of jsonpath item method %v is invalid for type bigint`,
-				ErrVerbose, val, node.Name(),
-			))
-		}
-		bigInt = int64(math.Round(val))
+		bigInt, ok = floatToInt64(val)
 	case json.Number:
-		var err error
-		bigInt, err = val.Int64()
-		if err != nil {
-			var f float64
-			f, err = val.Float64()
-			if err != nil || f > math.MaxInt64 || f < math.MinInt64 || math.IsInf(f, 0) || math.IsNaN(f) {
-				return exec.returnVerboseError(fmt.Errorf(
-					`%w: argument "%v" of jsonpath item method %v is invalid for type bigint`,
-					ErrVerbose, val, node.Name(),
-				))
-			}
-			bigInt = int64(math.Round(f))
-		}
+		bigInt, ok = jsonNumberToInt64(val)
 	case string:
 		var err error
 		bigInt, err = strconv.ParseInt(val, 10, 64)
-		if err != nil {
-			return exec.returnVerboseError(fmt.Errorf(
-				`%w: argument "%v" of jsonpath item method %v is invalid for type bigint`,
-				ErrVerbose, val, node.Name(),
-			))
-		}
+		ok = err == nil
 	default:
 		return exec.returnVerboseError(fmt.Errorf(
 			"%w: jsonpath item method %v can only be applied to a string or numeric value",
-			ErrVerbose, node.Name(),
+			errVerboseType, node.Name(),
 		))
 	}
 
+	if !ok {
+		return exec.returnVerboseError(invalidArgumentErr(errVerboseNumeric, value, node.Name(), "bigint"))
+	}
+
 	return exec.executeNextItem(ctx, node, nil, bigInt, found)
 }
 
+// floatToInt64 returns f rounded half away from zero to an integer, and
+// false if f is NaN or the result is out of the range of int64.
+func floatToInt64(f float64) (int64, bool) {
+	// Unlike math.MaxInt64, -math.MinInt64 (2^63) is exactly representable
+	// as a float64.
+	if math.IsNaN(f) || f >= -math.MinInt64 || f < math.MinInt64 {
+		return 0, false
+	}
+	return int64(math.Round(f)), true
+}
+
+// jsonNumberToInt64 returns num rounded half away from zero to an integer,
+// and false if num is not a valid number or the result is out of the range
+// of int64. Unlike converting num to a float64, it uses all of num's digits,
+// as PostgreSQL does for numeric values.
+func jsonNumberToInt64(num json.Number) (int64, bool) {
+	dec, ok := parseDecimal(string(num))
+	if !ok {
+		return 0, false
+	}
+	return dec.int64()
+}
+
+// invalidArgumentErr creates an error wrapping err for value, an argument to
+// the item method named by method that is invalid for the PostgreSQL type
+// typ. It formats value with argumentText.
+func invalidArgumentErr(err error, value, method any, typ string) error {
+	return fmt.Errorf(
+		`%w: argument "%v" of jsonpath item method %v is invalid for type %v`,
+		err, argumentText(value), method, typ,
+	)
+}
+
+// argumentText returns value as PostgreSQL displays an item method argument
+// in error messages: the text of a [json.Number] and a string verbatim,
+// without escapes or trimmed whitespace, an int64 in decimal, and a float64
+// in the shortest decimal that round-trips to it, without an exponent, as
+// PostgreSQL formats numeric values.
+func argumentText(value any) string {
+	switch val := value.(type) {
+	case json.Number:
+		return string(val)
+	case string:
+		return val
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(unsignedZero(val), 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
 // execMethodString handles the execution of .string(). value must be a
 // string, number, boolean, or able to be cast to a string.
 func (exec *Executor) execMethodString(
@@ -321,30 +339,24 @@ func (exec *Executor) execMethodString(
 	node *ast.MethodNode,
 	value any,
 	found *valueList,
-	unwrap bool,
 ) (resultStatus, error) {
 	var str string
 	name := node.Name()
 
 	switch val := value.(type) {
-	case []any:
-		if unwrap {
-			return exec.executeItemUnwrapTargetArray(ctx, node, value, found)
-		}
-		return exec.returnVerboseError(fmt.Errorf(
-			`%w: jsonpath item method %v can only be applied to a boolean, string, numeric, or datetime value`,
-			ErrVerbose, node.Name(),
-		))
 	case string:
-		str = val
+		var err error
+		if str, err = exec.validUTF8(val); err != nil {
+			return exec.returnError(err)
+		}
 	case types.DateTime:
-		str = val.String()
+		str = val.FormatStyle(exec.dateStyle)
 	case json.Number:
-		str = val.String()
+		str = unsignedZeroNumber(val).String()
 	case int64:
 		str = strconv.FormatInt(val, 10)
 	case float64:
-		str = strconv.FormatFloat(val, 'f', -1, 64)
+		str = strconv.FormatFloat(unsignedZero(val), 'f', -1, 64)
 	case bool:
 		if val {
 			str = "true"
@@ -354,7 +366,7 @@ func (exec *Executor) execMethodString(
 	default:
 		return exec.returnVerboseError(fmt.Errorf(
 			`%w: jsonpath item method %v can only be applied to a boolean, string, numeric, or datetime value`,
-			ErrVerbose, name,
+			errVerboseType, name,
 		))
 	}
 
@@ -363,49 +375,32 @@ func (exec *Executor) execMethodString(
 
 // execMethodBoolean handles the execution of .boolean(). value must be a
 // string, number, boolean, or able to be cast to a bool, int64, float64,
-// [json.Number], or string — or an array ([]any) to which .boolean() will be
-// applied to all of its values when unwrap is true. String values will be
-// converted to bool by [execBooleanString].
+// [json.Number], or string. String values will be converted to bool by
+// [execBooleanString].
 func (exec *Executor) execMethodBoolean(
 	ctx context.Context,
 	node *ast.MethodNode,
 	value any,
 	found *valueList,
-	unwrap bool,
 ) (resultStatus, error) {
 	var boolean bool
 	name := node.Name()
 
 	switch val := value.(type) {
-	case []any:
-		if unwrap {
-			return exec.executeItemUnwrapTargetArray(ctx, node, value, found)
-		}
-		// https://github.com/postgres/postgres/blob/REL_17_2/src/backend/utils/adt/jsonpath_exec.c#L1386
-		return exec.returnVerboseError(fmt.Errorf(
-			"%w: jsonpath item method %v can only be applied to a boolean, string, or numeric value",
-			ErrVerbose, name,
-		))
 	case bool:
 		boolean = val
 	case int64:
 		boolean = val != 0
 	case float64:
 		if val != math.Trunc(val) {
-			return exec.returnVerboseError(fmt.Errorf(
-				`%w: argument "%v" of jsonpath item method %v is invalid for type boolean`,
-				ErrVerbose, val, name,
-			))
+			return exec.returnVerboseError(invalidArgumentErr(errVerboseType, val, name, "boolean"))
 		}
 		boolean = val != 0
 
 	case json.Number:
 		num, err := val.Float64()
 		if err != nil || num != math.Trunc(num) {
-			return exec.returnVerboseError(fmt.Errorf(
-				`%w: argument %q of jsonpath item method %v is invalid for type boolean`,
-				ErrVerbose, val, name,
-			))
+			return exec.returnVerboseError(invalidArgumentErr(errVerboseType, val, name, "boolean"))
 		}
 		boolean = num != 0
 	case string:
@@ -418,88 +413,97 @@ func (exec *Executor) execMethodBoolean(
 	default:
 		return exec.returnVerboseError(fmt.Errorf(
 			"%w: jsonpath item method %v can only be applied to a boolean, string, or numeric value",
-			ErrVerbose, name,
+			errVerboseType, name,
 		))
 	}
 
 	return exec.executeNextItem(ctx, node, nil, boolean, found)
 }
 
-// execBooleanString converts val to a boolean. The value of val must
-// case-insensitively match one of:
-//   - t
-//   - true
-//   - f
-//   - false
-//   - y
-//   - yes
-//   - n
-//   - no
-//   - on
-//   - off
-//   - 1
-//   - 0
+// execBooleanString converts val to a boolean following the rules of the
+// PostgreSQL parse_bool() function. The value of val must be, ignoring
+// ASCII case, one of:
+//   - a prefix of "true" or "yes", or "1", which convert to true
+//   - a prefix of "false" or "no", or "0", which convert to false
+//   - a prefix of "on" at least two characters long, i.e., "on", which
+//     converts to true
+//   - a prefix of "off" at least two characters long, i.e., "of" or "off",
+//     which convert to false
+//
+// Like parse_bool(), and unlike the PostgreSQL boolean type input function,
+// it does not trim whitespace, so " true" is invalid.
 func execBooleanString(val string, name ast.MethodName) (bool, error) {
-	size := len(val)
-	if size == 0 {
-		return false, fmt.Errorf(
-			`%w: argument %q of jsonpath item method %v is invalid for type boolean`,
-			ErrVerbose, val, name,
-		)
+	if val != "" {
+		switch val[0] {
+		case 't', 'T':
+			if isBoolPrefix(val, "true") {
+				return true, nil
+			}
+		case 'f', 'F':
+			if isBoolPrefix(val, "false") {
+				return false, nil
+			}
+		case 'y', 'Y':
+			if isBoolPrefix(val, "yes") {
+				return true, nil
+			}
+		case 'n', 'N':
+			if isBoolPrefix(val, "no") {
+				return false, nil
+			}
+		case 'o', 'O':
+			// "o" alone is ambiguous.
+			if len(val) > 1 {
+				if isBoolPrefix(val, "on") {
+					return true, nil
+				} else if isBoolPrefix(val, "off") {
+					return false, nil
+				}
+			}
+		case '1':
+			if len(val) == 1 {
+				return true, nil
+			}
+		case '0':
+			if len(val) == 1 {
+				return false, nil
+			}
+		}
 	}
 
-	switch val[0] {
-	case 't', 'T':
-		if size == 1 || strings.EqualFold(val, "true") {
-			return true, nil
-		}
-	case 'f', 'F':
-		if size == 1 || strings.EqualFold(val, "false") {
-			return false, nil
-		}
-	case 'y', 'Y':
-		if size == 1 || strings.EqualFold(val, "yes") {
-			return true, nil
-		}
-	case 'n', 'N':
-		if size == 1 || strings.EqualFold(val, "no") {
-			return false, nil
-		}
-	case 'o', 'O':
-		if strings.EqualFold(val, "on") {
-			return true, nil
-		} else if strings.EqualFold(val, "off") {
-			return false, nil
-		}
-	case '1':
-		if size == 1 {
-			return true, nil
+	return false, invalidArgumentErr(errVerboseType, val, name, "boolean")
+}
+
+// isBoolPrefix returns true if val is a prefix of word, a lowercase ASCII
+// string, ignoring ASCII case. Like the PostgreSQL pg_strncasecmp()
+// function, it folds only ASCII letters.
+func isBoolPrefix(val, word string) bool {
+	if len(val) > len(word) {
+		return false
+	}
+	for i := range len(val) {
+		c := val[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
 		}
-	case '0':
-		if size == 1 {
-			return false, nil
+		if c != word[i] {
+			return false
 		}
 	}
-
-	return false, fmt.Errorf(
-		`%w: argument %q of jsonpath item method %v is invalid for type boolean`,
-		ErrVerbose, val, name,
-	)
+	return true
 }
 
 // executeNumberMethod implements the number() and decimal() methods. It
 // varies somewhat from Postgres because Postgres uses its arbitrary precision
-// numeric type, which can be huge and precise, while we use only float64 and
-// int64 values. If we ever switch to the github.com/shopspring/decimal
-// package we could make it more precise and therefore compatible, at least
-// when numbers are parsed into [json.Number]. The method parameter should
-// stringify to `.number()` or `.decimal()` as appropriate.
+// numeric type, which can be huge and precise, while we use float64 values,
+// except for .decimal() with a precision, which returns a [json.Number]
+// formatted with its scale. The method parameter should stringify to
+// `.number()` or `.decimal()` as appropriate.
 func (exec *Executor) executeNumberMethod(
 	ctx context.Context,
 	node ast.Node,
 	value any,
 	found *valueList,
-	unwrap bool,
 	method any,
 ) (resultStatus, error) {
 	var (
@@ -508,14 +512,6 @@ func (exec *Executor) executeNumberMethod(
 	)
 
 	switch val := value.(type) {
-	case []any:
-		if unwrap {
-			return exec.executeItemUnwrapTargetArray(ctx, node, val, found)
-		}
-		return exec.returnVerboseError(fmt.Errorf(
-			`%w: jsonpath item method %v can only be applied to a string or numeric value`,
-			ErrVerbose, method,
-		))
 	case float64:
 		num = val
 	case int64:
@@ -528,32 +524,27 @@ func (exec *Executor) executeNumberMethod(
 	default:
 		return exec.returnVerboseError(fmt.Errorf(
 			`%w: jsonpath item method %v can only be applied to a string or numeric value`,
-			ErrVerbose, method,
+			errVerboseType, method,
 		))
 	}
 
 	if err != nil {
-		return exec.returnVerboseError(fmt.Errorf(
-			`%w: argument "%v" of jsonpath item method %v is invalid for type numeric`,
-			ErrVerbose, value, method,
-		))
+		return exec.returnVerboseError(invalidArgumentErr(errVerboseNumeric, value, method, "numeric"))
 	}
 
-	if math.IsInf(num, 0) || math.IsNaN(num) {
-		return exec.returnVerboseError(fmt.Errorf(
-			"%w: NaN or Infinity is not allowed for jsonpath item method %v",
-			ErrVerbose, method,
-		))
+	if !isFinite(num) {
+		return exec.returnVerboseError(nonFiniteErr("item method", method))
 	}
 
 	if node, ok := node.(*ast.BinaryNode); ok {
-		num, err = exec.executeDecimalMethod(node, value, num)
+		res, err := exec.executeDecimalMethod(node, value, num)
 		if err != nil {
 			return exec.returnError(err)
 		}
+		return exec.executeNextItem(ctx, node, nil, res, found)
 	}
 
-	return exec.executeNextItem(ctx, node, nil, num, found)
+	return exec.executeNextItem(ctx, node, nil, unsignedZero(num), found)
 }
 
 // https://github.com/postgres/postgres/blob/REL_17_2/src/include/utils/numeric.h#L32-L35
@@ -565,13 +556,15 @@ const (
 
 // executeDecimalMethod processes the arguments to the .decimal() method,
 // which must have the precision and optional scale. It converts them to
-// int32, formats the number as string and then parses back into a float,
-// which it returns.
+// int32 and rounds value, or num if value is not a [json.Number] or string,
+// to the scale. Returns num if the method has no arguments, and otherwise
+// the rounded number as a [json.Number] with scale fractional digits, so
+// that it marshals like a PostgreSQL numeric value.
 func (exec *Executor) executeDecimalMethod(
 	node *ast.BinaryNode,
 	value any,
 	num float64,
-) (float64, error) {
+) (any, error) {
 	op := node.Operator()
 	if op != ast.BinaryDecimal || node.Left() == nil {
 		return num, nil
@@ -579,15 +572,15 @@ func (exec *Executor) executeDecimalMethod(
 
 	precision, err := getNodeInt32(node.Left(), op, "precision")
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// Verify the precision
 	// https://github.com/postgres/postgres/blob/REL_17_2/src/backend/utils/adt/numeric.c#L1333-L1337
 	if precision < 1 || precision > numericMaxPrecision {
-		return 0, fmt.Errorf(
+		return nil, fmt.Errorf(
 			"%w: NUMERIC precision %d must be between 1 and %d",
-			ErrExecution, precision, numericMaxPrecision,
+			ErrNumeric, precision, numericMaxPrecision,
 		)
 	}
 
@@ -596,67 +589,79 @@ func (exec *Executor) executeDecimalMethod(
 		var err error
 		scale, err = getNodeInt32(right, op, "scale")
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 
 		// Verify the scale.
 		// https://github.com/postgres/postgres/blob/REL_17_2/src/backend/utils/adt/numeric.c#L1338-L1342
 		if scale < numericMinScale || scale > numericMaxScale {
-			return 0, fmt.Errorf(
+			return nil, fmt.Errorf(
 				"%w: NUMERIC scale %d must be between %d and %d",
-				ErrExecution, scale, numericMinScale, numericMaxScale,
+				ErrNumeric, scale, numericMinScale, numericMaxScale,
 			)
 		}
 	}
 
-	// Round to the scale.
-	ratio := math.Pow10(scale)
-	rounded := math.Round(num*ratio) / ratio
-
-	// Count the digits before the decimal point.
-	numStr := strconv.FormatFloat(rounded, 'f', -1, 64)
-	count := 0
-	for _, ch := range numStr {
-		if ch == '.' {
-			break
-		}
-		if '1' <= ch && ch <= '9' {
-			count++
-		}
+	// Use all the digits of json.Number and string values, since converting
+	// them to float64 may lose precision.
+	var text string
+	switch val := value.(type) {
+	case json.Number:
+		text = string(val)
+	case string:
+		text = val
+	}
+	dec, ok := parseDecimal(text)
+	if !ok {
+		dec, _ = parseDecimal(strconv.FormatFloat(num, 'g', -1, 64))
 	}
 
-	// Make sure it's got no more than precision digits.
-	if count > 0 && count > precision-scale {
-		return 0, fmt.Errorf(
-			`%w: argument "%v" of jsonpath item method %v is invalid for type numeric`,
-			ErrVerbose, value, op,
-		)
+	// Make sure it's got no more than precision-scale integer digits after
+	// rounding.
+	if exp, nonZero := dec.roundedExp(scale); nonZero && exp > precision-scale {
+		return nil, invalidArgumentErr(errVerboseNumeric, value, op, "numeric")
 	}
-	return rounded, nil
+
+	return json.Number(dec.round(scale).text(scale)), nil
 }
 
-// intCallback defines a callback to carry out an operation on an int64.
-type intCallback func(int64) int64
+// intCallback defines a callback to carry out an operation on an int64. It
+// returns an int64, or a [json.Number] for a result outside the range of
+// int64.
+type intCallback func(int64) any
 
 // floatCallback defines a callback to carry out an operation on a float64.
 type floatCallback func(float64) float64
 
+// negMinInt64 is the negation of math.MinInt64, which is outside the range
+// of int64.
+const negMinInt64 = json.Number("9223372036854775808")
+
 // intAbs returns the absolute value of x. Implements intCallback.
-func intAbs(x int64) int64 {
-	if x < 0 {
+func intAbs(x int64) any {
+	switch {
+	case x == math.MinInt64:
+		return negMinInt64
+	case x < 0:
 		return -x
+	default:
+		return x
 	}
-	return x
 }
 
 // intSelf returns x. Implements intCallback.
-func intSelf(x int64) int64 { return x }
+func intSelf(x int64) any { return x }
 
 // floatSelf returns x.  Implements floatCallback.
 func floatSelf(x float64) float64 { return x }
 
 // intUMinus applies unary minus to x. Implements intCallback.
-func intUMinus(x int64) int64 { return -x }
+func intUMinus(x int64) any {
+	if x == math.MinInt64 {
+		return negMinInt64
+	}
+	return -x
+}
 
 // floatUMinus applies unary minus to x. Implements floatCallback.
 func floatUMinus(x float64) float64 { return -x }
@@ -667,7 +672,6 @@ func (exec *Executor) executeNumericItemMethod(
 	ctx context.Context,
 	node ast.Node,
 	value any,
-	unwrap bool,
 	intCallback intCallback,
 	floatCallback floatCallback,
 	found *valueList,
@@ -675,14 +679,6 @@ func (exec *Executor) executeNumericItemMethod(
 	var num any
 
 	switch val := value.(type) {
-	case []any:
-		if unwrap {
-			return exec.executeItemUnwrapTargetArray(ctx, node, value, found)
-		}
-		return exec.returnVerboseError(fmt.Errorf(
-			"%w: jsonpath item method %v can only be applied to a numeric value",
-			ErrVerbose, node,
-		))
 	case int64:
 		num = intCallback(val)
 	case float64:
@@ -695,15 +691,22 @@ func (exec *Executor) executeNumericItemMethod(
 		} else {
 			return exec.returnVerboseError(fmt.Errorf(
 				"%w: jsonpath item method %v can only be applied to a numeric value",
-				ErrVerbose, node,
+				errVerboseType, node,
 			))
 		}
 	default:
 		return exec.returnVerboseError(fmt.Errorf(
 			"%w: jsonpath item method %v can only be applied to a numeric value",
-			ErrVerbose, node,
+			errVerboseType, node,
 		))
 	}
 
+	if f, ok := num.(float64); ok {
+		if !isFinite(f) {
+			return exec.returnVerboseError(nonFiniteErr("item method", node))
+		}
+		num = unsignedZero(f)
+	}
+
 	return exec.executeNextItem(ctx, node, node.Next(), num, found)
 }
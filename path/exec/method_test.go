@@ -23,9 +23,8 @@ func TestExecMethodNode(t *testing.T) {
 	path, _ := parser.Parse("$")
 	ctx := context.Background()
 
-	// Offset of object in a slice is non-determinate, so calculate it at runtime.
 	value := []any{map[string]any{"x": true, "y": "hi"}}
-	offset := deltaBetween(value, value[0])
+	offset := int64(1)
 
 	for _, tc := range []struct {
 		name   string
@@ -333,11 +332,11 @@ func TestExecMethodNode(t *testing.T) {
 			// Set up an executor.
 			e := newTestExecutor(path, nil, true, false)
 			e.root = tc.value
-			_ = e.setTempBaseObject(e.root, 0)
+			_ = e.setTempBaseObject(0)
 
 			// Test execKeyNode with a list.
 			list := newList()
-			res, err := e.execMethodNode(ctx, node, tc.value, list, tc.unwrap)
+			res, err := e.executeNode(ctx, node, tc.value, list, tc.unwrap)
 			a.Equal(tc.exp, res)
 
 			// Check the error and list.
@@ -351,7 +350,7 @@ func TestExecMethodNode(t *testing.T) {
 			}
 
 			// Try with nil found.
-			res, err = e.execMethodNode(ctx, node, tc.value, nil, tc.unwrap)
+			res, err = e.executeNode(ctx, node, tc.value, nil, tc.unwrap)
 			a.Equal(tc.exp, res)
 			if tc.isErr == nil {
 				r.NoError(err)
@@ -611,20 +610,366 @@ func TestExecMethodSize(t *testing.T) {
 	}
 }
 
+func TestSizeMethodModes(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	const (
+		sizeErr     = "exec: jsonpath item method .size() can only be applied to an array"
+		wildcardErr = "exec: jsonpath wildcard array accessor can only be applied to an array"
+		keyErr      = `exec: JSON object does not contain key "a"`
+	)
+
+	// Each result corresponds to the path at the same index, compared to
+	// the output of PostgreSQL's jsonb_path_query().
+	paths := []string{
+		"strict $.a.size()",
+		"lax $.a.size()",
+		"strict $.a[*].size()",
+		"lax $.a[*].size()",
+		"strict $.a.**.size()",
+		"lax $.a.**.size()",
+	}
+
+	type result struct {
+		exp []any
+		err string
+	}
+
+	for _, tc := range []struct {
+		name string
+		json string
+		res  []result
+	}{
+		{
+			name: "object",
+			json: `{"a": {"b": 1}}`,
+			res: []result{
+				{err: sizeErr},
+				{exp: []any{int64(1)}},
+				{err: wildcardErr},
+				{exp: []any{int64(1)}},
+				{exp: []any{}},
+				{exp: []any{int64(1), int64(1)}},
+			},
+		},
+		{
+			name: "string",
+			json: `{"a": "x"}`,
+			res: []result{
+				{err: sizeErr},
+				{exp: []any{int64(1)}},
+				{err: wildcardErr},
+				{exp: []any{int64(1)}},
+				{exp: []any{}},
+				{exp: []any{int64(1)}},
+			},
+		},
+		{
+			name: "number",
+			json: `{"a": 42}`,
+			res: []result{
+				{err: sizeErr},
+				{exp: []any{int64(1)}},
+				{err: wildcardErr},
+				{exp: []any{int64(1)}},
+				{exp: []any{}},
+				{exp: []any{int64(1)}},
+			},
+		},
+		{
+			name: "bool",
+			json: `{"a": true}`,
+			res: []result{
+				{err: sizeErr},
+				{exp: []any{int64(1)}},
+				{err: wildcardErr},
+				{exp: []any{int64(1)}},
+				{exp: []any{}},
+				{exp: []any{int64(1)}},
+			},
+		},
+		{
+			name: "null",
+			json: `{"a": null}`,
+			res: []result{
+				{err: sizeErr},
+				{exp: []any{int64(1)}},
+				{err: wildcardErr},
+				{exp: []any{int64(1)}},
+				{exp: []any{}},
+				{exp: []any{int64(1)}},
+			},
+		},
+		{
+			name: "missing_key",
+			json: `{"b": [1]}`,
+			res: []result{
+				{err: keyErr},
+				{exp: []any{}},
+				{err: keyErr},
+				{exp: []any{}},
+				{err: keyErr},
+				{exp: []any{}},
+			},
+		},
+		{
+			name: "empty_array",
+			json: `{"a": []}`,
+			res: []result{
+				{exp: []any{int64(0)}},
+				{exp: []any{int64(0)}},
+				{exp: []any{}},
+				{exp: []any{}},
+				{exp: []any{int64(0)}},
+				{exp: []any{int64(0)}},
+			},
+		},
+		{
+			name: "array",
+			json: `{"a": [1, [2, 3], {"b": 4}]}`,
+			res: []result{
+				{exp: []any{int64(3)}},
+				{exp: []any{int64(3)}},
+				{err: sizeErr},
+				{exp: []any{int64(1), int64(2), int64(1)}},
+				{exp: []any{int64(3), int64(2)}},
+				{exp: []any{int64(3), int64(1), int64(2), int64(1), int64(1), int64(1), int64(1)}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r := require.New(t)
+			r.Len(tc.res, len(paths))
+
+			for i, res := range tc.res {
+				path, err := parser.Parse(paths[i])
+				r.NoError(err)
+				value := js(tc.json)
+
+				got, err := Query(ctx, path, value)
+				if res.err != "" {
+					r.EqualError(err, res.err, paths[i])
+					r.ErrorIs(err, ErrVerbose, paths[i])
+
+					// Silent mode suppresses the error.
+					got, err = Query(ctx, path, value, WithSilent())
+					r.NoError(err, paths[i])
+					r.Equal([]any{}, got, paths[i])
+				} else {
+					r.NoError(err, paths[i])
+					r.Equal(res.exp, got, paths[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMethodUnwrapModes(t *testing.T) {
+	t.Parallel()
+	registerTestMethods()
+	ctx := context.Background()
+
+	// Each item method is applied to a scalar, an array of two scalars, a
+	// nested array, and an empty array in strict and lax modes. As in
+	// PostgreSQL's jsonb_path_query(), lax mode applies the method to each
+	// item of an array, but unwraps only one level, so that nested arrays
+	// raise the same error as arrays in strict mode. .type() and .size()
+	// never unwrap arrays.
+	for _, tc := range []struct {
+		method string
+		scalar string
+		exp    any
+		err    string
+	}{
+		{
+			method: "double()",
+			scalar: `"1.5"`,
+			exp:    float64(1.5),
+			err:    "jsonpath item method .double() can only be applied to a string or numeric value",
+		},
+		{
+			method: "integer()",
+			scalar: `"42"`,
+			exp:    int64(42),
+			err:    "jsonpath item method .integer() can only be applied to a string or numeric value",
+		},
+		{
+			method: "bigint()",
+			scalar: `42.4`,
+			exp:    int64(42),
+			err:    "jsonpath item method .bigint() can only be applied to a string or numeric value",
+		},
+		{
+			method: "number()",
+			scalar: `"1.5"`,
+			exp:    float64(1.5),
+			err:    "jsonpath item method .number() can only be applied to a string or numeric value",
+		},
+		{
+			method: "decimal(3, 1)",
+			scalar: `1.25`,
+			exp:    json.Number("1.3"),
+			err:    "jsonpath item method .decimal() can only be applied to a string or numeric value",
+		},
+		{
+			method: "abs()",
+			scalar: `-3`,
+			exp:    float64(3),
+			err:    "jsonpath item method .abs() can only be applied to a numeric value",
+		},
+		{
+			method: "floor()",
+			scalar: `1.5`,
+			exp:    float64(1),
+			err:    "jsonpath item method .floor() can only be applied to a numeric value",
+		},
+		{
+			method: "ceiling()",
+			scalar: `1.5`,
+			exp:    float64(2),
+			err:    "jsonpath item method .ceiling() can only be applied to a numeric value",
+		},
+		{
+			method: "string()",
+			scalar: `true`,
+			exp:    "true",
+			err:    "jsonpath item method .string() can only be applied to a boolean, string, numeric, or datetime value",
+		},
+		{
+			method: "boolean()",
+			scalar: `"yes"`,
+			exp:    true,
+			err:    "jsonpath item method .boolean() can only be applied to a boolean, string, or numeric value",
+		},
+		{
+			method: "keyvalue().key",
+			scalar: `{"k": 1}`,
+			exp:    "k",
+			err:    "jsonpath item method .keyvalue() can only be applied to an object",
+		},
+		{
+			method: "datetime().string()",
+			scalar: `"2024-06-06"`,
+			exp:    "2024-06-06",
+			err:    "jsonpath item method .datetime() can only be applied to a string",
+		},
+		{
+			method: "date().string()",
+			scalar: `"2024-06-06"`,
+			exp:    "2024-06-06",
+			err:    "jsonpath item method .date() can only be applied to a string",
+		},
+		{
+			method: "time().string()",
+			scalar: `"12:34:56"`,
+			exp:    "12:34:56",
+			err:    "jsonpath item method .time() can only be applied to a string",
+		},
+		{
+			method: "time_tz().string()",
+			scalar: `"12:34:56+01"`,
+			exp:    "12:34:56+01:00",
+			err:    "jsonpath item method .time_tz() can only be applied to a string",
+		},
+		{
+			method: "timestamp().string()",
+			scalar: `"2024-06-06 12:34:56"`,
+			exp:    "2024-06-06T12:34:56",
+			err:    "jsonpath item method .timestamp() can only be applied to a string",
+		},
+		{
+			method: "timestamp_tz().string()",
+			scalar: `"2024-06-06 12:34:56+01"`,
+			exp:    "2024-06-06T12:34:56+01:00",
+			err:    "jsonpath item method .timestamp_tz() can only be applied to a string",
+		},
+		{
+			method: "test_upper()",
+			scalar: `"hi"`,
+			exp:    "HI",
+			err:    `jsonpath item method .test_upper() failed: not a string`,
+		},
+	} {
+		t.Run(tc.method, func(t *testing.T) {
+			t.Parallel()
+			r := require.New(t)
+			errMsg := "exec: " + tc.err
+
+			for _, in := range []struct {
+				name string
+				json string
+				lax  []any // nil for an error
+			}{
+				{name: "scalar", json: tc.scalar, lax: []any{tc.exp}},
+				{name: "array", json: "[" + tc.scalar + ", " + tc.scalar + "]", lax: []any{tc.exp, tc.exp}},
+				{name: "nested", json: "[[" + tc.scalar + "]]"},
+				{name: "empty", json: "[]", lax: []any{}},
+			} {
+				value := js(`{"a": ` + in.json + `}`)
+				for _, mode := range []string{"lax", "strict"} {
+					path, err := parser.Parse(mode + " $.a." + tc.method)
+					r.NoError(err)
+
+					got, err := Query(ctx, path, value)
+					switch {
+					case in.name == "scalar":
+						r.NoError(err, path)
+						r.Equal(in.lax, got, path)
+					case mode == "lax" && in.lax != nil:
+						r.NoError(err, path)
+						r.Equal(in.lax, got, path)
+					default:
+						r.EqualError(err, errMsg, path)
+						r.ErrorIs(err, ErrVerbose, path)
+					}
+				}
+			}
+		})
+	}
+
+	// .type() and .size() apply to arrays themselves in both modes.
+	for _, tc := range []struct {
+		json string
+		typ  string
+		size int64
+	}{
+		{json: `"x"`, typ: "string", size: 1},
+		{json: `["x", "y"]`, typ: "array", size: 2},
+		{json: `[["x"]]`, typ: "array", size: 1},
+		{json: `[]`, typ: "array", size: 0},
+	} {
+		t.Run("type_size_"+tc.json, func(t *testing.T) {
+			t.Parallel()
+			r := require.New(t)
+			value := js(`{"a": ` + tc.json + `}`)
+			for _, mode := range []string{"lax", "strict"} {
+				path, err := parser.Parse(mode + " $.a.type()")
+				r.NoError(err)
+				got, err := Query(ctx, path, value)
+				r.NoError(err, path)
+				r.Equal([]any{tc.typ}, got, path)
+
+				path, err = parser.Parse(mode + " $.a.size()")
+				r.NoError(err)
+				got, err = Query(ctx, path, value)
+				if mode == "strict" && tc.typ != "array" {
+					r.EqualError(err, "exec: jsonpath item method .size() can only be applied to an array", path)
+				} else {
+					r.NoError(err, path)
+					r.Equal([]any{tc.size}, got, path)
+				}
+			}
+		})
+	}
+}
+
 func TestExecMethodDouble(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
 	meth := ast.NewMethod(ast.MethodDouble)
 
 	for _, tc := range []methodTestCase{
-		{
-			name:   "array_unwrap",
-			node:   meth,
-			value:  []any{"1", "3.2"},
-			unwrap: true,
-			exp:    statusOK,
-			find:   []any{float64(1), float64(3.2)},
-		},
 		{
 			name:  "array_no_unwrap",
 			node:  meth,
@@ -705,6 +1050,22 @@ func TestExecMethodDouble(t *testing.T) {
 			err:   `exec: argument "hi" of jsonpath item method .double() is invalid for type double precision`,
 			isErr: ErrExecution,
 		},
+		{
+			name:  "string_whitespace",
+			node:  meth,
+			value: "  12 ",
+			exp:   statusFailed,
+			err:   `exec: argument "  12 " of jsonpath item method .double() is invalid for type double precision`,
+			isErr: ErrExecution,
+		},
+		{
+			name:  "string_quote",
+			node:  meth,
+			value: `1"2`,
+			exp:   statusFailed,
+			err:   `exec: argument "1"2" of jsonpath item method .double() is invalid for type double precision`,
+			isErr: ErrExecution,
+		},
 		{
 			name:  "bool",
 			node:  meth,
@@ -753,7 +1114,7 @@ func TestExecMethodDouble(t *testing.T) {
 
 			// Test execMethodDouble
 			e, list := tc.prep()
-			res, err := e.execMethodDouble(ctx, node, tc.value, list, tc.unwrap)
+			res, err := e.execMethodDouble(ctx, node, tc.value, list)
 			tc.checkResults(t, res, list, err)
 		})
 	}
@@ -851,6 +1212,30 @@ func TestExecMethodInteger(t *testing.T) {
 			err:   `exec: argument "hi" of jsonpath item method .integer() is invalid for type integer`,
 			isErr: ErrVerbose,
 		},
+		{
+			name:  "json_number_over_max_int",
+			node:  meth,
+			value: json.Number("2147483647.5"),
+			exp:   statusFailed,
+			err:   `exec: argument "2147483647.5" of jsonpath item method .integer() is invalid for type integer`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "json_number_big",
+			node:  meth,
+			value: json.Number("12345678901234567890"),
+			exp:   statusFailed,
+			err:   `exec: argument "12345678901234567890" of jsonpath item method .integer() is invalid for type integer`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "float_over_max_int",
+			node:  meth,
+			value: float64(12345678901),
+			exp:   statusFailed,
+			err:   `exec: argument "12345678901" of jsonpath item method .integer() is invalid for type integer`,
+			isErr: ErrVerbose,
+		},
 		{
 			name:  "string",
 			node:  meth,
@@ -928,14 +1313,6 @@ func TestExecMethodInteger(t *testing.T) {
 			err:   `exec: jsonpath item method .integer() can only be applied to a string or numeric value`,
 			isErr: ErrVerbose,
 		},
-		{
-			name:   "array_unwrap",
-			node:   meth,
-			value:  []any{float64(42.2), "88"},
-			unwrap: true,
-			exp:    statusOK,
-			find:   []any{int64(42), int64(88)},
-		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -945,7 +1322,7 @@ func TestExecMethodInteger(t *testing.T) {
 
 			// Test execMethodInteger
 			e, list := tc.prep()
-			res, err := e.execMethodInteger(ctx, node, tc.value, list, tc.unwrap)
+			res, err := e.execMethodInteger(ctx, node, tc.value, list)
 			tc.checkResults(t, res, list, err)
 		})
 	}
@@ -997,10 +1374,7 @@ func TestExecMethodBigInt(t *testing.T) {
 			node:  meth,
 			value: float64(math.MaxUint64),
 			exp:   statusFailed,
-			err: fmt.Sprintf(
-				`exec: argument "%v" of jsonpath item method .bigint() is invalid for type bigint`,
-				float64(math.MaxUint64),
-			),
+			err:   `exec: argument "18446744073709552000" of jsonpath item method .bigint() is invalid for type bigint`,
 			isErr: ErrVerbose,
 		},
 		{
@@ -1008,10 +1382,7 @@ func TestExecMethodBigInt(t *testing.T) {
 			node:  meth,
 			value: float64(-math.MaxUint64),
 			exp:   statusFailed,
-			err: fmt.Sprintf(
-				`exec: argument "%v" of jsonpath item method .bigint() is invalid for type bigint`,
-				float64(-math.MaxUint64),
-			),
+			err:   `exec: argument "-18446744073709552000" of jsonpath item method .bigint() is invalid for type bigint`,
 			isErr: ErrVerbose,
 		},
 		{
@@ -1051,6 +1422,51 @@ func TestExecMethodBigInt(t *testing.T) {
 			err:   `exec: argument "-18446744073709551615.123" of jsonpath item method .bigint() is invalid for type bigint`,
 			isErr: ErrVerbose,
 		},
+		{
+			name:  "float_two_to_63",
+			node:  meth,
+			value: float64(1 << 63),
+			exp:   statusFailed,
+			err:   `exec: argument "9223372036854776000" of jsonpath item method .bigint() is invalid for type bigint`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "json_max_int",
+			node:  meth,
+			value: json.Number("9223372036854775807"),
+			exp:   statusOK,
+			find:  []any{int64(math.MaxInt64)},
+		},
+		{
+			name:  "json_min_int",
+			node:  meth,
+			value: json.Number("-9223372036854775808"),
+			exp:   statusOK,
+			find:  []any{int64(math.MinInt64)},
+		},
+		{
+			name:  "json_past_max_int",
+			node:  meth,
+			value: json.Number("9223372036854775808"),
+			exp:   statusFailed,
+			err:   `exec: argument "9223372036854775808" of jsonpath item method .bigint() is invalid for type bigint`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "json_rounds_past_max_int",
+			node:  meth,
+			value: json.Number("9223372036854775807.5"),
+			exp:   statusFailed,
+			err:   `exec: argument "9223372036854775807.5" of jsonpath item method .bigint() is invalid for type bigint`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "json_exponent",
+			node:  meth,
+			value: json.Number("12e2"),
+			exp:   statusOK,
+			find:  []any{int64(1200)},
+		},
 		{
 			name:  "invalid_json",
 			node:  meth,
@@ -1143,22 +1559,6 @@ func TestExecMethodBigInt(t *testing.T) {
 			err:   `exec: jsonpath item method .bigint() can only be applied to a string or numeric value`,
 			isErr: ErrVerbose,
 		},
-		{
-			name:   "array_unwrap",
-			node:   meth,
-			value:  []any{int64(42), "1024"},
-			unwrap: true,
-			exp:    statusOK,
-			find:   []any{int64(42), int64(1024)},
-		},
-		{
-			name:   "array_unwrap_next",
-			node:   ast.LinkNodes([]ast.Node{ast.NewMethod(ast.MethodBigInt), ast.NewMethod(ast.MethodString)}),
-			value:  []any{int64(42), "1024"},
-			unwrap: true,
-			exp:    statusOK,
-			find:   []any{"42", "1024"},
-		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -1168,12 +1568,43 @@ func TestExecMethodBigInt(t *testing.T) {
 
 			// Test execMethodBigInt
 			e, list := tc.prep()
-			res, err := e.execMethodBigInt(ctx, node, tc.value, list, tc.unwrap)
+			res, err := e.execMethodBigInt(ctx, node, tc.value, list)
 			tc.checkResults(t, res, list, err)
 		})
 	}
 }
 
+func TestInvalidArgumentErr(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	for _, tc := range []struct {
+		name  string
+		value any
+		exp   string
+	}{
+		{"json_number", json.Number("1.50e3"), "1.50e3"},
+		{"json_invalid", json.Number("hi"), "hi"},
+		{"string", "  12 ", "  12 "},
+		{"string_quote", `"hi"`, `"hi"`},
+		{"int", int64(-42), "-42"},
+		{"float", float64(2.5), "2.5"},
+		{"float_large", float64(1.2345678901234567e+19), "12345678901234567000"},
+		{"float_small", float64(1.5e-10), "0.00000000015"},
+		{"float_neg_zero", math.Copysign(0, -1), "0"},
+		{"bool", true, "true"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a.Equal(tc.exp, argumentText(tc.value))
+			err := invalidArgumentErr(ErrNumeric, tc.value, ast.MethodInteger, "integer")
+			r.ErrorIs(err, ErrNumeric)
+			r.EqualError(err, `exec: argument "`+tc.exp+`" of jsonpath item method .integer() is invalid for type integer`)
+		})
+	}
+}
+
 func TestExecMethodString(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -1282,14 +1713,6 @@ func TestExecMethodString(t *testing.T) {
 			err:   `exec: jsonpath item method .string() can only be applied to a boolean, string, numeric, or datetime value`,
 			isErr: ErrVerbose,
 		},
-		{
-			name:   "array_unwrap",
-			node:   meth,
-			value:  []any{int64(42), true},
-			unwrap: true,
-			exp:    statusOK,
-			find:   []any{"42", "true"},
-		},
 		{
 			name:  "string_next",
 			node:  ast.LinkNodes([]ast.Node{ast.NewMethod(ast.MethodString), ast.NewMethod(ast.MethodInteger)}),
@@ -1306,7 +1729,7 @@ func TestExecMethodString(t *testing.T) {
 
 			// Test execMethodString
 			e, list := tc.prep()
-			res, err := e.execMethodString(ctx, node, tc.value, list, tc.unwrap)
+			res, err := e.execMethodString(ctx, node, tc.value, list)
 			tc.checkResults(t, res, list, err)
 		})
 	}
@@ -1403,6 +1826,14 @@ func TestExecMethodBoolean(t *testing.T) {
 			err:   `exec: argument "1.1" of jsonpath item method .boolean() is invalid for type boolean`,
 			isErr: ErrVerbose,
 		},
+		{
+			name:  "float_small",
+			node:  meth,
+			value: float64(1.5e-10),
+			exp:   statusFailed,
+			err:   `exec: argument "0.00000000015" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
 		{
 			name:  "float_dot_nine",
 			node:  meth,
@@ -1551,14 +1982,6 @@ func TestExecMethodBoolean(t *testing.T) {
 			err:   `exec: jsonpath item method .boolean() can only be applied to a boolean, string, or numeric value`,
 			isErr: ErrVerbose,
 		},
-		{
-			name:   "array_unwrap",
-			node:   meth,
-			value:  []any{true, false},
-			unwrap: true,
-			exp:    statusOK,
-			find:   []any{true, false},
-		},
 		{
 			name:  "bool_next",
 			node:  ast.LinkNodes([]ast.Node{ast.NewMethod(ast.MethodBoolean), ast.NewMethod(ast.MethodString)}),
@@ -1566,14 +1989,6 @@ func TestExecMethodBoolean(t *testing.T) {
 			exp:   statusOK,
 			find:  []any{"true"},
 		},
-		{
-			name:   "array_unwrap_next",
-			node:   ast.LinkNodes([]ast.Node{ast.NewMethod(ast.MethodBoolean), ast.NewMethod(ast.MethodString)}),
-			value:  []any{"t", "f"},
-			unwrap: true,
-			exp:    statusOK,
-			find:   []any{"true", "false"},
-		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -1583,7 +1998,7 @@ func TestExecMethodBoolean(t *testing.T) {
 
 			// Test execMethodBoolean
 			e, list := tc.prep()
-			res, err := e.execMethodBoolean(ctx, node, tc.value, list, tc.unwrap)
+			res, err := e.execMethodBoolean(ctx, node, tc.value, list)
 			tc.checkResults(t, res, list, err)
 		})
 	}
@@ -1607,6 +2022,18 @@ func TestExecBooleanString(t *testing.T) {
 			err:   `exec: argument "" of jsonpath item method .boolean() is invalid for type boolean`,
 			isErr: ErrVerbose,
 		},
+		{
+			name:  "whitespace",
+			val:   " yes ",
+			err:   `exec: argument " yes " of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "newline",
+			val:   "y\n",
+			err:   "exec: argument \"y\n\" of jsonpath item method .boolean() is invalid for type boolean",
+			isErr: ErrVerbose,
+		},
 		{
 			name: "t",
 			val:  "t",
@@ -1633,9 +2060,31 @@ func TestExecBooleanString(t *testing.T) {
 			exp:  true,
 		},
 		{
-			name:  "tru",
-			val:   "tru",
-			err:   `exec: argument "tru" of jsonpath item method .boolean() is invalid for type boolean`,
+			name: "tru",
+			val:  "tru",
+			exp:  true,
+		},
+		{
+			name: "tR",
+			val:  "tR",
+			exp:  true,
+		},
+		{
+			name:  "truex",
+			val:   "truex",
+			err:   `exec: argument "truex" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "trueish",
+			val:   "trueish",
+			err:   `exec: argument "trueish" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "tx",
+			val:   "tx",
+			err:   `exec: argument "tx" of jsonpath item method .boolean() is invalid for type boolean`,
 			isErr: ErrVerbose,
 		},
 		{
@@ -1664,9 +2113,25 @@ func TestExecBooleanString(t *testing.T) {
 			exp:  false,
 		},
 		{
-			name:  "fal",
-			val:   "fal",
-			err:   `exec: argument "fal" of jsonpath item method .boolean() is invalid for type boolean`,
+			name: "fal",
+			val:  "fal",
+			exp:  false,
+		},
+		{
+			name: "FA",
+			val:  "FA",
+			exp:  false,
+		},
+		{
+			name:  "falsey",
+			val:   "falsey",
+			err:   `exec: argument "falsey" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "fx",
+			val:   "fx",
+			err:   `exec: argument "fx" of jsonpath item method .boolean() is invalid for type boolean`,
 			isErr: ErrVerbose,
 		},
 		{
@@ -1695,9 +2160,31 @@ func TestExecBooleanString(t *testing.T) {
 			exp:  true,
 		},
 		{
-			name:  "ye",
-			val:   "ye",
-			err:   `exec: argument "ye" of jsonpath item method .boolean() is invalid for type boolean`,
+			name: "ye",
+			val:  "ye",
+			exp:  true,
+		},
+		{
+			name: "yE",
+			val:  "yE",
+			exp:  true,
+		},
+		{
+			name:  "yess",
+			val:   "yess",
+			err:   `exec: argument "yess" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "yeah",
+			val:   "yeah",
+			err:   `exec: argument "yeah" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "y_long_s",
+			val:   "yſ",
+			err:   `exec: argument "yſ" of jsonpath item method .boolean() is invalid for type boolean`,
 			isErr: ErrVerbose,
 		},
 		{
@@ -1762,6 +2249,46 @@ func TestExecBooleanString(t *testing.T) {
 			err:   `exec: argument "oof" of jsonpath item method .boolean() is invalid for type boolean`,
 			isErr: ErrVerbose,
 		},
+		{
+			name:  "o",
+			val:   "o",
+			err:   `exec: argument "o" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "O",
+			val:   "O",
+			err:   `exec: argument "O" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name: "of",
+			val:  "of",
+			exp:  false,
+		},
+		{
+			name: "OF",
+			val:  "OF",
+			exp:  false,
+		},
+		{
+			name:  "onn",
+			val:   "onn",
+			err:   `exec: argument "onn" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "offf",
+			val:   "offf",
+			err:   `exec: argument "offf" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "ox",
+			val:   "ox",
+			err:   `exec: argument "ox" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
 		{
 			name: "1",
 			val:  "1",
@@ -1772,6 +2299,24 @@ func TestExecBooleanString(t *testing.T) {
 			val:  "0",
 			exp:  false,
 		},
+		{
+			name:  "10",
+			val:   "10",
+			err:   `exec: argument "10" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "01",
+			val:   "01",
+			err:   `exec: argument "01" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "00",
+			val:   "00",
+			err:   `exec: argument "00" of jsonpath item method .boolean() is invalid for type boolean`,
+			isErr: ErrVerbose,
+		},
 		{
 			name:  "1_space",
 			val:   "1 ",
@@ -1868,6 +2413,14 @@ func TestExecuteNumberMethod(t *testing.T) {
 			err:   `exec: argument "hi" of jsonpath item method .number() is invalid for type numeric`,
 			isErr: ErrVerbose,
 		},
+		{
+			name:  "number_invalid_string",
+			node:  number,
+			value: " 1e2x ",
+			exp:   statusFailed,
+			err:   `exec: argument " 1e2x " of jsonpath item method .number() is invalid for type numeric`,
+			isErr: ErrVerbose,
+		},
 		{
 			name:  "invalid_json_decimal",
 			node:  decimal,
@@ -1928,14 +2481,6 @@ func TestExecuteNumberMethod(t *testing.T) {
 			err:   `exec: jsonpath item method .number() can only be applied to a string or numeric value`,
 			isErr: ErrVerbose,
 		},
-		{
-			name:   "array_unwrap",
-			node:   number,
-			value:  []any{"42", float64(98.6)},
-			unwrap: true,
-			exp:    statusOK,
-			find:   []any{float64(42), float64(98.6)},
-		},
 		{
 			name:  "inf",
 			node:  number,
@@ -1980,14 +2525,14 @@ func TestExecuteNumberMethod(t *testing.T) {
 			node:  ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("4"), nil),
 			value: float64(12.2),
 			exp:   statusOK,
-			find:  []any{float64(12)},
+			find:  []any{json.Number("12")},
 		},
 		{
 			name:  "float_decimal_precision_scale",
 			node:  ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("4"), ast.NewInteger("2")),
 			value: float64(12.233),
 			exp:   statusOK,
-			find:  []any{float64(12.23)},
+			find:  []any{json.Number("12.23")},
 		},
 		{
 			name:  "float_decimal_error",
@@ -2009,7 +2554,7 @@ func TestExecuteNumberMethod(t *testing.T) {
 
 			// Test execMethodNumber
 			e, list := tc.prep()
-			res, err := e.executeNumberMethod(ctx, tc.node, tc.value, list, tc.unwrap, meth)
+			res, err := e.executeNumberMethod(ctx, tc.node, tc.value, list, meth)
 			tc.checkResults(t, res, list, err)
 		})
 	}
@@ -2025,7 +2570,7 @@ func TestExecuteDecimalMethod(t *testing.T) {
 		node  *ast.BinaryNode
 		value any
 		num   float64
-		exp   float64
+		exp   any
 		err   string
 		isErr error
 	}{
@@ -2063,13 +2608,13 @@ func TestExecuteDecimalMethod(t *testing.T) {
 			name: "precision_1000",
 			node: ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("1000"), nil),
 			num:  float64(98.6),
-			exp:  float64(99),
+			exp:  json.Number("99"),
 		},
 		{
 			name: "precision_10",
 			node: ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("10"), nil),
 			num:  float64(98.6),
-			exp:  float64(99),
+			exp:  json.Number("99"),
 		},
 		{
 			name:  "precision_too_small",
@@ -2101,13 +2646,13 @@ func TestExecuteDecimalMethod(t *testing.T) {
 			name: "precision_scale_ok",
 			node: ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("5"), ast.NewInteger("3")),
 			num:  float64(12.333),
-			exp:  float64(12.333),
+			exp:  json.Number("12.333"),
 		},
 		{
 			name: "scale_down",
 			node: ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("5"), ast.NewInteger("2")),
 			num:  float64(12.333),
-			exp:  float64(12.33),
+			exp:  json.Number("12.33"),
 		},
 		{
 			name:  "scale_short",
@@ -2117,6 +2662,71 @@ func TestExecuteDecimalMethod(t *testing.T) {
 			err:   `exec: argument "12.333" of jsonpath item method .decimal() is invalid for type numeric`,
 			isErr: ErrExecution,
 		},
+		{
+			name:  "precision_power_of_ten",
+			node:  ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("2"), nil),
+			value: float64(100),
+			num:   float64(100),
+			err:   `exec: argument "100" of jsonpath item method .decimal() is invalid for type numeric`,
+			isErr: ErrExecution,
+		},
+		{
+			name: "precision_max",
+			node: ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("2"), nil),
+			num:  float64(99),
+			exp:  json.Number("99"),
+		},
+		{
+			name: "rounds_to_zero",
+			node: ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("1"), nil),
+			num:  float64(0.4),
+			exp:  json.Number("0"),
+		},
+		{
+			name:  "scale_over_precision",
+			node:  ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("4"), ast.NewInteger("6")),
+			value: float64(0.05),
+			num:   float64(0.05),
+			err:   `exec: argument "0.05" of jsonpath item method .decimal() is invalid for type numeric`,
+			isErr: ErrExecution,
+		},
+		{
+			name: "scale_over_precision_ok",
+			node: ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("4"), ast.NewInteger("6")),
+			num:  float64(0.005),
+			exp:  json.Number("0.005000"),
+		},
+		{
+			name:  "json_rounds_up_too_big",
+			node:  ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("3"), ast.NewInteger("2")),
+			value: json.Number("9.995"),
+			num:   float64(9.995),
+			err:   `exec: argument "9.995" of jsonpath item method .decimal() is invalid for type numeric`,
+			isErr: ErrExecution,
+		},
+		{
+			name:  "json_rounds_down",
+			node:  ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("3"), ast.NewInteger("2")),
+			value: json.Number("9.994"),
+			num:   float64(9.994),
+			exp:   json.Number("9.99"),
+		},
+		{
+			name:  "json_big",
+			node:  ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("20"), nil),
+			value: json.Number("123456789012345678901"),
+			num:   float64(123456789012345678901),
+			err:   `exec: argument "123456789012345678901" of jsonpath item method .decimal() is invalid for type numeric`,
+			isErr: ErrExecution,
+		},
+		{
+			name:  "string_rounds_up_too_big",
+			node:  ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("2"), nil),
+			value: "99.5",
+			num:   float64(99.5),
+			err:   `exec: argument "99.5" of jsonpath item method .decimal() is invalid for type numeric`,
+			isErr: ErrExecution,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -2145,6 +2755,7 @@ func TestNumericCallbacks(t *testing.T) {
 		for i, n := range []int64{0, -1, 2, -3, 4, 5} {
 			a.Equal(int64(i), intAbs(n))
 		}
+		a.Equal(json.Number("9223372036854775808"), intAbs(math.MinInt64))
 	})
 
 	t.Run("intSelf", func(t *testing.T) {
@@ -2170,6 +2781,7 @@ func TestNumericCallbacks(t *testing.T) {
 		for _, n := range []int64{4, 42, -99, -100323, 4, 10030} {
 			a.Equal(-n, intUMinus(n))
 		}
+		a.Equal(json.Number("9223372036854775808"), intUMinus(math.MinInt64))
 	})
 
 	t.Run("floatSelf", func(t *testing.T) {
@@ -2271,19 +2883,6 @@ func TestExecuteNumericItemMethod(t *testing.T) {
 				isErr: ErrVerbose,
 			},
 		},
-		{
-			methodTestCase: methodTestCase{
-				name:   "abs_array_unwrap",
-				path:   laxRootPath,
-				node:   abs,
-				value:  []any{int64(-42), float64(-42.2)},
-				unwrap: true,
-				exp:    statusOK,
-				find:   []any{int64(42), float64(42.2)},
-			},
-			intCB:   intAbs,
-			floatCB: math.Abs,
-		},
 		{
 			methodTestCase: methodTestCase{
 				name:  "int_floor",
@@ -2339,19 +2938,6 @@ func TestExecuteNumericItemMethod(t *testing.T) {
 				isErr: ErrVerbose,
 			},
 		},
-		{
-			methodTestCase: methodTestCase{
-				name:   "floor_array_unwrap",
-				path:   laxRootPath,
-				node:   floor,
-				value:  []any{int64(42), float64(42.8)},
-				unwrap: true,
-				exp:    statusOK,
-				find:   []any{int64(42), float64(42)},
-			},
-			intCB:   intSelf,
-			floatCB: math.Floor,
-		},
 
 		{
 			methodTestCase: methodTestCase{
@@ -2408,24 +2994,11 @@ func TestExecuteNumericItemMethod(t *testing.T) {
 				isErr: ErrVerbose,
 			},
 		},
-		{
-			methodTestCase: methodTestCase{
-				name:   "ceil_array_unwrap",
-				path:   laxRootPath,
-				node:   ceil,
-				value:  []any{int64(42), float64(42.8)},
-				unwrap: true,
-				exp:    statusOK,
-				find:   []any{int64(42), float64(43)},
-			},
-			intCB:   intSelf,
-			floatCB: math.Ceil,
-		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			e, list := tc.prep()
-			res, err := e.executeNumericItemMethod(ctx, tc.node, tc.value, tc.unwrap, tc.intCB, tc.floatCB, list)
+			res, err := e.executeNumericItemMethod(ctx, tc.node, tc.value, tc.intCB, tc.floatCB, list)
 			tc.checkResults(t, res, list, err)
 		})
 	}
@@ -0,0 +1,498 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/theory/sqljson/path/ast"
+	"golang.org/x/exp/maps" // Switch to maps when go 1.22 dropped
+)
+
+// location identifies the position of a JSON item in its parent container.
+// A nil parent indicates the root of the document.
+type location struct {
+	parent any // map[string]any or []any
+	key    string
+	index  int
+}
+
+// set replaces the item at loc with val.
+func (loc location) set(val any) {
+	switch parent := loc.parent.(type) {
+	case map[string]any:
+		parent[loc.key] = val
+	case []any:
+		parent[loc.index] = val
+	}
+}
+
+// remove removes the item at loc from its parent. Array items are replaced
+// with the deleted marker, to be removed by compact once all items have been
+// removed, so that the indexes of other locations in the same array remain
+// valid.
+func (loc location) remove() {
+	switch parent := loc.parent.(type) {
+	case map[string]any:
+		delete(parent, loc.key)
+	case []any:
+		parent[loc.index] = deleted{}
+	}
+}
+
+// deleted marks array items removed by [Delete].
+type deleted struct{}
+
+// WithStrictMutation causes [Replace] and [Delete] to return an error when
+// the path selects no items.
+func WithStrictMutation() Option { return func(e *Executor) { e.strictMutation = true } }
+
+// Replace returns a deep copy of value in which every item selected by path
+// is replaced with a deep copy of newValue, similar to the PostgreSQL
+// jsonb_set() function. Replacing the root item returns newValue. The path
+// may contain only accessors and filter expressions: object member accessors
+// (.key, .*), array accessors ([*], [1], [last], [1 to 3]), the .** accessor,
+// and ?() filter expressions. value itself is never modified.
+//
+// The copies of value and newValue contain only JSON values: typed Go maps
+// and slices become map[string]any and []any values, and, with
+// [WithStructTags], so do structs and pointers, converted as for [Query].
+// Returns an [ErrExecution] error for Go values that cannot be converted,
+// such as maps with non-string keys, or structs without [WithStructTags].
+//
+// If path selects no items, Replace returns the copy of value unchanged,
+// unless the [WithStrictMutation] Option is specified, in which case it
+// returns an error. The remaining Options act the same as for [Query].
+func Replace(ctx context.Context, path *ast.AST, value, newValue any, opt ...Option) (any, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.release()
+	doc, targets, err := exec.mutationTargets(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return doc, nil
+	}
+	if newValue, err = exec.jsonCopy(ctx, newValue); err != nil {
+		return nil, err
+	}
+
+	for i, loc := range targets {
+		val := newValue
+		if i > 0 {
+			// Each target gets a distinct copy.
+			val, _ = exec.copyValue(newValue)
+		}
+		if loc.parent == nil {
+			return val, nil
+		}
+		loc.set(val)
+	}
+
+	return doc, nil
+}
+
+// Delete returns a deep copy of value from which every item selected by path
+// has been removed, with object members deleted and array items spliced out.
+// Deleting the root item returns nil. The path may contain the same
+// accessors and filter expressions as for [Replace], the Options act the
+// same, and it likewise converts Go values to JSON values. value itself is
+// never modified.
+func Delete(ctx context.Context, path *ast.AST, value any, opt ...Option) (any, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.release()
+	doc, targets, err := exec.mutationTargets(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, loc := range targets {
+		if loc.parent == nil {
+			//nolint:nilnil // nil is a valid return value, standing in for JSON null.
+			return nil, nil
+		}
+		loc.remove()
+	}
+
+	return compact(doc), nil
+}
+
+// mutationTargets makes a deep copy of value and returns it along with the
+// locations of all the items in the copy selected by exec.path.
+func (exec *Executor) mutationTargets(ctx context.Context, value any) (any, []location, error) {
+	if exec.path.IsPredicate() {
+		return nil, nil, fmt.Errorf(
+			"%w: mutation requires a SQL standard path expression",
+			ErrExecution,
+		)
+	}
+
+	if err := exec.convertVars(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if idx, ok := value.(*Indexed); ok {
+		value = idx.doc
+	}
+	doc, err := exec.jsonCopy(ctx, value)
+	if err != nil {
+		return nil, nil, err
+	}
+	exec.root = doc
+	exec.current = doc
+
+	var targets []location
+	if err := exec.collectTargets(ctx, exec.path.Root(), location{}, doc, &targets); err != nil {
+		return nil, nil, err
+	}
+
+	if len(targets) == 0 && exec.strictMutation {
+		return nil, nil, fmt.Errorf(
+			"%w: jsonpath selected no items to modify",
+			ErrExecution,
+		)
+	}
+
+	return doc, targets, nil
+}
+
+// collectTargets executes node against value, located at loc, and appends
+// the locations of all the selected items to targets.
+func (exec *Executor) collectTargets(
+	ctx context.Context,
+	node ast.Node,
+	loc location,
+	value any,
+	targets *[]location,
+) error {
+	// Check for interrupts.
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := exec.descend(); err != nil {
+		return err
+	}
+	defer exec.ascend()
+
+	if node == nil {
+		*targets = append(*targets, loc)
+		return nil
+	}
+
+	switch node := node.(type) {
+	case *ast.ConstNode:
+		switch node.Const() {
+		case ast.ConstRoot:
+			return exec.collectTargets(ctx, node.Next(), loc, value, targets)
+		case ast.ConstAnyKey:
+			return exec.collectAnyKeyTargets(ctx, node, value, targets)
+		case ast.ConstAnyArray:
+			return exec.collectAnyArrayTargets(ctx, node, loc, value, targets)
+		case ast.ConstCurrent, ast.ConstLast, ast.ConstNull, ast.ConstTrue, ast.ConstFalse:
+			// Not accessors.
+		}
+	case *ast.KeyNode:
+		return exec.collectKeyTargets(ctx, node, loc, value, targets)
+	case *ast.ArrayIndexNode:
+		return exec.collectArrayIndexTargets(ctx, node, loc, value, targets)
+	case *ast.AnyNode:
+		return exec.collectAnyTargets(ctx, node.Next(), loc, value, 0, node.First(), node.Last(), targets)
+	case *ast.UnaryNode:
+		if node.Operator() == ast.UnaryFilter {
+			return exec.collectFilterTargets(ctx, node, loc, value, targets)
+		}
+	}
+
+	return fmt.Errorf(
+		"%w: jsonpath item %v cannot select items to modify",
+		ErrExecution, node,
+	)
+}
+
+// structuralError returns err unless exec.ignoreStructuralErrors is true or
+// exec.verbose is false, in which case it returns nil.
+func (exec *Executor) structuralError(err error) error {
+	if exec.ignoreStructuralErrors || !exec.verbose {
+		return nil
+	}
+	return err
+}
+
+// collectElementTargets passes each item in array to collectTargets for
+// node. Used to unwrap arrays in lax mode.
+func (exec *Executor) collectElementTargets(
+	ctx context.Context,
+	node ast.Node,
+	array []any,
+	targets *[]location,
+) error {
+	for i, v := range array {
+		if err := exec.collectTargets(ctx, node, location{parent: array, index: i}, v, targets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectKeyTargets selects the member of value named by node.
+func (exec *Executor) collectKeyTargets(
+	ctx context.Context,
+	node *ast.KeyNode,
+	loc location,
+	value any,
+	targets *[]location,
+) error {
+	key := node.Text()
+	switch value := value.(type) {
+	case map[string]any:
+		if val, stored, ok := exec.lookupKey(value, key); ok {
+			return exec.collectTargets(ctx, node.Next(), location{parent: value, key: stored}, val, targets)
+		}
+		if exec.missingAsNull {
+			// Nothing to modify.
+			return nil
+		}
+		return exec.structuralError(missingKeyErr(key))
+	case []any:
+		if exec.autoUnwrap() {
+			return exec.collectElementTargets(ctx, node, value, targets)
+		}
+	}
+
+	return exec.structuralError(memberAccessorTypeErr())
+}
+
+// collectAnyKeyTargets selects all the members of value.
+func (exec *Executor) collectAnyKeyTargets(
+	ctx context.Context,
+	node *ast.ConstNode,
+	value any,
+	targets *[]location,
+) error {
+	switch value := value.(type) {
+	case map[string]any:
+		// Process the keys in a deterministic order.
+		keys := maps.Keys(value)
+		slices.Sort(keys)
+		for _, k := range keys {
+			if err := exec.collectTargets(ctx, node.Next(), location{parent: value, key: k}, value[k], targets); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		if exec.autoUnwrap() {
+			return exec.collectElementTargets(ctx, node, value, targets)
+		}
+	}
+
+	return exec.structuralError(anyKeyTypeErr())
+}
+
+// collectAnyArrayTargets selects all the items of value.
+func (exec *Executor) collectAnyArrayTargets(
+	ctx context.Context,
+	node *ast.ConstNode,
+	loc location,
+	value any,
+	targets *[]location,
+) error {
+	if array, ok := value.([]any); ok {
+		return exec.collectElementTargets(ctx, node.Next(), array, targets)
+	}
+
+	if exec.autoWrap() {
+		return exec.collectTargets(ctx, node.Next(), loc, value, targets)
+	}
+
+	return exec.structuralError(anyArrayTypeErr())
+}
+
+// collectArrayIndexTargets selects the items of value identified by the
+// subscripts of node.
+func (exec *Executor) collectArrayIndexTargets(
+	ctx context.Context,
+	node *ast.ArrayIndexNode,
+	loc location,
+	value any,
+	targets *[]location,
+) error {
+	array, ok := value.([]any)
+	if !ok && !exec.autoWrap() {
+		return exec.structuralError(fmt.Errorf(
+			"%w: jsonpath array accessor can only be applied to an array",
+			errVerboseStructural,
+		))
+	}
+
+	size := 1
+	if ok {
+		size = len(array)
+	}
+
+	innermostArraySize := exec.innermostArraySize
+	defer func() { exec.innermostArraySize = innermostArraySize }()
+	exec.innermostArraySize = size // for LAST evaluation
+
+	for _, subscript := range node.Subscripts() {
+		indexFrom, indexTo, err := exec.execSubscript(ctx, subscript, value, size)
+		if err != nil {
+			if _, err = exec.returnError(err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for index := indexFrom; index <= indexTo; index++ {
+			itemLoc, item := loc, value // auto wrap
+			if ok {
+				itemLoc, item = location{parent: array, index: index}, array[index]
+			}
+			if err := exec.collectTargets(ctx, node.Next(), itemLoc, item, targets); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectAnyTargets implements the .** accessor, selecting value when level
+// falls between first and last, and descending into its items while level
+// is less than last. As in executeAnyItem, .**{last} selects the leaves
+// below the top level.
+func (exec *Executor) collectAnyTargets(
+	ctx context.Context,
+	next ast.Node,
+	loc location,
+	value any,
+	level, first, last uint32,
+	targets *[]location,
+) error {
+	if err := exec.descend(); err != nil {
+		return err
+	}
+	defer exec.ascend()
+
+	// Like executeAnyItem, consider only items below the top level leaves.
+	var leaf bool
+	switch value.(type) {
+	case map[string]any, []any:
+	default:
+		leaf = level > 0
+	}
+
+	if level >= first || (first == math.MaxUint32 && last == math.MaxUint32 && leaf) {
+		defer exec.tempSetIgnoreStructuralErrors(true)()
+		if err := exec.collectTargets(ctx, next, loc, value, targets); err != nil {
+			return err
+		}
+	}
+
+	if level >= last {
+		return nil
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		keys := maps.Keys(value)
+		slices.Sort(keys)
+		for _, k := range keys {
+			itemLoc := location{parent: value, key: k}
+			if err := exec.collectAnyTargets(ctx, next, itemLoc, value[k], level+1, first, last, targets); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, v := range value {
+			itemLoc := location{parent: value, index: i}
+			if err := exec.collectAnyTargets(ctx, next, itemLoc, v, level+1, first, last, targets); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectFilterTargets selects value if it passes the filter expression in
+// node. In lax mode, arrays are unwrapped and each item filtered.
+func (exec *Executor) collectFilterTargets(
+	ctx context.Context,
+	node *ast.UnaryNode,
+	loc location,
+	value any,
+	targets *[]location,
+) error {
+	if array, ok := value.([]any); ok && exec.autoUnwrap() {
+		for i, v := range array {
+			if err := exec.collectFilterItem(ctx, node, location{parent: array, index: i}, v, targets); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return exec.collectFilterItem(ctx, node, loc, value, targets)
+}
+
+// collectFilterItem selects value if it passes the filter expression in
+// node.
+func (exec *Executor) collectFilterItem(
+	ctx context.Context,
+	node *ast.UnaryNode,
+	loc location,
+	value any,
+	targets *[]location,
+) error {
+	st, err := exec.executeNestedBoolItem(ctx, node.Operand(), value)
+	if st != predTrue {
+		return err
+	}
+	return exec.collectTargets(ctx, node.Next(), loc, value, targets)
+}
+
+// jsonCopy returns a deep copy of value that contains only JSON values,
+// converting typed Go maps, slices, and arrays, and, when exec.structTag is
+// set, structs and pointers, as execution does, so that mutation can modify
+// every container in it. Returns an [ErrExecution] error for values that
+// cannot be converted.
+func (exec *Executor) jsonCopy(ctx context.Context, value any) (any, error) {
+	if exec.structTag != "" {
+		var err error
+		if value, err = exec.fromGo(ctx, value); err != nil {
+			return nil, err
+		}
+	}
+	return exec.copyValue(value)
+}
+
+// compact recursively removes the items marked deleted from arrays in value
+// and returns the result.
+func compact(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for k, v := range value {
+			value[k] = compact(v)
+		}
+		return value
+	case []any:
+		array := value[:0]
+		for _, v := range value {
+			if _, ok := v.(deleted); !ok {
+				array = append(array, compact(v))
+			}
+		}
+		return array
+	default:
+		return value
+	}
+}
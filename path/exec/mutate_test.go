@@ -0,0 +1,446 @@
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestReplaceAndDelete(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name    string
+		path    string
+		json    string
+		opts    []Option
+		replace any
+		deleted any
+		err     string
+		isErr   error
+	}{
+		{
+			name:    "root",
+			path:    "$",
+			json:    `{"a": 1}`,
+			replace: "new",
+			deleted: nil,
+		},
+		{
+			name:    "member",
+			path:    "$.a",
+			json:    `{"a": 1, "b": 2}`,
+			replace: js(`{"a": "new", "b": 2}`),
+			deleted: js(`{"b": 2}`),
+		},
+		{
+			name:    "nested_member",
+			path:    "$.a.b.c",
+			json:    `{"a": {"b": {"c": 1, "d": 2}}, "e": 3}`,
+			replace: js(`{"a": {"b": {"c": "new", "d": 2}}, "e": 3}`),
+			deleted: js(`{"a": {"b": {"d": 2}}, "e": 3}`),
+		},
+		{
+			name:    "missing_member",
+			path:    "$.x",
+			json:    `{"a": 1}`,
+			replace: js(`{"a": 1}`),
+			deleted: js(`{"a": 1}`),
+		},
+		{
+			name:  "missing_member_strict",
+			path:  "strict $.x",
+			json:  `{"a": 1}`,
+			err:   `exec: JSON object does not contain key "x"`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:    "missing_member_strict_silent",
+			path:    "strict $.x",
+			json:    `{"a": 1}`,
+			opts:    []Option{WithSilent()},
+			replace: js(`{"a": 1}`),
+			deleted: js(`{"a": 1}`),
+		},
+		{
+			name:    "missing_member_strict_missing_as_null",
+			path:    "strict $.x",
+			json:    `{"a": 1}`,
+			opts:    []Option{WithMissingAsNull()},
+			replace: js(`{"a": 1}`),
+			deleted: js(`{"a": 1}`),
+		},
+		{
+			name:    "case_insensitive_member",
+			path:    "strict $.userid",
+			json:    `{"UserId": 1, "USERID": 2, "name": 3}`,
+			opts:    []Option{WithCaseInsensitiveKeys()},
+			replace: js(`{"UserId": 1, "USERID": "new", "name": 3}`),
+			deleted: js(`{"UserId": 1, "name": 3}`),
+		},
+		{
+			name:  "no_match_strict_mutation",
+			path:  "$.x",
+			json:  `{"a": 1}`,
+			opts:  []Option{WithStrictMutation()},
+			err:   "exec: jsonpath selected no items to modify",
+			isErr: ErrExecution,
+		},
+		{
+			name:    "member_unwrap_array",
+			path:    "$.a",
+			json:    `[{"a": 1}, {"b": 2}, {"a": 3}]`,
+			replace: js(`[{"a": "new"}, {"b": 2}, {"a": "new"}]`),
+			deleted: js(`[{}, {"b": 2}, {}]`),
+		},
+		{
+			name:    "wildcard_member",
+			path:    "$.*",
+			json:    `{"a": 1, "b": [2]}`,
+			replace: js(`{"a": "new", "b": "new"}`),
+			deleted: js(`{}`),
+		},
+		{
+			name:    "subscript",
+			path:    "$[1]",
+			json:    `[1, 2, 3]`,
+			replace: js(`[1, "new", 3]`),
+			deleted: js(`[1, 3]`),
+		},
+		{
+			name:    "subscript_last",
+			path:    "$.a[last]",
+			json:    `{"a": [1, 2, 3]}`,
+			replace: js(`{"a": [1, 2, "new"]}`),
+			deleted: js(`{"a": [1, 2]}`),
+		},
+		{
+			name:    "subscript_range",
+			path:    "$[1 to last]",
+			json:    `[1, 2, 3, 4]`,
+			replace: js(`[1, "new", "new", "new"]`),
+			deleted: js(`[1]`),
+		},
+		{
+			name:    "multiple_subscripts",
+			path:    "$[0, 2 to 3]",
+			json:    `[1, 2, 3, 4, 5]`,
+			replace: js(`["new", 2, "new", "new", 5]`),
+			deleted: js(`[2, 5]`),
+		},
+		{
+			name:    "duplicate_subscripts",
+			path:    "$[1, 1]",
+			json:    `[1, 2, 3]`,
+			replace: js(`[1, "new", 3]`),
+			deleted: js(`[1, 3]`),
+		},
+		{
+			name:    "subscript_out_of_bounds",
+			path:    "$[5]",
+			json:    `[1, 2]`,
+			replace: js(`[1, 2]`),
+			deleted: js(`[1, 2]`),
+		},
+		{
+			name:  "subscript_out_of_bounds_strict",
+			path:  "strict $[5]",
+			json:  `[1, 2]`,
+			err:   "exec: jsonpath array subscript is out of bounds",
+			isErr: ErrVerbose,
+		},
+		{
+			name:    "subscript_auto_wrap",
+			path:    "$.a[0]",
+			json:    `{"a": 1}`,
+			replace: js(`{"a": "new"}`),
+			deleted: js(`{}`),
+		},
+		{
+			name:    "wildcard_array",
+			path:    "$.a[*]",
+			json:    `{"a": [1, 2, 3]}`,
+			replace: js(`{"a": ["new", "new", "new"]}`),
+			deleted: js(`{"a": []}`),
+		},
+		{
+			name:    "wildcard_array_nested",
+			path:    "$[*].b",
+			json:    `[{"a": 1, "b": 2}, {"a": 3, "b": 4}]`,
+			replace: js(`[{"a": 1, "b": "new"}, {"a": 3, "b": "new"}]`),
+			deleted: js(`[{"a": 1}, {"a": 3}]`),
+		},
+		{
+			name:  "wildcard_array_strict_scalar",
+			path:  "strict $.a[*]",
+			json:  `{"a": 1}`,
+			err:   "exec: jsonpath wildcard array accessor can only be applied to an array",
+			isErr: ErrVerbose,
+		},
+		{
+			name:    "filter",
+			path:    "$[*] ? (@ > 2)",
+			json:    `[1, 3, 2, 4]`,
+			replace: js(`[1, "new", 2, "new"]`),
+			deleted: js(`[1, 2]`),
+		},
+		{
+			name:    "filter_lax_unwrap",
+			path:    "$.a ? (@.x == 1)",
+			json:    `{"a": [{"x": 1}, {"x": 2}, {"x": 1, "y": 3}]}`,
+			replace: js(`{"a": ["new", {"x": 2}, "new"]}`),
+			deleted: js(`{"a": [{"x": 2}]}`),
+		},
+		{
+			name:    "filter_then_member",
+			path:    `$.items[*] ? (@.type == "a").value`,
+			json:    `{"items": [{"type": "a", "value": 1}, {"type": "b", "value": 2}, {"type": "a", "value": 3}]}`,
+			replace: js(`{"items": [{"type": "a", "value": "new"}, {"type": "b", "value": 2}, {"type": "a", "value": "new"}]}`),
+			deleted: js(`{"items": [{"type": "a"}, {"type": "b", "value": 2}, {"type": "a"}]}`),
+		},
+		{
+			name:    "filter_with_vars",
+			path:    "$[*] ? (@ == $x)",
+			json:    `[1, 2, 1]`,
+			opts:    []Option{WithVars(Vars{"x": float64(1)})},
+			replace: js(`["new", 2, "new"]`),
+			deleted: js(`[2]`),
+		},
+		{
+			name:    "any",
+			path:    "$.**.x",
+			json:    `{"x": 1, "a": {"x": 2, "b": [{"x": 3}]}}`,
+			replace: js(`{"x": "new", "a": {"x": "new", "b": [{"x": "new"}]}}`),
+			deleted: js(`{"a": {"b": [{}]}}`),
+		},
+		{
+			name:    "any_levels",
+			path:    "$.**{2}",
+			json:    `{"a": {"b": 1, "c": [2, 3]}}`,
+			replace: js(`{"a": {"b": "new", "c": "new"}}`),
+			deleted: js(`{"a": {}}`),
+		},
+		{
+			name:    "any_last",
+			path:    "$.**{last}",
+			json:    `{"a": {"b": 1, "c": [2, 3], "e": {}}, "d": 4}`,
+			replace: js(`{"a": {"b": "new", "c": ["new", "new"], "e": {}}, "d": "new"}`),
+			deleted: js(`{"a": {"c": [], "e": {}}}`),
+		},
+		{
+			name:    "any_to_last",
+			path:    "$.**{2 to last}",
+			json:    `{"a": {"b": 1, "c": [2, 3]}, "d": 4}`,
+			replace: js(`{"a": {"b": "new", "c": "new"}, "d": 4}`),
+			deleted: js(`{"a": {}, "d": 4}`),
+		},
+		{
+			name:    "any_filter",
+			path:    "$.** ? (@ == 2)",
+			json:    `{"a": 2, "b": [1, 2, {"c": 2}]}`,
+			replace: js(`{"a": "new", "b": [1, "new", {"c": "new"}]}`),
+			deleted: js(`{"b": [1, {}]}`),
+		},
+		{
+			name:  "method",
+			path:  "$.a.size()",
+			json:  `{"a": [1]}`,
+			err:   "exec: jsonpath item .size() cannot select items to modify",
+			isErr: ErrExecution,
+		},
+		{
+			name:  "variable",
+			path:  "$x.a",
+			json:  `{"a": [1]}`,
+			opts:  []Option{WithVars(Vars{"x": map[string]any{"a": 1}})},
+			err:   `exec: jsonpath item $"x" cannot select items to modify`,
+			isErr: ErrExecution,
+		},
+		{
+			name:  "predicate",
+			path:  "$.a == 1",
+			json:  `{"a": 1}`,
+			err:   "exec: mutation requires a SQL standard path expression",
+			isErr: ErrExecution,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			t.Run("replace", func(t *testing.T) {
+				t.Parallel()
+				value := js(tc.json)
+				res, err := Replace(ctx, path, value, "new", tc.opts...)
+				if tc.isErr != nil {
+					r.EqualError(err, tc.err)
+					r.ErrorIs(err, tc.isErr)
+					a.Nil(res)
+				} else {
+					r.NoError(err)
+					a.Equal(tc.replace, res)
+				}
+				// The original must not be modified.
+				a.Equal(js(tc.json), value)
+			})
+
+			t.Run("delete", func(t *testing.T) {
+				t.Parallel()
+				value := js(tc.json)
+				res, err := Delete(ctx, path, value, tc.opts...)
+				if tc.isErr != nil {
+					r.EqualError(err, tc.err)
+					r.ErrorIs(err, tc.isErr)
+					a.Nil(res)
+				} else {
+					r.NoError(err)
+					a.Equal(tc.deleted, res)
+				}
+				// The original must not be modified.
+				a.Equal(js(tc.json), value)
+			})
+		})
+	}
+}
+
+func TestReplaceCopiesNewValue(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	path, err := parser.Parse("$[*]")
+	r.NoError(err)
+	newValue := map[string]any{"x": []any{1}}
+	res, err := Replace(context.Background(), path, []any{1, 2}, newValue)
+	r.NoError(err)
+	a.Equal([]any{newValue, newValue}, res)
+
+	// Each replacement should be a distinct copy.
+	array, ok := res.([]any)
+	r.True(ok)
+	array[0].(map[string]any)["x"] = "changed"
+	a.Equal(map[string]any{"x": []any{1}}, array[1])
+	a.Equal(map[string]any{"x": []any{1}}, newValue)
+}
+
+func TestReplaceAndDeleteGoValues(t *testing.T) {
+	t.Parallel()
+
+	type record struct {
+		Name string `json:"name"`
+		Nums []int  `json:"nums"`
+	}
+
+	for _, tc := range []struct {
+		name    string
+		path    string
+		value   any
+		opts    []Option
+		replace any
+		deleted any
+		err     string
+	}{
+		{
+			name:    "typed_slice",
+			path:    "$[*] ? (@ > 1)",
+			value:   []int{1, 2, 3},
+			replace: []any{int64(1), 0, 0},
+			deleted: []any{int64(1)},
+		},
+		{
+			name:    "typed_map",
+			path:    "$.a",
+			value:   map[string]string{"a": "x", "b": "y"},
+			replace: map[string]any{"a": 0, "b": "y"},
+			deleted: map[string]any{"b": "y"},
+		},
+		{
+			name:    "nested_typed",
+			path:    "$.a[0]",
+			value:   map[string][]int{"a": {1, 2}},
+			replace: map[string]any{"a": []any{0, int64(2)}},
+			deleted: map[string]any{"a": []any{int64(2)}},
+		},
+		{
+			name:    "struct_tags",
+			path:    "$.nums[*] ? (@ > 1)",
+			value:   record{Name: "x", Nums: []int{1, 2}},
+			opts:    []Option{WithStructTags("json")},
+			replace: map[string]any{"name": "x", "nums": []any{int64(1), int64(0)}},
+			deleted: map[string]any{"name": "x", "nums": []any{int64(1)}},
+		},
+		{
+			name:    "struct_pointer_tags",
+			path:    "$.name",
+			value:   &record{Name: "x", Nums: []int{1}},
+			opts:    []Option{WithStructTags("json")},
+			replace: map[string]any{"name": int64(0), "nums": []any{int64(1)}},
+			deleted: map[string]any{"nums": []any{int64(1)}},
+		},
+		{
+			name:  "struct_no_tags",
+			path:  "$.name",
+			value: record{Name: "x"},
+			err:   "exec: unsupported value type exec.record",
+		},
+		{
+			name:  "non_string_keys",
+			path:  "$.a",
+			value: map[int]string{1: "x"},
+			err:   "exec: cannot query map[int]string: map keys must be strings",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			ctx := context.Background()
+
+			res, err := Replace(ctx, path, tc.value, 0, tc.opts...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(res)
+			} else {
+				r.NoError(err)
+				a.Equal(tc.replace, res)
+			}
+
+			res, err = Delete(ctx, path, tc.value, tc.opts...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(res)
+			} else {
+				r.NoError(err)
+				a.Equal(tc.deleted, res)
+			}
+		})
+	}
+}
+
+func TestReplaceCanceled(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	path, err := parser.Parse("$.a")
+	r.NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = Replace(ctx, path, map[string]any{"a": 1}, 2)
+	r.EqualError(err, "exec: context canceled")
+	r.ErrorIs(err, context.Canceled)
+	_, err = Delete(ctx, path, map[string]any{"a": 1})
+	r.ErrorIs(err, context.Canceled)
+}
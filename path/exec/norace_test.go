@@ -0,0 +1,6 @@
+//go:build !race
+
+package exec
+
+// raceEnabled is true when the race detector is enabled.
+const raceEnabled = false
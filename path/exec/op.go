@@ -4,10 +4,10 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"slices"
 	"strings"
 
 	"github.com/theory/sqljson/path/ast"
-	"golang.org/x/exp/maps" // Switch to maps when go 1.22 dropped
 )
 
 // execBinaryNode executes node's binary operation against value.
@@ -16,7 +16,6 @@ func (exec *Executor) execBinaryNode(
 	node *ast.BinaryNode,
 	value any,
 	found *valueList,
-	unwrap bool,
 ) (resultStatus, error) {
 	switch node.Operator() {
 	case ast.BinaryAnd, ast.BinaryOr, ast.BinaryEqual, ast.BinaryNotEqual,
@@ -28,7 +27,7 @@ func (exec *Executor) execBinaryNode(
 	case ast.BinaryAdd, ast.BinarySub, ast.BinaryMul, ast.BinaryDiv, ast.BinaryMod:
 		return exec.execBinaryMathExpr(ctx, node, value, found)
 	case ast.BinaryDecimal:
-		return exec.executeNumberMethod(ctx, node, value, found, unwrap, node.Operator())
+		return exec.executeNumberMethod(ctx, node, value, found, node.Operator())
 	case ast.BinarySubscript:
 		// This should not happen because the Parser disallows it.
 		return statusFailed, fmt.Errorf(
@@ -60,6 +59,9 @@ func (exec *Executor) execUnaryNode(
 			}
 		}
 
+		if exec.stats != nil {
+			exec.stats.FilterEvaluations++
+		}
 		st, err := exec.executeNestedBoolItem(ctx, node.Operand(), value)
 		if st != predTrue {
 			return statusNotFound, err
@@ -71,11 +73,6 @@ func (exec *Executor) execUnaryNode(
 		return exec.execUnaryMathExpr(ctx, node, value, intUMinus, floatUMinus, found)
 	case ast.UnaryDateTime, ast.UnaryDate, ast.UnaryTime, ast.UnaryTimeTZ,
 		ast.UnaryTimestamp, ast.UnaryTimestampTZ:
-		if unwrap {
-			if array, ok := value.([]any); ok {
-				return exec.executeAnyItem(ctx, node, array, found, 1, 1, 1, false, false)
-			}
-		}
 		return exec.executeDateTimeMethod(ctx, node, value, found)
 	}
 
@@ -110,6 +107,13 @@ func (exec *Executor) execAnyNode(
 	found *valueList,
 ) (resultStatus, error) {
 	next := node.Next()
+	if key, ok := next.(*ast.KeyNode); ok && exec.index != nil &&
+		node.First() == 0 && node.Last() == math.MaxUint32 {
+		if ord, ok := exec.indexedAny(value); ok {
+			return exec.executeIndexedAny(ctx, key, ord, found)
+		}
+	}
+
 	// first try without any intermediate steps
 	if node.First() == 0 {
 		defer exec.tempSetIgnoreStructuralErrors(true)()
@@ -121,13 +125,14 @@ func (exec *Executor) execAnyNode(
 
 	switch value := value.(type) {
 	case map[string]any:
+		values, keys := exec.members(value)
 		return exec.executeAnyItem(
-			ctx, next, maps.Values(value), found, 1,
+			ctx, next, values, keys, found, 1,
 			node.First(), node.Last(), true, exec.autoUnwrap(),
 		)
 	case []any:
 		return exec.executeAnyItem(
-			ctx, next, value, found, 1,
+			ctx, next, value, nil, found, 1,
 			node.First(), node.Last(), true, exec.autoUnwrap(),
 		)
 	}
@@ -135,16 +140,55 @@ func (exec *Executor) execAnyNode(
 	return statusNotFound, nil
 }
 
+// selectsOne returns true if node and the nodes that follow it are
+// accessors and methods that usually select one item from each item they're
+// applied to, or node is nil. Callers use it to estimate the number of items
+// a path will select.
+func selectsOne(node ast.Node) bool {
+	for ; node != nil; node = node.Next() {
+		switch node := node.(type) {
+		case *ast.KeyNode:
+		case *ast.MethodNode:
+			if node.Name() == ast.MethodKeyValue {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // collection converts v into a slice of values if it's either a map or a
-// slice. Otherwise it returns nil.
-func collection(v any) []any {
+// slice. For a map it also returns a slice of the keys corresponding to the
+// values. Otherwise it returns nil.
+func (exec *Executor) collection(v any) ([]any, []string) {
 	switch v := v.(type) {
 	case map[string]any:
-		return maps.Values(v) // Just work with the values
+		return exec.members(v)
 	case []any:
-		return v
+		return v, nil
 	}
-	return nil
+	return nil, nil
+}
+
+// members returns the values of obj and a slice of their corresponding keys.
+// It sorts them by key when [WithOrderedKeys] is set or when tracing, so
+// that results and traces are deterministic.
+func (exec *Executor) members(obj map[string]any) ([]any, []string) {
+	values := make([]any, 0, len(obj))
+	keys := make([]string, 0, len(obj))
+	for k, v := range obj {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	if exec.orderedKeys || exec.trace != nil {
+		slices.Sort(keys)
+		for i, k := range keys {
+			values[i] = obj[k]
+		}
+	}
+	return values, keys
 }
 
 // executeAnyItem is the implementation of several jsonpath nodes:
@@ -154,12 +198,16 @@ func collection(v any) []any {
 //   - ast.ConstAnyArray ([*] accessor)
 //
 // The value parameter must be a slice of values; the caller must properly
-// extract the values from a map. If found is not nil then resultStatus should
-// be ignored.
+// extract the values from a map, and pass their keys as keys so that errors
+// can report their locations. If found is not nil then resultStatus should
+// be ignored. The items of value are at level; it executes node against
+// those at levels first through last, and descends into containers only
+// while level is less than last, so that it never visits items below last.
 func (exec *Executor) executeAnyItem(
 	ctx context.Context,
 	node ast.Node,
 	value []any,
+	keys []string,
 	found *valueList,
 	level, first, last uint32,
 	ignoreStructuralErrors, unwrapNext bool,
@@ -170,6 +218,16 @@ func (exec *Executor) executeAnyItem(
 		return res, nil
 	}
 
+	if err := exec.descend(); err != nil {
+		return statusFailed, err
+	}
+	defer exec.ascend()
+
+	// The items of value are selected, not synthetic.
+	if exec.provenance {
+		defer exec.tempSetSynthetic(nil)()
+	}
+
 	// When found is not nil, executeAnyItem can return statusNotFound even
 	// when items were found. This seems to be because it returns the last
 	// result in the list it iterates over or from a recursive call. This
@@ -180,26 +238,53 @@ func (exec *Executor) executeAnyItem(
 	// result was.
 	size := 0
 	if found != nil {
-		size = len(found.list)
+		size = found.size()
+	}
+
+	if ignoreStructuralErrors && node != nil {
+		defer exec.tempSetIgnoreStructuralErrors(true)()
 	}
 
 	// Recursively iterate over jsonb objects/arrays
-	for _, v := range value {
-		col := collection(v)
+	loc := len(exec.location)
+	defer exec.leave(loc)
+	for i, v := range value {
+		// Check for interrupts.
+		if err := checkContext(ctx); err != nil {
+			return statusFailed, err
+		}
+		exec.leave(loc)
+		if keys != nil {
+			exec.enterKey(keys[i])
+		} else {
+			exec.enterIndex(i)
+		}
+		// Detect cycles by the original value, as normalize converts typed
+		// containers into new objects and arrays.
+		src := v
+		if v, err = exec.normalize(v); err != nil {
+			return statusFailed, exec.locate(err)
+		}
+
+		// Collect the members of v only to descend into them.
+		var col []any
+		var colKeys []string
+		if level < last {
+			col, colKeys = exec.collection(v)
+		}
 
 		if level >= first || (first == math.MaxUint32 && last == math.MaxUint32 && col == nil) {
 			// check expression
 			switch {
 			case node != nil:
-				if ignoreStructuralErrors {
-					defer exec.tempSetIgnoreStructuralErrors(true)()
-				}
 				res, err = exec.executeItemOptUnwrapTarget(ctx, node, v, found, unwrapNext)
 				if res.failed() || (res == statusOK && found == nil) {
 					return res, err
 				}
 			case found != nil:
-				found.append(v)
+				if err := found.append(v); err != nil {
+					return statusFailed, err
+				}
 				res = statusOK
 			default:
 				return statusOK, nil
@@ -207,9 +292,25 @@ func (exec *Executor) executeAnyItem(
 		}
 
 		if level < last {
+			// Refuse to descend into a container that contains itself.
+			addr := addrOf(src)
+			if len(col) > 0 {
+				if exec.descending[addr] {
+					return statusFailed, exec.locate(fmt.Errorf(
+						"%w: encountered a cycle via %T", ErrExecution, src,
+					))
+				}
+				if exec.descending == nil {
+					exec.descending = map[uintptr]bool{}
+				}
+				exec.descending[addr] = true
+			}
 			res, err = exec.executeAnyItem(
-				ctx, node, col, found, level+1, first, last, ignoreStructuralErrors, unwrapNext,
+				ctx, node, col, colKeys, found, level+1, first, last, ignoreStructuralErrors, unwrapNext,
 			)
+			if len(col) > 0 {
+				delete(exec.descending, addr)
+			}
 			if res.failed() || (res == statusOK && found == nil) {
 				return res, err
 			}
@@ -217,7 +318,7 @@ func (exec *Executor) executeAnyItem(
 	}
 
 	// Always return OK if items were found.
-	if found != nil && res != statusFailed && err == nil && len(found.list) > size {
+	if found != nil && res != statusFailed && err == nil && found.size() > size {
 		res = statusOK
 	}
 
@@ -239,8 +340,21 @@ func (exec *Executor) executeLikeRegex(_ context.Context, node ast.Node, value,
 	if !ok {
 		return predUnknown, nil
 	}
+	str, err := exec.validUTF8(str)
+	if err != nil {
+		_, err = exec.returnError(err)
+		return predUnknown, err
+	}
+
+	re, err := exec.compileRegex(rn)
+	if err != nil {
+		return predUnknown, fmt.Errorf("%w: %w", ErrExecution, err)
+	}
 
-	if rn.Regexp().MatchString(str) {
+	if exec.stats != nil {
+		exec.stats.RegexMatches++
+	}
+	if re.MatchString(str) {
 		return predTrue, nil
 	}
 	return predFalse, nil
@@ -248,16 +362,40 @@ func (exec *Executor) executeLikeRegex(_ context.Context, node ast.Node, value,
 
 // executeStartsWith is the STARTS_WITH predicate callback. It returns
 // predTrue when whole string starts with initial and predFalse if it does
-// not. Returns predUnknown if either whole or initial is not a string.
+// not. Returns predUnknown if whole is not a string or initial is null, and
+// a suppressible error if initial, the value of a variable, is not a string.
 // Implements predicateCallback.
-func executeStartsWith(_ context.Context, _ ast.Node, whole, initial any) (predOutcome, error) {
-	if str, ok := whole.(string); ok {
-		if prefix, ok := initial.(string); ok {
-			if strings.HasPrefix(str, prefix) {
-				return predTrue, nil
-			}
-			return predFalse, nil
-		}
+func (exec *Executor) executeStartsWith(_ context.Context, _ ast.Node, whole, initial any) (predOutcome, error) {
+	var prefix string
+	switch initial := initial.(type) {
+	case string:
+		prefix = initial
+	case nil:
+		return predUnknown, nil
+	default:
+		_, err := exec.returnError(fmt.Errorf(
+			"%w: right operand of jsonpath operator starts with is not a string",
+			errVerboseType,
+		))
+		return predUnknown, err
+	}
+
+	str, ok := whole.(string)
+	if !ok {
+		return predUnknown, nil
+	}
+
+	var err error
+	if str, err = exec.validUTF8(str); err == nil {
+		prefix, err = exec.validUTF8(prefix)
 	}
-	return predUnknown, nil
+	if err != nil {
+		_, err = exec.returnError(err)
+		return predUnknown, err
+	}
+
+	if strings.HasPrefix(str, prefix) {
+		return predTrue, nil
+	}
+	return predFalse, nil
 }
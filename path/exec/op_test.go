@@ -2,12 +2,16 @@ package exec
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/parser"
 	"github.com/theory/sqljson/path/types"
 )
 
@@ -124,7 +128,7 @@ func TestExecBinaryNode(t *testing.T) {
 			value: float64(12.233),
 			node:  ast.NewBinary(ast.BinaryDecimal, ast.NewInteger("4"), ast.NewInteger("2")),
 			exp:   statusOK,
-			find:  []any{float64(12.23)},
+			find:  []any{json.Number("12.23")},
 		},
 		{
 			name:  "subscript",
@@ -144,7 +148,7 @@ func TestExecBinaryNode(t *testing.T) {
 			t.Parallel()
 			e := newTestExecutor(laxRootPath, nil, true, false)
 			list := newList()
-			res, err := e.execBinaryNode(ctx, tc.node, tc.value, list, tc.unwrap)
+			res, err := e.executeNode(ctx, tc.node, tc.value, list, tc.unwrap)
 			a.Equal(tc.exp, res)
 
 			// Check the error and list.
@@ -350,7 +354,7 @@ func TestExecUnaryNode(t *testing.T) {
 			e := newTestExecutor(laxRootPath, nil, true, false)
 			e.root = tc.value
 			list := newList()
-			res, err := e.execUnaryNode(ctx, tc.node, tc.value, list, tc.unwrap)
+			res, err := e.executeNode(ctx, tc.node, tc.value, list, tc.unwrap)
 			a.Equal(tc.exp, res)
 
 			// Check the error and list.
@@ -592,6 +596,7 @@ func TestCollection(t *testing.T) {
 		name  string
 		value any
 		exp   []any
+		keys  []string
 	}{
 		{
 			name:  "slice",
@@ -600,8 +605,9 @@ func TestCollection(t *testing.T) {
 		},
 		{
 			name:  "map",
-			value: map[string]any{"x": "hi", "y": "hi"},
-			exp:   []any{"hi", "hi"},
+			value: map[string]any{"x": "hi", "y": "yo"},
+			exp:   []any{"hi", "yo"},
+			keys:  []string{"x", "y"},
 		},
 		{
 			name:  "int",
@@ -618,11 +624,122 @@ func TestCollection(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			a.Equal(tc.exp, collection(tc.value))
+			values, keys := (&Executor{}).collection(tc.value)
+			if tc.keys == nil {
+				a.Equal(tc.exp, values)
+				a.Nil(keys)
+				return
+			}
+
+			// Map order is random, but values must correspond to keys.
+			a.ElementsMatch(tc.keys, keys)
+			obj, _ := tc.value.(map[string]any)
+			for i, key := range keys {
+				a.Equal(obj[key], values[i])
+			}
+		})
+	}
+}
+
+func TestOrderedKeys(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	// Byte-wise order: digits, upper case, lower case, non-ASCII.
+	keys := []string{"10", "9", "B", "a", "aa", "b", "z", "é", "😀"}
+	obj := map[string]any{}
+	for i, key := range keys {
+		obj[key] = int64(i)
+	}
+	nested := map[string]any{"y": map[string]any{"b": "yb", "a": "ya"}, "x": "x"}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		exp   []any
+	}{
+		{
+			name:  "any_key",
+			path:  "$.*",
+			value: obj,
+			exp:   []any{int64(0), int64(1), int64(2), int64(3), int64(4), int64(5), int64(6), int64(7), int64(8)},
+		},
+		{
+			name:  "unwrapped_any_key",
+			path:  "lax $[*].*",
+			value: []any{map[string]any{"b": 2, "a": 1}, map[string]any{"d": 4, "c": 3}},
+			exp:   []any{1, 2, 3, 4},
+		},
+		{
+			name:  "any",
+			path:  "$.**",
+			value: nested,
+			exp:   []any{nested, "x", nested["y"], "ya", "yb"},
+		},
+		{
+			name:  "any_level",
+			path:  "$.**{2}",
+			value: nested,
+			exp:   []any{"ya", "yb"},
+		},
+		{
+			name:  "keyvalue",
+			path:  "$.*.keyvalue().key",
+			value: map[string]any{"o": map[string]any{"c": 1, "a": 2, "b": 3}},
+			exp:   []any{"a", "b", "c"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			// The order should be the same on every execution.
+			for range 10 {
+				res, err := Query(ctx, path, tc.value, WithOrderedKeys())
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+			}
+
+			// By default the order may vary.
+			res, err := Query(ctx, path, tc.value)
+			r.NoError(err)
+			a.ElementsMatch(tc.exp, res)
 		})
 	}
 }
 
+func TestSelectsOne(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	for _, tc := range []struct {
+		path string
+		exp  bool
+	}{
+		{"$[*]", true},
+		{"$[*].a", true},
+		{"$[*].a.b.size()", true},
+		{"$[*].double()", true},
+		{"$[*].keyvalue()", false},
+		{"$[*].a.keyvalue().key", false},
+		{"$[*] ? (@ > 1)", false},
+		{"$[*][*]", false},
+		{"$[*].*", false},
+		{"$[*].**", false},
+		{"$[*][0]", false},
+	} {
+		path, err := parser.Parse(tc.path)
+		r.NoError(err)
+		// Start after the wildcard array accessor.
+		a.Equal(tc.exp, selectsOne(path.Root().Next().Next()), tc.path)
+	}
+}
+
 func TestExecuteAnyItem(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
@@ -802,7 +919,7 @@ func TestExecuteAnyItem(t *testing.T) {
 
 			// Test with found first and ignore the result.
 			list := newList()
-			res, err := e.executeAnyItem(ctx, node, tc.value, list, 1, node.First(), node.Last(), tc.ignore, tc.unwrap)
+			res, err := e.executeAnyItem(ctx, node, tc.value, nil, list, 1, node.First(), node.Last(), tc.ignore, tc.unwrap)
 			a.Equal(tc.exp, res)
 			a.False(e.ignoreStructuralErrors)
 
@@ -817,7 +934,7 @@ func TestExecuteAnyItem(t *testing.T) {
 			}
 
 			// Test without found, pay attention to the result.
-			res, err = e.executeAnyItem(ctx, node, tc.value, nil, 1, node.First(), node.Last(), tc.ignore, tc.unwrap)
+			res, err = e.executeAnyItem(ctx, node, tc.value, nil, nil, 1, node.First(), node.Last(), tc.ignore, tc.unwrap)
 			a.False(e.ignoreStructuralErrors)
 			a.Equal(tc.exp, res)
 
@@ -832,6 +949,105 @@ func TestExecuteAnyItem(t *testing.T) {
 }
 
 // TestExecuteLikeRegex in exec_test.go tests happy paths.
+// levelDoc returns an object nested depth levels deep, each level of which
+// has width nested objects and a "b" member whose value is the number of
+// levels below it.
+func levelDoc(depth, width int) any {
+	if depth == 0 {
+		return int64(0)
+	}
+	obj := map[string]any{"b": int64(depth)}
+	for i := range width {
+		obj["k"+strconv.Itoa(i)] = levelDoc(depth-1, width)
+	}
+	return obj
+}
+
+func TestAnyLevelBounds(t *testing.T) {
+	t.Parallel()
+	const depth, width = 6, 4
+	doc := levelDoc(depth, width)
+
+	// Count the items at each level; level 0 is the root.
+	items := make([]int, depth+1)
+	items[0] = 1
+	for level, n := 1, 1; level <= depth; level++ {
+		items[level] = n * (width + 1)
+		n *= width
+	}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		first int
+		last  int
+	}{
+		{"level_2", "$.**{2}", 2, 2},
+		{"level_2_to_3", "$.**{2 to 3}", 2, 3},
+		{"level_0_to_1", "$.**{0 to 1}", 0, 1},
+		{"level_5_to_last", "$.**{5 to last}", 5, depth},
+		{"all", "$.**", 0, depth},
+		{"strict_level_2_to_3", "strict $.**{2 to 3}", 2, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			ctx := &countingContext{Context: context.Background()}
+			res, err := Query(ctx, path, doc)
+			r.NoError(err)
+
+			// Should select the items at levels first through last.
+			exp := []any{}
+			for level := tc.first; level <= tc.last; level++ {
+				levelPath, err := parser.Parse("$" + strings.Repeat(".*", level))
+				r.NoError(err)
+				vals, err := Query(context.Background(), levelPath, doc)
+				r.NoError(err)
+				exp = append(exp, vals...)
+			}
+			a.ElementsMatch(exp, res)
+
+			// Should visit only the items at levels 1 through last.
+			visits := 0
+			for _, n := range items[1 : tc.last+1] {
+				visits += n
+			}
+			a.LessOrEqual(ctx.calls, visits+2)
+		})
+	}
+}
+
+func BenchmarkAnyLevelBounds(b *testing.B) {
+	doc := levelDoc(6, 8)
+	for _, tc := range []struct {
+		name string
+		path string
+	}{
+		{"all", "$.**.b"},
+		{"level_2", "$.**{2}.b"},
+		{"level_2_to_3", "$.**{2 to 3}.b"},
+	} {
+		path, err := parser.Parse(tc.path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			ctx := &countingContext{Context: context.Background()}
+			for range b.N {
+				if _, err := Query(ctx, path, doc); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(ctx.calls)/float64(b.N), "visits/op")
+		})
+	}
+}
+
 func TestExecuteLikeRegexErrors(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
@@ -857,6 +1073,7 @@ func TestExecuteStartsWith(t *testing.T) {
 	a := assert.New(t)
 	r := require.New(t)
 	ctx := context.Background()
+	e := &Executor{}
 
 	for _, tc := range []struct {
 		name   string
@@ -896,9 +1113,105 @@ func TestExecuteStartsWith(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			res, err := executeStartsWith(ctx, nil, tc.str, tc.prefix)
+			res, err := e.executeStartsWith(ctx, nil, tc.str, tc.prefix)
 			a.Equal(tc.exp, res)
 			r.NoError(err)
 		})
 	}
 }
+
+func TestStartsWithVariable(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	vars := Vars{"p": "ab", "n": int64(1), "z": nil, "a": []any{"ab"}}
+	const notString = "exec: right operand of jsonpath operator starts with is not a string"
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		exp    []any
+		err    string
+		silent []any // result with WithSilent when err is not empty
+	}{
+		{
+			name: "string",
+			path: `$ starts with $p`,
+			exp:  []any{true},
+		},
+		{
+			name: "string_false",
+			path: `$[0] starts with $p`,
+			exp:  []any{false},
+		},
+		{
+			name: "string_filter",
+			path: `$[*] ? (@ starts with $p)`,
+			exp:  []any{"abc"},
+		},
+		{
+			name:   "number",
+			path:   `$ starts with $n`,
+			err:    notString,
+			silent: []any{nil},
+		},
+		{
+			name:   "number_strict",
+			path:   `strict $[1] starts with $n`,
+			err:    notString,
+			silent: []any{nil},
+		},
+		{
+			name:   "array",
+			path:   `$ starts with $a`,
+			err:    notString,
+			silent: []any{nil},
+		},
+		{
+			name: "number_filter",
+			path: `$[*] ? ((@ starts with $n) is unknown)`,
+			exp:  []any{"xbc", "abc"},
+		},
+		{
+			name: "null",
+			path: `$ starts with $z`,
+			exp:  []any{nil},
+		},
+		{
+			name: "null_filter",
+			path: `$[*] ? ((@ starts with $z) is unknown)`,
+			exp:  []any{"xbc", "abc"},
+		},
+		{
+			name: "missing",
+			path: `$ starts with $x`,
+			err:  `exec: could not find jsonpath variable "x"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			value := []any{"xbc", "abc"}
+
+			res, err := Query(ctx, path, value, WithVars(vars))
+			if tc.err == "" {
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+				return
+			}
+			r.EqualError(err, tc.err)
+			a.Nil(res)
+
+			res, err = Query(ctx, path, value, WithVars(vars), WithSilent())
+			if tc.silent == nil {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrVariable)
+			} else {
+				r.NoError(err)
+				a.Equal(tc.silent, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package exec
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/theory/sqljson/path/ast"
+)
+
+// DefaultParallelThreshold is the default minimum number of elements an
+// array must have for [WithParallel] to evaluate its wildcard array accessor
+// in parallel.
+const DefaultParallelThreshold = 4096
+
+// WithParallel evaluates the path following a wildcard array accessor
+// ([*]) across n goroutines when the array has at least
+// [DefaultParallelThreshold] elements, splitting it into n contiguous
+// chunks. Useful for filtering very large arrays, as in $[*] ? (@.x > 1).
+// Results and errors are the same as for sequential evaluation: results
+// appear in array order, and an error for an element prevents results from
+// subsequent elements, while results from preceding elements remain
+// available under [WithSilent]. Each goroutine stops once its own results
+// exceed the limits set by [WithMaxResults] or [WithMaxResultBytes], and
+// all stop once the merged results exceed them or an iterator such as
+// [Iter] stops. Execution remains sequential for n less than 2, for
+// accessors nested inside a parallel evaluation, and for paths that call
+// .keyvalue() after the accessor, since its generated IDs depend on the
+// order of evaluation. Execution returns an [ErrInvalid] error for a
+// negative n.
+func WithParallel(n int) Option { return func(e *Executor) { e.parallel = n } }
+
+// useParallel returns true if exec should pass array to
+// executeAnyArrayParallel to execute next against its elements.
+func (exec *Executor) useParallel(next ast.Node, array []any) bool {
+	return exec.parallel > 1 &&
+		exec.trace == nil &&
+		len(array) >= exec.parallelThreshold &&
+		next != nil &&
+		!hasKeyValue(next)
+}
+
+// fork returns a copy of exec to execute part of a parallel evaluation. The
+// copy has its own location, origins, compiled regular expressions, cycle
+// detection, and statistics, does not itself evaluate in parallel, and
+// locks mu to call the warning handler and logger.
+func (exec *Executor) fork(mu *sync.Mutex) *Executor {
+	worker := *exec
+	worker.location = slices.Clone(exec.location)
+	worker.origins = nil
+	worker.descending = nil
+	worker.regexes = nil
+	worker.memo = nil
+	worker.memoItems = nil
+	worker.memoStart = 0
+	worker.parallel = 0
+	worker.callbackMu = mu
+	if exec.stats != nil {
+		worker.stats = &Stats{}
+	}
+	return &worker
+}
+
+// chunkResult contains the result of executing a chunk of an array in
+// parallel.
+type chunkResult struct {
+	res     resultStatus
+	err     error
+	found   *valueList
+	origins map[uintptr]origin
+	stats   *Stats
+	done    chan struct{}
+}
+
+// workerList returns a valueList for a worker to collect the values it
+// finds for found. It starts with the limits of found, including the values
+// already counted, so that the worker stops once its own values exceed
+// them, but it collects values rather than passing them to found.yield.
+func workerList(worker *Executor, found *valueList) *valueList {
+	if found == nil {
+		return nil
+	}
+	vl := &valueList{counter: found.counter}
+	if found.limits != nil {
+		limits := *found.limits
+		vl.limits = &limits
+	}
+	if found.locator != nil {
+		vl.locator = worker
+	}
+	if found.provenance != nil {
+		vl.provenance = worker
+	}
+	return vl
+}
+
+// executeAnyArrayParallel is the parallel implementation of executeAnyItem
+// for the elements of array, without recursion. It splits array into
+// exec.parallel chunks and executes node against the elements of each in a
+// separate goroutine, merging the results of each chunk into found in array
+// order as soon as it and those preceding it complete. It stops at the first
+// chunk that stops execution, as executeAnyItem would, and when merging
+// exceeds the limits of found or its yield function returns false. Stopping
+// cancels the chunks that follow.
+func (exec *Executor) executeAnyArrayParallel(
+	ctx context.Context,
+	node ast.Node,
+	array []any,
+	found *valueList,
+	unwrapNext bool,
+) (resultStatus, error) {
+	if err := exec.descend(); err != nil {
+		return statusFailed, err
+	}
+	defer exec.ascend()
+
+	n := min(exec.parallel, len(array))
+	size := (len(array) + n - 1) / n
+	results := make([]chunkResult, n)
+	cancels := make([]context.CancelFunc, n)
+	ctxs := make([]context.Context, n)
+	for i := range n {
+		ctxs[i], cancels[i] = context.WithCancel(ctx)
+		results[i].done = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	defer func() {
+		// Cancel any chunks still running and wait for them to finish
+		// before collecting their statistics.
+		for _, cancel := range cancels {
+			cancel()
+		}
+		wg.Wait()
+		if exec.stats != nil {
+			for _, result := range results {
+				exec.stats.add(result.stats)
+			}
+		}
+	}()
+
+	// Fork the workers before starting them, since merging modifies exec
+	// and found while they run.
+	var mu sync.Mutex
+	workers := make([]*Executor, n)
+	for i := range n {
+		workers[i] = exec.fork(&mu)
+		results[i].found = workerList(workers[i], found)
+	}
+
+	for i, worker := range workers {
+		start := i * size
+		end := min(start+size, len(array))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := &results[i]
+			defer close(result.done)
+			result.res, result.err = worker.executeChunk(
+				ctxs[i], node, array[start:end], start, result.found, unwrapNext,
+			)
+			result.origins = worker.origins
+			result.stats = worker.stats
+			if result.res.failed() || (result.res == statusOK && found == nil) {
+				// Subsequent chunks cannot affect the outcome.
+				for _, cancel := range cancels[i+1:] {
+					cancel()
+				}
+			}
+		}()
+	}
+
+	res := statusNotFound
+	var err error
+	start := 0
+	if found != nil {
+		start = found.size()
+	}
+	for i := range results {
+		result := &results[i]
+		<-result.done
+		exec.mergeOrigins(result.origins)
+		if found != nil {
+			if err := found.appendList(result.found); err != nil {
+				return statusFailed, err
+			}
+		}
+		res, err = result.res, result.err
+		if res.failed() || (res == statusOK && found == nil) {
+			return res, err
+		}
+	}
+
+	// Always return OK if items were found.
+	if found != nil && err == nil && found.size() > start {
+		res = statusOK
+	}
+
+	return res, err
+}
+
+// executeChunk executes node against each value in chunk, the elements of
+// an array starting at index offset, like executeAnyItem at its first
+// level.
+func (exec *Executor) executeChunk(
+	ctx context.Context,
+	node ast.Node,
+	chunk []any,
+	offset int,
+	found *valueList,
+	unwrapNext bool,
+) (resultStatus, error) {
+	res := statusNotFound
+	var err error
+	loc := len(exec.location)
+	defer exec.leave(loc)
+	if exec.provenance {
+		defer exec.tempSetSynthetic(nil)()
+	}
+	for i, v := range chunk {
+		// Check for interrupts.
+		if err := checkContext(ctx); err != nil {
+			return statusFailed, err
+		}
+		exec.leave(loc)
+		exec.enterIndex(offset + i)
+		if v, err = exec.normalize(v); err != nil {
+			return statusFailed, err
+		}
+		res, err = exec.executeItemOptUnwrapTarget(ctx, node, v, found, unwrapNext)
+		if res.failed() || (res == statusOK && found == nil) {
+			return res, err
+		}
+	}
+	return res, err
+}
+
+// mergeOrigins records the original Go values in origins, as recorded by a
+// forked Executor, in exec.origins.
+func (exec *Executor) mergeOrigins(origins map[uintptr]origin) {
+	if len(origins) == 0 {
+		return
+	}
+	if exec.origins == nil {
+		exec.origins = make(map[uintptr]origin, len(origins))
+	}
+	for addr, orig := range origins {
+		exec.origins[addr] = orig
+	}
+}
@@ -0,0 +1,281 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+)
+
+// withParallelThreshold sets the parallel threshold to n so that tests can
+// exercise parallel execution with small arrays.
+func withParallelThreshold(n int) Option {
+	return func(e *Executor) { e.parallelThreshold = n }
+}
+
+func TestUseParallel(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	array := []any{int64(1), int64(2), int64(3), int64(4)}
+
+	for _, tc := range []struct {
+		name      string
+		path      string
+		parallel  int
+		threshold int
+		exp       bool
+	}{
+		{"filter", "$[*] ? (@ > 1)", 4, 4, true},
+		{"method", "$[*].double()", 2, 1, true},
+		{"not_parallel", "$[*] ? (@ > 1)", 0, 4, false},
+		{"one", "$[*] ? (@ > 1)", 1, 4, false},
+		{"too_small", "$[*] ? (@ > 1)", 4, 5, false},
+		{"no_next", "$[*]", 4, 4, false},
+		{"keyvalue", "$[*].keyvalue()", 4, 4, false},
+		{"nested_keyvalue", "$[*] ? (@.keyvalue().key == \"a\")", 4, 4, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			e, err := newExec(path, WithParallel(tc.parallel), withParallelThreshold(tc.threshold))
+			r.NoError(err)
+			node := path.Root().Next().Next()
+			a.Equal(tc.exp, e.useParallel(node, array))
+		})
+	}
+}
+
+func TestParallelQuery(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	// Create an array of objects with a few that trigger errors.
+	array := make([]any, 100)
+	for i := range array {
+		array[i] = map[string]any{"n": int64(i), "s": fmt.Sprint(i)}
+	}
+	array[42] = map[string]any{"n": "forty-two"}
+	array[77] = map[string]any{"s": "77"}
+
+	for _, tc := range []struct {
+		name string
+		path string
+		opt  []Option
+	}{
+		{name: "filter", path: `$[*] ? (@.s starts with "1")`},
+		{name: "filter_next", path: `$[*] ? (@.s like_regex "^[23]").n`},
+		{name: "filter_none", path: `$[*] ? (@.s == "nope")`},
+		{name: "method", path: `$[*].s.size()`},
+		{name: "lax_missing", path: `lax $[*].n`},
+		{name: "strict_missing", path: `strict $[*].n`},
+		{name: "strict_missing_silent", path: `strict $[*].n`, opt: []Option{WithSilent()}},
+		{name: "strict_filter", path: `strict $[*] ? (@.n > 90)`},
+		{name: "arithmetic", path: `$[*].n * 2`},
+		{name: "arithmetic_silent", path: `$[*].n * 2`, opt: []Option{WithSilent()}},
+		{name: "nested", path: `$[*] ? (@.n < 10) ? (exists($[*] ? (@.n == 3)))`},
+		{name: "last", path: `$[*] ? (@.n == $[last].n)`},
+		{name: "keyvalue", path: `$[*].keyvalue() ? (@.key == "n").id`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			exp, expErr := Query(ctx, path, array, tc.opt...)
+			for _, n := range []int{2, 3, 7, 100, 200} {
+				opt := append([]Option{WithParallel(n), withParallelThreshold(1)}, tc.opt...)
+				res, err := Query(ctx, path, array, opt...)
+				a.Equal(exp, res, "parallel %v", n)
+				if expErr == nil {
+					a.NoError(err, "parallel %v", n)
+				} else {
+					a.EqualError(err, expErr.Error(), "parallel %v", n)
+				}
+
+				ok, err := Exists(ctx, path, array, opt...)
+				expOK, expErr := Exists(ctx, path, array, tc.opt...)
+				a.Equal(expOK, ok, "parallel %v", n)
+				if expErr == nil {
+					a.NoError(err, "parallel %v", n)
+				} else {
+					a.EqualError(err, expErr.Error(), "parallel %v", n)
+				}
+			}
+		})
+	}
+}
+
+func TestParallelOrigins(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	// Typed maps are converted lazily, recording origins in each worker.
+	array := make([]any, 50)
+	for i := range array {
+		array[i] = map[string]int{"n": i}
+	}
+
+	path, err := parser.Parse("$[*] ? (@.n % 10 == 0)")
+	r.NoError(err)
+	exp, err := Query(ctx, path, array)
+	r.NoError(err)
+	a.Len(exp, 5)
+	res, err := Query(ctx, path, array, WithParallel(4), withParallelThreshold(1))
+	r.NoError(err)
+	a.Equal(exp, res)
+	a.Equal([]any{array[0], array[10], array[20], array[30], array[40]}, res)
+}
+
+func TestParallelCanceled(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	array := make([]any, 1000)
+	for i := range array {
+		array[i] = int64(i)
+	}
+
+	path, err := parser.Parse("$[*] ? (@ > 10)")
+	r.NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Query(ctx, path, array, WithParallel(4), withParallelThreshold(1))
+	r.ErrorIs(err, context.Canceled)
+}
+
+func TestParallelLimits(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	array := make([]any, 100)
+	for i := range array {
+		array[i] = map[string]any{"n": int64(i)}
+	}
+	array[60] = map[string]any{}
+
+	for _, tc := range []struct {
+		name string
+		path string
+		opt  []Option
+	}{
+		{name: "under_max", path: `$[*].n`, opt: []Option{WithMaxResults(100)}},
+		{name: "max_first_chunk", path: `$[*].n`, opt: []Option{WithMaxResults(5)}},
+		{name: "max_later_chunk", path: `$[*].n`, opt: []Option{WithMaxResults(50)}},
+		{name: "max_bytes", path: `$[*]`, opt: []Option{WithMaxResultBytes(300)}},
+		{name: "under_max_bytes", path: `$[*]`, opt: []Option{WithMaxResultBytes(3000)}},
+		{name: "error_first", path: `strict $[*].n`, opt: []Option{WithMaxResults(80)}},
+		{name: "limit_first", path: `strict $[*].n`, opt: []Option{WithMaxResults(20)}},
+		{name: "silent", path: `strict $[*].n`, opt: []Option{WithMaxResults(70), WithSilent()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			exp, expErr := Query(ctx, path, array, tc.opt...)
+			for _, n := range []int{2, 3, 7} {
+				opt := append([]Option{WithParallel(n), withParallelThreshold(1)}, tc.opt...)
+				res, err := Query(ctx, path, array, opt...)
+				a.Equal(exp, res, "parallel %v", n)
+				if expErr == nil {
+					a.NoError(err, "parallel %v", n)
+				} else {
+					a.EqualError(err, expErr.Error(), "parallel %v", n)
+				}
+			}
+		})
+	}
+
+	t.Run("workers_stop", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+
+		large := make([]any, 10_000)
+		for i := range large {
+			large[i] = int64(i)
+		}
+		path, err := parser.Parse(`$[*] ? (@ >= 0)`)
+		r.NoError(err)
+
+		// Each worker stops once its own results exceed the limit.
+		var stats Stats
+		_, err = Query(
+			ctx, path, large, WithMaxResults(5), WithStats(&stats),
+			WithParallel(4), withParallelThreshold(1),
+		)
+		r.ErrorIs(err, ErrLimit)
+		a.LessOrEqual(stats.FilterEvaluations, 4*6)
+	})
+}
+
+func TestParallelCallbacks(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	array := make([]any, 200)
+	for i := range array {
+		array[i] = "12:34:56.123"
+	}
+	path, err := parser.Parse(`$[*].time(7)`)
+	r.NoError(err)
+
+	// Calls to the warning handler never overlap.
+	var active, overlaps atomic.Int32
+	warnings := 0
+	res, err := Query(
+		context.Background(), path, array,
+		WithParallel(8), withParallelThreshold(1),
+		WithWarningHandler(func(string) {
+			if !active.CompareAndSwap(0, 1) {
+				overlaps.Add(1)
+			}
+			warnings++
+			time.Sleep(time.Microsecond)
+			active.Store(0)
+		}),
+	)
+	r.NoError(err)
+	a.Len(res, len(array))
+	a.Equal(len(array), warnings)
+	a.Zero(overlaps.Load())
+}
+
+func BenchmarkParallelFilter(b *testing.B) {
+	const size = 1_000_000
+	array := make([]any, size)
+	for i := range array {
+		array[i] = map[string]any{"id": int64(i), "name": fmt.Sprintf("item %v", i)}
+	}
+
+	path, err := parser.Parse(`$[*] ? (@.id % 7 == 0 && @.name like_regex "9$")`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, n := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("parallel_%v", n), func(b *testing.B) {
+			for range b.N {
+				if _, err := Query(ctx, path, array, WithParallel(n)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
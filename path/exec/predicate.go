@@ -61,27 +61,33 @@ func (exec *Executor) executePredicate(
 	found := false
 
 	// Left argument is always auto-unwrapped.
-	lSeq := newList()
-	res, err := exec.executeItemOptUnwrapResultSilent(ctx, left, value, true, lSeq)
+	lSeq := getList()
+	defer putList(lSeq)
+	res, err := exec.executeOperand(ctx, left, value, true, lSeq)
 	if res == statusFailed {
 		return predUnknown, err
 	}
 
-	rSeq := newList()
+	rSeq := getList()
+	defer putList(rSeq)
 	if right != nil {
 		// Right argument is conditionally auto-unwrapped.
-		res, err := exec.executeItemOptUnwrapResultSilent(ctx, right, value, unwrapRightArg, rSeq)
+		res, err := exec.executeOperand(ctx, right, value, unwrapRightArg, rSeq)
 		if res == statusFailed {
 			return predUnknown, err
 		}
 	} else {
-		// Right arg is nil.
-		rSeq.append(nil)
+		// Right arg is nil. Intermediate lists have no limits.
+		_ = rSeq.append(nil)
 	}
 
 	for _, lVal := range lSeq.list {
 		// Loop over right arg sequence.
 		for _, rVal := range rSeq.list {
+			// Check for interrupts.
+			if err := checkContext(ctx); err != nil {
+				return predUnknown, err
+			}
 			res, err := callback(ctx, pred, lVal, rVal)
 			if err != nil {
 				return predUnknown, err
@@ -41,7 +41,7 @@ func TestPredicateCallback(t *testing.T) {
 
 	e := newTestExecutor(laxRootPath, nil, true, false)
 	a.IsType((predicateCallback)(nil), predicateCallback(e.compareItems))
-	a.IsType((predicateCallback)(nil), predicateCallback(executeStartsWith))
+	a.IsType((predicateCallback)(nil), predicateCallback(e.executeStartsWith))
 	a.IsType((predicateCallback)(nil), predicateCallback(e.executeLikeRegex))
 }
 
@@ -70,7 +70,7 @@ func TestExecutePredicate(t *testing.T) {
 			path:     laxRootPath,
 			left:     ast.NewMethod(ast.MethodBigInt),
 			value:    "hi",
-			callback: func(_ *Executor) predicateCallback { return executeStartsWith },
+			callback: func(e *Executor) predicateCallback { return e.executeStartsWith },
 			exp:      predUnknown,
 		},
 		{
@@ -79,7 +79,7 @@ func TestExecutePredicate(t *testing.T) {
 			left:     ast.NewInteger("42"),
 			right:    ast.NewMethod(ast.MethodBigInt),
 			value:    "hi",
-			callback: func(_ *Executor) predicateCallback { return executeStartsWith },
+			callback: func(e *Executor) predicateCallback { return e.executeStartsWith },
 			exp:      predUnknown,
 		},
 		{
@@ -0,0 +1,94 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/theory/sqljson/path/ast"
+	"golang.org/x/exp/maps" // Switch to maps when go 1.22 dropped
+)
+
+// Project executes rows against value and returns an object for each item
+// it selects, with a member for each of columns, which maps member names to
+// paths. Execution of each column path treats the row item as its root, $,
+// so that, as in the COLUMNS clause of the SQL/JSON JSON_TABLE() function,
+// `$.id` selects the "id" member of each row. Variables passed to
+// [WithVars] are available to all the paths. For example, given the
+// rows path `$.items[*] ? (@.qty > 0)` and the column paths `$.id` and
+// `$.qty` named "id" and "qty", Project returns an object with the id and
+// qty of each item with a positive qty.
+//
+// A column path that selects no items, such as a lax mode path for a
+// missing member, leaves its member out of the row object, while a strict
+// mode path for a missing member returns an error, as do column paths that
+// select more than one item. Column paths may be predicate check
+// expressions, whose boolean results become member values. Errors from
+// column paths wrap the underlying error and name the column and the index
+// of the row. The options act the same as for [Query], and apply to all the
+// paths.
+func Project(
+	ctx context.Context,
+	rows *ast.AST,
+	value any,
+	columns map[string]*ast.AST,
+	opt ...Option,
+) ([]map[string]any, error) {
+	exec, err := newExec(rows, opt...)
+	if err != nil {
+		return nil, err
+	}
+	if err := exec.checkPredicate("Project", false); err != nil {
+		return nil, err
+	}
+
+	// Set up execution of each column once, as QueryBatch does. Sort the
+	// names to report errors deterministically.
+	names := maps.Keys(columns)
+	slices.Sort(names)
+	cols := make([]*Executor, len(names))
+	origins := make([]map[uintptr]origin, len(names))
+	for i, name := range names {
+		if cols[i], err = newExec(columns[name], opt...); err != nil {
+			return nil, err
+		}
+		if err := cols[i].convertVars(ctx); err != nil {
+			return nil, err
+		}
+		origins[i] = cols[i].origins
+	}
+
+	items, err := exec.execute(ctx, value, exec.newResultList())
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]map[string]any, len(items.list))
+	for i, item := range items.list {
+		row := make(map[string]any, len(names))
+		for j, col := range cols {
+			if err := checkContext(ctx); err != nil {
+				return nil, err
+			}
+
+			col.reset(origins[j])
+			vals, err := col.queryAll(ctx, item)
+			if err != nil {
+				return nil, fmt.Errorf("%w (column %q of row %d)", err, names[j], i)
+			}
+			switch len(vals) {
+			case 0:
+				// Leave the member out.
+			case 1:
+				row[names[j]] = vals[0]
+			default:
+				return nil, fmt.Errorf(
+					"%w: column %q of row %d selected %d items but may select at most one",
+					ErrExecution, names[j], i, len(vals),
+				)
+			}
+		}
+		res[i] = row
+	}
+	return res, nil
+}
@@ -0,0 +1,226 @@
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestProject(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	value := map[string]any{
+		"orders": []any{
+			map[string]any{
+				"id": "o1",
+				"items": []any{
+					map[string]any{"id": int64(1), "qty": int64(2), "dims": map[string]any{"w": int64(3)}},
+					map[string]any{"id": int64(2), "qty": int64(0)},
+				},
+			},
+			map[string]any{
+				"id": "o2",
+				"items": []any{
+					map[string]any{"id": int64(3), "qty": int64(5), "tags": []any{"a", "b"}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		rows    string
+		columns map[string]string
+		opt     []Option
+		exp     []map[string]any
+		err     string
+	}{
+		{
+			name:    "rows",
+			rows:    `$.orders[*].items[*]`,
+			columns: map[string]string{"id": `$.id`, "qty": `$.qty`},
+			exp: []map[string]any{
+				{"id": int64(1), "qty": int64(2)},
+				{"id": int64(2), "qty": int64(0)},
+				{"id": int64(3), "qty": int64(5)},
+			},
+		},
+		{
+			name:    "filter",
+			rows:    `$.orders[*].items[*] ? (@.qty > 0)`,
+			columns: map[string]string{"id": `$.id`, "qty": `$.qty`},
+			exp: []map[string]any{
+				{"id": int64(1), "qty": int64(2)},
+				{"id": int64(3), "qty": int64(5)},
+			},
+		},
+		{
+			name:    "nested_filter",
+			rows:    `$.orders[*] ? (exists(@.items[*] ? (@.qty == 0)))`,
+			columns: map[string]string{"order": `$.id`, "count": `$.items.size()`},
+			exp:     []map[string]any{{"order": "o1", "count": int64(2)}},
+		},
+		{
+			name: "nested_members",
+			rows: `$.orders[*].items[*]`,
+			columns: map[string]string{
+				"id":    `$.id`,
+				"width": `$.dims.w`,
+				"dims":  `$.dims`,
+				"tags":  `$.tags`,
+			},
+			exp: []map[string]any{
+				{"id": int64(1), "width": int64(3), "dims": map[string]any{"w": int64(3)}},
+				{"id": int64(2)},
+				{"id": int64(3), "tags": []any{"a", "b"}},
+			},
+		},
+		{
+			name:    "nested_rows",
+			rows:    `$.orders[*]`,
+			columns: map[string]string{"id": `$.id`, "first": `$.items[0].id`, "qty": `$.items[last].qty`},
+			exp: []map[string]any{
+				{"id": "o1", "first": int64(1), "qty": int64(0)},
+				{"id": "o2", "first": int64(3), "qty": int64(5)},
+			},
+		},
+		{
+			name:    "computed",
+			rows:    `$.orders[*].items[*]`,
+			columns: map[string]string{"id": `$.id`, "double": `$.qty * 2`, "big": `$.qty > $min`},
+			opt:     []Option{WithVars(Vars{"min": int64(1)})},
+			exp: []map[string]any{
+				{"id": int64(1), "double": int64(4), "big": true},
+				{"id": int64(2), "double": int64(0), "big": false},
+				{"id": int64(3), "double": int64(10), "big": true},
+			},
+		},
+		{
+			name:    "no_rows",
+			rows:    `$.orders[*] ? (@.id == "o3")`,
+			columns: map[string]string{"id": `$.id`},
+			exp:     []map[string]any{},
+		},
+		{
+			name:    "no_columns",
+			rows:    `$.orders[*]`,
+			columns: map[string]string{},
+			exp:     []map[string]any{{}, {}},
+		},
+		{
+			name:    "strict_missing",
+			rows:    `$.orders[*].items[*]`,
+			columns: map[string]string{"id": `$.id`, "dims": `strict $.dims`},
+			err:     `exec: JSON object does not contain key "dims" (column "dims" of row 1)`,
+		},
+		{
+			name:    "strict_missing_silent",
+			rows:    `$.orders[*].items[*]`,
+			columns: map[string]string{"id": `$.id`, "dims": `strict $.dims.w`},
+			opt:     []Option{WithSilent()},
+			exp: []map[string]any{
+				{"id": int64(1), "dims": int64(3)},
+				{"id": int64(2)},
+				{"id": int64(3)},
+			},
+		},
+		{
+			name:    "multiple_items",
+			rows:    `$.orders[*].items[*]`,
+			columns: map[string]string{"id": `$.id`, "tags": `$.tags[*]`},
+			err:     `exec: column "tags" of row 2 selected 2 items but may select at most one`,
+		},
+		{
+			name:    "rows_error",
+			rows:    `strict $.order[*]`,
+			columns: map[string]string{"id": `$.id`},
+			err:     `exec: JSON object does not contain key "order"`,
+		},
+		{
+			name:    "undefined_variable",
+			rows:    `$.orders[*]`,
+			columns: map[string]string{"id": `$.id`, "x": `$x`},
+			err:     `exec: could not find jsonpath variable "x" (column "x" of row 0)`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			rows, err := parser.Parse(tc.rows)
+			r.NoError(err)
+			columns := make(map[string]*ast.AST, len(tc.columns))
+			for name, path := range tc.columns {
+				columns[name], err = parser.Parse(path)
+				r.NoError(err)
+			}
+
+			res, err := Project(ctx, rows, value, columns, tc.opt...)
+			if tc.err == "" {
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+			} else {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(res)
+			}
+		})
+	}
+}
+
+func TestProjectNested(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	value := []any{
+		map[string]any{"name": "a", "kids": []any{
+			map[string]any{"name": "a1", "age": int64(3)},
+			map[string]any{"name": "a2", "age": int64(7)},
+		}},
+		map[string]any{"name": "b", "kids": []any{}},
+	}
+	parse := func(path string) *ast.AST {
+		tree, err := parser.Parse(path)
+		r.NoError(err)
+		return tree
+	}
+
+	// Project the parents, keeping the kids to project in turn.
+	parents, err := Project(ctx, parse(`$[*]`), value, map[string]*ast.AST{
+		"parent": parse(`$.name`),
+		"kids":   parse(`$.kids`),
+	})
+	r.NoError(err)
+	r.Len(parents, 2)
+
+	kidRows := parse(`$.kids[*] ? (@.age > $min)`)
+	kidCols := map[string]*ast.AST{"kid": parse(`$.name`), "age": parse(`$.age`)}
+	res := []map[string]any{}
+	for _, parent := range parents {
+		kids, err := Project(ctx, kidRows, parent, kidCols, WithVars(Vars{"min": int64(1)}))
+		r.NoError(err)
+		for _, kid := range kids {
+			kid["parent"] = parent["parent"]
+			res = append(res, kid)
+		}
+	}
+	a.Equal([]map[string]any{
+		{"parent": "a", "kid": "a1", "age": int64(3)},
+		{"parent": "a", "kid": "a2", "age": int64(7)},
+	}, res)
+
+	// Predicate check expressions are not row sources.
+	_, err = Project(ctx, parse(`$[*].name == "a"`), value, kidCols, WithPredicateCheck())
+	r.EqualError(
+		err,
+		`exec: Project expects a SQL standard path expression but "($[*].\"name\" == \"a\")" is a predicate check expression`,
+	)
+}
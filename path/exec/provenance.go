@@ -0,0 +1,93 @@
+package exec
+
+import (
+	"context"
+
+	"github.com/theory/sqljson/path/ast"
+)
+
+// Result is a JSON item selected by [Query] or [QueryArray] with
+// [WithProvenance], along with whether execution constructed it.
+type Result struct {
+	// Value is the selected item.
+	Value any
+
+	// Synthetic is true when execution constructed Value, and false when
+	// it selected Value from the queried value or a variable.
+	Synthetic bool
+}
+
+// WithProvenance makes [Query] and [QueryArray] return [Result] values
+// rather than the selected items themselves, to distinguish items selected
+// from the queried value or a variable, which alias it, from items
+// constructed by the path, which are independent of it. Other query
+// functions ignore the option.
+//
+// Synthetic items include the objects generated by .keyvalue(); the results
+// of predicates, including comparisons, exists, and is unknown; the results
+// of arithmetic operators and built-in item methods, such as .size(),
+// .double(), and .datetime(); and literals. Accessors, wildcards, and .**
+// select items rather than construct them, as do filters, which select the
+// items they're applied to, so that $.a ? (@ > 1) is not synthetic while
+// $.a.size() ? (@ > 1) is. Items selected from a synthetic object, such as
+// the value of an object generated by .keyvalue(), are not synthetic, and
+// neither are the results of custom methods, which may return their input.
+//
+// A synthetic object or array may contain items of the queried value, such
+// as the values of objects generated by .keyvalue(), so modifying them can
+// modify the queried value. [WithCopyResults] returns copies of all items.
+//
+// This option diverges from PostgreSQL, which always returns copies.
+func WithProvenance() Option { return func(e *Executor) { e.provenance = true } }
+
+// synthesizes returns true if cur constructs the item it passes to the next
+// node, and false if it selects it. Returns input, whether the item cur was
+// applied to is synthetic, for filters.
+func synthesizes(cur ast.Node, input bool) bool {
+	switch cur := cur.(type) {
+	case nil, *ast.KeyNode, *ast.ArrayIndexNode, *ast.AnyNode, *ast.VariableNode:
+		return false
+	case *ast.ConstNode:
+		switch cur.Const() {
+		case ast.ConstRoot, ast.ConstCurrent, ast.ConstAnyKey, ast.ConstAnyArray:
+			return false
+		case ast.ConstNull, ast.ConstTrue, ast.ConstFalse, ast.ConstLast:
+			return true
+		}
+	case *ast.UnaryNode:
+		if cur.Operator() == ast.UnaryFilter {
+			return input
+		}
+	case *ast.MethodNode:
+		// Custom methods may return their input.
+		return cur.Custom() == ""
+	}
+	return true
+}
+
+// tempSetSynthetic records whether the item cur passes to the next node is
+// synthetic, and returns a function that restores the previous state.
+func (exec *Executor) tempSetSynthetic(cur ast.Node) func() {
+	saved := exec.synthetic
+	exec.synthetic = synthesizes(cur, saved)
+	return func() { exec.synthetic = saved }
+}
+
+// queryResults executes exec.path against value and returns all selected
+// values as [Result] values.
+func (exec *Executor) queryResults(ctx context.Context, value any) ([]any, error) {
+	vals := exec.newResultList()
+	vals.provenance = exec
+	vals.synthetic = []bool{}
+	if _, err := exec.execute(ctx, value, vals); err != nil {
+		return nil, err
+	}
+	for i, val := range vals.list {
+		val, err := exec.result(val)
+		if err != nil {
+			return nil, err
+		}
+		vals.list[i] = Result{Value: val, Synthetic: vals.synthetic[i]}
+	}
+	return vals.list, nil
+}
@@ -0,0 +1,235 @@
+package exec
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+	"github.com/theory/sqljson/path/types"
+)
+
+func TestProvenance(t *testing.T) {
+	t.Parallel()
+	registerTestMethods()
+	ctx := context.Background()
+	obj := map[string]any{"x": int64(1)}
+	arr := []any{int64(1), int64(2)}
+	doc := map[string]any{"a": obj, "b": arr, "s": "2024-01-02", "n": int64(-3)}
+
+	for _, tc := range []struct {
+		name string
+		path string
+		opt  []Option
+		exp  []Result
+	}{
+		// Selected items.
+		{
+			name: "root",
+			path: `$`,
+			exp:  []Result{{Value: doc}},
+		},
+		{
+			name: "key",
+			path: `$.a`,
+			exp:  []Result{{Value: obj}},
+		},
+		{
+			name: "index",
+			path: `$.b[1]`,
+			exp:  []Result{{Value: int64(2)}},
+		},
+		{
+			name: "array_wildcard",
+			path: `$.b[*]`,
+			exp:  []Result{{Value: int64(1)}, {Value: int64(2)}},
+		},
+		{
+			name: "lax_unwrap",
+			path: `$.b.abs()`,
+			exp:  []Result{{Value: int64(1), Synthetic: true}, {Value: int64(2), Synthetic: true}},
+		},
+		{
+			name: "any",
+			path: `$.**.x`,
+			exp:  []Result{{Value: int64(1)}},
+		},
+		{
+			name: "variable",
+			path: `$v`,
+			opt:  []Option{WithVars(Vars{"v": arr})},
+			exp:  []Result{{Value: arr}},
+		},
+		{
+			name: "filter",
+			path: `$.a ? (@.x == 1)`,
+			exp:  []Result{{Value: obj}},
+		},
+		{
+			name: "keyvalue_value",
+			path: `$.a.keyvalue().value`,
+			exp:  []Result{{Value: int64(1)}},
+		},
+		{
+			name: "custom_method",
+			path: `$.s.test_upper()`,
+			exp:  []Result{{Value: "2024-01-02"}},
+		},
+
+		// Constructed items.
+		{
+			name: "keyvalue",
+			path: `$.a.keyvalue()`,
+			exp: []Result{{
+				Value:     map[string]any{"key": "x", "value": int64(1), "id": int64(1)},
+				Synthetic: true,
+			}},
+		},
+		{
+			name: "keyvalue_filter",
+			path: `$.a.keyvalue() ? (@.key == "x")`,
+			exp: []Result{{
+				Value:     map[string]any{"key": "x", "value": int64(1), "id": int64(1)},
+				Synthetic: true,
+			}},
+		},
+		{
+			name: "comparison",
+			path: `$.a.x == 1`,
+			exp:  []Result{{Value: true, Synthetic: true}},
+		},
+		{
+			name: "is_unknown",
+			path: `($.a == 1) is unknown`,
+			exp:  []Result{{Value: true, Synthetic: true}},
+		},
+		{
+			name: "unknown",
+			path: `$.a == 1`,
+			exp:  []Result{{Value: nil, Synthetic: true}},
+		},
+		{
+			name: "exists",
+			path: `exists($.a)`,
+			exp:  []Result{{Value: true, Synthetic: true}},
+		},
+		{
+			name: "like_regex",
+			path: `$.s like_regex "^2024"`,
+			exp:  []Result{{Value: true, Synthetic: true}},
+		},
+		{
+			name: "arithmetic",
+			path: `$.n + 1`,
+			exp:  []Result{{Value: int64(-2), Synthetic: true}},
+		},
+		{
+			name: "unary_minus",
+			path: `-$.n`,
+			exp:  []Result{{Value: int64(3), Synthetic: true}},
+		},
+		{
+			name: "numeric_method",
+			path: `$.n.abs()`,
+			exp:  []Result{{Value: int64(3), Synthetic: true}},
+		},
+		{
+			name: "numeric_method_filter",
+			path: `$.n.abs() ? (@ > 1)`,
+			exp:  []Result{{Value: int64(3), Synthetic: true}},
+		},
+		{
+			name: "size",
+			path: `$.b.size()`,
+			exp:  []Result{{Value: int64(2), Synthetic: true}},
+		},
+		{
+			name: "datetime",
+			path: `$.s.datetime()`,
+			exp: []Result{{
+				Value:     types.NewDate(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+				Synthetic: true,
+			}},
+		},
+		{
+			name: "literal",
+			path: `"hi"`,
+			exp:  []Result{{Value: "hi", Synthetic: true}},
+		},
+		{
+			name: "last",
+			path: `$.b[last]`,
+			exp:  []Result{{Value: int64(2)}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			opt := append([]Option{WithProvenance()}, tc.opt...)
+
+			exp := make([]any, len(tc.exp))
+			for i, res := range tc.exp {
+				exp[i] = res
+			}
+
+			res, err := Query(ctx, path, doc, opt...)
+			r.NoError(err)
+			a.Equal(exp, res)
+
+			res, err = QueryArray(ctx, path, doc, opt...)
+			r.NoError(err)
+			a.Equal(exp, res)
+
+		})
+	}
+}
+
+func TestProvenanceAlias(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	doc := js(`{"a": {"b": 1}, "c": [{"d": 2}, {"d": 3}]}`)
+
+	// Modifying a selected object modifies the document.
+	path, err := parser.Parse(`$.a`)
+	r.NoError(err)
+	res, err := Query(ctx, path, doc, WithProvenance())
+	r.NoError(err)
+	r.Len(res, 1)
+	item, ok := res[0].(Result)
+	r.True(ok)
+	a.False(item.Synthetic)
+	item.Value.(map[string]any)["b"] = "changed" //nolint:forcetypeassert // tested above
+	a.Equal(js(`{"a": {"b": "changed"}, "c": [{"d": 2}, {"d": 3}]}`), doc)
+
+	// Parallel execution, with and without other result options.
+	path, err = parser.Parse(`$.c[*].d.abs()`)
+	r.NoError(err)
+	for _, opt := range [][]Option{
+		{WithParallel(2), withParallelThreshold(1)},
+		{WithParallel(2), withParallelThreshold(1), WithCopyResults()},
+	} {
+		res, err = Query(ctx, path, doc, append(opt, WithProvenance())...)
+		r.NoError(err)
+		a.Equal([]any{
+			Result{Value: float64(2), Synthetic: true},
+			Result{Value: float64(3), Synthetic: true},
+		}, res)
+	}
+	path, err = parser.Parse(`$.c[*]`)
+	r.NoError(err)
+	res, err = Query(ctx, path, doc, WithProvenance(), WithParallel(2), withParallelThreshold(1))
+	r.NoError(err)
+	a.Equal([]any{Result{Value: js(`{"d": 2}`)}, Result{Value: js(`{"d": 3}`)}}, res)
+
+	// Other functions ignore the option.
+	first, err := First(ctx, path, doc, WithProvenance())
+	r.NoError(err)
+	a.Equal(js(`{"d": 2}`), first)
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package exec
+
+// raceEnabled is true when the race detector is enabled, which, among other
+// things, causes sync.Pool to randomly drop items.
+const raceEnabled = true
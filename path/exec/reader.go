@@ -0,0 +1,133 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/theory/sqljson/path/ast"
+)
+
+// WithMaxDocumentBytes limits the size of the document read by
+// [QueryReader], [ExistsReader], [MatchReader], and [FirstReader] to n
+// bytes, including any whitespace surrounding the JSON value. Decoding stops
+// with an [ErrDecode] error as soon as it reads more, without reading the
+// rest of the document. Useful for executing paths against documents
+// submitted by users. Functions that take decoded values ignore the option.
+// Zero, the default, disables the limit. Execution returns an [ErrInvalid]
+// error for a negative value.
+func WithMaxDocumentBytes(n int) Option { return func(e *Executor) { e.maxDocumentBytes = n } }
+
+// errDocumentSize indicates that a limitReader read more than its limit.
+var errDocumentSize = errors.New("document exceeds maximum size")
+
+// QueryReader is like [Query], but decodes the value from r, which must
+// contain a single JSON value, decoding numbers as [encoding/json.Number]
+// values to preserve their precision. Returns an [ErrDecode] error if r
+// contains invalid JSON, more than one JSON value, or more bytes than the
+// limit set by [WithMaxDocumentBytes]. Use a [encoding/json.Decoder] and
+// [QueryBatch] to query a stream of JSON values.
+func QueryReader(ctx context.Context, path *ast.AST, r io.Reader, opt ...Option) ([]any, error) {
+	value, err := decodeReader(path, r, opt)
+	if err != nil {
+		return nil, err
+	}
+	return Query(ctx, path, value, opt...)
+}
+
+// ExistsReader is like [Exists], but decodes the value from r. See
+// [QueryReader] for details.
+func ExistsReader(ctx context.Context, path *ast.AST, r io.Reader, opt ...Option) (bool, error) {
+	value, err := decodeReader(path, r, opt)
+	if err != nil {
+		return false, err
+	}
+	return Exists(ctx, path, value, opt...)
+}
+
+// MatchReader is like [Match], but decodes the value from r. See
+// [QueryReader] for details.
+func MatchReader(ctx context.Context, path *ast.AST, r io.Reader, opt ...Option) (bool, error) {
+	value, err := decodeReader(path, r, opt)
+	if err != nil {
+		return false, err
+	}
+	return Match(ctx, path, value, opt...)
+}
+
+// FirstReader is like [First], but decodes the value from r. See
+// [QueryReader] for details.
+func FirstReader(ctx context.Context, path *ast.AST, r io.Reader, opt ...Option) (any, error) {
+	value, err := decodeReader(path, r, opt)
+	if err != nil {
+		return nil, err
+	}
+	return First(ctx, path, value, opt...)
+}
+
+// decodeReader decodes the single JSON value read from r, subject to the
+// limit set by [WithMaxDocumentBytes] in opt. Returns an [ErrInvalid] error
+// if opt is invalid.
+func decodeReader(path *ast.AST, r io.Reader, opt []Option) (any, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return nil, err
+	}
+	limit := exec.maxDocumentBytes
+	exec.release()
+	if limit > 0 {
+		r = &limitReader{r: r, remaining: limit}
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var val any
+	if err := dec.Decode(&val); err != nil {
+		return nil, readerError(err, limit)
+	}
+	if err := checkJSONEnd(dec); err != nil {
+		return nil, readerError(err, limit)
+	}
+	return val, nil
+}
+
+// readerError returns an [ErrDecode] error for err, an error decoding JSON
+// from a limitReader with a limit of limit bytes.
+func readerError(err error, limit int) error {
+	if errors.Is(err, errDocumentSize) {
+		return fmt.Errorf("%w: %w: document exceeds %d bytes", ErrExecution, ErrDecode, limit)
+	}
+	if errors.Is(err, ErrDecode) {
+		return err
+	}
+	return jsonError(err)
+}
+
+// limitReader reads from r until it has read remaining bytes, then returns
+// errDocumentSize if r contains more. Unlike [io.LimitReader], it
+// distinguishes a reader that ends at the limit from one that exceeds it.
+type limitReader struct {
+	r         io.Reader
+	remaining int
+}
+
+// Read reads up to len(p) bytes into p, but no more than one byte past the
+// limit, and returns errDocumentSize once it reads past the limit.
+func (lr *limitReader) Read(p []byte) (int, error) {
+	if lr.remaining < 0 {
+		return 0, errDocumentSize
+	}
+	if len(p) > lr.remaining+1 {
+		p = p[:lr.remaining+1]
+	}
+	n, err := lr.r.Read(p)
+	if n > lr.remaining {
+		n = lr.remaining
+		lr.remaining = -1
+		return n, errDocumentSize
+	}
+	lr.remaining -= n
+	return n, err //nolint:wrapcheck // io.Reader errors are returned as is
+}
@@ -0,0 +1,196 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestQueryReader(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		json   string
+		opt    []Option
+		exp    []any
+		err    string
+		decode bool // error wraps ErrDecode
+	}{
+		{
+			name: "object",
+			path: `$.a[*]`,
+			json: `{"a": [1, 2.5, "x"]}`,
+			exp:  []any{json.Number("1"), json.Number("2.5"), "x"},
+		},
+		{
+			name: "whitespace",
+			path: `$`,
+			json: " \n\ttrue\n",
+			exp:  []any{true},
+		},
+		{
+			name: "big_number",
+			path: `$`,
+			json: `12345678901234567890.123`,
+			exp:  []any{json.Number("12345678901234567890.123")},
+		},
+		{
+			name: "at_limit",
+			path: `$.a`,
+			json: `{"a": 1}`,
+			opt:  []Option{WithMaxDocumentBytes(8)},
+			exp:  []any{json.Number("1")},
+		},
+		{
+			name:   "over_limit",
+			path:   `$.a`,
+			json:   `{"a": 1}`,
+			opt:    []Option{WithMaxDocumentBytes(7)},
+			err:    "exec: invalid JSON: document exceeds 7 bytes",
+			decode: true,
+		},
+		{
+			name:   "whitespace_over_limit",
+			path:   `$.a`,
+			json:   `{"a": 1}  `,
+			opt:    []Option{WithMaxDocumentBytes(9)},
+			err:    "exec: invalid JSON: document exceeds 9 bytes",
+			decode: true,
+		},
+		{
+			name:   "empty",
+			path:   `$`,
+			json:   ``,
+			err:    "exec: invalid JSON: unexpected EOF",
+			decode: true,
+		},
+		{
+			name:   "invalid",
+			path:   `$`,
+			json:   `{"a": }`,
+			err:    "exec: invalid JSON: invalid character '}' looking for beginning of value",
+			decode: true,
+		},
+		{
+			name:   "multiple_values",
+			path:   `$`,
+			json:   `{"a": 1} {"a": 2}`,
+			err:    "exec: invalid JSON: unexpected data after top-level value",
+			decode: true,
+		},
+		{
+			name: "execution_error",
+			path: `strict $.b`,
+			json: `{"a": 1}`,
+			err:  `exec: JSON object does not contain key "b"`,
+		},
+		{
+			name: "invalid_option",
+			path: `$`,
+			json: `{"a": 1}`,
+			opt:  []Option{WithMaxDocumentBytes(-1)},
+			err:  "exec invalid: WithMaxDocumentBytes value -1 is negative",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			res, err := QueryReader(ctx, path, strings.NewReader(tc.json), tc.opt...)
+			if tc.err == "" {
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+				return
+			}
+			r.EqualError(err, tc.err)
+			a.Nil(res)
+			a.Equal(tc.decode, errors.Is(err, ErrDecode))
+		})
+	}
+}
+
+func TestReaderFuncs(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	doc := `{"a": [1, 2], "b": true}`
+
+	path, err := parser.Parse(`$.a[*]`)
+	r.NoError(err)
+	exists, err := ExistsReader(ctx, path, strings.NewReader(doc))
+	r.NoError(err)
+	a.True(exists)
+	first, err := FirstReader(ctx, path, strings.NewReader(doc))
+	r.NoError(err)
+	a.Equal(json.Number("1"), first)
+
+	path, err = parser.Parse(`$.b == true`)
+	r.NoError(err)
+	match, err := MatchReader(ctx, path, strings.NewReader(doc))
+	r.NoError(err)
+	a.True(match)
+
+	// All return decoding errors.
+	for name, fn := range map[string]func(io.Reader) error{
+		"exists": func(rd io.Reader) error { _, err := ExistsReader(ctx, path, rd); return err },
+		"match":  func(rd io.Reader) error { _, err := MatchReader(ctx, path, rd); return err },
+		"first":  func(rd io.Reader) error { _, err := FirstReader(ctx, path, rd); return err },
+	} {
+		err := fn(strings.NewReader(doc + " 1"))
+		a.ErrorIs(err, ErrDecode, name)
+		err = fn(strings.NewReader(doc))
+		a.NoError(err, name)
+		err = fn(strings.NewReader(`[`))
+		a.ErrorIs(err, ErrDecode, name)
+	}
+}
+
+// countingReader counts the bytes read from an endless JSON array.
+type countingReader struct {
+	read int
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	if cr.read == 0 {
+		p[0] = '['
+		cr.read++
+		return 1, nil
+	}
+	for i := range p {
+		if i%2 == 0 {
+			p[i] = '1'
+		} else {
+			p[i] = ','
+		}
+	}
+	cr.read += len(p)
+	return len(p), nil
+}
+
+func TestMaxDocumentBytesStopsEarly(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	path, err := parser.Parse(`$`)
+	r.NoError(err)
+	src := &countingReader{}
+	_, err = QueryReader(context.Background(), path, src, WithMaxDocumentBytes(100))
+	r.ErrorIs(err, ErrDecode)
+	r.EqualError(err, "exec: invalid JSON: document exceeds 100 bytes")
+	a.LessOrEqual(src.read, 101)
+}
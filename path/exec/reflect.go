@@ -0,0 +1,791 @@
+package exec
+
+import (
+	"context"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/theory/sqljson/path/types"
+)
+
+// WithStructTags allows execution against arbitrary Go values, including
+// structs, pointers, and typed slices and maps, which are traversed via
+// reflection rather than requiring a JSON marshal and unmarshal round trip.
+// The tag param names the struct tag used to determine object member names,
+// typically "json". Follows the conventions of [encoding/json]:
+//
+//   - Only exported struct fields are included
+//   - Tag names override field names; "-" omits a field
+//   - The omitempty tag option omits fields with empty values
+//   - Fields of embedded structs are promoted unless tagged with a name, and
+//     never override fields of the outer struct
+//   - nil pointers, interfaces, slices, and maps are JSON null
+//   - Integer and float types of all sizes are numbers
+//   - []byte values are base64-encoded strings
+//   - Map keys must be strings, integers, or implement
+//     [encoding.TextMarshaler]
+//
+// In addition, [time.Time] values become [types.TimestampTZ] values.
+//
+// Objects and arrays returned by [Query] and [First] that were converted
+// from Go values are returned as the original Go values, e.g., a struct
+// pointer rather than a map[string]any. Scalars are returned as their JSON
+// equivalents: int64, float64, string, bool, or nil, except that
+// [json.Number] values are returned unchanged.
+func WithStructTags(tag string) Option {
+	return func(e *Executor) { e.structTag = tag }
+}
+
+// WithVarsFrom specifies variables to use during execution from v, a struct,
+// pointer to a struct, or map with string keys, converted as described for
+// [WithStructTags] so that, e.g., variables of type int, uint8, and float32
+// compare equal to the same numbers in paths and JSON values. Struct fields
+// use the names in their json tags, or in the tags named by
+// [WithStructTags]. Nested structs and maps become objects, so paths can
+// access their members, as in $conf.limits.max. Variables specified by
+// [WithVars] take precedence over those from v. Execution returns an
+// [ErrExecution] error if v does not convert to an object.
+func WithVarsFrom(v any) Option { return func(e *Executor) { e.varsFrom = v } }
+
+// WithRootVar specifies doc as an additional document that paths can refer
+// to as the variable $name, e.g., to join it with the queried value inside
+// a filter, as in $.items[*] ? (@.price > $limits.max). Whereas [WithVars]
+// takes an object whose members are individual variables, doc is a whole
+// document of any size, converted like the value passed to [Query]: JSON
+// values are used as is, without copying, and nested Go maps and slices are
+// converted only as execution reaches them, unless [WithStructTags] is
+// specified. Pass WithRootVar multiple times to specify multiple documents.
+// Documents take precedence over variables of the same name specified by
+// [WithVars] and [WithVarsFrom].
+func WithRootVar(name string, doc any) Option {
+	return func(e *Executor) {
+		if e.rootVars == nil {
+			e.rootVars = Vars{}
+		}
+		e.rootVars[name] = doc
+	}
+}
+
+// fromGo converts value into a JSON value for execution, recording the
+// original Go values for all converted objects and arrays in exec.origins.
+func (exec *Executor) fromGo(ctx context.Context, value any) (any, error) {
+	if exec.origins == nil {
+		exec.origins = map[uintptr]origin{}
+	}
+	conv := &goConverter{ctx: ctx, tag: exec.structTag, origins: exec.origins, seen: map[uintptr]bool{}}
+	return conv.convert(reflect.ValueOf(value))
+}
+
+// convertVars converts exec.varsFrom into exec.vars, recording the original
+// Go values of converted objects and arrays in exec.origins. It then
+// converts the variables in exec.goVars and adds them to exec.vars, so that
+// they take precedence; see convertGoVars. It then adds the documents in
+// exec.rootVars to exec.vars; see convertRootVars. Finally, it converts the
+// variables in exec.goVarOverrides into exec.varOverrides; see
+// convertVarOverrides.
+func (exec *Executor) convertVars(ctx context.Context) error {
+	if exec.varsFrom != nil {
+		res, err := exec.varsConverter(ctx).convert(reflect.ValueOf(exec.varsFrom))
+		if err != nil {
+			return err
+		}
+
+		vars, ok := res.(map[string]any)
+		if !ok {
+			return fmt.Errorf(
+				"%w: cannot use %T as variables: not an object",
+				ErrVariable, exec.varsFrom,
+			)
+		}
+		for k, v := range exec.vars {
+			vars[k] = v
+		}
+		exec.vars = vars
+		exec.varsFrom = nil
+	}
+
+	if err := exec.convertGoVars(ctx); err != nil {
+		return err
+	}
+	if err := exec.convertRootVars(ctx); err != nil {
+		return err
+	}
+	return exec.convertVarOverrides(ctx)
+}
+
+// convertGoVars converts the values in exec.goVars as convertVars converts
+// exec.varsFrom and adds them to a copy of exec.vars. Returns an
+// [ErrVariable] error for a variable that contains a value of a Go type
+// that cannot be converted to JSON.
+func (exec *Executor) convertGoVars(ctx context.Context) error {
+	if exec.goVars == nil {
+		return nil
+	}
+
+	conv := exec.varsConverter(ctx)
+	vars := make(Vars, len(exec.vars)+len(exec.goVars))
+	for k, v := range exec.vars {
+		vars[k] = v
+	}
+	for name, val := range exec.goVars {
+		res, err := conv.convertVar(name, val)
+		if err != nil {
+			return err
+		}
+		vars[name] = res
+	}
+	exec.vars = vars
+	exec.goVars = nil
+	return nil
+}
+
+// convertVarOverrides converts the values in exec.goVarOverrides as
+// convertGoVars converts exec.goVars, but into exec.varOverrides, leaving
+// exec.vars untouched so that overriding a variable does not copy them.
+func (exec *Executor) convertVarOverrides(ctx context.Context) error {
+	if exec.goVarOverrides == nil {
+		return nil
+	}
+
+	conv := exec.varsConverter(ctx)
+	vars := make(Vars, len(exec.goVarOverrides))
+	for name, val := range exec.goVarOverrides {
+		res, err := conv.convertVar(name, val)
+		if err != nil {
+			return err
+		}
+		vars[name] = res
+	}
+	exec.varOverrides = vars
+	exec.goVarOverrides = nil
+	return nil
+}
+
+// variable returns the value of the variable named name and true, or false
+// if there is no such variable. Variables in exec.varOverrides take
+// precedence over those in exec.vars.
+func (exec *Executor) variable(name string) (any, bool) {
+	if val, ok := exec.varOverrides[name]; ok {
+		return val, true
+	}
+	val, ok := exec.vars[name]
+	return val, ok
+}
+
+// varsConverter returns a goConverter for converting variables, which
+// records the original Go values of converted objects and arrays in
+// exec.origins. Uses the json struct tag unless [WithStructTags] specifies
+// another.
+func (exec *Executor) varsConverter(ctx context.Context) *goConverter {
+	tag := exec.structTag
+	if tag == "" {
+		tag = "json"
+	}
+	if exec.origins == nil {
+		exec.origins = map[uintptr]origin{}
+	}
+	return &goConverter{ctx: ctx, tag: tag, origins: exec.origins, seen: map[uintptr]bool{}}
+}
+
+// convertRootVars converts the documents in exec.rootVars as it converts
+// the value passed to execute and adds them to a copy of exec.vars, so that
+// they take precedence over other variables without modifying the caller's
+// [Vars].
+func (exec *Executor) convertRootVars(ctx context.Context) error {
+	if exec.rootVars == nil {
+		return nil
+	}
+
+	vars := make(Vars, len(exec.vars)+len(exec.rootVars))
+	for k, v := range exec.vars {
+		vars[k] = v
+	}
+	for name, doc := range exec.rootVars {
+		var err error
+		if exec.structTag != "" {
+			doc, err = exec.fromGo(ctx, doc)
+		} else {
+			doc, err = exec.normalizeDoc(doc)
+		}
+		if err != nil {
+			return err
+		}
+		vars[name] = doc
+	}
+	exec.vars = vars
+	exec.rootVars = nil
+	return nil
+}
+
+// addrOf returns the pointer address of obj when obj is a valid JSON
+// container: one of map[string]any, []any, or Vars. Otherwise it returns 0.
+// Identifies containers only while they remain in memory: execution may
+// free containers it creates, such as those converted by normalize, and
+// reuse their addresses, so callers that key on addresses must keep the
+// containers referenced, as origin does.
+func addrOf(obj any) uintptr {
+	switch obj := obj.(type) {
+	case []any, map[string]any, Vars:
+		return reflect.ValueOf(obj).Pointer()
+	default:
+		return 0
+	}
+}
+
+// origin is the original Go value of a JSON object or array converted from
+// it. Origins are keyed by the address of the object or array, and origin
+// references it so that execution cannot free it and reuse its address for
+// another object or array, such as one created by .keyvalue(), while the
+// origin is recorded.
+type origin struct {
+	value any
+	orig  any
+}
+
+// toGo returns the original Go value from which val was converted by fromGo.
+// If val is a [types.DateTime], it returns the value returned by
+// outputDateTime. Otherwise returns val.
+func (exec *Executor) toGo(val any) any {
+	switch dt := val.(type) {
+	case map[string]any, []any:
+		if orig, ok := exec.origins[addrOf(val)]; ok {
+			return orig.orig
+		}
+	case types.DateTime:
+		return exec.outputDateTime(dt)
+	}
+	return val
+}
+
+// result returns val for return by a query function: a deep copy of val if
+// exec.copyResults is true, and otherwise the value returned by toGo.
+func (exec *Executor) result(val any) (any, error) {
+	if !exec.copyResults {
+		return exec.toGo(val), nil
+	}
+	if dt, ok := val.(types.DateTime); ok && exec.dateTimeOutput != dateTimeOutputTypes {
+		return exec.outputDateTime(dt), nil
+	}
+	return exec.copyValue(val)
+}
+
+// copyValue returns a deep copy of val. It copies map[string]any and []any
+// values and the values of [types.DateTime] pointers, and normalizes and
+// copies typed Go maps, slices, and arrays not yet converted to JSON values.
+// Returns an [ErrExecution] error for maps with non-string keys.
+func (exec *Executor) copyValue(val any) (any, error) {
+	switch val := val.(type) {
+	case nil, string, int64, float64, bool, json.Number:
+		return val, nil
+	case map[string]any:
+		obj := make(map[string]any, len(val))
+		for k, v := range val {
+			c, err := exec.copyValue(v)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = c
+		}
+		return obj, nil
+	case []any:
+		array := make([]any, len(val))
+		for i, v := range val {
+			c, err := exec.copyValue(v)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = c
+		}
+		return array, nil
+	case *types.Date:
+		c := *val
+		return &c, nil
+	case *types.Time:
+		c := *val
+		return &c, nil
+	case *types.TimeTZ:
+		c := *val
+		return &c, nil
+	case *types.Timestamp:
+		c := *val
+		return &c, nil
+	case *types.TimestampTZ:
+		if exec.displayLoc != nil {
+			return exec.displayDateTime(val), nil
+		}
+		c := *val
+		return &c, nil
+	}
+
+	norm, err := exec.normalize(val)
+	if err != nil {
+		return nil, err
+	}
+	switch norm.(type) {
+	case map[string]any, []any:
+		return exec.copyValue(norm)
+	}
+	return norm, nil
+}
+
+// goConverter converts Go values to JSON values.
+type goConverter struct {
+	ctx     context.Context //nolint:containedctx // Required for TimestampTZ
+	tag     string
+	origins map[uintptr]origin
+	seen    map[uintptr]bool
+}
+
+//nolint:gochecknoglobals
+var (
+	jsonNumberType    = reflect.TypeFor[json.Number]()
+	timeType          = reflect.TypeFor[time.Time]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	dateTimeType      = reflect.TypeFor[types.DateTime]()
+)
+
+// convert converts val into a JSON value.
+//
+//nolint:exhaustive // Remaining kinds unsupported
+func (conv *goConverter) convert(val reflect.Value) (any, error) {
+	if !val.IsValid() {
+		return nil, nil
+	}
+
+	switch typ := val.Type(); {
+	case typ == timeType:
+		//nolint:forcetypeassert // Guaranteed by the type check
+		return types.NewTimestampTZ(conv.ctx, val.Interface().(time.Time)), nil
+	case typ.Kind() == reflect.Pointer && typ.Implements(dateTimeType):
+		if val.IsNil() {
+			return nil, nil
+		}
+		return val.Interface(), nil
+	}
+
+	if res, ok := convertScalar(val); ok {
+		return res, nil
+	}
+
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return conv.convertPointer(val)
+	case reflect.Slice:
+		if val.IsNil() {
+			return nil, nil
+		}
+		return conv.convertArray(val)
+	case reflect.Array:
+		return conv.convertArray(val)
+	case reflect.Map:
+		return conv.convertMap(val)
+	case reflect.Struct:
+		return conv.convertStruct(val)
+	}
+
+	return nil, &unsupportedTypeError{typ: val.Type()}
+}
+
+// convertVar converts val, the value of the variable named name, into a JSON
+// value. Returns an [ErrVariable] error if val contains a value of a Go type
+// that cannot be converted to JSON.
+func (conv *goConverter) convertVar(name string, val any) (any, error) {
+	res, err := conv.convert(reflect.ValueOf(val))
+	if err != nil {
+		var typeErr *unsupportedTypeError
+		if errors.As(err, &typeErr) {
+			return nil, fmt.Errorf(
+				"%w: variable %q has unsupported type %v",
+				ErrVariable, name, typeErr.typ,
+			)
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+// unsupportedTypeError is the error goConverter returns for a value of a Go
+// type it cannot convert to JSON. It wraps [ErrExecution].
+type unsupportedTypeError struct {
+	typ reflect.Type
+}
+
+// Error returns a message naming the unsupported type.
+func (e *unsupportedTypeError) Error() string {
+	return fmt.Sprintf("%v: unsupported Go type %v", ErrExecution, e.typ)
+}
+
+// Unwrap returns [ErrExecution].
+func (e *unsupportedTypeError) Unwrap() error { return ErrExecution }
+
+// convertScalar converts val into a JSON scalar if its kind is a boolean,
+// number, or string, or if it's a []byte, which it converts to a
+// base64-encoded string. Returns false if val is not a scalar.
+//
+//nolint:exhaustive // Remaining kinds not scalars
+func convertScalar(val reflect.Value) (any, bool) {
+	switch val.Kind() {
+	case reflect.Bool:
+		return val.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := val.Uint(); u <= math.MaxInt64 {
+			return int64(u), true
+		}
+		return float64(val.Uint()), true
+	case reflect.Float32:
+		// Use the shortest decimal representation so that, e.g., float32(1.1)
+		// equals the path literal 1.1.
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(val.Float(), 'g', -1, 32), 64)
+		return f, true
+	case reflect.Float64:
+		return val.Float(), true
+	case reflect.String:
+		if val.Type() == jsonNumberType {
+			// Preserve the original number.
+			return json.Number(val.String()), true
+		}
+		return val.String(), true
+	case reflect.Slice:
+		if val.Type().Elem().Kind() == reflect.Uint8 && !val.IsNil() {
+			return base64.StdEncoding.EncodeToString(val.Bytes()), true
+		}
+	}
+	return nil, false
+}
+
+// convertPointer converts the value pointed to by val, which must be a
+// pointer or interface. Returns nil if val is nil. If the pointed-to value is
+// converted to an object or array, records val as its original value.
+func (conv *goConverter) convertPointer(val reflect.Value) (any, error) {
+	if val.IsNil() {
+		return nil, nil
+	}
+
+	if val.Kind() == reflect.Pointer {
+		// Detect cycles.
+		ptr := val.Pointer()
+		if conv.seen[ptr] {
+			return nil, fmt.Errorf("%w: encountered a cycle via %v", ErrExecution, val.Type())
+		}
+		conv.seen[ptr] = true
+		defer delete(conv.seen, ptr)
+	}
+
+	res, err := conv.convert(val.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	if val.Kind() == reflect.Pointer {
+		conv.record(res, val)
+	}
+	return res, nil
+}
+
+// record records orig as the original Go value of res if res is an object or
+// non-empty array.
+func (conv *goConverter) record(res any, orig reflect.Value) {
+	if !orig.CanInterface() {
+		return
+	}
+	switch res := res.(type) {
+	case map[string]any:
+		conv.origins[addrOf(res)] = origin{res, orig.Interface()}
+	case []any:
+		// Empty slices may share an address.
+		if len(res) > 0 {
+			conv.origins[addrOf(res)] = origin{res, orig.Interface()}
+		}
+	}
+}
+
+// convertArray converts val, which must be a slice or an array, into []any.
+func (conv *goConverter) convertArray(val reflect.Value) (any, error) {
+	size := val.Len()
+	array := make([]any, size)
+	for i := range size {
+		item, err := conv.convert(val.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		array[i] = item
+	}
+	conv.record(array, val)
+	return array, nil
+}
+
+// convertMap converts val, which must be a map, into map[string]any.
+func (conv *goConverter) convertMap(val reflect.Value) (any, error) {
+	if val.IsNil() {
+		return nil, nil
+	}
+
+	obj := make(map[string]any, val.Len())
+	iter := val.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		item, err := conv.convert(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = item
+	}
+	conv.record(obj, val)
+	return obj, nil
+}
+
+// mapKey converts key into a string.
+//
+//nolint:exhaustive // Remaining kinds unsupported
+func mapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+
+	if key.Type().Implements(textMarshalerType) {
+		if key.Kind() == reflect.Pointer && key.IsNil() {
+			return "", nil
+		}
+		//nolint:forcetypeassert // Guaranteed by the type check
+		text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrExecution, err)
+		}
+		return string(text), nil
+	}
+
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("%w: unsupported Go map key type %v", ErrExecution, key.Type())
+}
+
+// convertStruct converts val, which must be a struct, into map[string]any.
+func (conv *goConverter) convertStruct(val reflect.Value) (any, error) {
+	obj := map[string]any{}
+	if err := conv.addFields(obj, val); err != nil {
+		return nil, err
+	}
+	conv.record(obj, val)
+	return obj, nil
+}
+
+// addFields adds the fields of struct val to obj. Fields of embedded structs
+// are added after the fields of val, and only if obj does not already
+// contain their names.
+func (conv *goConverter) addFields(obj map[string]any, val reflect.Value) error {
+	typ := val.Type()
+	var embedded []reflect.Value
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get(conv.tag), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		fieldVal := val.Field(i)
+		if field.Anonymous && name == "" {
+			// Promote the fields of embedded structs.
+			if fieldVal.Kind() == reflect.Pointer {
+				if fieldVal.IsNil() {
+					continue
+				}
+				fieldVal = fieldVal.Elem()
+			}
+			if fieldVal.Kind() == reflect.Struct {
+				embedded = append(embedded, fieldVal)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if hasOption(opts, "omitempty") && isEmptyValue(fieldVal) {
+			continue
+		}
+
+		item, err := conv.convert(fieldVal)
+		if err != nil {
+			return err
+		}
+		obj[name] = item
+	}
+
+	for _, fieldVal := range embedded {
+		sub := map[string]any{}
+		if err := conv.addFields(sub, fieldVal); err != nil {
+			return err
+		}
+		for k, v := range sub {
+			if _, ok := obj[k]; !ok {
+				obj[k] = v
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasOption returns true if the comma-delimited struct tag opts contains
+// name.
+func hasOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyValue returns true if val is empty as defined by the encoding/json
+// omitempty tag option.
+//
+//nolint:exhaustive // Remaining kinds never empty
+func isEmptyValue(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return val.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return val.IsZero()
+	}
+	return false
+}
+
+// normalize converts value into a JSON object or array if it's a map with
+// string keys or a slice or array of a type other than map[string]any and
+// []any. It converts one level at a time: boolean, number, and string
+// elements are converted to JSON scalars, but nested maps, slices, and
+// arrays are left to be converted when execution reaches them. It records
+// the original values of converted maps and slices in exec.origins so that
+// results can be returned as the original values. Returns an
+// [ErrExecution] error for maps with non-string keys and for values that
+// are neither containers nor scalars, such as channels, pointers, and
+// structs, which require [WithStructTags].
+//
+//nolint:exhaustive // Remaining kinds unsupported
+func (exec *Executor) normalize(value any) (any, error) {
+	switch value.(type) {
+	case nil, map[string]any, []any, string, int64, float64, bool, json.Number, types.DateTime:
+		return value, nil
+	}
+
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Map:
+		if kind := val.Type().Key().Kind(); kind != reflect.String {
+			return nil, fmt.Errorf(
+				"%w: cannot query %v: map keys must be strings",
+				ErrExecution, val.Type(),
+			)
+		}
+		if val.IsNil() {
+			return nil, nil
+		}
+		obj := make(map[string]any, val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			obj[iter.Key().String()] = normalizeElem(iter.Value())
+		}
+		exec.recordOrigin(obj, value)
+		return obj, nil
+	case reflect.Slice, reflect.Array:
+		if val.Kind() == reflect.Slice {
+			if val.IsNil() {
+				return nil, nil
+			}
+			if res, ok := convertScalar(val); ok {
+				// []byte
+				return res, nil
+			}
+		}
+		array := make([]any, val.Len())
+		for i := range array {
+			array[i] = normalizeElem(val.Index(i))
+		}
+		exec.recordOrigin(array, value)
+		return array, nil
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported value type %T", ErrExecution, value)
+}
+
+// normalizeDoc normalizes value, a document to query, with normalize. Like
+// the elements of maps, slices, and arrays, it also converts booleans,
+// numbers, and strings of other types, such as int and named string types,
+// into JSON scalars.
+func (exec *Executor) normalizeDoc(value any) (any, error) {
+	value, err := exec.normalize(value)
+	if err != nil {
+		return nil, err
+	}
+	switch value.(type) {
+	case nil, map[string]any, []any, string, int64, float64, bool, json.Number, types.DateTime:
+		return value, nil
+	}
+	if res, ok := convertScalar(reflect.ValueOf(value)); ok {
+		return res, nil
+	}
+	return value, nil
+}
+
+// normalizeElem converts val, an element of a map, slice, or array, into a
+// JSON scalar if it's a boolean, number, or string. Otherwise it returns the
+// value of val, to be converted by normalize when execution reaches it.
+func normalizeElem(val reflect.Value) any {
+	if val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if res, ok := convertScalar(val); ok {
+		return res
+	}
+	return val.Interface()
+}
+
+// recordOrigin records orig as the original value of the JSON object or
+// array res, so that toGo returns orig in place of res.
+func (exec *Executor) recordOrigin(res, orig any) {
+	if array, ok := res.([]any); ok && len(array) == 0 {
+		// Empty slices may share an address.
+		return
+	}
+	if exec.origins == nil {
+		exec.origins = map[uintptr]origin{}
+	}
+	exec.origins[addrOf(res)] = origin{res, orig}
+}
@@ -0,0 +1,1345 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+	"github.com/theory/sqljson/path/types"
+)
+
+type reflectAddress struct {
+	City string `json:"city"`
+	Zip  uint32 `json:"zip,omitempty"`
+}
+
+type reflectBase struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type reflectUser struct {
+	reflectBase
+	Name     string          `json:"name"` // Overrides reflectBase.Name
+	Age      int32           `json:"age"`
+	Score    float32         `json:"score"`
+	Admin    bool            `json:"admin"`
+	Address  *reflectAddress `json:"address"`
+	Tags     []string        `json:"tags"`
+	Attrs    map[string]any  `json:"attrs,omitempty"`
+	Counts   map[int]uint    `json:"counts,omitempty"`
+	Created  time.Time       `json:"created"`
+	Secret   string          `json:"-"`
+	Raw      []byte          `json:"raw,omitempty"`
+	Untagged string
+	Number   json.Number       `json:"number,omitempty"`
+	Friends  []*reflectAddress `json:"friends,omitempty"`
+	internal string
+}
+
+type badMarshaler struct{}
+
+func (badMarshaler) MarshalText() ([]byte, error) { return nil, errors.New("oops") }
+
+func TestFromGo(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	created := time.Date(2024, 6, 5, 12, 30, 0, 0, time.UTC)
+
+	type cycle struct {
+		Next *cycle
+	}
+	loop := &cycle{}
+	loop.Next = loop
+
+	for _, tc := range []struct {
+		name  string
+		value any
+		exp   any
+		err   string
+	}{
+		{name: "nil", value: nil, exp: nil},
+		{name: "bool", value: true, exp: true},
+		{name: "int8", value: int8(-3), exp: int64(-3)},
+		{name: "uint32", value: uint32(42), exp: int64(42)},
+		{name: "uint64_big", value: uint64(1 << 63), exp: float64(1 << 63)},
+		{name: "float32", value: float32(1.1), exp: float64(1.1)},
+		{name: "float64", value: 98.6, exp: 98.6},
+		{name: "string", value: "hi", exp: "hi"},
+		{name: "json_number", value: json.Number("12"), exp: json.Number("12")},
+		{name: "bytes", value: []byte("hi"), exp: "aGk="},
+		{name: "nil_slice", value: []string(nil), exp: nil},
+		{name: "nil_map", value: map[string]int(nil), exp: nil},
+		{name: "nil_pointer", value: (*reflectUser)(nil), exp: nil},
+		{name: "typed_slice", value: []int{1, 2}, exp: []any{int64(1), int64(2)}},
+		{name: "array", value: [2]bool{true, false}, exp: []any{true, false}},
+		{name: "int_keys", value: map[int8]string{1: "a"}, exp: map[string]any{"1": "a"}},
+		{name: "uint_keys", value: map[uint]string{1: "a"}, exp: map[string]any{"1": "a"}},
+		{
+			name:  "text_keys",
+			value: map[time.Time]int{created: 1},
+			exp:   map[string]any{"2024-06-05T12:30:00Z": int64(1)},
+		},
+		{
+			name:  "time",
+			value: created,
+			exp:   types.NewTimestampTZ(ctx, created),
+		},
+		{
+			name:  "datetime",
+			value: types.NewDate(created),
+			exp:   types.NewDate(created),
+		},
+		{
+			name: "struct",
+			value: reflectUser{
+				reflectBase: reflectBase{ID: 1, Name: "base"},
+				Name:        "Kamala",
+				Age:         42,
+				Score:       3.3,
+				Address:     &reflectAddress{City: "Oakland"},
+				Tags:        []string{"a"},
+				Counts:      map[int]uint{2: 3},
+				Created:     created,
+				Secret:      "shh",
+				Untagged:    "x",
+				internal:    "y",
+			},
+			exp: map[string]any{
+				"id":       int64(1),
+				"name":     "Kamala",
+				"age":      int64(42),
+				"score":    3.3,
+				"admin":    false,
+				"address":  map[string]any{"city": "Oakland"},
+				"tags":     []any{"a"},
+				"counts":   map[string]any{"2": int64(3)},
+				"created":  types.NewTimestampTZ(ctx, created),
+				"Untagged": "x",
+			},
+		},
+		{
+			name:  "embedded_pointer",
+			value: struct{ *reflectBase }{&reflectBase{ID: 2}},
+			exp:   map[string]any{"id": int64(2), "name": ""},
+		},
+		{
+			name:  "nil_embedded_pointer",
+			value: struct{ *reflectBase }{},
+			exp:   map[string]any{},
+		},
+		{
+			name: "tagged_embedded",
+			value: struct {
+				reflectBase `json:"base"`
+			}{reflectBase{ID: 3}},
+			exp: map[string]any{},
+		},
+		{
+			name:  "unsupported",
+			value: map[string]any{"x": make(chan int)},
+			err:   "exec: unsupported Go type chan int",
+		},
+		{
+			name:  "unsupported_key",
+			value: map[float64]int{1: 1},
+			err:   "exec: unsupported Go map key type float64",
+		},
+		{
+			name:  "bad_marshaler",
+			value: map[badMarshaler]int{{}: 1},
+			err:   "exec: oops",
+		},
+		{
+			name:  "cycle",
+			value: loop,
+			err:   "exec: encountered a cycle via *exec.cycle",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			e := &Executor{structTag: "json"}
+			res, err := e.fromGo(ctx, tc.value)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(res)
+				return
+			}
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+		})
+	}
+}
+
+func TestStructTags(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	oakland := &reflectAddress{City: "Oakland", Zip: 94612}
+	users := []*reflectUser{
+		{
+			Name:    "Kamala",
+			Age:     42,
+			Score:   1.1,
+			Admin:   true,
+			Address: oakland,
+			Tags:    []string{"go", "sql"},
+			Created: time.Date(2024, 6, 5, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			Name:    "Tim",
+			Age:     25,
+			Score:   2.5,
+			Tags:    []string{"js"},
+			Created: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+	}
+	data := map[string][]*reflectUser{"users": users}
+	opt := WithStructTags("json")
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		exists bool
+		match  bool
+		query  []any
+	}{
+		{
+			name:   "member",
+			path:   "$.users[*].name",
+			exists: true,
+			query:  []any{"Kamala", "Tim"},
+		},
+		{
+			name:   "struct_results",
+			path:   "$.users[*] ? (@.age > 30)",
+			exists: true,
+			query:  []any{users[0]},
+		},
+		{
+			name:   "nested_struct_result",
+			path:   "$.users[*].address",
+			exists: true,
+			query:  []any{oakland, nil},
+		},
+		{
+			name:   "slice_result",
+			path:   "$.users[0].tags",
+			exists: true,
+			query:  []any{[]string{"go", "sql"}},
+		},
+		{
+			name:   "float32_literal",
+			path:   "$.users[*] ? (@.score == 1.1).name",
+			exists: true,
+			query:  []any{"Kamala"},
+		},
+		{
+			name:   "unsigned",
+			path:   "$.users[*].address.zip",
+			exists: true,
+			query:  []any{int64(94612)},
+		},
+		{
+			name:   "nil_pointer_is_null",
+			path:   "$.users[*] ? (@.address == null).name",
+			exists: true,
+			query:  []any{"Tim"},
+		},
+		{
+			name:   "omitted",
+			path:   "$.users[*].Secret",
+			exists: false,
+		},
+		{
+			name:   "time",
+			path:   `$.users[*] ? (@.created < "2024-01-01T00:00:00Z".datetime()).name`,
+			exists: true,
+			query:  []any{"Tim"},
+		},
+		{
+			name:   "predicate",
+			path:   `$.users[0].admin == true`,
+			exists: true,
+			match:  true,
+			query:  []any{true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			if !path.IsPredicate() {
+				ok, err := Exists(ctx, path, data, opt, WithSilent())
+				r.NoError(err)
+				a.Equal(tc.exists, ok)
+			} else {
+				ok, err := Match(ctx, path, data, opt)
+				r.NoError(err)
+				a.Equal(tc.match, ok)
+			}
+
+			if tc.query != nil {
+				res, err := Query(ctx, path, data, opt)
+				r.NoError(err)
+				a.Equal(tc.query, res)
+
+				first, err := First(ctx, path, data, opt)
+				r.NoError(err)
+				a.Equal(tc.query[0], first)
+			}
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+		path, err := parser.Parse("$")
+		r.NoError(err)
+		value := []any{func() {}}
+
+		_, err = Query(ctx, path, value, opt)
+		r.EqualError(err, "exec: unsupported Go type func()")
+		_, err = Exists(ctx, path, value, opt)
+		r.EqualError(err, "exec: unsupported Go type func()")
+	})
+}
+
+func TestTypedContainers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	type color string
+	strMap := map[string]string{"a": "x", "b": "y"}
+	floatMap := map[string]float64{"a": 1.5, "b": 3}
+	strSlice := []string{"x", "y", "z"}
+	intSlice := []int{1, 2, 3}
+	nested := map[string][]int{"a": {1, 2}, "b": {3}}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		exp   []any
+		rand  bool
+		err   string
+	}{
+		{
+			name:  "str_map_key",
+			path:  "$.a",
+			value: strMap,
+			exp:   []any{"x"},
+		},
+		{
+			name:  "str_map_wildcard",
+			path:  "$.*",
+			value: strMap,
+			exp:   []any{"x", "y"},
+			rand:  true,
+		},
+		{
+			name:  "str_map_keyvalue",
+			path:  "$.keyvalue().key",
+			value: strMap,
+			exp:   []any{"a", "b"},
+		},
+		{
+			name:  "str_map_type",
+			path:  "$.type()",
+			value: strMap,
+			exp:   []any{"object"},
+		},
+		{
+			name:  "str_map_filter",
+			path:  `$.* ? (@ starts with "y")`,
+			value: strMap,
+			exp:   []any{"y"},
+		},
+		{
+			name:  "str_map_root",
+			path:  "$",
+			value: strMap,
+			exp:   []any{strMap},
+		},
+		{
+			name:  "float_map_compare",
+			path:  "$.* ? (@ > 2)",
+			value: floatMap,
+			exp:   []any{float64(3)},
+		},
+		{
+			name:  "float_map_math",
+			path:  "$.a * 2",
+			value: floatMap,
+			exp:   []any{float64(3)},
+		},
+		{
+			name:  "str_slice_index",
+			path:  "$[1]",
+			value: strSlice,
+			exp:   []any{"y"},
+		},
+		{
+			name:  "str_slice_size",
+			path:  "$.size()",
+			value: strSlice,
+			exp:   []any{int64(3)},
+		},
+		{
+			name:  "str_slice_last",
+			path:  "$[last]",
+			value: strSlice,
+			exp:   []any{"z"},
+		},
+		{
+			name:  "int_slice_wildcard",
+			path:  "$[*]",
+			value: intSlice,
+			exp:   []any{int64(1), int64(2), int64(3)},
+		},
+		{
+			name:  "int_slice_filter",
+			path:  "$[*] ? (@ >= 2)",
+			value: intSlice,
+			exp:   []any{int64(2), int64(3)},
+		},
+		{
+			name:  "int_slice_unwrap",
+			path:  "$.abs()",
+			value: intSlice,
+			exp:   []any{int64(1), int64(2), int64(3)},
+		},
+		{
+			name:  "int_slice_root",
+			path:  "$",
+			value: intSlice,
+			exp:   []any{intSlice},
+		},
+		{
+			name:  "array",
+			path:  "$[*]",
+			value: [2]int{4, 5},
+			exp:   []any{int64(4), int64(5)},
+		},
+		{
+			name:  "named_strings",
+			path:  `$[*] ? (@ == "red")`,
+			value: []color{"red", "blue"},
+			exp:   []any{"red"},
+		},
+		{
+			name:  "int_root",
+			path:  "$",
+			value: 1,
+			exp:   []any{int64(1)},
+		},
+		{
+			name:  "int32_root_math",
+			path:  "$ * 2",
+			value: int32(21),
+			exp:   []any{int64(42)},
+		},
+		{
+			name:  "uint8_root_compare",
+			path:  "$ ? (@ == 7)",
+			value: uint8(7),
+			exp:   []any{int64(7)},
+		},
+		{
+			name:  "float32_root",
+			path:  "$ ? (@ == 1.1)",
+			value: float32(1.1),
+			exp:   []any{float64(1.1)},
+		},
+		{
+			name:  "named_string_root",
+			path:  `$ ? (@ starts with "r")`,
+			value: color("red"),
+			exp:   []any{"red"},
+		},
+		{
+			name:  "named_string_type",
+			path:  "$.type()",
+			value: color("red"),
+			exp:   []any{"string"},
+		},
+		{
+			name:  "nested",
+			path:  "$.a[*]",
+			value: nested,
+			exp:   []any{int64(1), int64(2)},
+		},
+		{
+			name:  "nested_result",
+			path:  "$.b",
+			value: nested,
+			exp:   []any{[]int{3}},
+		},
+		{
+			name:  "nested_any",
+			path:  "strict $.** ? (@.type() == \"number\")",
+			value: nested,
+			exp:   []any{int64(1), int64(2), int64(3)},
+			rand:  true,
+		},
+		{
+			name:  "nested_size",
+			path:  "$.a.size()",
+			value: nested,
+			exp:   []any{int64(2)},
+		},
+		{
+			name:  "mixed_any_slice",
+			path:  "$[*][*]",
+			value: []any{[]string{"x"}, map[string]int{"a": 1}},
+			exp:   []any{"x", map[string]int{"a": 1}},
+		},
+		{
+			name:  "non_string_keys",
+			path:  "$.a",
+			value: map[int]string{1: "x"},
+			err:   "exec: cannot query map[int]string: map keys must be strings",
+		},
+		{
+			name:  "nested_non_string_keys",
+			path:  "$.a.b",
+			value: map[string]any{"a": map[float64]bool{1: true}},
+			err:   "exec: cannot query map[float64]bool: map keys must be strings",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := Query(ctx, path, tc.value)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(res)
+
+				ok, err := Exists(ctx, path, tc.value)
+				r.EqualError(err, tc.err)
+				a.False(ok)
+				return
+			}
+
+			r.NoError(err)
+			if tc.rand {
+				a.ElementsMatch(tc.exp, res)
+			} else {
+				a.Equal(tc.exp, res)
+			}
+
+			ok, err := Exists(ctx, path, tc.value)
+			r.NoError(err)
+			a.True(ok)
+		})
+	}
+
+	t.Run("vars", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+
+		path, err := parser.Parse("$[*] ? (@ == $x[*])")
+		r.NoError(err)
+		res, err := Query(ctx, path, intSlice, WithVars(Vars{"x": []int64{2, 3, 4}}))
+		r.NoError(err)
+		a.Equal([]any{int64(2), int64(3)}, res)
+	})
+
+	t.Run("match", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+
+		path, err := parser.Parse(`$.a == "x"`)
+		r.NoError(err)
+		ok, err := Match(ctx, path, strMap)
+		r.NoError(err)
+		a.True(ok)
+	})
+}
+
+func TestTypedContainerOrigins(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	// Enough converted containers that execution frees some and reuses
+	// their addresses for the objects .keyvalue() creates.
+	const size = 200_000
+	value := make([]map[string]int, size)
+	for i := range value {
+		value[i] = map[string]int{"n": i}
+	}
+
+	path, err := parser.Parse("$[*].keyvalue()")
+	r.NoError(err)
+	res, err := Query(context.Background(), path, value)
+	r.NoError(err)
+	r.Len(res, size)
+	for i, item := range res {
+		obj, ok := item.(map[string]any)
+		r.Truef(ok, "item %d is %T", i, item)
+		a.Equal(int64(i), obj["value"])
+	}
+}
+
+func TestWithVarsFrom(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	type limits struct {
+		Min float32 `json:"min"`
+		Max *uint16 `json:"max"`
+	}
+	type config struct {
+		Limits  limits         `json:"limits"`
+		Names   []string       `json:"names"`
+		Extra   map[string]int `json:"extra"`
+		Created time.Time      `json:"created"`
+		Ignore  string         `json:"-"`
+	}
+	type numbers struct {
+		Int     int     `json:"int"`
+		Int8    int8    `json:"int8"`
+		Int16   int16   `json:"int16"`
+		Int32   int32   `json:"int32"`
+		Int64   int64   `json:"int64"`
+		Uint    uint    `json:"uint"`
+		Uint8   uint8   `json:"uint8"`
+		Uint16  uint16  `json:"uint16"`
+		Uint32  uint32  `json:"uint32"`
+		Uint64  uint64  `json:"uint64"`
+		Float32 float32 `json:"float32"`
+		Float64 float64 `json:"float64"`
+	}
+
+	maxVal := uint16(10)
+	conf := &config{
+		Limits:  limits{Min: 2.5, Max: &maxVal},
+		Names:   []string{"a", "c"},
+		Extra:   map[string]int{"x": 3},
+		Created: time.Date(2024, 6, 5, 12, 30, 0, 0, time.UTC),
+		Ignore:  "secret",
+	}
+	nums := numbers{
+		Int: 3, Int8: 3, Int16: 3, Int32: 3, Int64: 3,
+		Uint: 3, Uint8: 3, Uint16: 3, Uint32: 3, Uint64: 3,
+		Float32: 3, Float64: 3,
+	}
+	users := []*reflectUser{{Name: "a", Age: 5}, {Name: "c", Age: 8}, {Name: "c", Age: 12}}
+	items := []any{
+		map[string]any{"name": "a", "n": int64(1)},
+		map[string]any{"name": "b", "n": float64(2.5)},
+		map[string]any{"name": "c", "n": int64(7)},
+		map[string]any{"name": "d", "n": int64(11)},
+	}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		vars  any
+		opt   []Option
+		value any
+		exp   []any
+		err   string
+	}{
+		{
+			name:  "nested_filter",
+			path:  `$[*] ? (@.n >= $conf.limits.min && @.n <= $conf.limits.max).name`,
+			vars:  map[string]any{"conf": conf},
+			value: items,
+			exp:   []any{"b", "c"},
+		},
+		{
+			name:  "struct_members",
+			path:  `$[*] ? (@.name == $names[*]).n`,
+			vars:  conf,
+			value: items,
+			exp:   []any{int64(1), int64(7)},
+		},
+		{
+			name:  "map_member",
+			path:  `$extra.x`,
+			vars:  conf,
+			value: nil,
+			exp:   []any{int64(3)},
+		},
+		{
+			name:  "time",
+			path:  `$created.type()`,
+			vars:  conf,
+			value: nil,
+			exp:   []any{"timestamp with time zone"},
+		},
+		{
+			name:  "object_result",
+			path:  `$conf.limits`,
+			vars:  map[string]*config{"conf": conf},
+			value: nil,
+			exp:   []any{conf.Limits},
+		},
+		{
+			name:  "omitted_field",
+			path:  `$Ignore`,
+			vars:  conf,
+			value: nil,
+			err:   `exec: could not find jsonpath variable "Ignore"`,
+		},
+		{
+			name:  "with_vars_precedence",
+			path:  `$names`,
+			vars:  conf,
+			opt:   []Option{WithVars(Vars{"names": "override"})},
+			value: nil,
+			exp:   []any{"override"},
+		},
+		{
+			name:  "with_vars_merge",
+			path:  `$extra.x + $y`,
+			vars:  conf,
+			opt:   []Option{WithVars(Vars{"y": int64(4)})},
+			value: nil,
+			exp:   []any{int64(7)},
+		},
+		{
+			name:  "struct_tags",
+			path:  `$[*] ? (@.name == $names[1] && @.age < $conf.limits.max)`,
+			vars:  map[string]any{"conf": conf, "names": conf.Names},
+			opt:   []Option{WithStructTags("json")},
+			value: users,
+			exp:   []any{users[1]},
+		},
+		{
+			name:  "not_object",
+			path:  `$x`,
+			vars:  []int{1},
+			value: nil,
+			err:   `exec: cannot use []int as variables: not an object`,
+		},
+		{
+			name:  "unsupported",
+			path:  `$x`,
+			vars:  map[string]any{"x": func() {}},
+			value: nil,
+			err:   `exec: unsupported Go type func()`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			opt := append([]Option{WithVarsFrom(tc.vars)}, tc.opt...)
+
+			res, err := Query(ctx, path, tc.value, opt...)
+			ok, existsErr := Exists(ctx, path, tc.value, opt...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				r.EqualError(existsErr, tc.err)
+				return
+			}
+			r.NoError(err)
+			r.NoError(existsErr)
+			a.Equal(tc.exp, res)
+			a.Equal(len(tc.exp) > 0, ok)
+		})
+	}
+
+	// Each numeric kind compares equal to a path number.
+	for _, name := range []string{
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse("$" + name + " == 3 && $" + name + " > 2.5")
+			r.NoError(err)
+			ok, err := Match(ctx, path, nil, WithVarsFrom(nums))
+			r.NoError(err)
+			a.True(ok)
+
+			path, err = parser.Parse("$[*] ? (@ == $" + name + ")")
+			r.NoError(err)
+			res, err := Query(ctx, path, []any{int64(2), json.Number("3"), float64(3)}, WithVarsFrom(&nums))
+			r.NoError(err)
+			a.Equal([]any{json.Number("3"), float64(3)}, res)
+		})
+	}
+
+	// Mutation functions also convert variables.
+	t.Run("delete", func(t *testing.T) {
+		t.Parallel()
+		r := require.New(t)
+		path, err := parser.Parse(`$[*] ? (@.n > $conf.limits.max)`)
+		r.NoError(err)
+		res, err := Delete(ctx, path, items, WithVarsFrom(map[string]any{"conf": conf}))
+		r.NoError(err)
+		r.Equal(items[:3], res)
+	})
+}
+
+func TestWithVars(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	addr := &reflectAddress{City: "Paris", Zip: 75001}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		vars  Vars
+		value any
+		exp   []any
+		err   string
+	}{
+		{
+			name:  "struct",
+			path:  `$addr.city`,
+			vars:  Vars{"addr": addr},
+			value: nil,
+			exp:   []any{"Paris"},
+		},
+		{
+			name:  "struct_result",
+			path:  `$addr`,
+			vars:  Vars{"addr": addr},
+			value: nil,
+			exp:   []any{addr},
+		},
+		{
+			name:  "nested_struct",
+			path:  `$x.addrs[*] ? (@.zip > 75000).city`,
+			vars:  Vars{"x": map[string]any{"addrs": []*reflectAddress{addr, {City: "Lyon"}}}},
+			value: nil,
+			exp:   []any{"Paris"},
+		},
+		{
+			name:  "typed_slice",
+			path:  `$[*] ? (@ == $nums[*])`,
+			vars:  Vars{"nums": []int8{1, 3}},
+			value: []any{int64(1), int64(2), float64(3)},
+			exp:   []any{int64(1), float64(3)},
+		},
+		{
+			name:  "time",
+			path:  `$t.type()`,
+			vars:  Vars{"t": time.Now()},
+			value: nil,
+			exp:   []any{"timestamp with time zone"},
+		},
+		{
+			name:  "func",
+			path:  `$x`,
+			vars:  Vars{"x": func() {}},
+			value: nil,
+			err:   `exec: variable "x" has unsupported type func()`,
+		},
+		{
+			name:  "chan",
+			path:  `$x`,
+			vars:  Vars{"x": make(chan int)},
+			value: nil,
+			err:   `exec: variable "x" has unsupported type chan int`,
+		},
+		{
+			name:  "complex",
+			path:  `$x`,
+			vars:  Vars{"x": complex64(1)},
+			value: nil,
+			err:   `exec: variable "x" has unsupported type complex64`,
+		},
+		{
+			name:  "nested_func",
+			path:  `$y`,
+			vars:  Vars{"x": []any{map[string]any{"f": func(int) {}}}, "y": int64(1)},
+			value: nil,
+			err:   `exec: variable "x" has unsupported type func(int)`,
+		},
+		{
+			name:  "unused",
+			path:  `$`,
+			vars:  Vars{"x": make(chan bool)},
+			value: nil,
+			err:   `exec: variable "x" has unsupported type chan bool`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := Query(ctx, path, tc.value, WithVars(tc.vars))
+			ok, existsErr := Exists(ctx, path, tc.value, WithVars(tc.vars), WithSilent())
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrVariable)
+				r.EqualError(existsErr, tc.err)
+				return
+			}
+			r.NoError(err)
+			r.NoError(existsErr)
+			a.Equal(tc.exp, res)
+			a.Equal(len(tc.exp) > 0, ok)
+		})
+	}
+
+	// Each numeric kind compares equal to a path number.
+	for _, tc := range []struct {
+		name string
+		val  any
+	}{
+		{"int", int(3)},
+		{"int8", int8(3)},
+		{"int16", int16(3)},
+		{"int32", int32(3)},
+		{"int64", int64(3)},
+		{"uint", uint(3)},
+		{"uint8", uint8(3)},
+		{"uint16", uint16(3)},
+		{"uint32", uint32(3)},
+		{"uint64", uint64(3)},
+		{"float32", float32(3)},
+		{"float64", float64(3)},
+		{"json_number", json.Number("3")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			vars := WithVars(Vars{"n": tc.val})
+			path, err := parser.Parse("$n == 3 && $n > 2.5 && $n < 3.5 && $n.type() == \"number\"")
+			r.NoError(err)
+			ok, err := Match(ctx, path, nil, vars)
+			r.NoError(err)
+			a.True(ok)
+
+			path, err = parser.Parse("$[*] ? (@ == $n)")
+			r.NoError(err)
+			res, err := Query(ctx, path, []any{int64(2), json.Number("3"), float64(3)}, vars)
+			r.NoError(err)
+			a.Equal([]any{json.Number("3"), float64(3)}, res)
+		})
+	}
+
+	// float32 values use their shortest decimal representation.
+	t.Run("float32_decimal", func(t *testing.T) {
+		t.Parallel()
+		r := require.New(t)
+		path, err := parser.Parse("$x == 1.1")
+		r.NoError(err)
+		ok, err := Match(ctx, path, nil, WithVars(Vars{"x": float32(1.1)}))
+		r.NoError(err)
+		r.True(ok)
+	})
+
+	// Changes to the Vars after WithVars do not affect execution.
+	t.Run("copy", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse("$x")
+		r.NoError(err)
+		vars := Vars{"x": int64(1)}
+		opt := WithVars(vars)
+		vars["x"] = int64(2)
+		res, err := Query(ctx, path, nil, opt)
+		r.NoError(err)
+		a.Equal([]any{int64(1)}, res)
+	})
+}
+
+func TestWithRootVar(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := map[string]any{
+		"items": []any{
+			map[string]any{"name": "a", "price": int64(5), "cat": "x"},
+			map[string]any{"name": "b", "price": int64(12), "cat": "y"},
+			map[string]any{"name": "c", "price": int64(20), "cat": "x"},
+		},
+	}
+	limits := map[string]any{"max": int64(10)}
+	cats := []any{
+		map[string]any{"id": "x", "active": true, "max": int64(15)},
+		map[string]any{"id": "y", "active": false, "max": int64(100)},
+	}
+	type limit struct {
+		Max int `json:"max"`
+	}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opt   []Option
+		exp   []any
+		err   string
+	}{
+		{
+			name:  "filter",
+			path:  `$.items[*] ? (@.price > $limits.max).name`,
+			value: store,
+			opt:   []Option{WithRootVar("limits", limits)},
+			exp:   []any{"b", "c"},
+		},
+		{
+			name:  "join",
+			path:  `$.items[*] ? (@.cat == $cats[*] ? (@.active == true).id).name`,
+			value: store,
+			opt:   []Option{WithRootVar("cats", cats)},
+			exp:   []any{"a", "c"},
+		},
+		{
+			name:  "join_nested_filter",
+			path:  `$.items[*] ? (exists ($cats[*] ? (@.id == $cat && @.max < $max))).name`,
+			value: store,
+			opt: []Option{
+				WithRootVar("cats", cats),
+				WithVars(Vars{"cat": "x", "max": int64(20)}),
+			},
+			exp: []any{"a", "b", "c"},
+		},
+		{
+			name:  "scalar",
+			path:  `$.items[*] ? (@.price > $max).name`,
+			value: store,
+			opt:   []Option{WithRootVar("max", 10)},
+			exp:   []any{"b", "c"},
+		},
+		{
+			name:  "multiple",
+			path:  `$cats[*] ? (@.max > $limits.max).id`,
+			value: nil,
+			opt:   []Option{WithRootVar("cats", cats), WithRootVar("limits", limits)},
+			exp:   []any{"x", "y"},
+		},
+		{
+			name:  "precedence",
+			path:  `$limits.max + $y`,
+			value: nil,
+			opt: []Option{
+				WithVars(Vars{"limits": "override", "y": int64(1)}),
+				WithVarsFrom(map[string]any{"limits": "from"}),
+				WithRootVar("limits", limits),
+			},
+			exp: []any{int64(11)},
+		},
+		{
+			name:  "go_values",
+			path:  `$.items[*] ? (@.price > $limits.max).name`,
+			value: store,
+			opt:   []Option{WithRootVar("limits", map[string]int{"max": 15})},
+			exp:   []any{"c"},
+		},
+		{
+			name:  "struct_tags",
+			path:  `$.items[*] ? (@.price < $limits.max).name`,
+			value: store,
+			opt:   []Option{WithRootVar("limits", &limit{Max: 6}), WithStructTags("json")},
+			exp:   []any{"a"},
+		},
+		{
+			name:  "not_found",
+			path:  `$cats`,
+			value: nil,
+			opt:   []Option{WithRootVar("limits", limits)},
+			err:   `exec: could not find jsonpath variable "cats"`,
+		},
+		{
+			name:  "bad_map",
+			path:  `$x`,
+			value: nil,
+			opt:   []Option{WithRootVar("x", map[int]string{1: "hi"})},
+			err:   `exec: cannot query map[int]string: map keys must be strings`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := Query(ctx, path, tc.value, tc.opt...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				return
+			}
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+		})
+	}
+
+	t.Run("no_copy", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse(`$cats[0]`)
+		r.NoError(err)
+
+		vars := Vars{"x": int64(1)}
+		res, err := Query(ctx, path, nil, WithVars(vars), WithRootVar("cats", cats))
+		r.NoError(err)
+		r.Len(res, 1)
+		a.Equal(addrOf(cats[0]), addrOf(res[0]))
+		a.Equal(Vars{"x": int64(1)}, vars)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse(`$[*] ? (@ > $limits.max)`)
+		r.NoError(err)
+
+		res, err := QueryBatch(ctx, path, []any{
+			[]any{int64(9), int64(11)},
+			[]any{int64(12)},
+		}, WithRootVar("limits", map[string]int{"max": 10}))
+		r.NoError(err)
+		a.Equal([]BatchResult{
+			{Values: []any{int64(11)}},
+			{Values: []any{int64(12)}},
+		}, res)
+	})
+}
+
+func TestWithVarOverride(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	addr := &reflectAddress{City: "Paris", Zip: 75001}
+	vars := WithVars(Vars{"x": int64(1), "y": int64(2)})
+
+	for _, tc := range []struct {
+		name string
+		path string
+		opt  []Option
+		exp  []any
+		err  string
+	}{
+		{
+			name: "vars",
+			path: `$x + $y`,
+			opt:  []Option{vars, WithVarOverride("x", int64(10))},
+			exp:  []any{int64(12)},
+		},
+		{
+			name: "before_vars",
+			path: `$x + $y`,
+			opt:  []Option{WithVarOverride("x", int64(10)), vars},
+			exp:  []any{int64(12)},
+		},
+		{
+			name: "new_var",
+			path: `$x + $z`,
+			opt:  []Option{vars, WithVarOverride("z", int64(10))},
+			exp:  []any{int64(11)},
+		},
+		{
+			name: "no_vars",
+			path: `$z`,
+			opt:  []Option{WithVarOverride("z", "hi")},
+			exp:  []any{"hi"},
+		},
+		{
+			name: "multiple",
+			path: `$x + $y`,
+			opt:  []Option{vars, WithVarOverride("x", int64(10)), WithVarOverride("y", int64(20))},
+			exp:  []any{int64(30)},
+		},
+		{
+			name: "last_wins",
+			path: `$x`,
+			opt:  []Option{WithVarOverride("x", int64(10)), WithVarOverride("x", int64(20))},
+			exp:  []any{int64(20)},
+		},
+		{
+			name: "vars_from",
+			path: `$x`,
+			opt:  []Option{WithVarsFrom(map[string]any{"x": "from"}), WithVarOverride("x", "override")},
+			exp:  []any{"override"},
+		},
+		{
+			name: "root_var",
+			path: `$x`,
+			opt:  []Option{WithRootVar("x", []any{int64(1)}), WithVarOverride("x", "override")},
+			exp:  []any{"override"},
+		},
+		{
+			name: "convert",
+			path: `$addr.zip + $n`,
+			opt:  []Option{WithVarOverride("addr", addr), WithVarOverride("n", uint8(1))},
+			exp:  []any{int64(75002)},
+		},
+		{
+			name: "struct_result",
+			path: `$addr`,
+			opt:  []Option{WithVarOverride("addr", addr)},
+			exp:  []any{addr},
+		},
+		{
+			name: "unsupported",
+			path: `$x`,
+			opt:  []Option{vars, WithVarOverride("f", func() {})},
+			err:  `exec: variable "f" has unsupported type func()`,
+		},
+		{
+			name: "not_found",
+			path: `$z`,
+			opt:  []Option{vars, WithVarOverride("x", int64(10))},
+			err:  `exec: could not find jsonpath variable "z"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := Query(ctx, path, nil, tc.opt...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrVariable)
+				return
+			}
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+		})
+	}
+
+	// Concurrent executions with different overrides of shared Vars are
+	// isolated from each other and leave the Vars unchanged.
+	t.Run("concurrent", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		path, err := parser.Parse(`$x + $obj.n + $arr[0]`)
+		r.NoError(err)
+
+		base := Vars{
+			"x":   int64(1),
+			"obj": map[string]any{"n": int64(10)},
+			"arr": []any{int64(100)},
+		}
+		common := WithVars(base)
+
+		const n = 100
+		results := make([][]any, n)
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				opts := []Option{common}
+				switch i % 3 {
+				case 0:
+					opts = append(opts, WithVarOverride("x", int64(i)))
+				case 1:
+					opts = append(opts, WithVarOverride("obj", map[string]any{"n": int64(i)}))
+				}
+				results[i], errs[i] = Query(ctx, path, nil, opts...)
+			}()
+		}
+		wg.Wait()
+
+		for i := range n {
+			r.NoError(errs[i])
+			switch i % 3 {
+			case 0:
+				a.Equal([]any{int64(i + 110)}, results[i], i)
+			case 1:
+				a.Equal([]any{int64(i + 101)}, results[i], i)
+			default:
+				a.Equal([]any{int64(111)}, results[i], i)
+			}
+		}
+
+		a.Equal(Vars{
+			"x":   int64(1),
+			"obj": map[string]any{"n": int64(10)},
+			"arr": []any{int64(100)},
+		}, base)
+	})
+}
+
+func TestAddrOf(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	for _, tc := range []struct {
+		name  string
+		value any
+		noID  bool
+	}{
+		{
+			name:  "map",
+			value: map[string]any{"hi": 1},
+		},
+		{
+			name:  "slice",
+			value: []any{1, 2},
+		},
+		{
+			name:  "vars",
+			value: Vars{"x": true},
+		},
+		{
+			name:  "int",
+			value: int64(42),
+			noID:  true,
+		},
+		{
+			name:  "bool",
+			value: true,
+			noID:  true,
+		},
+		{
+			name: "nil",
+			noID: true,
+		},
+		{
+			name:  "datetime",
+			value: types.NewDate(time.Now()),
+			noID:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ptr := addrOf(tc.value)
+			if tc.noID {
+				a.Zero(ptr)
+			} else {
+				a.Equal(ptr, reflect.ValueOf(tc.value).Pointer())
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package exec
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stats contains counters that describe the execution of a path, for
+// monitoring the cost of queries. Pass a pointer to a Stats to [WithStats]
+// to have execution fill it in.
+type Stats struct {
+	// NodesVisited is the number of times execution applied a path node to
+	// a JSON item, including the nodes of filter expressions and other
+	// operands.
+	NodesVisited int
+	// ItemsMatched is the number of items the path selected: the number of
+	// results of [Query] and of those from which [First] returns the first.
+	// For [Match] it is the single result of the predicate check. For
+	// [Exists] in lax mode it is one if the path selected an item, because
+	// execution stops at the first.
+	ItemsMatched int
+	// MaxDepth is the greatest nesting depth execution reached. Executing
+	// the nodes following a node, or the operands of a filter or other
+	// expression, increases the depth.
+	MaxDepth int
+	// FilterEvaluations is the number of times execution evaluated the
+	// condition of a filter expression against an item.
+	FilterEvaluations int
+	// RegexMatches is the number of strings execution matched against
+	// like_regex patterns, whether or not they matched.
+	RegexMatches int
+	// Duration is the wall time spent executing the path.
+	Duration time.Duration
+}
+
+// String returns a single-line summary of s suitable for logging.
+func (s *Stats) String() string {
+	return fmt.Sprintf(
+		"nodes=%v matched=%v depth=%v filters=%v regexes=%v duration=%v",
+		s.NodesVisited, s.ItemsMatched, s.MaxDepth, s.FilterEvaluations,
+		s.RegexMatches, s.Duration,
+	)
+}
+
+// add adds the counters in other to s, and raises s.MaxDepth to
+// other.MaxDepth if it's greater.
+func (s *Stats) add(other *Stats) {
+	s.NodesVisited += other.NodesVisited
+	s.ItemsMatched += other.ItemsMatched
+	s.MaxDepth = max(s.MaxDepth, other.MaxDepth)
+	s.FilterEvaluations += other.FilterEvaluations
+	s.RegexMatches += other.RegexMatches
+	s.Duration += other.Duration
+}
+
+// WithStats fills in stats during execution. Execution adds to the existing
+// counters, except for [Stats.MaxDepth], which it raises, so that a single
+// Stats can accumulate the statistics of several executions; pass a zero
+// Stats to collect those of a single execution. Concurrent executions must
+// not share a Stats. [WithParallel] collects the statistics of each
+// goroutine separately and adds them to stats once they finish.
+func WithStats(stats *Stats) Option { return func(e *Executor) { e.stats = stats } }
@@ -0,0 +1,171 @@
+package exec
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestWithStats(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	value := map[string]any{
+		"a": []any{
+			map[string]any{"b": int64(1), "c": "x"},
+			map[string]any{"b": int64(3), "c": "y"},
+			map[string]any{"b": int64(5)},
+		},
+	}
+
+	query := func(ctx context.Context, path *ast.AST, opt ...Option) error {
+		_, err := Query(ctx, path, value, opt...)
+		return err
+	}
+	first := func(ctx context.Context, path *ast.AST, opt ...Option) error {
+		_, err := First(ctx, path, value, opt...)
+		return err
+	}
+	exists := func(ctx context.Context, path *ast.AST, opt ...Option) error {
+		_, err := Exists(ctx, path, value, opt...)
+		return err
+	}
+	match := func(ctx context.Context, path *ast.AST, opt ...Option) error {
+		_, err := Match(ctx, path, value, opt...)
+		return err
+	}
+
+	for _, tc := range []struct {
+		name string
+		fn   func(context.Context, *ast.AST, ...Option) error
+		path string
+		opt  []Option
+		exp  Stats
+	}{
+		{
+			name: "query_member",
+			fn:   query,
+			path: `$.a`,
+			exp:  Stats{NodesVisited: 2, ItemsMatched: 1, MaxDepth: 2},
+		},
+		{
+			name: "query_wildcard",
+			fn:   query,
+			path: `$.a[*].b`,
+			exp:  Stats{NodesVisited: 6, ItemsMatched: 3, MaxDepth: 5},
+		},
+		{
+			name: "query_filter",
+			fn:   query,
+			path: `$.a[*] ? (@.b > 2)`,
+			exp: Stats{
+				NodesVisited: 3 + 3*4, ItemsMatched: 2, MaxDepth: 7,
+				FilterEvaluations: 3,
+			},
+		},
+		{
+			name: "query_like_regex",
+			fn:   query,
+			path: `$.a[*] ? (@.c like_regex "^y")`,
+			exp: Stats{
+				NodesVisited: 3 + 3*3, ItemsMatched: 1, MaxDepth: 7,
+				FilterEvaluations: 3, RegexMatches: 2,
+			},
+		},
+		{
+			name: "query_none",
+			fn:   query,
+			path: `$.x`,
+			exp:  Stats{NodesVisited: 2, MaxDepth: 2},
+		},
+		{
+			name: "first",
+			fn:   first,
+			path: `$.a[*].b`,
+			exp:  Stats{NodesVisited: 6, ItemsMatched: 3, MaxDepth: 5},
+		},
+		{
+			name: "exists_lax",
+			fn:   exists,
+			path: `$.a[*].b`,
+			exp:  Stats{NodesVisited: 4, ItemsMatched: 1, MaxDepth: 5},
+		},
+		{
+			name: "exists_strict",
+			fn:   exists,
+			path: `strict $.a[*].b`,
+			exp:  Stats{NodesVisited: 6, ItemsMatched: 3, MaxDepth: 5},
+		},
+		{
+			name: "exists_none",
+			fn:   exists,
+			path: `$.x`,
+			exp:  Stats{NodesVisited: 2, MaxDepth: 2},
+		},
+		{
+			name: "match",
+			fn:   match,
+			path: `exists($.a[*] ? (@.b == 5))`,
+			exp: Stats{
+				NodesVisited: 4 + 3*4, ItemsMatched: 1, MaxDepth: 8,
+				FilterEvaluations: 3,
+			},
+		},
+		{
+			name: "parallel",
+			fn:   query,
+			path: `$.a[*] ? (@.b > 2)`,
+			opt:  []Option{WithParallel(2), withParallelThreshold(1)},
+			exp: Stats{
+				NodesVisited: 3 + 3*4, ItemsMatched: 2, MaxDepth: 7,
+				FilterEvaluations: 3,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			stats := &Stats{}
+			r.NoError(tc.fn(ctx, path, append(tc.opt, WithStats(stats))...))
+			a.GreaterOrEqual(stats.Duration, time.Duration(0))
+			stats.Duration = 0
+			a.Equal(tc.exp, *stats)
+
+			// Execution adds to the counters.
+			r.NoError(tc.fn(ctx, path, append(tc.opt, WithStats(stats))...))
+			stats.Duration = 0
+			a.Equal(Stats{
+				NodesVisited:      tc.exp.NodesVisited * 2,
+				ItemsMatched:      tc.exp.ItemsMatched * 2,
+				MaxDepth:          tc.exp.MaxDepth,
+				FilterEvaluations: tc.exp.FilterEvaluations * 2,
+				RegexMatches:      tc.exp.RegexMatches * 2,
+			}, *stats)
+		})
+	}
+}
+
+func TestStatsString(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	stats := &Stats{
+		NodesVisited:      12,
+		ItemsMatched:      2,
+		MaxDepth:          5,
+		FilterEvaluations: 3,
+		RegexMatches:      1,
+		Duration:          1500 * time.Microsecond,
+	}
+	a.Equal("nodes=12 matched=2 depth=5 filters=3 regexes=1 duration=1.5ms", stats.String())
+	a.Equal("nodes=0 matched=0 depth=0 filters=0 regexes=0 duration=0s", (&Stats{}).String())
+}
@@ -0,0 +1,120 @@
+package exec
+
+import (
+	"context"
+
+	"github.com/theory/sqljson/path/ast"
+)
+
+// Ternary represents the result of a SQL/JSON predicate under SQL's
+// three-valued logic: true, false, or unknown, which PostgreSQL represents
+// as NULL. The zero value is [Null].
+type Ternary uint8
+
+const (
+	// Null represents an unknown result, NULL in PostgreSQL.
+	Null Ternary = iota
+	// False represents a false result.
+	False
+	// True represents a true result.
+	True
+)
+
+// ternaryOf returns [True] if b is true and [False] if it is false.
+func ternaryOf(b bool) Ternary {
+	if b {
+		return True
+	}
+	return False
+}
+
+// String returns "true", "false", or "null".
+func (t Ternary) String() string {
+	switch t {
+	case True:
+		return "true"
+	case False:
+		return "false"
+	default:
+		return "null"
+	}
+}
+
+// Bool returns true if t is [True] and false otherwise. Returns the [NULL]
+// error value if t is [Null].
+func (t Ternary) Bool() (bool, error) {
+	if t == Null {
+		return false, NULL
+	}
+	return t == True, nil
+}
+
+// boolResult converts the result of a Ternary function into the result of
+// its bool counterpart, returning false and [NULL] for [Null] when err is
+// nil.
+func boolResult(t Ternary, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	return t.Bool()
+}
+
+// ExistsTri is like [Exists], but returns [Null] rather than the [NULL]
+// error value when the result is unknown, so that it mirrors the
+// three-valued result of the PostgreSQL jsonb_path_exists() function.
+// Returns [Null] with any error.
+func ExistsTri(ctx context.Context, path *ast.AST, value any, opt ...Option) (Ternary, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return Null, err
+	}
+	defer exec.release()
+	return exec.existsResult(ctx, "Exists", value)
+}
+
+// MatchTri is like [Match], but returns [Null] rather than the [NULL] error
+// value when the result is unknown, so that it mirrors the three-valued
+// result of the PostgreSQL jsonb_path_match() function. Returns [Null] with
+// any error.
+func MatchTri(ctx context.Context, path *ast.AST, value any, opt ...Option) (Ternary, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return Null, err
+	}
+	defer exec.release()
+	return exec.matchResult(ctx, "Match", value)
+}
+
+// AtQuestionTri is like [AtQuestion], but returns [Null] rather than the
+// [NULL] error value when the result is unknown, just as the PostgreSQL @?
+// operator returns NULL. Returns [Null] with any error.
+func AtQuestionTri(ctx context.Context, path *ast.AST, value any, opt ...Option) (Ternary, error) {
+	exec, err := newExec(path, silently(opt)...)
+	if err != nil {
+		return Null, err
+	}
+	defer exec.release()
+	return exec.existsResult(ctx, "AtQuestion", value)
+}
+
+// AtAtTri is like [AtAt], but returns [Null] rather than the [NULL] error
+// value when the result is unknown, just as the PostgreSQL @@ operator
+// returns NULL. Returns [Null] with any error.
+func AtAtTri(ctx context.Context, path *ast.AST, value any, opt ...Option) (Ternary, error) {
+	exec, err := newExec(path, silently(opt)...)
+	if err != nil {
+		return Null, err
+	}
+	defer exec.release()
+	return exec.matchResult(ctx, "AtAt", value)
+}
+
+// ExistsOrMatchTri is like [ExistsOrMatch], but dispatches to [AtAtTri] or
+// [AtQuestionTri], returning [Null] rather than the [NULL] error value when
+// the result is unknown.
+func ExistsOrMatchTri(ctx context.Context, path *ast.AST, value any, opt ...Option) (Ternary, error) {
+	if path.IsPredicate() {
+		return AtAtTri(ctx, path, value, opt...)
+	}
+	return AtQuestionTri(ctx, path, value, opt...)
+}
@@ -0,0 +1,56 @@
+package exec
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTernary(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	for _, tc := range []struct {
+		name  string
+		tri   Ternary
+		str   string
+		exp   bool
+		isErr error
+	}{
+		{"true", True, "true", true, nil},
+		{"false", False, "false", false, nil},
+		{"null", Null, "null", false, NULL},
+		{"unknown", Ternary(42), "null", false, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a.Equal(tc.str, tc.tri.String())
+			res, err := tc.tri.Bool()
+			a.Equal(tc.exp, res)
+			if tc.isErr == nil {
+				r.NoError(err)
+			} else {
+				r.ErrorIs(err, tc.isErr)
+			}
+		})
+	}
+
+	a.Equal(Null, Ternary(0))
+	a.Equal(True, ternaryOf(true))
+	a.Equal(False, ternaryOf(false))
+
+	// boolResult prefers the error.
+	oops := errors.New("oops")
+	res, err := boolResult(True, oops)
+	r.ErrorIs(err, oops)
+	a.False(res)
+	res, err = boolResult(Null, nil)
+	r.ErrorIs(err, NULL)
+	a.False(res)
+	res, err = boolResult(True, nil)
+	r.NoError(err)
+	a.True(res)
+}
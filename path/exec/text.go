@@ -0,0 +1,231 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/types"
+)
+
+// Limits on the exponents of numbers appendNumeric expands, the largest
+// numbers of digits before and after the decimal point of a PostgreSQL
+// numeric value.
+const (
+	maxNumericWeight = 131072
+	maxNumericScale  = 16383
+)
+
+// QueryText is like [Query], but returns each selected item formatted as
+// PostgreSQL formats jsonb values as text, so that the results compare
+// equal to the output of jsonb_path_query() in psql. It formats:
+//
+//   - Objects with their keys sorted first by length and then bytewise, as
+//     jsonb stores them, and with a space after each colon and comma
+//   - Arrays with a space after each comma
+//   - [json.Number] values without exponents and with the number of
+//     fractional digits they specify, so that 1e2 becomes 100 and 1.50
+//     remains 1.50
+//   - Strings with only quotation marks, backslashes, and control
+//     characters escaped, so that non-ASCII characters appear unescaped
+//   - Datetime values as strings in the ISO 8601 format PostgreSQL uses
+//     for JSON
+//
+// Numbers computed as float64 values, such as the results of arithmetic
+// on floats, have no trailing fractional zeros, whereas PostgreSQL
+// numeric values may retain them. JSON objects decoded by
+// [encoding/json] retain the last value of duplicate keys, as jsonb does.
+// The options act the same as for [Query], except that results are always
+// formatted from their JSON values, never from the original Go values.
+func QueryText(ctx context.Context, path *ast.AST, value any, opt ...Option) ([]string, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.release()
+	if err := exec.checkPredicate("QueryText", false); err != nil {
+		return nil, err
+	}
+
+	vals, err := exec.execute(ctx, value, exec.newResultList())
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, len(vals.list))
+	for i, val := range vals.list {
+		if exec.scratch, err = exec.appendText(exec.scratch[:0], val); err != nil {
+			return nil, err
+		}
+		res[i] = string(exec.scratch)
+	}
+	return res, nil
+}
+
+// appendText appends the PostgreSQL jsonb text format of val to b.
+// Converts Go values not yet converted to JSON values as execution does.
+// Returns an [ErrExecution] error for values that cannot be converted.
+func (exec *Executor) appendText(b []byte, val any) ([]byte, error) {
+	var err error
+	switch val := val.(type) {
+	case nil:
+		return append(b, "null"...), nil
+	case bool:
+		return strconv.AppendBool(b, val), nil
+	case string:
+		return appendString(b, val), nil
+	case int64:
+		return strconv.AppendInt(b, val, 10), nil
+	case float64:
+		return strconv.AppendFloat(b, unsignedZero(val), 'f', -1, 64), nil
+	case json.Number:
+		return appendNumeric(b, val), nil
+	case types.DateTime:
+		return appendString(b, exec.displayDateTime(val).String()), nil
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		slices.SortFunc(keys, compareKeys)
+		b = append(b, '{')
+		for i, k := range keys {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b = appendString(b, k)
+			b = append(b, ": "...)
+			if b, err = exec.appendText(b, val[k]); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, '}'), nil
+	case []any:
+		b = append(b, '[')
+		for i, v := range val {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			if b, err = exec.appendText(b, v); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ']'), nil
+	}
+
+	if res, ok := convertScalar(reflect.ValueOf(val)); ok {
+		return exec.appendText(b, res)
+	}
+	norm, err := exec.normalize(val)
+	if err != nil {
+		return nil, err
+	}
+	return exec.appendText(b, norm)
+}
+
+// compareKeys compares object keys in the order jsonb stores them: shorter
+// keys first, and keys of the same length bytewise.
+func compareKeys(a, b string) int {
+	if cmp := len(a) - len(b); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(a, b)
+}
+
+// appendString appends str to b as a JSON string, escaped as PostgreSQL
+// escapes JSON strings: quotation marks, backslashes, and the control
+// characters with short escapes use them, other control characters use
+// \u escapes, and all other characters appear unescaped.
+func appendString(b []byte, str string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	for i := range len(str) {
+		switch c := str[i]; c {
+		case '"':
+			b = append(b, `\"`...)
+		case '\\':
+			b = append(b, `\\`...)
+		case '\b':
+			b = append(b, `\b`...)
+		case '\f':
+			b = append(b, `\f`...)
+		case '\n':
+			b = append(b, `\n`...)
+		case '\r':
+			b = append(b, `\r`...)
+		case '\t':
+			b = append(b, `\t`...)
+		default:
+			if c < ' ' {
+				b = append(b, `\u00`...)
+				b = append(b, hex[c>>4], hex[c&0xf])
+			} else {
+				b = append(b, c)
+			}
+		}
+	}
+	return append(b, '"')
+}
+
+// appendNumeric appends num to b as PostgreSQL formats numeric values:
+// without an exponent and with the number of fractional digits num
+// specifies, less its exponent, so that 1e2 appends 100, 1.50 appends
+// 1.50, and 1.50e1 appends 15.0. Negative zero appends as zero. Appends num
+// unchanged if it is not a valid number or its exponent is out of the
+// range of numeric.
+func appendNumeric(b []byte, num json.Number) []byte {
+	str := string(num)
+	neg := false
+	if str != "" && (str[0] == '-' || str[0] == '+') {
+		neg = str[0] == '-'
+		str = str[1:]
+	}
+
+	exp := 0
+	if idx := strings.IndexAny(str, "eE"); idx >= 0 {
+		var err error
+		if exp, err = strconv.Atoi(str[idx+1:]); err != nil ||
+			exp > maxNumericWeight || exp < -maxNumericScale {
+			return append(b, num...)
+		}
+		str = str[:idx]
+	}
+
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool {
+		return r < '0' || r > '9'
+	}) >= 0 {
+		return append(b, num...)
+	}
+
+	// Move the decimal point exp digits, padding with zeros as necessary.
+	// The fractional digits that remain determine the scale.
+	var whole, frac string
+	switch point := len(intPart) + exp; {
+	case point <= 0:
+		frac = strings.Repeat("0", -point) + digits
+	case point >= len(digits):
+		whole = digits + strings.Repeat("0", point-len(digits))
+	default:
+		whole, frac = digits[:point], digits[point:]
+	}
+
+	whole = strings.TrimLeft(whole, "0")
+	if whole == "" {
+		whole = "0"
+	}
+	if neg && strings.Trim(digits, "0") != "" {
+		b = append(b, '-')
+	}
+	b = append(b, whole...)
+	if frac != "" {
+		b = append(b, '.')
+		b = append(b, frac...)
+	}
+	return b
+}
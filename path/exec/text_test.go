@@ -0,0 +1,286 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestQueryText(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// Expected values are the output of jsonb_path_query() in psql for the
+	// same path and document, e.g.:
+	//
+	//	SELECT jsonb_path_query('{"qty": 1e2}', '$.qty');
+	doc := `{
+		"name": "Café \"Bob\"",
+		"tags": ["a\tb", "\u0001", "/", "\\", "é\n"],
+		"price": 1.50,
+		"qty": 1e2,
+		"ratio": 1.50E+1,
+		"tiny": 1e-3,
+		"scaled": 12.300e-1,
+		"neg": -0,
+		"negz": -0.0,
+		"big": 12345678901234567890,
+		"nested": {"bb": true, "a": null, "ccc": {}, "dd": [1, {"y": 2, "x": 1}]},
+		"list": [],
+		"when": "2024-01-02 03:04:05+01",
+		"dup": {"a": 1, "a": 2}
+	}`
+
+	for _, tc := range []struct {
+		name string
+		path string
+		exp  []string
+	}{
+		{
+			name: "object",
+			path: `$.nested`,
+			exp:  []string{`{"a": null, "bb": true, "dd": [1, {"x": 1, "y": 2}], "ccc": {}}`},
+		},
+		{
+			name: "empty_array",
+			path: `$.list`,
+			exp:  []string{`[]`},
+		},
+		{
+			name: "array",
+			path: `$.tags`,
+			exp:  []string{`["a\tb", "\u0001", "/", "\\", "é\n"]`},
+		},
+		{
+			name: "strings",
+			path: `$.tags[*]`,
+			exp:  []string{`"a\tb"`, `"\u0001"`, `"/"`, `"\\"`, `"é\n"`},
+		},
+		{
+			name: "quotes",
+			path: `$.name`,
+			exp:  []string{`"Café \"Bob\""`},
+		},
+		{
+			name: "price",
+			path: `$.price`,
+			exp:  []string{`1.50`},
+		},
+		{
+			name: "exponent",
+			path: `$.qty`,
+			exp:  []string{`100`},
+		},
+		{
+			name: "exponent_scale",
+			path: `$.ratio`,
+			exp:  []string{`15.0`},
+		},
+		{
+			name: "negative_exponent",
+			path: `$.tiny`,
+			exp:  []string{`0.001`},
+		},
+		{
+			name: "negative_exponent_scale",
+			path: `$.scaled`,
+			exp:  []string{`1.2300`},
+		},
+		{
+			name: "negative_zero",
+			path: `$.neg`,
+			exp:  []string{`0`},
+		},
+		{
+			name: "negative_zero_scale",
+			path: `$.negz`,
+			exp:  []string{`0.0`},
+		},
+		{
+			name: "big",
+			path: `$.big`,
+			exp:  []string{`12345678901234567890`},
+		},
+		{
+			name: "duplicate_keys",
+			path: `$.dup`,
+			exp:  []string{`{"a": 2}`},
+		},
+		{
+			name: "true",
+			path: `$.nested.bb`,
+			exp:  []string{`true`},
+		},
+		{
+			name: "null",
+			path: `$.nested.a`,
+			exp:  []string{`null`},
+		},
+		{
+			name: "predicate",
+			path: `$.list.size() == 0`,
+			exp:  []string{`true`},
+		},
+		{
+			name: "computed",
+			path: `$.tags.size() * 2`,
+			exp:  []string{`10`},
+		},
+		{
+			name: "type",
+			path: `$.name.type()`,
+			exp:  []string{`"string"`},
+		},
+		{
+			name: "timestamptz",
+			path: `$.when.datetime()`,
+			exp:  []string{`"2024-01-02T03:04:05+01:00"`},
+		},
+		{
+			name: "date",
+			path: `"2024-01-02".datetime()`,
+			exp:  []string{`"2024-01-02"`},
+		},
+		{
+			name: "time",
+			path: `"12:34:56.789".datetime()`,
+			exp:  []string{`"12:34:56.789"`},
+		},
+		{
+			name: "no_results",
+			path: `$.nope`,
+			exp:  []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			dec := json.NewDecoder(strings.NewReader(doc))
+			dec.UseNumber()
+			var value any
+			r.NoError(dec.Decode(&value))
+
+			res, err := QueryText(ctx, path, value)
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+		})
+	}
+}
+
+func TestQueryTextValues(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		opt   []Option
+		exp   []string
+		err   string
+	}{
+		{
+			name:  "floats",
+			path:  `$[*]`,
+			value: []any{float64(1.5), float64(-0.0), float64(1e21), float64(0.1)},
+			exp:   []string{`1.5`, `0`, `1000000000000000000000`, `0.1`},
+		},
+		{
+			name:  "typed_containers",
+			path:  `$`,
+			value: map[string][]int8{"x": {1, 2}},
+			exp:   []string{`{"x": [1, 2]}`},
+		},
+		{
+			name:  "struct_tags",
+			path:  `$`,
+			value: &reflectAddress{City: "Zürich", Zip: 8001},
+			opt:   []Option{WithStructTags("json")},
+			exp:   []string{`{"zip": 8001, "city": "Zürich"}`},
+		},
+		{
+			name:  "vars",
+			path:  `$x`,
+			value: nil,
+			opt:   []Option{WithVars(Vars{"x": []float32{1.1}})},
+			exp:   []string{`[1.1]`},
+		},
+		{
+			name:  "unsupported",
+			path:  `$`,
+			value: []any{func() {}},
+			err:   "exec: unsupported value type func()",
+		},
+		{
+			name:  "predicate_check",
+			path:  `$ == 1`,
+			value: int64(1),
+			opt:   []Option{WithPredicateCheck()},
+			err:   `exec: QueryText expects a SQL standard path expression but "($ == 1)" is a predicate check expression`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+
+			res, err := QueryText(ctx, path, tc.value, tc.opt...)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(res)
+				return
+			}
+			r.NoError(err)
+			a.Equal(tc.exp, res)
+		})
+	}
+}
+
+func TestAppendNumeric(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		num json.Number
+		exp string
+	}{
+		{"0", "0"},
+		{"-0", "0"},
+		{"-0.00", "0.00"},
+		{"42", "42"},
+		{"-42", "-42"},
+		{"007", "7"},
+		{"1e2", "100"},
+		{"1E+2", "100"},
+		{"1.5e1", "15"},
+		{"1.50e1", "15.0"},
+		{"1.5e-1", "0.15"},
+		{"-1.5e-3", "-0.0015"},
+		{"123.456e1", "1234.56"},
+		{"123.456e-5", "0.00123456"},
+		{"0.0e5", "0"},
+		{"1e-2", "0.01"},
+		{"+3", "3"},
+		{"1e131073", "1e131073"},
+		{"1e-16384", "1e-16384"},
+		{"1ex", "1ex"},
+		{"x", "x"},
+		{"", ""},
+	} {
+		t.Run(string(tc.num), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.exp, string(appendNumeric(nil, tc.num)))
+		})
+	}
+}
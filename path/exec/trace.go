@@ -0,0 +1,217 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/types"
+	"golang.org/x/exp/maps" // Switch to maps when go 1.22 dropped
+)
+
+// DefaultTraceLimit is the default maximum number of events recorded by
+// [Explain].
+const DefaultTraceLimit = 10000
+
+// traceItemSize is the maximum size of [TraceEvent] Item strings, not
+// including the ellipsis that replaces the remainder.
+const traceItemSize = 60
+
+// TraceEvent records a step in the execution of a path by [Explain]: the
+// application of a path node to a JSON item.
+type TraceEvent struct {
+	// Depth is the nesting depth of the step. Steps that execute the nodes
+	// following a node, or the operands of a filter or other expression,
+	// have greater depth.
+	Depth int
+	// Node is the SQL/JSON path representation of the node, without the
+	// nodes that follow it.
+	Node string
+	// Path is the location of the item, a normalized path such as
+	// $."a"[2].
+	Path string
+	// Item is the JSON representation of the item, with object members
+	// sorted by key and truncated to about 60 bytes.
+	Item string
+	// Result is the result of the step: "OK", "NOT_FOUND", or "FAILED" for
+	// path nodes and "TRUE", "FALSE", or "UNKNOWN" for predicates, such as
+	// the conditions of filter expressions.
+	Result string
+}
+
+// String returns a single-line representation of ev indented by its depth.
+func (ev TraceEvent) String() string {
+	return fmt.Sprintf(
+		"%v%v  %v = %v  → %v",
+		strings.Repeat("  ", ev.Depth), ev.Node, ev.Path, ev.Item, ev.Result,
+	)
+}
+
+// Trace contains the steps recorded by [Explain], in the order in which
+// execution started them.
+type Trace struct {
+	Events []TraceEvent
+	// Truncated is true when execution took more steps than the trace
+	// limit; see [WithTraceLimit].
+	Truncated bool
+}
+
+// String returns a multi-line representation of t, one line per event.
+func (t *Trace) String() string {
+	var buf strings.Builder
+	for _, ev := range t.Events {
+		buf.WriteString(ev.String())
+		buf.WriteByte('\n')
+	}
+	if t.Truncated {
+		buf.WriteString("…\n")
+	}
+	return buf.String()
+}
+
+// WithTraceLimit limits the number of events recorded by [Explain] to n,
+// after which it stops recording events and sets [Trace.Truncated] to true.
+// Defaults to [DefaultTraceLimit]; zero disables the limit. Explain returns
+// an [ErrInvalid] error for a negative value.
+func WithTraceLimit(n int) Option { return func(e *Executor) { e.traceLimit = n } }
+
+// Explain executes path against value like [Query], recording each step of
+// execution in the returned [Trace] in order to help debug paths that don't
+// select the expected items. Traces are deterministic for deterministic
+// inputs: wildcard and .** accessors iterate over object members sorted by
+// key, and [WithParallel] has no effect. Returns the trace along with any
+// error returned by execution, so that the trace shows where it failed.
+// Executing other functions never records traces.
+func Explain(ctx context.Context, path *ast.AST, value any, opt ...Option) (*Trace, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.release()
+	exec.trace = &Trace{}
+	_, err = exec.execute(ctx, value, exec.newResultList())
+	return exec.trace, err
+}
+
+// traceStart records the start of the execution of node against value and
+// returns the index of its event, or -1 if the trace is full.
+func (exec *Executor) traceStart(node ast.Node, value any) int {
+	if exec.traceLimit > 0 && len(exec.trace.Events) >= exec.traceLimit {
+		exec.trace.Truncated = true
+		return -1
+	}
+	exec.trace.Events = append(exec.trace.Events, TraceEvent{
+		Depth: exec.depth,
+		Node:  traceNode(node),
+		Path:  exec.locationString(),
+		Item:  traceItem(value),
+	})
+	return len(exec.trace.Events) - 1
+}
+
+// traceEnd records result as the result of the event at index idx.
+func (exec *Executor) traceEnd(idx int, result fmt.Stringer) {
+	if idx >= 0 {
+		exec.trace.Events[idx].Result = result.String()
+	}
+}
+
+// traceNode returns the SQL/JSON path representation of node without the
+// nodes that follow it.
+func traceNode(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.KeyNode:
+		return "." + node.String()
+	case *ast.ConstNode:
+		if node.Const() == ast.ConstAnyKey {
+			return ".*"
+		}
+		return node.String()
+	case *ast.AnyNode:
+		return "." + ast.NewAny(int(node.First()), int(node.Last())).String()
+	case *ast.ArrayIndexNode:
+		return ast.NewArrayIndex(node.Subscripts()).String()
+	case *ast.UnaryNode:
+		return ast.NewUnary(node.Operator(), node.Operand()).String()
+	case *ast.BinaryNode:
+		return ast.NewBinary(node.Operator(), node.Left(), node.Right()).String()
+	case nil:
+		return ""
+	default:
+		return node.String()
+	}
+}
+
+// traceItem returns the JSON representation of value, truncated to about
+// traceItemSize bytes.
+func traceItem(value any) string {
+	buf := appendTraceItem(nil, value)
+	if len(buf) <= traceItemSize {
+		return string(buf)
+	}
+
+	// Truncate on a rune boundary.
+	size := traceItemSize
+	for size > 0 && !utf8.RuneStart(buf[size]) {
+		size--
+	}
+	return string(buf[:size]) + "…"
+}
+
+// appendTraceItem appends the JSON representation of value to buf, sorting
+// object members by key. Stops appending to objects and arrays once buf
+// exceeds traceItemSize bytes.
+func appendTraceItem(buf []byte, value any) []byte {
+	switch value := value.(type) {
+	case nil:
+		return append(buf, "null"...)
+	case bool:
+		return strconv.AppendBool(buf, value)
+	case int64:
+		return strconv.AppendInt(buf, value, 10)
+	case float64:
+		return strconv.AppendFloat(buf, value, 'g', -1, 64)
+	case json.Number:
+		return append(buf, value...)
+	case string:
+		return strconv.AppendQuote(buf, value)
+	case types.DateTime:
+		return strconv.AppendQuote(buf, value.String())
+	case []any:
+		buf = append(buf, '[')
+		for i, v := range value {
+			if len(buf) > traceItemSize {
+				break
+			}
+			if i > 0 {
+				buf = append(buf, ", "...)
+			}
+			buf = appendTraceItem(buf, v)
+		}
+		return append(buf, ']')
+	case map[string]any:
+		keys := maps.Keys(value)
+		slices.Sort(keys)
+		buf = append(buf, '{')
+		for i, k := range keys {
+			if len(buf) > traceItemSize {
+				break
+			}
+			if i > 0 {
+				buf = append(buf, ", "...)
+			}
+			buf = strconv.AppendQuote(buf, k)
+			buf = append(buf, ": "...)
+			buf = appendTraceItem(buf, value[k])
+		}
+		return append(buf, '}')
+	default:
+		// Unconverted Go value.
+		return fmt.Appendf(buf, "%v", value)
+	}
+}
@@ -0,0 +1,198 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	value := map[string]any{
+		"a": []any{
+			map[string]any{"b": int64(1), "c": "x"},
+			map[string]any{"b": int64(3)},
+		},
+		"z": map[string]any{"y": int64(1), "x": int64(2)},
+	}
+
+	for _, tc := range []struct {
+		name string
+		path string
+		opt  []Option
+		exp  string
+		err  string
+	}{
+		{
+			name: "filter",
+			path: `$.a[*] ? (@.b > 2).b`,
+			exp: `  $  $ = {"a": [{"b": 1, "c": "x"}, {"b": 3}], "z": {"x": 2, "y": 1}}  → OK
+    ."a"  $ = {"a": [{"b": 1, "c": "x"}, {"b": 3}], "z": {"x": 2, "y": 1}}  → OK
+      [*]  $."a" = [{"b": 1, "c": "x"}, {"b": 3}]  → OK
+          ?(@."b" > 2)  $."a"[0] = {"b": 1, "c": "x"}  → NOT_FOUND
+          @."b" > 2  $."a"[0] = {"b": 1, "c": "x"}  → FALSE
+            @  $."a"[0] = {"b": 1, "c": "x"}  → OK
+              ."b"  $."a"[0] = {"b": 1, "c": "x"}  → OK
+            2  $."a"[0] = {"b": 1, "c": "x"}  → OK
+          ?(@."b" > 2)  $."a"[1] = {"b": 3}  → OK
+          @."b" > 2  $."a"[1] = {"b": 3}  → TRUE
+            @  $."a"[1] = {"b": 3}  → OK
+              ."b"  $."a"[1] = {"b": 3}  → OK
+            2  $."a"[1] = {"b": 3}  → OK
+            ."b"  $."a"[1] = {"b": 3}  → OK
+`,
+		},
+		{
+			name: "error",
+			path: `strict $.a[*].c`,
+			exp: `  $  $ = {"a": [{"b": 1, "c": "x"}, {"b": 3}], "z": {"x": 2, "y": 1}}  → FAILED
+    ."a"  $ = {"a": [{"b": 1, "c": "x"}, {"b": 3}], "z": {"x": 2, "y": 1}}  → FAILED
+      [*]  $."a" = [{"b": 1, "c": "x"}, {"b": 3}]  → FAILED
+          ."c"  $."a"[0] = {"b": 1, "c": "x"}  → OK
+          ."c"  $."a"[1] = {"b": 3}  → FAILED
+`,
+			err: `exec: JSON object does not contain key "c"`,
+		},
+		{
+			name: "sorted_members",
+			path: `$.z.*.type()`,
+			exp: `  $  $ = {"a": [{"b": 1, "c": "x"}, {"b": 3}], "z": {"x": 2, "y": 1}}  → OK
+    ."z"  $ = {"a": [{"b": 1, "c": "x"}, {"b": 3}], "z": {"x": 2, "y": 1}}  → OK
+      .*  $."z" = {"x": 2, "y": 1}  → OK
+          .type()  $."z"."x" = 2  → OK
+          .type()  $."z"."y" = 1  → OK
+`,
+		},
+		{
+			name: "limit",
+			path: `$.a[*].b`,
+			opt:  []Option{WithTraceLimit(3)},
+			exp: `  $  $ = {"a": [{"b": 1, "c": "x"}, {"b": 3}], "z": {"x": 2, "y": 1}}  → OK
+    ."a"  $ = {"a": [{"b": 1, "c": "x"}, {"b": 3}], "z": {"x": 2, "y": 1}}  → OK
+      [*]  $."a" = [{"b": 1, "c": "x"}, {"b": 3}]  → OK
+…
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			trace, err := Explain(ctx, path, value, tc.opt...)
+			if tc.err == "" {
+				r.NoError(err)
+			} else {
+				r.EqualError(err, tc.err)
+			}
+			a.Equal(tc.exp, trace.String())
+			a.Equal(strings.HasSuffix(tc.exp, "…\n"), trace.Truncated)
+
+			// Output must be deterministic.
+			again, _ := Explain(ctx, path, value, tc.opt...)
+			a.Equal(trace, again)
+		})
+	}
+}
+
+func TestExplainParallel(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+	ctx := context.Background()
+
+	array := make([]any, 100)
+	for i := range array {
+		array[i] = int64(i)
+	}
+
+	path, err := parser.Parse("$[*] ? (@ > 10)")
+	r.NoError(err)
+	exp, err := Explain(ctx, path, array, WithTraceLimit(0))
+	r.NoError(err)
+	r.False(exp.Truncated)
+	trace, err := Explain(ctx, path, array, WithTraceLimit(0), WithParallel(4), withParallelThreshold(1))
+	r.NoError(err)
+	r.Equal(exp, trace)
+}
+
+func TestTraceEvent(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	ev := TraceEvent{Depth: 2, Node: `."a"`, Path: "$", Item: `{"a": 1}`, Result: "OK"}
+	a.Equal(`    ."a"  $ = {"a": 1}  → OK`, ev.String())
+}
+
+func TestTraceNode(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	path, err := parser.Parse(
+		`$.a[*].**{1 to 2}.* ? (@.b > 1).size()[0 to last].decimal(4, 2)`,
+	)
+	r.NoError(err)
+
+	labels := []string{}
+	for node := path.Root(); node != nil; node = node.Next() {
+		labels = append(labels, traceNode(node))
+	}
+	a.Equal([]string{
+		"$", `."a"`, "[*]", ".**{1 to 2}", ".*", `?(@."b" > 1)`,
+		".size()", "[0 to last]", ".decimal(4,2)",
+	}, labels)
+	a.Equal("", traceNode(nil))
+	a.Equal(`"x"`, traceNode(ast.NewString("x")))
+}
+
+func TestTraceItem(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		value any
+		exp   string
+	}{
+		{"null", nil, "null"},
+		{"true", true, "true"},
+		{"int", int64(42), "42"},
+		{"float", float64(98.6), "98.6"},
+		{"number", json.Number("1.50"), "1.50"},
+		{"string", "hi\n", `"hi\n"`},
+		{"datetime", pt(ctx, "2024-06-05"), `"2024-06-05"`},
+		{"array", []any{int64(1), "two"}, `[1, "two"]`},
+		{"object", map[string]any{"b": int64(1), "a": nil}, `{"a": null, "b": 1}`},
+		{"go_value", []int{1, 2}, "[1 2]"},
+		{
+			"long_string",
+			strings.Repeat("x", 100),
+			`"` + strings.Repeat("x", 59) + "…",
+		},
+		{
+			"long_array",
+			[]any{strings.Repeat("x", 50), strings.Repeat("y", 50), "z"},
+			`["` + strings.Repeat("x", 50) + `", "` + strings.Repeat("y", 4) + "…",
+		},
+		{
+			"multibyte",
+			strings.Repeat("é", 40),
+			`"` + strings.Repeat("é", 29) + "…",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a.Equal(tc.exp, traceItem(tc.value))
+		})
+	}
+}
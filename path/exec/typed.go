@@ -0,0 +1,315 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/types"
+)
+
+// QueryStrings is like [Query], but returns the selected items as strings.
+// Returns an [ErrType] error naming the index and type of the first item
+// that is not a string. Unlike [QueryText], it does not format other items
+// as strings. The options act the same as for [Query].
+func QueryStrings(ctx context.Context, path *ast.AST, value any, opt ...Option) ([]string, error) {
+	return queryAs[string](ctx, "QueryStrings", path, value, opt)
+}
+
+// QueryInts is like [Query], but returns the selected items as int64
+// values. Converts numbers with no fractional part, and returns an
+// [ErrType] error naming the index and type of the first item that is not
+// a number, or an [ErrNumeric] error for the first number that is not an
+// integer or is out of range. The options act the same as for [Query].
+func QueryInts(ctx context.Context, path *ast.AST, value any, opt ...Option) ([]int64, error) {
+	return queryAs[int64](ctx, "QueryInts", path, value, opt)
+}
+
+// QueryFloats is like [Query], but returns the selected items as float64
+// values. Returns an [ErrType] error naming the index and type of the first
+// item that is not a number. The options act the same as for [Query].
+func QueryFloats(ctx context.Context, path *ast.AST, value any, opt ...Option) ([]float64, error) {
+	return queryAs[float64](ctx, "QueryFloats", path, value, opt)
+}
+
+// QueryBools is like [Query], but returns the selected items as bools.
+// Returns an [ErrType] error naming the index and type of the first item
+// that is not a boolean. The options act the same as for [Query].
+func QueryBools(ctx context.Context, path *ast.AST, value any, opt ...Option) ([]bool, error) {
+	return queryAs[bool](ctx, "QueryBools", path, value, opt)
+}
+
+// QueryAs is like [Query], but converts the selected items to T, much as
+// [json.Unmarshal] would convert their JSON representations:
+//
+//   - Items of a type assignable to T, including any item when T is an
+//     interface type, are returned as is
+//   - Numbers convert to integer and floating point types, provided they
+//     are within the range of the type and, for integer types, have no
+//     fractional part
+//   - Strings and booleans convert to types with string and bool
+//     underlying types
+//   - Date and time values convert to [time.Time]
+//   - Other items convert to structs, maps, slices, arrays, and pointers by
+//     marshaling them to JSON and unmarshaling the JSON into T
+//   - JSON null converts to the zero value of pointers, maps, slices, and
+//     interfaces
+//
+// Returns an [ErrType] error naming the index and type of the first item
+// that cannot be converted to T, or an [ErrNumeric] error for a number out
+// of range of T or, for an integer type, not an integer. The options act
+// the same as for [Query].
+func QueryAs[T any](ctx context.Context, path *ast.AST, value any, opt ...Option) ([]T, error) {
+	return queryAs[T](ctx, "QueryAs", path, value, opt)
+}
+
+// queryAs implements [QueryAs] and its typed variants, the name of which
+// should be passed as fn.
+func queryAs[T any](ctx context.Context, fn string, path *ast.AST, value any, opt []Option) ([]T, error) {
+	exec, err := newExec(path, opt...)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.release()
+	if err := exec.checkPredicate(fn, false); err != nil {
+		return nil, err
+	}
+
+	vals, err := exec.queryAll(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]T, len(vals))
+	for i, val := range vals {
+		if err := convertResult(val, reflect.ValueOf(&res[i]).Elem()); err != nil {
+			var convErr *conversionError
+			if !errors.As(err, &convErr) {
+				return nil, err
+			}
+			msg := fmt.Sprintf(
+				"cannot convert result %d of type %v to %v",
+				i, resultTypeName(val), reflect.TypeFor[T](),
+			)
+			if convErr.reason != "" {
+				msg += ": " + convErr.reason
+			}
+			return nil, fmt.Errorf("%w: %v", convErr.category, msg)
+		}
+	}
+	return res, nil
+}
+
+// conversionError is the error convertResult returns for a value it cannot
+// convert. queryAs replaces it with an error that names the value and
+// wraps category.
+type conversionError struct {
+	// ErrType or ErrNumeric
+	category error
+	// why the conversion failed, if not because of the type of the value
+	reason string
+}
+
+// Error returns the reason for the error.
+func (e *conversionError) Error() string { return e.reason }
+
+// convertResult converts val, a result of a query, into dst, which must be
+// settable. Returns a *conversionError if it cannot.
+//
+//nolint:exhaustive // Remaining kinds unsupported
+func convertResult(val any, dst reflect.Value) error {
+	typ := dst.Type()
+	if val == nil {
+		switch typ.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice:
+			// Leave the zero value.
+			return nil
+		default:
+			return resultTypeError()
+		}
+	}
+
+	src := reflect.ValueOf(val)
+	if src.Type().AssignableTo(typ) {
+		dst.Set(src)
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		num, err := resultInt(src)
+		if err != nil {
+			return err
+		}
+		if dst.OverflowInt(num) {
+			return resultRangeError(val)
+		}
+		dst.SetInt(num)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		num, err := resultUint(src)
+		if err != nil {
+			return err
+		}
+		if dst.OverflowUint(num) {
+			return resultRangeError(val)
+		}
+		dst.SetUint(num)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		num, err := resultFloat(src)
+		if err != nil {
+			return err
+		}
+		if dst.OverflowFloat(num) {
+			return resultRangeError(val)
+		}
+		dst.SetFloat(num)
+		return nil
+	case reflect.String, reflect.Bool:
+		// Numbers are not strings, even json.Number.
+		if src.Kind() != typ.Kind() || src.Type() == jsonNumberType {
+			return resultTypeError()
+		}
+		dst.Set(src.Convert(typ))
+		return nil
+	case reflect.Struct:
+		if typ == timeType {
+			dt, ok := val.(types.DateTime)
+			if !ok {
+				return resultTypeError()
+			}
+			dst.Set(reflect.ValueOf(dt.GoTime()))
+			return nil
+		}
+		return convertResultJSON(val, dst)
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Pointer:
+		return convertResultJSON(val, dst)
+	default:
+		return resultTypeError()
+	}
+}
+
+// convertResultJSON converts val into dst by marshaling it to JSON and
+// unmarshaling the JSON into dst.
+func convertResultJSON(val any, dst reflect.Value) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return &conversionError{ErrType, err.Error()}
+	}
+	if err := json.Unmarshal(data, dst.Addr().Interface()); err != nil {
+		return &conversionError{ErrType, err.Error()}
+	}
+	return nil
+}
+
+// resultInt returns the integer value of src, which must be a number with
+// no fractional part within the range of int64.
+//
+//nolint:exhaustive // Other kinds handled below
+func resultInt(src reflect.Value) (int64, error) {
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return src.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if num := src.Uint(); num <= math.MaxInt64 {
+			return int64(num), nil
+		}
+		return 0, resultRangeError(src.Interface())
+	}
+
+	if src.Type() == jsonNumberType {
+		if num, err := strconv.ParseInt(src.String(), 10, 64); err == nil {
+			return num, nil
+		}
+	}
+
+	num, err := resultFloat(src)
+	if err != nil {
+		return 0, err
+	}
+	if num != math.Trunc(num) {
+		return 0, &conversionError{ErrNumeric, fmt.Sprintf("%v is not an integer", src.Interface())}
+	}
+	// float64(math.MaxInt64) rounds up to 2^63, itself out of range.
+	if num < math.MinInt64 || num >= math.MaxInt64 {
+		return 0, resultRangeError(src.Interface())
+	}
+	return int64(num), nil
+}
+
+// resultUint returns the unsigned integer value of src, which must be a
+// non-negative number with no fractional part within the range of uint64.
+//
+//nolint:exhaustive // Other kinds handled below
+func resultUint(src reflect.Value) (uint64, error) {
+	switch src.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return src.Uint(), nil
+	}
+
+	if src.Type() == jsonNumberType {
+		if num, err := strconv.ParseUint(src.String(), 10, 64); err == nil {
+			return num, nil
+		}
+	}
+
+	num, err := resultInt(src)
+	if err != nil {
+		return 0, err
+	}
+	if num < 0 {
+		return 0, resultRangeError(src.Interface())
+	}
+	return uint64(num), nil
+}
+
+// resultFloat returns the floating point value of src, which must be a
+// number.
+//
+//nolint:exhaustive // Remaining kinds not numbers
+func resultFloat(src reflect.Value) (float64, error) {
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(src.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(src.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return src.Float(), nil
+	case reflect.String:
+		if src.Type() == jsonNumberType {
+			num, err := strconv.ParseFloat(src.String(), 64)
+			if err != nil {
+				return 0, resultRangeError(src.Interface())
+			}
+			return num, nil
+		}
+	}
+	return 0, resultTypeError()
+}
+
+// resultTypeError returns the error for a value of a type that cannot
+// convert to the requested type, which queryAs names.
+func resultTypeError() error {
+	return &conversionError{category: ErrType}
+}
+
+// resultRangeError returns the error for val out of the range of the
+// requested type.
+func resultRangeError(val any) error {
+	return &conversionError{ErrNumeric, fmt.Sprintf("%v is out of range", val)}
+}
+
+// resultTypeName returns the name of the type of val for error messages:
+// "null" for nil and its Go type otherwise.
+func resultTypeName(val any) string {
+	if val == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%T", val)
+}
@@ -0,0 +1,377 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/ast"
+	"github.com/theory/sqljson/path/parser"
+	"github.com/theory/sqljson/path/types"
+)
+
+func TestQueryStrings(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		value any
+		exp   []string
+		err   string
+	}{
+		{
+			name:  "strings",
+			path:  `$[*]`,
+			value: []any{"a", "b", "c"},
+			exp:   []string{"a", "b", "c"},
+		},
+		{
+			name:  "none",
+			path:  `$[*]`,
+			value: []any{},
+			exp:   []string{},
+		},
+		{
+			name:  "method",
+			path:  `$[*].string()`,
+			value: []any{int64(1), true},
+			exp:   []string{"1", "true"},
+		},
+		{
+			name:  "number",
+			path:  `$[*]`,
+			value: []any{"a", int64(1)},
+			err:   "exec: cannot convert result 1 of type int64 to string",
+		},
+		{
+			name:  "json_number",
+			path:  `$[*]`,
+			value: []any{json.Number("1")},
+			err:   "exec: cannot convert result 0 of type json.Number to string",
+		},
+		{
+			name:  "null",
+			path:  `$[*]`,
+			value: []any{"a", "b", nil},
+			err:   "exec: cannot convert result 2 of type null to string",
+		},
+		{
+			name:  "exec_error",
+			path:  `strict $.a`,
+			value: map[string]any{},
+			err:   `exec: JSON object does not contain key "a"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			res, err := QueryStrings(ctx, path, tc.value)
+			if tc.err == "" {
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+			} else {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrExecution)
+				a.Nil(res)
+			}
+		})
+	}
+}
+
+func TestQueryInts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		value any
+		exp   []int64
+		err   string
+		cat   error
+	}{
+		{
+			name:  "int64",
+			value: []any{int64(1), int64(-2), int64(math.MaxInt64)},
+			exp:   []int64{1, -2, math.MaxInt64},
+		},
+		{
+			name:  "float64",
+			value: []any{float64(1), float64(-2), 1e10},
+			exp:   []int64{1, -2, 1e10},
+		},
+		{
+			name:  "json_number",
+			value: []any{json.Number("1"), json.Number("-2.0"), json.Number("1e3")},
+			exp:   []int64{1, -2, 1000},
+		},
+		{
+			name:  "fraction",
+			value: []any{int64(1), 1.5},
+			err:   "exec: cannot convert result 1 of type float64 to int64: 1.5 is not an integer",
+			cat:   ErrNumeric,
+		},
+		{
+			name:  "json_number_fraction",
+			value: []any{json.Number("2.25")},
+			err:   "exec: cannot convert result 0 of type json.Number to int64: 2.25 is not an integer",
+			cat:   ErrNumeric,
+		},
+		{
+			name:  "out_of_range",
+			value: []any{1e19},
+			err:   "exec: cannot convert result 0 of type float64 to int64: 1e+19 is out of range",
+			cat:   ErrNumeric,
+		},
+		{
+			name:  "json_number_out_of_range",
+			value: []any{json.Number("9223372036854775808")},
+			err:   "exec: cannot convert result 0 of type json.Number to int64: 9223372036854775808 is out of range",
+			cat:   ErrNumeric,
+		},
+		{
+			name:  "string",
+			value: []any{"1"},
+			err:   "exec: cannot convert result 0 of type string to int64",
+			cat:   ErrType,
+		},
+		{
+			name:  "bool",
+			value: []any{int64(1), int64(2), true},
+			err:   "exec: cannot convert result 2 of type bool to int64",
+			cat:   ErrType,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(`$[*]`)
+			r.NoError(err)
+			res, err := QueryInts(ctx, path, tc.value)
+			if tc.err == "" {
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+			} else {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, tc.cat)
+				a.Nil(res)
+			}
+		})
+	}
+}
+
+func TestQueryFloats(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		value any
+		exp   []float64
+		err   string
+	}{
+		{
+			name:  "numbers",
+			value: []any{float64(1.5), int64(-2), json.Number("1e3")},
+			exp:   []float64{1.5, -2, 1000},
+		},
+		{
+			name:  "string",
+			value: []any{float64(1.5), "x"},
+			err:   "exec: cannot convert result 1 of type string to float64",
+		},
+		{
+			name:  "object",
+			value: []any{map[string]any{}},
+			err:   "exec: cannot convert result 0 of type map[string]interface {} to float64",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := parser.Parse(`$[*]`)
+			r.NoError(err)
+			res, err := QueryFloats(ctx, path, tc.value)
+			if tc.err == "" {
+				r.NoError(err)
+				a.Equal(tc.exp, res)
+			} else {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrType)
+				a.Nil(res)
+			}
+		})
+	}
+}
+
+func TestQueryBools(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	path, err := parser.Parse(`$[*]`)
+	r.NoError(err)
+	res, err := QueryBools(ctx, path, []any{true, false})
+	r.NoError(err)
+	a.Equal([]bool{true, false}, res)
+
+	res, err = QueryBools(ctx, path, []any{true, "true"})
+	r.EqualError(err, "exec: cannot convert result 1 of type string to bool")
+	r.ErrorIs(err, ErrType)
+	a.Nil(res)
+
+	// Predicate check expressions return a boolean, or null if unknown.
+	path, err = parser.Parse(`$[*] > 2`)
+	r.NoError(err)
+	res, err = QueryBools(ctx, path, []any{int64(1), int64(5)})
+	r.NoError(err)
+	a.Equal([]bool{true}, res)
+
+	res, err = QueryBools(ctx, path, []any{"x"})
+	r.EqualError(err, "exec: cannot convert result 0 of type null to bool")
+	r.ErrorIs(err, ErrType)
+	a.Nil(res)
+}
+
+func TestQueryAs(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	type item struct {
+		Name  string   `json:"name"`
+		Price float64  `json:"price"`
+		Tags  []string `json:"tags"`
+	}
+	type label struct {
+		Price string `json:"price"`
+	}
+	type color string
+
+	value := map[string]any{
+		"items": []any{
+			map[string]any{"name": "pen", "price": json.Number("1.5"), "tags": []any{"office"}},
+			map[string]any{"name": "lamp", "price": float64(40)},
+		},
+		"colors": []any{"red", "blue"},
+		"counts": []any{int64(1), float64(200), json.Number("3")},
+		"when":   "2024-06-01 12:30:00",
+	}
+	query := func(path string) *ast.AST {
+		tree, err := parser.Parse(path)
+		r.NoError(err)
+		return tree
+	}
+
+	// Objects re-marshaled into structs.
+	items, err := QueryAs[item](ctx, query(`$.items[*]`), value)
+	r.NoError(err)
+	a.Equal([]item{
+		{Name: "pen", Price: 1.5, Tags: []string{"office"}},
+		{Name: "lamp", Price: 40},
+	}, items)
+
+	// And into pointers to structs.
+	ptrs, err := QueryAs[*item](ctx, query(`$.items[*] ? (@.price > 10)`), value)
+	r.NoError(err)
+	a.Equal([]*item{{Name: "lamp", Price: 40}}, ptrs)
+
+	// And into maps.
+	maps, err := QueryAs[map[string]any](ctx, query(`$.items[1]`), value)
+	r.NoError(err)
+	a.Equal([]map[string]any{{"name": "lamp", "price": float64(40)}}, maps)
+
+	// A struct from the wrong type of item.
+	items, err = QueryAs[item](ctx, query(`$.colors[*]`), value)
+	r.EqualError(err, "exec: cannot convert result 0 of type string to exec.item: "+
+		"json: cannot unmarshal string into Go value of type exec.item")
+	r.ErrorIs(err, ErrType)
+	a.Nil(items)
+
+	// And with a member of the wrong type.
+	labels, err := QueryAs[label](ctx, query(`$.items[*]`), value)
+	r.EqualError(err, "exec: cannot convert result 0 of type map[string]interface {} to exec.label: "+
+		"json: cannot unmarshal number into Go struct field label.price of type string")
+	r.ErrorIs(err, ErrType)
+	a.Nil(labels)
+
+	// Named types.
+	colors, err := QueryAs[color](ctx, query(`$.colors[*]`), value)
+	r.NoError(err)
+	a.Equal([]color{"red", "blue"}, colors)
+
+	// Smaller numeric types.
+	uint8s, err := QueryAs[uint8](ctx, query(`$.counts[*]`), value)
+	r.NoError(err)
+	a.Equal([]uint8{1, 200, 3}, uint8s)
+
+	int8s, err := QueryAs[int8](ctx, query(`$.counts[*]`), value)
+	r.EqualError(err, "exec: cannot convert result 1 of type float64 to int8: 200 is out of range")
+	r.ErrorIs(err, ErrNumeric)
+	a.Nil(int8s)
+
+	uints, err := QueryAs[uint](ctx, query(`$.counts[0] - 2`), value)
+	r.EqualError(err, "exec: cannot convert result 0 of type int64 to uint: -1 is out of range")
+	r.ErrorIs(err, ErrNumeric)
+	a.Nil(uints)
+
+	float32s, err := QueryAs[float32](ctx, query(`$.counts[*]`), value)
+	r.NoError(err)
+	a.Equal([]float32{1, 200, 3}, float32s)
+
+	// Date and time values.
+	times, err := QueryAs[time.Time](ctx, query(`$.when.timestamp()`), value)
+	r.NoError(err)
+	r.Len(times, 1)
+	a.True(times[0].Equal(time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)))
+
+	times, err = QueryAs[time.Time](ctx, query(`$.when`), value)
+	r.EqualError(err, "exec: cannot convert result 0 of type string to time.Time")
+	r.ErrorIs(err, ErrType)
+	a.Nil(times)
+
+	dates, err := QueryAs[*types.Date](ctx, query(`$.when.date()`), value)
+	r.NoError(err)
+	a.Len(dates, 1)
+	a.Equal("2024-06-01", dates[0].String())
+
+	// Null.
+	anys, err := QueryAs[any](ctx, query(`$.items[*].tags`), value)
+	r.NoError(err)
+	a.Equal([]any{[]any{"office"}}, anys)
+
+	slices, err := QueryAs[[]string](ctx, query(`$.items[*].tags`), value)
+	r.NoError(err)
+	a.Equal([][]string{{"office"}}, slices)
+
+	ptrs, err = QueryAs[*item](ctx, query(`null`), value)
+	r.NoError(err)
+	a.Equal([]*item{nil}, ptrs)
+
+	items, err = QueryAs[item](ctx, query(`null`), value)
+	r.EqualError(err, "exec: cannot convert result 0 of type null to exec.item")
+	r.ErrorIs(err, ErrType)
+	a.Nil(items)
+
+	// Predicate check.
+	_, err = QueryAs[bool](ctx, query(`$.colors[*] == "red"`), value, WithPredicateCheck())
+	r.EqualError(
+		err,
+		`exec: QueryAs expects a SQL standard path expression but "($.\"colors\"[*] == \"red\")" is a predicate check expression`,
+	)
+}
@@ -0,0 +1,58 @@
+package exec
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// WithLenientUTF8 makes string operations replace invalid UTF-8 sequences
+// in strings with the Unicode replacement character, U+FFFD, rather than
+// raise an error.
+//
+// By default, like_regex, starts with, string comparisons, .string(), and
+// the keys emitted by .keyvalue() return an [ErrType] error for strings
+// that contain invalid UTF-8, which can appear in documents and variables
+// built in Go rather than decoded from JSON. PostgreSQL rejects such
+// strings when it parses JSON, so never evaluates them; this option
+// diverges from PostgreSQL. As with other type errors, [WithSilent]
+// suppresses the error, and filters treat it as unknown, so that
+// $[*] ? (@ == "x") skips strings with invalid UTF-8.
+func WithLenientUTF8() Option { return func(e *Executor) { e.lenientUTF8 = true } }
+
+// validUTF8 returns str if it's valid UTF-8. Otherwise it returns str with
+// invalid sequences replaced by U+FFFD when exec.lenientUTF8 is true, and a
+// suppressible error when it is false.
+func (exec *Executor) validUTF8(str string) (string, error) {
+	if utf8.ValidString(str) {
+		return str, nil
+	}
+	if exec.lenientUTF8 {
+		return strings.ToValidUTF8(str, string(utf8.RuneError)), nil
+	}
+	return "", fmt.Errorf("%w: invalid UTF-8 in string value", errVerboseType)
+}
+
+// validUTF8Keys returns keys if all are valid UTF-8. Otherwise it returns a
+// copy of keys with invalid sequences replaced by U+FFFD when
+// exec.lenientUTF8 is true, and a suppressible error when it is false.
+func (exec *Executor) validUTF8Keys(keys []string) ([]string, error) {
+	var names []string
+	for i, key := range keys {
+		name, err := exec.validUTF8(key)
+		if err != nil {
+			return nil, err
+		}
+		if name != key && names == nil {
+			names = make([]string, len(keys))
+			copy(names, keys)
+		}
+		if names != nil {
+			names[i] = name
+		}
+	}
+	if names == nil {
+		return keys, nil
+	}
+	return names, nil
+}
@@ -0,0 +1,157 @@
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/parser"
+)
+
+func TestInvalidUTF8(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	const errMsg = "exec: invalid UTF-8 in string value"
+
+	for _, tc := range []struct {
+		name    string
+		path    string
+		value   any
+		vars    Vars
+		err     bool  // error unless silent or lenient; otherwise, silent result
+		silent  []any // result with WithSilent
+		lenient []any // result with WithLenientUTF8
+	}{
+		{
+			name:    "like_regex",
+			path:    `$[*] ? (@ like_regex "^a.$")`,
+			value:   []any{"a\xff", "ab"},
+			silent:  []any{"ab"},
+			lenient: []any{"a\xff", "ab"},
+		},
+		{
+			name:    "like_regex_predicate",
+			path:    `$ like_regex "^a"`,
+			value:   "a\xff",
+			err:     true,
+			silent:  []any{nil},
+			lenient: []any{true},
+		},
+		{
+			name:    "starts_with",
+			path:    `$[*] ? (@ starts with "a")`,
+			value:   []any{"a\xff", "ab"},
+			silent:  []any{"ab"},
+			lenient: []any{"a\xff", "ab"},
+		},
+		{
+			name:    "starts_with_invalid_prefix",
+			path:    `$ starts with $p`,
+			value:   "�a",
+			vars:    Vars{"p": "\xfe"},
+			err:     true,
+			silent:  []any{nil},
+			lenient: []any{true},
+		},
+		{
+			name:    "equal",
+			path:    `$[*] ? (@ == "a")`,
+			value:   []any{"\xff", "a"},
+			silent:  []any{"a"},
+			lenient: []any{"a"},
+		},
+		{
+			name:    "equal_replaced",
+			path:    `$ == $v`,
+			value:   "a\xff",
+			vars:    Vars{"v": "a\xfe"},
+			err:     true,
+			silent:  []any{nil},
+			lenient: []any{true},
+		},
+		{
+			name:    "less_than_variable",
+			path:    `$ < $v`,
+			value:   "a",
+			vars:    Vars{"v": "b\xff"},
+			err:     true,
+			silent:  []any{nil},
+			lenient: []any{true},
+		},
+		{
+			name:    "not_equal_non_string",
+			path:    `$ != 1`,
+			value:   "\xff",
+			silent:  []any{nil},
+			lenient: []any{nil},
+		},
+		{
+			name:    "string_method",
+			path:    `$.string()`,
+			value:   "a\xffb",
+			err:     true,
+			silent:  []any{},
+			lenient: []any{"a�b"},
+		},
+		{
+			name:    "keyvalue",
+			path:    `$.keyvalue()`,
+			value:   map[string]any{"a\xff": int64(1)},
+			err:     true,
+			silent:  []any{},
+			lenient: []any{map[string]any{"key": "a�", "value": int64(1), "id": int64(0)}},
+		},
+		{
+			name:    "keyvalue_key",
+			path:    `$.keyvalue().key`,
+			value:   map[string]any{"b": int64(2), "a\xff\xfe": int64(1)},
+			err:     true,
+			silent:  []any{},
+			lenient: []any{"a�", "b"},
+		},
+		{
+			name:    "keyvalue_value",
+			path:    `$.keyvalue().value`,
+			value:   map[string]any{"b": int64(2), "a\xff": int64(1)},
+			err:     true,
+			silent:  []any{},
+			lenient: []any{int64(1), int64(2)},
+		},
+		{
+			name:    "valid",
+			path:    `$.keyvalue() ? (@.key starts with "é" && @.value like_regex "^ü").key.string()`,
+			value:   map[string]any{"é": "über", "x": "y"},
+			silent:  []any{"é"},
+			lenient: []any{"é"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			path, err := parser.Parse(tc.path)
+			r.NoError(err)
+			opt := []Option{WithVars(tc.vars)}
+
+			res, err := Query(ctx, path, tc.value, opt...)
+			if tc.err {
+				r.EqualError(err, errMsg)
+				r.ErrorIs(err, ErrType)
+				a.True(IsSuppressible(err))
+				a.Nil(res)
+			} else {
+				r.NoError(err)
+				a.Equal(tc.silent, res)
+			}
+
+			res, err = Query(ctx, path, tc.value, append(opt, WithSilent())...)
+			r.NoError(err)
+			a.Equal(tc.silent, res)
+
+			res, err = Query(ctx, path, tc.value, append(opt, WithLenientUTF8())...)
+			r.NoError(err)
+			a.Equal(tc.lenient, res)
+		})
+	}
+}
@@ -32,25 +32,26 @@ func getNodeInt32(node ast.Node, meth any, field string) (int, error) {
 	default:
 		return 0, fmt.Errorf(
 			"%w: invalid jsonpath item type for %v %v",
-			ErrExecution, meth, field,
+			ErrType, meth, field,
 		)
 	}
 
 	if num > math.MaxInt32 || num < math.MinInt32 {
 		return 0, fmt.Errorf(
 			"%w: %v of jsonpath item method %v is out of integer range",
-			ErrVerbose, field, meth,
+			errVerboseNumeric, field, meth,
 		)
 	}
 
 	return int(num), nil
 }
 
-// getJSONInt32 casts val to int32 and returns it. If val is a float, its
-// value will be truncated, not rounded. The op param is used in error
-// messages.
+// getJSONInt32 casts val to int32 and returns it. If val is a float or
+// [json.Number], its value will be truncated toward zero, not rounded, as in
+// PostgreSQL. The op param is used in error messages.
 func getJSONInt32(val any, op string) (int, error) {
 	var num int64
+	inRange := true
 	switch val := val.(type) {
 	case int64:
 		num = val
@@ -58,41 +59,64 @@ func getJSONInt32(val any, op string) (int, error) {
 		if math.IsInf(val, 0) || math.IsNaN(val) {
 			return 0, fmt.Errorf(
 				"%w: NaN or Infinity is not allowed for jsonpath %v",
-				ErrVerbose, op,
+				errVerboseNumeric, op,
 			)
 		}
-		num = int64(val)
+		// Check the range before conversion, which is undefined for values
+		// outside the range of int64.
+		trunc := math.Trunc(val)
+		inRange = trunc <= math.MaxInt32 && trunc >= math.MinInt32
+		num = int64(trunc)
 	case json.Number:
-		if integer, err := val.Int64(); err == nil {
-			num = integer
-		} else if float, err := val.Float64(); err == nil {
-			if math.IsInf(float, 0) || math.IsNaN(float) {
+		dec, ok := parseDecimal(string(val))
+		if !ok {
+			if float, err := val.Float64(); err == nil && (math.IsInf(float, 0) || math.IsNaN(float)) {
 				return 0, fmt.Errorf(
 					"%w: NaN or Infinity is not allowed for jsonpath %v",
-					ErrVerbose, op,
+					errVerboseNumeric, op,
 				)
 			}
-			num = int64(float)
-		} else {
 			// json.Number should never be invalid.
 			return 0, fmt.Errorf(
 				"%w: jsonpath %v is not a single numeric value",
 				ErrInvalid, op,
 			)
 		}
+		num, inRange = dec.trunc().int64()
 	default:
 		return 0, fmt.Errorf(
 			"%w: jsonpath %v is not a single numeric value",
-			ErrVerbose, op,
+			errVerboseType, op,
 		)
 	}
 
-	if num > math.MaxInt32 || num < math.MinInt32 {
+	if !inRange || num > math.MaxInt32 || num < math.MinInt32 {
 		return 0, fmt.Errorf(
 			"%w: jsonpath %v is out of integer range",
-			ErrVerbose, op,
+			errVerboseNumeric, op,
 		)
 	}
 
 	return int(num), nil
 }
+
+// unsignedZero returns f, or positive zero if f is negative zero. PostgreSQL
+// numeric values have no negative zero, so -0.0 and 0.0 are the same value
+// and always print as 0.
+func unsignedZero(f float64) float64 {
+	if f == 0 {
+		return 0
+	}
+	return f
+}
+
+// unsignedZeroNumber returns num without its minus sign if it represents
+// zero, so that json.Number("-0.0") becomes json.Number("0.0").
+func unsignedZeroNumber(num json.Number) json.Number {
+	if len(num) > 1 && num[0] == '-' {
+		if dec, ok := parseDecimal(string(num)); ok && dec.sign() == 0 {
+			return num[1:]
+		}
+	}
+	return num
+}
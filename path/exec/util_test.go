@@ -15,7 +15,7 @@ func TestCastJSONNumber(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
 
-	doubleInt := func(i int64) int64 { return i * 2 }
+	doubleInt := func(i int64) any { return i * 2 }
 	doubleFloat := func(i float64) float64 { return i * 2 }
 
 	for _, tc := range []struct {
@@ -215,6 +215,59 @@ func TestGetJSONInt32(t *testing.T) {
 			err:   `exec: NaN or Infinity is not allowed for jsonpath xyz`,
 			isErr: ErrVerbose,
 		},
+		{
+			name:  "json_huge",
+			val:   json.Number("1e400"),
+			op:    "xyz",
+			err:   `exec: jsonpath xyz is out of integer range`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "json_beyond_int64",
+			val:   json.Number("-99999999999999999999.5"),
+			op:    "xyz",
+			err:   `exec: jsonpath xyz is out of integer range`,
+			isErr: ErrVerbose,
+		},
+		{
+			name: "json_neg_trunc",
+			val:  json.Number("-1.5"),
+			exp:  -1,
+		},
+		{
+			name: "json_exponent",
+			val:  json.Number("2.5e1"),
+			exp:  25,
+		},
+		{
+			name: "json_max_int32_trunc",
+			val:  json.Number("2147483647.9"),
+			exp:  math.MaxInt32,
+		},
+		{
+			name:  "float_huge",
+			val:   float64(1e20),
+			op:    "xyz",
+			err:   `exec: jsonpath xyz is out of integer range`,
+			isErr: ErrVerbose,
+		},
+		{
+			name:  "float_huge_neg",
+			val:   float64(-1e300),
+			op:    "xyz",
+			err:   `exec: jsonpath xyz is out of integer range`,
+			isErr: ErrVerbose,
+		},
+		{
+			name: "float_neg_trunc",
+			val:  float64(-0.5),
+			exp:  0,
+		},
+		{
+			name: "float_min_int32_trunc",
+			val:  float64(math.MinInt32) - 0.9,
+			exp:  math.MinInt32,
+		},
 		{
 			name:  "string",
 			val:   "hi",
@@ -250,3 +303,31 @@ func TestGetJSONInt32(t *testing.T) {
 		})
 	}
 }
+
+func TestUnsignedZero(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	negZero := math.Copysign(0, -1)
+	a.True(math.Signbit(negZero))
+	a.False(math.Signbit(unsignedZero(negZero)))
+	a.False(math.Signbit(unsignedZero(0)))
+	a.InDelta(-1.5, unsignedZero(-1.5), 0)
+	a.InDelta(1.5, unsignedZero(1.5), 0)
+
+	for num, exp := range map[json.Number]json.Number{
+		"-0":     "0",
+		"-0.0":   "0.0",
+		"-0e10":  "0e10",
+		"-00.00": "00.00",
+		"0":      "0",
+		"0.0":    "0.0",
+		"-":      "-",
+		"-1":     "-1",
+		"-0.01":  "-0.01",
+		"-1e-10": "-1e-10",
+		"":       "",
+	} {
+		a.Equal(exp, unsignedZeroNumber(num), num)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build go1.23
+
+package path
+
+import (
+	"context"
+	"iter"
+
+	"github.com/theory/sqljson/path/exec"
+)
+
+// Iter is like [Query], but returns an iterator that yields each JSON item
+// selected by path from json as soon as execution selects it. Breaking out
+// of a range loop over the iterator stops execution. See [exec.Iter] for
+// details, and the Options section for details on the optional
+// [exec.WithVars], [exec.WithTZ], and [exec.WithSilent] options.
+func (path *Path) Iter(ctx context.Context, json any, opt ...exec.Option) iter.Seq2[any, error] {
+	return exec.Iter(ctx, path.AST, json, opt...)
+}
+
+// IterKeyValues is like [Path.Iter], but yields the key and value of each
+// member of the objects selected by path from json, as if path ended in
+// .keyvalue(). Call the returned function after iterating to check for an
+// error. See [exec.IterKeyValues] for details.
+func (path *Path) IterKeyValues(ctx context.Context, json any, opt ...exec.Option) (iter.Seq2[string, any], func() error) {
+	return exec.IterKeyValues(ctx, path.AST, json, opt...)
+}
+
+// IterIndexed is like [Path.Iter], but yields the index and value of each
+// element of the arrays selected by path from json, as if path ended in
+// [*]. Call the returned function after iterating to check for an error.
+// See [exec.IterIndexed] for details.
+func (path *Path) IterIndexed(ctx context.Context, json any, opt ...exec.Option) (iter.Seq2[int, any], func() error) {
+	return exec.IterIndexed(ctx, path.AST, json, opt...)
+}
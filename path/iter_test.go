@@ -0,0 +1,72 @@
+//go:build go1.23
+
+package path
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theory/sqljson/path/exec"
+)
+
+func TestIter(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	path := MustParse("$.a[*]")
+	json := map[string]any{"a": []any{
+		map[string]any{"y": int64(1), "x": "hi"},
+		[]any{"a", "b"},
+	}}
+
+	vals := []any{}
+	for val, err := range path.Iter(ctx, json) {
+		r.NoError(err)
+		vals = append(vals, val)
+	}
+	a.Equal([]any{json["a"].([]any)[0], []any{"a", "b"}}, vals)
+
+	// Stop after the first.
+	vals = []any{}
+	for val := range path.Iter(ctx, json) {
+		vals = append(vals, val)
+		break
+	}
+	a.Equal([]any{json["a"].([]any)[0]}, vals)
+
+	// Key/value pairs.
+	keys := []string{}
+	vals = []any{}
+	seq, errFn := MustParse("$.a[0]").IterKeyValues(ctx, json)
+	for k, v := range seq {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	}
+	r.NoError(errFn())
+	a.Equal([]string{"x", "y"}, keys)
+	a.Equal([]any{"hi", int64(1)}, vals)
+
+	// Indexed elements.
+	idxs := []int{}
+	vals = []any{}
+	idxSeq, errFn := MustParse("strict $.a[1]").IterIndexed(ctx, json)
+	for i, v := range idxSeq {
+		idxs = append(idxs, i)
+		vals = append(vals, v)
+	}
+	r.NoError(errFn())
+	a.Equal([]int{0, 1}, idxs)
+	a.Equal([]any{"a", "b"}, vals)
+
+	// Errors.
+	seq, errFn = MustParse("strict $.a").IterKeyValues(ctx, json)
+	for range seq {
+		a.Fail("should yield nothing")
+	}
+	r.EqualError(errFn(), "exec: jsonpath item method .keyvalue() can only be applied to an object")
+	r.ErrorIs(errFn(), exec.ErrExecution)
+}
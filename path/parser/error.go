@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Contexts in which a SyntaxError may occur.
+const (
+	ContextString     = "string literal"
+	ContextVariable   = "variable name"
+	ContextRegexFlags = "like_regex flags"
+	ContextRegex      = "like_regex pattern"
+)
+
+// SyntaxError describes an error parsing a path. Use [errors.As] to extract
+// it from errors returned by [Parse]. It always wraps [ErrParse].
+type SyntaxError struct {
+	// Msg describes the error, without position information, e.g.,
+	// "syntax error".
+	Msg string
+
+	// Line is the line number of the error, starting at 1.
+	Line int
+
+	// Column is the column number of the error in characters, starting at
+	// 1.
+	Column int
+
+	// Offset is the byte offset of the error, starting at 0.
+	Offset int
+
+	// Token is the source text of the offending token, if any.
+	Token string
+
+	// Expected lists the constructs the grammar would have accepted in place
+	// of Token, e.g., "integer" or `")"`, when known.
+	Expected []string
+
+	// Context describes the construct containing the error, if it's one of
+	// ContextString, ContextVariable, ContextRegexFlags, or ContextRegex.
+	Context string
+}
+
+// Error returns the error message, including the position of the error.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v: %v at %v:%v", ErrParse, e.Msg, e.Line, e.Column)
+}
+
+// Unwrap returns [ErrParse].
+func (e *SyntaxError) Unwrap() error {
+	return ErrParse
+}
+
+// newSyntaxError creates a SyntaxError for msg at the current position of
+// l. If msg is a verbose goyacc syntax error message, it parses the
+// expected tokens into Expected and trims them from Msg.
+func (l *lexer) newSyntaxError(msg string) *SyntaxError {
+	pos := l.pos()
+	err := &SyntaxError{
+		Msg:     msg,
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Offset:  pos.Offset,
+		Context: l.context,
+	}
+
+	if l.tokPos >= 0 && l.tokEnd > l.tokPos {
+		err.Token = string(l.srcBuf[l.tokPos:l.tokEnd])
+	}
+
+	const prefix = "syntax error"
+	if rest, ok := strings.CutPrefix(msg, prefix+": unexpected "); ok {
+		err.Msg = prefix
+		if _, list, ok := strings.Cut(rest, ", expecting "); ok {
+			for _, name := range strings.Split(list, " or ") {
+				err.Expected = append(err.Expected, describeToken(name))
+			}
+		}
+	}
+
+	return err
+}
+
+// tokenDescriptions maps token names that are not keywords to descriptions
+// for SyntaxError.Expected.
+//
+//nolint:gochecknoglobals
+var tokenDescriptions = map[string]string{
+	"$end":           "end of input",
+	"IDENT_P":        "identifier",
+	"STRING_P":       "string",
+	"NUMERIC_P":      "number",
+	"INT_P":          "integer",
+	"VARIABLE_P":     "variable",
+	"METHOD_P":       "method",
+	"OR_P":           `"||"`,
+	"AND_P":          `"&&"`,
+	"NOT_P":          `"!"`,
+	"LESS_P":         `"<"`,
+	"LESSEQUAL_P":    `"<="`,
+	"EQUAL_P":        `"=="`,
+	"NOTEQUAL_P":     `"!="`,
+	"GREATEREQUAL_P": `">="`,
+	"GREATER_P":      `">"`,
+	"ANY_P":          `"**"`,
+	"STRINGFUNC_P":   `"string"`,
+}
+
+// describeToken converts the goyacc token name to a description of the
+// token. Returns descriptions from tokenDescriptions, double-quoted
+// characters for character tokens, and double-quoted lowercase keywords for
+// all other tokens.
+func describeToken(name string) string {
+	if desc, ok := tokenDescriptions[name]; ok {
+		return desc
+	}
+	if len(name) == 3 && name[0] == '\'' && name[2] == '\'' {
+		return `"` + name[1:2] + `"`
+	}
+	return `"` + strings.ToLower(strings.TrimSuffix(name, "_P")) + `"`
+}
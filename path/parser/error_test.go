@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyntaxError(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	err := &SyntaxError{Msg: "syntax error", Line: 2, Column: 7, Offset: 12}
+	a.EqualError(err, "parser: syntax error at 2:7")
+	a.ErrorIs(err, ErrParse)
+	a.Equal(ErrParse, err.Unwrap())
+}
+
+func TestDescribeToken(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		exp  string
+	}{
+		{"$end", "end of input"},
+		{"INT_P", "integer"},
+		{"IDENT_P", "identifier"},
+		{"OR_P", `"||"`},
+		{"STRINGFUNC_P", `"string"`},
+		{"LIKE_REGEX_P", `"like_regex"`},
+		{"TIMESTAMP_TZ_P", `"timestamp_tz"`},
+		{"')'", `")"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.exp, describeToken(tc.name))
+		})
+	}
+}
@@ -89,7 +89,8 @@ const TIME_P = 57390
 const TIME_TZ_P = 57391
 const TIMESTAMP_P = 57392
 const TIMESTAMP_TZ_P = 57393
-const UMINUS = 57394
+const METHOD_P = 57394
+const UMINUS = 57395
 
 var pathToknames = [...]string{
 	"$end",
@@ -143,6 +144,7 @@ var pathToknames = [...]string{
 	"TIME_TZ_P",
 	"TIMESTAMP_P",
 	"TIMESTAMP_TZ_P",
+	"METHOD_P",
 	"'+'",
 	"'-'",
 	"'*'",
@@ -168,109 +170,112 @@ const pathEofCode = 1
 const pathErrCode = 2
 const pathInitialStackSize = 16
 
-//line grammar.y:331
+//line grammar.y:334
 
 var pathExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
 	-1, 80,
-	58, 123,
+	59, 124,
 	-2, 99,
 	-1, 81,
-	58, 124,
+	59, 125,
 	-2, 100,
 	-1, 82,
-	58, 125,
+	59, 126,
 	-2, 101,
 	-1, 83,
-	58, 126,
+	59, 127,
 	-2, 102,
 	-1, 84,
-	58, 127,
+	59, 128,
 	-2, 103,
 	-1, 85,
-	58, 128,
+	59, 129,
 	-2, 104,
 	-1, 86,
-	58, 129,
+	59, 130,
 	-2, 106,
 	-1, 87,
-	58, 130,
+	59, 131,
 	-2, 112,
 	-1, 88,
-	58, 131,
+	59, 132,
 	-2, 113,
 	-1, 89,
-	58, 132,
+	59, 133,
 	-2, 116,
 	-1, 90,
-	58, 133,
+	59, 134,
 	-2, 117,
 	-1, 91,
-	58, 134,
+	59, 135,
 	-2, 118,
+	-1, 92,
+	59, 136,
+	-2, 123,
 }
 
 const pathPrivate = 57344
 
-const pathLast = 251
+const pathLast = 252
 
 var pathAct = [...]uint8{
-	160, 146, 65, 111, 154, 6, 137, 180, 133, 7,
-	134, 132, 49, 50, 52, 43, 47, 130, 168, 48,
-	44, 46, 177, 175, 30, 31, 32, 33, 34, 174,
-	56, 141, 173, 59, 60, 61, 62, 63, 42, 41,
-	172, 171, 167, 37, 39, 35, 36, 40, 38, 150,
-	112, 64, 66, 28, 49, 29, 143, 129, 117, 128,
-	127, 115, 126, 125, 116, 94, 95, 96, 97, 98,
-	99, 100, 92, 93, 42, 41, 30, 31, 32, 33,
-	34, 163, 121, 114, 176, 124, 79, 101, 102, 103,
-	104, 105, 106, 107, 80, 81, 82, 83, 84, 85,
+	161, 147, 65, 112, 155, 6, 138, 181, 134, 7,
+	135, 178, 49, 50, 52, 43, 47, 131, 133, 48,
+	44, 46, 30, 31, 32, 33, 34, 169, 176, 142,
+	56, 42, 41, 59, 60, 61, 62, 63, 42, 41,
+	175, 42, 41, 37, 39, 35, 36, 40, 38, 174,
+	113, 64, 66, 28, 49, 29, 173, 172, 118, 168,
+	151, 116, 144, 130, 117, 95, 96, 97, 98, 99,
+	100, 101, 93, 94, 177, 164, 129, 30, 31, 32,
+	33, 34, 141, 122, 115, 140, 79, 102, 103, 104,
+	105, 106, 107, 108, 80, 81, 82, 83, 84, 85,
 	86, 73, 87, 88, 72, 71, 89, 90, 91, 74,
-	75, 76, 77, 15, 123, 68, 147, 140, 131, 57,
-	122, 138, 32, 33, 34, 136, 41, 108, 42, 41,
-	42, 41, 157, 158, 159, 55, 112, 164, 165, 21,
-	22, 23, 3, 4, 15, 161, 20, 24, 25, 26,
-	119, 170, 13, 120, 148, 149, 21, 22, 23, 162,
-	169, 54, 19, 20, 24, 25, 26, 21, 22, 23,
-	178, 139, 156, 113, 20, 24, 25, 26, 179, 19,
-	47, 42, 41, 153, 44, 46, 10, 11, 135, 166,
-	19, 142, 9, 12, 17, 18, 37, 39, 35, 36,
-	40, 38, 58, 10, 11, 109, 28, 53, 29, 51,
-	78, 17, 18, 2, 10, 11, 70, 27, 110, 144,
-	51, 145, 17, 18, 30, 31, 32, 33, 34, 30,
-	31, 32, 33, 34, 8, 155, 30, 31, 32, 33,
-	34, 151, 152, 5, 118, 67, 69, 45, 14, 16,
-	1,
+	75, 76, 77, 92, 128, 127, 68, 42, 41, 132,
+	57, 126, 139, 15, 125, 137, 30, 31, 32, 33,
+	34, 124, 162, 158, 159, 160, 123, 113, 165, 166,
+	21, 22, 23, 109, 55, 15, 163, 20, 24, 25,
+	26, 3, 4, 13, 32, 33, 34, 21, 22, 23,
+	41, 114, 171, 19, 20, 24, 25, 26, 21, 22,
+	23, 179, 54, 42, 41, 20, 24, 25, 26, 180,
+	19, 47, 170, 157, 154, 44, 46, 148, 10, 11,
+	136, 19, 120, 143, 9, 121, 17, 18, 37, 39,
+	35, 36, 40, 38, 12, 10, 11, 110, 28, 58,
+	29, 51, 167, 17, 18, 78, 10, 11, 53, 2,
+	70, 27, 51, 111, 17, 18, 149, 150, 145, 146,
+	8, 156, 30, 31, 32, 33, 34, 152, 153, 30,
+	31, 32, 33, 34, 5, 119, 67, 69, 45, 14,
+	16, 1,
 }
 
 var pathPact = [...]int16{
-	116, -1000, 134, -1000, -1000, -1000, 177, 165, -47, 134,
-	162, 162, -1000, 103, -1000, 77, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 162, 89, 190,
-	162, 162, 162, 162, 162, -1000, -1000, -1000, -1000, -1000,
-	-1000, 134, 134, -1000, 61, -1000, 69, 151, 114, 24,
-	-1000, 134, -1000, -1000, 134, 162, 172, 138, 50, 68,
-	68, -1000, -1000, -1000, -1000, 177, 109, -1000, -1000, -1000,
-	62, 56, 27, 5, 4, 2, 1, -1, -1000, -48,
+	125, -1000, 135, -1000, -1000, -1000, 179, 157, -48, 135,
+	163, 163, -1000, 113, -1000, 85, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 163, 90, 197,
+	163, 163, 163, 163, 163, -1000, -1000, -1000, -1000, -1000,
+	-1000, 135, 135, -1000, 61, -1000, 84, 152, 101, 24,
+	-1000, 135, -1000, -1000, 135, 163, 73, 180, 51, 99,
+	99, -1000, -1000, -1000, -1000, 179, 143, -1000, -1000, -1000,
+	77, 72, 65, 62, 56, 55, 17, 4, -1000, -49,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 134, -53,
-	-54, -1000, 184, 117, -47, 112, 58, -28, -1000, -1000,
-	-1000, 179, -3, 102, -10, 171, 158, 158, 158, 158,
-	131, 22, -1000, 162, -1000, 162, 180, -1000, -1000, -47,
-	-1000, -1000, -1000, -1000, -17, -44, -1000, -1000, 146, 137,
-	-1000, -18, -1000, -1000, -19, -1000, -1000, -27, -30, -36,
-	18, -1000, -1000, -1000, -1000, 172, -1000, -1000, 102, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 131, -1000, -59,
-	-1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 135,
+	-47, -55, -1000, 186, 117, -48, 25, 22, -31, -1000,
+	-1000, -1000, 181, 2, 173, 0, 172, 169, 169, 169,
+	169, 118, 15, -1000, 163, -1000, 163, 203, -1000, -1000,
+	-48, -1000, -1000, -1000, -1000, -1, -36, -1000, -1000, 168,
+	148, -1000, -3, -1000, -1000, -4, -1000, -1000, -11, -20,
+	-32, 7, -1000, -1000, -1000, -1000, 73, -1000, -1000, 173,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 118, -1000,
+	-60, -1000,
 }
 
 var pathPgo = [...]uint8{
-	0, 250, 249, 248, 2, 247, 246, 6, 245, 9,
-	193, 3, 244, 243, 242, 241, 1, 235, 4, 234,
-	221, 219, 218, 217, 216, 213, 210, 0,
+	0, 251, 250, 249, 2, 248, 247, 6, 246, 9,
+	204, 3, 245, 244, 238, 237, 1, 231, 4, 230,
+	229, 228, 223, 221, 220, 219, 215, 0,
 }
 
 var pathR1 = [...]int8{
@@ -286,8 +291,8 @@ var pathR1 = [...]int8{
 	26, 26, 26, 26, 26, 26, 26, 26, 26, 26,
 	26, 26, 26, 26, 26, 26, 26, 26, 26, 26,
 	26, 26, 26, 26, 26, 26, 26, 26, 26, 26,
-	26, 26, 26, 24, 24, 24, 24, 24, 24, 24,
-	24, 24, 24, 24, 24,
+	26, 26, 26, 26, 24, 24, 24, 24, 24, 24,
+	24, 24, 24, 24, 24, 24, 24,
 }
 
 var pathR2 = [...]int8{
@@ -304,29 +309,29 @@ var pathR2 = [...]int8{
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1,
 }
 
 var pathChk = [...]int16{
-	-1000, -1, -25, 26, 27, -13, -4, -9, -19, 58,
-	52, 53, -10, 18, -3, 10, -2, 60, 61, 28,
+	-1000, -1, -25, 26, 27, -13, -4, -9, -19, 59,
+	53, 54, -10, 18, -3, 10, -2, 61, 62, 28,
 	12, 5, 6, 7, 13, 14, 15, -23, 29, 31,
-	52, 53, 54, 55, 56, 21, 22, 19, 24, 20,
-	23, 17, 16, -7, 67, -5, 68, 63, -9, -4,
-	-4, 58, -4, -10, 58, 58, -4, 30, 12, -4,
-	-4, -4, -4, -4, -9, -4, -9, -8, 54, -6,
+	53, 54, 55, 56, 57, 21, 22, 19, 24, 20,
+	23, 17, 16, -7, 68, -5, 69, 64, -9, -4,
+	-4, 59, -4, -10, 59, 59, -4, 30, 12, -4,
+	-4, -4, -4, -4, -9, -4, -9, -8, 55, -6,
 	-24, 44, 43, 40, 48, 49, 50, 51, -26, 25,
 	33, 34, 35, 36, 37, 38, 39, 41, 42, 45,
-	46, 47, 11, 12, 4, 5, 6, 7, 8, 9,
-	10, 26, 27, 28, 29, 30, 31, 32, 58, 54,
-	-22, -11, -4, 59, 59, -9, -9, -4, -12, 12,
-	15, 32, 58, 58, 58, 58, 58, 58, 58, 58,
-	65, -9, 64, 62, 64, 4, 8, -7, -7, 59,
-	59, 59, 12, 59, -21, -20, -16, 14, 52, 53,
-	59, -15, -14, 12, -18, -17, 14, -18, -18, -18,
-	-27, 14, 28, 59, -11, -4, 9, 59, 62, 14,
-	14, 59, 59, 59, 59, 59, 66, 4, -16, -27,
-	66,
+	46, 47, 52, 11, 12, 4, 5, 6, 7, 8,
+	9, 10, 26, 27, 28, 29, 30, 31, 32, 59,
+	55, -22, -11, -4, 60, 60, -9, -9, -4, -12,
+	12, 15, 32, 59, 59, 59, 59, 59, 59, 59,
+	59, 66, -9, 65, 63, 65, 4, 8, -7, -7,
+	60, 60, 60, 12, 60, -21, -20, -16, 14, 53,
+	54, 60, -15, -14, 12, -18, -17, 14, -18, -18,
+	-18, -27, 14, 28, 60, -11, -4, 9, 60, 63,
+	14, 14, 60, 60, 60, 60, 60, 67, 4, -16,
+	-27, 67,
 }
 
 var pathDef = [...]int8{
@@ -339,32 +344,32 @@ var pathDef = [...]int8{
 	46, 47, 48, 49, 24, 0, 25, 61, 62, 64,
 	0, 115, 114, 105, 119, 120, 121, 122, 87, 58,
 	-2, -2, -2, -2, -2, -2, -2, -2, -2, -2,
-	-2, -2, 88, 89, 90, 91, 92, 93, 94, 95,
-	96, 97, 98, 107, 108, 109, 110, 111, 0, 0,
-	0, 52, 50, 20, 42, 0, 0, 0, 28, 31,
-	32, 0, 0, 80, 0, 86, 83, 83, 83, 83,
-	0, 0, 54, 0, 55, 0, 0, 39, 38, 0,
-	20, 21, 30, 65, 0, 79, 77, 74, 0, 0,
-	68, 0, 85, 84, 0, 82, 81, 0, 0, 0,
-	0, 56, 57, 66, 53, 51, 27, 67, 0, 75,
-	76, 69, 70, 71, 72, 73, 59, 0, 78, 0,
-	60,
+	-2, -2, -2, 88, 89, 90, 91, 92, 93, 94,
+	95, 96, 97, 98, 107, 108, 109, 110, 111, 0,
+	0, 0, 52, 50, 20, 42, 0, 0, 0, 28,
+	31, 32, 0, 0, 80, 0, 86, 83, 83, 83,
+	83, 0, 0, 54, 0, 55, 0, 0, 39, 38,
+	0, 20, 21, 30, 65, 0, 79, 77, 74, 0,
+	0, 68, 0, 85, 84, 0, 82, 81, 0, 0,
+	0, 0, 56, 57, 66, 53, 51, 27, 67, 0,
+	75, 76, 69, 70, 71, 72, 73, 59, 0, 78,
+	0, 60,
 }
 
 var pathTok1 = [...]int8{
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 60, 56, 3, 3,
-	58, 59, 54, 52, 62, 53, 67, 55, 3, 3,
+	3, 3, 3, 3, 3, 3, 61, 57, 3, 3,
+	59, 60, 55, 53, 63, 54, 68, 56, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 68, 61, 3, 3, 3, 3, 3,
+	3, 3, 3, 69, 62, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 63, 3, 64, 3, 3, 3, 3, 3, 3,
+	3, 64, 3, 65, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 65, 3, 66,
+	3, 3, 3, 66, 3, 67,
 }
 
 var pathTok2 = [...]int8{
@@ -373,7 +378,7 @@ var pathTok2 = [...]int8{
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
 	32, 33, 34, 35, 36, 37, 38, 39, 40, 41,
 	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
-	57,
+	52, 58,
 }
 
 var pathTok3 = [...]int8{
@@ -718,412 +723,412 @@ pathdefault:
 
 	case 1:
 		pathDollar = pathS[pathpt-2 : pathpt+1]
-//line grammar.y:81
+//line grammar.y:82
 		{
 			pathlex.(*lexer).setResult(pathDollar[1].boolean, pathDollar[2].value)
 		}
 	case 2:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:85
+//line grammar.y:86
 		{
 			pathVAL.value = pathDollar[1].value
 		}
 	case 3:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:86
+//line grammar.y:87
 		{
 			pathVAL.value = pathDollar[1].value
 			pathlex.(*lexer).setPred()
 		}
 	case 4:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:90
+//line grammar.y:91
 		{
 			pathVAL.boolean = false
 		}
 	case 5:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:91
+//line grammar.y:92
 		{
 			pathVAL.boolean = true
 		}
 	case 6:
 		pathDollar = pathS[pathpt-0 : pathpt+1]
-//line grammar.y:92
+//line grammar.y:93
 		{
 			pathVAL.boolean = true
 		}
 	case 7:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:96
+//line grammar.y:97
 		{
-			pathVAL.value = ast.NewString(pathDollar[1].str)
+			pathVAL.value = pathlex.(*lexer).newString(pathDollar[1].str)
 		}
 	case 8:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:97
+//line grammar.y:98
 		{
 			pathVAL.value = ast.NewConst(ast.ConstNull)
 		}
 	case 9:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:98
+//line grammar.y:99
 		{
 			pathVAL.value = ast.NewConst(ast.ConstTrue)
 		}
 	case 10:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:99
+//line grammar.y:100
 		{
 			pathVAL.value = ast.NewConst(ast.ConstFalse)
 		}
 	case 11:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:100
+//line grammar.y:101
 		{
 			pathVAL.value = ast.NewNumeric(pathDollar[1].str)
 		}
 	case 12:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:101
+//line grammar.y:102
 		{
 			pathVAL.value = ast.NewInteger(pathDollar[1].str)
 		}
 	case 13:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:102
+//line grammar.y:103
 		{
 			pathVAL.value = ast.NewVariable(pathDollar[1].str)
 		}
 	case 14:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:106
+//line grammar.y:107
 		{
 			pathVAL.optype = ast.BinaryEqual
 		}
 	case 15:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:107
+//line grammar.y:108
 		{
 			pathVAL.optype = ast.BinaryNotEqual
 		}
 	case 16:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:108
+//line grammar.y:109
 		{
 			pathVAL.optype = ast.BinaryLess
 		}
 	case 17:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:109
+//line grammar.y:110
 		{
 			pathVAL.optype = ast.BinaryGreater
 		}
 	case 18:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:110
+//line grammar.y:111
 		{
 			pathVAL.optype = ast.BinaryLessOrEqual
 		}
 	case 19:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:111
+//line grammar.y:112
 		{
 			pathVAL.optype = ast.BinaryGreaterOrEqual
 		}
 	case 20:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:115
+//line grammar.y:116
 		{
 			pathVAL.value = pathDollar[2].value
 		}
 	case 21:
 		pathDollar = pathS[pathpt-4 : pathpt+1]
-//line grammar.y:116
+//line grammar.y:117
 		{
 			pathVAL.value = ast.NewUnary(ast.UnaryExists, pathDollar[3].value)
 		}
 	case 22:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:120
+//line grammar.y:121
 		{
 			pathVAL.value = pathDollar[1].value
 		}
 	case 23:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:121
+//line grammar.y:122
 		{
 			pathVAL.value = ast.NewBinary(pathDollar[2].optype, pathDollar[1].value, pathDollar[3].value)
 		}
 	case 24:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:122
+//line grammar.y:123
 		{
 			pathVAL.value = ast.NewBinary(ast.BinaryAnd, pathDollar[1].value, pathDollar[3].value)
 		}
 	case 25:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:123
+//line grammar.y:124
 		{
 			pathVAL.value = ast.NewBinary(ast.BinaryOr, pathDollar[1].value, pathDollar[3].value)
 		}
 	case 26:
 		pathDollar = pathS[pathpt-2 : pathpt+1]
-//line grammar.y:124
+//line grammar.y:125
 		{
 			pathVAL.value = ast.NewUnary(ast.UnaryNot, pathDollar[2].value)
 		}
 	case 27:
 		pathDollar = pathS[pathpt-5 : pathpt+1]
-//line grammar.y:126
+//line grammar.y:127
 		{
 			pathVAL.value = ast.NewUnary(ast.UnaryIsUnknown, pathDollar[2].value)
 		}
 	case 28:
 		pathDollar = pathS[pathpt-4 : pathpt+1]
-//line grammar.y:128
+//line grammar.y:129
 		{
 			pathVAL.value = ast.NewBinary(ast.BinaryStartsWith, pathDollar[1].value, pathDollar[4].value)
 		}
 	case 29:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:130
+//line grammar.y:131
 		{
 			var err error
 			pathVAL.value, err = ast.NewRegex(pathDollar[1].value, pathDollar[3].str, "")
 			if err != nil {
-				pathlex.Error(err.Error())
+				pathlex.(*lexer).regexError(err, pathDollar[3].str, "")
 			}
 		}
 	case 30:
 		pathDollar = pathS[pathpt-5 : pathpt+1]
-//line grammar.y:138
+//line grammar.y:139
 		{
 			var err error
 			pathVAL.value, err = ast.NewRegex(pathDollar[1].value, pathDollar[3].str, pathDollar[5].str)
 			if err != nil {
-				pathlex.Error(err.Error())
+				pathlex.(*lexer).regexError(err, pathDollar[3].str, pathDollar[5].str)
 			}
 		}
 	case 31:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:148
+//line grammar.y:149
 		{
 			pathVAL.value = ast.NewString(pathDollar[1].str)
 		}
 	case 32:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:149
+//line grammar.y:150
 		{
 			pathVAL.value = ast.NewVariable(pathDollar[1].str)
 		}
 	case 33:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:153
+//line grammar.y:154
 		{
 			pathVAL.value = pathDollar[1].value
 		}
 	case 34:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:154
+//line grammar.y:155
 		{
 			pathVAL.value = ast.NewConst(ast.ConstRoot)
 		}
 	case 35:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:155
+//line grammar.y:156
 		{
 			pathVAL.value = ast.NewConst(ast.ConstCurrent)
 		}
 	case 36:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:156
+//line grammar.y:157
 		{
 			pathVAL.value = ast.NewConst(ast.ConstLast)
 		}
 	case 37:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:160
+//line grammar.y:161
 		{
 			pathVAL.elems = []ast.Node{pathDollar[1].value}
 		}
 	case 38:
 		pathDollar = pathS[pathpt-4 : pathpt+1]
-//line grammar.y:161
+//line grammar.y:162
 		{
 			pathVAL.elems = []ast.Node{pathDollar[2].value, pathDollar[4].value}
 		}
 	case 39:
 		pathDollar = pathS[pathpt-4 : pathpt+1]
-//line grammar.y:162
+//line grammar.y:163
 		{
 			pathVAL.elems = []ast.Node{pathDollar[2].value, pathDollar[4].value}
 		}
 	case 40:
 		pathDollar = pathS[pathpt-2 : pathpt+1]
-//line grammar.y:163
+//line grammar.y:164
 		{
 			pathVAL.elems = append(pathVAL.elems, pathDollar[2].value)
 		}
 	case 41:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:167
+//line grammar.y:168
 		{
 			pathVAL.value = ast.LinkNodes(pathDollar[1].elems)
 		}
 	case 42:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:168
+//line grammar.y:169
 		{
 			pathVAL.value = pathDollar[2].value
 		}
 	case 43:
 		pathDollar = pathS[pathpt-2 : pathpt+1]
-//line grammar.y:169
+//line grammar.y:170
 		{
 			pathVAL.value = ast.NewUnaryOrNumber(ast.UnaryPlus, pathDollar[2].value)
 		}
 	case 44:
 		pathDollar = pathS[pathpt-2 : pathpt+1]
-//line grammar.y:170
+//line grammar.y:171
 		{
 			pathVAL.value = ast.NewUnaryOrNumber(ast.UnaryMinus, pathDollar[2].value)
 		}
 	case 45:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:171
+//line grammar.y:172
 		{
 			pathVAL.value = ast.NewBinary(ast.BinaryAdd, pathDollar[1].value, pathDollar[3].value)
 		}
 	case 46:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:172
+//line grammar.y:173
 		{
 			pathVAL.value = ast.NewBinary(ast.BinarySub, pathDollar[1].value, pathDollar[3].value)
 		}
 	case 47:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:173
+//line grammar.y:174
 		{
 			pathVAL.value = ast.NewBinary(ast.BinaryMul, pathDollar[1].value, pathDollar[3].value)
 		}
 	case 48:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:174
+//line grammar.y:175
 		{
 			pathVAL.value = ast.NewBinary(ast.BinaryDiv, pathDollar[1].value, pathDollar[3].value)
 		}
 	case 49:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:175
+//line grammar.y:176
 		{
 			pathVAL.value = ast.NewBinary(ast.BinaryMod, pathDollar[1].value, pathDollar[3].value)
 		}
 	case 50:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:179
+//line grammar.y:180
 		{
-			pathVAL.value = ast.NewBinary(ast.BinarySubscript, pathDollar[1].value, nil)
+			pathVAL.value = ast.NewBinary(ast.BinarySubscript, pathlex.(*lexer).subscript(pathDollar[1].value), nil)
 		}
 	case 51:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:180
+//line grammar.y:181
 		{
-			pathVAL.value = ast.NewBinary(ast.BinarySubscript, pathDollar[1].value, pathDollar[3].value)
+			pathVAL.value = ast.NewBinary(ast.BinarySubscript, pathlex.(*lexer).subscript(pathDollar[1].value), pathlex.(*lexer).subscript(pathDollar[3].value))
 		}
 	case 52:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:184
+//line grammar.y:185
 		{
 			pathVAL.indexs = []ast.Node{pathDollar[1].value}
 		}
 	case 53:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:185
+//line grammar.y:186
 		{
 			pathVAL.indexs = append(pathVAL.indexs, pathDollar[3].value)
 		}
 	case 54:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:189
+//line grammar.y:190
 		{
 			pathVAL.value = ast.NewConst(ast.ConstAnyArray)
 		}
 	case 55:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:190
+//line grammar.y:191
 		{
 			pathVAL.value = ast.NewArrayIndex(pathDollar[2].indexs)
 		}
 	case 56:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:194
+//line grammar.y:195
 		{
 			pathVAL.integer, _ = strconv.Atoi(pathDollar[1].str)
 		}
 	case 57:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:195
+//line grammar.y:196
 		{
 			pathVAL.integer = -1
 		}
 	case 58:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:199
+//line grammar.y:200
 		{
 			pathVAL.value = ast.NewAny(0, -1)
 		}
 	case 59:
 		pathDollar = pathS[pathpt-4 : pathpt+1]
-//line grammar.y:200
+//line grammar.y:201
 		{
 			pathVAL.value = ast.NewAny(pathDollar[3].integer, pathDollar[3].integer)
 		}
 	case 60:
 		pathDollar = pathS[pathpt-6 : pathpt+1]
-//line grammar.y:202
+//line grammar.y:203
 		{
 			pathVAL.value = ast.NewAny(pathDollar[3].integer, pathDollar[5].integer)
 		}
 	case 61:
 		pathDollar = pathS[pathpt-2 : pathpt+1]
-//line grammar.y:206
+//line grammar.y:207
 		{
 			pathVAL.value = pathDollar[2].value
 		}
 	case 62:
 		pathDollar = pathS[pathpt-2 : pathpt+1]
-//line grammar.y:207
+//line grammar.y:208
 		{
 			pathVAL.value = ast.NewConst(ast.ConstAnyKey)
 		}
 	case 63:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:208
+//line grammar.y:209
 		{
 			pathVAL.value = pathDollar[1].value
 		}
 	case 64:
 		pathDollar = pathS[pathpt-2 : pathpt+1]
-//line grammar.y:209
+//line grammar.y:210
 		{
 			pathVAL.value = pathDollar[2].value
 		}
 	case 65:
 		pathDollar = pathS[pathpt-4 : pathpt+1]
-//line grammar.y:210
+//line grammar.y:211
 		{
 			pathVAL.value = pathDollar[2].method
 		}
 	case 66:
 		pathDollar = pathS[pathpt-4 : pathpt+1]
-//line grammar.y:211
+//line grammar.y:212
 		{
 			pathVAL.value = ast.NewUnary(ast.UnaryFilter, pathDollar[3].value)
 		}
 	case 67:
 		pathDollar = pathS[pathpt-5 : pathpt+1]
-//line grammar.y:213
+//line grammar.y:214
 		{
 			switch len(pathDollar[4].elems) {
 			case 0:
@@ -1138,196 +1143,202 @@ pathdefault:
 		}
 	case 68:
 		pathDollar = pathS[pathpt-4 : pathpt+1]
-//line grammar.y:225
+//line grammar.y:226
 		{
 			pathVAL.value = ast.NewUnary(ast.UnaryDate, nil)
 		}
 	case 69:
 		pathDollar = pathS[pathpt-5 : pathpt+1]
-//line grammar.y:227
+//line grammar.y:228
 		{
 			pathVAL.value = ast.NewUnary(ast.UnaryDateTime, pathDollar[4].value)
 		}
 	case 70:
 		pathDollar = pathS[pathpt-5 : pathpt+1]
-//line grammar.y:229
+//line grammar.y:230
 		{
 			pathVAL.value = ast.NewUnary(ast.UnaryTime, pathDollar[4].value)
 		}
 	case 71:
 		pathDollar = pathS[pathpt-5 : pathpt+1]
-//line grammar.y:231
+//line grammar.y:232
 		{
 			pathVAL.value = ast.NewUnary(ast.UnaryTimeTZ, pathDollar[4].value)
 		}
 	case 72:
 		pathDollar = pathS[pathpt-5 : pathpt+1]
-//line grammar.y:233
+//line grammar.y:234
 		{
 			pathVAL.value = ast.NewUnary(ast.UnaryTimestamp, pathDollar[4].value)
 		}
 	case 73:
 		pathDollar = pathS[pathpt-5 : pathpt+1]
-//line grammar.y:235
+//line grammar.y:236
 		{
 			pathVAL.value = ast.NewUnary(ast.UnaryTimestampTZ, pathDollar[4].value)
 		}
 	case 74:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:240
+//line grammar.y:241
 		{
 			pathVAL.value = ast.NewInteger(pathDollar[1].str)
 		}
 	case 75:
 		pathDollar = pathS[pathpt-2 : pathpt+1]
-//line grammar.y:242
+//line grammar.y:243
 		{
 			pathVAL.value = ast.NewUnaryOrNumber(ast.UnaryPlus, ast.NewInteger(pathDollar[2].str))
 		}
 	case 76:
 		pathDollar = pathS[pathpt-2 : pathpt+1]
-//line grammar.y:244
+//line grammar.y:245
 		{
 			pathVAL.value = ast.NewUnaryOrNumber(ast.UnaryMinus, ast.NewInteger(pathDollar[2].str))
 		}
 	case 77:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:248
+//line grammar.y:249
 		{
 			pathVAL.elems = []ast.Node{pathDollar[1].value}
 		}
 	case 78:
 		pathDollar = pathS[pathpt-3 : pathpt+1]
-//line grammar.y:249
+//line grammar.y:250
 		{
 			pathVAL.elems = append(pathVAL.elems, pathDollar[3].value)
 		}
 	case 79:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:253
+//line grammar.y:254
 		{
 			pathVAL.elems = pathDollar[1].elems
 		}
 	case 80:
 		pathDollar = pathS[pathpt-0 : pathpt+1]
-//line grammar.y:254
+//line grammar.y:255
 		{
 			pathVAL.elems = nil
 		}
 	case 81:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:258
+//line grammar.y:259
 		{
 			pathVAL.value = ast.NewInteger(pathDollar[1].str)
 		}
 	case 82:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:262
+//line grammar.y:263
 		{
 			pathVAL.value = pathDollar[1].value
 		}
 	case 83:
 		pathDollar = pathS[pathpt-0 : pathpt+1]
-//line grammar.y:263
+//line grammar.y:264
 		{
 			pathVAL.value = nil
 		}
 	case 84:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:267
+//line grammar.y:268
 		{
 			pathVAL.value = ast.NewString(pathDollar[1].str)
 		}
 	case 85:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:271
+//line grammar.y:272
 		{
 			pathVAL.value = pathDollar[1].value
 		}
 	case 86:
 		pathDollar = pathS[pathpt-0 : pathpt+1]
-//line grammar.y:272
+//line grammar.y:273
 		{
 			pathVAL.value = nil
 		}
 	case 87:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:276
+//line grammar.y:277
 		{
 			pathVAL.value = ast.NewKey(pathDollar[1].str)
 		}
-	case 123:
+	case 124:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:318
+//line grammar.y:320
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodAbs)
 		}
-	case 124:
+	case 125:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:319
+//line grammar.y:321
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodSize)
 		}
-	case 125:
+	case 126:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:320
+//line grammar.y:322
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodType)
 		}
-	case 126:
+	case 127:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:321
+//line grammar.y:323
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodFloor)
 		}
-	case 127:
+	case 128:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:322
+//line grammar.y:324
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodDouble)
 		}
-	case 128:
+	case 129:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:323
+//line grammar.y:325
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodCeiling)
 		}
-	case 129:
+	case 130:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:324
+//line grammar.y:326
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodKeyValue)
 		}
-	case 130:
+	case 131:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:325
+//line grammar.y:327
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodBigInt)
 		}
-	case 131:
+	case 132:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:326
+//line grammar.y:328
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodBoolean)
 		}
-	case 132:
+	case 133:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:327
+//line grammar.y:329
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodInteger)
 		}
-	case 133:
+	case 134:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:328
+//line grammar.y:330
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodNumber)
 		}
-	case 134:
+	case 135:
 		pathDollar = pathS[pathpt-1 : pathpt+1]
-//line grammar.y:329
+//line grammar.y:331
 		{
 			pathVAL.method = ast.NewMethod(ast.MethodString)
 		}
+	case 136:
+		pathDollar = pathS[pathpt-1 : pathpt+1]
+//line grammar.y:332
+		{
+			pathVAL.method = ast.NewCustomMethod(pathDollar[1].str)
+		}
 	}
 	goto pathstack /* stack new state and value */
 }
@@ -43,6 +43,7 @@ import (
 %token	<str>		DATETIME_P
 %token	<str>		BIGINT_P BOOLEAN_P DATE_P DECIMAL_P INTEGER_P NUMBER_P
 %token	<str>		STRINGFUNC_P TIME_P TIME_TZ_P TIMESTAMP_P TIMESTAMP_TZ_P
+%token	<str>		METHOD_P
 
 %type	<result>	result
 
@@ -93,7 +94,7 @@ mode:
 	;
 
 scalar_value:
-	STRING_P						{ $$ = ast.NewString($1) }
+	STRING_P						{ $$ = pathlex.(*lexer).newString($1) }
 	| NULL_P						{ $$ = ast.NewConst(ast.ConstNull) }
 	| TRUE_P						{ $$ = ast.NewConst(ast.ConstTrue) }
 	| FALSE_P						{ $$ = ast.NewConst(ast.ConstFalse) }
@@ -131,7 +132,7 @@ predicate:
 		var err error
 		$$, err = ast.NewRegex($1, $3, "")
 		if err != nil {
-			pathlex.Error(err.Error())
+			pathlex.(*lexer).regexError(err, $3, "")
 		}
 	}
 	| expr LIKE_REGEX_P STRING_P FLAG_P STRING_P
@@ -139,7 +140,7 @@ predicate:
 		var err error
 		$$, err = ast.NewRegex($1, $3, $5)
 		if err != nil {
-			pathlex.Error(err.Error())
+			pathlex.(*lexer).regexError(err, $3, $5)
 		}
 	}
 	;
@@ -176,8 +177,8 @@ expr:
 	;
 
 index_elem:
-	expr							{ $$ = ast.NewBinary(ast.BinarySubscript, $1, nil) }
-	| expr TO_P expr				{ $$ = ast.NewBinary(ast.BinarySubscript, $1, $3) }
+	expr							{ $$ = ast.NewBinary(ast.BinarySubscript, pathlex.(*lexer).subscript($1), nil) }
+	| expr TO_P expr				{ $$ = ast.NewBinary(ast.BinarySubscript, pathlex.(*lexer).subscript($1), pathlex.(*lexer).subscript($3)) }
 	;
 
 index_list:
@@ -312,6 +313,7 @@ key_name:
 	| TIME_TZ_P
 	| TIMESTAMP_P
 	| TIMESTAMP_TZ_P
+	| METHOD_P
 	;
 
 method:
@@ -327,5 +329,6 @@ method:
 	| INTEGER_P						{ $$ = ast.NewMethod(ast.MethodInteger) }
 	| NUMBER_P						{ $$ = ast.NewMethod(ast.MethodNumber) }
 	| STRINGFUNC_P					{ $$ = ast.NewMethod(ast.MethodString) }
+	| METHOD_P						{ $$ = ast.NewCustomMethod($1) }
 	;
 %%
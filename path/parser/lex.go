@@ -32,6 +32,7 @@ package parser
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf16"
@@ -53,6 +54,12 @@ func (pos position) String() string {
 	return fmt.Sprintf("%d:%d", pos.Line, pos.Column)
 }
 
+// stringToken records the position and source text of a string literal.
+type stringToken struct {
+	position
+	text string
+}
+
 const (
 	// whitespace selects white space characters.
 	whitespace = 1<<'\t' | 1<<'\n' | 1<<'\r' | 1<<' '
@@ -71,6 +78,9 @@ const (
 	dollar    = '$'
 	null      = rune(0)
 
+	// First UTF-16 low surrogate; lower surrogates are high surrogates.
+	lowSurrogate = 0xdc00
+
 	// Numeric bases.
 	decimal = 10
 	hex     = 16
@@ -83,11 +93,23 @@ type lexer struct {
 	// Collects errors while lexing.
 	errors []string
 
+	// The SyntaxError describing the first error in errors, if reported by
+	// Error.
+	err *SyntaxError
+
+	// The construct being scanned, for SyntaxError.Context.
+	context string
+
 	// The parser stores the parsed result here, using setResult() and
 	// setPred().
 	result *ast.AST
 	pred   bool
 
+	// The most recently scanned string literal, and those the parser
+	// converted to nodes, so that errors can point to them.
+	lastString stringToken
+	stringToks map[*ast.StringNode]stringToken
+
 	// Buffer to hold normalized string while parsing JavaScript string.
 	strBuf strings.Builder
 
@@ -209,10 +231,27 @@ func (l *lexer) peek() rune {
 
 // Error implements the Error function required by the pathLexer interface
 // generated by the parser grammar. It appends msg and the current position to
-// l.errors.
+// l.errors, and records the details of the first error in l.err.
 func (l *lexer) Error(msg string) {
 	l.tokEnd = l.srcPos - l.lastCharLen // make sure token text is terminated
-	l.errors = append(l.errors, fmt.Sprintf("%v at %v", msg, l.pos()))
+	err := l.newSyntaxError(msg)
+	if len(l.errors) == 0 {
+		l.err = err
+	}
+	l.errors = append(l.errors, fmt.Sprintf("%v at %v", err.Msg, l.pos()))
+}
+
+// regexError sends err, returned by ast.NewRegex for pattern and flags, to
+// Error, identifying whether it pertains to the flags or the pattern.
+func (l *lexer) regexError(err error, pattern, flags string) {
+	l.context = ContextRegex
+	if _, ferr := ast.NewRegex(nil, "", flags); ferr != nil {
+		l.context = ContextRegexFlags
+		pattern = flags
+	}
+	l.Error(err.Error())
+	l.err.Token = pattern
+	l.context = ""
 }
 
 // errorf provides a fmt-compatible interface sending an error to [Error].
@@ -220,6 +259,21 @@ func (l *lexer) errorf(format string, args ...any) {
 	l.Error(fmt.Sprintf(format, args...))
 }
 
+// errorAt works like Error, but reports the error at tok rather than the
+// current position.
+func (l *lexer) errorAt(msg string, tok stringToken) {
+	if len(l.errors) == 0 {
+		l.err = &SyntaxError{
+			Msg:    msg,
+			Line:   tok.Line,
+			Column: tok.Column,
+			Offset: tok.Offset,
+			Token:  tok.text,
+		}
+	}
+	l.errors = append(l.errors, fmt.Sprintf("%v at %v", msg, tok.position))
+}
+
 // pos returns the position of the character immediately after the character
 // or token returned by the last call to Next or Scan. Use l.Position for the
 // start position of the most recently scanned token.
@@ -317,6 +371,9 @@ redo:
 
 	l.ch = ch
 	lval.str = l.tokenText()
+	if tok == STRING_P {
+		l.lastString = stringToken{l.position, string(l.srcBuf[l.tokPos:l.tokEnd])}
+	}
 	return int(tok)
 }
 
@@ -475,7 +532,32 @@ func (l *lexer) scanNumber(ch rune, seenDot bool) (rune, rune) {
 		return stopTok, stopTok
 	}
 
-	return tok, ch
+	return l.checkRange(tok, prefix), ch
+}
+
+// checkRange ensures that the number most recently scanned by scanNumber can
+// be represented as an int64 (INT_P) or float64 (NUMERIC_P). Decimal integers
+// out of int64 range become NUMERIC_P, as in PostgreSQL. Records an error
+// and returns stopTok for other out of range values.
+func (l *lexer) checkRange(tok, prefix rune) rune {
+	l.tokEnd = l.srcPos - l.lastCharLen // make sure token text is terminated
+	text := l.tokenText()
+	if tok == INT_P {
+		if _, err := strconv.ParseInt(text, 0, 64); err == nil {
+			return tok
+		}
+		if prefix != 0 && prefix != '0' {
+			l.errorf("%s out of range", litName(prefix))
+			return stopTok
+		}
+		tok = NUMERIC_P
+	}
+
+	if _, err := strconv.ParseFloat(text, 64); err != nil {
+		l.Error("numeric literal out of range")
+		return stopTok
+	}
+	return tok
 }
 
 // tokenText returns the string corresponding to the most recently scanned token.
@@ -565,6 +647,32 @@ func (l *lexer) setResult(lax bool, node ast.Node) {
 	l.result = ast
 }
 
+// newString creates an ast.StringNode for str, the string literal most
+// recently scanned, and records its position. Called by the parser grammar.
+func (l *lexer) newString(str string) *ast.StringNode {
+	node := ast.NewString(str)
+	if l.stringToks == nil {
+		l.stringToks = map[*ast.StringNode]stringToken{}
+	}
+	l.stringToks[node] = l.lastString
+	return node
+}
+
+// subscript returns node. If node is a string literal, it first reports an
+// error pointing to the string, since a string can never be an array
+// subscript, and SQL/JSON path has no $["key"] syntax for object members.
+// Called by the parser grammar.
+func (l *lexer) subscript(node ast.Node) ast.Node {
+	if str, ok := node.(*ast.StringNode); ok {
+		tok := l.stringToks[str]
+		l.errorAt(fmt.Sprintf(
+			"string %v is not a valid array subscript; use .%v to access an object member",
+			tok.text, str,
+		), tok)
+	}
+	return node
+}
+
 // setPred indicates that the path being lexed is a predicate path query.
 // Called by the parser grammar.
 func (l *lexer) setPred() {
@@ -717,10 +825,20 @@ func (l *lexer) scanIdent(ch rune) (rune, rune) {
 	}
 
 	l.gotString = true
-	return identToken(l.strBuf.String()), ch
+	ident := l.strBuf.String()
+	if tok := identToken(ident); tok != IDENT_P || !isMethod(ident) {
+		return tok, ch
+	}
+	return METHOD_P, ch
 }
 
 func (l *lexer) scanString(ret rune) (rune, rune) {
+	l.context = ContextString
+	if ret == VARIABLE_P {
+		l.context = ContextVariable
+	}
+	defer func() { l.context = "" }()
+
 	ch := l.next() // read character after quote
 	for ch != quote {
 		if ch == newline || ch < 0 {
@@ -813,6 +931,10 @@ func (l *lexer) scanUnicode() rune {
 		if rr1 <= null {
 			return rr1
 		}
+		if rr < lowSurrogate && utf16.IsSurrogate(rr1) && rr1 < lowSurrogate {
+			l.Error("Unicode high surrogate must not follow a high surrogate")
+			return stopTok
+		}
 
 		if dec := utf16.DecodeRune(rr, rr1); dec != unicode.ReplacementChar {
 			// A valid pair; encode it as UTF-8.
@@ -865,6 +987,19 @@ func isIdentRune(ch rune, i int) bool {
 	return ch == '_' || ch == '\\' || (i == 0 && xid.Start(ch)) || (i > 0 && xid.Continue(ch))
 }
 
+// isIdent returns true if str is an identifier without escapes.
+func isIdent(str string) bool {
+	if str == "" {
+		return false
+	}
+	for i, ch := range []rune(str) {
+		if ch == backslash || !isIdentRune(ch, i) {
+			return false
+		}
+	}
+	return true
+}
+
 // isVariableRune is a predicate controlling the characters accepted as a rune
 // in a variable name. It follows the same conventions as isIdentRune, except
 // that the first character is not treated different, because in SQL/JSON paths,
@@ -918,9 +1053,10 @@ func (l *lexer) decodeUnicode() rune {
 		// parse '\u{NN...}'
 		c := l.next()
 
-		// Consume up to six hexadecimal characters and combine them into a
+		// Consume one to six hexadecimal characters and combine them into a
 		// single rune.
-		for i := 0; i < 6 && c != '}'; i, c = i+1, l.next() {
+		i := 0
+		for ; i < 6 && c != '}'; i, c = i+1, l.next() {
 			si := hexChar(c)
 			if si < null {
 				l.Error("invalid Unicode escape sequence")
@@ -930,7 +1066,7 @@ func (l *lexer) decodeUnicode() rune {
 			rr = merge(rr, si)
 		}
 
-		if c != '}' {
+		if c != '}' || i == 0 {
 			l.Error("invalid Unicode escape sequence")
 			return stopTok
 		}
@@ -954,10 +1090,14 @@ func (l *lexer) decodeUnicode() rune {
 		}
 	}
 
-	if rr == null {
+	switch {
+	case rr == null:
 		// \u0000, null, not supported.
 		l.Error(`\u0000 cannot be converted to text`)
 		return stopTok
+	case rr > unicode.MaxRune:
+		l.Error("could not convert Unicode to server encoding")
+		return stopTok
 	}
 
 	return rr
@@ -521,8 +521,8 @@ func TestScanNumbers(t *testing.T) {
 		{"one", "1", "1", INT_P, ""},
 		{"zero", "0", "0", INT_P, ""},
 		{"max_int", "9223372036854775807", "9223372036854775807", INT_P, ""},
-		{"min_int", "9223372036854775808", "9223372036854775808", INT_P, ""}, // without -
-		{"max_uint", "18446744073709551615", "18446744073709551615", INT_P, ""},
+		{"min_int", "9223372036854775808", "9223372036854775808", NUMERIC_P, ""}, // without -, overflows int64
+		{"max_uint", "18446744073709551615", "18446744073709551615", NUMERIC_P, ""},
 		{"underscores", "1_000_000", "1_000_000", INT_P, ""},
 		{"hex", "0x1EEE_FFFF", "0x1EEE_FFFF", INT_P, ""},
 		{"HEX", "0X1EEE_FFFF", "0X1EEE_FFFF", INT_P, ""},
@@ -598,14 +598,14 @@ func TestScanNumbers(t *testing.T) {
 			"go_int_example_10",
 			"170141183460469231731687303715884105727",
 			"170141183460469231731687303715884105727",
-			INT_P,
+			NUMERIC_P, // Overflows int64
 			"",
 		},
 		{
 			"go_int_example_11",
 			"170_141183_460469_231731_687303_715884_105727",
 			"170_141183_460469_231731_687303_715884_105727",
-			INT_P,
+			NUMERIC_P, // Overflows int64
 			"",
 		},
 		{"go_int_example_12", "_42", "_42", IDENT_P, ""},
@@ -784,6 +784,20 @@ func TestScanNumbers(t *testing.T) {
 			stopTok,
 			"'e' exponent requires decimal mantissa at 1:5",
 		},
+		{
+			"hex_out_of_range",
+			`0xFFFF_FFFF_FFFF_FFFF`, // Postgres: 18446744073709551615
+			"0xFFFF_FFFF_FFFF_FFFF",
+			stopTok,
+			"hexadecimal literal out of range at 1:22",
+		},
+		{
+			"numeric_out_of_range",
+			`1e400`, // Postgres: 1 followed by 400 zeros
+			"1e400",
+			stopTok,
+			"numeric literal out of range at 1:6",
+		},
 		{
 			"invalid_octal",
 			`0o9`, // Postgres: syntax error at end of jsonpath input
@@ -6,23 +6,65 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/theory/sqljson/path/ast"
 )
 
 //go:generate goyacc -v "" -o grammar.go -p path grammar.y
 
+// Enable verbose goyacc error messages, from which SyntaxError extracts
+// expected tokens.
+//
+//nolint:gochecknoinits
+func init() { pathErrorVerbose = true }
+
 // ErrParse errors are returned by the parser.
 var ErrParse = errors.New("parser")
 
-// Parse parses path.
+// Parse parses path. Syntax errors are [*SyntaxError] values; all errors
+// wrap [ErrParse].
 func Parse(path string) (*ast.AST, error) {
 	lexer := newLexer(path)
 	_ = pathParse(lexer)
 
+	if lexer.err != nil {
+		return nil, lexer.err
+	}
 	if len(lexer.errors) > 0 {
 		return nil, fmt.Errorf("%w: %v", ErrParse, lexer.errors[0])
 	}
 
 	return lexer.result, nil
 }
+
+// methods records the names of custom methods registered by RegisterMethod.
+//
+//nolint:gochecknoglobals
+var methods sync.Map
+
+// RegisterMethod registers name as the name of a custom item method, so that
+// Parse accepts paths that call it, as in $.name(). Custom methods take no
+// arguments. Names are case-sensitive, must be valid identifiers, and must
+// not be jsonpath reserved words, including the names of the built-in
+// methods. Registering the same name again has no effect. Most applications
+// should use [github.com/theory/sqljson/path/exec.RegisterMethod] instead,
+// which also registers the method's implementation.
+func RegisterMethod(name string) error {
+	if !isIdent(name) {
+		return fmt.Errorf("%w: invalid method name %q", ErrParse, name)
+	}
+	if identToken(name) != IDENT_P {
+		return fmt.Errorf(
+			"%w: method name %q conflicts with a reserved word", ErrParse, name,
+		)
+	}
+	methods.Store(name, struct{}{})
+	return nil
+}
+
+// isMethod returns true if name has been registered by RegisterMethod.
+func isMethod(name string) bool {
+	_, ok := methods.Load(name)
+	return ok
+}
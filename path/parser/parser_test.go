@@ -2,8 +2,13 @@ package parser
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	goast "go/ast"
+	goparser "go/parser"
+	"go/token"
 	"io"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -21,14 +26,13 @@ func mkAST(t *testing.T, lax, pred bool, node ast.Node) *ast.AST {
 
 func TestParser(t *testing.T) {
 	t.Parallel()
-	a := assert.New(t)
-	r := require.New(t)
 
 	for _, tc := range []struct {
 		name string
 		path string
 		ast  *ast.AST
 		err  string
+		syn  *SyntaxError
 	}{
 		{
 			name: "root",
@@ -49,10 +53,135 @@ func TestParser(t *testing.T) {
 			name: "error",
 			path: "$()",
 			err:  "parser: syntax error at 1:3",
+			syn:  &SyntaxError{Msg: "syntax error", Line: 1, Column: 3, Offset: 2, Token: "("},
+		},
+		{
+			name: "expected",
+			path: "$.a[1 2]",
+			err:  "parser: syntax error at 1:8",
+			syn: &SyntaxError{
+				Msg: "syntax error", Line: 1, Column: 8, Offset: 7, Token: "2",
+				Expected: []string{`","`, `"]"`},
+			},
+		},
+		{
+			name: "expected_end",
+			path: "$ ? (@ > 1",
+			err:  "parser: syntax error at 1:11",
+			syn: &SyntaxError{
+				Msg: "syntax error", Line: 1, Column: 11, Offset: 10,
+				Expected: []string{`"||"`, `"&&"`, `")"`},
+			},
+		},
+		{
+			name: "second_line",
+			path: "$.a\n  starts with 1",
+			err:  "parser: syntax error at 2:16",
+			syn: &SyntaxError{
+				Msg: "syntax error", Line: 2, Column: 16, Offset: 19, Token: "1",
+				Expected: []string{"string", "variable"},
+			},
+		},
+		{
+			name: "string",
+			path: `$."abc`,
+			err:  "parser: literal not terminated at 1:7",
+			syn: &SyntaxError{
+				Msg: "literal not terminated", Line: 1, Column: 7, Offset: 6,
+				Token: `"abc`, Context: ContextString,
+			},
+		},
+		{
+			name: "variable",
+			path: `$"x`,
+			err:  "parser: literal not terminated at 1:4",
+			syn: &SyntaxError{
+				Msg: "literal not terminated", Line: 1, Column: 4, Offset: 3,
+				Token: `$"x`, Context: ContextVariable,
+			},
+		},
+		{
+			name: "regex_flags",
+			path: `$ ? (@ like_regex "x" flag "z")`,
+			err:  `parser: Unrecognized flag character "z" in LIKE_REGEX predicate at 1:31`,
+			syn: &SyntaxError{
+				Msg:  `Unrecognized flag character "z" in LIKE_REGEX predicate`,
+				Line: 1, Column: 31, Offset: 30, Token: "z", Context: ContextRegexFlags,
+			},
+		},
+		{
+			name: "regex_pattern",
+			path: `$ ? (@ like_regex "(")`,
+			err:  "parser: error parsing regexp: missing closing ): `(` at 1:23",
+			syn: &SyntaxError{
+				Msg:  "error parsing regexp: missing closing ): `(`",
+				Line: 1, Column: 23, Offset: 22, Token: "(", Context: ContextRegex,
+			},
+		},
+		{
+			name: "string_subscript",
+			path: `$["a"]`,
+			err:  `parser: string "a" is not a valid array subscript; use ."a" to access an object member at 1:3`,
+			syn: &SyntaxError{
+				Msg:  `string "a" is not a valid array subscript; use ."a" to access an object member`,
+				Line: 1, Column: 3, Offset: 2, Token: `"a"`,
+			},
+		},
+		{
+			name: "string_subscript_list",
+			path: "$.x[0,\n  \"odd \\u0022key\" to last]",
+			err:  `parser: string "odd \u0022key" is not a valid array subscript; use ."odd \"key" to access an object member at 2:3`,
+			syn: &SyntaxError{
+				Msg:  `string "odd \u0022key" is not a valid array subscript; use ."odd \"key" to access an object member`,
+				Line: 2, Column: 3, Offset: 9, Token: `"odd \u0022key"`,
+			},
+		},
+		{
+			name: "string_subscript_upper",
+			path: `$[1 to "😀"]`,
+			err:  `parser: string "😀" is not a valid array subscript; use ."😀" to access an object member at 1:8`,
+			syn: &SyntaxError{
+				Msg:  `string "😀" is not a valid array subscript; use ."😀" to access an object member`,
+				Line: 1, Column: 8, Offset: 7, Token: `"😀"`,
+			},
+		},
+		{
+			name: "not_path",
+			path: "!$.x",
+			err:  "parser: syntax error at 1:3",
+			syn: &SyntaxError{
+				Msg: "syntax error", Line: 1, Column: 3, Offset: 2, Token: "$",
+				Expected: []string{`"exists"`, `"("`},
+			},
+		},
+		{
+			name: "not_path_in_filter",
+			path: "$ ? (@ > 1 && !@.a)",
+			err:  "parser: syntax error at 1:17",
+			syn: &SyntaxError{
+				Msg: "syntax error", Line: 1, Column: 17, Offset: 16, Token: "@",
+				Expected: []string{`"exists"`, `"("`},
+			},
+		},
+		{
+			name: "not_not",
+			path: "$ ? (!!(@ == 1))",
+			err:  "parser: syntax error at 1:8",
+			syn: &SyntaxError{
+				Msg: "syntax error", Line: 1, Column: 8, Offset: 7, Token: "!",
+				Expected: []string{`"exists"`, `"("`},
+			},
+		},
+		{
+			name: "not_syntax",
+			path: "last",
+			err:  "parser: LAST is allowed only in array subscripts",
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
 			ast, err := Parse(tc.path)
 			if tc.err == "" {
 				r.NoError(err)
@@ -61,11 +190,99 @@ func TestParser(t *testing.T) {
 				r.EqualError(err, tc.err)
 				r.ErrorIs(err, ErrParse)
 				a.Nil(ast)
+				var syn *SyntaxError
+				a.Equal(tc.syn != nil, errors.As(err, &syn))
+				a.Equal(tc.syn, syn)
 			}
 		})
 	}
 }
 
+func TestParseMaxDepth(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	nested := func(depth int) string {
+		return strings.Repeat("1 + (", depth) + "1" + strings.Repeat(")", depth)
+	}
+
+	// Should parse up to the max depth.
+	tree, err := Parse(nested(ast.MaxDepth / 2))
+	r.NoError(err)
+	a.NotNil(tree)
+
+	// But fail beyond it.
+	for _, path := range []string{
+		nested(ast.MaxDepth + 1),
+		"$" + strings.Repeat(".a", ast.MaxDepth+1),
+		"$ ? (" + strings.Repeat("exists(@ ? (", ast.MaxDepth) + "@ == 1" + strings.Repeat("))", ast.MaxDepth) + ")",
+	} {
+		tree, err = Parse(path)
+		r.EqualError(err, "parser: maximum nesting depth exceeded")
+		r.ErrorIs(err, ErrParse)
+		a.Nil(tree)
+	}
+}
+
+// pathLiterals returns the string values of all "path" fields in composite
+// literals in the Go source file fn.
+func pathLiterals(t *testing.T, fn string) []string {
+	t.Helper()
+	file, err := goparser.ParseFile(token.NewFileSet(), fn, nil, 0)
+	require.NoError(t, err)
+
+	paths := []string{}
+	goast.Inspect(file, func(n goast.Node) bool {
+		kv, ok := n.(*goast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		if key, ok := kv.Key.(*goast.Ident); !ok || key.Name != "path" {
+			return true
+		}
+		if lit, ok := kv.Value.(*goast.BasicLit); ok && lit.Kind == token.STRING {
+			str, err := strconv.Unquote(lit.Value)
+			require.NoError(t, err)
+			paths = append(paths, str)
+		}
+		return true
+	})
+	return paths
+}
+
+// TestRoundTrip ensures that the normalized string representation of every
+// path in the test suites parses back into an equivalent AST.
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	for _, fn := range []string{"parser_test.go", "../exec/pg_test.go", "../exec/exec_test.go"} {
+		paths := pathLiterals(t, fn)
+		r.NotEmpty(paths)
+		for _, path := range paths {
+			tree, err := Parse(path)
+			if err != nil {
+				continue
+			}
+
+			// Parse → String → Parse → String must be a fixed point.
+			str := tree.String()
+			again, err := Parse(str)
+			if !a.NoError(err, "%v: %q → %q", fn, path, str) {
+				continue
+			}
+			a.Equal(str, again.String(), "%v: %q", fn, path)
+
+			// And parsing the normalized string yields an equal AST.
+			third, err := Parse(again.String())
+			r.NoError(err)
+			a.Equal(again, third, "%v: %q", fn, path)
+		}
+	}
+}
+
 type testCase struct {
 	name string
 	path string
@@ -226,6 +443,16 @@ func TestJSONPathString(t *testing.T) {
 			path: "$.a/+-1",
 			exp:  `($."a" / -1)`,
 		},
+		{
+			name: "minus_minus_one",
+			path: "-(-1)",
+			exp:  "1",
+		},
+		{
+			name: "minus_minus_numeric",
+			path: "- -1.5",
+			exp:  "1.5",
+		},
 		{
 			name: "math",
 			path: "1 * 2 + 4 % -3 != false",
@@ -245,7 +472,7 @@ func TestJSONPathEscapesString(t *testing.T) {
 		{
 			name: "js_escapes",
 			path: `"\b\f\r\n\t\v\"\'\\"`,
-			exp:  `"\b\f\r\n\t\v\"'\\"`,
+			exp:  `"\b\f\r\n\t\u000b\"'\\"`,
 		},
 		{
 			name: "hex_and_unicode_escapes",
@@ -262,11 +489,172 @@ func TestJSONPathEscapesString(t *testing.T) {
 			path: `"\z"`, // unrecognized escape is just the literal char
 			exp:  `"z"`,
 		},
+		{
+			name: "control_chars",
+			path: `"\x01\u001f\u007f\u2028"`,
+			exp:  "\"\\u0001\\u001f\u007f\u2028\"",
+		},
+		{
+			name: "empty_brace_escape",
+			path: `"\u{}"`,
+			err:  `parser: invalid Unicode escape sequence at 1:5`,
+		},
+		{
+			name: "brace_escape_too_long",
+			path: `"\u{1234567}"`,
+			err:  `parser: invalid Unicode escape sequence at 1:11`,
+		},
+		{
+			name: "brace_escape_too_big",
+			path: `"\u{110000}"`,
+			err:  `parser: could not convert Unicode to server encoding at 1:11`,
+		},
+		{
+			name: "null_escape",
+			path: `"\u0000"`,
+			err:  `parser: \u0000 cannot be converted to text at 1:7`,
+		},
+		{
+			name: "null_brace_escape",
+			path: `$."\u{0}"`,
+			err:  `parser: \u0000 cannot be converted to text at 1:8`,
+		},
+		{
+			name: "surrogate_pair",
+			path: `"\ud83d\ude00"`,
+			exp:  `"😀"`,
+		},
+		{
+			name: "brace_surrogate_pair",
+			path: `"\u{D83D}\u{DE00}"`,
+			exp:  `"😀"`,
+		},
+		{
+			name: "lone_high_surrogate",
+			path: `"\ud83d"`,
+			err:  `parser: Unicode low surrogate must follow a high surrogate at 1:8`,
+		},
+		{
+			name: "lone_low_surrogate",
+			path: `"\ude00\ud83d"`,
+			err:  `parser: Unicode low surrogate must follow a high surrogate at 1:13`,
+		},
+		{
+			name: "high_surrogate_after_high",
+			path: `"\ud83d\ud83d"`,
+			err:  `parser: Unicode high surrogate must not follow a high surrogate at 1:13`,
+		},
+		{
+			name: "surrogate_then_char",
+			path: `"\ud83dx"`,
+			err:  `parser: Unicode low surrogate must follow a high surrogate at 1:8`,
+		},
+	} {
+		t.Run(tc.name, tc.run)
+	}
+}
+
+func TestJSONPathSpecialKeysString(t *testing.T) {
+	t.Parallel()
+
+	//nolint:paralleltest
+	for _, tc := range []testCase{
+		{
+			name: "quote",
+			path: `$."a\"b"`,
+			exp:  `$."a\"b"`,
+		},
+		{
+			name: "unicode_quote",
+			path: `$."a\u0022b"`,
+			exp:  `$."a\"b"`,
+		},
+		{
+			name: "backslash",
+			path: `$."a\\b"`,
+			exp:  `$."a\\b"`,
+		},
+		{
+			name: "newline",
+			path: `$."line\nbreak"`,
+			exp:  `$."line\nbreak"`,
+		},
+		{
+			name: "control",
+			path: `$."bell\x07\r"`,
+			exp:  `$."bell\u0007\r"`,
+		},
+		{
+			name: "non_bmp",
+			path: `$."😀"`,
+			exp:  `$."😀"`,
+		},
+		{
+			name: "non_bmp_brace_escape",
+			path: `$."\u{1F600}"`,
+			exp:  `$."😀"`,
+		},
+		{
+			name: "non_bmp_surrogate_pair",
+			path: `$."\ud83d\ude00"`,
+			exp:  `$."😀"`,
+		},
+		{
+			name: "all_together",
+			path: `$."\"\\\n😀".x[*]."\\"`,
+			exp:  `$."\"\\\n😀"."x"[*]."\\"`,
+		},
+		{
+			name: "bracket_string",
+			path: `$.a["b"]`,
+			err:  `parser: string "b" is not a valid array subscript; use ."b" to access an object member at 1:5`,
+		},
 	} {
 		t.Run(tc.name, tc.run)
 	}
 }
 
+func TestEscapeEquivalence(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		escaped string
+		literal string
+	}{
+		{"backspace", `"a\bz"`, "\"a\bz\""},
+		{"form_feed", `"a\fz"`, "\"a\fz\""},
+		{"carriage_return", `"a\rz"`, "\"a\rz\""},
+		{"tab", `"a\tz"`, "\"a\tz\""},
+		{"vertical_tab", `"a\vz"`, "\"a\vz\""},
+		{"backslash", `"a\\z"`, `"a\\z"`},
+		{"double_quote", `"a\"z"`, `"a\"z"`},
+		{"slash", `"a\/z"`, `"a/z"`},
+		{"hex", `"a\x41z"`, `"aAz"`},
+		{"unicode", `"\u00e9tat"`, `"état"`},
+		{"unicode_brace", `"\u{e9}tat"`, `"état"`},
+		{"surrogate_pair", `"\ud83d\ude00"`, `"😀"`},
+		{"brace_astral", `"\u{1F600}"`, `"😀"`},
+		{"key", `$."\u00e9tat"`, `$."état"`},
+		{"key_brace", `$."\u{1F600}"`, `$."😀"`},
+		{"key_ident", `$.\u00e9tat`, `$.état`},
+		{"key_ident_brace", `$.\u{e9}tat`, `$.état`},
+		{"key_ident_mixed", `$.\u00e9tat`, `$."état"`},
+		{"variable", `$"\u00e9tat"`, `$"état"`},
+		{"regex", `$ ? (@ like_regex "\u00e9\t")`, "$ ? (@ like_regex \"é\t\")"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			escaped, err := Parse(tc.escaped)
+			require.NoError(t, err)
+			literal, err := Parse(tc.literal)
+			require.NoError(t, err)
+			assert.Equal(t, literal, escaped)
+			assert.Equal(t, literal.String(), escaped.String())
+		})
+	}
+}
+
 func TestJSONPathFilterString(t *testing.T) {
 	// https://github.com/postgres/postgres/blob/REL_17_2/src/src/test/regress/sql/jsonpath.sql#L37-L50
 	t.Parallel()
@@ -585,6 +973,41 @@ func TestJSONPathDecimal(t *testing.T) {
 			path: `$.decimal(4,2,1)`,
 			err:  "parser: invalid input syntax: .decimal() can only have an optional precision[,scale] at 1:17",
 		},
+		{
+			name: "decimal_int32_range",
+			path: `$.decimal(2147483647,-2147483648)`,
+			exp:  `$.decimal(2147483647,-2147483648)`,
+		},
+		{
+			name: "decimal_precision_range",
+			path: `$.decimal(12345678901,1)`,
+			err:  "parser: precision of jsonpath item method .decimal() is out of integer range",
+		},
+		{
+			name: "decimal_plus_precision_range",
+			path: `$.decimal(+2147483648)`,
+			err:  "parser: precision of jsonpath item method .decimal() is out of integer range",
+		},
+		{
+			name: "decimal_minus_precision_range",
+			path: `$.decimal(-2147483649, 2)`,
+			err:  "parser: precision of jsonpath item method .decimal() is out of integer range",
+		},
+		{
+			name: "decimal_scale_range",
+			path: `$.decimal(1,12345678901)`,
+			err:  "parser: scale of jsonpath item method .decimal() is out of integer range",
+		},
+		{
+			name: "decimal_minus_scale_range",
+			path: `$.decimal(1,-2147483649)`,
+			err:  "parser: scale of jsonpath item method .decimal() is out of integer range",
+		},
+		{
+			name: "decimal_filter_scale_range",
+			path: `$ ? (@.decimal(5,2147483648) > 1)`,
+			err:  "parser: scale of jsonpath item method .decimal() is out of integer range",
+		},
 	} {
 		t.Run(tc.name, tc.run)
 	}
@@ -650,7 +1073,8 @@ func TestJSONPathRegexString(t *testing.T) {
 		{
 			name: "flag_xsms",
 			path: `$ ? (@ like_regex "pattern" flag "xsms")`,
-			err:  `parser: XQuery "x" flag (expanded regular expressions) is not implemented at 1:40`,
+			// pg: ERROR: XQuery "x" flag (expanded regular expressions) is not implemented
+			exp: `$?(@ like_regex "pattern" flag "smx")`,
 		},
 		{
 			name: "flag_q",
@@ -1034,22 +1458,22 @@ func TestJSONPathNumericLiteralsString(t *testing.T) {
 		{
 			name: "zero_dot_zero",
 			path: `0.0`,
-			exp:  `0`, // postgres: 0.00
+			exp:  `0.0`,
 		},
 		{
 			name: "zero_dot_000",
 			path: `0.000`,
-			exp:  `0`, // postgres: 0.00
+			exp:  `0.000`,
 		},
 		{
 			name: "float_expo_1",
 			path: `0.000e1`,
-			exp:  `0`, // postgres: 0.00
+			exp:  `0.00`,
 		},
 		{
 			name: "float_expo_2",
 			path: `0.000e2`,
-			exp:  `0`, // postgres: 0.00
+			exp:  `0.0`,
 		},
 		{
 			name: "float_expo_3",
@@ -1059,22 +1483,22 @@ func TestJSONPathNumericLiteralsString(t *testing.T) {
 		{
 			name: "0_dot_0010",
 			path: `0.0010`,
-			exp:  `0.001`, // postgres: 0.0010
+			exp:  `0.0010`,
 		},
 		{
 			name: "float_neg_expo_1",
 			path: `0.0010e-1`,
-			exp:  `0.0001`, // postgres: 0.00010
+			exp:  `0.00010`,
 		},
 		{
 			name: "float_pos_expo_1",
 			path: `0.0010e+1`,
-			exp:  `0.01`, // postgres: 0.010
+			exp:  `0.010`,
 		},
 		{
 			name: "float_pos_expo_2",
 			path: `0.0010e+2`,
-			exp:  `0.1`, // postgres: 0.10
+			exp:  `0.10`,
 		},
 		{
 			name: "dot_001",
@@ -1434,7 +1858,7 @@ func TestJSONPathEncodingString(t *testing.T) {
 		{
 			name: "two_highs",
 			path: `"\ud83d\ud83d"`, // 2 high surrogates in a row
-			err:  `parser: Unicode low surrogate must follow a high surrogate at 1:13`,
+			err:  `parser: Unicode high surrogate must not follow a high surrogate at 1:13`,
 		},
 		{
 			name: "wrong_order",
@@ -1516,7 +1940,7 @@ func TestJSONPathEncodingString(t *testing.T) {
 		{
 			name: "two_highs_key",
 			path: `$."\ud83d\ud83d"`, // 2 high surrogates in a row
-			err:  `parser: Unicode low surrogate must follow a high surrogate at 1:15`,
+			err:  `parser: Unicode high surrogate must not follow a high surrogate at 1:15`,
 		},
 		{
 			name: "wrong_order_key",
@@ -1616,6 +2040,71 @@ func TestNumericEdgeCases(t *testing.T) {
 	}
 }
 
+func TestRegisterMethod(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	// Unregistered methods are syntax errors. Use a name never registered,
+	// since registration outlives the test when run with -count.
+	_, err := Parse(`$.untrimmed()`)
+	r.EqualError(err, `parser: syntax error at 1:13`)
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		err    string
+	}{
+		{"empty", "", `parser: invalid method name ""`},
+		{"space", "a b", `parser: invalid method name "a b"`},
+		{"digit", "1a", `parser: invalid method name "1a"`},
+		{"escape", `\u0061`, `parser: invalid method name "\\u0061"`},
+		{"builtin", "size", `parser: method name "size" conflicts with a reserved word`},
+		{"builtin_case", "Double", `parser: method name "Double" conflicts with a reserved word`},
+		{"keyword", "strict", `parser: method name "strict" conflicts with a reserved word`},
+		{"literal", "null", `parser: method name "null" conflicts with a reserved word`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r.EqualError(RegisterMethod(tc.method), tc.err)
+		})
+	}
+
+	r.NoError(RegisterMethod("trimmed"))
+	r.NoError(RegisterMethod("trimmed"))
+	r.NoError(RegisterMethod("_ünïcode"))
+	a.True(isMethod("trimmed"))
+	a.False(isMethod("Trimmed"))
+
+	for _, tc := range []struct {
+		name string
+		path string
+		exp  string
+		err  string
+	}{
+		{"method", `$.trimmed()`, `$.trimmed()`, ""},
+		{"unicode", `$._ünïcode()`, `$._ünïcode()`, ""},
+		{"chain", `$.a.trimmed().size()`, `$."a".trimmed().size()`, ""},
+		{"filter", `$ ? (@.trimmed() == "x")`, `$?(@.trimmed() == "x")`, ""},
+		{"key", `$.trimmed`, `$."trimmed"`, ""},
+		{"quoted_key", `$."trimmed"`, `$."trimmed"`, ""},
+		{"case", `$.Trimmed()`, "", `parser: syntax error at 1:11`},
+		{"args", `$.trimmed(1)`, "", `parser: syntax error at 1:12`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := Parse(tc.path)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				a.Nil(path)
+				return
+			}
+			r.NoError(err)
+			a.Equal(tc.exp, path.String())
+		})
+	}
+}
+
 func TestDebugOutput(t *testing.T) {
 	t.Parallel()
 	node, _ := Parse("$.x + 2")
@@ -8,16 +8,17 @@ description of the SQL/JSON Path language.
 
 List of the PostgreSQL jsonpath functions and their path Package equivalents:
 
-  - @? Operator: Use [Path.Exists] with [exec.WithSilent]
-  - @@ Operator: Use [Path.Match] with [exec.Silent]
+  - @? Operator: Use [Path.AtQuestion]
+  - @@ Operator: Use [Path.AtAt]
   - jsonb_path_exists(): Use [Path.Exists]
   - jsonb_path_match(): Use [Path.Match]
-  - jsonb_path_query() and jsonb_path_query_array(): Use [Path.Query]
-  - jsonb_path_query_first(): Use [Path.First]
+  - jsonb_path_query(): Use [Path.Query]
+  - jsonb_path_query_array(): Use [Path.QueryArray]
+  - jsonb_path_query_first(): Use [Path.First] or [Path.FirstOrDefault]
   - jsonb_path_exists_tz(): Use [Path.Exists] with [exec.WithTZ]
   - jsonb_path_match_tz(): Use [Path.Match] with [exec.WithTZ]
-  - jsonb_path_query_tz() and jsonb_path_query_array_tz(): Use [Path.Query]
-    with [exec.WithTZ]
+  - jsonb_path_query_tz(): Use [Path.Query] with [exec.WithTZ]
+  - jsonb_path_query_array_tz(): Use [Path.QueryArray] with [exec.WithTZ]
   - jsonb_path_query_first_tz(): Use [Path.First] with [exec.WithTZ]
 
 # Options
@@ -26,6 +27,14 @@ The path query methods take an optional list of [exec.Option] arguments.
 
   - [exec.WithVars] provides named values to be substituted into the
     path expression. See the WithVars example for a demonstration.
+    [exec.WithVarsFrom] provides them from the fields of a Go struct or
+    other Go value, converting numbers of all types so that they compare
+    equal to path numbers, and nested structs to objects, as in
+    $conf.limits.max. [exec.WithRootVar] provides a whole document as a
+    single variable, without copying it, so that paths can join it with the
+    queried value, as in $.items[*] ? (@.price > $limits.max).
+    [exec.WithVarOverride] overrides a single variable without copying the
+    others, so that executions can share the same WithVars option.
 
   - [exec.WithSilent] suppresses [exec.ErrVerbose] errors, including missing
     object field or array element, unexpected JSON item type, and datetime
@@ -40,6 +49,82 @@ The path query methods take an optional list of [exec.Option] arguments.
     method. See the WithTZ example for a demonstration, and [types] for more
     comprehensive examples.
 
+  - [exec.WithDateStyle] specifies the [types.DateStyle] used by the
+    .string() method to format date and time values. Defaults to
+    [types.DateStyleISO]; use [types.DateStylePostgres] to reproduce the
+    output of PostgreSQL 16 and earlier with the "Postgres" DateStyle.
+
+  - [exec.WithStrictMutation] causes [Path.Replace] and [Path.Delete] to
+    return an error when the path selects no items.
+
+  - [exec.WithMaxDepth] limits the recursion depth of execution over
+    deeply-nested paths and JSON values, returning an error rather than
+    overflowing the stack. Defaults to [exec.DefaultMaxDepth].
+
+  - [exec.WithStructTags] allows execution against Go structs, pointers, and
+    typed slices and maps, traversed via reflection and honoring the named
+    struct tags, e.g., "json". See the WithStructTags example.
+
+  - [exec.WithPredicateCheck] causes [Path.Match] and [Path.AtAt] to return
+    an error for SQL standard path expressions, and the other query methods
+    to return an error for predicate check expressions (see
+    [Path.IsPredicate]). [exec.WithPredicateWarning] passes the latter errors
+    to a function instead of returning them.
+
+  - [exec.WithTimeValues] converts selected date and time values to
+    [time.Time] values, and [exec.WithStringDateTime] converts them to RFC
+    3339 strings, rather than returning [types.DateTime] values.
+
+  - [exec.WithCopyResults] returns deep copies of selected objects, arrays,
+    and date and time values, so that modifying results never modifies the
+    queried value. By default, objects and arrays selected from the queried
+    value are returned without copying.
+
+  - [exec.WithParallel] evaluates the path following a wildcard array
+    accessor across multiple goroutines for arrays with at least
+    [exec.DefaultParallelThreshold] elements, returning the same results
+    and errors as sequential evaluation.
+
+  - [exec.WithMethods] provides implementations of custom item methods for
+    a single execution, taking precedence over those registered by
+    [exec.RegisterMethod].
+
+  - [exec.WithTraceLimit] limits the number of steps recorded by
+    [exec.Explain], which executes a path and returns a trace of each step
+    to help debug paths that don't select the expected items.
+
+  - [exec.WithStats] fills in an [exec.Stats] with counters describing an
+    execution, such as the number of nodes visited, items matched, and
+    filters evaluated, along with its wall time, to monitor query costs.
+
+  - [exec.WithOrderedKeys] makes the wildcard member accessors .* and .**
+    visit object members in sorted key order, so that results are
+    reproducible. By default they follow Go's random map iteration order.
+
+  - [exec.WithMissingAsNull] makes member accessors in strict mode select
+    null for missing object keys rather than raise an error, so that strict
+    paths can query optional members. PostgreSQL has no equivalent.
+
+  - [exec.WithCaseInsensitiveKeys] makes member accessors match object keys
+    case-insensitively, preferring an exact match and otherwise the first
+    matching key in sorted order. PostgreSQL has no equivalent.
+
+  - [exec.WithLenientUTF8] makes string operations replace invalid UTF-8 in
+    strings built in Go with U+FFFD rather than raise an error. PostgreSQL
+    rejects invalid UTF-8 when it parses JSON.
+
+  - [exec.WithProvenance] makes [exec.Query] and [exec.QueryArray] return
+    [exec.Result] values that report whether execution constructed each
+    item or selected it from the queried value. PostgreSQL has no
+    equivalent.
+
+  - [exec.WithLogger] logs execution warnings to a [log/slog.Logger], and
+    [exec.WithLogSuppressed] also logs the structural errors lax mode
+    suppresses. PostgreSQL reports warnings as WARNING messages.
+
+  - [exec.WithStrict] and [exec.WithLax] execute a path in strict or lax
+    mode, taking precedence over its mode prefix without changing the path.
+
 # Two Types of Queries
 
 PostgreSQL supports two flavors of path expressions, and this package follows
@@ -64,9 +149,12 @@ this duality:
   - [Path.PgIndexOperator] returns a string representing the appropriate
     Postgres operator to use when sending queries to the database: @? for
     SQL-standard expressions and @@ for predicate check expressions.
-  - [Path.ExistsOrMatch] dispatches to the appropriate function, [Path.Exists]
-    or [Path.Match], depending on whether the path is a SQL standard or
-    predicate check expression.
+  - [Path.AtQuestion] and [Path.AtAt] implement the exact semantics of the
+    Postgres @? and @@ operators, which act like [Path.Exists] and
+    [Path.Match] with [exec.WithSilent].
+  - [Path.ExistsOrMatch] dispatches to the appropriate operator function,
+    [Path.AtQuestion] or [Path.AtAt], depending on whether the path is a SQL
+    standard or predicate check expression.
 
 # Errors
 
@@ -79,12 +167,31 @@ The path query methods return four types of errors:
   - [exec.ErrInvalid]: Usage errors due to flaws in the implementation,
     indicating a bug that needs fixing. Should be rare.
   - [exec.NULL]: Special error value returned by [Path.Exists] and [Path.Match]
-    when the result is unknown.
+    when the result is unknown. [Path.ExistsTri] and [Path.MatchTri] instead
+    return the [exec.Null] value of [exec.Ternary].
+
+Execution errors further wrap one of the categories [exec.ErrStructural],
+[exec.ErrType], [exec.ErrNumeric], [exec.ErrDateTime], or [exec.ErrVariable],
+so that [errors.Is] can tell, e.g., a missing key from a type mismatch. Use
+[exec.IsSuppressible] to determine whether [exec.WithSilent] would suppress
+an error. As in PostgreSQL, it suppresses structural, type, numeric, and
+datetime errors raised while evaluating the path against a value, including
+division by zero, but not references to undefined variables.
+
+Errors that wrap [exec.ErrExecution] raised while traversing a JSON value
+are [*exec.Error] values, which record where in the value the error occurred.
+Use [errors.As] to get its normalized path:
+
+	var execErr *exec.Error
+	if errors.As(err, &execErr) {
+		fmt.Println(execErr.Path()) // → $."a"."b"[2]."c"
+	}
 
 In addition, when [context.Context.Done] is closed in the context passed to a
-query function, the query will cease operation and return an
-[exec.ErrExecution] that wraps the [context.Canceled] and
-[context.DeadlineExceeded] error returned from [context.Context.Err].
+query function, the query will promptly cease operation, even while
+traversing deeply-nested values, and return an error that wraps the
+[context.Canceled] or [context.DeadlineExceeded] error returned from
+[context.Context.Err]. This error does not wrap [exec.ErrExecution].
 
 # Examples
 */
@@ -92,6 +199,7 @@ package path
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"fmt"
@@ -104,6 +212,9 @@ import (
 
 // Path provides SQL/JSON Path operations.
 type Path struct {
+	// AST is the parsed path. Pass it to the [exec] functions to execute
+	// the path without parsing it again. Use [New] to create a Path from an
+	// AST returned by [parser.Parse].
 	*ast.AST
 }
 
@@ -111,6 +222,14 @@ type Path struct {
 // properly.
 var _ types.DateTime = (*types.Time)(nil)
 
+// Make sure Path and NullPath implement the database/sql interfaces.
+var (
+	_ sql.Scanner   = (*Path)(nil)
+	_ driver.Valuer = (*Path)(nil)
+	_ sql.Scanner   = (*NullPath)(nil)
+	_ driver.Valuer = NullPath{}
+)
+
 var (
 	// ErrPath wraps parsing and execution errors.
 	ErrPath = errors.New("path")
@@ -121,7 +240,8 @@ var (
 
 // Parse parses path and returns the resulting Path. Returns an error on parse
 // failure. Returns an [ErrPath] error on parse failure (wraps
-// [parser.ErrParse]).
+// [parser.ErrParse]). Use [errors.As] to get the [*parser.SyntaxError]
+// describing the position of a syntax error.
 func Parse(path string) (*Path, error) {
 	ast, err := parser.Parse(path)
 	if err != nil {
@@ -211,16 +331,76 @@ func (path *Path) Match(ctx context.Context, json any, opt ...exec.Option) (bool
 	return exec.Match(ctx, path.AST, json, opt...)
 }
 
-// ExistsOrMatch dispatches SQL standard path expressions to [Exists] and
-// predicate check expressions to [Match], reducing the need to know which to
-// call. Results and options are the same as for those methods.
+// AtQuestion implements the semantics of the PostgreSQL @? operator. It's the
+// same as [Path.Exists] with the [exec.WithSilent] option, so that
+// structural and execution errors such as an array subscript out of bounds
+// in a strict path return false and [exec.NULL] rather than an error. See
+// [exec.AtQuestion] for details.
+func (path *Path) AtQuestion(ctx context.Context, json any, opt ...exec.Option) (bool, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.AtQuestion(ctx, path.AST, json, opt...)
+}
+
+// AtAt implements the semantics of the PostgreSQL @@ operator. It's the same
+// as [Path.Match] with the [exec.WithSilent] option, so that suppressible
+// errors and results other than a single boolean value return false and
+// [exec.NULL] rather than an error. See [exec.AtAt] for details.
+func (path *Path) AtAt(ctx context.Context, json any, opt ...exec.Option) (bool, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.AtAt(ctx, path.AST, json, opt...)
+}
+
+// ExistsOrMatch dispatches SQL standard path expressions to [Path.AtQuestion]
+// and predicate check expressions to [Path.AtAt], just as
+// [Path.PgIndexOperator] selects the @? or @@ operator, reducing the need to
+// know which to call. Results and options are the same as for those methods.
+// See [exec.ExistsOrMatch] for details.
 func (path *Path) ExistsOrMatch(ctx context.Context, json any, opt ...exec.Option) (bool, error) {
 	//nolint:wrapcheck // Okay to return unwrapped error
-	if path.IsPredicate() {
-		return exec.Match(ctx, path.AST, json, opt...)
-	}
+	return exec.ExistsOrMatch(ctx, path.AST, json, opt...)
+}
+
+// ExistsTri is like [Path.Exists], but returns [exec.Null] rather than the
+// [exec.NULL] error value when the result is unknown, mirroring the
+// three-valued result of jsonb_path_exists(). See [exec.ExistsTri] for
+// details.
+func (path *Path) ExistsTri(ctx context.Context, json any, opt ...exec.Option) (exec.Ternary, error) {
 	//nolint:wrapcheck // Okay to return unwrapped error
-	return exec.Exists(ctx, path.AST, json, opt...)
+	return exec.ExistsTri(ctx, path.AST, json, opt...)
+}
+
+// MatchTri is like [Path.Match], but returns [exec.Null] rather than the
+// [exec.NULL] error value when the result is unknown, mirroring the
+// three-valued result of jsonb_path_match(). See [exec.MatchTri] for
+// details.
+func (path *Path) MatchTri(ctx context.Context, json any, opt ...exec.Option) (exec.Ternary, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.MatchTri(ctx, path.AST, json, opt...)
+}
+
+// AtQuestionTri is like [Path.AtQuestion], but returns [exec.Null] rather
+// than the [exec.NULL] error value when the result is unknown. See
+// [exec.AtQuestionTri] for details.
+func (path *Path) AtQuestionTri(ctx context.Context, json any, opt ...exec.Option) (exec.Ternary, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.AtQuestionTri(ctx, path.AST, json, opt...)
+}
+
+// AtAtTri is like [Path.AtAt], but returns [exec.Null] rather than the
+// [exec.NULL] error value when the result is unknown. See [exec.AtAtTri]
+// for details.
+func (path *Path) AtAtTri(ctx context.Context, json any, opt ...exec.Option) (exec.Ternary, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.AtAtTri(ctx, path.AST, json, opt...)
+}
+
+// ExistsOrMatchTri is like [Path.ExistsOrMatch], but dispatches to
+// [Path.AtQuestionTri] or [Path.AtAtTri], returning [exec.Null] rather
+// than the [exec.NULL] error value when the result is unknown. See
+// [exec.ExistsOrMatchTri] for details.
+func (path *Path) ExistsOrMatchTri(ctx context.Context, json any, opt ...exec.Option) (exec.Ternary, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.ExistsOrMatchTri(ctx, path.AST, json, opt...)
 }
 
 // Query returns all JSON items returned by path for json. For SQL-standard
@@ -255,6 +435,142 @@ func (path *Path) First(ctx context.Context, json any, opt ...exec.Option) (any,
 	return exec.First(ctx, path.AST, json, opt...)
 }
 
+// QueryArray mirrors the PostgreSQL jsonb_path_query_array() function. It
+// is equivalent to [Query], returning an empty slice rather than an error
+// when the path selects no items. See the Options section for details on the
+// optional [exec.WithVars], [exec.WithTZ], and [exec.WithSilent] options; the
+// latter acts like the silent argument to jsonb_path_query_array().
+func (path *Path) QueryArray(ctx context.Context, json any, opt ...exec.Option) ([]any, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.QueryArray(ctx, path.AST, json, opt...)
+}
+
+// FirstOrDefault is like [First], but returns def instead of nil when path
+// returns no results for json. Useful to distinguish between no results and a
+// first result of JSON null. See the Options section for details on the
+// optional [exec.WithVars], [exec.WithTZ], and [exec.WithSilent] options.
+func (path *Path) FirstOrDefault(ctx context.Context, json, def any, opt ...exec.Option) (any, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.FirstOrDefault(ctx, path.AST, json, def, opt...)
+}
+
+// QueryLocations is like [Query], but returns the location of each item
+// selected by path from json, as a normalized path and a JSON Pointer, along
+// with its value. See [exec.QueryLocations] for details, and the Options
+// section for details on the optional [exec.WithVars], [exec.WithTZ], and
+// [exec.WithSilent] options.
+func (path *Path) QueryLocations(ctx context.Context, json any, opt ...exec.Option) ([]exec.Located, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.QueryLocations(ctx, path.AST, json, opt...)
+}
+
+// QueryText is like [Query], but returns each item selected by path from
+// json formatted as PostgreSQL formats jsonb values as text, for comparison
+// to the output of jsonb_path_query() in psql. See [exec.QueryText] for
+// details, and the Options section for details on the optional
+// [exec.WithVars], [exec.WithTZ], and [exec.WithSilent] options.
+func (path *Path) QueryText(ctx context.Context, json any, opt ...exec.Option) ([]string, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.QueryText(ctx, path.AST, json, opt...)
+}
+
+// QueryStrings is like [Query], but returns the items selected by path from
+// json as strings, and an error for any other item. See [exec.QueryStrings]
+// for details, and [exec.QueryAs] to convert items to other Go types.
+func (path *Path) QueryStrings(ctx context.Context, json any, opt ...exec.Option) ([]string, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.QueryStrings(ctx, path.AST, json, opt...)
+}
+
+// QueryInts is like [Query], but returns the items selected by path from
+// json as int64 values, and an error for any item that is not an integer
+// within range. See [exec.QueryInts] for details.
+func (path *Path) QueryInts(ctx context.Context, json any, opt ...exec.Option) ([]int64, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.QueryInts(ctx, path.AST, json, opt...)
+}
+
+// QueryFloats is like [Query], but returns the items selected by path from
+// json as float64 values, and an error for any item that is not a number.
+// See [exec.QueryFloats] for details.
+func (path *Path) QueryFloats(ctx context.Context, json any, opt ...exec.Option) ([]float64, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.QueryFloats(ctx, path.AST, json, opt...)
+}
+
+// QueryBools is like [Query], but returns the items selected by path from
+// json as bools, and an error for any item that is not a boolean. See
+// [exec.QueryBools] for details.
+func (path *Path) QueryBools(ctx context.Context, json any, opt ...exec.Option) ([]bool, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.QueryBools(ctx, path.AST, json, opt...)
+}
+
+// Project executes path against json and returns an object for each
+// selected item, with a member for each of columns, executed with the item
+// as its root, $. See [exec.Project] for details, and the Options section
+// for details on the optional [exec.WithVars], [exec.WithTZ], and
+// [exec.WithSilent] options.
+func (path *Path) Project(
+	ctx context.Context,
+	json any,
+	columns map[string]*Path,
+	opt ...exec.Option,
+) ([]map[string]any, error) {
+	cols := make(map[string]*ast.AST, len(columns))
+	for name, col := range columns {
+		cols[name] = col.AST
+	}
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.Project(ctx, path.AST, json, cols, opt...)
+}
+
+// Keys is like [Query], but returns the keys of the objects selected by path
+// from json, as if path ended in .keyvalue().key. See [exec.Keys] for
+// details, and the Options section for details on the optional
+// [exec.WithVars], [exec.WithTZ], and [exec.WithSilent] options.
+func (path *Path) Keys(ctx context.Context, json any, opt ...exec.Option) ([]string, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.Keys(ctx, path.AST, json, opt...)
+}
+
+// Values is like [Query], but returns the values of the objects selected by
+// path from json, as if path ended in .keyvalue().value. See [exec.Values]
+// for details, and the Options section for details on the optional
+// [exec.WithVars], [exec.WithTZ], and [exec.WithSilent] options.
+func (path *Path) Values(ctx context.Context, json any, opt ...exec.Option) ([]any, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.Values(ctx, path.AST, json, opt...)
+}
+
+// QueryBatch is like [Query], but executes path against each of values,
+// setting up execution only once, and returns an [exec.BatchResult] for
+// each. See [exec.QueryBatch] for details, and the Options section for
+// details on the optional [exec.WithVars], [exec.WithTZ], and
+// [exec.WithSilent] options.
+func (path *Path) QueryBatch(ctx context.Context, values []any, opt ...exec.Option) ([]exec.BatchResult, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.QueryBatch(ctx, path.AST, values, opt...)
+}
+
+// Replace returns a deep copy of json in which every item selected by path is
+// replaced with newValue, similar to the PostgreSQL jsonb_set() function.
+// json itself is not modified. Returns an error if path contains anything
+// other than accessors and filter expressions, or if it selects no items and
+// the [exec.WithStrictMutation] option is specified. See [exec.Replace] for
+// details.
+func (path *Path) Replace(ctx context.Context, json, newValue any, opt ...exec.Option) (any, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.Replace(ctx, path.AST, json, newValue, opt...)
+}
+
+// Delete is like [Replace], but removes the items selected by path from the
+// copy of json. See [exec.Delete] for details.
+func (path *Path) Delete(ctx context.Context, json any, opt ...exec.Option) (any, error) {
+	//nolint:wrapcheck // Okay to return unwrapped error
+	return exec.Delete(ctx, path.AST, json, opt...)
+}
+
 // Scan implements sql.Scanner so Paths can be read from databases
 // transparently. Currently, database types that map to string and []byte are
 // supported. Please consult database-specific driver documentation for
@@ -294,13 +610,48 @@ func (path *Path) Scan(src any) error {
 	return nil
 }
 
-// Value implements sql.Valuer so that Paths can be written to databases
+// Value implements driver.Valuer so that Paths can be written to databases
 // transparently. Currently, Paths map to strings. Please consult
-// database-specific driver documentation for matching types.
+// database-specific driver documentation for matching types. Returns nil, to
+// write NULL, for a nil Path or a Path without an AST, such as one that
+// scanned NULL.
 func (path *Path) Value() (driver.Value, error) {
+	if path == nil || path.AST == nil {
+		return nil, nil //nolint:nilnil // nil writes NULL.
+	}
 	return path.String(), nil
 }
 
+// NullPath represents a [Path] that may be NULL. NullPath implements the
+// sql.Scanner and driver.Valuer interfaces so it can be used as a scan
+// destination or query argument for nullable jsonpath columns, similar to
+// [sql.NullString].
+type NullPath struct {
+	Path  Path
+	Valid bool // Valid is true if Path is not NULL
+}
+
+// Scan implements sql.Scanner. It scans src into np.Path as [Path.Scan] does,
+// and sets np.Valid to true unless src is nil or empty. Returns [ErrScan] on
+// scan failure (and may wrap [parser.ErrParse]).
+func (np *NullPath) Scan(src any) error {
+	np.Path, np.Valid = Path{}, false
+	if err := np.Path.Scan(src); err != nil {
+		return err
+	}
+	np.Valid = np.Path.AST != nil
+	return nil
+}
+
+// Value implements driver.Valuer. Returns nil, to write NULL, if np.Valid is
+// false, and otherwise the string representation of np.Path.
+func (np NullPath) Value() (driver.Value, error) {
+	if !np.Valid {
+		return nil, nil //nolint:nilnil // nil writes NULL.
+	}
+	return np.Path.Value()
+}
+
 // MarshalText implements encoding.TextMarshaler.
 func (path *Path) MarshalText() ([]byte, error) {
 	return path.MarshalBinary()
@@ -2,6 +2,12 @@ package path
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"slices"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,11 +52,21 @@ func TestPath(t *testing.T) {
 		a.NotPanics(func() { res = MustQuery(tc.path, tc.json) })
 		a.Equal(tc.exp, res)
 
+		// Test QueryArray.
+		array, err := path.QueryArray(ctx, tc.json)
+		r.NoError(err)
+		a.Equal(tc.exp, array)
+
 		// Test First.
 		res, err = path.First(ctx, tc.json)
 		r.NoError(err)
 		a.Equal(tc.exp[0], res)
 
+		// Test FirstOrDefault.
+		res, err = path.FirstOrDefault(ctx, tc.json, "default")
+		r.NoError(err)
+		a.Equal(tc.exp[0], res)
+
 		// Tests Exists.
 		ok, err := path.Exists(ctx, tc.json, exec.WithSilent())
 		r.NoError(err)
@@ -63,10 +79,37 @@ func TestPath(t *testing.T) {
 			a.Equal(true, res)
 		}
 
+		// Tests AtQuestion or AtAt.
+		if path.IsPredicate() {
+			ok, err = path.AtAt(ctx, tc.json)
+		} else {
+			ok, err = path.AtQuestion(ctx, tc.json)
+		}
+		r.NoError(err)
+		a.True(ok)
+
 		// Tests ExistsOrMatch.
-		ok, err = path.ExistsOrMatch(ctx, tc.json, exec.WithSilent())
+		ok, err = path.ExistsOrMatch(ctx, tc.json)
 		r.NoError(err)
 		a.True(ok)
+
+		// Test the Ternary variants.
+		tri, err := path.ExistsTri(ctx, tc.json, exec.WithSilent())
+		r.NoError(err)
+		a.Equal(exec.True, tri)
+		if path.IsPredicate() {
+			tri, err = path.MatchTri(ctx, tc.json)
+			r.NoError(err)
+			a.Equal(exec.True, tri)
+			tri, err = path.AtAtTri(ctx, tc.json)
+		} else {
+			tri, err = path.AtQuestionTri(ctx, tc.json)
+		}
+		r.NoError(err)
+		a.Equal(exec.True, tri)
+		tri, err = path.ExistsOrMatchTri(ctx, tc.json)
+		r.NoError(err)
+		a.Equal(exec.True, tri)
 	}
 
 	for _, tc := range []testCase{
@@ -178,12 +221,36 @@ func TestQueryErrors(t *testing.T) {
 			r.ErrorIs(err, exec.ErrExecution)
 			a.Nil(res)
 
+			// Test QueryArray
+			array, err := path.QueryArray(context.Background(), tc.json)
+			r.EqualError(err, tc.err)
+			r.ErrorIs(err, exec.ErrExecution)
+			a.Nil(array)
+
 			// Test First
 			first, err := path.First(context.Background(), tc.json)
 			r.EqualError(err, tc.err)
 			r.ErrorIs(err, exec.ErrExecution)
 			a.Nil(first)
 
+			// Test FirstOrDefault
+			first, err = path.FirstOrDefault(context.Background(), tc.json, "default")
+			r.EqualError(err, tc.err)
+			r.ErrorIs(err, exec.ErrExecution)
+			a.Nil(first)
+
+			// Test Keys
+			keys, err := path.Keys(context.Background(), tc.json)
+			r.EqualError(err, tc.err)
+			r.ErrorIs(err, exec.ErrExecution)
+			a.Nil(keys)
+
+			// Test Values
+			values, err := path.Values(context.Background(), tc.json)
+			r.EqualError(err, tc.err)
+			r.ErrorIs(err, exec.ErrExecution)
+			a.Nil(values)
+
 			// Test MustQuery
 			a.PanicsWithError(tc.err, func() {
 				path.MustQuery(context.Background(), tc.json)
@@ -206,11 +273,33 @@ func TestQueryErrors(t *testing.T) {
 			r.ErrorIs(err, exec.ErrExecution)
 			a.False(ok)
 
-			// Test ExistsOrMatch
-			ok, err = path.Exists(context.Background(), tc.json)
-			r.EqualError(err, tc.err)
-			r.ErrorIs(err, exec.ErrExecution)
+			// Test AtQuestion, AtAt, and ExistsOrMatch, which suppress the error.
+			ok, err = path.AtQuestion(context.Background(), tc.json)
+			r.ErrorIs(err, exec.NULL)
+			a.False(ok)
+			ok, err = path.AtAt(context.Background(), tc.json)
+			r.ErrorIs(err, exec.NULL)
 			a.False(ok)
+			ok, err = path.ExistsOrMatch(context.Background(), tc.json)
+			r.ErrorIs(err, exec.NULL)
+			a.False(ok)
+
+			// Test the Ternary variants.
+			tri, err := path.MatchTri(context.Background(), tc.json)
+			r.EqualError(err, tc.err)
+			a.Equal(exec.Null, tri)
+			tri, err = path.ExistsTri(context.Background(), tc.json)
+			r.EqualError(err, tc.err)
+			a.Equal(exec.Null, tri)
+			tri, err = path.AtQuestionTri(context.Background(), tc.json)
+			r.NoError(err)
+			a.Equal(exec.Null, tri)
+			tri, err = path.AtAtTri(context.Background(), tc.json)
+			r.NoError(err)
+			a.Equal(exec.Null, tri)
+			tri, err = path.ExistsOrMatchTri(context.Background(), tc.json)
+			r.NoError(err)
+			a.Equal(exec.Null, tri)
 		})
 	}
 }
@@ -310,3 +399,514 @@ func TestScanNilPath(t *testing.T) {
 		r.ErrorIs(err, ErrScan)
 	})
 }
+
+func TestReplaceAndDelete(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	path := MustParse("$.a[*] ? (@ > 1)")
+	json := map[string]any{"a": []any{int64(1), int64(2), int64(3)}}
+
+	res, err := path.Replace(ctx, json, int64(0))
+	r.NoError(err)
+	a.Equal(map[string]any{"a": []any{int64(1), int64(0), int64(0)}}, res)
+
+	res, err = path.Delete(ctx, json)
+	r.NoError(err)
+	a.Equal(map[string]any{"a": []any{int64(1)}}, res)
+
+	// Original unchanged.
+	a.Equal(map[string]any{"a": []any{int64(1), int64(2), int64(3)}}, json)
+
+	// Strict mutation.
+	path = MustParse("$.b")
+	_, err = path.Replace(ctx, json, int64(0), exec.WithStrictMutation())
+	r.EqualError(err, "exec: jsonpath selected no items to modify")
+	r.ErrorIs(err, exec.ErrExecution)
+	_, err = path.Delete(ctx, json, exec.WithStrictMutation())
+	r.ErrorIs(err, exec.ErrExecution)
+}
+
+func TestPathAndExec(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+	json := map[string]any{"a": []any{int64(1), int64(2)}}
+	opt := []exec.Option{exec.WithVars(exec.Vars{"x": int64(1)})}
+
+	// Execute a Path's AST with exec.
+	path := MustParse("$.a[*] ? (@ > $x)")
+	ok, err := exec.Exists(ctx, path.AST, json, opt...)
+	r.NoError(err)
+	a.True(ok)
+	res, err := exec.Query(ctx, path.AST, json, opt...)
+	r.NoError(err)
+	a.Equal([]any{int64(2)}, res)
+
+	// Wrap an AST from the parser in a Path.
+	tree, err := parser.Parse("$.a[*] ? (@ > $x)")
+	r.NoError(err)
+	path = New(tree)
+	a.Same(tree, path.AST)
+	ok, err = path.Exists(ctx, json, opt...)
+	r.NoError(err)
+	a.True(ok)
+	val, err := path.Query(ctx, json, opt...)
+	r.NoError(err)
+	a.Equal([]any{int64(2)}, val)
+}
+
+func TestQueryBatch(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	path := MustParse("strict $.a")
+	values := []any{
+		map[string]any{"a": int64(1)},
+		map[string]any{"b": int64(2)},
+	}
+
+	res, err := path.QueryBatch(ctx, values)
+	r.NoError(err)
+	r.Len(res, 2)
+	a.Equal([]any{int64(1)}, res[0].Values)
+	r.NoError(res[0].Err)
+	a.Nil(res[1].Values)
+	r.EqualError(res[1].Err, `exec: JSON object does not contain key "a"`)
+
+	res, err = path.QueryBatch(ctx, values, exec.WithFailFast())
+	r.EqualError(err, `exec: JSON object does not contain key "a"`)
+	r.ErrorIs(err, exec.ErrExecution)
+	a.Len(res, 2)
+}
+
+func TestKeysAndValues(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	path := MustParse("$.a[*]")
+	json := map[string]any{"a": []any{
+		map[string]any{"y": int64(1), "x": "hi"},
+		map[string]any{"z": true},
+	}}
+
+	keys, err := path.Keys(ctx, json)
+	r.NoError(err)
+	a.Equal([]string{"x", "y", "z"}, keys)
+
+	values, err := path.Values(ctx, json)
+	r.NoError(err)
+	a.Equal([]any{"hi", int64(1), true}, values)
+
+	// Non-objects.
+	path = MustParse("strict $.a")
+	_, err = path.Keys(ctx, json)
+	r.EqualError(err, "exec: jsonpath item method .keyvalue() can only be applied to an object")
+	r.ErrorIs(err, exec.ErrExecution)
+	keys, err = path.Keys(ctx, json, exec.WithSilent())
+	r.NoError(err)
+	a.Empty(keys)
+}
+
+func TestQueryLocations(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	path := MustParse(`$.a[*] ? (@.x == "hi").y`)
+	json := map[string]any{"a": []any{
+		map[string]any{"x": "bye", "y": int64(1)},
+		map[string]any{"x": "hi", "y": int64(2)},
+	}}
+
+	locs, err := path.QueryLocations(ctx, json)
+	r.NoError(err)
+	a.Equal([]exec.Located{{Value: int64(2), Path: `$."a"[1]."y"`, Pointer: "/a/1/y"}}, locs)
+
+	// Errors.
+	path = MustParse("strict $.a[*].z")
+	_, err = path.QueryLocations(ctx, json)
+	r.EqualError(err, `exec: JSON object does not contain key "z"`)
+	r.ErrorIs(err, exec.ErrExecution)
+	locs, err = path.QueryLocations(ctx, json, exec.WithSilent())
+	r.NoError(err)
+	a.Empty(locs)
+}
+
+func TestQueryText(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	path := MustParse(`$.a[*] ? (@.x == "hi")`)
+	json := map[string]any{"a": []any{
+		map[string]any{"x": "bye", "yy": int64(1)},
+		map[string]any{"yy": []any{int64(2), "é"}, "x": "hi"},
+	}}
+
+	res, err := path.QueryText(ctx, json)
+	r.NoError(err)
+	a.Equal([]string{`{"x": "hi", "yy": [2, "é"]}`}, res)
+
+	// Errors.
+	path = MustParse("strict $.a[*].z")
+	_, err = path.QueryText(ctx, json)
+	r.EqualError(err, `exec: JSON object does not contain key "z"`)
+	r.ErrorIs(err, exec.ErrExecution)
+	res, err = path.QueryText(ctx, json, exec.WithSilent())
+	r.NoError(err)
+	a.Empty(res)
+}
+
+func TestQueryTyped(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	json := map[string]any{"a": []any{
+		map[string]any{"x": "hi", "n": int64(2), "f": 1.5, "b": true},
+		map[string]any{"x": "bye", "n": float64(3), "f": int64(2), "b": false},
+	}}
+
+	strs, err := MustParse("$.a[*].x").QueryStrings(ctx, json)
+	r.NoError(err)
+	a.Equal([]string{"hi", "bye"}, strs)
+
+	ints, err := MustParse("$.a[*].n").QueryInts(ctx, json)
+	r.NoError(err)
+	a.Equal([]int64{2, 3}, ints)
+
+	floats, err := MustParse("$.a[*].f").QueryFloats(ctx, json)
+	r.NoError(err)
+	a.Equal([]float64{1.5, 2}, floats)
+
+	bools, err := MustParse("$.a[*].b").QueryBools(ctx, json)
+	r.NoError(err)
+	a.Equal([]bool{true, false}, bools)
+
+	// Errors.
+	ints, err = MustParse("$.a[*].f").QueryInts(ctx, json)
+	r.EqualError(err, "exec: cannot convert result 0 of type float64 to int64: 1.5 is not an integer")
+	r.ErrorIs(err, exec.ErrNumeric)
+	a.Nil(ints)
+
+	bools, err = MustParse("$.a[*].x").QueryBools(ctx, json)
+	r.EqualError(err, "exec: cannot convert result 0 of type string to bool")
+	r.ErrorIs(err, exec.ErrType)
+	a.Nil(bools)
+}
+
+func TestProject(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := context.Background()
+
+	json := map[string]any{"items": []any{
+		map[string]any{"id": int64(1), "qty": int64(2), "name": "pen"},
+		map[string]any{"id": int64(2), "qty": int64(0), "name": "lamp"},
+	}}
+	path := MustParse(`$.items[*] ? (@.qty > 0)`)
+	res, err := path.Project(ctx, json, map[string]*Path{
+		"id":  MustParse("$.id"),
+		"qty": MustParse("$.qty"),
+	})
+	r.NoError(err)
+	a.Equal([]map[string]any{{"id": int64(1), "qty": int64(2)}}, res)
+
+	// Errors.
+	res, err = path.Project(ctx, json, map[string]*Path{"x": MustParse("strict $.x")})
+	r.EqualError(err, `exec: JSON object does not contain key "x" (column "x" of row 0)`)
+	r.ErrorIs(err, exec.ErrExecution)
+	a.Nil(res)
+}
+
+func TestEscapeQuery(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	json := map[string]any{
+		"état":       "ok",
+		"😀":          []any{"a\tb", "a\bb", "q\"\\", "é"},
+		"a/b":        int64(1),
+		"line\nfeed": "lf",
+	}
+
+	for _, tc := range []struct {
+		name    string
+		escaped string
+		literal string
+		exp     []any
+	}{
+		{"key", `$."\u00e9tat"`, `$."état"`, []any{"ok"}},
+		{"key_brace", `$."\u{e9}tat"`, `$.état`, []any{"ok"}},
+		{"key_ident", `$.\u00e9tat`, `$.état`, []any{"ok"}},
+		{"key_surrogates", `$."\ud83d\ude00"[0]`, `$."😀"[0]`, []any{"a\tb"}},
+		{"key_astral", `$."\u{1F600}"[1]`, `$."😀"[1]`, []any{"a\bb"}},
+		{"key_slash", `$."a\/b"`, `$."a/b"`, []any{int64(1)}},
+		{"key_newline", `$."line\nfeed"`, `$."line\u000afeed"`, []any{"lf"}},
+		{"tab", `$.*[*] ? (@ == "a\tb")`, "$.*[*] ? (@ == \"a\tb\")", []any{"a\tb"}},
+		{"backspace", `$.*[*] ? (@ == "a\bb")`, "$.*[*] ? (@ == \"a\bb\")", []any{"a\bb"}},
+		{"quote_backslash", `$.*[*] ? (@ == "q\"\\")`, `$.*[*] ? (@ == "q\"\\")`, []any{"q\"\\"}},
+		{"unicode", `$.*[*] ? (@ == "\u00e9")`, `$.*[*] ? (@ == "é")`, []any{"é"}},
+		{"starts_with", `$.* ? (@ starts with "\x6f")`, `$.* ? (@ starts with "o")`, []any{"ok"}},
+		{"regex", `$.*[*] ? (@ like_regex "^\u{e9}$")`, `$.*[*] ? (@ like_regex "^é$")`, []any{"é"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			escaped := MustParse(tc.escaped)
+			literal := MustParse(tc.literal)
+			assert.Equal(t, literal.String(), escaped.String())
+
+			res, err := escaped.Query(ctx, json)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, res)
+
+			res, err = literal.Query(ctx, json)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, res)
+		})
+	}
+}
+
+func TestNullPath(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		src   any
+		valid bool
+		str   string
+		err   string
+	}{
+		{name: "nil", src: nil},
+		{name: "empty_string", src: ""},
+		{name: "no_bytes", src: []byte{}},
+		{name: "string", src: "$.a[*] ? (@ > 1)", valid: true, str: `$."a"[*]?(@ > 1)`},
+		{name: "bytes", src: []byte("strict $.a"), valid: true, str: `strict $."a"`},
+		{name: "parse_error", src: "$ ?", err: "scan: parser: syntax error at 1:4"},
+		{name: "unknown_type", src: 42, err: "scan: unable to scan type int into Path"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			// Start with a valid path to make sure Scan resets it.
+			np := NullPath{Path: *MustParse("$.x"), Valid: true}
+			err := np.Scan(tc.src)
+			if tc.err != "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrScan)
+				a.False(np.Valid)
+				a.Nil(np.Path.AST)
+				return
+			}
+
+			r.NoError(err)
+			a.Equal(tc.valid, np.Valid)
+			val, err := np.Value()
+			r.NoError(err)
+			if !tc.valid {
+				a.Nil(np.Path.AST)
+				a.Nil(val)
+				return
+			}
+			a.Equal(tc.str, np.Path.String())
+			a.Equal(tc.str, val)
+		})
+	}
+
+	t.Run("null_path_value", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+
+		val, err := (*Path)(nil).Value()
+		r.NoError(err)
+		a.Nil(val)
+		val, err = new(Path).Value()
+		r.NoError(err)
+		a.Nil(val)
+
+		// Valid false ignores Path.
+		val, err = NullPath{Path: *MustParse("$")}.Value()
+		r.NoError(err)
+		a.Nil(val)
+	})
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	json := map[string]any{
+		"a": []any{int64(1), int64(2), int64(3)},
+		"b": map[string]any{"c": "hi", "d": nil},
+	}
+
+	for _, tc := range []struct {
+		name string
+		path string
+	}{
+		{"root", "$"},
+		{"strict", "strict $.a[1 to last]"},
+		{"filter", `$.a[*] ? (@ >= $min && @ != 3)`},
+		{"any", "lax $.a.**{0 to 1}"},
+		{"methods", "$.b.keyvalue().key"},
+		{"like_regex", `$.b.c ? (@ like_regex "^H" flag "i")`},
+		{"predicate", "$.a[*] > 2"},
+		{"arithmetic", "-$.a[0] + $.a.size() * 2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+			db := sql.OpenDB(&stubConnector{})
+			defer db.Close()
+
+			orig := MustParse(tc.path)
+			_, err := db.ExecContext(ctx, "INSERT", orig)
+			r.NoError(err)
+			_, err = db.ExecContext(ctx, "INSERT", NullPath{Path: *orig, Valid: true})
+			r.NoError(err)
+
+			rows, err := db.QueryContext(ctx, "SELECT")
+			r.NoError(err)
+			defer rows.Close()
+
+			r.True(rows.Next())
+			var path Path
+			r.NoError(rows.Scan(&path))
+			r.True(rows.Next())
+			var np NullPath
+			r.NoError(rows.Scan(&np))
+			r.True(np.Valid)
+			r.False(rows.Next())
+			r.NoError(rows.Err())
+
+			opt := exec.WithVars(exec.Vars{"min": int64(2)})
+			for _, got := range []*Path{&path, &np.Path} {
+				a.Equal(orig.String(), got.String())
+				a.Equal(orig.IsPredicate(), got.IsPredicate())
+				exp, expErr := orig.Query(ctx, json, opt)
+				res, err := got.Query(ctx, json, opt)
+				a.Equal(expErr, err)
+				a.Equal(exp, res)
+			}
+		})
+	}
+
+	t.Run("null", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		db := sql.OpenDB(&stubConnector{})
+		defer db.Close()
+
+		_, err := db.ExecContext(ctx, "INSERT", NullPath{})
+		r.NoError(err)
+		_, err = db.ExecContext(ctx, "INSERT", (*Path)(nil))
+		r.NoError(err)
+
+		rows, err := db.QueryContext(ctx, "SELECT")
+		r.NoError(err)
+		defer rows.Close()
+
+		for rows.Next() {
+			np := NullPath{Path: *MustParse("$"), Valid: true}
+			r.NoError(rows.Scan(&np))
+			a.False(np.Valid)
+			a.Nil(np.Path.AST)
+		}
+		r.NoError(rows.Err())
+	})
+
+	t.Run("parse_error", func(t *testing.T) {
+		t.Parallel()
+		a := assert.New(t)
+		r := require.New(t)
+		db := sql.OpenDB(&stubConnector{})
+		defer db.Close()
+
+		_, err := db.ExecContext(ctx, "INSERT", "$ ?")
+		r.NoError(err)
+
+		rows, err := db.QueryContext(ctx, "SELECT")
+		r.NoError(err)
+		defer rows.Close()
+
+		r.True(rows.Next())
+		var path Path
+		err = rows.Scan(&path)
+		r.ErrorIs(err, ErrScan)
+		r.ErrorIs(err, parser.ErrParse)
+		a.Nil(path.AST)
+	})
+}
+
+// stubConnector is a driver.Connector for an in-memory database with a
+// single column table. Its statements ignore the query: Exec appends its
+// argument to the table and Query selects all the values in the table,
+// returning strings as []byte as PostgreSQL drivers do.
+type stubConnector struct {
+	mu   sync.Mutex
+	vals []driver.Value
+}
+
+func (c *stubConnector) Connect(context.Context) (driver.Conn, error) { return &stubConn{c}, nil }
+func (c *stubConnector) Driver() driver.Driver                        { return stubDriver{} }
+
+type stubDriver struct{}
+
+func (stubDriver) Open(string) (driver.Conn, error) { return nil, errors.New("use stubConnector") }
+
+type stubConn struct{ db *stubConnector }
+
+func (c *stubConn) Prepare(string) (driver.Stmt, error) { return &stubStmt{c.db}, nil }
+func (c *stubConn) Close() error                        { return nil }
+func (c *stubConn) Begin() (driver.Tx, error)           { return nil, errors.New("no transactions") }
+
+type stubStmt struct{ db *stubConnector }
+
+func (s *stubStmt) Close() error  { return nil }
+func (s *stubStmt) NumInput() int { return -1 }
+
+func (s *stubStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.vals = append(s.db.vals, args[0])
+	return driver.RowsAffected(1), nil
+}
+
+func (s *stubStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return &stubRows{vals: slices.Clone(s.db.vals)}, nil
+}
+
+type stubRows struct{ vals []driver.Value }
+
+func (*stubRows) Columns() []string { return []string{"path"} }
+func (*stubRows) Close() error      { return nil }
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if len(r.vals) == 0 {
+		return io.EOF
+	}
+	dest[0] = r.vals[0]
+	if str, ok := dest[0].(string); ok {
+		dest[0] = []byte(str)
+	}
+	r.vals = r.vals[1:]
+	return nil
+}
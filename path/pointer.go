@@ -0,0 +1,281 @@
+package path
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/theory/sqljson/path/ast"
+)
+
+// BuildOption configures the Path created by [FromJSONPointer] and
+// [FromDotted].
+type BuildOption func(*builder)
+
+// WithStrict creates a strict mode Path rather than the default lax mode
+// Path.
+func WithStrict() BuildOption { return func(b *builder) { b.lax = false } }
+
+// builder assembles the nodes of a Path constructed from a JSON Pointer or
+// dotted path.
+type builder struct {
+	lax   bool
+	nodes []ast.Node
+}
+
+// newBuilder creates a builder configured by opt that starts with the root
+// node.
+func newBuilder(opt []BuildOption) *builder {
+	b := &builder{lax: true, nodes: []ast.Node{ast.NewConst(ast.ConstRoot)}}
+	for _, o := range opt {
+		o(b)
+	}
+	return b
+}
+
+// key appends a key accessor for name.
+func (b *builder) key(name string) {
+	b.nodes = append(b.nodes, ast.NewKey(name))
+}
+
+// index appends an array accessor for the array index idx, which must
+// contain only ASCII digits.
+func (b *builder) index(idx string) bool {
+	if n, err := strconv.ParseUint(idx, 10, 32); err != nil || n > math.MaxInt32 {
+		return false
+	}
+	b.nodes = append(b.nodes, ast.NewArrayIndex([]ast.Node{
+		ast.NewBinary(ast.BinarySubscript, ast.NewInteger(idx), nil),
+	}))
+	return true
+}
+
+// path creates the Path.
+func (b *builder) path() (*Path, error) {
+	tree, err := ast.New(b.lax, false, ast.LinkNodes(b.nodes))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPath, err)
+	}
+	return &Path{tree}, nil
+}
+
+// FromJSONPointer creates a Path equivalent to the [RFC 6901] JSON Pointer
+// ptr. Each reference token becomes a key accessor, except for array
+// indexes—"0" or digits without a leading zero—which become array
+// accessors. Thus "/a/b/0" becomes `$."a"."b"[0]`, and the empty string
+// becomes `$`. The escapes "~1" and "~0" are decoded to "/" and "~". The
+// Path is in lax mode unless configured by [WithStrict].
+//
+// Because the Path uses array accessors for array indexes, it won't select
+// object members with numeric names, except in lax mode when the index is
+// 0, which selects the object itself.
+//
+// [RFC 6901]: https://www.rfc-editor.org/rfc/rfc6901
+func FromJSONPointer(ptr string, opt ...BuildOption) (*Path, error) {
+	b := newBuilder(opt)
+	if ptr == "" {
+		return b.path()
+	}
+
+	rest, ok := strings.CutPrefix(ptr, "/")
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w: JSON pointer %q does not start with a slash",
+			ErrPath, ptr,
+		)
+	}
+
+	for _, tok := range strings.Split(rest, "/") {
+		if isArrayIndex(tok) {
+			if !b.index(tok) {
+				return nil, fmt.Errorf(
+					"%w: array index %v in JSON pointer %q is out of integer range",
+					ErrPath, tok, ptr,
+				)
+			}
+			continue
+		}
+
+		name, ok := unescapePointerToken(tok)
+		if !ok {
+			return nil, fmt.Errorf(
+				"%w: invalid escape in JSON pointer %q",
+				ErrPath, ptr,
+			)
+		}
+		b.key(name)
+	}
+
+	return b.path()
+}
+
+// isArrayIndex returns true if tok is an RFC 6901 array index: "0" or ASCII
+// digits without a leading zero.
+func isArrayIndex(tok string) bool {
+	if tok == "" || (tok[0] == '0' && len(tok) > 1) {
+		return false
+	}
+	for i := range len(tok) {
+		if tok[i] < '0' || tok[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// unescapePointerToken decodes the "~1" and "~0" escapes in the JSON
+// Pointer reference token tok. Returns false if tok contains any other
+// sequence starting with "~".
+func unescapePointerToken(tok string) (string, bool) {
+	if !strings.Contains(tok, "~") {
+		return tok, true
+	}
+
+	buf := new(strings.Builder)
+	for i := 0; i < len(tok); i++ {
+		if tok[i] != '~' {
+			buf.WriteByte(tok[i])
+			continue
+		}
+		i++
+		if i == len(tok) {
+			return "", false
+		}
+		switch tok[i] {
+		case '0':
+			buf.WriteByte('~')
+		case '1':
+			buf.WriteByte('/')
+		default:
+			return "", false
+		}
+	}
+	return buf.String(), true
+}
+
+// FromDotted creates a Path equivalent to the dotted path p, a list of
+// object member names separated by dots, each optionally followed by array
+// indexes in square brackets. Thus "a.b[0]" becomes `$."a"."b"[0]`, "[1]"
+// becomes `$[1]`, and the empty string becomes `$`. Names containing dots,
+// square brackets, or double quotes must be double-quoted, with backslashes
+// escaping double quotes and backslashes, e.g., `"a.b"."say \"hi\""`. The
+// Path is in lax mode unless configured by [WithStrict].
+func FromDotted(p string, opt ...BuildOption) (*Path, error) {
+	b := newBuilder(opt)
+	if p == "" {
+		return b.path()
+	}
+
+	d := dottedParser{src: p, b: b}
+	if err := d.parse(); err != nil {
+		return nil, fmt.Errorf("%w: %v in dotted path %q", ErrPath, err, p)
+	}
+	return b.path()
+}
+
+// dottedParser parses a dotted path into a builder.
+type dottedParser struct {
+	src string
+	pos int
+	b   *builder
+}
+
+// parse parses d.src. Returns an error describing the first syntax error.
+func (d *dottedParser) parse() error {
+	// A leading array index applies to the root.
+	if d.src[0] == '[' {
+		if err := d.indexes(); err != nil {
+			return err
+		}
+		if d.pos == len(d.src) {
+			return nil
+		}
+		if d.src[d.pos] != '.' {
+			return d.errorf("expected dot")
+		}
+		d.pos++
+	}
+
+	for {
+		if err := d.name(); err != nil {
+			return err
+		}
+		if err := d.indexes(); err != nil {
+			return err
+		}
+		if d.pos == len(d.src) {
+			return nil
+		}
+		if d.src[d.pos] != '.' {
+			return d.errorf("expected dot")
+		}
+		d.pos++
+	}
+}
+
+// name parses a plain or double-quoted member name.
+func (d *dottedParser) name() error {
+	if d.pos < len(d.src) && d.src[d.pos] == '"' {
+		return d.quotedName()
+	}
+
+	start := d.pos
+	for d.pos < len(d.src) && !strings.ContainsRune(`.[]"`, rune(d.src[d.pos])) {
+		d.pos++
+	}
+	if d.pos == start {
+		return d.errorf("expected name")
+	}
+	d.b.key(d.src[start:d.pos])
+	return nil
+}
+
+// quotedName parses a double-quoted member name.
+func (d *dottedParser) quotedName() error {
+	buf := new(strings.Builder)
+	for d.pos++; d.pos < len(d.src); d.pos++ {
+		switch c := d.src[d.pos]; c {
+		case '"':
+			d.pos++
+			d.b.key(buf.String())
+			return nil
+		case '\\':
+			d.pos++
+			if d.pos == len(d.src) || (d.src[d.pos] != '"' && d.src[d.pos] != '\\') {
+				return d.errorf("invalid escape")
+			}
+			buf.WriteByte(d.src[d.pos])
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return d.errorf("unterminated quoted name")
+}
+
+// indexes parses zero or more array indexes in square brackets.
+func (d *dottedParser) indexes() error {
+	for d.pos < len(d.src) && d.src[d.pos] == '[' {
+		start := d.pos + 1
+		end := strings.IndexByte(d.src[start:], ']')
+		if end < 0 {
+			return d.errorf("unterminated array index")
+		}
+		idx := d.src[start : start+end]
+		if !isArrayIndex(idx) {
+			return d.errorf("invalid array index")
+		}
+		if !d.b.index(idx) {
+			return d.errorf("array index out of integer range")
+		}
+		d.pos = start + end + 1
+	}
+	return nil
+}
+
+// errorf returns an error describing a syntax error at the current
+// position.
+func (d *dottedParser) errorf(msg string) error {
+	//nolint:err113
+	return fmt.Errorf("%v at offset %v", msg, d.pos)
+}
@@ -0,0 +1,443 @@
+package path
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pointerDoc is the JSON document used to test paths created by
+// FromJSONPointer and FromDotted.
+//
+//nolint:gochecknoglobals
+var pointerDoc = map[string]any{
+	"a": map[string]any{
+		"b": []any{"zero", "one", map[string]any{"c": true}},
+	},
+	"a.b":      "dotted",
+	"x/y":      "slashed",
+	"m~n":      "tilde",
+	"~1":       "escaped",
+	`say "hi"`: "quoted",
+	`back\`:    "backslash",
+	"[0]":      "bracketed",
+	"":         "empty",
+	"héllo":    map[string]any{"日本": "unicode"},
+	"0":        "zero key",
+	"list":     []any{[]any{int64(1), int64(2)}, []any{int64(3)}},
+}
+
+func TestFromJSONPointer(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name string
+		ptr  string
+		opt  []BuildOption
+		exp  string
+		res  []any
+		err  string
+	}{
+		{
+			name: "root",
+			ptr:  "",
+			exp:  `$`,
+			res:  []any{pointerDoc},
+		},
+		{
+			name: "keys_and_index",
+			ptr:  "/a/b/0",
+			exp:  `$."a"."b"[0]`,
+			res:  []any{"zero"},
+		},
+		{
+			name: "nested_index",
+			ptr:  "/a/b/2/c",
+			exp:  `$."a"."b"[2]."c"`,
+			res:  []any{true},
+		},
+		{
+			name: "strict",
+			ptr:  "/a/b/1",
+			opt:  []BuildOption{WithStrict()},
+			exp:  `strict $."a"."b"[1]`,
+			res:  []any{"one"},
+		},
+		{
+			name: "strict_missing",
+			ptr:  "/a/nope",
+			opt:  []BuildOption{WithStrict()},
+			exp:  `strict $."a"."nope"`,
+			err:  `exec: JSON object does not contain key "nope"`,
+		},
+		{
+			name: "lax_missing",
+			ptr:  "/a/nope",
+			exp:  `$."a"."nope"`,
+			res:  []any{},
+		},
+		{
+			name: "dot",
+			ptr:  "/a.b",
+			exp:  `$."a.b"`,
+			res:  []any{"dotted"},
+		},
+		{
+			name: "slash",
+			ptr:  "/x~1y",
+			exp:  `$."x/y"`,
+			res:  []any{"slashed"},
+		},
+		{
+			name: "tilde",
+			ptr:  "/m~0n",
+			exp:  `$."m~n"`,
+			res:  []any{"tilde"},
+		},
+		{
+			name: "escaped_escape",
+			ptr:  "/~01",
+			exp:  `$."~1"`,
+			res:  []any{"escaped"},
+		},
+		{
+			name: "quotes",
+			ptr:  `/say "hi"`,
+			exp:  `$."say \"hi\""`,
+			res:  []any{"quoted"},
+		},
+		{
+			name: "backslash",
+			ptr:  `/back\`,
+			exp:  `$."back\\"`,
+			res:  []any{"backslash"},
+		},
+		{
+			name: "brackets",
+			ptr:  "/[0]",
+			exp:  `$."[0]"`,
+			res:  []any{"bracketed"},
+		},
+		{
+			name: "empty_key",
+			ptr:  "/",
+			exp:  `$.""`,
+			res:  []any{"empty"},
+		},
+		{
+			name: "unicode",
+			ptr:  "/héllo/日本",
+			exp:  `$."héllo"."日本"`,
+			res:  []any{"unicode"},
+		},
+		{
+			name: "leading_zero_key",
+			ptr:  "/a/b/01",
+			exp:  `$."a"."b"."01"`,
+			res:  []any{},
+		},
+		{
+			name: "dash_key",
+			ptr:  "/a/b/-",
+			exp:  `$."a"."b"."-"`,
+			res:  []any{},
+		},
+		{
+			name: "nested_arrays",
+			ptr:  "/list/0/1",
+			exp:  `$."list"[0][1]`,
+			res:  []any{int64(2)},
+		},
+		{
+			name: "max_index",
+			ptr:  "/list/2147483647",
+			exp:  `$."list"[2147483647]`,
+			res:  []any{},
+		},
+		{
+			name: "no_slash",
+			ptr:  "a/b",
+			err:  `path: JSON pointer "a/b" does not start with a slash`,
+		},
+		{
+			name: "bad_escape",
+			ptr:  "/a~2",
+			err:  `path: invalid escape in JSON pointer "/a~2"`,
+		},
+		{
+			name: "trailing_tilde",
+			ptr:  "/a~",
+			err:  `path: invalid escape in JSON pointer "/a~"`,
+		},
+		{
+			name: "index_too_big",
+			ptr:  "/list/2147483648",
+			err:  `path: array index 2147483648 in JSON pointer "/list/2147483648" is out of integer range`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := FromJSONPointer(tc.ptr, tc.opt...)
+			if tc.exp == "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrPath)
+				a.Nil(path)
+				return
+			}
+			r.NoError(err)
+			checkBuiltPath(ctx, a, r, path, tc.exp, tc.res, tc.err)
+		})
+	}
+}
+
+func TestFromDotted(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name string
+		path string
+		opt  []BuildOption
+		exp  string
+		res  []any
+		err  string
+	}{
+		{
+			name: "root",
+			path: "",
+			exp:  `$`,
+			res:  []any{pointerDoc},
+		},
+		{
+			name: "keys_and_index",
+			path: "a.b[0]",
+			exp:  `$."a"."b"[0]`,
+			res:  []any{"zero"},
+		},
+		{
+			name: "nested_index",
+			path: "a.b[2].c",
+			exp:  `$."a"."b"[2]."c"`,
+			res:  []any{true},
+		},
+		{
+			name: "multiple_indexes",
+			path: "list[0][1]",
+			exp:  `$."list"[0][1]`,
+			res:  []any{int64(2)},
+		},
+		{
+			name: "root_index",
+			path: "[1]",
+			exp:  `$[1]`,
+			res:  []any{},
+		},
+		{
+			name: "root_index_key",
+			path: "[0].a.b[1]",
+			exp:  `$[0]."a"."b"[1]`,
+			res:  []any{"one"},
+		},
+		{
+			name: "strict",
+			path: "a.b[1]",
+			opt:  []BuildOption{WithStrict()},
+			exp:  `strict $."a"."b"[1]`,
+			res:  []any{"one"},
+		},
+		{
+			name: "strict_out_of_bounds",
+			path: "a.b[3]",
+			opt:  []BuildOption{WithStrict()},
+			exp:  `strict $."a"."b"[3]`,
+			err:  `exec: jsonpath array subscript is out of bounds`,
+		},
+		{
+			name: "quoted_dot",
+			path: `"a.b"`,
+			exp:  `$."a.b"`,
+			res:  []any{"dotted"},
+		},
+		{
+			name: "slash",
+			path: "x/y",
+			exp:  `$."x/y"`,
+			res:  []any{"slashed"},
+		},
+		{
+			name: "tilde",
+			path: "m~n",
+			exp:  `$."m~n"`,
+			res:  []any{"tilde"},
+		},
+		{
+			name: "quoted_quotes",
+			path: `"say \"hi\""`,
+			exp:  `$."say \"hi\""`,
+			res:  []any{"quoted"},
+		},
+		{
+			name: "quoted_backslash",
+			path: `"back\\"`,
+			exp:  `$."back\\"`,
+			res:  []any{"backslash"},
+		},
+		{
+			name: "plain_backslash",
+			path: `back\`,
+			exp:  `$."back\\"`,
+			res:  []any{"backslash"},
+		},
+		{
+			name: "quoted_brackets",
+			path: `"[0]"`,
+			exp:  `$."[0]"`,
+			res:  []any{"bracketed"},
+		},
+		{
+			name: "quoted_empty",
+			path: `""`,
+			exp:  `$.""`,
+			res:  []any{"empty"},
+		},
+		{
+			name: "quoted_then_index",
+			path: `"a".b[0]`,
+			exp:  `$."a"."b"[0]`,
+			res:  []any{"zero"},
+		},
+		{
+			name: "unicode",
+			path: "héllo.日本",
+			exp:  `$."héllo"."日本"`,
+			res:  []any{"unicode"},
+		},
+		{
+			name: "numeric_key",
+			path: "0",
+			exp:  `$."0"`,
+			res:  []any{"zero key"},
+		},
+		{
+			name: "spaces",
+			path: "a b",
+			exp:  `$."a b"`,
+			res:  []any{},
+		},
+		{
+			name: "leading_dot",
+			path: ".a",
+			err:  `path: expected name at offset 0 in dotted path ".a"`,
+		},
+		{
+			name: "trailing_dot",
+			path: "a.",
+			err:  `path: expected name at offset 2 in dotted path "a."`,
+		},
+		{
+			name: "double_dot",
+			path: "a..b",
+			err:  `path: expected name at offset 2 in dotted path "a..b"`,
+		},
+		{
+			name: "bad_index",
+			path: "a[x]",
+			err:  `path: invalid array index at offset 1 in dotted path "a[x]"`,
+		},
+		{
+			name: "negative_index",
+			path: "a[-1]",
+			err:  `path: invalid array index at offset 1 in dotted path "a[-1]"`,
+		},
+		{
+			name: "empty_index",
+			path: "a[]",
+			err:  `path: invalid array index at offset 1 in dotted path "a[]"`,
+		},
+		{
+			name: "index_too_big",
+			path: "a[2147483648]",
+			err:  `path: array index out of integer range at offset 1 in dotted path "a[2147483648]"`,
+		},
+		{
+			name: "unterminated_index",
+			path: "a[1",
+			err:  `path: unterminated array index at offset 1 in dotted path "a[1"`,
+		},
+		{
+			name: "stray_bracket",
+			path: "a]",
+			err:  `path: expected dot at offset 1 in dotted path "a]"`,
+		},
+		{
+			name: "text_after_quote",
+			path: `"a"b`,
+			err:  `path: expected dot at offset 3 in dotted path "\"a\"b"`,
+		},
+		{
+			name: "text_after_root_index",
+			path: `[0]a`,
+			err:  `path: expected dot at offset 3 in dotted path "[0]a"`,
+		},
+		{
+			name: "unterminated_quote",
+			path: `"a`,
+			err:  `path: unterminated quoted name at offset 2 in dotted path "\"a"`,
+		},
+		{
+			name: "bad_escape",
+			path: `"a\n"`,
+			err:  `path: invalid escape at offset 3 in dotted path "\"a\\n\""`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			path, err := FromDotted(tc.path, tc.opt...)
+			if tc.exp == "" {
+				r.EqualError(err, tc.err)
+				r.ErrorIs(err, ErrPath)
+				a.Nil(path)
+				return
+			}
+			r.NoError(err)
+			checkBuiltPath(ctx, a, r, path, tc.exp, tc.res, tc.err)
+		})
+	}
+}
+
+// checkBuiltPath checks that path stringifies to exp, that exp parses to an
+// identical path, and that both return res or the execution error err when
+// queried against pointerDoc.
+func checkBuiltPath(
+	ctx context.Context,
+	a *assert.Assertions,
+	r *require.Assertions,
+	path *Path,
+	exp string,
+	res []any,
+	err string,
+) {
+	a.Equal(exp, path.String())
+	parsed, parseErr := Parse(exp)
+	r.NoError(parseErr)
+	a.Equal(parsed, path)
+
+	for _, p := range []*Path{path, parsed} {
+		got, qErr := p.Query(ctx, pointerDoc)
+		if err != "" {
+			r.EqualError(qErr, err)
+			continue
+		}
+		r.NoError(qErr)
+		a.Equal(res, got)
+	}
+}
@@ -290,6 +290,12 @@ func Example_string() {
 	// ["2023-08-15"]
 }
 
+func Example_string_date_style() {
+	opt := exec.WithDateStyle(types.DateStylePostgres)
+	pp(path.MustQuery("$.timestamp().string()", "2023-08-15 12:34:56", opt)) // → ["Tue Aug 15 12:34:56 2023"]
+	// Output: ["Tue Aug 15 12:34:56 2023"]
+}
+
 func Example_double() {
 	pp(path.MustQuery("$.len.double() * 2", val(`{"len": "1.9"}`))) // → [3.8]
 	// Output: [3.8]
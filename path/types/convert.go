@@ -0,0 +1,256 @@
+package types
+
+import (
+	"context"
+	"fmt"
+)
+
+// ConversionError reports that a [DateTime] value cannot be converted to
+// another type, either because PostgreSQL does not support the conversion,
+// or because it requires time zone usage and the caller disabled it.
+type ConversionError struct {
+	// From is the name of the PostgreSQL type converted from, e.g., "date".
+	From string
+	// To is the name of the PostgreSQL type converted to, e.g., "timestamptz".
+	To string
+	// TZRequired indicates that the conversion is supported, but only with
+	// time zone usage.
+	TZRequired bool
+}
+
+// Error returns the error message.
+func (e *ConversionError) Error() string {
+	if e.TZRequired {
+		return fmt.Sprintf(
+			"cannot convert value from %v to %v without time zone usage",
+			e.From, e.To,
+		)
+	}
+	return fmt.Sprintf("cannot convert value from %v to %v", e.From, e.To)
+}
+
+// Unwrap returns [ErrSQLType].
+func (e *ConversionError) Unwrap() error { return ErrSQLType }
+
+// unsupported returns an error reporting that dt is not one of the types
+// defined by this package.
+func unsupported(dt DateTime) error {
+	return fmt.Errorf("%w: unsupported DateTime type %T", ErrSQLType, dt)
+}
+
+// typeName returns the name of the PostgreSQL type for dt, or false if dt is
+// not one of the types defined by this package.
+func typeName(dt DateTime) (string, bool) {
+	switch dt.(type) {
+	case *Date:
+		return "date", true
+	case *Time:
+		return "time", true
+	case *TimeTZ:
+		return "timetz", true
+	case *Timestamp:
+		return "timestamp", true
+	case *TimestampTZ:
+		return "timestamptz", true
+	default:
+		return "", false
+	}
+}
+
+// convErr returns a [ConversionError] reporting that from cannot be
+// converted to the type named to. Returns an unsupported type error if from
+// is not one of the types defined by this package.
+func convErr(from DateTime, to string, tzRequired bool) error {
+	name, ok := typeName(from)
+	if !ok {
+		return unsupported(from)
+	}
+	return &ConversionError{From: name, To: to, TZRequired: tzRequired}
+}
+
+// ToDate converts dt to [Date] in the time zone in ctx. Returns a
+// [ConversionError] if dt is a [Time] or [TimeTZ], or if it's a
+// [TimestampTZ] and useTZ is false.
+func ToDate(ctx context.Context, dt DateTime, useTZ bool) (*Date, error) {
+	switch dt := dt.(type) {
+	case *Date:
+		return dt, nil
+	case *Timestamp:
+		return dt.ToDate(ctx), nil
+	case *TimestampTZ:
+		if !useTZ {
+			return nil, convErr(dt, "date", true)
+		}
+		return dt.ToDate(ctx), nil
+	default:
+		return nil, convErr(dt, "date", false)
+	}
+}
+
+// ToTime converts dt to [Time] in the time zone in ctx. Returns a
+// [ConversionError] if dt is a [Date], or if it's a [TimeTZ] or
+// [TimestampTZ] and useTZ is false.
+func ToTime(ctx context.Context, dt DateTime, useTZ bool) (*Time, error) {
+	switch dt := dt.(type) {
+	case *Time:
+		return dt, nil
+	case *TimeTZ:
+		if !useTZ {
+			return nil, convErr(dt, "time", true)
+		}
+		return dt.ToTime(ctx), nil
+	case *Timestamp:
+		return dt.ToTime(ctx), nil
+	case *TimestampTZ:
+		if !useTZ {
+			return nil, convErr(dt, "time", true)
+		}
+		return dt.ToTime(ctx), nil
+	default:
+		return nil, convErr(dt, "time", false)
+	}
+}
+
+// ToTimeTZ converts dt to [TimeTZ] in the time zone in ctx. Returns a
+// [ConversionError] if dt is a [Date] or [Timestamp], or if it's a [Time] and
+// useTZ is false.
+func ToTimeTZ(ctx context.Context, dt DateTime, useTZ bool) (*TimeTZ, error) {
+	switch dt := dt.(type) {
+	case *Time:
+		if !useTZ {
+			return nil, convErr(dt, "timetz", true)
+		}
+		return dt.ToTimeTZ(ctx), nil
+	case *TimeTZ:
+		return dt, nil
+	case *TimestampTZ:
+		return dt.ToTimeTZ(ctx), nil
+	default:
+		return nil, convErr(dt, "timetz", false)
+	}
+}
+
+// ToTimestamp converts dt to [Timestamp] in the time zone in ctx. Returns a
+// [ConversionError] if dt is a [Time] or [TimeTZ], or if it's a
+// [TimestampTZ] and useTZ is false.
+func ToTimestamp(ctx context.Context, dt DateTime, useTZ bool) (*Timestamp, error) {
+	switch dt := dt.(type) {
+	case *Date:
+		return dt.ToTimestamp(ctx), nil
+	case *Timestamp:
+		return dt, nil
+	case *TimestampTZ:
+		if !useTZ {
+			return nil, convErr(dt, "timestamp", true)
+		}
+		return dt.ToTimestamp(ctx), nil
+	default:
+		return nil, convErr(dt, "timestamp", false)
+	}
+}
+
+// ToTimestampTZ converts dt to [TimestampTZ] in the time zone in ctx.
+// Returns a [ConversionError] if dt is a [Time] or [TimeTZ], or if it's a
+// [Date] or [Timestamp] and useTZ is false.
+func ToTimestampTZ(ctx context.Context, dt DateTime, useTZ bool) (*TimestampTZ, error) {
+	switch dt := dt.(type) {
+	case *Date:
+		if !useTZ {
+			return nil, convErr(dt, "timestamptz", true)
+		}
+		return dt.ToTimestampTZ(ctx), nil
+	case *Timestamp:
+		if !useTZ {
+			return nil, convErr(dt, "timestamptz", true)
+		}
+		return dt.ToTimestampTZ(ctx), nil
+	case *TimestampTZ:
+		return dt, nil
+	default:
+		return nil, convErr(dt, "timestamptz", false)
+	}
+}
+
+// Compare compares a to b the way PostgreSQL compares SQL/JSON datetime
+// values, returning -1 if a is before b, +1 if a is after b, and 0 if they're
+// the same. As in PostgreSQL, comparing a value without a time zone to a
+// value with a time zone first converts the former in the time zone in ctx,
+// and requires useTZ to be true.
+//
+// Returns a [ConversionError] if a and b are not comparable, such as a [Date]
+// and a [Time], or if comparing them requires time zone usage and useTZ is
+// false. For the latter, From always names the type without a time zone.
+func Compare(ctx context.Context, a, b DateTime, useTZ bool) (int, error) {
+	switch a := a.(type) {
+	case *Date:
+		switch b := b.(type) {
+		case *Date:
+			return a.Compare(b.Time), nil
+		case *Timestamp:
+			return a.Compare(b.Time), nil
+		case *TimestampTZ:
+			if !useTZ {
+				return 0, convErr(a, "timestamptz", true)
+			}
+			return a.ToTimestampTZ(ctx).Compare(b.Time), nil
+		}
+	case *Time:
+		switch b := b.(type) {
+		case *Time:
+			return a.Compare(b.Time), nil
+		case *TimeTZ:
+			if !useTZ {
+				return 0, convErr(a, "timetz", true)
+			}
+			// TimeTZ has special comparison rules, so use its Compare
+			// method and invert the result.
+			return -b.Compare(a.ToTimeTZ(ctx).Time), nil
+		}
+	case *TimeTZ:
+		switch b := b.(type) {
+		case *Time:
+			if !useTZ {
+				return 0, convErr(b, "timetz", true)
+			}
+			return a.Compare(b.ToTimeTZ(ctx).Time), nil
+		case *TimeTZ:
+			return a.Compare(b.Time), nil
+		}
+	case *Timestamp:
+		switch b := b.(type) {
+		case *Date:
+			return a.Compare(b.Time), nil
+		case *Timestamp:
+			return a.Compare(b.Time), nil
+		case *TimestampTZ:
+			if !useTZ {
+				return 0, convErr(a, "timestamptz", true)
+			}
+			return a.ToTimestampTZ(ctx).Compare(b.Time), nil
+		}
+	case *TimestampTZ:
+		switch b := b.(type) {
+		case *Date:
+			if !useTZ {
+				return 0, convErr(b, "timestamptz", true)
+			}
+			return a.Compare(b.ToTimestampTZ(ctx).Time), nil
+		case *Timestamp:
+			if !useTZ {
+				return 0, convErr(b, "timestamptz", true)
+			}
+			return a.Compare(b.ToTimestampTZ(ctx).Time), nil
+		case *TimestampTZ:
+			return a.Compare(b.Time), nil
+		}
+	default:
+		return 0, unsupported(a)
+	}
+
+	// Incomparable types.
+	to, ok := typeName(b)
+	if !ok {
+		return 0, unsupported(b)
+	}
+	return 0, convErr(a, to, false)
+}
@@ -0,0 +1,357 @@
+package types
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// convertTestValues returns one value of each DateTime type.
+func convertTestValues(ctx context.Context) map[string]DateTime {
+	moment := time.Date(2024, 6, 6, 1, 48, 22, 0, time.FixedZone("", 5*secondsPerHour+1800))
+	return map[string]DateTime{
+		"date":        NewDate(moment),
+		"time":        NewTime(moment),
+		"timetz":      NewTimeTZ(moment),
+		"timestamp":   NewTimestamp(moment),
+		"timestamptz": NewTimestampTZ(ctx, moment),
+	}
+}
+
+// mockDateTime is a DateTime not defined by this package.
+type mockDateTime struct{}
+
+func (mockDateTime) GoTime() time.Time            { return time.Time{} }
+func (mockDateTime) String() string               { return "" }
+func (mockDateTime) FormatStyle(DateStyle) string { return "" }
+
+func TestConversionError(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	err := &ConversionError{From: "date", To: "time"}
+	a.EqualError(err, "cannot convert value from date to time")
+	a.ErrorIs(err, ErrSQLType)
+
+	err = &ConversionError{From: "time", To: "timetz", TZRequired: true}
+	a.EqualError(err, "cannot convert value from time to timetz without time zone usage")
+	a.ErrorIs(err, ErrSQLType)
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+	ctx := ContextWithTZ(context.Background(), time.FixedZone("", -7*secondsPerHour))
+	values := convertTestValues(ctx)
+	convert := map[string]func(DateTime, bool) (DateTime, error){
+		"date": func(dt DateTime, useTZ bool) (DateTime, error) {
+			return ToDate(ctx, dt, useTZ)
+		},
+		"time": func(dt DateTime, useTZ bool) (DateTime, error) {
+			return ToTime(ctx, dt, useTZ)
+		},
+		"timetz": func(dt DateTime, useTZ bool) (DateTime, error) {
+			return ToTimeTZ(ctx, dt, useTZ)
+		},
+		"timestamp": func(dt DateTime, useTZ bool) (DateTime, error) {
+			return ToTimestamp(ctx, dt, useTZ)
+		},
+		"timestamptz": func(dt DateTime, useTZ bool) (DateTime, error) {
+			return ToTimestampTZ(ctx, dt, useTZ)
+		},
+	}
+
+	for _, tc := range []struct {
+		from string
+		to   string
+		exp  string // empty when the conversion is not supported
+		tz   bool   // conversion requires time zone usage
+	}{
+		{from: "date", to: "date", exp: "2024-06-06"},
+		{from: "date", to: "time"},
+		{from: "date", to: "timetz"},
+		{from: "date", to: "timestamp", exp: "2024-06-06T00:00:00"},
+		{from: "date", to: "timestamptz", exp: "2024-06-06T00:00:00-07:00", tz: true},
+		{from: "time", to: "date"},
+		{from: "time", to: "time", exp: "01:48:22"},
+		{from: "time", to: "timetz", exp: "01:48:22-07:00", tz: true},
+		{from: "time", to: "timestamp"},
+		{from: "time", to: "timestamptz"},
+		{from: "timetz", to: "date"},
+		{from: "timetz", to: "time", exp: "01:48:22", tz: true},
+		{from: "timetz", to: "timetz", exp: "01:48:22+05:30"},
+		{from: "timetz", to: "timestamp"},
+		{from: "timetz", to: "timestamptz"},
+		{from: "timestamp", to: "date", exp: "2024-06-06"},
+		{from: "timestamp", to: "time", exp: "01:48:22"},
+		{from: "timestamp", to: "timetz"},
+		{from: "timestamp", to: "timestamp", exp: "2024-06-06T01:48:22"},
+		{from: "timestamp", to: "timestamptz", exp: "2024-06-06T01:48:22-07:00", tz: true},
+		{from: "timestamptz", to: "date", exp: "2024-06-05", tz: true},
+		{from: "timestamptz", to: "time", exp: "13:18:22", tz: true},
+		{from: "timestamptz", to: "timetz", exp: "13:18:22-07:00"},
+		{from: "timestamptz", to: "timestamp", exp: "2024-06-05T13:18:22", tz: true},
+		{from: "timestamptz", to: "timestamptz", exp: "2024-06-06T01:48:22+05:30"},
+	} {
+		for _, useTZ := range []bool{false, true} {
+			name := tc.from + "_" + tc.to
+			if useTZ {
+				name += "_tz"
+			}
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				a := assert.New(t)
+				r := require.New(t)
+
+				res, err := convert[tc.to](values[tc.from], useTZ)
+				switch {
+				case tc.exp == "":
+					a.Nil(res)
+					r.Equal(&ConversionError{From: tc.from, To: tc.to}, err)
+				case tc.tz && !useTZ:
+					a.Nil(res)
+					r.Equal(&ConversionError{From: tc.from, To: tc.to, TZRequired: true}, err)
+				default:
+					r.NoError(err)
+					name, ok := typeName(res)
+					a.True(ok)
+					a.Equal(tc.to, name)
+					a.Equal(tc.exp, res.String())
+				}
+				if tc.from == tc.to {
+					a.Same(values[tc.from], res)
+				}
+			})
+		}
+	}
+
+	// Unsupported type.
+	for name, fn := range convert {
+		t.Run(name+"_unsupported", func(t *testing.T) {
+			t.Parallel()
+			res, err := fn(mockDateTime{}, true)
+			assert.Nil(t, res)
+			require.EqualError(t, err, "type: unsupported DateTime type types.mockDateTime")
+			require.ErrorIs(t, err, ErrSQLType)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	ctx := ContextWithTZ(context.Background(), time.FixedZone("", -7*secondsPerHour))
+	values := convertTestValues(ctx)
+
+	for _, tc := range []struct {
+		a   string
+		b   string
+		exp int
+		err *ConversionError // error when useTZ is false
+		tz  bool             // comparable with time zone usage
+	}{
+		{a: "date", b: "date", exp: 0},
+		{a: "date", b: "time", err: &ConversionError{From: "date", To: "time"}},
+		{a: "date", b: "timetz", err: &ConversionError{From: "date", To: "timetz"}},
+		{a: "date", b: "timestamp", exp: -1},
+		{
+			a: "date", b: "timestamptz", exp: 1, tz: true,
+			err: &ConversionError{From: "date", To: "timestamptz", TZRequired: true},
+		},
+		{a: "time", b: "date", err: &ConversionError{From: "time", To: "date"}},
+		{a: "time", b: "time", exp: 0},
+		{
+			a: "time", b: "timetz", exp: 1, tz: true,
+			err: &ConversionError{From: "time", To: "timetz", TZRequired: true},
+		},
+		{a: "time", b: "timestamp", err: &ConversionError{From: "time", To: "timestamp"}},
+		{a: "time", b: "timestamptz", err: &ConversionError{From: "time", To: "timestamptz"}},
+		{a: "timetz", b: "date", err: &ConversionError{From: "timetz", To: "date"}},
+		{
+			a: "timetz", b: "time", exp: -1, tz: true,
+			err: &ConversionError{From: "time", To: "timetz", TZRequired: true},
+		},
+		{a: "timetz", b: "timetz", exp: 0},
+		{a: "timetz", b: "timestamp", err: &ConversionError{From: "timetz", To: "timestamp"}},
+		{a: "timetz", b: "timestamptz", err: &ConversionError{From: "timetz", To: "timestamptz"}},
+		{a: "timestamp", b: "date", exp: 1},
+		{a: "timestamp", b: "time", err: &ConversionError{From: "timestamp", To: "time"}},
+		{a: "timestamp", b: "timetz", err: &ConversionError{From: "timestamp", To: "timetz"}},
+		{a: "timestamp", b: "timestamp", exp: 0},
+		{
+			a: "timestamp", b: "timestamptz", exp: 1, tz: true,
+			err: &ConversionError{From: "timestamp", To: "timestamptz", TZRequired: true},
+		},
+		{
+			a: "timestamptz", b: "date", exp: -1, tz: true,
+			err: &ConversionError{From: "date", To: "timestamptz", TZRequired: true},
+		},
+		{a: "timestamptz", b: "time", err: &ConversionError{From: "timestamptz", To: "time"}},
+		{a: "timestamptz", b: "timetz", err: &ConversionError{From: "timestamptz", To: "timetz"}},
+		{
+			a: "timestamptz", b: "timestamp", exp: -1, tz: true,
+			err: &ConversionError{From: "timestamp", To: "timestamptz", TZRequired: true},
+		},
+		{a: "timestamptz", b: "timestamptz", exp: 0},
+	} {
+		for _, useTZ := range []bool{false, true} {
+			name := tc.a + "_" + tc.b
+			if useTZ {
+				name += "_tz"
+			}
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				a := assert.New(t)
+				r := require.New(t)
+
+				res, err := Compare(ctx, values[tc.a], values[tc.b], useTZ)
+				if tc.err == nil || (tc.tz && useTZ) {
+					r.NoError(err)
+					a.Equal(tc.exp, res)
+				} else {
+					r.Equal(tc.err, err)
+					a.Zero(res)
+				}
+			})
+		}
+	}
+
+	// Unsupported types.
+	for name, val := range convertTestValues(ctx) {
+		t.Run(name+"_unsupported", func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			for _, args := range [][]DateTime{{val, mockDateTime{}}, {mockDateTime{}, val}} {
+				res, err := Compare(ctx, args[0], args[1], true)
+				a.Zero(res)
+				a.EqualError(err, "type: unsupported DateTime type types.mockDateTime")
+				a.ErrorIs(err, ErrSQLType)
+			}
+		})
+	}
+}
+
+func TestComparePostgres(t *testing.T) {
+	t.Parallel()
+	ctx := ContextWithTZ(context.Background(), time.UTC)
+
+	// Values and results from the PostgreSQL datetime comparison tests,
+	// executed with time zone usage in UTC. A nil result indicates
+	// incomparable types.
+	// https://github.com/postgres/postgres/blob/REL_17_2/src/test/regress/sql/jsonb_jsonpath.sql#L803-L1066
+	lt, eq, gt := -1, 0, 1
+	for _, tc := range []struct {
+		name string
+		ref  string
+		exp  map[string]*int
+	}{
+		{
+			name: "date",
+			ref:  "2017-03-10",
+			exp: map[string]*int{
+				"2017-03-10":             &eq,
+				"2017-03-11":             &gt,
+				"2017-03-09":             &lt,
+				"12:34:56":               nil,
+				"01:02:03+04":            nil,
+				"2017-03-10 00:00:00":    &eq,
+				"2017-03-10 12:34:56":    &gt,
+				"2017-03-10 01:02:03+04": &lt,
+				"2017-03-10 03:00:00+03": &eq,
+			},
+		},
+		{
+			name: "time",
+			ref:  "12:35:00",
+			exp: map[string]*int{
+				"12:34:00":               &lt,
+				"12:35:00":               &eq,
+				"12:36:00":               &gt,
+				"12:35:00+00":            &eq,
+				"12:35:00+01":            &lt,
+				"13:35:00+01":            &lt,
+				"2017-03-10":             nil,
+				"2017-03-10 12:35:00":    nil,
+				"2017-03-10 12:35:00+01": nil,
+			},
+		},
+		{
+			name: "timetz",
+			ref:  "12:35:00+01",
+			exp: map[string]*int{
+				"12:34:00+01":            &lt,
+				"12:35:00+01":            &eq,
+				"12:36:00+01":            &gt,
+				"12:35:00+02":            &lt,
+				"12:35:00-02":            &gt,
+				"10:35:00":               &lt,
+				"11:35:00":               &gt,
+				"12:35:00":               &gt,
+				"2017-03-10":             nil,
+				"2017-03-10 12:35:00":    nil,
+				"2017-03-10 12:35:00+01": nil,
+			},
+		},
+		{
+			name: "timestamp",
+			ref:  "2017-03-10 12:35:00",
+			exp: map[string]*int{
+				"2017-03-10 12:34:00":    &lt,
+				"2017-03-10 12:35:00":    &eq,
+				"2017-03-10 12:36:00":    &gt,
+				"2017-03-10 12:35:00+01": &lt,
+				"2017-03-10 13:35:00+01": &eq,
+				"2017-03-10 12:35:00-01": &gt,
+				"2017-03-10":             &lt,
+				"2017-03-11":             &gt,
+				"12:34:56":               nil,
+				"12:34:56+01":            nil,
+			},
+		},
+		{
+			name: "timestamptz",
+			ref:  "2017-03-10 12:35:00+01",
+			exp: map[string]*int{
+				"2017-03-10 12:34:00+01": &lt,
+				"2017-03-10 12:35:00+01": &eq,
+				"2017-03-10 12:36:00+01": &gt,
+				"2017-03-10 12:35:00+02": &lt,
+				"2017-03-10 12:35:00-02": &gt,
+				"2017-03-10 10:35:00":    &lt,
+				"2017-03-10 11:35:00":    &eq,
+				"2017-03-10 12:35:00":    &gt,
+				"2017-03-10":             &lt,
+				"2017-03-11":             &gt,
+				"12:34:56":               nil,
+				"12:34:56+01":            nil,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			r := require.New(t)
+
+			ref, ok := ParseTime(ctx, tc.ref, -1)
+			r.True(ok)
+			for src, exp := range tc.exp {
+				val, ok := ParseTime(ctx, src, -1)
+				r.True(ok)
+
+				// Comparisons must be antisymmetric.
+				res, err := Compare(ctx, val, ref, true)
+				inv, invErr := Compare(ctx, ref, val, true)
+				if exp == nil {
+					a.ErrorIs(err, ErrSQLType, src)
+					a.ErrorIs(invErr, ErrSQLType, src)
+					continue
+				}
+				r.NoError(err, src)
+				r.NoError(invErr, src)
+				a.Equal(*exp, res, src)
+				a.Equal(-*exp, inv, src)
+			}
+		})
+	}
+}
@@ -25,9 +25,15 @@ func (d *Date) GoTime() time.Time { return d.Time }
 // dateFormat represents the canonical string format for Date values.
 const dateFormat = "2006-01-02"
 
-// String returns the string representation of d.
+// String returns the string representation of d using the format
+// "2006-01-02", followed by " BC" for dates before 1 AD.
 func (d *Date) String() string {
-	return d.Time.Format(dateFormat)
+	return string(appendEra(appendFormat(nil, d.Time, dateFormat), d.Time))
+}
+
+// FormatStyle returns the string representation of d in style.
+func (d *Date) FormatStyle(style DateStyle) string {
+	return string(appendEra(appendFormat(nil, d.Time, dateFormatFor(style)), d.Time))
 }
 
 // ToTimestamp converts ts to *Timestamp.
@@ -35,12 +41,14 @@ func (d *Date) ToTimestamp(context.Context) *Timestamp {
 	return NewTimestamp(d.Time)
 }
 
-// ToTimestampTZ converts d to TimestampTZ in the time zone in ctx.
+// ToTimestampTZ converts d to TimestampTZ in the time zone in ctx, resolving
+// times skipped by daylight saving time transitions the same way as
+// PostgreSQL.
 func (d *Date) ToTimestampTZ(ctx context.Context) *TimestampTZ {
 	t := d.Time
 	return NewTimestampTZ(
 		ctx,
-		time.Date(
+		dateIn(
 			t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, TZFromContext(ctx),
 		),
 	)
@@ -53,21 +61,27 @@ func (d *Date) Compare(u time.Time) int {
 }
 
 // MarshalJSON implements the json.Marshaler interface. The time is a quoted
-// string in the RFC 3339 format with sub-second precision.
+// string in the format returned by [Date.String].
 func (d *Date) MarshalJSON() ([]byte, error) {
 	const dateJSONSize = len(dateFormat) + len(`""`)
 	b := make([]byte, 0, dateJSONSize)
 	b = append(b, '"')
-	b = d.Time.AppendFormat(b, dateFormat)
+	b = appendEra(appendFormat(b, d.Time, dateFormat), d.Time)
 	b = append(b, '"')
 	return b, nil
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface. The time must be a
-// quoted string in the RFC 3339 format.
+// quoted string in the format returned by [Date.String]. JSON null leaves d
+// unchanged.
 func (d *Date) UnmarshalJSON(data []byte) error {
-	tim, err := time.Parse(dateFormat, string(data[1:len(data)-1]))
-	if err != nil {
+	str, ok, err := unquoteJSON(data, "date")
+	if !ok {
+		return err
+	}
+	year, str := splitYear(str)
+	tim, ok := year.parse(dateFormat, str)
+	if !ok {
 		return fmt.Errorf("%w: Cannot parse %s as %q", ErrSQLType, data, dateFormat)
 	}
 	*d = *NewDate(tim)
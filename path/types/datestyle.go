@@ -0,0 +1,178 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateStyle represents a PostgreSQL [DateStyle] output format for date and
+// time values. The non-ISO styles use the PostgreSQL default month-day-year
+// field ordering (MDY).
+//
+// [DateStyle]: https://www.postgresql.org/docs/current/datatype-datetime.html#DATATYPE-DATETIME-OUTPUT
+type DateStyle uint8
+
+const (
+	// DateStyleISO formats values using the ISO 8601 formats output by the
+	// String methods, e.g., 2023-08-15T12:34:56. This is the default.
+	DateStyleISO DateStyle = iota
+
+	// DateStylePostgres formats values using the traditional PostgreSQL
+	// format, e.g., Tue Aug 15 12:34:56 2023.
+	DateStylePostgres
+
+	// DateStyleSQL formats values using the SQL format, e.g., 08/15/2023
+	// 12:34:56.
+	DateStyleSQL
+
+	// DateStyleGerman formats values using the German format, e.g.,
+	// 15.08.2023 12:34:56.
+	DateStyleGerman
+)
+
+// String returns the PostgreSQL name of the style.
+func (s DateStyle) String() string {
+	switch s {
+	case DateStyleISO:
+		return "ISO"
+	case DateStylePostgres:
+		return "Postgres"
+	case DateStyleSQL:
+		return "SQL"
+	case DateStyleGerman:
+		return "German"
+	default:
+		return "UNKNOWN_DATE_STYLE"
+	}
+}
+
+const (
+	// styleTimeFormat represents the time format for all non-ISO styles,
+	// which display no more than microsecond precision.
+	styleTimeFormat = "15:04:05.999999"
+
+	// Date formats for the non-ISO styles.
+	postgresDateFormat = "01-02-2006"
+	sqlDateFormat      = "01/02/2006"
+	germanDateFormat   = "02.01.2006"
+
+	// Timestamp formats for the non-ISO styles.
+	postgresTimestampFormat = "Mon Jan 02 " + styleTimeFormat + " 2006"
+	sqlTimestampFormat      = sqlDateFormat + " " + styleTimeFormat
+	germanTimestampFormat   = germanDateFormat + " " + styleTimeFormat
+)
+
+// dateFormatFor returns the date format for style. Returns the ISO format
+// for unknown styles.
+func dateFormatFor(style DateStyle) string {
+	switch style {
+	case DateStylePostgres:
+		return postgresDateFormat
+	case DateStyleSQL:
+		return sqlDateFormat
+	case DateStyleGerman:
+		return germanDateFormat
+	default:
+		return dateFormat
+	}
+}
+
+// timestampFormatFor returns the timestamp format for style. Returns the ISO
+// format for unknown styles.
+func timestampFormatFor(style DateStyle) string {
+	switch style {
+	case DateStylePostgres:
+		return postgresTimestampFormat
+	case DateStyleSQL:
+		return sqlTimestampFormat
+	case DateStyleGerman:
+		return germanTimestampFormat
+	default:
+		return timestampFormat
+	}
+}
+
+// appendFormat appends t formatted with layout to b, like
+// [time.Time.AppendFormat], except that, as in PostgreSQL, it formats years
+// before 1 AD as BC years, so that year 0 appears as 0001 and year -1 as
+// 0002. Use appendEra to append the " BC" that follows.
+func appendFormat(b []byte, t time.Time, layout string) []byte {
+	year := t.Year()
+	before, after, found := strings.Cut(layout, "2006")
+	if year > 0 || !found {
+		return t.AppendFormat(b, layout)
+	}
+
+	const yearDigits = 4
+	bcYear := strconv.Itoa(1 - year)
+	b = t.AppendFormat(b, before)
+	for range yearDigits - len(bcYear) {
+		b = append(b, '0')
+	}
+	b = append(b, bcYear...)
+	return t.AppendFormat(b, after)
+}
+
+// appendEra appends " BC" to b if t falls before 1 AD, as PostgreSQL
+// appends it to the end of dates and timestamps.
+func appendEra(b []byte, t time.Time) []byte {
+	if t.Year() < 1 {
+		b = append(b, " BC"...)
+	}
+	return b
+}
+
+// appendOffset appends the offset of t to b in the PostgreSQL format: the
+// sign and hours, followed by minutes and seconds only when they're not
+// zero, e.g., -07, +05:30, +01:02:03. If xsd is true, it always appends the
+// minutes, as PostgreSQL does for XML Schema and JSON output, e.g., -07:00,
+// +00:00.
+func appendOffset(b []byte, t time.Time, xsd bool) []byte {
+	_, off := t.Zone()
+	sign := byte('+')
+	if off < 0 {
+		sign = '-'
+		off = -off
+	}
+	const secondsPerMinute = 60
+	hour := off / secondsPerHour
+	minute := off / secondsPerMinute % secondsPerMinute
+	sec := off % secondsPerMinute
+
+	b = append(b, sign)
+	b = appendTwoDigits(b, hour)
+	if xsd || minute != 0 || sec != 0 {
+		b = append(b, ':')
+		b = appendTwoDigits(b, minute)
+		if sec != 0 {
+			b = append(b, ':')
+			b = appendTwoDigits(b, sec)
+		}
+	}
+	return b
+}
+
+// zoneAbbrev returns the abbreviation of the time zone of loc at t, such as
+// PDT, as formatted by the MST layout element, if it has the offset of t's
+// time zone and is alphabetic. Returns "" otherwise, including for the
+// numeric abbreviations of some zones, such as -03.
+func zoneAbbrev(t time.Time, loc *time.Location) string {
+	if loc == nil {
+		return ""
+	}
+	name, off := t.In(loc).Zone()
+	if off != zoneOffset(t) || name == "" || name[0] == '+' || name[0] == '-' {
+		return ""
+	}
+	return name
+}
+
+// appendTwoDigits appends num to b zero-padded to two digits.
+func appendTwoDigits(b []byte, num int) []byte {
+	const ten = 10
+	if num < ten {
+		b = append(b, '0')
+	}
+	return strconv.AppendInt(b, int64(num), ten)
+}
@@ -0,0 +1,253 @@
+package types
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateStyle(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	for _, tc := range []struct {
+		style DateStyle
+		exp   string
+	}{
+		{DateStyleISO, "ISO"},
+		{DateStylePostgres, "Postgres"},
+		{DateStyleSQL, "SQL"},
+		{DateStyleGerman, "German"},
+		{DateStyleGerman + 1, "UNKNOWN_DATE_STYLE"},
+	} {
+		a.Equal(tc.exp, tc.style.String())
+	}
+}
+
+func TestFormatStyle(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	plus530 := time.FixedZone("", 5*secondsPerHour+30*60)
+	minus7 := time.FixedZone("", -7*secondsPerHour)
+	odd := time.FixedZone("", secondsPerHour+2*60+3)
+	moment := time.Date(2023, 8, 15, 12, 34, 56, 0, plus530)
+	micro := time.Date(2023, 8, 5, 7, 4, 6, 123456789, minus7)
+	ides := time.Date(-43, 3, 15, 12, 0, 0, 0, plus530)
+	pacific, err := time.LoadLocation("PST8PDT")
+	require.NoError(t, err)
+	pacificCtx := ContextWithTZ(ctx, pacific)
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		dt   DateTime
+		exp  map[DateStyle]string
+	}{
+		{
+			name: "date",
+			dt:   NewDate(moment),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "2023-08-15",
+				DateStylePostgres: "08-15-2023",
+				DateStyleSQL:      "08/15/2023",
+				DateStyleGerman:   "15.08.2023",
+			},
+		},
+		{
+			name: "time",
+			dt:   NewTime(moment),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "12:34:56",
+				DateStylePostgres: "12:34:56",
+				DateStyleSQL:      "12:34:56",
+				DateStyleGerman:   "12:34:56",
+			},
+		},
+		{
+			name: "time_micro",
+			dt:   NewTime(micro),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "07:04:06.123456789",
+				DateStylePostgres: "07:04:06.123456",
+				DateStyleSQL:      "07:04:06.123456",
+				DateStyleGerman:   "07:04:06.123456",
+			},
+		},
+		{
+			name: "timetz",
+			dt:   NewTimeTZ(moment),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "12:34:56+05:30",
+				DateStylePostgres: "12:34:56+05:30",
+				DateStyleSQL:      "12:34:56+05:30",
+				DateStyleGerman:   "12:34:56+05:30",
+			},
+		},
+		{
+			name: "timetz_hour_offset",
+			dt:   NewTimeTZ(micro),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "07:04:06.123456789-07:00",
+				DateStylePostgres: "07:04:06.123456-07",
+				DateStyleSQL:      "07:04:06.123456-07",
+				DateStyleGerman:   "07:04:06.123456-07",
+			},
+		},
+		{
+			name: "timetz_second_offset",
+			dt:   NewTimeTZ(time.Date(2023, 8, 15, 12, 34, 56, 0, odd)),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "12:34:56+01:02:03",
+				DateStylePostgres: "12:34:56+01:02:03",
+				DateStyleSQL:      "12:34:56+01:02:03",
+				DateStyleGerman:   "12:34:56+01:02:03",
+			},
+		},
+		{
+			name: "timestamp",
+			dt:   NewTimestamp(moment),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "2023-08-15T12:34:56",
+				DateStylePostgres: "Tue Aug 15 12:34:56 2023",
+				DateStyleSQL:      "08/15/2023 12:34:56",
+				DateStyleGerman:   "15.08.2023 12:34:56",
+			},
+		},
+		{
+			name: "timestamp_micro",
+			dt:   NewTimestamp(micro),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "2023-08-05T07:04:06.123456789",
+				DateStylePostgres: "Sat Aug 05 07:04:06.123456 2023",
+				DateStyleSQL:      "08/05/2023 07:04:06.123456",
+				DateStyleGerman:   "05.08.2023 07:04:06.123456",
+			},
+		},
+		{
+			name: "timestamptz",
+			dt:   NewTimestampTZ(ctx, moment),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "2023-08-15T12:34:56+05:30",
+				DateStylePostgres: "Tue Aug 15 12:34:56 2023 +05:30",
+				DateStyleSQL:      "08/15/2023 12:34:56+05:30",
+				DateStyleGerman:   "15.08.2023 12:34:56+05:30",
+			},
+		},
+		{
+			name: "timestamptz_micro",
+			dt:   NewTimestampTZ(ctx, micro),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "2023-08-05T07:04:06.123456789-07:00",
+				DateStylePostgres: "Sat Aug 05 07:04:06.123456 2023 -07",
+				DateStyleSQL:      "08/05/2023 07:04:06.123456-07",
+				DateStyleGerman:   "05.08.2023 07:04:06.123456-07",
+			},
+		},
+		{
+			name: "timestamptz_abbrev",
+			dt:   NewTimestampTZ(pacificCtx, micro),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "2023-08-05T07:04:06.123456789-07:00",
+				DateStylePostgres: "Sat Aug 05 07:04:06.123456 2023 PDT",
+				DateStyleSQL:      "08/05/2023 07:04:06.123456 PDT",
+				DateStyleGerman:   "05.08.2023 07:04:06.123456 PDT",
+			},
+		},
+		{
+			name: "timestamptz_abbrev_utc",
+			dt:   NewTimestampTZ(ctx, time.Date(2023, 8, 15, 12, 34, 56, 0, time.UTC)),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "2023-08-15T12:34:56+00:00",
+				DateStylePostgres: "Tue Aug 15 12:34:56 2023 UTC",
+				DateStyleSQL:      "08/15/2023 12:34:56 UTC",
+				DateStyleGerman:   "15.08.2023 12:34:56 UTC",
+			},
+		},
+		{
+			name: "timestamptz_other_offset",
+			dt:   NewTimestampTZ(pacificCtx, moment),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "2023-08-15T12:34:56+05:30",
+				DateStylePostgres: "Tue Aug 15 12:34:56 2023 +05:30",
+				DateStyleSQL:      "08/15/2023 12:34:56+05:30",
+				DateStyleGerman:   "15.08.2023 12:34:56+05:30",
+			},
+		},
+		{
+			name: "timestamptz_numeric_abbrev",
+			dt: NewTimestampTZ(
+				ContextWithTZ(ctx, saoPaulo),
+				time.Date(2023, 8, 15, 12, 34, 56, 0, saoPaulo),
+			),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "2023-08-15T12:34:56-03:00",
+				DateStylePostgres: "Tue Aug 15 12:34:56 2023 -03",
+				DateStyleSQL:      "08/15/2023 12:34:56-03",
+				DateStyleGerman:   "15.08.2023 12:34:56-03",
+			},
+		},
+		{
+			name: "date_bc",
+			dt:   NewDate(ides),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "0044-03-15 BC",
+				DateStylePostgres: "03-15-0044 BC",
+				DateStyleSQL:      "03/15/0044 BC",
+				DateStyleGerman:   "15.03.0044 BC",
+			},
+		},
+		{
+			name: "date_big_year",
+			dt:   NewDate(time.Date(1000000, 1, 1, 0, 0, 0, 0, time.UTC)),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "1000000-01-01",
+				DateStylePostgres: "01-01-1000000",
+				DateStyleSQL:      "01/01/1000000",
+				DateStyleGerman:   "01.01.1000000",
+			},
+		},
+		{
+			name: "timestamp_bc",
+			dt:   NewTimestamp(ides),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "0044-03-15T12:00:00 BC",
+				DateStylePostgres: "Fri Mar 15 12:00:00 0044 BC",
+				DateStyleSQL:      "03/15/0044 12:00:00 BC",
+				DateStyleGerman:   "15.03.0044 12:00:00 BC",
+			},
+		},
+		{
+			name: "timestamp_1_bc_leap_day",
+			dt:   NewTimestamp(time.Date(0, 2, 29, 1, 2, 3, 0, time.UTC)),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "0001-02-29T01:02:03 BC",
+				DateStylePostgres: "Tue Feb 29 01:02:03 0001 BC",
+				DateStyleSQL:      "02/29/0001 01:02:03 BC",
+				DateStyleGerman:   "29.02.0001 01:02:03 BC",
+			},
+		},
+		{
+			name: "timestamptz_bc",
+			dt:   NewTimestampTZ(ctx, ides),
+			exp: map[DateStyle]string{
+				DateStyleISO:      "0044-03-15T12:00:00+05:30 BC",
+				DateStylePostgres: "Fri Mar 15 12:00:00 0044 +05:30 BC",
+				DateStyleSQL:      "03/15/0044 12:00:00+05:30 BC",
+				DateStyleGerman:   "15.03.0044 12:00:00+05:30 BC",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			a.Equal(tc.dt.String(), tc.dt.FormatStyle(DateStyleISO))
+			for style, exp := range tc.exp {
+				a.Equal(exp, tc.dt.FormatStyle(style), style.String())
+			}
+		})
+	}
+}
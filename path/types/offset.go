@@ -23,6 +23,55 @@ func offsetOnlyTimeFor(t time.Time) time.Time {
 	return t
 }
 
+// dateIn returns the time corresponding to the wall clock time
+// yyyy-mm-dd hh:mm:ss + nsec in loc. Unlike [time.Date], it resolves wall
+// clock times made ambiguous or nonexistent by a time zone transition the
+// same way as PostgreSQL: it interprets a time skipped by a spring-forward
+// transition using the UTC offset that prevailed just before the transition,
+// and a time repeated by a fall-back transition using the UTC offset that
+// prevailed just after the transition. In America/New_York, for example,
+// 2024-03-10 02:30 resolves to 03:30 EDT and 2024-11-03 01:30 resolves to
+// 01:30 EST.
+func dateIn(year int, month time.Month, day, hour, minute, sec, nsec int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, minute, sec, nsec, loc)
+	wall := time.Date(year, month, day, hour, minute, sec, nsec, time.UTC)
+	start, end := t.ZoneBounds()
+
+	// withOffset returns the time for wall in loc with offset off.
+	withOffset := func(off int) time.Time {
+		return wall.Add(-time.Duration(off) * time.Second).In(loc)
+	}
+
+	// Return the later interpretation of an ambiguous time.
+	tWall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	if tWall.Equal(wall) {
+		if !end.IsZero() {
+			_, off := end.Zone()
+			if alt := withOffset(off); alt.After(t) && zoneOffset(alt) == off {
+				return alt
+			}
+		}
+		return t
+	}
+
+	// Nonexistent time: use the offset before the transition. time.Date
+	// normalized it to a time either before the transition, in which case t
+	// has the offset before the transition, or after it.
+	if tWall.Before(wall) {
+		return withOffset(zoneOffset(t))
+	}
+	if start.IsZero() {
+		return t
+	}
+	return withOffset(zoneOffset(start.Add(-time.Nanosecond)))
+}
+
+// zoneOffset returns the offset in seconds east of UTC of t's time zone.
+func zoneOffset(t time.Time) int {
+	_, off := t.Zone()
+	return off
+}
+
 // key is an unexported type for keys defined in this package. This prevents
 // collisions with keys defined in other packages.
 type key int
@@ -88,3 +88,109 @@ func TestContextWithTZ(t *testing.T) {
 		a.Equal(time.UTC, loc)
 	})
 }
+
+func TestDateIn(t *testing.T) {
+	t.Parallel()
+
+	nyc := loadTZ("America/New_York")
+	lhi := loadTZ("Australia/Lord_Howe")
+
+	for _, tc := range []struct {
+		name string
+		loc  *time.Location
+		wall time.Time
+		exp  string
+	}{
+		{
+			name: "nyc_winter",
+			loc:  nyc,
+			wall: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+			exp:  "2024-01-15T12:00:00-05:00",
+		},
+		{
+			name: "nyc_summer",
+			loc:  nyc,
+			wall: time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC),
+			exp:  "2024-07-15T12:00:00-04:00",
+		},
+		{
+			name: "nyc_before_gap",
+			loc:  nyc,
+			wall: time.Date(2024, 3, 10, 1, 59, 59, 0, time.UTC),
+			exp:  "2024-03-10T01:59:59-05:00",
+		},
+		{
+			name: "nyc_gap",
+			loc:  nyc,
+			wall: time.Date(2024, 3, 10, 2, 30, 0, 0, time.UTC),
+			exp:  "2024-03-10T03:30:00-04:00",
+		},
+		{
+			name: "nyc_after_gap",
+			loc:  nyc,
+			wall: time.Date(2024, 3, 10, 3, 0, 0, 0, time.UTC),
+			exp:  "2024-03-10T03:00:00-04:00",
+		},
+		{
+			name: "nyc_before_overlap",
+			loc:  nyc,
+			wall: time.Date(2024, 11, 3, 0, 59, 59, 0, time.UTC),
+			exp:  "2024-11-03T00:59:59-04:00",
+		},
+		{
+			name: "nyc_overlap",
+			loc:  nyc,
+			wall: time.Date(2024, 11, 3, 1, 30, 0, 0, time.UTC),
+			exp:  "2024-11-03T01:30:00-05:00",
+		},
+		{
+			name: "nyc_after_overlap",
+			loc:  nyc,
+			wall: time.Date(2024, 11, 3, 2, 0, 0, 0, time.UTC),
+			exp:  "2024-11-03T02:00:00-05:00",
+		},
+		{
+			name: "lord_howe_gap",
+			loc:  lhi,
+			wall: time.Date(2024, 10, 6, 2, 15, 0, 0, time.UTC),
+			exp:  "2024-10-06T02:45:00+11:00",
+		},
+		{
+			name: "lord_howe_after_gap",
+			loc:  lhi,
+			wall: time.Date(2024, 10, 6, 2, 30, 0, 0, time.UTC),
+			exp:  "2024-10-06T02:30:00+11:00",
+		},
+		{
+			name: "lord_howe_overlap",
+			loc:  lhi,
+			wall: time.Date(2024, 4, 7, 1, 45, 0, 0, time.UTC),
+			exp:  "2024-04-07T01:45:00+10:30",
+		},
+		{
+			name: "lord_howe_after_overlap",
+			loc:  lhi,
+			wall: time.Date(2024, 4, 7, 2, 0, 0, 0, time.UTC),
+			exp:  "2024-04-07T02:00:00+10:30",
+		},
+		{
+			name: "fixed",
+			loc:  time.FixedZone("", secondsPerHour*-3),
+			wall: time.Date(2024, 3, 10, 2, 30, 0, 0, time.UTC),
+			exp:  "2024-03-10T02:30:00-03:00",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+
+			w := tc.wall
+			got := dateIn(
+				w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(),
+				w.Second(), w.Nanosecond(), tc.loc,
+			)
+			a.Equal(tc.exp, got.Format(time.RFC3339Nano))
+			a.Equal(tc.loc, got.Location())
+		})
+	}
+}
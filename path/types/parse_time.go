@@ -3,6 +3,8 @@ package types
 import (
 	"context"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -11,12 +13,39 @@ import (
 // time_tz, time, timestamp_tz, and timestamp. Returns false if the string
 // cannot be parsed by any of the formats.
 //
+// Times and timestamps may also end with a time zone abbreviation, such as
+// EST or UTC, or a time zone name, such as America/New_York, as in
+// PostgreSQL, to produce time_tz and timestamp_tz values with the offset
+// of that zone.
+//
+// As in PostgreSQL, dates and timestamps may have years of up to seven
+// digits, such as 1000000-01-01, and may end with " BC" for years before 1
+// AD, such as 0044-03-15 BC. Dates may range from 4714-11-24 BC to 5874897
+// AD and timestamps from 4714-11-24 BC to 294276 AD.
+//
+// ParseTime also parses these PostgreSQL special input strings, which it
+// compares case-insensitively:
+//
+//   - "epoch": the timestamp 1970-01-01 00:00:00
+//   - "allballs": the time 00:00:00
+//   - "J" followed by a Julian day number, such as J2451187: the date of
+//     that day, 1999-01-08 in this example
+//
+// It does not parse the special strings whose values depend on the current
+// time, "now", "today", "tomorrow", and "yesterday"; see [IsVolatile].
+//
 // We also support ISO 8601 format (with "T") for timestamps, because
 // PostgreSQL to_json() and to_jsonb() functions use this format.
 func ParseTime(ctx context.Context, src string, precision int) (DateTime, bool) {
+	if value, ok := parseSpecial(src); ok {
+		return value, true
+	}
+
+	year, src := splitYear(src)
+
 	// Date first.
-	value, err := time.Parse("2006-01-02", src)
-	if err == nil {
+	value, ok := year.parse(dateFormat, src)
+	if ok {
 		return NewDate(value), true
 	}
 
@@ -32,7 +61,7 @@ func ParseTime(ctx context.Context, src string, precision int) (DateTime, bool)
 	}
 
 	// Time without TZ
-	value, err = time.Parse("15:04:05", src)
+	value, err := time.Parse("15:04:05", src)
 	if err == nil {
 		return NewTime(adjustPrecision(value, precision)), true
 	}
@@ -44,9 +73,9 @@ func ParseTime(ctx context.Context, src string, precision int) (DateTime, bool)
 		"2006-01-02T15:04:05Z07:00",
 		"2006-01-02 15:04:05Z07:00",
 	} {
-		value, err := time.Parse(format, src)
-		if err == nil {
-			return NewTimestampTZ(ctx, adjustPrecision(value, precision)), true
+		value, ok := year.parse(format, src)
+		if ok {
+			return NewTimestampTZ(ctx, adjustTimestampPrecision(value, precision)), true
 		}
 	}
 
@@ -55,16 +84,161 @@ func ParseTime(ctx context.Context, src string, precision int) (DateTime, bool)
 		"2006-01-02T15:04:05",
 		"2006-01-02 15:04:05",
 	} {
-		value, err := time.Parse(format, src)
-		if err == nil {
-			return NewTimestamp(adjustPrecision(value, precision)), true
+		value, ok := year.parse(format, src)
+		if ok {
+			return NewTimestamp(adjustTimestampPrecision(value, precision)), true
+		}
+	}
+
+	// Time or timestamp with a zone abbreviation or name
+	return parseZoned(ctx, src, precision, year)
+}
+
+const (
+	// minYear is the earliest year of dates and timestamps, 4714 BC, of
+	// which only November 24 and later are valid; see minDateTime.
+	minYear = -4713
+	// maxDateYear is the latest year of dates.
+	maxDateYear = 5874897
+	// maxTimestampYear is the latest year of timestamps.
+	maxTimestampYear = 294276
+	// placeholderYear replaces years that time.Parse cannot parse. It's a
+	// leap year, so that February 29 parses.
+	placeholderYear = "2000"
+	// julianEpoch is the Julian day number of pgEpoch.
+	julianEpoch = 2451545
+)
+
+// minDateTime is the earliest date and timestamp, 4714-11-24 BC, Julian day
+// zero.
+//
+//nolint:gochecknoglobals
+var minDateTime = time.Date(minYear, 11, 24, 0, 0, 0, 0, time.UTC)
+
+// extendedYear is a year that [time.Parse] cannot parse, either one beyond
+// 9999 or a BC year, found by splitYear.
+type extendedYear struct {
+	// year is the year, where 0 is 1 BC, -1 is 2 BC, and so on.
+	year int
+	// ok is true if splitYear found an extended year.
+	ok bool
+}
+
+// splitYear looks for a year at the start of src that [time.Parse] cannot
+// parse: a year of five to seven digits, or one of any number of digits
+// when src ends with " BC". If it finds one, it returns it along with src
+// with the year replaced by placeholderYear and " BC" removed. Otherwise it
+// returns src unchanged.
+func splitYear(src string) (extendedYear, string) {
+	str, bc := strings.CutSuffix(src, " BC")
+	if !bc {
+		str, bc = strings.CutSuffix(str, " bc")
+	}
+
+	const minDigits, maxDigits = 4, 7
+	digits := 0
+	for digits < len(str) && str[digits] >= '0' && str[digits] <= '9' {
+		digits++
+	}
+	if digits < minDigits || digits > maxDigits || digits == len(str) || str[digits] != '-' {
+		return extendedYear{}, src
+	}
+	if digits == minDigits && !bc {
+		return extendedYear{}, src
+	}
+
+	year, _ := strconv.Atoi(str[:digits])
+	if bc {
+		if year < 1 {
+			// There is no year 0 BC.
+			return extendedYear{}, src
 		}
+		year = 1 - year
+	}
+	return extendedYear{year: year, ok: true}, placeholderYear + str[digits:]
+}
+
+// parse parses value with layout like [time.Parse], and, if y.ok is true,
+// replaces the year of the result with y.year. Returns false if value does
+// not parse, if its day does not exist in y.year, such as February 29 of a
+// common year, or if y.year is out of the range PostgreSQL supports for
+// dates, when layout is dateFormat, or timestamps.
+func (y extendedYear) parse(layout, value string) (time.Time, bool) {
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return t, false
+	}
+	if !y.ok {
+		return t, true
 	}
 
-	// Not found.
-	return nil, false
+	maxYear := maxTimestampYear
+	if layout == dateFormat {
+		maxYear = maxDateYear
+	}
+	if y.year < minYear || y.year > maxYear {
+		return t, false
+	}
+
+	res := time.Date(
+		y.year, t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+		t.Location(),
+	)
+	return res, res.Day() == t.Day() && !res.Before(minDateTime)
 }
 
+// parseSpecial parses the PostgreSQL special date and time input strings
+// with fixed values: "epoch", "allballs", and Julian day numbers, such as
+// "J2451187". Returns false for any other string.
+func parseSpecial(src string) (DateTime, bool) {
+	switch strings.ToLower(src) {
+	case "epoch":
+		return NewTimestamp(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)), true
+	case "allballs":
+		return NewTime(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)), true
+	}
+
+	if len(src) < 2 || (src[0] != 'J' && src[0] != 'j') {
+		return nil, false
+	}
+	for _, c := range src[1:] {
+		if c < '0' || c > '9' {
+			return nil, false
+		}
+	}
+	day, err := strconv.ParseInt(src[1:], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	// Check the range before time.Date can overflow.
+	const maxJulianDay = 2147483493 // 5874897-12-31
+	if day > maxJulianDay {
+		return nil, false
+	}
+	return NewDate(time.Date(2000, 1, 1+int(day-julianEpoch), 0, 0, 0, 0, time.UTC)), true
+}
+
+// IsVolatile returns true if src is one of the PostgreSQL special date and
+// time input strings whose values depend on the current time: "now",
+// "today", "tomorrow", and "yesterday", compared case-insensitively.
+// [ParseTime] does not parse them, because, as in PostgreSQL, their values
+// would make SQL/JSON path expressions depend on when they execute.
+func IsVolatile(src string) bool {
+	switch strings.ToLower(src) {
+	case "now", "today", "tomorrow", "yesterday":
+		return true
+	default:
+		return false
+	}
+}
+
+// pgEpoch is the PostgreSQL epoch, relative to which it stores timestamps.
+var pgEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// adjustPrecision rounds the fractional seconds of value to precision
+// digits, unless precision is negative. Halfway values round up, as in
+// PostgreSQL.
 func adjustPrecision(value time.Time, precision int) time.Time {
 	if precision > -1 {
 		value = value.Round(time.Second / time.Duration(math.Pow10(precision)))
@@ -72,6 +246,19 @@ func adjustPrecision(value time.Time, precision int) time.Time {
 	return value
 }
 
+// adjustTimestampPrecision is like adjustPrecision, except that, like
+// PostgreSQL, it rounds halfway values away from pgEpoch, so that
+// timestamps before it round down.
+func adjustTimestampPrecision(value time.Time, precision int) time.Time {
+	if precision > -1 && value.Before(pgEpoch) {
+		unit := time.Second / time.Duration(math.Pow10(precision))
+		if trunc := value.Truncate(unit); value.Sub(trunc)*2 == unit {
+			return trunc
+		}
+	}
+	return adjustPrecision(value, precision)
+}
+
 // // https://www.postgresql.org/docs/devel/functions-formatting.html
 // // https://pkg.go.dev/time#pkg-constants
 // var formatMap = map[string]string{
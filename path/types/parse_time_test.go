@@ -2,10 +2,12 @@ package types
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 //nolint:unparam // keep s in case we need it in the future.
@@ -265,6 +267,155 @@ func TestParseFail(t *testing.T) {
 	}
 }
 
+func TestParseTimeExtendedYear(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := ContextWithTZ(context.Background(), time.UTC)
+
+	for _, tc := range []struct {
+		name  string
+		value string
+		exp   string
+	}{
+		{"date_five_digits", "10000-01-01", "10000-01-01"},
+		{"date_seven_digits", "1000000-06-15", "1000000-06-15"},
+		{"date_max", "5874897-12-31", "5874897-12-31"},
+		{"date_bc", "0044-03-15 BC", "0044-03-15 BC"},
+		{"date_lower_bc", "0044-03-15 bc", "0044-03-15 BC"},
+		{"date_min", "4713-01-01 BC", "4713-01-01 BC"},
+		{"date_bc_leap_day", "0001-02-29 BC", "0001-02-29 BC"},
+		{"date_big_leap_day", "10000-02-29", "10000-02-29"},
+		{"timestamp_big", "12345-01-02 03:04:05", "12345-01-02T03:04:05"},
+		{"timestamp_t_big", "12345-01-02T03:04:05.5", "12345-01-02T03:04:05.5"},
+		{"timestamp_max", "294276-12-31 23:59:59", "294276-12-31T23:59:59"},
+		{"timestamp_bc", "0044-03-15 12:00:00 BC", "0044-03-15T12:00:00 BC"},
+		{"timestamptz_bc", "0044-03-15 12:00:00+01 BC", "0044-03-15T12:00:00+01:00 BC"},
+		{"timestamptz_big", "12345-01-02 03:04:05-07:30", "12345-01-02T03:04:05-07:30"},
+		{"timestamptz_zone_bc", "0044-03-15 12:00:00 UTC BC", "0044-03-15T12:00:00+00:00 BC"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dt, ok := ParseTime(ctx, tc.value, -1)
+			r.True(ok)
+			a.Equal(tc.exp, dt.String())
+		})
+	}
+
+	// Out of range and invalid years.
+	for _, value := range []string{
+		"0000-01-01 BC",
+		"4714-01-01 BC",
+		"5874898-01-01",
+		"294277-01-01 00:00:00",
+		"0002-02-29 BC",
+		"10001-02-29",
+		"12345678-01-01",
+		"012-01-01",
+		"2024-01-01 AD",
+	} {
+		dt, ok := ParseTime(ctx, value, -1)
+		a.False(ok, value)
+		a.Nil(dt, value)
+	}
+
+	// BC years sort before AD years.
+	bc, ok := ParseTime(ctx, "0001-12-31 BC", -1)
+	r.True(ok)
+	ad, ok := ParseTime(ctx, "0001-01-01", -1)
+	r.True(ok)
+	a.True(bc.GoTime().Before(ad.GoTime()))
+
+	// JSON round-trips.
+	for _, tc := range []struct {
+		value string
+		dest  DateTime
+	}{
+		{"0044-03-15 BC", &Date{}},
+		{"1000000-01-01", &Date{}},
+		{"0044-03-15 12:00:00 BC", &Timestamp{}},
+		{"12345-01-02 03:04:05", &Timestamp{}},
+		{"0044-03-15 12:00:00+01 BC", &TimestampTZ{}},
+		{"12345-01-02 03:04:05+02", &TimestampTZ{}},
+	} {
+		dt, ok := ParseTime(ctx, tc.value, -1)
+		r.True(ok, tc.value)
+		data, err := json.Marshal(dt)
+		r.NoError(err, tc.value)
+		r.NoError(json.Unmarshal(data, tc.dest), tc.value)
+		a.Equal(dt.String(), tc.dest.String(), tc.value)
+	}
+}
+
+func TestParseSpecial(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name  string
+		value string
+		exp   DateTime
+	}{
+		{"epoch", "epoch", NewTimestamp(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{"upper_epoch", "EPOCH", NewTimestamp(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{"allballs", "allballs", NewTime(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{"mixed_allballs", "AllBalls", NewTime(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{"julian", "J2451187", NewDate(time.Date(1999, 1, 8, 0, 0, 0, 0, time.UTC))},
+		{"lower_julian", "j2451545", NewDate(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{"julian_zero", "J0", NewDate(time.Date(-4713, 11, 24, 0, 0, 0, 0, time.UTC))},
+		{"julian_max", "J2147483493", NewDate(time.Date(5874897, 12, 31, 0, 0, 0, 0, time.UTC))},
+		{"julian_leading_zeros", "J0002451187", NewDate(time.Date(1999, 1, 8, 0, 0, 0, 0, time.UTC))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dt, ok := ParseTime(ctx, tc.value, -1)
+			a.True(ok)
+			a.Equal(tc.exp, dt)
+		})
+	}
+
+	// Not special.
+	for _, value := range []string{
+		"J",
+		"J-1",
+		"J+1",
+		"J2147483494",
+		"J99999999999999999999",
+		"J12.5",
+		"J 12",
+		"X2451187",
+		" epoch",
+		"epochs",
+		"now",
+		"today",
+	} {
+		dt, ok := ParseTime(ctx, value, -1)
+		a.False(ok, value)
+		a.Nil(dt, value)
+	}
+
+	// Before Julian day zero.
+	dt, ok := ParseTime(ctx, "4714-11-24 BC", -1)
+	a.True(ok)
+	a.Equal("4714-11-24 BC", dt.String())
+	dt, ok = ParseTime(ctx, "4714-11-23 BC", -1)
+	a.False(ok)
+	a.Nil(dt)
+}
+
+func TestIsVolatile(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	for _, value := range []string{"now", "today", "tomorrow", "yesterday", "NOW", "Today"} {
+		a.True(IsVolatile(value), value)
+	}
+	for _, value := range []string{"epoch", "allballs", "J2451187", "nowish", "2024-01-01", ""} {
+		a.False(IsVolatile(value), value)
+	}
+}
+
 func TestParseTimePrecision(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
@@ -361,6 +512,38 @@ func TestParseTimePrecision(t *testing.T) {
 	}
 }
 
+func TestParseTimePrecisionHalfway(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+	ctx := ContextWithTZ(context.Background(), time.UTC)
+
+	for _, tc := range []struct {
+		name      string
+		value     string
+		precision int
+		exp       string
+	}{
+		{"time_up", "12:34:56.5", 0, "12:34:57"},
+		{"time_up_two", "12:34:56.125", 2, "12:34:56.13"},
+		{"time_tz_up", "12:34:56.05+01", 1, "12:34:56.1+01:00"},
+		{"timestamp_up", "2023-08-15 12:34:56.125", 2, "2023-08-15T12:34:56.13"},
+		{"timestamp_epoch_up", "2000-01-01 00:00:00.5", 0, "2000-01-01T00:00:01"},
+		{"timestamp_before_epoch", "1999-12-31 23:59:59.5", 0, "1999-12-31T23:59:59"},
+		{"timestamp_before_epoch_two", "1999-12-31 23:59:59.125", 2, "1999-12-31T23:59:59.12"},
+		{"timestamp_before_epoch_not_half", "1999-12-31 23:59:59.126", 2, "1999-12-31T23:59:59.13"},
+		{"timestamp_tz_after_epoch", "2000-01-01 00:30:00.5+00:30", 0, "2000-01-01T00:30:01+00:30"},
+		{"timestamp_tz_before_epoch", "2000-01-01 00:30:00.5+01", 0, "2000-01-01T00:30:00+01:00"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dt, ok := ParseTime(ctx, tc.value, tc.precision)
+			r.True(ok)
+			a.Equal(tc.exp, dt.String())
+		})
+	}
+}
+
 func cmpNano(a *assert.Assertions, value string, precision, exp int) {
 	dt, ok := ParseTime(context.Background(), value, precision)
 	a.True(ok)
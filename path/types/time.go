@@ -35,11 +35,20 @@ func (t *Time) String() string {
 	return t.Time.Format(timeFormat)
 }
 
+// FormatStyle returns the string representation of t in style. All styles
+// other than [DateStyleISO] use the format "15:04:05.999999".
+func (t *Time) FormatStyle(style DateStyle) string {
+	if style == DateStyleISO {
+		return t.String()
+	}
+	return t.Time.Format(styleTimeFormat)
+}
+
 // ToTimeTZ converts t to *TimeTZ in the time zone in ctx. It works relative
 // the current date.
 func (t *Time) ToTimeTZ(ctx context.Context) *TimeTZ {
 	now := time.Now()
-	return NewTimeTZ(time.Date(
+	return NewTimeTZ(dateIn(
 		now.Year(), now.Month(), now.Day(),
 		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
 		TZFromContext(ctx),
@@ -64,9 +73,14 @@ func (t *Time) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface. The time must be a
-// quoted string in the "15:04:05.999999999" format.
+// quoted string in the "15:04:05.999999999" format. JSON null leaves t
+// unchanged.
 func (t *Time) UnmarshalJSON(data []byte) error {
-	tim, err := time.Parse(timeFormat, string(data[1:len(data)-1]))
+	str, ok, err := unquoteJSON(data, "time")
+	if !ok {
+		return err
+	}
+	tim, err := time.Parse(timeFormat, str)
 	if err != nil {
 		return fmt.Errorf(
 			"%w: Cannot parse %s as %q",
@@ -32,9 +32,15 @@ const (
 )
 
 // String returns the string representation of ts using the format
-// "2006-01-02T15:04:05.999999999".
+// "2006-01-02T15:04:05.999999999", followed by " BC" for timestamps before 1
+// AD.
 func (ts *Timestamp) String() string {
-	return ts.Time.Format(timestampFormat)
+	return string(appendEra(appendFormat(nil, ts.Time, timestampFormat), ts.Time))
+}
+
+// FormatStyle returns the string representation of ts in style.
+func (ts *Timestamp) FormatStyle(style DateStyle) string {
+	return string(appendEra(appendFormat(nil, ts.Time, timestampFormatFor(style)), ts.Time))
 }
 
 // ToDate converts ts to *Date.
@@ -47,10 +53,12 @@ func (ts *Timestamp) ToTime(context.Context) *Time {
 	return NewTime(ts.Time)
 }
 
-// ToTimestampTZ converts ts to *TimestampTZ.
+// ToTimestampTZ converts ts to *TimestampTZ in the time zone in ctx,
+// resolving times skipped or repeated by daylight saving time transitions
+// the same way as PostgreSQL.
 func (ts *Timestamp) ToTimestampTZ(ctx context.Context) *TimestampTZ {
 	t := ts.Time
-	return NewTimestampTZ(ctx, time.Date(
+	return NewTimestampTZ(ctx, dateIn(
 		t.Year(), t.Month(), t.Day(),
 		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
 		TZFromContext(ctx),
@@ -64,21 +72,27 @@ func (ts *Timestamp) Compare(u time.Time) int {
 }
 
 // MarshalJSON implements the json.Marshaler interface. The time is a quoted
-// string using the "2006-01-02T15:04:05.999999999" format.
+// string using the format returned by [Timestamp.String].
 func (ts *Timestamp) MarshalJSON() ([]byte, error) {
 	const timestampJSONSize = len(timestampFormat) + len(`""`)
 	b := make([]byte, 0, timestampJSONSize)
 	b = append(b, '"')
-	b = ts.Time.AppendFormat(b, timestampFormat)
+	b = appendEra(appendFormat(b, ts.Time, timestampFormat), ts.Time)
 	b = append(b, '"')
 	return b, nil
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface. The time must be a
-// quoted string in the "2006-01-02T15:04:05.999999999" format.
+// quoted string in the format returned by [Timestamp.String]. JSON null
+// leaves ts unchanged.
 func (ts *Timestamp) UnmarshalJSON(data []byte) error {
-	tim, err := time.Parse(timestampFormat, string(data[1:len(data)-1]))
-	if err != nil {
+	str, ok, err := unquoteJSON(data, "timestamp")
+	if !ok {
+		return err
+	}
+	year, str := splitYear(str)
+	tim, ok := year.parse(timestampFormat, str)
+	if !ok {
 		return fmt.Errorf(
 			"%w: Cannot parse %s as %q",
 			ErrSQLType, data, timestampFormat,
@@ -44,9 +44,39 @@ const (
 
 // String returns the string representation of ts in the time zone in the
 // Context passed to NewTimestampTZ, using the format
-// "2006-01-02T15:04:05.999999999-07:00".
+// "2006-01-02T15:04:05.999999999-07:00", extended to "-07:00:00" for offsets
+// with seconds, and followed by " BC" for timestamps before 1 AD.
 func (ts *TimestampTZ) String() string {
-	return ts.Time.Format(timestampTZOutputFormat)
+	return string(ts.appendISO(nil))
+}
+
+// appendISO appends the string representation of ts returned by String to
+// b.
+func (ts *TimestampTZ) appendISO(b []byte) []byte {
+	b = appendFormat(b, ts.Time, timestampFormat)
+	return appendEra(appendOffset(b, ts.Time, true), ts.Time)
+}
+
+// FormatStyle returns the string representation of ts in style. Like
+// PostgreSQL, styles other than [DateStyleISO] append the abbreviation of
+// the time zone in the Context passed to NewTimestampTZ, such as PDT, when
+// ts has its offset and it has an alphabetic abbreviation. Otherwise they
+// append the offset hours, plus minutes and seconds only if they're not
+// zero.
+func (ts *TimestampTZ) FormatStyle(style DateStyle) string {
+	if style == DateStyleISO {
+		return ts.String()
+	}
+	b := appendFormat(nil, ts.Time, timestampFormatFor(style))
+	if abbrev := zoneAbbrev(ts.Time, ts.tz); abbrev != "" {
+		b = append(append(b, ' '), abbrev...)
+	} else {
+		if style == DateStylePostgres {
+			b = append(b, ' ')
+		}
+		b = appendOffset(b, ts.Time, false)
+	}
+	return string(appendEra(b, ts.Time))
 }
 
 // ToDate converts ts to *Date in the time zone in ctx.
@@ -76,12 +106,13 @@ func (ts *TimestampTZ) Compare(u time.Time) int {
 }
 
 // MarshalJSON implements the json.Marshaler interface. The time is a quoted
-// string using the "2006-01-02T15:04:05.999999999-07:00" format.
+// string using the format returned by [TimestampTZ.String], the same format
+// PostgreSQL uses for timestamp with time zone values in JSON.
 func (ts *TimestampTZ) MarshalJSON() ([]byte, error) {
-	const timestampJSONSize = len(timestampTZOutputFormat) + len(`""`)
+	const timestampJSONSize = len(timestampTZSecondFormat) + len(`""`)
 	b := make([]byte, 0, timestampJSONSize)
 	b = append(b, '"')
-	b = ts.Time.AppendFormat(b, timestampTZOutputFormat)
+	b = ts.appendISO(b)
 	b = append(b, '"')
 	return b, nil
 }
@@ -91,8 +122,15 @@ func (ts *TimestampTZ) MarshalJSON() ([]byte, error) {
 //   - 2006-01-02T15:04:05.999999999Z07:00:00
 //   - 2006-01-02T15:04:05.999999999Z07:00
 //   - 2006-01-02T15:04:05.999999999Z07
+//
+// Each may be followed by " BC", as output by [TimestampTZ.String]. JSON
+// null leaves ts unchanged.
 func (ts *TimestampTZ) UnmarshalJSON(data []byte) error {
-	str := data[1 : len(data)-1] // Unquote
+	str, ok, err := unquoteJSON(data, "timestamp with time zone")
+	if !ok {
+		return err
+	}
+	year, str := splitYear(str)
 
 	// Figure out which TZ format we need.
 	var format string
@@ -110,8 +148,8 @@ func (ts *TimestampTZ) UnmarshalJSON(data []byte) error {
 		format = timestampTZHourFormat
 	}
 
-	tim, err := time.Parse(format, string(str))
-	if err != nil {
+	tim, ok := year.parse(format, str)
+	if !ok {
 		return fmt.Errorf("%w: Cannot parse %s as %q", ErrSQLType, data, format)
 	}
 	*ts = TimestampTZ{Time: tim}
@@ -38,9 +38,27 @@ const (
 )
 
 // String returns the string representation of ts using the format
-// "15:04:05.999999999-07:00".
+// "15:04:05.999999999-07:00", extended to "-07:00:00" for offsets with
+// seconds.
 func (t *TimeTZ) String() string {
-	return t.Time.Format(timeTZOutputFormat)
+	return string(t.appendISO(nil))
+}
+
+// appendISO appends the string representation of t returned by String to b.
+func (t *TimeTZ) appendISO(b []byte) []byte {
+	b = t.Time.AppendFormat(b, timeFormat)
+	return appendOffset(b, t.Time, true)
+}
+
+// FormatStyle returns the string representation of t in style. All styles
+// other than [DateStyleISO] use the format "15:04:05.999999" followed by the
+// offset hours, plus minutes and seconds only if they're not zero.
+func (t *TimeTZ) FormatStyle(style DateStyle) string {
+	if style == DateStyleISO {
+		return t.String()
+	}
+	b := t.Time.AppendFormat(nil, styleTimeFormat)
+	return string(appendOffset(b, t.Time, false))
 }
 
 // ToTime converts t to *Time.
@@ -75,12 +93,13 @@ func (t *TimeTZ) Compare(u time.Time) int {
 }
 
 // MarshalJSON implements the json.Marshaler interface. The time is a quoted
-// string using the "15:04:05.999999999-07:00" format.
+// string using the format returned by [TimeTZ.String], the same format
+// PostgreSQL uses for time with time zone values in JSON.
 func (t *TimeTZ) MarshalJSON() ([]byte, error) {
-	const timeJSONSize = len(timeTZOutputFormat) + len(`""`)
+	const timeJSONSize = len(timeTZSecondFormat) + len(`""`)
 	b := make([]byte, 0, timeJSONSize)
 	b = append(b, '"')
-	b = t.Time.AppendFormat(b, timeTZOutputFormat)
+	b = t.appendISO(b)
 	b = append(b, '"')
 	return b, nil
 }
@@ -90,8 +109,13 @@ func (t *TimeTZ) MarshalJSON() ([]byte, error) {
 //   - 15:04:05.999999999Z07:00:00
 //   - 15:04:05.999999999Z07:00
 //   - 15:04:05.999999999Z07
+//
+// JSON null leaves t unchanged.
 func (t *TimeTZ) UnmarshalJSON(data []byte) error {
-	str := data[1 : len(data)-1] // Unquote
+	str, ok, err := unquoteJSON(data, "time with time zone")
+	if !ok {
+		return err
+	}
 
 	// Figure out which TZ format we need.
 	var format string
@@ -101,15 +125,15 @@ func (t *TimeTZ) UnmarshalJSON(data []byte) error {
 	)
 	size := len(str)
 	switch {
-	case str[size-secPlace] == '-' || str[size-secPlace] == '+':
+	case size >= secPlace && (str[size-secPlace] == '-' || str[size-secPlace] == '+'):
 		format = timeTZSecondFormat
-	case str[size-minPlace] == '-' || str[size-minPlace] == '+':
+	case size >= minPlace && (str[size-minPlace] == '-' || str[size-minPlace] == '+'):
 		format = timeTZMinuteFormat
 	default:
 		format = timeTZHourFormat
 	}
 
-	tim, err := time.Parse(format, string(str))
+	tim, err := time.Parse(format, str)
 	if err != nil {
 		return fmt.Errorf("%w: Cannot parse %s as %q", ErrSQLType, data, format)
 	}
@@ -21,6 +21,13 @@ Each provides a constructor that takes a [time.Time] object, which defines the
 underlying representation. Each also provides casting functions between the
 types, but only for supported casts.
 
+The [ToDate], [ToTime], [ToTimeTZ], [ToTimestamp], and [ToTimestampTZ]
+functions convert any [DateTime] to the corresponding type, and [Compare]
+compares any two [DateTime] values, both following the rules of SQL/JSON Path
+execution: they return a [ConversionError] for unsupported conversions and
+for conversions between offset-aware and offset-unaware types when time zone
+usage is disabled.
+
 # Time Zones
 
 Like the PostgreSQL timetz and timestamptz types, [TimeTZ] and [TimestampTZ]
@@ -64,6 +71,32 @@ add it to the context passed to any constructor or method that takes a context:
 This time zone affects casts, as well, between offset-aware types ([TimeTZ],
 [TimestampTZ]) and offset-unaware types ([Date], [Time], [Timestamp]). For any
 execution, be sure to pass the same context to all operations.
+
+Named time zones may observe daylight saving time. Casts from offset-unaware
+types resolve local times skipped or repeated by a transition the same way
+as PostgreSQL: a skipped time uses the offset in effect before the
+transition, so that 2024-03-10 02:30 in America/New_York becomes
+2024-03-10T03:30:00-04:00, while a repeated time uses the offset in effect
+after the transition, so that 2024-11-03 01:30 becomes
+2024-11-03T01:30:00-05:00.
+
+# JSON
+
+Each type implements [json.Marshaler] and [json.Unmarshaler], marshaling to
+the same strings as their String methods, which match the output of
+PostgreSQL's JSON functions, such as jsonb_path_query():
+
+  - date: 2023-08-15
+  - time: 12:34:56.789
+  - timetz: 12:34:56.789+05:30
+  - timestamp: 2023-08-15T12:34:56.789
+  - timestamptz: 2023-08-15T12:34:56.789+05:30
+
+Fractional seconds omit trailing zeros, and are omitted entirely when zero.
+Offsets always include the sign, hours, and minutes, even for UTC, which
+marshals as +00:00 rather than Z, and include seconds only when they're not
+zero, e.g., +01:02:03. Unmarshaling also accepts offsets of hours only, and Z
+for UTC.
 */
 package types
 
@@ -85,4 +118,19 @@ type DateTime interface {
 	fmt.Stringer
 	// GoTime returns the underlying time.Time object.
 	GoTime() time.Time
+	// FormatStyle returns the string representation in style.
+	FormatStyle(style DateStyle) string
+}
+
+// unquoteJSON returns the contents of the JSON string data and true. Returns
+// false if data is JSON null, and false and an error if data is not a JSON
+// string. typ names the type for the error message.
+func unquoteJSON(data []byte, typ string) (string, bool, error) {
+	if string(data) == "null" {
+		return "", false, nil
+	}
+	if len(data) < len(`""`) || data[0] != '"' || data[len(data)-1] != '"' {
+		return "", false, fmt.Errorf("%w: Cannot unmarshal %s into %s", ErrSQLType, data, typ)
+	}
+	return string(data[1 : len(data)-1]), true, nil
 }
@@ -1,9 +1,11 @@
 package types
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDateTime(t *testing.T) {
@@ -26,3 +28,98 @@ func TestDateTime(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	for _, tc := range []struct {
+		name string
+		obj  func() DateTime
+		json string
+		exp  string
+	}{
+		{"date", func() DateTime { return &Date{} }, `"2023-08-15"`, ""},
+		{"time", func() DateTime { return &Time{} }, `"12:34:56"`, ""},
+		{"time_fraction", func() DateTime { return &Time{} }, `"12:34:56.789012"`, ""},
+		{"time_trim_zeros", func() DateTime { return &Time{} }, `"12:34:56.7890"`, `"12:34:56.789"`},
+		{"time_zero_fraction", func() DateTime { return &Time{} }, `"12:34:56.000"`, `"12:34:56"`},
+		{"timetz", func() DateTime { return &TimeTZ{} }, `"12:34:56+05:30"`, ""},
+		{"timetz_fraction", func() DateTime { return &TimeTZ{} }, `"12:34:56.79-09:30"`, ""},
+		{"timetz_utc", func() DateTime { return &TimeTZ{} }, `"12:34:56+00:00"`, ""},
+		{"timetz_z", func() DateTime { return &TimeTZ{} }, `"12:34:56Z"`, `"12:34:56+00:00"`},
+		{"timetz_hour", func() DateTime { return &TimeTZ{} }, `"12:34:56+05"`, `"12:34:56+05:00"`},
+		{"timetz_seconds", func() DateTime { return &TimeTZ{} }, `"12:34:56+01:02:03"`, ""},
+		{"timestamp", func() DateTime { return &Timestamp{} }, `"2023-08-15T12:34:56"`, ""},
+		{"timestamp_fraction", func() DateTime { return &Timestamp{} }, `"2023-08-15T12:34:56.5"`, ""},
+		{"timestamptz", func() DateTime { return &TimestampTZ{} }, `"2023-08-15T12:34:56+05:30"`, ""},
+		{"timestamptz_fraction", func() DateTime { return &TimestampTZ{} }, `"2023-08-15T12:34:56.789-07:00"`, ""},
+		{"timestamptz_utc", func() DateTime { return &TimestampTZ{} }, `"2023-08-15T12:34:56+00:00"`, ""},
+		{"timestamptz_z", func() DateTime { return &TimestampTZ{} }, `"2023-08-15T12:34:56Z"`, `"2023-08-15T12:34:56+00:00"`},
+		{"timestamptz_hour", func() DateTime { return &TimestampTZ{} }, `"2023-08-15T12:34:56-03"`, `"2023-08-15T12:34:56-03:00"`},
+		{"timestamptz_seconds", func() DateTime { return &TimestampTZ{} }, `"2023-08-15T12:34:56-01:02:03"`, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			exp := tc.exp
+			if exp == "" {
+				exp = tc.json
+			}
+
+			obj := tc.obj()
+			r.NoError(json.Unmarshal([]byte(tc.json), obj))
+			got, err := json.Marshal(obj)
+			r.NoError(err)
+			a.Equal(exp, string(got))
+			a.Equal(exp, `"`+obj.String()+`"`)
+
+			// Round-trip the output.
+			obj2 := tc.obj()
+			r.NoError(json.Unmarshal(got, obj2))
+			a.True(obj.GoTime().Equal(obj2.GoTime()))
+			_, off := obj.GoTime().Zone()
+			_, off2 := obj2.GoTime().Zone()
+			a.Equal(off, off2)
+		})
+	}
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	for _, tc := range []struct {
+		name string
+		obj  json.Unmarshaler
+		typ  string
+	}{
+		{"date", &Date{}, "date"},
+		{"time", &Time{}, "time"},
+		{"timetz", &TimeTZ{}, "time with time zone"},
+		{"timestamp", &Timestamp{}, "timestamp"},
+		{"timestamptz", &TimestampTZ{}, "timestamp with time zone"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// JSON null is a no-op.
+			r.NoError(tc.obj.UnmarshalJSON([]byte("null")))
+
+			// Non-strings are errors.
+			for _, data := range []string{`1`, `"`, `true`, `{}`} {
+				err := tc.obj.UnmarshalJSON([]byte(data))
+				r.EqualError(err, "type: Cannot unmarshal "+data+" into "+tc.typ)
+				r.ErrorIs(err, ErrSQLType)
+			}
+
+			// Short strings are parse errors.
+			for _, data := range []string{`""`, `"1"`} {
+				err := tc.obj.UnmarshalJSON([]byte(data))
+				r.ErrorIs(err, ErrSQLType)
+				a.Contains(err.Error(), "type: Cannot parse "+data)
+			}
+		})
+	}
+}
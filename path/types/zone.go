@@ -0,0 +1,185 @@
+package types
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// zoneAbbrevs maps time zone abbreviations to their offsets in seconds east
+// of UTC. It contains the commonly-used abbreviations from the PostgreSQL
+// Default timezone_abbreviations set, with the same meanings, so that, for
+// example, IST means Israel Standard Time and CST means Central Standard
+// Time.
+//
+//nolint:gochecknoglobals
+var zoneAbbrevs = map[string]int{
+	// UTC
+	"GMT":  0,
+	"UCT":  0,
+	"UT":   0,
+	"UTC":  0,
+	"Z":    0,
+	"ZULU": 0,
+
+	// Europe and Africa
+	"WET":    0,
+	"BST":    1 * 3600,
+	"CET":    1 * 3600,
+	"MET":    1 * 3600,
+	"MEZ":    1 * 3600,
+	"WAT":    1 * 3600,
+	"WETDST": 1 * 3600,
+	"CEST":   2 * 3600,
+	"CETDST": 2 * 3600,
+	"EET":    2 * 3600,
+	"MEST":   2 * 3600,
+	"MESZ":   2 * 3600,
+	"METDST": 2 * 3600,
+	"SAST":   2 * 3600,
+	"EAT":    3 * 3600,
+	"EEST":   3 * 3600,
+	"EETDST": 3 * 3600,
+	"MSK":    3 * 3600,
+	"MSD":    4 * 3600,
+
+	// Asia
+	"IST": 2 * 3600,
+	"IDT": 3 * 3600,
+	"IRT": 3*3600 + 30*60,
+	"AFT": 4*3600 + 30*60,
+	"PKT": 5 * 3600,
+	"NPT": 5*3600 + 45*60,
+	"BDT": 6 * 3600,
+	"ICT": 7 * 3600,
+	"CCT": 8 * 3600,
+	"HKT": 8 * 3600,
+	"MYT": 8 * 3600,
+	"PHT": 8 * 3600,
+	"SGT": 8 * 3600,
+	"JST": 9 * 3600,
+	"KST": 9 * 3600,
+
+	// Australia and the Pacific
+	"AWST": 8 * 3600,
+	"ACST": 9*3600 + 30*60,
+	"AEST": 10 * 3600,
+	"ACDT": 10*3600 + 30*60,
+	"AEDT": 11 * 3600,
+	"NZST": 12 * 3600,
+	"NZDT": 13 * 3600,
+	"HST":  -10 * 3600,
+
+	// The Americas
+	"AKST": -9 * 3600,
+	"AKDT": -8 * 3600,
+	"PST":  -8 * 3600,
+	"PDT":  -7 * 3600,
+	"MST":  -7 * 3600,
+	"MDT":  -6 * 3600,
+	"CST":  -6 * 3600,
+	"CDT":  -5 * 3600,
+	"EST":  -5 * 3600,
+	"EDT":  -4 * 3600,
+	"AST":  -4 * 3600,
+	"CLT":  -4 * 3600,
+	"ADT":  -3 * 3600,
+	"ART":  -3 * 3600,
+	"BRT":  -3 * 3600,
+	"CLST": -3 * 3600,
+	"NST":  -(3*3600 + 30*60),
+	"BRST": -2 * 3600,
+	"NDT":  -(2*3600 + 30*60),
+}
+
+// zoneNames caches the time.Location values loaded for time zone names.
+//
+//nolint:gochecknoglobals
+var zoneNames sync.Map
+
+// lookupZone returns the time.Location for name, either a time zone
+// abbreviation in zoneAbbrevs, compared case-insensitively, or a time zone
+// name known to [time.LoadLocation], such as "America/New_York". Returns
+// false for any other name, including "Local".
+func lookupZone(name string) (*time.Location, bool) {
+	if off, ok := zoneAbbrevs[strings.ToUpper(name)]; ok {
+		return time.FixedZone("", off), true
+	}
+	if name == "Local" {
+		return nil, false
+	}
+	if loc, ok := zoneNames.Load(name); ok {
+		//nolint:forcetypeassert // zoneNames contains only *time.Location
+		return loc.(*time.Location), true
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+	zoneNames.Store(name, loc)
+	return loc, true
+}
+
+// parseZoned parses src as a time or timestamp followed by a time zone
+// abbreviation or name, optionally preceded by a space, such as
+// "12:34:56 EST" or "2017-03-10T12:34:56.789America/New_York", and returns
+// a TimeTZ or TimestampTZ with the offset of the zone. Named zones resolve
+// to the offset in effect at the time of a timestamp, including daylight
+// saving time, and, as in PostgreSQL, at the time on the current date for a
+// time. year is the year splitYear found in src, if any. Returns false if
+// src does not end with a zone, or the zone is unknown.
+func parseZoned(ctx context.Context, src string, precision int, year extendedYear) (DateTime, bool) {
+	// The zone starts with the first letter after the seconds.
+	colon := strings.LastIndexByte(src, ':')
+	if colon < 0 {
+		return nil, false
+	}
+	start := strings.IndexFunc(src[colon:], isASCIILetter)
+	if start < 0 {
+		return nil, false
+	}
+	start += colon
+	clock := strings.TrimSuffix(src[:start], " ")
+
+	// Time with TZ
+	if value, err := time.Parse("15:04:05", clock); err == nil {
+		loc, ok := lookupZone(src[start:])
+		if !ok {
+			return nil, false
+		}
+		now := time.Now().In(loc)
+		value = dateIn(
+			now.Year(), now.Month(), now.Day(),
+			value.Hour(), value.Minute(), value.Second(), value.Nanosecond(), loc,
+		)
+		return NewTimeTZ(adjustPrecision(offsetOnlyTimeFor(value), precision)), true
+	}
+
+	// Timestamp with TZ, with and without "T"
+	for _, format := range []string{
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+	} {
+		value, ok := year.parse(format, clock)
+		if !ok {
+			continue
+		}
+		loc, ok := lookupZone(src[start:])
+		if !ok {
+			return nil, false
+		}
+		value = dateIn(
+			value.Year(), value.Month(), value.Day(),
+			value.Hour(), value.Minute(), value.Second(), value.Nanosecond(), loc,
+		)
+		return NewTimestampTZ(ctx, adjustTimestampPrecision(value, precision)), true
+	}
+
+	return nil, false
+}
+
+// isASCIILetter returns true if r is an ASCII letter.
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,174 @@
+package types
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupZone(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	ny, err := time.LoadLocation("America/New_York")
+	r.NoError(err)
+
+	for _, tc := range []struct {
+		name string
+		zone string
+		off  int
+		loc  *time.Location
+		ok   bool
+	}{
+		{"utc", "UTC", 0, nil, true},
+		{"lower_utc", "utc", 0, nil, true},
+		{"z", "Z", 0, nil, true},
+		{"est", "EST", -5 * 3600, nil, true},
+		{"mixed_est", "Est", -5 * 3600, nil, true},
+		{"pdt", "PDT", -7 * 3600, nil, true},
+		{"cest", "CEST", 2 * 3600, nil, true},
+		{"ist", "IST", 2 * 3600, nil, true},
+		{"npt", "NPT", 5*3600 + 45*60, nil, true},
+		{"nst", "NST", -(3*3600 + 30*60), nil, true},
+		{"name", "America/New_York", 0, ny, true},
+		{"unknown", "XYZ", 0, nil, false},
+		{"local", "Local", 0, nil, false},
+		{"lower_name", "america/new_york", 0, nil, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			loc, ok := lookupZone(tc.zone)
+			a.Equal(tc.ok, ok)
+			switch {
+			case !tc.ok:
+				a.Nil(loc)
+			case tc.loc != nil:
+				a.Equal(tc.loc.String(), loc.String())
+			default:
+				_, off := time.Date(2024, 1, 1, 0, 0, 0, 0, loc).Zone()
+				a.Equal(tc.off, off)
+			}
+		})
+	}
+
+	// Names are cached.
+	loc, ok := lookupZone("Europe/Paris")
+	r.True(ok)
+	again, ok := lookupZone("Europe/Paris")
+	r.True(ok)
+	a.Same(loc, again)
+}
+
+func TestParseZoned(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	r := require.New(t)
+
+	loc, err := time.LoadLocation("PST8PDT")
+	r.NoError(err)
+	ctx := ContextWithTZ(context.Background(), loc)
+
+	for _, tc := range []struct {
+		name  string
+		value string
+		exp   DateTime
+	}{
+		{
+			name:  "timestamp_t_abbrev",
+			value: "2017-03-10T12:34:56.789EST",
+			exp:   NewTimestampTZ(ctx, time.Date(2017, 3, 10, 12, 34, 56, 789000000, neg(5, 0, 0))),
+		},
+		{
+			name:  "timestamp_abbrev",
+			value: "2017-03-10 12:34:56UTC",
+			exp:   NewTimestampTZ(ctx, time.Date(2017, 3, 10, 12, 34, 56, 0, offsetZero)),
+		},
+		{
+			name:  "timestamp_space_abbrev",
+			value: "2017-03-10 12:34:56 GMT",
+			exp:   NewTimestampTZ(ctx, time.Date(2017, 3, 10, 12, 34, 56, 0, offsetZero)),
+		},
+		{
+			name:  "timestamp_lower_abbrev",
+			value: "2017-03-10 12:34:56 pst",
+			exp:   NewTimestampTZ(ctx, time.Date(2017, 3, 10, 12, 34, 56, 0, neg(8, 0, 0))),
+		},
+		{
+			name:  "timestamp_half_hour_abbrev",
+			value: "2017-03-10 12:34:56 ACST",
+			exp:   NewTimestampTZ(ctx, time.Date(2017, 3, 10, 12, 34, 56, 0, pos(9, 30, 0))),
+		},
+		{
+			name:  "timestamp_name_standard",
+			value: "2024-01-15 12:00:00 America/New_York",
+			exp:   NewTimestampTZ(ctx, time.Date(2024, 1, 15, 12, 0, 0, 0, neg(5, 0, 0))),
+		},
+		{
+			name:  "timestamp_name_dst",
+			value: "2024-07-15T12:00:00America/New_York",
+			exp:   NewTimestampTZ(ctx, time.Date(2024, 7, 15, 12, 0, 0, 0, neg(4, 0, 0))),
+		},
+		{
+			name:  "timestamp_name_skipped",
+			value: "2024-03-10 02:30:00 America/New_York",
+			exp:   NewTimestampTZ(ctx, time.Date(2024, 3, 10, 3, 30, 0, 0, neg(4, 0, 0))),
+		},
+		{
+			name:  "timestamp_name_digits",
+			value: "2024-01-15 12:00:00 Etc/GMT+3",
+			exp:   NewTimestampTZ(ctx, time.Date(2024, 1, 15, 12, 0, 0, 0, neg(3, 0, 0))),
+		},
+		{
+			name:  "time_abbrev",
+			value: "12:34:56EST",
+			exp:   NewTimeTZ(time.Date(0, 1, 1, 12, 34, 56, 0, neg(5, 0, 0))),
+		},
+		{
+			name:  "time_space_abbrev",
+			value: "12:34:56.5 CET",
+			exp:   NewTimeTZ(time.Date(0, 1, 1, 12, 34, 56, 500000000, pos(1, 0, 0))),
+		},
+		{
+			name:  "time_name",
+			value: "12:34:56 Asia/Tokyo",
+			exp:   NewTimeTZ(time.Date(0, 1, 1, 12, 34, 56, 0, pos(9, 0, 0))),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dt, ok := ParseTime(ctx, tc.value, -1)
+			a.True(ok)
+			a.Equal(tc.exp, dt)
+		})
+	}
+
+	// Precision applies.
+	dt, ok := ParseTime(ctx, "2017-03-10 12:34:56.789 EST", 1)
+	r.True(ok)
+	a.Equal(NewTimestampTZ(ctx, time.Date(2017, 3, 10, 12, 34, 56, 800000000, neg(5, 0, 0))), dt)
+
+	dt, ok = ParseTime(ctx, "12:34:56.789 EST", 0)
+	r.True(ok)
+	a.Equal(NewTimeTZ(time.Date(0, 1, 1, 12, 34, 57, 0, neg(5, 0, 0))), dt)
+
+	// Not zones.
+	for _, value := range []string{
+		"2017-03-10 12:34:56 XYZ",
+		"2017-03-10 12:34:56 Local",
+		"2017-03-10 12:34:56 Nowhere/Special",
+		"2017-03-10 12:34:56  EST",
+		"2017-03-10 12:34:56+03 EST",
+		"2017-03-10 EST",
+		"12:34:56 EST5",
+		"12:34 EST",
+		"EST",
+	} {
+		dt, ok := ParseTime(ctx, value, -1)
+		a.False(ok, value)
+		a.Nil(dt, value)
+	}
+}